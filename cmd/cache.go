@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/container"
+)
+
+func newCacheCommand(ctx context.Context, input *Input) *cobra.Command {
+	cacheCmd := &cobra.Command{
+		Use:   "cache",
+		Short: "List the cache volumes of --cache-volume",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, err := input.containerContext(ctx)
+			if err != nil {
+				return err
+			}
+			volumes, err := container.ListCacheVolumes(ctx)
+			if err != nil {
+				return err
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "NAME\tVOLUME\tPATH\tAGE")
+			for _, v := range volumes {
+				age := "-"
+				if !v.Created.IsZero() {
+					age = time.Since(v.Created).Round(time.Second).String()
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", v.Name, v.Volume, v.Path, age)
+			}
+			return w.Flush()
+		},
+	}
+
+	var force bool
+	pruneCmd := &cobra.Command{
+		Use:   "prune [name...]",
+		Short: "Remove the cache volumes, all of them unless their names are given",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, err := input.containerContext(ctx)
+			if err != nil {
+				return err
+			}
+			volumes, err := container.ListCacheVolumes(ctx)
+			if err != nil {
+				return err
+			}
+
+			found := map[string]bool{}
+			for _, v := range volumes {
+				if len(args) > 0 && !common.ContainsString(args, v.Name) {
+					continue
+				}
+				found[v.Name] = true
+				if err := container.NewDockerVolumeRemoveExecutor(v.Volume, force)(ctx); err != nil {
+					return fmt.Errorf("unable to remove the cache volume %s: %w", v.Name, err)
+				}
+				fmt.Println(v.Name)
+			}
+			for _, name := range args {
+				if !found[name] {
+					return fmt.Errorf("no cache volume named '%s'", name)
+				}
+			}
+			return nil
+		},
+	}
+	pruneCmd.Flags().BoolVarP(&force, "force", "f", false, "remove the volumes even if they are used by a container")
+
+	cacheCmd.AddCommand(pruneCmd)
+	return cacheCmd
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvictCache(t *testing.T) {
+	xdg := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", xdg)
+	dir := filepath.Join(xdg, "act")
+
+	old := time.Now().Add(-2 * cacheEvictionMinAge)
+	paths := []string{
+		filepath.Join(dir, "actions", "actions-checkout@v3"),
+		filepath.Join(dir, "tool_cache", "node"),
+		filepath.Join(dir, "workflows", "plan-1"),
+		filepath.Join(dir, "0123456789abcdef"),
+		filepath.Join(dir, "actions-setup-go@v4"),
+	}
+	for _, path := range paths {
+		assert.NoError(t, os.MkdirAll(path, 0o755))
+		assert.NoError(t, os.WriteFile(filepath.Join(path, "file"), []byte("content"), 0o600))
+		assert.NoError(t, os.Chtimes(path, old, old))
+	}
+
+	evictCache(0)
+
+	assert.NoDirExists(t, paths[0])
+	assert.NoDirExists(t, paths[1])
+	assert.DirExists(t, paths[2], "the workflow cache is bounded on its own")
+	assert.DirExists(t, paths[3], "the workdirs of the host executor are never evicted")
+	assert.NoDirExists(t, paths[4], "the clones of the earlier layout are moved to actions and evicted")
+}
+
+func TestMigrateActionCache(t *testing.T) {
+	dir := t.TempDir()
+	for _, path := range []string{
+		filepath.Join(dir, "actions-checkout@v3"),
+		filepath.Join(dir, "actions-setup-go@v4"),
+		filepath.Join(dir, "actions", "actions-setup-go@v4"),
+		filepath.Join(dir, "0123456789abcdef"),
+	} {
+		assert.NoError(t, os.MkdirAll(path, 0o755))
+	}
+
+	migrateActionCache(dir)
+
+	assert.NoDirExists(t, filepath.Join(dir, "actions-checkout@v3"))
+	assert.DirExists(t, filepath.Join(dir, "actions", "actions-checkout@v3"))
+	assert.NoDirExists(t, filepath.Join(dir, "actions-setup-go@v4"))
+	assert.DirExists(t, filepath.Join(dir, "actions", "actions-setup-go@v4"))
+	assert.DirExists(t, filepath.Join(dir, "0123456789abcdef"))
+}
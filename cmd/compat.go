@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/nektos/act/pkg/model"
+)
+
+func newCompatCommand(ctx context.Context, input *Input) *cobra.Command {
+	return &cobra.Command{
+		Use:   "compat",
+		Short: "Report the constructs of the workflows which act does not support",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			files, err := workflowFiles(input.WorkflowsPath(), input.noWorkflowRecurse)
+			if err != nil {
+				return err
+			}
+
+			count := 0
+			for _, file := range files {
+				f, err := os.Open(file)
+				if err != nil {
+					return err
+				}
+				findings, err := model.CheckCompat(f)
+				f.Close()
+				if err != nil {
+					return fmt.Errorf("unable to read workflow '%s': %w", file, err)
+				}
+
+				name := file
+				if rel, err := filepath.Rel(input.Workdir(), file); err == nil {
+					name = rel
+				}
+				for _, finding := range findings {
+					fmt.Printf("%s:%s\n", name, finding)
+				}
+				count += len(findings)
+			}
+
+			if count == 0 {
+				fmt.Printf("act supports all constructs of the %d workflow(s)\n", len(files))
+			}
+			return nil
+		},
+	}
+}
+
+// workflowFiles lists the workflow files in the path, a file or a directory which is searched recursively unless
+// noWorkflowRecurse is set
+func workflowFiles(path string, noWorkflowRecurse bool) ([]string, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !fi.IsDir() {
+		return []string{path}, nil
+	}
+
+	files := make([]string, 0)
+	err = filepath.Walk(path, func(p string, f os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if f.IsDir() {
+			if p != path && noWorkflowRecurse {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if ext := filepath.Ext(p); ext == ".yml" || ext == ".yaml" {
+			files = append(files, p)
+		}
+		return nil
+	})
+	return files, err
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// completeEvents completes the event name with the events of the workflows
+func completeEvents(input *Input) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		planner, err := newWorkflowPlanner(input)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		return filterPrefix(planner.GetEvents(), toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeJobs completes the job ID with the jobs of the workflows
+func completeJobs(input *Input) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		planner, err := newWorkflowPlanner(input)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		jobs := make([]string, 0)
+		seen := map[string]bool{}
+		for _, stage := range planner.PlanAll().Stages {
+			for _, jobID := range stage.GetJobIDs() {
+				if !seen[jobID] {
+					seen[jobID] = true
+					jobs = append(jobs, jobID)
+				}
+			}
+		}
+		return filterPrefix(jobs, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+func filterPrefix(values []string, prefix string) []string {
+	filtered := make([]string, 0, len(values))
+	for _, v := range values {
+		if strings.HasPrefix(v, prefix) {
+			filtered = append(filtered, v)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompletion(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	dir := t.TempDir()
+	writeTestWorkflow(t, dir)
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, ".github", "workflows", "release.yml"), []byte(`
+on: [release, pull_request]
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+    - run: echo
+  publish:
+    needs: test
+    runs-on: ubuntu-latest
+    steps:
+    - run: echo
+`), 0o600))
+	input := &Input{workdir: dir, workflowsPath: "./.github/workflows/"}
+
+	events, directive := completeEvents(input)(&cobra.Command{}, nil, "")
+	assert.Equal(t, []string{"pull_request", "push", "release"}, events)
+	assert.Equal(t, cobra.ShellCompDirectiveNoFileComp, directive)
+
+	events, _ = completeEvents(input)(&cobra.Command{}, nil, "pu")
+	assert.Equal(t, []string{"pull_request", "push"}, events)
+
+	events, _ = completeEvents(input)(&cobra.Command{}, []string{"push"}, "")
+	assert.Empty(t, events, "only the first argument is an event")
+
+	jobs, directive := completeJobs(input)(&cobra.Command{}, nil, "")
+	assert.ElementsMatch(t, []string{"test", "publish"}, jobs)
+	assert.Equal(t, cobra.ShellCompDirectiveNoFileComp, directive)
+
+	jobs, _ = completeJobs(input)(&cobra.Command{}, nil, "pub")
+	assert.Equal(t, []string{"publish"}, jobs)
+
+	entries, err := os.ReadDir(filepath.Join(cacheLocation(), "workflows"))
+	assert.NoError(t, err)
+	assert.NotEmpty(t, entries, "the completions use the workflow cache")
+
+	input.workflowsPath = "./missing"
+	_, directive = completeEvents(input)(&cobra.Command{}, nil, "")
+	assert.Equal(t, cobra.ShellCompDirectiveError, directive)
+	_, directive = completeJobs(input)(&cobra.Command{}, nil, "")
+	assert.Equal(t, cobra.ShellCompDirectiveError, directive)
+}
+
+func TestFilterPrefix(t *testing.T) {
+	assert.Equal(t, []string{"push", "pull_request"}, filterPrefix([]string{"push", "pull_request", "release"}, "pu"))
+	assert.Equal(t, []string{}, filterPrefix([]string{"push"}, "release"))
+}
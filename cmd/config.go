@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+
+	"github.com/nektos/act/pkg/model"
+)
+
+// actConfig is the config file of a repository
+type actConfig struct {
+	Workflows map[string]*workflowConfig `yaml:"workflows"`
+}
+
+// workflowConfig overrides the flags for the runs of a workflow file
+type workflowConfig struct {
+	EventPath string            `yaml:"eventpath"` // path to the event JSON file, unless --eventpath is passed
+	Platforms map[string]string `yaml:"platforms"` // images of the platforms, replacing the ones of -P
+	EnvFiles  []string          `yaml:"env-files"` // env files read after --env-file
+	SkipJobs  []string          `yaml:"skip-jobs"` // IDs of the jobs which are not run
+}
+
+// readWorkflowConfig returns the config of the workflow of the plan, the config only applies when the plan
+// runs a single workflow file
+func (i *Input) readWorkflowConfig(plan *model.Plan) (string, *workflowConfig, error) {
+	content, err := os.ReadFile(i.ConfigFile())
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil, nil
+	} else if err != nil {
+		return "", nil, err
+	}
+	var config actConfig
+	if err := yaml.Unmarshal(content, &config); err != nil {
+		return "", nil, fmt.Errorf("unable to read the config from %s: %w", i.ConfigFile(), err)
+	}
+
+	files := map[string]bool{}
+	for _, stage := range plan.Stages {
+		for _, run := range stage.Runs {
+			files[run.Workflow.File] = true
+		}
+	}
+	configured := make([]string, 0, len(files))
+	for file := range files {
+		if config.Workflows[file] != nil {
+			configured = append(configured, file)
+		}
+	}
+	if len(configured) == 0 {
+		return "", nil, nil
+	}
+	if len(files) > 1 {
+		sort.Strings(configured)
+		log.Warnf("Ignoring the config of %v in %s, it applies only when a single workflow is run (e.g. -W .github/workflows/%s)", configured, i.ConfigFile(), configured[0])
+		return "", nil, nil
+	}
+	log.Debugf("Using the config of %s from %s", configured[0], i.ConfigFile())
+	return configured[0], config.Workflows[configured[0]], nil
+}
+
+// skipJobs removes the jobs from the plan, the stages left without jobs are dropped
+func skipJobs(plan *model.Plan, jobIDs []string) {
+	skip := map[string]bool{}
+	for _, jobID := range jobIDs {
+		skip[jobID] = true
+	}
+
+	stages := make([]*model.Stage, 0, len(plan.Stages))
+	for _, stage := range plan.Stages {
+		runs := make([]*model.Run, 0, len(stage.Runs))
+		for _, run := range stage.Runs {
+			if skip[run.JobID] {
+				log.Infof("Skipping job %s as configured", run.JobID)
+				continue
+			}
+			runs = append(runs, run)
+		}
+		if len(runs) > 0 {
+			stage.Runs = runs
+			stages = append(stages, stage)
+		}
+	}
+	plan.Stages = stages
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nektos/act/pkg/model"
+)
+
+func testPlan(runs ...[]*model.Run) *model.Plan {
+	plan := &model.Plan{}
+	for _, stage := range runs {
+		plan.Stages = append(plan.Stages, &model.Stage{Runs: stage})
+	}
+	return plan
+}
+
+func TestReadWorkflowConfig(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "act.yaml"), []byte(`
+workflows:
+  release.yml:
+    eventpath: fixtures/tag.json
+    platforms:
+      ubuntu-latest: node:16-buster
+    env-files: [release.env]
+    skip-jobs: [publish]
+`), 0o600))
+	input := &Input{workdir: dir, configFile: "act.yaml"}
+
+	release := &model.Workflow{File: "release.yml"}
+	ci := &model.Workflow{File: "ci.yml"}
+
+	file, config, err := input.readWorkflowConfig(testPlan([]*model.Run{{Workflow: release, JobID: "build"}}, []*model.Run{{Workflow: release, JobID: "publish"}}))
+	assert.NoError(t, err)
+	assert.Equal(t, "release.yml", file)
+	assert.Equal(t, &workflowConfig{
+		EventPath: "fixtures/tag.json",
+		Platforms: map[string]string{"ubuntu-latest": "node:16-buster"},
+		EnvFiles:  []string{"release.env"},
+		SkipJobs:  []string{"publish"},
+	}, config)
+
+	// a workflow without config
+	file, config, err = input.readWorkflowConfig(testPlan([]*model.Run{{Workflow: ci, JobID: "test"}}))
+	assert.NoError(t, err)
+	assert.Equal(t, "", file)
+	assert.Nil(t, config)
+
+	// the config is ignored when several workflows run
+	file, config, err = input.readWorkflowConfig(testPlan([]*model.Run{{Workflow: ci, JobID: "test"}, {Workflow: release, JobID: "build"}}))
+	assert.NoError(t, err)
+	assert.Equal(t, "", file)
+	assert.Nil(t, config)
+
+	// a missing config file is no error
+	input.configFile = "missing.yaml"
+	_, config, err = input.readWorkflowConfig(testPlan([]*model.Run{{Workflow: release, JobID: "build"}}))
+	assert.NoError(t, err)
+	assert.Nil(t, config)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "invalid.yaml"), []byte("workflows: ["), 0o600))
+	input.configFile = "invalid.yaml"
+	_, _, err = input.readWorkflowConfig(testPlan([]*model.Run{{Workflow: release, JobID: "build"}}))
+	assert.ErrorContains(t, err, "unable to read the config from")
+}
+
+func TestSkipJobs(t *testing.T) {
+	workflow := &model.Workflow{File: "release.yml"}
+	plan := testPlan(
+		[]*model.Run{{Workflow: workflow, JobID: "build"}, {Workflow: workflow, JobID: "lint"}},
+		[]*model.Run{{Workflow: workflow, JobID: "publish"}},
+		[]*model.Run{{Workflow: workflow, JobID: "notify"}},
+	)
+
+	skipJobs(plan, []string{"lint", "publish", "unknown"})
+
+	assert.Len(t, plan.Stages, 2, "the stage left without jobs is dropped")
+	assert.Equal(t, []string{"build"}, plan.Stages[0].GetJobIDs())
+	assert.Equal(t, []string{"notify"}, plan.Stages[1].GetJobIDs())
+
+	skipJobs(plan, nil)
+	assert.Len(t, plan.Stages, 2)
+}
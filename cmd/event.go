@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/nektos/act/pkg/common/git"
+	"github.com/nektos/act/pkg/githubapi"
+	"github.com/nektos/act/pkg/model"
+)
+
+// eventOptions are the flags of act event generate overriding the state of the git repository
+type eventOptions struct {
+	output  string
+	ref     string
+	base    string
+	tag     string
+	body    string
+	commits int
+}
+
+func newEventCommand(ctx context.Context, input *Input) *cobra.Command {
+	eventCmd := &cobra.Command{
+		Use:   "event",
+		Short: "Work with the payloads of the events",
+	}
+
+	opts := &eventOptions{}
+	generateCmd := &cobra.Command{
+		Use:       "generate <event>",
+		Short:     fmt.Sprintf("Print a payload of the event (%s) built from the branch, the commits and the remote of the git repository, to pass with --eventpath", strings.Join(model.GeneratedEvents, ", ")),
+		Args:      cobra.ExactArgs(1),
+		ValidArgs: model.GeneratedEvents,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			event, err := generateEvent(ctx, input, args[0], opts)
+			if err != nil {
+				return err
+			}
+			content, err := json.MarshalIndent(event, "", "  ")
+			if err != nil {
+				return err
+			}
+			content = append(content, '\n')
+			if opts.output == "" || opts.output == "-" {
+				_, err = cmd.OutOrStdout().Write(content)
+				return err
+			}
+			if err := os.WriteFile(opts.output, content, 0o644); err != nil {
+				return err
+			}
+			log.Infof("Wrote the payload of the event '%s' to %s, run it with act %s --eventpath %s", args[0], opts.output, args[0], opts.output)
+			return nil
+		},
+	}
+	generateCmd.Flags().StringVarP(&opts.output, "output", "o", "", "file to write the payload to, stdout if empty")
+	generateCmd.Flags().StringVar(&opts.ref, "ref", "", "the ref of the event, like refs/heads/main, the checked-out branch or tag if empty")
+	generateCmd.Flags().StringVar(&opts.base, "base", "", "the base branch of the pull_request, --defaultbranch if empty")
+	generateCmd.Flags().StringVar(&opts.tag, "tag", "", "the tag of the release, the checked-out tag if empty")
+	generateCmd.Flags().StringVar(&opts.body, "body", "", "the body of the pull_request, the release or the issue_comment")
+	generateCmd.Flags().IntVar(&opts.commits, "commits", 1, "the number of commits of the push, from the checked-out revision back")
+	generateCmd.Flags().StringVar(&input.defaultBranch, "defaultbranch", "", "the name of the main branch")
+	generateCmd.Flags().StringVar(&input.remoteName, "remote-name", "origin", "git remote name that will be used to retrieve url of git repo")
+
+	eventCmd.AddCommand(generateCmd)
+	return eventCmd
+}
+
+// generateEvent builds the payload of the event from the git repository of the working directory
+func generateEvent(ctx context.Context, input *Input, eventName string, opts *eventOptions) (map[string]interface{}, error) {
+	urls, err := githubapi.NewURLs(input.githubInstance)
+	if err != nil {
+		return nil, err
+	}
+	if err := urls.Override(input.githubServerURL, input.githubAPIURL, input.githubGraphQLURL); err != nil {
+		return nil, err
+	}
+	instance := strings.TrimPrefix(strings.TrimPrefix(urls.ServerURL, "https://"), "http://")
+	repository, err := git.FindGithubRepo(ctx, input.Workdir(), instance, input.remoteName)
+	if err != nil {
+		// like the runs, the payload is still generated without the repository
+		log.Warnf("unable to get git repo: %v", err)
+	}
+
+	_, sha, err := git.FindGitRevision(ctx, input.Workdir())
+	if err != nil {
+		return nil, err
+	}
+	ref := opts.ref
+	if ref == "" {
+		if ref, err = git.FindGitRef(ctx, input.Workdir()); err != nil {
+			return nil, err
+		}
+	}
+	n := opts.commits
+	if n < 1 {
+		n = 1
+	}
+	commits, err := git.FindGitCommits(ctx, input.Workdir(), n)
+	if err != nil {
+		return nil, err
+	}
+
+	return model.GenerateEvent(eventName, &model.EventSource{
+		Repository:    repository,
+		ServerURL:     urls.ServerURL,
+		DefaultBranch: input.defaultBranch,
+		Ref:           ref,
+		SHA:           sha,
+		BaseRef:       opts.base,
+		Tag:           opts.tag,
+		Body:          opts.body,
+		Actor:         input.actor,
+		Commits:       commits,
+	})
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"context"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateEvent(t *testing.T) {
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "--initial-branch=main"},
+		{"-c", "user.name=Mona", "-c", "user.email=mona@example.com", "commit", "--allow-empty", "-m", "first"},
+		{"-c", "user.name=Mona", "-c", "user.email=mona@example.com", "commit", "--allow-empty", "-m", "second"},
+		{"remote", "add", "origin", "https://github.com/nektos/act.git"},
+	} {
+		out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	input := &Input{workdir: dir, githubInstance: "github.com", remoteName: "origin", actor: "octocat", defaultBranch: "main"}
+
+	event, err := generateEvent(context.Background(), input, "push", &eventOptions{commits: 5})
+	require.NoError(t, err)
+	assert.Equal(t, "refs/heads/main", event["ref"])
+	assert.Len(t, event["commits"], 2)
+	assert.Equal(t, "nektos/act", event["repository"].(map[string]interface{})["full_name"])
+	assert.Equal(t, "second", event["head_commit"].(map[string]interface{})["message"])
+
+	event, err = generateEvent(context.Background(), input, "pull_request", &eventOptions{ref: "refs/heads/feature", commits: 1})
+	require.NoError(t, err)
+	pr := event["pull_request"].(map[string]interface{})
+	assert.Equal(t, "feature", pr["head"].(map[string]interface{})["ref"])
+	assert.Equal(t, "main", pr["base"].(map[string]interface{})["ref"])
+
+	_, err = generateEvent(context.Background(), input, "release", &eventOptions{commits: 1})
+	assert.Error(t, err)
+}
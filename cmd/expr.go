@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/nektos/act/pkg/model"
+	"github.com/nektos/act/pkg/runner"
+)
+
+func newExprCommand(ctx context.Context, input *Input) *cobra.Command {
+	exprCmd := &cobra.Command{
+		Use:   "expr [expression]",
+		Short: "Evaluate an expression against a constructed context, starts an interactive prompt if no expression is passed",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ee, err := newExprEvaluator(ctx, cmd, input)
+			if err != nil {
+				return err
+			}
+
+			if len(args) > 0 {
+				value, err := evaluateExpression(ctx, ee, args[0])
+				if err != nil {
+					return err
+				}
+				fmt.Println(value)
+				return nil
+			}
+			return exprREPL(ctx, ee, os.Stdin, os.Stdout)
+		},
+	}
+	exprCmd.Flags().String("event", "push", "name of the event in the github context")
+	exprCmd.Flags().String("event-json", "", "path to the event JSON file used as github.event")
+	exprCmd.Flags().StringArray("matrix", []string{}, "matrix value to make available to the expression (e.g. --matrix os=ubuntu)")
+	return exprCmd
+}
+
+func newExprEvaluator(ctx context.Context, cmd *cobra.Command, input *Input) (runner.ExpressionEvaluator, error) {
+	eventName, err := cmd.Flags().GetString("event")
+	if err != nil {
+		return nil, err
+	}
+	eventPath, err := cmd.Flags().GetString("event-json")
+	if err != nil {
+		return nil, err
+	}
+	matrixValues, err := cmd.Flags().GetStringArray("matrix")
+	if err != nil {
+		return nil, err
+	}
+
+	// the env of a run: --env and the env file
+	log.Debugf("Loading environment from %s", input.Envfile())
+	envs := make(map[string]string)
+	_ = parseEnvs(input.envs, envs)
+	_ = readEnvs(input.Envfile(), envs)
+
+	log.Debugf("Loading secrets from %s", input.Secretfile())
+	secrets := newSecrets(nil, nil)
+	_ = readEnvs(input.Secretfile(), secrets)
+
+	matrix := make(map[string]string)
+	_ = parseEnvs(matrixValues, matrix)
+	matrixContext := make(map[string]interface{}, len(matrix))
+	for k, v := range matrix {
+		matrixContext[k] = v
+	}
+
+	contextOverrides, err := input.ContextOverrides()
+	if err != nil {
+		return nil, err
+	}
+
+	config := &runner.Config{
+		Actor:            input.actor,
+		EventName:        eventName,
+		EventPath:        input.resolve(eventPath),
+		Workdir:          input.Workdir(),
+		Env:              envs,
+		Secrets:          secrets,
+		Token:            secrets["GITHUB_TOKEN"],
+		GitHubInstance:   input.githubInstance,
+		GitHubServerURL:  input.githubServerURL,
+		GitHubAPIURL:     input.githubAPIURL,
+		GitHubGraphQLURL: input.githubGraphQLURL,
+		RemoteName:       input.remoteName,
+		ContextOverrides: contextOverrides,
+	}
+	run := &model.Run{
+		JobID: "expr",
+		Workflow: &model.Workflow{
+			Name: "expr",
+			Jobs: map[string]*model.Job{"expr": {Name: "expr"}},
+		},
+	}
+	return runner.NewJobExpressionEvaluator(ctx, config, run, matrixContext)
+}
+
+// evaluateExpression evaluates either a bare expression or a string with embedded ${{ }} expressions,
+// values which are not a string are returned as JSON
+func evaluateExpression(ctx context.Context, ee runner.ExpressionEvaluator, expr string) (value string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("invalid expression '%s': %v", expr, r)
+		}
+	}()
+
+	if !strings.Contains(expr, "${{") {
+		expr = fmt.Sprintf("${{ %s }}", expr)
+	}
+	node := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: expr}
+	if err := ee.EvaluateYamlNode(ctx, node); err != nil {
+		return "", err
+	}
+
+	var evaluated interface{}
+	if err := node.Decode(&evaluated); err != nil {
+		return "", err
+	}
+	if str, ok := evaluated.(string); ok {
+		return str, nil
+	}
+	out, err := json.MarshalIndent(evaluated, "", "  ")
+	return string(out), err
+}
+
+func exprREPL(ctx context.Context, ee runner.ExpressionEvaluator, in io.Reader, out io.Writer) error {
+	scanner := bufio.NewScanner(in)
+	for {
+		fmt.Fprint(out, "> ")
+		if !scanner.Scan() {
+			fmt.Fprintln(out)
+			return scanner.Err()
+		}
+
+		expr := strings.TrimSpace(scanner.Text())
+		if expr == "" {
+			continue
+		}
+		if expr == "exit" || expr == "quit" {
+			return nil
+		}
+
+		value, err := evaluateExpression(ctx, ee, expr)
+		if err != nil {
+			fmt.Fprintf(out, "error: %v\n", err)
+			continue
+		}
+		fmt.Fprintln(out, value)
+	}
+}
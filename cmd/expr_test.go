@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nektos/act/pkg/runner"
+)
+
+func newTestExprEvaluator(t *testing.T, input *Input, flags ...string) runner.ExpressionEvaluator {
+	t.Helper()
+	cmd := &cobra.Command{}
+	cmd.Flags().String("event", "push", "")
+	cmd.Flags().String("event-json", "", "")
+	cmd.Flags().StringArray("matrix", []string{}, "")
+	assert.NoError(t, cmd.Flags().Parse(flags))
+
+	ee, err := newExprEvaluator(context.Background(), cmd, input)
+	assert.NoError(t, err)
+	return ee
+}
+
+func TestExprEvaluatorEnv(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, ".env"), []byte("FROM_FILE=file\nOVERRIDDEN=file\n"), 0o600))
+	input := &Input{
+		workdir: dir,
+		envfile: ".env",
+		envs:    []string{"GREETING=hello", "OVERRIDDEN=flag"},
+	}
+	ee := newTestExprEvaluator(t, input, "--matrix", "os=ubuntu")
+
+	for expr, expected := range map[string]string{
+		"env.GREETING":                  "hello",
+		"env.FROM_FILE":                 "file",
+		"env.OVERRIDDEN":                "file",
+		"matrix.os":                     "ubuntu",
+		"github.event_name":             "push",
+		"Hello ${{ env.GREETING }}!":    "Hello hello!",
+		"format('{0}', env.FROM_FILE)":  "file",
+		"env.GREETING == 'hello'":       "true",
+		"fromJSON('{\"a\": 1}').a":      "1",
+		"env.MISSING || 'fallback'":     "fallback",
+		"contains(env.GREETING, 'ell')": "true",
+	} {
+		value, err := evaluateExpression(context.Background(), ee, expr)
+		assert.NoError(t, err, expr)
+		assert.Equal(t, expected, value, expr)
+	}
+}
+
+func TestExprREPL(t *testing.T) {
+	ee := newTestExprEvaluator(t, &Input{workdir: t.TempDir(), envs: []string{"GREETING=hello"}})
+
+	out := &bytes.Buffer{}
+	assert.NoError(t, exprREPL(context.Background(), ee, strings.NewReader("env.GREETING\n\nfromJSON('[1, 2]')\nexit\nenv.GREETING\n"), out))
+	assert.Equal(t, "> hello\n> > [\n  1,\n  2\n]\n> ", out.String())
+}
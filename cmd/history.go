@@ -0,0 +1,510 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"text/tabwriter"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/runner"
+)
+
+const (
+	// historyMaxEntries is the number of runs kept in the history, the oldest runs are removed
+	historyMaxEntries = 200
+	// historyCancelPoll is the interval a run in progress checks its .cancel file at
+	historyCancelPoll = 250 * time.Millisecond
+	// historyRunningStale is the age of a .running file which isn't refreshed anymore, its act process is gone
+	historyRunningStale = 10 * time.Second
+)
+
+// errHistoryRunCancelled is returned by a run cancelled with act history cancel
+var errHistoryRunCancelled = errors.New("the run was cancelled with act history cancel")
+
+// historyEntry is a run recorded in the history, act history rerun runs it again with the same arguments
+type historyEntry struct {
+	ID         int           `json:"id"`
+	Workdir    string        `json:"workdir"`
+	Branch     string        `json:"branch,omitempty"` // the checked-out branch, history list shows the runs of the current one
+	Args       []string      `json:"args"`             // arguments of act, without the values of the secrets
+	Trigger    string        `json:"trigger"`          // what started the run, manual or the changes seen by --watch
+	Started    time.Time     `json:"started"`
+	Duration   time.Duration `json:"duration"`
+	Conclusion string        `json:"conclusion"` // in_progress, success, failure or cancelled
+	Error      string        `json:"error,omitempty"`
+	Jobs       []*historyJob `json:"jobs"`
+	Logs       []string      `json:"logs,omitempty"` // files of --log-sink the logs of the run were written to
+}
+
+type historyJob struct {
+	Workflow string `json:"workflow"`
+	Name     string `json:"name"`
+	Result   string `json:"result"`
+}
+
+type historyTriggerKey string
+
+const historyTriggerKeyVal = historyTriggerKey("cmd.historyTrigger")
+
+// withHistoryTrigger records what started the run in its history entry
+func withHistoryTrigger(ctx context.Context, trigger string) context.Context {
+	return context.WithValue(ctx, historyTriggerKeyVal, trigger)
+}
+
+func historyDir() string {
+	return filepath.Join(cacheLocation(), "history")
+}
+
+// newHistoryExecutor records the run in the history, it is listed in progress while it runs and act history cancel
+// cancels it like Ctrl+C
+func newHistoryExecutor(input *Input, executor common.Executor) common.Executor {
+	return func(ctx context.Context) error {
+		if common.Dryrun(ctx) {
+			return executor(ctx)
+		}
+		results := runner.ResultsFrom(ctx)
+		if results == nil {
+			results = &runner.Results{}
+			ctx = runner.WithResults(ctx, results)
+		}
+
+		trigger, _ := ctx.Value(historyTriggerKeyVal).(string)
+		if trigger == "" {
+			trigger = "manual"
+		}
+		started := time.Now()
+		entry := &historyEntry{
+			Workdir:    input.Workdir(),
+			Branch:     currentBranch(ctx, input.Workdir()),
+			Args:       redactSecretArgs(os.Args[1:]),
+			Trigger:    trigger,
+			Started:    started,
+			Conclusion: "in_progress",
+			Jobs:       make([]*historyJob, 0),
+			Logs:       logSinkFiles(input.logSinks),
+		}
+		dir := historyDir()
+		reserveErr := recordHistory(dir, entry)
+		if reserveErr != nil {
+			log.Warnf("Unable to record the run in the history: %v", reserveErr)
+		}
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		cancelled := func() bool { return false }
+		if reserveErr == nil {
+			var stop func()
+			cancelled, stop = watchHistoryCancel(dir, entry.ID, cancel)
+			defer stop()
+		}
+
+		err := executor(ctx)
+
+		entry.Duration = time.Since(started).Round(time.Millisecond)
+		entry.Conclusion = "success"
+		if err != nil {
+			entry.Conclusion = "failure"
+			if ctx.Err() != nil {
+				entry.Conclusion = "cancelled"
+			}
+			entry.Error = err.Error()
+		}
+		for _, job := range results.Jobs {
+			entry.Jobs = append(entry.Jobs, &historyJob{Workflow: job.Workflow, Name: job.Name, Result: job.Result})
+		}
+		if reserveErr == nil {
+			if recordErr := writeHistoryEntry(dir, entry); recordErr != nil {
+				log.Warnf("Unable to record the run in the history: %v", recordErr)
+			} else {
+				log.Debugf("Recorded the run as %d in the history", entry.ID)
+			}
+		}
+		if err != nil && cancelled() {
+			return errHistoryRunCancelled
+		}
+		return err
+	}
+}
+
+// watchHistoryCancel refreshes the .running file of the run in progress and cancels it once its .cancel file is
+// created, cancelled reports whether it did. The returned stop func removes both files.
+func watchHistoryCancel(dir string, id int, cancel context.CancelFunc) (cancelled func() bool, stop func()) {
+	runningFile := filepath.Join(dir, fmt.Sprintf("%d.running", id))
+	cancelFile := filepath.Join(dir, fmt.Sprintf("%d.cancel", id))
+	_ = os.WriteFile(runningFile, nil, 0o644)
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	var requested int32
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(historyCancelPoll)
+		defer ticker.Stop()
+		refreshed := time.Now()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+			if _, err := os.Stat(cancelFile); err == nil && atomic.LoadInt32(&requested) == 0 {
+				log.Infof("Cancelling the run %d, requested with act history cancel", id)
+				atomic.StoreInt32(&requested, 1)
+				cancel()
+			}
+			if time.Since(refreshed) > historyRunningStale/4 {
+				refreshed = time.Now()
+				_ = os.Chtimes(runningFile, refreshed, refreshed)
+			}
+		}
+	}()
+	return func() bool { return atomic.LoadInt32(&requested) == 1 }, func() {
+		close(done)
+		<-stopped
+		_ = os.Remove(runningFile)
+		_ = os.Remove(cancelFile)
+	}
+}
+
+// cancelHistoryRun requests the act process running the entry to cancel it
+func cancelHistoryRun(dir string, entry *historyEntry) error {
+	if entry.Conclusion != "in_progress" {
+		return fmt.Errorf("run %d is not in progress, it concluded with %s", entry.ID, entry.Conclusion)
+	}
+	info, err := os.Stat(filepath.Join(dir, fmt.Sprintf("%d.running", entry.ID)))
+	if err != nil || time.Since(info.ModTime()) > historyRunningStale {
+		return fmt.Errorf("run %d is not in progress, its act process is gone", entry.ID)
+	}
+	return os.WriteFile(filepath.Join(dir, fmt.Sprintf("%d.cancel", entry.ID)), nil, 0o644)
+}
+
+// redactSecretArgs removes the values of the secrets passed with -s from the arguments
+func redactSecretArgs(args []string) []string {
+	redacted := make([]string, 0, len(args))
+	secret := false
+	for _, arg := range args {
+		switch {
+		case secret:
+			arg, _, _ = strings.Cut(arg, "=")
+			secret = false
+		case arg == "-s" || arg == "--secret":
+			secret = true
+		case strings.HasPrefix(arg, "--secret="):
+			arg, _, _ = strings.Cut(strings.TrimPrefix(arg, "--secret="), "=")
+			arg = "--secret=" + arg
+		case strings.HasPrefix(arg, "-s") && !strings.HasPrefix(arg, "--"):
+			arg, _, _ = strings.Cut(arg, "=")
+		}
+		redacted = append(redacted, arg)
+	}
+	return redacted
+}
+
+// logSinkFiles returns the files of the log sinks
+func logSinkFiles(specs []string) []string {
+	files := make([]string, 0)
+	for _, spec := range specs {
+		kind, target, _ := strings.Cut(spec, ":")
+		if (kind != "file" && kind != "json") || target == "" || target == "-" {
+			continue
+		}
+		if abs, err := filepath.Abs(target); err == nil {
+			target = abs
+		}
+		files = append(files, target)
+	}
+	return files
+}
+
+// recordHistory adds the entry with the next ID to the history and removes the oldest entries exceeding
+// historyMaxEntries
+func recordHistory(dir string, entry *historyEntry) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	entries, err := readHistory(dir)
+	if err != nil {
+		return err
+	}
+	entry.ID = 1
+	if len(entries) > 0 {
+		entry.ID = entries[len(entries)-1].ID + 1
+	}
+
+	content, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return err
+	}
+	for {
+		// another act may record a run concurrently
+		f, err := os.OpenFile(filepath.Join(dir, fmt.Sprintf("%d.json", entry.ID)), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+		if errors.Is(err, os.ErrExist) {
+			entry.ID++
+			continue
+		} else if err != nil {
+			return err
+		}
+		_, err = f.Write(content)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return err
+		}
+		break
+	}
+
+	for i := 0; i < len(entries)+1-historyMaxEntries; i++ {
+		_ = os.Remove(filepath.Join(dir, fmt.Sprintf("%d.json", entries[i].ID)))
+	}
+	return nil
+}
+
+// writeHistoryEntry replaces the entry recorded in the history with recordHistory
+func writeHistoryEntry(dir string, entry *historyEntry) error {
+	content, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return err
+	}
+	// the entry is replaced at once, act history may read it concurrently
+	tmp, err := os.CreateTemp(dir, fmt.Sprintf(".%d-*.json", entry.ID))
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(content)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), filepath.Join(dir, fmt.Sprintf("%d.json", entry.ID)))
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+	}
+	return err
+}
+
+// readHistory returns the entries of the history ordered by their ID
+func readHistory(dir string) ([]*historyEntry, error) {
+	files, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	entries := make([]*historyEntry, 0, len(files))
+	for _, file := range files {
+		id, err := strconv.Atoi(strings.TrimSuffix(file.Name(), ".json"))
+		if err != nil || file.IsDir() {
+			continue
+		}
+		entry, err := readHistoryEntry(dir, id)
+		if err != nil {
+			log.Debugf("Skipping the history entry %s: %v", file.Name(), err)
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].ID < entries[j].ID })
+	return entries, nil
+}
+
+func readHistoryEntry(dir string, id int) (*historyEntry, error) {
+	content, err := os.ReadFile(filepath.Join(dir, fmt.Sprintf("%d.json", id)))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("run %d is not in the history", id)
+	} else if err != nil {
+		return nil, err
+	}
+	entry := &historyEntry{}
+	if err := json.Unmarshal(content, entry); err != nil {
+		return nil, err
+	}
+	entry.ID = id
+	return entry, nil
+}
+
+func newHistoryCommand(ctx context.Context, input *Input) *cobra.Command {
+	historyCmd := &cobra.Command{
+		Use:   "history",
+		Short: "List, show, rerun and cancel the runs of act recorded in the history",
+		Args:  cobra.NoArgs,
+	}
+
+	var all bool
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the runs of the working directory on the checked-out branch, the latest last",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entries, err := readHistory(historyDir())
+			if err != nil {
+				return err
+			}
+			return printHistory(os.Stdout, entries, input.Workdir(), currentBranch(ctx, input.Workdir()), all)
+		},
+	}
+	listCmd.Flags().BoolVarP(&all, "all", "", false, "list the runs of all working directories and branches")
+
+	showCmd := &cobra.Command{
+		Use:   "show <id>",
+		Short: "Show a run with the results of its jobs",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entry, err := historyEntryArg(args[0])
+			if err != nil {
+				return err
+			}
+			return printHistoryEntry(os.Stdout, entry)
+		},
+	}
+
+	rerunCmd := &cobra.Command{
+		Use:   "rerun <id>",
+		Short: "Run act again with the arguments of a run in its working directory, secrets passed with -s are prompted for",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entry, err := historyEntryArg(args[0])
+			if err != nil {
+				return err
+			}
+			executable, err := os.Executable()
+			if err != nil {
+				return err
+			}
+			rerunArgs := withoutWatchArgs(entry.Args)
+			log.Infof("Rerunning %d: act %s", entry.ID, strings.Join(rerunArgs, " "))
+			rerun := exec.CommandContext(ctx, executable, rerunArgs...)
+			rerun.Dir = entry.Workdir
+			rerun.Stdin = os.Stdin
+			rerun.Stdout = os.Stdout
+			rerun.Stderr = os.Stderr
+			return rerun.Run()
+		},
+	}
+
+	cancelCmd := &cobra.Command{
+		Use:   "cancel <id>",
+		Short: "Cancel a run in progress, like Ctrl+C in its act process",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entry, err := historyEntryArg(args[0])
+			if err != nil {
+				return err
+			}
+			if err := cancelHistoryRun(historyDir(), entry); err != nil {
+				return err
+			}
+			log.Infof("Requested the cancellation of the run %d", entry.ID)
+			return nil
+		},
+	}
+
+	historyCmd.AddCommand(listCmd, showCmd, rerunCmd, cancelCmd)
+	return historyCmd
+}
+
+func historyEntryArg(arg string) (*historyEntry, error) {
+	id, err := strconv.Atoi(arg)
+	if err != nil {
+		return nil, fmt.Errorf("invalid run '%s', expected the ID of a run of act history list", arg)
+	}
+	return readHistoryEntry(historyDir(), id)
+}
+
+// withoutWatchArgs removes --watch from the arguments, a rerun runs once
+func withoutWatchArgs(args []string) []string {
+	result := make([]string, 0, len(args))
+	for _, arg := range args {
+		if arg == "-w" || arg == "--watch" || strings.HasPrefix(arg, "--watch=") {
+			continue
+		}
+		result = append(result, arg)
+	}
+	return result
+}
+
+// printHistory lists the runs of the working directory on the branch, the runs recorded without a branch are listed
+// on any branch
+func printHistory(out io.Writer, entries []*historyEntry, workdir string, branch string, all bool) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tSTARTED\tDURATION\tCONCLUSION\tBRANCH\tTRIGGER\tARGS")
+	for _, entry := range entries {
+		if !all && (entry.Workdir != workdir || (entry.Branch != "" && branch != "" && entry.Branch != branch)) {
+			continue
+		}
+		entryBranch := entry.Branch
+		if entryBranch == "" {
+			entryBranch = "-"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n", entry.ID, entry.Started.Format(time.RFC3339), entry.Duration.Round(time.Second), entry.Conclusion, entryBranch, entry.Trigger, strings.Join(entry.Args, " "))
+	}
+	return w.Flush()
+}
+
+func printHistoryEntry(out io.Writer, entry *historyEntry) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Run:\t%d\n", entry.ID)
+	fmt.Fprintf(w, "Directory:\t%s\n", entry.Workdir)
+	if entry.Branch != "" {
+		fmt.Fprintf(w, "Branch:\t%s\n", entry.Branch)
+	}
+	fmt.Fprintf(w, "Arguments:\t%s\n", strings.Join(entry.Args, " "))
+	fmt.Fprintf(w, "Trigger:\t%s\n", entry.Trigger)
+	fmt.Fprintf(w, "Started:\t%s\n", entry.Started.Format(time.RFC3339))
+	fmt.Fprintf(w, "Duration:\t%s\n", entry.Duration)
+	fmt.Fprintf(w, "Conclusion:\t%s\n", entry.Conclusion)
+	if entry.Error != "" {
+		fmt.Fprintf(w, "Error:\t%s\n", entry.Error)
+	}
+	for _, logs := range entry.Logs {
+		fmt.Fprintf(w, "Logs:\t%s\n", logs)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if len(entry.Jobs) == 0 {
+		return nil
+	}
+	fmt.Fprintln(out)
+	w = tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "WORKFLOW\tJOB\tRESULT")
+	for _, job := range entry.Jobs {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", job.Workflow, job.Name, job.Result)
+	}
+	return w.Flush()
+}
+
+// watchTrigger describes the changes of the files in dir that started a run of --watch
+func watchTrigger(dir string, changes ...[]string) string {
+	files := make([]string, 0)
+	for _, changed := range changes {
+		files = append(files, changed...)
+	}
+	if len(files) == 0 {
+		return "watch"
+	}
+	file := files[0]
+	if rel, err := filepath.Rel(dir, file); err == nil {
+		file = rel
+	}
+	if len(files) > 1 {
+		return fmt.Sprintf("watch: %s (+%d more)", file, len(files)-1)
+	}
+	return "watch: " + file
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactSecretArgs(t *testing.T) {
+	args := []string{"push", "-s", "TOKEN=abc", "--secret=KEY=def", "-sPASSWORD=ghi", "--secret", "NAME", "--env", "FOO=bar", "--secret-file", "my.secrets"}
+	assert.Equal(t, []string{"push", "-s", "TOKEN", "--secret=KEY", "-sPASSWORD", "--secret", "NAME", "--env", "FOO=bar", "--secret-file", "my.secrets"}, redactSecretArgs(args))
+}
+
+func TestRecordHistory(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "history")
+
+	entries, err := readHistory(dir)
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+
+	started := time.Date(2023, 4, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		entry := &historyEntry{
+			Workdir:    "/src/project",
+			Args:       []string{"push", "-j", "test"},
+			Trigger:    "manual",
+			Started:    started,
+			Duration:   90 * time.Second,
+			Conclusion: "success",
+			Jobs:       []*historyJob{{Workflow: ".github/workflows/ci.yml", Name: "test", Result: "success"}},
+		}
+		assert.NoError(t, recordHistory(dir, entry))
+		assert.Equal(t, i+1, entry.ID)
+	}
+
+	entries, err = readHistory(dir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 3)
+	assert.Equal(t, []*historyJob{{Workflow: ".github/workflows/ci.yml", Name: "test", Result: "success"}}, entries[2].Jobs)
+
+	entry, err := readHistoryEntry(dir, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, entry.ID)
+	assert.Equal(t, started, entry.Started.UTC())
+
+	_, err = readHistoryEntry(dir, 4)
+	assert.EqualError(t, err, "run 4 is not in the history")
+
+	out := &bytes.Buffer{}
+	assert.NoError(t, printHistory(out, entries, "/src/other", "", false))
+	assert.Equal(t, "ID  STARTED  DURATION  CONCLUSION  BRANCH  TRIGGER  ARGS\n", out.String())
+}
+
+func TestCancelHistoryRun(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "history")
+	entry := &historyEntry{Workdir: "/src/project", Args: []string{"push"}, Trigger: "manual", Conclusion: "in_progress"}
+	assert.NoError(t, recordHistory(dir, entry))
+
+	assert.EqualError(t, cancelHistoryRun(dir, entry), "run 1 is not in progress, its act process is gone")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cancelled, stop := watchHistoryCancel(dir, entry.ID, cancel)
+	assert.False(t, cancelled())
+	assert.NoError(t, cancelHistoryRun(dir, entry))
+	select {
+	case <-ctx.Done():
+	case <-time.After(10 * historyCancelPoll):
+		t.Fatal("the run was not cancelled")
+	}
+	assert.True(t, cancelled())
+	stop()
+	_, err := os.Stat(filepath.Join(dir, "1.running"))
+	assert.True(t, os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(dir, "1.cancel"))
+	assert.True(t, os.IsNotExist(err))
+
+	entry.Conclusion = "cancelled"
+	assert.NoError(t, writeHistoryEntry(dir, entry))
+	entries, err := readHistory(dir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "cancelled", entries[0].Conclusion)
+	assert.EqualError(t, cancelHistoryRun(dir, entries[0]), "run 1 is not in progress, it concluded with cancelled")
+}
+
+func TestPrintHistoryBranch(t *testing.T) {
+	started := time.Date(2023, 4, 1, 12, 0, 0, 0, time.UTC)
+	entries := []*historyEntry{
+		{ID: 1, Workdir: "/src/project", Started: started, Conclusion: "success", Trigger: "manual"},
+		{ID: 2, Workdir: "/src/project", Branch: "main", Started: started, Conclusion: "success", Trigger: "manual"},
+		{ID: 3, Workdir: "/src/project", Branch: "feature", Started: started, Conclusion: "failure", Trigger: "watch"},
+	}
+	ids := func(branch string, all bool) []string {
+		out := &bytes.Buffer{}
+		assert.NoError(t, printHistory(out, entries, "/src/project", branch, all))
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")[1:]
+		result := make([]string, 0, len(lines))
+		for _, line := range lines {
+			result = append(result, strings.Fields(line)[0])
+		}
+		return result
+	}
+
+	assert.Equal(t, []string{"1", "3"}, ids("feature", false))
+	assert.Equal(t, []string{"1", "2"}, ids("main", false))
+	assert.Equal(t, []string{"1", "2", "3"}, ids("", false))
+	assert.Equal(t, []string{"1", "2", "3"}, ids("feature", true))
+}
+
+func TestWatchTrigger(t *testing.T) {
+	assert.Equal(t, "watch", watchTrigger("/src/project"))
+	assert.Equal(t, "watch: main.go", watchTrigger("/src/project", nil, nil, []string{"/src/project/main.go"}))
+	assert.Equal(t, "watch: go.mod (+2 more)", watchTrigger("/src/project", []string{"/src/project/go.mod"}, []string{"/src/project/a.go"}, []string{"/src/project/b.go"}))
+}
+
+func TestWithoutWatchArgs(t *testing.T) {
+	assert.Equal(t, []string{"push", "-j", "test"}, withoutWatchArgs([]string{"push", "-w", "-j", "test", "--watch"}))
+}
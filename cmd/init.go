@@ -0,0 +1,229 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// starterWorkflows are the CI workflows act init scaffolds by language, %[1]s is the default branch
+var starterWorkflows = map[string]string{
+	"go": `name: CI
+
+on:
+  push:
+    branches: [ "%[1]s" ]
+  pull_request:
+    branches: [ "%[1]s" ]
+
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v3
+      - uses: actions/setup-go@v4
+        with:
+          go-version-file: go.mod
+      - name: Build
+        run: go build -v ./...
+      - name: Test
+        run: go test -v ./...
+`,
+	"node": `name: CI
+
+on:
+  push:
+    branches: [ "%[1]s" ]
+  pull_request:
+    branches: [ "%[1]s" ]
+
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v3
+      - uses: actions/setup-node@v3
+        with:
+          node-version: 18
+      - run: npm ci
+      - run: npm run build --if-present
+      - run: npm test
+`,
+	"python": `name: CI
+
+on:
+  push:
+    branches: [ "%[1]s" ]
+  pull_request:
+    branches: [ "%[1]s" ]
+
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v3
+      - uses: actions/setup-python@v4
+        with:
+          python-version: "3.11"
+      - name: Install dependencies
+        run: |
+          python -m pip install --upgrade pip
+          pip install pytest
+          if [ -f requirements.txt ]; then pip install -r requirements.txt; fi
+      - name: Test
+        run: pytest
+`,
+	"other": `name: CI
+
+on:
+  push:
+    branches: [ "%[1]s" ]
+  pull_request:
+    branches: [ "%[1]s" ]
+
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v3
+      - name: Build
+        run: echo "Add the commands building and testing the project"
+`,
+}
+
+// languageFiles are the files of the projects of the languages of the starter workflows
+var languageFiles = []struct {
+	language string
+	files    []string
+}{
+	{"go", []string{"go.mod"}},
+	{"node", []string{"package.json"}},
+	{"python", []string{"pyproject.toml", "requirements.txt", "setup.py"}},
+}
+
+// detectLanguage returns the language of the project in dir by its files, other if none is found
+func detectLanguage(dir string) string {
+	for _, lf := range languageFiles {
+		for _, file := range lf.files {
+			if _, err := os.Stat(filepath.Join(dir, file)); err == nil {
+				return lf.language
+			}
+		}
+	}
+	return "other"
+}
+
+// initOptions are the flags of act init, the prompts ask for the ones which aren't set
+type initOptions struct {
+	language string
+	image    string
+	yes      bool
+	force    bool
+}
+
+func newInitCommand(ctx context.Context, input *Input) *cobra.Command {
+	opts := &initOptions{}
+	initCmd := &cobra.Command{
+		Use:   "init",
+		Short: "Create a starter .github/workflows/ci.yml for the language of the project (Go, Node or Python) and a .actrc with the images of its platforms",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir := input.Workdir()
+			if opts.language == "" {
+				opts.language = detectLanguage(dir)
+			}
+			if opts.image == "" {
+				opts.image = "Medium"
+			}
+			if !opts.yes && term.IsTerminal(int(os.Stdin.Fd())) {
+				if err := askInitOptions(opts); err != nil {
+					return err
+				}
+			}
+			branch := input.defaultBranch
+			if branch == "" {
+				branch = currentBranch(ctx, dir)
+			}
+			if branch == "" {
+				branch = "main"
+			}
+			return scaffold(dir, opts, branch)
+		},
+	}
+	initCmd.Flags().StringVar(&opts.language, "language", "", "language of the starter workflow: go, node, python or other, detected from go.mod, package.json, pyproject.toml, requirements.txt or setup.py if empty")
+	initCmd.Flags().StringVar(&opts.image, "image", "", "size of the image of ubuntu-latest in .actrc: Large, Medium or Micro, Medium if empty")
+	initCmd.Flags().BoolVarP(&opts.yes, "yes", "y", false, "don't prompt, use the detected language and the flags")
+	initCmd.Flags().BoolVarP(&opts.force, "force", "f", false, "overwrite the existing ci.yml and .actrc")
+	initCmd.Flags().StringVar(&input.defaultBranch, "defaultbranch", "", "the branch the workflow runs for, the checked-out branch if empty")
+	return initCmd
+}
+
+// askInitOptions prompts for the language and the image size, the options are the defaults
+func askInitOptions(opts *initOptions) error {
+	languages := make([]string, 0, len(starterWorkflows))
+	for language := range starterWorkflows {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+	if err := survey.AskOne(&survey.Select{
+		Message: "Language of the starter workflow:",
+		Options: languages,
+		Default: opts.language,
+	}, &opts.language); err != nil {
+		return err
+	}
+	image, err := askDefaultImage()
+	if err != nil {
+		return err
+	}
+	opts.image = image
+	return nil
+}
+
+// scaffold writes the starter workflow and the .actrc of the options in dir, an existing .actrc is kept unless
+// forced
+func scaffold(dir string, opts *initOptions, branch string) error {
+	workflow, ok := starterWorkflows[strings.ToLower(opts.language)]
+	if !ok {
+		return fmt.Errorf("invalid language '%s', expected go, node, python or other", opts.language)
+	}
+	actrc, ok := defaultImageOptions[opts.image]
+	if !ok {
+		return fmt.Errorf("invalid image '%s', expected Large, Medium or Micro", opts.image)
+	}
+
+	workflowFile := filepath.Join(dir, ".github", "workflows", "ci.yml")
+	if _, err := os.Stat(workflowFile); err == nil && !opts.force {
+		return fmt.Errorf("%s already exists, pass --force to overwrite it", workflowFile)
+	}
+	if err := os.MkdirAll(filepath.Dir(workflowFile), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(workflowFile, []byte(fmt.Sprintf(workflow, branch)), 0o644); err != nil {
+		return err
+	}
+	log.Infof("Created %s for %s", workflowFile, strings.ToLower(opts.language))
+
+	actrcFile := filepath.Join(dir, ".actrc")
+	if _, err := os.Stat(actrcFile); err == nil && !opts.force {
+		log.Infof("Kept the existing %s", actrcFile)
+	} else if err == nil || errors.Is(err, os.ErrNotExist) {
+		if err := os.WriteFile(actrcFile, []byte(actrc), 0o644); err != nil {
+			return err
+		}
+		log.Infof("Created %s with the %s images", actrcFile, strings.ToLower(opts.image))
+	} else {
+		return err
+	}
+	log.Infof("Run the workflow with: act push")
+	return nil
+}
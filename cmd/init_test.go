@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nektos/act/pkg/model"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	for file, language := range map[string]string{
+		"go.mod":           "go",
+		"package.json":     "node",
+		"pyproject.toml":   "python",
+		"requirements.txt": "python",
+		"README.md":        "other",
+	} {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, file), nil, 0o644))
+		assert.Equal(t, language, detectLanguage(dir), file)
+	}
+}
+
+func TestStarterWorkflows(t *testing.T) {
+	for language := range starterWorkflows {
+		dir := t.TempDir()
+		require.NoError(t, scaffold(dir, &initOptions{language: language, image: "Medium"}, "main"))
+
+		content, err := os.ReadFile(filepath.Join(dir, ".github", "workflows", "ci.yml"))
+		require.NoError(t, err)
+		assert.Empty(t, model.ValidateWorkflow("ci.yml", content, nil), language)
+		assert.Contains(t, string(content), `branches: [ "main" ]`)
+
+		actrc, err := os.ReadFile(filepath.Join(dir, ".actrc"))
+		require.NoError(t, err)
+		assert.Equal(t, defaultImageOptions["Medium"], string(actrc))
+	}
+}
+
+func TestScaffoldExisting(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".actrc"), []byte("-P ubuntu-latest=custom\n"), 0o644))
+	require.NoError(t, scaffold(dir, &initOptions{language: "go", image: "Micro"}, "main"))
+
+	// the existing .actrc is kept
+	actrc, err := os.ReadFile(filepath.Join(dir, ".actrc"))
+	require.NoError(t, err)
+	assert.Equal(t, "-P ubuntu-latest=custom\n", string(actrc))
+
+	err = scaffold(dir, &initOptions{language: "node", image: "Micro"}, "main")
+	assert.ErrorContains(t, err, "ci.yml already exists, pass --force to overwrite it")
+
+	require.NoError(t, scaffold(dir, &initOptions{language: "node", image: "Micro", force: true}, "main"))
+	actrc, err = os.ReadFile(filepath.Join(dir, ".actrc"))
+	require.NoError(t, err)
+	assert.Equal(t, defaultImageOptions["Micro"], string(actrc))
+
+	assert.EqualError(t, scaffold(dir, &initOptions{language: "rust", image: "Micro", force: true}, "main"), "invalid language 'rust', expected go, node, python or other")
+}
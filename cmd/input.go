@@ -1,8 +1,20 @@
 package cmd
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/container"
+	"github.com/nektos/act/pkg/githubapi"
+	"github.com/nektos/act/pkg/logsink"
+	"github.com/nektos/act/pkg/model"
+	"github.com/nektos/act/pkg/runner"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -11,6 +23,7 @@ type Input struct {
 	actor                              string
 	workdir                            string
 	workflowsPath                      string
+	workflowStdin                      bool
 	autodetectEvent                    bool
 	eventPath                          string
 	reuseContainers                    bool
@@ -28,25 +41,98 @@ type Input struct {
 	secretfile                         string
 	insecureSecrets                    bool
 	defaultBranch                      string
+	watchBranch                        string // the branch of the previous run of --watch
+	protectedBranches                  []string
 	privileged                         bool
 	usernsMode                         string
 	containerArchitecture              string
 	containerDaemonSocket              string
+	containerBackend                   string
 	containerOptions                   string
 	noWorkflowRecurse                  bool
 	useGitIgnore                       bool
 	githubInstance                     string
+	githubServerURL                    string
+	githubAPIURL                       string
+	githubGraphQLURL                   string
 	containerCapAdd                    []string
 	containerCapDrop                   []string
+	containerUsers                     []string
 	autoRemove                         bool
 	artifactServerPath                 string
 	artifactServerAddr                 string
 	artifactServerPort                 string
+	cacheServerPath                    string
+	cacheServerPort                    string
+	fixturesServerPath                 string
+	fixturesServerPort                 string
+	concurrencyDir                     string
 	jsonLogger                         bool
 	noSkipCheckout                     bool
 	remoteName                         string
 	replaceGheActionWithGithubCom      []string
 	replaceGheActionTokenWithGithubCom string
+	composeServices                    string
+	repos                              []string
+	parallelRepos                      bool
+	simulateCI                         bool
+	assertFile                         string
+	mockActions                        []string
+	mockActionsFile                    string
+	injectSteps                        []string
+	simulatePermissions                bool
+	runAttempt                         int
+	autoStartVM                        string
+	stopVM                             bool
+	preflight                          bool
+	logSinks                           []string
+	configFile                         string
+	contextFile                        string
+	secretCacheMode                    string
+	secretCache                        *secretCache // the secrets entered at the prompt, with --secret-cache session
+	outputSizeLimit                    int
+	envSizeLimit                       int
+	strictLimits                       bool
+	propagateEnvToNeeds                bool
+	workflowPrefix                     string
+	experimentalGoActions              bool
+	runnerManifest                     string
+	logPrefix                          string
+	matrix                             []string
+	matrixWorkspace                    string
+	installCA                          []string
+	sshAgent                           bool
+	sshAgentKeys                       []string
+	gitUserName                        string
+	gitUserEmail                       string
+	gitSigningKey                      string
+	downloadArtifacts                  string
+	summaryPath                        string
+	downloadArtifactNames              []string
+	cacheMaxSize                       string
+	cacheVolumes                       []string
+	frozenImageLock                    bool
+	localRegistry                      string
+	useHost                            bool
+	engines                            []string
+	githubScript                       string
+	hangTimeout                        time.Duration
+	killHungSteps                      bool
+	stepDebug                          bool
+	skipUnsupported                    bool
+	shellOnError                       bool
+	concurrentJobs                     int
+	exportWorkspace                    string
+	containerRuntime                   string
+	containerCreatePatch               string
+	stepDebugger                       *runner.StepDebugger
+	traceExpressions                   bool
+	graphqlFixtures                    string
+	reportToGithub                     bool
+	progressInterval                   time.Duration
+	repository                         string
+	sinks                              []logsink.Sink
+	runtimeTokens                      *common.RuntimeTokens
 }
 
 func (i *Input) resolve(path string) string {
@@ -92,3 +178,207 @@ func (i *Input) EventPath() string {
 func (i *Input) Inputfile() string {
 	return i.resolve(i.inputfile)
 }
+
+// ComposeServices returns the path to the docker-compose file providing services
+func (i *Input) ComposeServices() string {
+	return i.resolve(i.composeServices)
+}
+
+// AssertFile returns the path to the assertions file
+func (i *Input) AssertFile() string {
+	return i.resolve(i.assertFile)
+}
+
+// GraphQLFixtures returns the responses of the GraphQL API served instead of GitHub, nil without --graphql-fixtures
+func (i *Input) GraphQLFixtures() ([]*githubapi.GraphQLFixture, error) {
+	if i.graphqlFixtures == "" {
+		return nil, nil
+	}
+	return githubapi.ReadGraphQLFixtures(i.resolve(i.graphqlFixtures))
+}
+
+// ContainerCreateHooks returns the hooks changing the requests creating the containers, the patch of
+// --container-create-patch
+func (i *Input) ContainerCreateHooks() ([]container.CreateHook, error) {
+	if i.containerCreatePatch == "" {
+		return nil, nil
+	}
+	hook, err := container.ReadCreatePatchHook(i.resolve(i.containerCreatePatch))
+	if err != nil {
+		return nil, err
+	}
+	return []container.CreateHook{hook}, nil
+}
+
+// MockActionsFile returns the path to the file with the action mocks
+func (i *Input) MockActionsFile() string {
+	return i.resolve(i.mockActionsFile)
+}
+
+// ContextOverrides reads the overrides of the contexts from the context file, nil if there is none
+func (i *Input) ContextOverrides() (*runner.ContextOverrides, error) {
+	if i.contextFile == "" {
+		return nil, nil
+	}
+	return runner.ReadContextOverrides(i.resolve(i.contextFile))
+}
+
+// RunnerManifest returns the tools to install into the tool cache of the jobs, nil without --runner-manifest
+func (i *Input) RunnerManifest() (*runner.RunnerManifest, error) {
+	if i.runnerManifest == "" {
+		return nil, nil
+	}
+	return runner.ReadRunnerManifest(i.resolve(i.runnerManifest))
+}
+
+// CACertificates returns the bundle of the certificates to install into the job containers, empty without --install-ca
+func (i *Input) CACertificates() (string, error) {
+	if len(i.installCA) == 0 {
+		return "", nil
+	}
+	paths := make([]string, 0, len(i.installCA))
+	for _, p := range i.installCA {
+		paths = append(paths, i.resolve(p))
+	}
+	return runner.ReadCACertificates(paths)
+}
+
+// SSHAgentKeys returns the paths to the keys of the SSH agent scoped to the run
+func (i *Input) SSHAgentKeys() []string {
+	keys := make([]string, 0, len(i.sshAgentKeys))
+	for _, key := range i.sshAgentKeys {
+		keys = append(keys, i.resolve(key))
+	}
+	return keys
+}
+
+// GitIdentity returns the git identity to configure in the job containers, nil without --git-user-name,
+// --git-user-email and --git-signing-key
+func (i *Input) GitIdentity() (*runner.GitIdentity, error) {
+	if i.gitUserName == "" && i.gitUserEmail == "" && i.gitSigningKey == "" {
+		return nil, nil
+	}
+	identity := &runner.GitIdentity{
+		UserName:  i.gitUserName,
+		UserEmail: i.gitUserEmail,
+	}
+	if i.gitSigningKey != "" {
+		key, err := runner.ReadGitSigningKey(i.resolve(i.gitSigningKey))
+		if err != nil {
+			return nil, err
+		}
+		identity.SigningKey = key
+	}
+	return identity, nil
+}
+
+// CacheVolumes returns the paths the cache volumes of --cache-volume are mounted at in the job containers by name
+func (i *Input) CacheVolumes() (map[string]string, error) {
+	return runner.ParseCacheVolumes(i.cacheVolumes)
+}
+
+// ContainerUsers returns the users the job containers run as by platform
+func (i *Input) ContainerUsers() (map[string]string, error) {
+	return runner.ParseContainerUsers(i.containerUsers)
+}
+
+// Matrix returns the values of the matrix keys selecting the legs of the matrix jobs to run
+func (i *Input) Matrix() (map[string]map[string]bool, error) {
+	return runner.ParseMatrixSelection(i.matrix)
+}
+
+// EngineRoutes returns the engines running the jobs by the labels of their runs-on, the docker hosts of the VMs are
+// the sockets forwarded from them
+func (i *Input) EngineRoutes() ([]*runner.EngineRoute, error) {
+	routes, err := runner.ParseEngineRoutes(i.engines)
+	if err != nil {
+		return nil, err
+	}
+	for _, route := range routes {
+		if route.VM == "" {
+			continue
+		}
+		vm, err := parseDockerVM(route.VM)
+		if err != nil {
+			return nil, err
+		}
+		if socket := vm.socket(); socket != "" {
+			route.DockerHost = "unix://" + socket
+		}
+	}
+	return routes, nil
+}
+
+// ContainerBackend returns the container engine running the containers
+func (i *Input) ContainerBackend() (container.Backend, error) {
+	return container.ParseBackend(i.containerBackend)
+}
+
+// containerContext returns the context whose containers run on the backend of --container-backend
+func (i *Input) containerContext(ctx context.Context) (context.Context, error) {
+	backend, err := i.ContainerBackend()
+	if err != nil {
+		return nil, err
+	}
+	return container.WithBackend(ctx, backend), nil
+}
+
+// ImageLockFile returns the path to the lockfile pinning the images of the workflows
+func (i *Input) ImageLockFile() string {
+	return i.resolve(runner.ImageLockFile)
+}
+
+// ImageLock returns the digests the images of the workflows are pinned to, nil if the working directory has no
+// lockfile. With --frozen the lockfile is required.
+func (i *Input) ImageLock() (*runner.ImageLock, error) {
+	lock, err := runner.ReadImageLock(i.ImageLockFile())
+	if errors.Is(err, os.ErrNotExist) {
+		if i.frozenImageLock {
+			return nil, fmt.Errorf("--frozen requires %s, run 'act lock' to create it", runner.ImageLockFile)
+		}
+		return nil, nil
+	}
+	return lock, err
+}
+
+// DownloadArtifacts returns the path to the directory the artifacts are extracted to after the run
+func (i *Input) DownloadArtifacts() string {
+	return i.resolve(i.downloadArtifacts)
+}
+
+// FixturesServerPath returns the directory served by the fixtures server, empty if it is disabled
+func (i *Input) FixturesServerPath() string {
+	return i.resolve(i.fixturesServerPath)
+}
+
+// SummaryPath returns the directory the summaries of the jobs are written to, empty if they are printed
+func (i *Input) SummaryPath() string {
+	return i.resolve(i.summaryPath)
+}
+
+// ConfigFile returns the path to the config file with the overrides per workflow
+func (i *Input) ConfigFile() string {
+	return i.resolve(i.configFile)
+}
+
+// ArtifactServerAddr returns the address the artifact server binds to, an IPv6 address may be given in brackets
+func (i *Input) ArtifactServerAddr() string {
+	return strings.TrimSuffix(strings.TrimPrefix(i.artifactServerAddr, "["), "]")
+}
+
+// workflowTemplateValues returns the values of the placeholders of workflow templates, the main branch defaults to
+// master as in the events of act
+func (i *Input) workflowTemplateValues() *model.WorkflowTemplateValues {
+	values := &model.WorkflowTemplateValues{
+		DefaultBranch:     i.defaultBranch,
+		ProtectedBranches: i.protectedBranches,
+		CronDaily:         "0 0 * * *",
+	}
+	if values.DefaultBranch == "" {
+		values.DefaultBranch = "master"
+	}
+	if len(values.ProtectedBranches) == 0 {
+		values.ProtectedBranches = []string{values.DefaultBranch}
+	}
+	return values
+}
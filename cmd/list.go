@@ -1,10 +1,15 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
 	"strconv"
 	"strings"
 
+	"gopkg.in/yaml.v3"
+
 	"github.com/nektos/act/pkg/model"
 )
 
@@ -105,3 +110,116 @@ func printList(plan *model.Plan) error {
 	}
 	return nil
 }
+
+// planJSON is the plan printed by --list-json, the workflows carry the metadata GitHub shows for a run
+type planJSON struct {
+	Workflows []workflowJSON `json:"workflows"`
+	Stages    [][]jobJSON    `json:"stages"`
+}
+
+type workflowJSON struct {
+	Name        string            `json:"name"`
+	File        string            `json:"file"`
+	RunName     string            `json:"runName,omitempty"` // the run-name as written, its expressions need the event to be evaluated
+	Events      []string          `json:"events"`
+	Env         map[string]string `json:"env,omitempty"`
+	Permissions model.Permissions `json:"permissions,omitempty"`
+}
+
+type jobJSON struct {
+	JobID    string `json:"jobID"`
+	JobName  string `json:"jobName"`
+	Workflow string `json:"workflow"` // file of the workflow
+}
+
+func printListJSON(plan *model.Plan) error {
+	out := planJSON{
+		Workflows: []workflowJSON{},
+		Stages:    make([][]jobJSON, 0, len(plan.Stages)),
+	}
+	workflows := map[string]bool{}
+	for _, stage := range plan.Stages {
+		jobs := make([]jobJSON, 0, len(stage.Runs))
+		for _, r := range stage.Runs {
+			jobs = append(jobs, jobJSON{
+				JobID:    r.JobID,
+				JobName:  r.String(),
+				Workflow: r.Workflow.File,
+			})
+			if workflows[r.Workflow.File] {
+				continue
+			}
+			workflows[r.Workflow.File] = true
+
+			permissions, err := model.ParsePermissions(r.Workflow.RawPermissions)
+			if err != nil {
+				return fmt.Errorf("%s: %w", r.Workflow.File, err)
+			}
+			out.Workflows = append(out.Workflows, workflowJSON{
+				Name:        r.Workflow.Name,
+				File:        r.Workflow.File,
+				RunName:     r.Workflow.RunName,
+				Events:      r.Workflow.On(),
+				Env:         r.Workflow.Env,
+				Permissions: permissions,
+			})
+		}
+		out.Stages = append(out.Stages, jobs)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(out)
+}
+
+// listDocument is the plan printed by --list --format json or yaml, a job per entry in the order of the stages
+type listDocument struct {
+	Jobs []listEntry `json:"jobs" yaml:"jobs"`
+}
+
+type listEntry struct {
+	Stage        int      `json:"stage" yaml:"stage"`
+	JobID        string   `json:"jobID" yaml:"jobID"`
+	JobName      string   `json:"jobName" yaml:"jobName"`
+	WorkflowName string   `json:"workflowName" yaml:"workflowName"`
+	WorkflowFile string   `json:"workflowFile" yaml:"workflowFile"`
+	Needs        []string `json:"needs" yaml:"needs"`
+	Events       []string `json:"events" yaml:"events"`
+	RunsOn       []string `json:"runsOn" yaml:"runsOn"` // as written, the expressions aren't evaluated
+}
+
+func printListDocument(w io.Writer, plan *model.Plan, format string) error {
+	doc := listDocument{Jobs: []listEntry{}}
+	for i, stage := range plan.Stages {
+		for _, r := range stage.Runs {
+			entry := listEntry{
+				Stage:        i,
+				JobID:        r.JobID,
+				JobName:      r.String(),
+				WorkflowName: r.Workflow.Name,
+				WorkflowFile: r.Workflow.File,
+				Needs:        []string{},
+				Events:       r.Workflow.On(),
+				RunsOn:       []string{},
+			}
+			entry.Needs = append(entry.Needs, r.Job().Needs()...)
+			entry.RunsOn = append(entry.RunsOn, r.Job().RunsOn()...)
+			if entry.Events == nil {
+				entry.Events = []string{}
+			}
+			doc.Jobs = append(doc.Jobs, entry)
+		}
+	}
+
+	if format == "yaml" {
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(doc); err != nil {
+			return err
+		}
+		return encoder.Close()
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(doc)
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+
+	"github.com/nektos/act/pkg/model"
+)
+
+func TestPrintListDocument(t *testing.T) {
+	workflow, err := model.ReadWorkflow(bytes.NewReader([]byte(`
+name: CI
+on: [push, pull_request]
+jobs:
+  build:
+    name: Build
+    runs-on: [self-hosted, gpu]
+    steps:
+    - run: echo
+  test:
+    needs: build
+    runs-on: ${{ matrix.os }}
+    steps:
+    - run: echo
+`)))
+	assert.NoError(t, err)
+	workflow.File = "ci.yml"
+	plan := &model.Plan{Stages: []*model.Stage{
+		{Runs: []*model.Run{{Workflow: workflow, JobID: "build"}}},
+		{Runs: []*model.Run{{Workflow: workflow, JobID: "test"}}},
+	}}
+	expected := listDocument{Jobs: []listEntry{
+		{Stage: 0, JobID: "build", JobName: "Build", WorkflowName: "CI", WorkflowFile: "ci.yml", Needs: []string{}, Events: []string{"push", "pull_request"}, RunsOn: []string{"self-hosted", "gpu"}},
+		{Stage: 1, JobID: "test", JobName: "test", WorkflowName: "CI", WorkflowFile: "ci.yml", Needs: []string{"build"}, Events: []string{"push", "pull_request"}, RunsOn: []string{"${{ matrix.os }}"}},
+	}}
+
+	out := &bytes.Buffer{}
+	assert.NoError(t, printListDocument(out, plan, "json"))
+	var doc listDocument
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &doc))
+	assert.Equal(t, expected, doc)
+	assert.Contains(t, out.String(), `"needs": []`)
+
+	out.Reset()
+	assert.NoError(t, printListDocument(out, plan, "yaml"))
+	doc = listDocument{}
+	assert.NoError(t, yaml.Unmarshal(out.Bytes(), &doc))
+	assert.Equal(t, expected, doc)
+	assert.Contains(t, out.String(), "workflowFile: ci.yml")
+}
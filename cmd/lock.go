@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/nektos/act/pkg/container"
+	"github.com/nektos/act/pkg/runner"
+)
+
+func newLockCommand(ctx context.Context, input *Input) *cobra.Command {
+	lockCmd := &cobra.Command{
+		Use:   "lock",
+		Short: "Resolve the images of the workflows to their digests and pin them in act.lock, the runs use the pinned digests",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, err := input.containerContext(ctx)
+			if err != nil {
+				return err
+			}
+			planner, err := newWorkflowPlanner(input)
+			if err != nil {
+				return err
+			}
+
+			lock := &runner.ImageLock{Images: map[string]string{}}
+			config := &runner.Config{
+				Workdir:   input.Workdir(),
+				Platforms: input.newPlatforms(),
+			}
+			for _, image := range runner.PlanImages(ctx, config, planner.PlanAll()) {
+				digest, err := container.ImageDigest(ctx, image)
+				if err != nil {
+					return err
+				}
+				lock.Images[image] = digest
+				fmt.Printf("%s@%s\n", image, digest)
+			}
+			return lock.Write(input.ImageLockFile())
+		},
+	}
+	lockCmd.Flags().StringArrayVarP(&input.platforms, "platform", "P", []string{}, "custom image to use per platform (e.g. -P ubuntu-18.04=nektos/act-environments-ubuntu:18.04)")
+	return lockCmd
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+func (i *Input) newMockActions() (map[string]string, error) {
+	mocks := map[string]string{}
+
+	if i.mockActionsFile != "" {
+		content, err := os.ReadFile(i.MockActionsFile())
+		if err != nil {
+			return nil, err
+		}
+		if err := yaml.Unmarshal(content, &mocks); err != nil {
+			return nil, fmt.Errorf("unable to read mocks from %s: %w", i.MockActionsFile(), err)
+		}
+	}
+
+	for _, m := range i.mockActions {
+		mParts := strings.SplitN(m, "=", 2)
+		if len(mParts) != 2 {
+			return nil, fmt.Errorf("invalid mock '%s', expected <action>=<path>", m)
+		}
+		mocks[mParts[0]] = mParts[1]
+	}
+
+	// mocks are run as local actions, which are resolved relative to the working directory
+	for pattern, mock := range mocks {
+		rel, err := filepath.Rel(i.Workdir(), i.resolve(mock))
+		if err != nil {
+			return nil, err
+		}
+		mocks[pattern] = "./" + filepath.ToSlash(rel)
+	}
+	return mocks, nil
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -21,16 +22,7 @@ func displayNotices(input *Input) {
 	select {
 	case notices := <-noticesLoaded:
 		if len(notices) > 0 {
-			noticeLogger := log.New()
-			if input.jsonLogger {
-				noticeLogger.SetFormatter(&log.JSONFormatter{})
-			} else {
-				noticeLogger.SetFormatter(&log.TextFormatter{
-					DisableQuote:     true,
-					DisableTimestamp: true,
-					PadLevelText:     true,
-				})
-			}
+			noticeLogger := newNoticeLogger(input)
 
 			fmt.Printf("\n")
 			for _, notice := range notices {
@@ -46,6 +38,20 @@ func displayNotices(input *Input) {
 	}
 }
 
+func newNoticeLogger(input *Input) *log.Logger {
+	noticeLogger := log.New()
+	if input.jsonLogger {
+		noticeLogger.SetFormatter(&log.JSONFormatter{})
+	} else {
+		noticeLogger.SetFormatter(&log.TextFormatter{
+			DisableQuote:     true,
+			DisableTimestamp: true,
+			PadLevelText:     true,
+		})
+	}
+	return noticeLogger
+}
+
 var noticesLoaded = make(chan []Notice)
 
 func loadVersionNotices(version string) {
@@ -86,5 +92,10 @@ func getVersionNotices(version string) []Notice {
 		return nil
 	}
 
+	if latest, err := getLatestRelease(context.Background()); err != nil {
+		log.Debug(err)
+	} else if notice := upgradeNotice(version, latest); notice != nil {
+		notices = append(notices, *notice)
+	}
 	return notices
 }
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/nektos/act/pkg/container"
+)
+
+func newPsCommand(ctx context.Context, input *Input) *cobra.Command {
+	var all bool
+
+	psCmd := &cobra.Command{
+		Use:   "ps",
+		Short: "List the containers kept by --reuse for the working directory",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, err := input.containerContext(ctx)
+			if err != nil {
+				return err
+			}
+			containers, err := container.ListReusableContainers(ctx)
+			if err != nil {
+				return err
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "NAME\tKEY\tWORKFLOW\tJOB\tBRANCH\tIMAGE\tSTATE\tAGE")
+			for _, c := range containers {
+				if !all && c.Workdir != input.Workdir() {
+					continue
+				}
+				age := time.Since(c.Created).Round(time.Second)
+				branch := c.Branch
+				if branch == "" {
+					branch = "-"
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", c.Name, c.Key, c.Workflow, c.Job, branch, c.Image, c.State, age)
+			}
+			return w.Flush()
+		},
+	}
+	psCmd.Flags().BoolVarP(&all, "all", "", false, "list the containers of all working directories")
+	return psCmd
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/common/git"
+	"github.com/nektos/act/pkg/githubapi"
+	"github.com/nektos/act/pkg/runner"
+)
+
+// githubReportContext prefixes the contexts of the statuses posted by --report-to-github, so they are not mistaken
+// for the statuses of the workflows run by GitHub
+const githubReportContext = "act (local)"
+
+// newGithubReportExecutor posts the results of the jobs as statuses of the commit checked out in the working
+// directory after the run, also if it failed
+func newGithubReportExecutor(input *Input, token string, executor common.Executor) common.Executor {
+	return func(ctx context.Context) error {
+		results := runner.ResultsFrom(ctx)
+		if results == nil {
+			results = &runner.Results{}
+			ctx = runner.WithResults(ctx, results)
+		}
+		err := executor(ctx)
+		if common.Dryrun(ctx) {
+			return err
+		}
+
+		if reportErr := reportToGithub(ctx, input, token, results.Jobs); reportErr != nil {
+			log.Errorf("Unable to report the results to GitHub: %v", reportErr)
+			if err == nil {
+				err = reportErr
+			}
+		}
+		return err
+	}
+}
+
+func reportToGithub(ctx context.Context, input *Input, token string, jobs []*runner.JobResult) error {
+	urls, err := githubapi.NewURLs(input.githubInstance)
+	if err != nil {
+		return err
+	}
+	if err := urls.Override(input.githubServerURL, input.githubAPIURL, input.githubGraphQLURL); err != nil {
+		return err
+	}
+	instance := strings.TrimPrefix(strings.TrimPrefix(urls.ServerURL, "https://"), "http://")
+	repository, err := git.FindGithubRepo(ctx, input.Workdir(), instance, input.remoteName)
+	if err != nil {
+		return err
+	}
+	_, sha, err := git.FindGitRevision(ctx, input.Workdir())
+	if err != nil {
+		return err
+	}
+
+	for _, job := range jobs {
+		status := commitStatus(job)
+		if err := githubapi.CreateCommitStatus(ctx, urls.APIURL, token, repository, sha, status); err != nil {
+			return err
+		}
+		log.Infof("Reported '%s' as %s on %s@%s", status.Context, status.State, repository, sha)
+	}
+	return nil
+}
+
+// commitStatus returns the status reporting the result of the job, labeled as run locally
+func commitStatus(job *runner.JobResult) *githubapi.CommitStatus {
+	status := &githubapi.CommitStatus{
+		Context: fmt.Sprintf("%s / %s / %s", githubReportContext, filepath.Base(job.Workflow), job.Name),
+	}
+	switch job.Result {
+	case "success":
+		status.State = githubapi.StatusSuccess
+		status.Description = "Succeeded locally with act"
+		if job.Continued {
+			status.Description = "Failed locally with act, continued on error"
+		}
+	case "failure":
+		status.State = githubapi.StatusFailure
+		status.Description = "Failed locally with act"
+	default:
+		status.State = githubapi.StatusError
+		status.Description = "Did not complete locally with act"
+	}
+	return status
+}
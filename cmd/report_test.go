@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nektos/act/pkg/githubapi"
+	"github.com/nektos/act/pkg/runner"
+)
+
+func TestCommitStatus(t *testing.T) {
+	tables := []struct {
+		job      *runner.JobResult
+		expected *githubapi.CommitStatus
+	}{
+		{
+			&runner.JobResult{Workflow: ".github/workflows/ci.yml", Name: "test", Result: "success"},
+			&githubapi.CommitStatus{State: "success", Context: "act (local) / ci.yml / test", Description: "Succeeded locally with act"},
+		},
+		{
+			&runner.JobResult{Workflow: ".github/workflows/ci.yml", Name: "lint", Result: "success", Continued: true},
+			&githubapi.CommitStatus{State: "success", Context: "act (local) / ci.yml / lint", Description: "Failed locally with act, continued on error"},
+		},
+		{
+			&runner.JobResult{Workflow: ".github/workflows/ci.yml", Name: "build-1", Result: "failure"},
+			&githubapi.CommitStatus{State: "failure", Context: "act (local) / ci.yml / build-1", Description: "Failed locally with act"},
+		},
+		{
+			&runner.JobResult{Workflow: ".github/workflows/release.yml", Name: "deploy", Result: "cancelled"},
+			&githubapi.CommitStatus{State: "error", Context: "act (local) / release.yml / deploy", Description: "Did not complete locally with act"},
+		},
+	}
+	for _, table := range tables {
+		assert.Equal(t, table.expected, commitStatus(table.job), table.job.Name)
+	}
+}
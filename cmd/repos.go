@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/nektos/act/pkg/common"
+)
+
+type repoResult struct {
+	repo string
+	err  error
+}
+
+// runRepos plans and runs the workflows of every repository passed with --repo,
+// the artifact server and the action cache are shared between all of them
+func runRepos(ctx context.Context, cmd *cobra.Command, input *Input, args []string) error {
+	results := make([]*repoResult, len(input.repos))
+	executors := make([]common.Executor, 0, len(input.repos))
+	for i, repo := range input.repos {
+		repoInput := *input
+		repoInput.workdir = input.resolve(repo)
+		repoInput.repos = nil
+		// the logs of the repositories running in parallel are told apart by the prefix of their jobs
+		repoInput.repository = filepath.Base(repoInput.workdir)
+
+		log.Debugf("Planning repository %s", repoInput.workdir)
+		executor, err := newPlanExecutor(cmd, &repoInput, args)
+		if err != nil {
+			return fmt.Errorf("%s: %w", repo, err)
+		}
+		// the platforms picked in the default image survey are used for the remaining repositories
+		input.platforms = repoInput.platforms
+		if executor == nil {
+			continue
+		}
+
+		result := &repoResult{repo: repo}
+		results[i] = result
+		executors = append(executors, func(ctx context.Context) error {
+			result.err = executor(ctx)
+			return nil
+		})
+	}
+	if len(executors) == 0 {
+		return nil
+	}
+
+	parallel := 1
+	if input.parallelRepos {
+		parallel = len(executors)
+	}
+
+	cancel := startServers(ctx, input)
+
+	ctx = common.WithDryrun(ctx, input.dryrun)
+	executor := common.NewParallelExecutor(parallel, executors...).Then(func(ctx context.Context) error {
+		return printRepoResults(os.Stdout, results)
+	})
+	if watch, err := cmd.Flags().GetBool("watch"); err != nil {
+		return err
+	} else if watch {
+		return watchAndRun(ctx, executor)
+	}
+
+	executor = executor.Finally(func(ctx context.Context) error {
+		cancel()
+		return nil
+	})
+	return executor(ctx)
+}
+
+// printRepoResults prints a table of the results of the repositories, the error reports the failed ones
+func printRepoResults(out io.Writer, results []*repoResult) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Repository\tResult")
+
+	total, failed := 0, 0
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+		total++
+		if result.err != nil {
+			failed++
+			fmt.Fprintf(w, "%s\tfailure: %v\n", result.repo, result.err)
+		} else {
+			fmt.Fprintf(w, "%s\tsuccess\n", result.repo)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d repositories failed", failed, total)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestRunCommand returns a command with the flags of the root command read when planning a run
+func newTestRunCommand() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Flags().Bool("watch", false, "")
+	cmd.Flags().Bool("list", false, "")
+	cmd.Flags().Bool("list-json", false, "")
+	cmd.Flags().String("format", "table", "")
+	cmd.Flags().Bool("graph", false, "")
+	cmd.Flags().String("job", "", "")
+	cmd.Flags().String("defaultbranch", "", "")
+	cmd.Flags().String("eventpath", "", "")
+	cmd.Flags().Bool("verbose", false, "")
+	return cmd
+}
+
+func writeTestWorkflow(t *testing.T, dir string) {
+	t.Helper()
+	workflows := filepath.Join(dir, ".github", "workflows")
+	assert.NoError(t, os.MkdirAll(workflows, 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(workflows, "ci.yml"), []byte(`
+on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+    - run: echo
+`), 0o600))
+}
+
+func TestRunRepos(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	dir := t.TempDir()
+	writeTestWorkflow(t, filepath.Join(dir, "service-a"))
+	writeTestWorkflow(t, filepath.Join(dir, "service-b"))
+
+	cmd := newTestRunCommand()
+	assert.NoError(t, cmd.Flags().Set("list", "true"))
+	input := &Input{
+		workdir:       dir,
+		workflowsPath: "./.github/workflows/",
+		repos:         []string{"service-a", "service-b"},
+		platforms:     []string{"ubuntu-latest=node:16-buster-slim"},
+	}
+	assert.NoError(t, runRepos(context.Background(), cmd, input, nil))
+	assert.Equal(t, []string{"service-a", "service-b"}, input.repos, "the repositories don't change the shared input")
+	assert.Equal(t, "", input.repository)
+
+	input.repos = []string{"service-a", "missing"}
+	err := runRepos(context.Background(), cmd, input, nil)
+	assert.ErrorContains(t, err, "missing: ")
+}
+
+func TestPrintRepoResults(t *testing.T) {
+	out := &bytes.Buffer{}
+	err := printRepoResults(out, []*repoResult{
+		{repo: "service-a"},
+		nil,
+		{repo: "service-b", err: errors.New("Job 'test' failed")},
+	})
+	assert.EqualError(t, err, "1 of 2 repositories failed")
+	assert.Equal(t, "Repository  Result\nservice-a   success\nservice-b   failure: Job 'test' failed\n", out.String())
+
+	out.Reset()
+	assert.NoError(t, printRepoResults(out, []*repoResult{{repo: "service-a"}}))
+	assert.Equal(t, "Repository  Result\nservice-a   success\n", out.String())
+}
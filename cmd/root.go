@@ -3,6 +3,7 @@ package cmd
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -10,6 +11,7 @@ import (
 	"runtime"
 	"runtime/debug"
 	"strings"
+	"time"
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/andreaskoch/go-fswatch"
@@ -19,9 +21,12 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
+	"github.com/nektos/act/pkg/artifactcache"
 	"github.com/nektos/act/pkg/artifacts"
 	"github.com/nektos/act/pkg/common"
 	"github.com/nektos/act/pkg/container"
+	"github.com/nektos/act/pkg/fixtures"
+	"github.com/nektos/act/pkg/logsink"
 	"github.com/nektos/act/pkg/model"
 	"github.com/nektos/act/pkg/runner"
 )
@@ -39,38 +44,74 @@ func Execute(ctx context.Context, version string) {
 		Version:           version,
 		SilenceUsage:      true,
 	}
-	rootCmd.Flags().BoolP("watch", "w", false, "watch the contents of the local repo and run when files change")
+	rootCmd.Flags().BoolP("watch", "w", false, "watch the contents of the local repo and run when files change, the runs of a branch other than --defaultbranch keep their own containers of --reuse and caches of the cache server")
 	rootCmd.Flags().BoolP("list", "l", false, "list workflows")
+	rootCmd.Flags().Bool("list-json", false, "list workflows as JSON, including the run-name, env and permissions of the workflows")
+	rootCmd.Flags().String("format", "table", "format of --list: table, or json or yaml for a document listing the jobs with their stage, workflow file, needs, events and runs-on")
 	rootCmd.Flags().BoolP("graph", "g", false, "draw workflows")
 	rootCmd.Flags().StringP("job", "j", "", "run a specific job ID")
 	rootCmd.Flags().BoolP("bug-report", "", false, "Display system information for bug report")
+	rootCmd.ValidArgsFunction = completeEvents(input)
+	_ = rootCmd.RegisterFlagCompletionFunc("job", completeJobs(input))
 
 	rootCmd.Flags().StringVar(&input.remoteName, "remote-name", "origin", "git remote name that will be used to retrieve url of git repo")
 	rootCmd.Flags().StringArrayVarP(&input.secrets, "secret", "s", []string{}, "secret to make available to actions with optional value (e.g. -s mysecret=foo or -s mysecret)")
+	rootCmd.Flags().StringVar(&input.secretCacheMode, "secret-cache", secretCacheNone, "keep the values of the secrets entered at the prompt: none or session (encrypted in memory and reused by the runs of --watch and --repo)")
 	rootCmd.Flags().StringArrayVarP(&input.envs, "env", "", []string{}, "env to make available to actions with optional value (e.g. --env myenv=foo or --env myenv)")
-	rootCmd.Flags().StringArrayVarP(&input.inputs, "input", "", []string{}, "action input to make available to actions (e.g. --input myinput=foo)")
+	rootCmd.Flags().StringArrayVarP(&input.inputs, "input", "", []string{}, "action input to make available to actions, and input of the workflow_dispatch event checked against the inputs the workflows declare, the missing required inputs are asked for in a terminal (e.g. --input myinput=foo)")
 	rootCmd.Flags().StringArrayVarP(&input.platforms, "platform", "P", []string{}, "custom image to use per platform (e.g. -P ubuntu-18.04=nektos/act-environments-ubuntu:18.04)")
 	rootCmd.Flags().BoolVarP(&input.reuseContainers, "reuse", "r", false, "don't remove container(s) on successfully completed workflow(s) to maintain state between runs")
 	rootCmd.Flags().BoolVarP(&input.bindWorkdir, "bind", "b", false, "bind working directory to container, rather than copy")
+	rootCmd.Flags().StringArrayVar(&input.matrix, "matrix", []string{}, "run only the legs of the matrix jobs with the value of a matrix key, <key>:<value>, repeat it to select several keys or several values of a key (e.g. --matrix os:ubuntu-latest --matrix node:18)")
+	rootCmd.Flags().StringVar(&input.matrixWorkspace, "matrix-workspace", runner.MatrixWorkspaceIsolated, "workspace of the legs of a matrix job with --bind: isolated (every leg gets a copy of the working directory), base (every leg gets a copy made in its container from the working directory bound read-only, faster for large directories) or shared (the legs share the bound working directory)")
 	rootCmd.Flags().BoolVarP(&input.forcePull, "pull", "p", true, "pull docker image(s) even if already present")
 	rootCmd.Flags().BoolVarP(&input.forceRebuild, "rebuild", "", true, "rebuild local action docker image(s) even if already present")
 	rootCmd.Flags().BoolVarP(&input.autodetectEvent, "detect-event", "", false, "Use first event type from workflow as event that triggered the workflow")
 	rootCmd.Flags().StringVarP(&input.eventPath, "eventpath", "e", "", "path to event JSON file")
 	rootCmd.Flags().StringVar(&input.defaultBranch, "defaultbranch", "", "the name of the main branch")
+	rootCmd.Flags().StringArrayVarP(&input.protectedBranches, "protected-branches", "", []string{}, "protected branches replacing $protected-branches in the workflow templates of an organization (workflow-templates/), defaults to the main branch")
 	rootCmd.Flags().BoolVar(&input.privileged, "privileged", false, "use privileged mode")
 	rootCmd.Flags().StringVar(&input.usernsMode, "userns", "", "user namespace to use")
 	rootCmd.Flags().BoolVar(&input.useGitIgnore, "use-gitignore", true, "Controls whether paths specified in .gitignore should be copied into container")
 	rootCmd.Flags().StringArrayVarP(&input.containerCapAdd, "container-cap-add", "", []string{}, "kernel capabilities to add to the workflow containers (e.g. --container-cap-add SYS_PTRACE)")
+	rootCmd.Flags().StringArrayVar(&input.containerUsers, "container-user", []string{}, "user the job containers run as, [<platform>=]<user|uid>[:<group|gid>], can be repeated to override the user of a platform; the workspace is chowned to the user and HOME is set to a writable directory (e.g. --container-user 1001:1001 --container-user ubuntu-latest=root)")
 	rootCmd.Flags().StringArrayVarP(&input.containerCapDrop, "container-cap-drop", "", []string{}, "kernel capabilities to remove from the workflow containers (e.g. --container-cap-drop SYS_PTRACE)")
 	rootCmd.Flags().BoolVar(&input.autoRemove, "rm", false, "automatically remove container(s)/volume(s) after a workflow(s) failure")
 	rootCmd.Flags().StringArrayVarP(&input.replaceGheActionWithGithubCom, "replace-ghe-action-with-github-com", "", []string{}, "If you are using GitHub Enterprise Server and allow specified actions from GitHub (github.com), you can set actions on this. (e.g. --replace-ghe-action-with-github-com =github/super-linter)")
+	rootCmd.Flags().StringArrayVarP(&input.repos, "repo", "", []string{}, "path to a repository whose workflows are run, can be repeated to run several repositories in one invocation (e.g. --repo ./service-a --repo ./service-b)")
+	rootCmd.Flags().BoolVar(&input.parallelRepos, "parallel-repos", false, "run the repositories passed with --repo in parallel")
+	rootCmd.Flags().StringVar(&input.assertFile, "assert-file", "", "path to a YAML file with the expected job results, step outputs, exported env and artifact contents, the run fails if they don't match")
+	rootCmd.Flags().StringArrayVarP(&input.mockActions, "mock-action", "", []string{}, "replace an action with a local stub action, the ref may contain wildcards (e.g. --mock-action 'aws-actions/configure-aws-credentials@*=./mocks/aws-creds')")
+	rootCmd.Flags().StringVar(&input.mockActionsFile, "mock-actions-file", "", "YAML file mapping actions to the local stub actions replacing them")
+	rootCmd.Flags().StringArrayVarP(&input.injectSteps, "inject-step", "", []string{}, "splice a step into the jobs before or after the step with the given name or id, '*' matches every step (e.g. --inject-step 'before:Run tests:run=env | sort')")
+	rootCmd.Flags().BoolVar(&input.simulatePermissions, "simulate-permissions", false, "restrict the GITHUB_TOKEN of the jobs to their 'permissions:', GitHub API calls exceeding them are rejected and reported")
+	rootCmd.Flags().IntVar(&input.runAttempt, "run-attempt", 1, "attempt number of the run exposed as github.run_attempt, overrides GITHUB_RUN_ATTEMPT of --env")
+	rootCmd.Flags().BoolVar(&input.preflight, "preflight", false, "probe the images for the tools the jobs need (bash, git, tar, node) and warn about missing ones before running the jobs")
+	rootCmd.Flags().DurationVar(&input.progressInterval, "progress-interval", 0, "interval of the rendering of the states of the jobs (running, waiting for the scheduler or a concurrency group, blocked on needs, queued or done) while they run, defaults to 30s with --verbose, 0 disables it")
+	rootCmd.Flags().IntVar(&input.outputSizeLimit, "output-size-limit", runner.DefaultOutputSizeLimit, "size in bytes of an output of a step and of all outputs of a job above which act warns as GitHub rejects them, 0 disables the check")
+	rootCmd.Flags().IntVar(&input.envSizeLimit, "env-size-limit", runner.DefaultEnvSizeLimit, "size in bytes of a variable exported through GITHUB_ENV above which act warns as it breaks the processes on GitHub, 0 disables the check")
+	rootCmd.Flags().BoolVar(&input.propagateEnvToNeeds, "propagate-env-to-needs", false, "make the variables a job exports through GITHUB_ENV available to the jobs needing it, as the outputs ACT_ENV_<name> of the job and in their env (an act extension, GitHub doesn't share GITHUB_ENV between jobs)")
+	rootCmd.Flags().BoolVar(&input.strictLimits, "strict-limits", false, "fail the steps and jobs exceeding --output-size-limit or --env-size-limit instead of warning")
+	rootCmd.Flags().StringVar(&input.workflowPrefix, "workflow-prefix", runner.WorkflowPrefixName, "namespace the logs, results, artifacts and container names of the jobs by their workflow: name, file or auto (by the file when several workflow files are run)")
+	rootCmd.Flags().BoolVar(&input.experimentalGoActions, "experimental-go-actions", false, "EXPERIMENTAL: run the actions with 'runs.using: go', their 'main' package is built with the go toolchain of the job container and run with the inputs in the env")
+	rootCmd.Flags().StringVar(&input.runnerManifest, "runner-manifest", "", "JSON file of the tools preinstalled on the emulated runner (e.g. ubuntu-22.04.json), the tools missing in the environment of a job are installed into the tool cache before its steps run")
+	rootCmd.Flags().StringArrayVar(&input.installCA, "install-ca", []string{}, "PEM file of CA certificates to install into the trust stores of the job containers, NODE_EXTRA_CA_CERTS and GIT_SSL_CAINFO of the steps include them (e.g. --install-ca ./corp-root.pem)")
+	rootCmd.Flags().BoolVar(&input.sshAgent, "ssh-agent", false, "forward the SSH agent of SSH_AUTH_SOCK into the job containers, steps cloning over SSH use it through SSH_AUTH_SOCK")
+	rootCmd.Flags().StringArrayVar(&input.sshAgentKeys, "ssh-agent-key", []string{}, "private key served by a read-only SSH agent scoped to the run, which is forwarded instead of the agent of SSH_AUTH_SOCK, not supported on macOS (e.g. --ssh-agent-key ~/.ssh/deploy_key)")
+	rootCmd.Flags().StringVar(&input.gitUserName, "git-user-name", "", "user.name of the global git config of the job containers")
+	rootCmd.Flags().StringVar(&input.gitUserEmail, "git-user-email", "", "user.email of the global git config of the job containers")
+	rootCmd.Flags().StringVar(&input.gitSigningKey, "git-signing-key", "", "armored OpenPGP or OpenSSH private key without a passphrase, the commits and tags created in the job containers are signed with it")
 	rootCmd.Flags().StringVar(&input.replaceGheActionTokenWithGithubCom, "replace-ghe-action-token-with-github-com", "", "If you are using replace-ghe-action-with-github-com  and you want to use private actions on GitHub, you have to set personal access token")
 	rootCmd.PersistentFlags().StringVarP(&input.actor, "actor", "a", "nektos/act", "user that triggered the event")
 	rootCmd.PersistentFlags().StringVarP(&input.workflowsPath, "workflows", "W", "./.github/workflows/", "path to workflow file(s)")
+	rootCmd.Flags().BoolVar(&input.workflowStdin, "workflow-stdin", false, "read the workflow to run from stdin instead of '--workflows'/'-W' (e.g. act --workflow-stdin < ci.yml)")
 	rootCmd.PersistentFlags().BoolVarP(&input.noWorkflowRecurse, "no-recurse", "", false, "Flag to disable running workflows from subdirectories of specified path in '--workflows'/'-W' flag")
 	rootCmd.PersistentFlags().StringVarP(&input.workdir, "directory", "C", ".", "working directory")
 	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "verbose output")
 	rootCmd.PersistentFlags().BoolVar(&input.jsonLogger, "json", false, "Output logs in json format")
+	rootCmd.PersistentFlags().StringVar(&input.logPrefix, "log-prefix", "", "template of the prefix of the log lines of a job with the placeholders {workflow}, {file}, {job}, {jobID}, {matrix} and {index} (e.g. '{workflow}/{job}/{matrix}'), the JSON logs and the results of the jobs contain the prefix as jobName")
+	rootCmd.PersistentFlags().StringArrayVarP(&input.logSinks, "log-sink", "", []string{}, "additional destination of the logs, can be repeated: file:<path>, json:<path> (json:- for stdout), syslog[:<network>://<address>] or loki:<url> (e.g. --log-sink json:act.log --log-sink loki:http://localhost:3100)")
+	rootCmd.PersistentFlags().StringVarP(&input.configFile, "config-file", "", "act.yaml", "config file with overrides per workflow file (eventpath, platforms, env-files, skip-jobs), e.g. 'workflows: { release.yml: { eventpath: fixtures/tag.json } }'")
 	rootCmd.PersistentFlags().BoolVarP(&input.noOutput, "quiet", "q", false, "disable logging of output from steps")
 	rootCmd.PersistentFlags().BoolVarP(&input.dryrun, "dryrun", "n", false, "dryrun mode")
 	rootCmd.PersistentFlags().StringVarP(&input.secretfile, "secret-file", "", ".secrets", "file with list of secrets to read from (e.g. --secret-file .secrets)")
@@ -79,12 +120,61 @@ func Execute(ctx context.Context, version string) {
 	rootCmd.PersistentFlags().StringVarP(&input.inputfile, "input-file", "", ".input", "input file to read and use as action input")
 	rootCmd.PersistentFlags().StringVarP(&input.containerArchitecture, "container-architecture", "", "", "Architecture which should be used to run containers, e.g.: linux/amd64. If not specified, will use host default architecture. Requires Docker server API Version 1.41+. Ignored on earlier Docker server platforms.")
 	rootCmd.PersistentFlags().StringVarP(&input.containerDaemonSocket, "container-daemon-socket", "", "/var/run/docker.sock", "Path to Docker daemon socket which will be mounted to containers")
+	rootCmd.PersistentFlags().StringVar(&input.containerBackend, "container-backend", string(container.BackendDocker), "container engine running the containers: docker, the daemon of DOCKER_HOST, or podman, the API service of podman at CONTAINER_HOST or the socket of the rootless service of the user, without emulating the docker socket")
 	rootCmd.PersistentFlags().StringVarP(&input.containerOptions, "container-options", "", "", "Custom docker container options for the job container without an options property in the job definition")
-	rootCmd.PersistentFlags().StringVarP(&input.githubInstance, "github-instance", "", "github.com", "GitHub instance to use. Don't use this if you are not using GitHub Enterprise Server.")
+	rootCmd.Flags().StringVar(&input.containerRuntime, "container-runtime", "", "OCI runtime of the job, service and step containers registered in the container engine, like runsc (gVisor) or kata-runtime (Kata Containers), to run untrusted actions in a sandbox. The default runtime of the engine if empty")
+	rootCmd.Flags().StringVar(&input.containerCreatePatch, "container-create-patch", "", "JSON file with a merge patch (RFC 7396) applied to the requests creating the job, service and step containers, an object with the Config, HostConfig and NetworkingConfig of the Docker engine API, for what a runtime needs beyond --container-runtime (e.g. {\"HostConfig\": {\"Runtime\": \"runsc\", \"Devices\": []}})")
+	rootCmd.PersistentFlags().StringVarP(&input.contextFile, "context-file", "", "", "JSON file with values merged into the github, runner and vars contexts, e.g. '{\"github\": {\"ref\": \"refs/tags/v1.0.0\"}, \"vars\": {\"STAGE\": \"prod\"}}'")
+	rootCmd.PersistentFlags().StringVarP(&input.githubInstance, "github-instance", "", "github.com", "GitHub instance to use, with an optional port and path prefix (e.g. ghe.example.com:8443/github). Don't use this if you are not using GitHub Enterprise Server.")
+	rootCmd.PersistentFlags().StringVarP(&input.githubServerURL, "github-server-url", "", "", "GITHUB_SERVER_URL of the jobs, defaults to the URL of --github-instance")
+	rootCmd.PersistentFlags().StringVarP(&input.githubAPIURL, "github-api-url", "", "", "GITHUB_API_URL of the jobs, defaults to the API of --github-instance (e.g. https://api.github.example.com for a split deployment)")
+	rootCmd.PersistentFlags().StringVarP(&input.githubGraphQLURL, "github-graphql-url", "", "", "GITHUB_GRAPHQL_URL of the jobs, defaults to the GraphQL API of --github-instance")
 	rootCmd.PersistentFlags().StringVarP(&input.artifactServerPath, "artifact-server-path", "", "", "Defines the path where the artifact server stores uploads and retrieves downloads from. If not specified the artifact server will not start.")
-	rootCmd.PersistentFlags().StringVarP(&input.artifactServerAddr, "artifact-server-addr", "", common.GetOutboundIP().String(), "Defines the address to which the artifact server binds.")
+	rootCmd.PersistentFlags().StringVarP(&input.artifactServerAddr, "artifact-server-addr", "", "", "Defines the address to which the artifact server binds, a hostname, an IPv4 or an IPv6 address. If not specified, it binds to all interfaces and the containers reach it through the host name of the container engine (host.docker.internal or host.containers.internal).")
 	rootCmd.PersistentFlags().StringVarP(&input.artifactServerPort, "artifact-server-port", "", "34567", "Defines the port where the artifact server listens.")
+	rootCmd.PersistentFlags().StringVarP(&input.cacheServerPath, "cache-server-path", "", "", "path where the cache server of actions/cache stores the cache entries, which are restored by the following runs. If not specified the cache server will not start.")
+	rootCmd.PersistentFlags().StringVarP(&input.cacheServerPort, "cache-server-port", "", "34568", "port where the cache server listens, on the address of --artifact-server-addr")
+	rootCmd.PersistentFlags().StringVarP(&input.fixturesServerPath, "fixtures-server", "", "", "directory served read-only over HTTP to the jobs, which find it at $ACT_FIXTURES_URL (e.g. --fixtures-server ./testdata). If not specified the fixtures server will not start.")
+	rootCmd.PersistentFlags().StringVarP(&input.fixturesServerPort, "fixtures-server-port", "", "34569", "port where the fixtures server listens, on the address of --artifact-server-addr")
+	rootCmd.PersistentFlags().StringVarP(&input.concurrencyDir, "concurrency-dir", "", "", "directory shared by the invocations of act queueing the jobs and workflows of their concurrency groups, a newer run cancels the pending ones and with cancel-in-progress the ones in progress. If not specified only the runs of one invocation, e.g. with --watch, share the concurrency groups.")
+	rootCmd.Flags().BoolVar(&input.reportToGithub, "report-to-github", false, "post the results of the jobs as statuses of the commit checked out in the working directory, labeled 'act (local)', with the token of -s GITHUB_TOKEN")
+	rootCmd.Flags().StringVar(&input.summaryPath, "summary-path", "", "directory the markdown the steps write to $GITHUB_STEP_SUMMARY is written to after the run, one file per job, the summaries are printed to the terminal without it")
+	rootCmd.Flags().StringVar(&input.downloadArtifacts, "download-artifacts", "", "directory the artifacts uploaded by the run are extracted to after the run, one directory per artifact (e.g. --download-artifacts ./out), requires --artifact-server-path")
+	rootCmd.Flags().StringArrayVar(&input.downloadArtifactNames, "download-artifact", []string{}, "name of an artifact extracted by --download-artifacts, all artifacts are extracted without it")
 	rootCmd.PersistentFlags().BoolVarP(&input.noSkipCheckout, "no-skip-checkout", "", false, "Do not skip actions/checkout")
+	rootCmd.PersistentFlags().BoolVarP(&input.simulateCI, "simulate-ci", "", true, "set CI detection environment variables (CI, GITHUB_ACTIONS) inside the containers, use --simulate-ci=false to remove them and the other CI markers (CONTINUOUS_INTEGRATION, BUILD_ID, BUILD_NUMBER, CI_NAME, RUN_ID) to run workflows as outside of CI")
+	rootCmd.PersistentFlags().StringVarP(&input.autoStartVM, "auto-start-vm", "", "", "start the VM providing the docker daemon if it is stopped, colima[:<profile>] or lima[:<instance>] (e.g. --auto-start-vm colima)")
+	rootCmd.PersistentFlags().BoolVarP(&input.stopVM, "stop-vm", "", false, "stop the VM started by --auto-start-vm after the run")
+	rootCmd.PersistentFlags().StringVarP(&input.cacheMaxSize, "cache-max-size", "", "", "disk budget of the cache of act (actions, reusable workflows and the tool cache of -P <platform>=-self-hosted), the entries used least recently are evicted after a run (e.g. --cache-max-size 20GB)")
+	rootCmd.Flags().StringArrayVar(&input.cacheVolumes, "cache-volume", []string{}, "name:path of a volume managed by act keeping a language cache across the job containers, declare it in .actrc to share it between the runs and list or prune it with 'act cache' (e.g. --cache-volume go-build:/root/.cache/go-build)")
+	rootCmd.Flags().StringVar(&input.graphqlFixtures, "graphql-fixtures", "", "JSON file or directory of JSON files with responses of the GraphQL API, served to the jobs for the requests matching their operationName and variables instead of calling GitHub, requests matching no fixture fail without a GITHUB_TOKEN secret")
+	rootCmd.Flags().BoolVar(&input.frozenImageLock, "frozen", false, "fail the jobs running on an image or using a docker:// image which isn't pinned in act.lock, run 'act lock' to pin the images")
+	rootCmd.Flags().StringVar(&input.localRegistry, "local-registry", "", "host:port of a throwaway registry started for the run, the images of the docker actions built by act are pushed to it and their containers are created from it, so remote docker hosts can pull them; the host must be reachable by the container engine, which requires registries other than localhost to be listed in its insecure-registries (e.g. --local-registry localhost:5000)")
+	rootCmd.Flags().BoolVar(&input.useHost, "use-host", false, "run the steps of all the jobs directly on the host like -P <platform>=-self-hosted, for machines without a container engine, the jobs are not isolated from the host and their containers and services are not started")
+	rootCmd.Flags().StringArrayVar(&input.engines, "engine", []string{}, "<label>[,<label>...]=<engine> running the jobs whose runs-on matches all the labels, which may be globs, on another engine than the one of the run: docker, podman, host, a docker host like ssh://user@host or tcp://host:2376, or a VM colima[:<profile>] or lima[:<instance>] which is started if it is stopped. The first matching --engine wins and the logs of all the jobs are written by act with the engine as a field (e.g. --engine 'ubuntu-*=docker' --engine self-hosted,gpu=ssh://gpu-host --engine 'macos-*=colima:macos')")
+	rootCmd.Flags().StringVar(&input.githubScript, "github-script", runner.GithubScriptAuto, "how the steps of actions/github-script run: action (run the action), api (run the script without cloning the action, with a shim of octokit sending its calls to the GitHub API, through the API proxy of act if it runs for --simulate-permissions or --graphql-fixtures), noop (like api, but the calls are only logged and resolve with empty data, for offline runs) or auto (api if the API proxy runs, action otherwise). The return value of the script is the output result of the step like with the action.")
+	rootCmd.Flags().DurationVar(&input.hangTimeout, "hang-timeout", 0, "warn that a step possibly hangs when it has written no output and its job container has used no CPU for this long, with a listing of the processes of the container (ps, their state, wait channel and kernel stack from /proc), only the output is watched on the host. 0 disables the detection (e.g. --hang-timeout 10m)")
+	rootCmd.Flags().BoolVar(&input.skipUnsupported, "skip-unsupported", false, "skip the steps using a feature act can't emulate instead of failing them, like an expression with an unsupported context or function or a command which isn't installed in the image (exit code 127), the steps are reported with the reason at the end of their job")
+	rootCmd.Flags().BoolVar(&input.shellOnError, "shell-on-error", false, "when a step fails, open an interactive shell (bash, or sh if the image has no bash) with the env of the step in the job container, which is kept running until the shell exits, then the job goes on with its post steps")
+	rootCmd.Flags().IntVar(&input.concurrentJobs, "concurrent-jobs", 0, "number of jobs running at once, the legs of the matrixes and the jobs of the reusable workflows included, within the max-parallel of the matrixes. 0 runs as many jobs at once as the container engine has CPUs")
+	rootCmd.Flags().StringVar(&input.exportWorkspace, "export-workspace", "", "directory the workspace of every job is copied into from its container once its steps are done, in a directory per job and matrix leg (e.g. CI-build-1) replacing the one of a previous run, to inspect the generated files without keeping the containers (e.g. --export-workspace ./snapshot)")
+	rootCmd.Flags().BoolVar(&input.stepDebug, "step-debug", false, "pause before and after each step with an interactive prompt to continue, skip the step, open a shell in the job container, print the env and contexts of the step or abort the job, the prompts of the jobs running in parallel are asked one after the other")
+	rootCmd.Flags().BoolVar(&input.killHungSteps, "kill-hung-steps", false, "kill the steps which possibly hang after --hang-timeout, they fail like steps exceeding their timeout-minutes")
+	rootCmd.Flags().BoolVar(&input.traceExpressions, "trace-expressions", false, "log every evaluated expression of the jobs and steps (if:, with:, env:, ...) with its result and the values of its context references and function calls, the secrets are masked")
+	rootCmd.PersistentFlags().StringVarP(&input.composeServices, "compose-services", "", "", "path to a docker-compose file whose services are started for the run, job containers join its network and can reach the services by name")
+	rootCmd.AddCommand(newUpgradeCommand(ctx, input))
+	rootCmd.AddCommand(newExprCommand(ctx, input))
+	rootCmd.AddCommand(newRunActionCommand(ctx, input))
+	rootCmd.AddCommand(newPsCommand(ctx, input))
+	rootCmd.AddCommand(newCompatCommand(ctx, input))
+	rootCmd.AddCommand(newValidateCommand(ctx, input))
+	rootCmd.AddCommand(newEventCommand(ctx, input))
+	rootCmd.AddCommand(newInitCommand(ctx, input))
+	rootCmd.AddCommand(newCacheCommand(ctx, input))
+	rootCmd.AddCommand(newLockCommand(ctx, input))
+	rootCmd.AddCommand(newHistoryCommand(ctx, input))
+	rootCmd.AddCommand(newWhatifCommand(ctx, input))
+	rootCmd.AddCommand(newVendorCommand(ctx, input))
 	rootCmd.SetArgs(args())
 
 	if err := rootCmd.Execute(); err != nil {
@@ -113,6 +203,37 @@ func configLocations() []string {
 	}
 }
 
+func cacheLocation() string {
+	if xdg, ok := os.LookupEnv("XDG_CACHE_HOME"); ok && xdg != "" {
+		return filepath.Join(xdg, "act")
+	}
+	home, err := homedir.Dir()
+	if err != nil {
+		log.Fatal(err)
+	}
+	return filepath.Join(home, ".cache", "act")
+}
+
+const (
+	// workflowCacheMaxSize bounds the size of the cache of parsed workflows and plans
+	workflowCacheMaxSize = 64 << 20
+	// workflowCacheMaxAge is the time after which unused entries of the workflow cache are removed
+	workflowCacheMaxAge = 30 * 24 * time.Hour
+)
+
+// newWorkflowPlanner loads the workflows of the input, parsed workflows and plans are cached by their content
+func newWorkflowPlanner(input *Input) (model.WorkflowPlanner, error) {
+	cache := model.NewWorkflowCache(filepath.Join(cacheLocation(), "workflows"))
+	planner, err := model.NewCachedWorkflowPlanner(input.WorkflowsPath(), input.noWorkflowRecurse, cache, input.workflowTemplateValues())
+	if err != nil {
+		return nil, err
+	}
+	if err := cache.Prune(workflowCacheMaxSize, workflowCacheMaxAge); err != nil {
+		log.Debugf("Unable to prune the workflow cache: %v", err)
+	}
+	return planner, nil
+}
+
 func args() []string {
 	actrc := configLocations()
 
@@ -129,6 +250,7 @@ func bugReport(ctx context.Context, version string) error {
 	var commonSocketPaths = []string{
 		"/var/run/docker.sock",
 		"/var/run/podman/podman.sock",
+		"$XDG_RUNTIME_DIR/podman/podman.sock",
 		"$HOME/.colima/docker.sock",
 		"$XDG_RUNTIME_DIR/docker.sock",
 		`\\.\pipe\docker_engine`,
@@ -302,6 +424,27 @@ func newRunCommand(ctx context.Context, input *Input) func(*cobra.Command, []str
 			return bugReport(ctx, cmd.Version)
 		}
 
+		finishRun, err := prepareRun(input)
+		if err != nil {
+			return err
+		}
+		// flushes the buffered entries of the sinks when act is done
+		defer finishRun()
+
+		if input.workflowStdin {
+			if cmd.Flags().Changed("workflows") {
+				return fmt.Errorf("--workflow-stdin and --workflows are mutually exclusive")
+			}
+			if input.stepDebug || input.shellOnError {
+				return fmt.Errorf("--step-debug and --shell-on-error read from stdin, they can't be used with --workflow-stdin")
+			}
+			removeWorkflow, err := readWorkflowStdin(os.Stdin, input)
+			if err != nil {
+				return err
+			}
+			defer removeWorkflow()
+		}
+
 		if runtime.GOOS == "darwin" && runtime.GOARCH == "arm64" && input.containerArchitecture == "" {
 			l := log.New()
 			l.SetFormatter(&log.TextFormatter{
@@ -311,208 +454,679 @@ func newRunCommand(ctx context.Context, input *Input) func(*cobra.Command, []str
 			l.Warnf(" \U000026A0 You are using Apple M1 chip and you have not specified container architecture, you might encounter issues while running act. If so, try running it with '--container-architecture linux/amd64'. \U000026A0 \n")
 		}
 
-		log.Debugf("Loading environment from %s", input.Envfile())
-		envs := make(map[string]string)
-		_ = parseEnvs(input.envs, envs)
-		_ = readEnvs(input.Envfile(), envs)
+		// the VM runs the docker daemon, which is needed to plan the run and resolve the images
+		if input.autoStartVM != "" && !input.dryrun {
+			vm, err := parseDockerVM(input.autoStartVM)
+			if err != nil {
+				return err
+			}
+			stopVM, err := startDockerVM(ctx, vm)
+			if err != nil {
+				return err
+			}
+			if input.stopVM {
+				// a VM which was already running is never stopped
+				defer stopVM()
+			}
+			if host := vm.dockerHost(); host != "" && os.Getenv("DOCKER_HOST") == "" && !cmd.Flags().Changed("container-daemon-socket") {
+				log.Debugf("Using the docker host %s of the %s", host, vm)
+				input.containerDaemonSocket = host
+			}
+		}
+		if !input.dryrun {
+			stopVMs, err := startEngineVMs(ctx, input)
+			if err != nil {
+				return err
+			}
+			if input.stopVM {
+				defer stopVMs()
+			}
+		}
 
-		log.Debugf("Loading action inputs from %s", input.Inputfile())
-		inputs := make(map[string]string)
-		_ = parseEnvs(input.inputs, inputs)
-		_ = readEnvs(input.Inputfile(), inputs)
+		if len(input.repos) > 0 {
+			return runRepos(ctx, cmd, input, args)
+		}
 
-		log.Debugf("Loading secrets from %s", input.Secretfile())
-		secrets := newSecrets(input.secrets)
-		_ = readEnvs(input.Secretfile(), secrets)
+		plan := func() (common.Executor, error) {
+			executor, err := newPlanExecutor(cmd, input, args)
+			if err != nil || executor == nil {
+				return nil, err
+			}
+			executor = newSummaryExecutor(input, executor)
 
-		planner, err := model.NewWorkflowPlanner(input.WorkflowsPath(), input.noWorkflowRecurse)
-		if err != nil {
-			return err
+			if input.downloadArtifacts != "" {
+				if input.artifactServerPath == "" {
+					return nil, fmt.Errorf("--download-artifacts requires the artifact server, use --artifact-server-path")
+				}
+				executor = newArtifactDownloadExecutor(input, executor)
+			}
+			if input.assertFile != "" {
+				assertions, err := runner.ReadAssertions(input.AssertFile())
+				if err != nil {
+					return nil, err
+				}
+				executor = newAssertionExecutor(input, assertions, executor)
+			}
+			return newHistoryExecutor(input, executor), nil
 		}
 
-		jobID, err := cmd.Flags().GetString("job")
-		if err != nil {
+		executor, err := plan()
+		if err != nil || executor == nil {
 			return err
 		}
 
-		// check if we should just list the workflows
-		list, err := cmd.Flags().GetBool("list")
-		if err != nil {
-			return err
-		}
+		cancel := startServers(ctx, input)
 
-		// check if we should just draw the graph
-		graph, err := cmd.Flags().GetBool("graph")
-		if err != nil {
+		ctx = common.WithDryrun(ctx, input.dryrun)
+		if watch, err := cmd.Flags().GetBool("watch"); err != nil {
 			return err
+		} else if watch {
+			return watchAndRun(ctx, func(ctx context.Context) error {
+				// plan again, so changes of the workflows are picked up
+				if executor == nil {
+					if executor, err = plan(); err != nil || executor == nil {
+						return err
+					}
+				}
+				defer func() { executor = nil }()
+				return executor(ctx)
+			})
 		}
 
-		// collect all events from loaded workflows
-		events := planner.GetEvents()
+		executor = executor.Finally(func(ctx context.Context) error {
+			cancel()
+			return nil
+		})
+		return executor(ctx)
+	}
+}
 
-		// plan with filtered jobs - to be used for filtering only
-		var filterPlan *model.Plan
+// startServers starts the artifact server, the cache server and the fixtures server enabled by the flags, the returned
+// function stops them
+func startServers(ctx context.Context, input *Input) context.CancelFunc {
+	cancelArtifacts := artifacts.Serve(ctx, input.artifactServerPath, input.ArtifactServerAddr(), input.artifactServerPort, input.runtimeTokens)
+	cancelCache := artifactcache.Serve(ctx, input.cacheServerPath, input.ArtifactServerAddr(), input.cacheServerPort, input.runtimeTokens)
+	cancelFixtures := fixtures.Serve(ctx, input.FixturesServerPath(), input.ArtifactServerAddr(), input.fixturesServerPort)
+	return func() {
+		cancelArtifacts()
+		cancelCache()
+		cancelFixtures()
+	}
+}
 
-		// Determine the event name to be filtered
-		var filterEventName string = ""
+// cacheEvictionMinAge is the time the cache entries are kept after their last use, as another run may be using them
+const cacheEvictionMinAge = time.Hour
 
-		if len(args) > 0 {
-			log.Debugf("Using first passed in arguments event for filtering: %s", args[0])
-			filterEventName = args[0]
-		} else if input.autodetectEvent && len(events) > 0 && len(events[0]) > 0 {
-			// set default event type to first event from many available
-			// this way user dont have to specify the event.
-			log.Debugf("Using first detected workflow event for filtering: %s", events[0])
-			filterEventName = events[0]
-		}
-
-		if jobID != "" {
-			log.Debugf("Preparing plan with a job: %s", jobID)
-			filterPlan = planner.PlanJob(jobID)
-		} else if filterEventName != "" {
-			log.Debugf("Preparing plan for a event: %s", filterEventName)
-			filterPlan = planner.PlanEvent(filterEventName)
-		} else {
-			log.Debugf("Preparing plan with all jobs")
-			filterPlan = planner.PlanAll()
+// prepareRun opens the log sinks and creates the issuer of the runtime tokens and the cache of the secrets for a run,
+// the returned function evicts the cache entries exceeding --cache-max-size, drops the cached secrets and closes
+// the log sinks
+func prepareRun(input *Input) (func(), error) {
+	var cacheMaxSize int64 = -1
+	if input.cacheMaxSize != "" {
+		size, err := common.ParseSize(input.cacheMaxSize)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --cache-max-size: %w", err)
 		}
-
-		if list {
-			return printList(filterPlan)
+		cacheMaxSize = size
+	}
+	switch input.secretCacheMode {
+	case "", secretCacheNone:
+	case secretCacheSession:
+		if input.secretCache == nil {
+			cache, err := newSecretCache()
+			if err != nil {
+				return nil, err
+			}
+			input.secretCache = cache
 		}
+	default:
+		return nil, fmt.Errorf("invalid --secret-cache '%s', expected %s or %s", input.secretCacheMode, secretCacheNone, secretCacheSession)
+	}
 
-		if graph {
-			return drawGraph(filterPlan)
+	closeLogSinks := func() {
+		for _, sink := range input.sinks {
+			_ = sink.Close()
+		}
+	}
+	for _, spec := range input.logSinks {
+		sink, err := logsink.New(spec)
+		if err != nil {
+			closeLogSinks()
+			return nil, err
 		}
+		// the sinks are attached to the job loggers, which mask the secrets
+		input.sinks = append(input.sinks, sink)
+	}
 
-		// plan with triggered jobs
-		var plan *model.Plan
+	if input.artifactServerPath != "" {
+		// the jobs authenticate to the artifact server with their own ACTIONS_RUNTIME_TOKEN
+		tokens, err := common.NewRuntimeTokens()
+		if err != nil {
+			closeLogSinks()
+			return nil, err
+		}
+		input.runtimeTokens = tokens
+	}
+	return func() {
+		if cacheMaxSize >= 0 {
+			evictCache(cacheMaxSize)
+		}
+		input.secretCache.clear()
+		closeLogSinks()
+	}, nil
+}
 
-		// Determine the event name to be triggered
-		var eventName string
+// evictCache removes the cached actions and tools used least recently until they fit into maxSize, the other
+// directories of the cache (e.g. the workdirs of the host executor) are never evicted
+func evictCache(maxSize int64) {
+	dir := cacheLocation()
+	migrateActionCache(dir)
+	evicted, err := common.EvictCache([]string{filepath.Join(dir, "actions"), filepath.Join(dir, "tool_cache")}, maxSize, cacheEvictionMinAge)
+	var total int64
+	for _, entry := range evicted {
+		log.Infof("Evicted %s (%s) from the cache, last used %s", entry.Path, common.FormatSize(entry.Size), entry.LastUsed.Format(time.RFC3339))
+		total += entry.Size
+	}
+	if len(evicted) > 0 {
+		log.Infof("Evicted %d entries (%s) from the cache to fit into %s", len(evicted), common.FormatSize(total), common.FormatSize(maxSize))
+	}
+	if err != nil {
+		log.Warnf("Unable to evict the cache: %v", err)
+	}
+}
 
-		if len(args) > 0 {
-			log.Debugf("Using first passed in arguments event: %s", args[0])
-			eventName = args[0]
-		} else if len(events) == 1 && len(events[0]) > 0 {
-			log.Debugf("Using the only detected workflow event: %s", events[0])
-			eventName = events[0]
-		} else if input.autodetectEvent && len(events) > 0 && len(events[0]) > 0 {
-			// set default event type to first event from many available
-			// this way user dont have to specify the event.
-			log.Debugf("Using first detected workflow event: %s", events[0])
-			eventName = events[0]
-		} else {
-			log.Debugf("Using default workflow event: push")
-			eventName = "push"
+// migrateActionCache moves the actions and reusable workflows cloned into the cache directory itself by the earlier
+// versions of act, named <owner>-<repo>@<ref>, into its actions subdirectory, where they are evicted. A clone which
+// is already in actions is removed.
+func migrateActionCache(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() || !strings.Contains(entry.Name(), "@") {
+			continue
+		}
+		legacy := filepath.Join(dir, entry.Name())
+		target := filepath.Join(dir, "actions", entry.Name())
+		if _, err := os.Stat(target); os.IsNotExist(err) {
+			if err = os.MkdirAll(filepath.Dir(target), 0o755); err == nil {
+				err = os.Rename(legacy, target)
+			}
+			if err == nil {
+				log.Debugf("Moved the cached action %s to %s", legacy, target)
+				continue
+			}
 		}
+		if err := os.RemoveAll(legacy); err != nil {
+			log.Warnf("Unable to remove the cached action %s: %v", legacy, err)
+		}
+	}
+}
 
-		// build the plan for this run
-		if jobID != "" {
-			log.Debugf("Planning job: %s", jobID)
-			plan = planner.PlanJob(jobID)
-		} else {
-			log.Debugf("Planning jobs for event: %s", eventName)
-			plan = planner.PlanEvent(eventName)
+// newAssertionExecutor verifies the assertions after the run, the assertions decide whether the run failed
+func newAssertionExecutor(input *Input, assertions *runner.Assertions, executor common.Executor) common.Executor {
+	return func(ctx context.Context) error {
+		results := &runner.Results{}
+		if err := executor(runner.WithResults(ctx, results)); err != nil {
+			log.Infof("Run failed, verifying assertions: %v", err)
 		}
 
-		// check to see if the main branch was defined
-		defaultbranch, err := cmd.Flags().GetString("defaultbranch")
-		if err != nil {
+		return assertions.Verify(results, func(runID string, name string, file string) ([]byte, error) {
+			if input.artifactServerPath == "" {
+				return nil, fmt.Errorf("the artifact server is not enabled, use --artifact-server-path")
+			}
+			return artifacts.ReadFile(input.artifactServerPath, runID, name, file)
+		})
+	}
+}
+
+// newArtifactDownloadExecutor extracts the artifacts of the run into the directory of --download-artifacts after the
+// run, also if it failed
+func newArtifactDownloadExecutor(input *Input, executor common.Executor) common.Executor {
+	return func(ctx context.Context) error {
+		results := runner.ResultsFrom(ctx)
+		if results == nil {
+			results = &runner.Results{}
+			ctx = runner.WithResults(ctx, results)
+		}
+		err := executor(ctx)
+		if common.Dryrun(ctx) {
 			return err
 		}
 
-		// Check if platforms flag is set, if not, run default image survey
-		if len(input.platforms) == 0 {
-			cfgFound := false
-			cfgLocations := configLocations()
-			for _, v := range cfgLocations {
-				_, err := os.Stat(v)
-				if os.IsExist(err) {
-					cfgFound = true
-				}
+		// the jobs of the workflows namespaced by --workflow-prefix upload into runs of their own
+		runIDs := make([]string, 0)
+		for _, job := range results.Jobs {
+			if !common.ContainsString(runIDs, job.RunID) {
+				runIDs = append(runIDs, job.RunID)
 			}
-			if !cfgFound && len(cfgLocations) > 0 {
-				if err := defaultImageSurvey(cfgLocations[0]); err != nil {
-					log.Fatal(err)
+		}
+
+		extracted := make([]string, 0)
+		for _, runID := range runIDs {
+			names, extractErr := artifacts.Extract(input.artifactServerPath, runID, input.downloadArtifactNames, input.DownloadArtifacts())
+			extracted = append(extracted, names...)
+			if extractErr != nil {
+				log.Errorf("Unable to download the artifacts of run %s: %v", runID, extractErr)
+				if err == nil {
+					err = extractErr
 				}
-				input.platforms = readArgsFile(cfgLocations[0], true)
 			}
 		}
-		deprecationWarning := "--%s is deprecated and will be removed soon, please switch to cli: `--container-options \"%[2]s\"` or `.actrc`: `--container-options %[2]s`."
-		if input.privileged {
-			log.Warnf(deprecationWarning, "privileged", "--privileged")
-		}
-		if len(input.usernsMode) > 0 {
-			log.Warnf(deprecationWarning, "userns", fmt.Sprintf("--userns=%s", input.usernsMode))
-		}
-		if len(input.containerCapAdd) > 0 {
-			log.Warnf(deprecationWarning, "container-cap-add", fmt.Sprintf("--cap-add=%s", input.containerCapAdd))
-		}
-		if len(input.containerCapDrop) > 0 {
-			log.Warnf(deprecationWarning, "container-cap-drop", fmt.Sprintf("--cap-drop=%s", input.containerCapDrop))
-		}
-
-		// run the plan
-		config := &runner.Config{
-			Actor:                              input.actor,
-			EventName:                          eventName,
-			EventPath:                          input.EventPath(),
-			DefaultBranch:                      defaultbranch,
-			ForcePull:                          input.forcePull,
-			ForceRebuild:                       input.forceRebuild,
-			ReuseContainers:                    input.reuseContainers,
-			Workdir:                            input.Workdir(),
-			BindWorkdir:                        input.bindWorkdir,
-			LogOutput:                          !input.noOutput,
-			JSONLogger:                         input.jsonLogger,
-			Env:                                envs,
-			Secrets:                            secrets,
-			Inputs:                             inputs,
-			Token:                              secrets["GITHUB_TOKEN"],
-			InsecureSecrets:                    input.insecureSecrets,
-			Platforms:                          input.newPlatforms(),
-			Privileged:                         input.privileged,
-			UsernsMode:                         input.usernsMode,
-			ContainerArchitecture:              input.containerArchitecture,
-			ContainerDaemonSocket:              input.containerDaemonSocket,
-			ContainerOptions:                   input.containerOptions,
-			UseGitIgnore:                       input.useGitIgnore,
-			GitHubInstance:                     input.githubInstance,
-			ContainerCapAdd:                    input.containerCapAdd,
-			ContainerCapDrop:                   input.containerCapDrop,
-			AutoRemove:                         input.autoRemove,
-			ArtifactServerPath:                 input.artifactServerPath,
-			ArtifactServerAddr:                 input.artifactServerAddr,
-			ArtifactServerPort:                 input.artifactServerPort,
-			NoSkipCheckout:                     input.noSkipCheckout,
-			RemoteName:                         input.remoteName,
-			ReplaceGheActionWithGithubCom:      input.replaceGheActionWithGithubCom,
-			ReplaceGheActionTokenWithGithubCom: input.replaceGheActionTokenWithGithubCom,
-		}
-		r, err := runner.New(config)
+		for _, name := range input.downloadArtifactNames {
+			if !common.ContainsString(extracted, name) {
+				log.Warnf("The run uploaded no artifact '%s'", name)
+			}
+		}
+		if len(extracted) > 0 {
+			log.Infof("Downloaded the artifacts %s to %s", strings.Join(extracted, ", "), input.DownloadArtifacts())
+		}
+		return err
+	}
+}
+
+// newPlanExecutor plans the run for the workflows of the input, the returned executor is nil if only a list or graph was requested
+//
+//nolint:gocyclo
+func newPlanExecutor(cmd *cobra.Command, input *Input, args []string) (common.Executor, error) {
+	log.Debugf("Loading environment from %s", input.Envfile())
+	envs := make(map[string]string)
+	_ = parseEnvs(input.envs, envs)
+	_ = readEnvs(input.Envfile(), envs)
+
+	log.Debugf("Loading action inputs from %s", input.Inputfile())
+	inputs := make(map[string]string)
+	_ = parseEnvs(input.inputs, inputs)
+	_ = readEnvs(input.Inputfile(), inputs)
+
+	log.Debugf("Loading secrets from %s", input.Secretfile())
+	secrets := newSecrets(input.secrets, input.secretCache)
+	_ = readEnvs(input.Secretfile(), secrets)
+
+	planner, err := newWorkflowPlanner(input)
+	if err != nil {
+		return nil, err
+	}
+
+	jobID, err := cmd.Flags().GetString("job")
+	if err != nil {
+		return nil, err
+	}
+
+	// check if we should just list the workflows
+	list, err := cmd.Flags().GetBool("list")
+	if err != nil {
+		return nil, err
+	}
+	listJSON, err := cmd.Flags().GetBool("list-json")
+	if err != nil {
+		return nil, err
+	}
+	listFormat, err := cmd.Flags().GetString("format")
+	if err != nil {
+		return nil, err
+	}
+	switch listFormat {
+	case "table", "json", "yaml":
+	default:
+		return nil, fmt.Errorf("invalid format '%s', expected table, json or yaml", listFormat)
+	}
+
+	// check if we should just draw the graph
+	graph, err := cmd.Flags().GetBool("graph")
+	if err != nil {
+		return nil, err
+	}
+
+	// collect all events from loaded workflows
+	events := planner.GetEvents()
+
+	// plan with filtered jobs - to be used for filtering only
+	var filterPlan *model.Plan
+
+	// Determine the event name to be filtered
+	var filterEventName string = ""
+
+	if len(args) > 0 {
+		log.Debugf("Using first passed in arguments event for filtering: %s", args[0])
+		filterEventName = args[0]
+	} else if input.autodetectEvent && len(events) > 0 && len(events[0]) > 0 {
+		// set default event type to first event from many available
+		// this way user dont have to specify the event.
+		log.Debugf("Using first detected workflow event for filtering: %s", events[0])
+		filterEventName = events[0]
+	}
+
+	if jobID != "" {
+		log.Debugf("Preparing plan with a job: %s", jobID)
+		filterPlan = planner.PlanJob(jobID)
+	} else if filterEventName != "" {
+		log.Debugf("Preparing plan for a event: %s", filterEventName)
+		filterPlan = planner.PlanEvent(filterEventName)
+	} else {
+		log.Debugf("Preparing plan with all jobs")
+		filterPlan = planner.PlanAll()
+	}
+
+	if listJSON {
+		return nil, printListJSON(filterPlan)
+	}
+	if list && listFormat != "table" {
+		return nil, printListDocument(os.Stdout, filterPlan, listFormat)
+	}
+	if list {
+		return nil, printList(filterPlan)
+	}
+
+	if graph {
+		return nil, drawGraph(filterPlan)
+	}
+
+	// plan with triggered jobs
+	var plan *model.Plan
+
+	// Determine the event name to be triggered
+	var eventName string
+
+	if len(args) > 0 {
+		log.Debugf("Using first passed in arguments event: %s", args[0])
+		eventName = args[0]
+	} else if len(events) == 1 && len(events[0]) > 0 {
+		log.Debugf("Using the only detected workflow event: %s", events[0])
+		eventName = events[0]
+	} else if input.autodetectEvent && len(events) > 0 && len(events[0]) > 0 {
+		// set default event type to first event from many available
+		// this way user dont have to specify the event.
+		log.Debugf("Using first detected workflow event: %s", events[0])
+		eventName = events[0]
+	} else {
+		log.Debugf("Using default workflow event: push")
+		eventName = "push"
+	}
+
+	// build the plan for this run
+	if jobID != "" {
+		log.Debugf("Planning job: %s", jobID)
+		plan = planner.PlanJob(jobID)
+	} else {
+		log.Debugf("Planning jobs for event: %s", eventName)
+		plan = planner.PlanEvent(eventName)
+	}
+
+	eventPath := input.EventPath()
+	workflowFile, workflowConfig, err := input.readWorkflowConfig(plan)
+	if err != nil {
+		return nil, err
+	}
+	if workflowConfig != nil {
+		if workflowConfig.EventPath != "" && !cmd.Flags().Changed("eventpath") {
+			eventPath = input.resolve(workflowConfig.EventPath)
+		}
+		for _, envfile := range workflowConfig.EnvFiles {
+			log.Debugf("Loading environment of %s from %s", workflowFile, input.resolve(envfile))
+			if !readEnvs(input.resolve(envfile), envs) {
+				return nil, fmt.Errorf("env file %s of %s not found", envfile, workflowFile)
+			}
+		}
+		skipJobs(plan, workflowConfig.SkipJobs)
+	}
+	if eventName == "workflow_dispatch" && eventPath == "" {
+		if err := promptDispatchInputs(plan, inputs); err != nil {
+			return nil, err
+		}
+	}
+
+	// check to see if the main branch was defined
+	defaultbranch, err := cmd.Flags().GetString("defaultbranch")
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if platforms flag is set, if not, run default image survey
+	// (--use-host runs the jobs without an image)
+	if len(input.platforms) == 0 && !input.useHost {
+		cfgFound := false
+		cfgLocations := configLocations()
+		for _, v := range cfgLocations {
+			_, err := os.Stat(v)
+			if os.IsExist(err) {
+				cfgFound = true
+			}
+		}
+		if !cfgFound && len(cfgLocations) > 0 {
+			if err := defaultImageSurvey(cfgLocations[0]); err != nil {
+				log.Fatal(err)
+			}
+			input.platforms = readArgsFile(cfgLocations[0], true)
+		}
+	}
+	deprecationWarning := "--%s is deprecated and will be removed soon, please switch to cli: `--container-options \"%[2]s\"` or `.actrc`: `--container-options %[2]s`."
+	if input.privileged {
+		log.Warnf(deprecationWarning, "privileged", "--privileged")
+	}
+	if len(input.usernsMode) > 0 {
+		log.Warnf(deprecationWarning, "userns", fmt.Sprintf("--userns=%s", input.usernsMode))
+	}
+	if len(input.containerCapAdd) > 0 {
+		log.Warnf(deprecationWarning, "container-cap-add", fmt.Sprintf("--cap-add=%s", input.containerCapAdd))
+	}
+	if len(input.containerCapDrop) > 0 {
+		log.Warnf(deprecationWarning, "container-cap-drop", fmt.Sprintf("--cap-drop=%s", input.containerCapDrop))
+	}
+
+	mockActions, err := input.newMockActions()
+	if err != nil {
+		return nil, err
+	}
+
+	injectSteps := make([]*runner.InjectStep, 0, len(input.injectSteps))
+	for _, value := range input.injectSteps {
+		injectStep, err := runner.ParseInjectStep(value)
 		if err != nil {
-			return err
+			return nil, err
 		}
+		injectSteps = append(injectSteps, injectStep)
+	}
 
-		cancel := artifacts.Serve(ctx, input.artifactServerPath, input.artifactServerAddr, input.artifactServerPort)
+	runAttempt := 0
+	if cmd.Flags().Changed("run-attempt") {
+		if input.runAttempt < 1 {
+			return nil, fmt.Errorf("invalid run attempt %d, the first attempt of a run is 1", input.runAttempt)
+		}
+		runAttempt = input.runAttempt
+	}
 
-		ctx = common.WithDryrun(ctx, input.dryrun)
-		if watch, err := cmd.Flags().GetBool("watch"); err != nil {
-			return err
-		} else if watch {
-			return watchAndRun(ctx, r.NewPlanExecutor(plan))
+	contextOverrides, err := input.ContextOverrides()
+	if err != nil {
+		return nil, err
+	}
+	runnerManifest, err := input.RunnerManifest()
+	if err != nil {
+		return nil, err
+	}
+	caCertificates, err := input.CACertificates()
+	if err != nil {
+		return nil, err
+	}
+	gitIdentity, err := input.GitIdentity()
+	if err != nil {
+		return nil, err
+	}
+	cacheVolumes, err := input.CacheVolumes()
+	if err != nil {
+		return nil, err
+	}
+	imageLock, err := input.ImageLock()
+	if err != nil {
+		return nil, err
+	}
+	containerUsers, err := input.ContainerUsers()
+	if err != nil {
+		return nil, err
+	}
+	matrix, err := input.Matrix()
+	if err != nil {
+		return nil, err
+	}
+	containerBackend, err := input.ContainerBackend()
+	if err != nil {
+		return nil, err
+	}
+	containerDaemonSocket := input.containerDaemonSocket
+	if containerBackend == container.BackendPodman && !cmd.Flags().Changed("container-daemon-socket") {
+		// the socket of the API service of podman is mounted instead
+		containerDaemonSocket = ""
+	}
+	graphqlFixtures, err := input.GraphQLFixtures()
+	if err != nil {
+		return nil, err
+	}
+	containerCreateHooks, err := input.ContainerCreateHooks()
+	if err != nil {
+		return nil, err
+	}
+	engineRoutes, err := input.EngineRoutes()
+	if err != nil {
+		return nil, err
+	}
+	progressInterval := input.progressInterval
+	if verbose, _ := cmd.Flags().GetBool("verbose"); verbose && !cmd.Flags().Changed("progress-interval") {
+		progressInterval = 30 * time.Second
+	}
+
+	platforms := input.newPlatforms()
+	if workflowConfig != nil {
+		for platform, image := range workflowConfig.Platforms {
+			platforms[platform] = image
 		}
+	}
 
-		executor := r.NewPlanExecutor(plan).Finally(func(ctx context.Context) error {
-			cancel()
-			return nil
-		})
-		return executor(ctx)
+	branchScope := ""
+	if watch, _ := cmd.Flags().GetBool("watch"); watch {
+		branchScope = watchBranchScope(context.Background(), input, defaultbranch)
+	}
+
+	if input.stepDebug && input.stepDebugger == nil {
+		// the prompt keeps the commands it has read ahead from stdin across the plans of --watch
+		input.stepDebugger = runner.NewStepDebugger(os.Stdin, os.Stderr)
+	}
+
+	// run the plan
+	config := &runner.Config{
+		Actor:                              input.actor,
+		EventName:                          eventName,
+		EventPath:                          eventPath,
+		DefaultBranch:                      defaultbranch,
+		ForcePull:                          input.forcePull,
+		ForceRebuild:                       input.forceRebuild,
+		ReuseContainers:                    input.reuseContainers,
+		BranchScope:                        branchScope,
+		Workdir:                            input.Workdir(),
+		BindWorkdir:                        input.bindWorkdir,
+		LogOutput:                          !input.noOutput,
+		JSONLogger:                         input.jsonLogger,
+		Env:                                envs,
+		Secrets:                            secrets,
+		Inputs:                             inputs,
+		Token:                              secrets["GITHUB_TOKEN"],
+		InsecureSecrets:                    input.insecureSecrets,
+		Platforms:                          platforms,
+		Privileged:                         input.privileged,
+		UsernsMode:                         input.usernsMode,
+		ContainerArchitecture:              input.containerArchitecture,
+		ContainerDaemonSocket:              containerDaemonSocket,
+		ContainerBackend:                   string(containerBackend),
+		ContainerOptions:                   input.containerOptions,
+		ContainerRuntime:                   input.containerRuntime,
+		UseGitIgnore:                       input.useGitIgnore,
+		GitHubInstance:                     input.githubInstance,
+		GitHubServerURL:                    input.githubServerURL,
+		GitHubAPIURL:                       input.githubAPIURL,
+		GitHubGraphQLURL:                   input.githubGraphQLURL,
+		ContainerCapAdd:                    input.containerCapAdd,
+		ContainerCapDrop:                   input.containerCapDrop,
+		ContainerUsers:                     containerUsers,
+		AutoRemove:                         input.autoRemove,
+		ArtifactServerPath:                 input.artifactServerPath,
+		ArtifactServerAddr:                 input.ArtifactServerAddr(),
+		ArtifactServerPort:                 input.artifactServerPort,
+		CacheServerPath:                    input.cacheServerPath,
+		CacheServerPort:                    input.cacheServerPort,
+		FixturesServerPath:                 input.FixturesServerPath(),
+		FixturesServerPort:                 input.fixturesServerPort,
+		ConcurrencyDir:                     input.concurrencyDir,
+		NoSkipCheckout:                     input.noSkipCheckout,
+		RemoteName:                         input.remoteName,
+		ReplaceGheActionWithGithubCom:      input.replaceGheActionWithGithubCom,
+		ReplaceGheActionTokenWithGithubCom: input.replaceGheActionTokenWithGithubCom,
+		ComposeServices:                    input.ComposeServices(),
+		NoSimulateCI:                       !input.simulateCI,
+		MockActions:                        mockActions,
+		InjectSteps:                        injectSteps,
+		SimulatePermissions:                input.simulatePermissions,
+		RunAttempt:                         runAttempt,
+		Preflight:                          input.preflight,
+		LogSinks:                           input.sinks,
+		RuntimeTokens:                      input.runtimeTokens,
+		OutputSizeLimit:                    input.outputSizeLimit,
+		EnvSizeLimit:                       input.envSizeLimit,
+		StrictLimits:                       input.strictLimits,
+		PropagateEnvToNeeds:                input.propagateEnvToNeeds,
+		WorkflowPrefix:                     input.workflowPrefix,
+		ExperimentalGoActions:              input.experimentalGoActions,
+		RunnerManifest:                     runnerManifest,
+		LogPrefix:                          input.logPrefix,
+		Repository:                         input.repository,
+		MatrixWorkspace:                    input.matrixWorkspace,
+		CACertificates:                     caCertificates,
+		SSHAgent:                           input.sshAgent,
+		SSHAgentKeys:                       input.SSHAgentKeys(),
+		GitIdentity:                        gitIdentity,
+		CacheVolumes:                       cacheVolumes,
+		ImageLock:                          imageLock,
+		FrozenImageLock:                    input.frozenImageLock,
+		LocalRegistry:                      input.localRegistry,
+		UseHost:                            input.useHost,
+		EngineRoutes:                       engineRoutes,
+		GithubScript:                       input.githubScript,
+		HangTimeout:                        input.hangTimeout,
+		KillHungSteps:                      input.killHungSteps,
+		SkipUnsupported:                    input.skipUnsupported,
+		ShellOnError:                       input.shellOnError,
+		MaxParallelJobs:                    input.concurrentJobs,
+		ExportWorkspace:                    input.exportWorkspace,
+		StepDebugger:                       input.stepDebugger,
+		TraceExpressions:                   input.traceExpressions,
+		GraphQLFixtures:                    graphqlFixtures,
+		Matrix:                             matrix,
+		ContainerCreateHooks:               containerCreateHooks,
+		ProgressInterval:                   progressInterval,
+		ContextOverrides:                   contextOverrides,
 	}
+	r, err := runner.New(config)
+	if err != nil {
+		return nil, err
+	}
+	executor := r.NewPlanExecutor(plan)
+	if input.reportToGithub {
+		if secrets["GITHUB_TOKEN"] == "" {
+			return nil, fmt.Errorf("--report-to-github requires a token allowed to create commit statuses, pass it with -s GITHUB_TOKEN")
+		}
+		executor = newGithubReportExecutor(input, secrets["GITHUB_TOKEN"], executor)
+	}
+	return executor, nil
 }
 
-func defaultImageSurvey(actrc string) error {
+// defaultImageOptions are the platforms of .actrc for the image sizes of the default image survey
+var defaultImageOptions = map[string]string{
+	"Large":  "-P ubuntu-latest=catthehacker/ubuntu:full-latest\n-P ubuntu-latest=catthehacker/ubuntu:full-20.04\n-P ubuntu-18.04=catthehacker/ubuntu:full-18.04\n",
+	"Medium": "-P ubuntu-latest=catthehacker/ubuntu:act-latest\n-P ubuntu-22.04=catthehacker/ubuntu:act-22.04\n-P ubuntu-20.04=catthehacker/ubuntu:act-20.04\n-P ubuntu-18.04=catthehacker/ubuntu:act-18.04\n",
+	"Micro":  "-P ubuntu-latest=node:16-buster-slim\n-P ubuntu-22.04=node:16-bullseye-slim\n-P ubuntu-20.04=node:16-buster-slim\n-P ubuntu-18.04=node:16-buster-slim\n",
+}
+
+// askDefaultImage asks for the size of the default image, one of the keys of defaultImageOptions
+func askDefaultImage() (string, error) {
 	var answer string
 	confirmation := &survey.Select{
 		Message: "Please choose the default image you want to use with act:\n\n  - Large size image: +20GB Docker image, includes almost all tools used on GitHub Actions (IMPORTANT: currently only ubuntu-18.04 platform is available)\n  - Medium size image: ~500MB, includes only necessary tools to bootstrap actions and aims to be compatible with all actions\n  - Micro size image: <200MB, contains only NodeJS required to bootstrap actions, doesn't work with all actions\n\nDefault image and other options can be changed manually in ~/.actrc (please refer to https://github.com/nektos/act#configuration for additional information about file structure)",
@@ -520,21 +1134,16 @@ func defaultImageSurvey(actrc string) error {
 		Default: "Medium",
 		Options: []string{"Large", "Medium", "Micro"},
 	}
-
 	err := survey.AskOne(confirmation, &answer)
+	return answer, err
+}
+
+func defaultImageSurvey(actrc string) error {
+	answer, err := askDefaultImage()
 	if err != nil {
 		return err
 	}
-
-	var option string
-	switch answer {
-	case "Large":
-		option = "-P ubuntu-latest=catthehacker/ubuntu:full-latest\n-P ubuntu-latest=catthehacker/ubuntu:full-20.04\n-P ubuntu-18.04=catthehacker/ubuntu:full-18.04\n"
-	case "Medium":
-		option = "-P ubuntu-latest=catthehacker/ubuntu:act-latest\n-P ubuntu-22.04=catthehacker/ubuntu:act-22.04\n-P ubuntu-20.04=catthehacker/ubuntu:act-20.04\n-P ubuntu-18.04=catthehacker/ubuntu:act-18.04\n"
-	case "Micro":
-		option = "-P ubuntu-latest=node:16-buster-slim\n-P ubuntu-22.04=node:16-bullseye-slim\n-P ubuntu-20.04=node:16-buster-slim\n-P ubuntu-18.04=node:16-buster-slim\n"
-	}
+	option := defaultImageOptions[answer]
 
 	f, err := os.Create(actrc)
 	if err != nil {
@@ -579,18 +1188,53 @@ func watchAndRun(ctx context.Context, fn common.Executor) error {
 
 	folderWatcher.Start()
 
+	// the runs are serialized, the changes during a run cancel its concurrency groups with cancel-in-progress and
+	// queue the next run, like a push superseding the one in progress on GitHub
 	go func() {
-		for folderWatcher.IsRunning() {
-			if err = fn(ctx); err != nil {
-				break
-			}
-			log.Debugf("Watching %s for changes", dir)
-			for changes := range folderWatcher.ChangeDetails() {
-				log.Debugf("%s", changes.String())
-				if err = fn(ctx); err != nil {
-					break
+		done := make(chan error)
+		running := false
+		var next context.Context
+		start := func(ctx context.Context) {
+			running = true
+			go func() {
+				done <- fn(ctx)
+			}()
+		}
+		start(ctx)
+		changeDetails := folderWatcher.ChangeDetails()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case runErr := <-done:
+				running = false
+				if next != nil {
+					// the superseded run may fail by its cancelled jobs
+					start(next)
+					next = nil
+					continue
+				}
+				if runErr != nil && !errors.Is(runErr, errHistoryRunCancelled) {
+					err = runErr
+					return
 				}
 				log.Debugf("Watching %s for changes", dir)
+			case changes, ok := <-changeDetails:
+				if !ok {
+					return
+				}
+				log.Debugf("%s", changes.String())
+				trigger := watchTrigger(dir, changes.New(), changes.Moved(), changes.Modified())
+				if !running {
+					start(withHistoryTrigger(ctx, trigger))
+					continue
+				}
+				next = withHistoryTrigger(ctx, trigger)
+				if cancelled := runner.CancelInProgress(); cancelled > 0 {
+					log.Infof("Cancelling %d concurrency groups with cancel-in-progress, the changes supersede the run in progress", cancelled)
+				} else {
+					log.Infof("The changes are run once the run in progress is done")
+				}
 			}
 		}
 	}()
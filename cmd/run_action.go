@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/runner"
+)
+
+// runActionJobID is the job of the workflow generated to run an action
+const runActionJobID = "action"
+
+func newRunActionCommand(ctx context.Context, input *Input) *cobra.Command {
+	var actionInputs []string
+	var runsOn string
+
+	runActionCmd := &cobra.Command{
+		Use:   "run-action [action]",
+		Short: "Run a single action without a workflow and print its outputs, the action is a local path (e.g. ./my-action) or owner/repo[/path]@ref",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			uses, err := runActionUses(input.Workdir(), args[0])
+			if err != nil {
+				return err
+			}
+			with, err := parseActionInputs(actionInputs)
+			if err != nil {
+				return err
+			}
+
+			workflowsPath, err := os.MkdirTemp("", "act-run-action")
+			if err != nil {
+				return err
+			}
+			defer os.RemoveAll(workflowsPath)
+			if err := writeRunActionWorkflow(filepath.Join(workflowsPath, "run-action.yml"), uses, runsOn, with); err != nil {
+				return err
+			}
+
+			finishRun, err := prepareRun(input)
+			if err != nil {
+				return err
+			}
+			defer finishRun()
+
+			actionInput := *input
+			actionInput.workflowsPath = workflowsPath
+			actionInput.noWorkflowRecurse = true
+			actionInput.inputs = nil
+			// the flags of the run command (e.g. --job) keep their defaults
+			executor, err := newPlanExecutor(cmd.Root(), &actionInput, []string{"workflow_dispatch"})
+			if err != nil || executor == nil {
+				return err
+			}
+
+			cancel := startServers(ctx, input)
+			defer cancel()
+
+			results := &runner.Results{}
+			err = executor(runner.WithResults(common.WithDryrun(ctx, input.dryrun), results))
+			if printErr := printActionOutputs(os.Stdout, results); printErr != nil && err == nil {
+				err = printErr
+			}
+			return err
+		},
+	}
+	runActionCmd.Flags().StringArrayVarP(&actionInputs, "input", "", []string{}, "input of the action (e.g. --input name=value)")
+	runActionCmd.Flags().StringVar(&runsOn, "runs-on", "ubuntu-latest", "platform of the job running the action")
+	runActionCmd.Flags().StringArrayVarP(&input.secrets, "secret", "s", []string{}, "secret to make available to the action with optional value (e.g. -s mysecret=foo or -s mysecret)")
+	runActionCmd.Flags().StringArrayVarP(&input.envs, "env", "", []string{}, "env to make available to the action with optional value (e.g. --env myenv=foo or --env myenv)")
+	runActionCmd.Flags().StringArrayVarP(&input.platforms, "platform", "P", []string{}, "custom image to use per platform (e.g. -P ubuntu-18.04=nektos/act-environments-ubuntu:18.04)")
+	return runActionCmd
+}
+
+// runActionUses returns the uses of the step running the action, a local action has to be inside the working directory
+func runActionUses(workdir string, action string) (string, error) {
+	if strings.HasPrefix(action, "docker://") {
+		return action, nil
+	}
+
+	path := action
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(workdir, path)
+	}
+	if _, err := os.Stat(path); err != nil {
+		if strings.Contains(action, "@") {
+			// a remote action
+			return action, nil
+		}
+		return "", fmt.Errorf("action '%s' is neither a local path nor owner/repo@ref", action)
+	}
+
+	rel, err := filepath.Rel(workdir, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("local action '%s' has to be inside the working directory %s", action, workdir)
+	}
+	return "./" + filepath.ToSlash(rel), nil
+}
+
+// parseActionInputs parses the --input flags of the form name=value
+func parseActionInputs(values []string) (map[string]string, error) {
+	with := make(map[string]string, len(values))
+	for _, value := range values {
+		name, v, ok := strings.Cut(value, "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("invalid input '%s', expected name=value", value)
+		}
+		with[name] = v
+	}
+	return with, nil
+}
+
+// writeRunActionWorkflow writes a workflow with a single job running the action
+func writeRunActionWorkflow(file string, uses string, runsOn string, with map[string]string) error {
+	step := map[string]interface{}{
+		"id":   runActionJobID,
+		"uses": uses,
+	}
+	if len(with) > 0 {
+		step["with"] = with
+	}
+	workflow := map[string]interface{}{
+		"name": "run-action",
+		"on":   "workflow_dispatch",
+		"jobs": map[string]interface{}{
+			runActionJobID: map[string]interface{}{
+				"name":    uses,
+				"runs-on": runsOn,
+				"steps":   []interface{}{step},
+			},
+		},
+	}
+
+	b, err := yaml.Marshal(workflow)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, b, 0o600)
+}
+
+// printActionOutputs prints the conclusion and the outputs of the action
+func printActionOutputs(out io.Writer, results *runner.Results) error {
+	for _, job := range results.Job(runActionJobID) {
+		step, ok := job.Steps[runActionJobID]
+		if !ok {
+			continue
+		}
+
+		names := make([]string, 0, len(step.Outputs))
+		for name := range step.Outputs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(w, "Conclusion\t%s\n", step.Conclusion)
+		for _, name := range names {
+			fmt.Fprintf(w, "Output %s\t%s\n", name, step.Outputs[name])
+		}
+		return w.Flush()
+	}
+	return nil
+}
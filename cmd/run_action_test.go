@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nektos/act/pkg/model"
+	"github.com/nektos/act/pkg/runner"
+)
+
+func TestRunActionUses(t *testing.T) {
+	workdir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(workdir, "actions", "hello"), 0o755))
+	outside := t.TempDir()
+
+	tables := []struct {
+		action string
+		uses   string
+		err    string
+	}{
+		{"./actions/hello", "./actions/hello", ""},
+		{"actions/hello", "./actions/hello", ""},
+		{filepath.Join(workdir, "actions", "hello"), "./actions/hello", ""},
+		{"actions/checkout@v3", "actions/checkout@v3", ""},
+		{"docker://alpine:3.17", "docker://alpine:3.17", ""},
+		{"./missing", "", "neither a local path nor owner/repo@ref"},
+		{outside, "", "has to be inside the working directory"},
+	}
+	for _, table := range tables {
+		uses, err := runActionUses(workdir, table.action)
+		if table.err != "" {
+			assert.ErrorContains(t, err, table.err, table.action)
+			continue
+		}
+		assert.NoError(t, err, table.action)
+		assert.Equal(t, table.uses, uses, table.action)
+	}
+}
+
+func TestParseActionInputs(t *testing.T) {
+	with, err := parseActionInputs([]string{"name=act", "empty=", "expr=a=b"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"name": "act", "empty": "", "expr": "a=b"}, with)
+
+	_, err = parseActionInputs([]string{"name"})
+	assert.ErrorContains(t, err, "invalid input 'name'")
+	_, err = parseActionInputs([]string{"=value"})
+	assert.ErrorContains(t, err, "invalid input '=value'")
+}
+
+func TestWriteRunActionWorkflow(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "run-action.yml")
+	assert.NoError(t, writeRunActionWorkflow(file, "actions/hello@v1", "ubuntu-22.04", map[string]string{"who": "act"}))
+
+	f, err := os.Open(file)
+	assert.NoError(t, err)
+	defer f.Close()
+	workflow, err := model.ReadWorkflow(f)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"workflow_dispatch"}, workflow.On())
+	job := workflow.GetJob(runActionJobID)
+	assert.Equal(t, "actions/hello@v1", job.Name)
+	assert.Equal(t, []string{"ubuntu-22.04"}, job.RunsOn())
+	assert.Len(t, job.Steps, 1)
+	assert.Equal(t, runActionJobID, job.Steps[0].ID)
+	assert.Equal(t, "actions/hello@v1", job.Steps[0].Uses)
+	assert.Equal(t, map[string]string{"who": "act"}, job.Steps[0].With)
+}
+
+func TestPrintActionOutputs(t *testing.T) {
+	results := &runner.Results{Jobs: []*runner.JobResult{
+		{JobID: "other"},
+		{JobID: runActionJobID, Steps: map[string]*model.StepResult{
+			runActionJobID: {
+				Conclusion: model.StepStatusSuccess,
+				Outputs:    map[string]string{"version": "1.2.3", "digest": "sha256:abc"},
+			},
+		}},
+	}}
+
+	out := &bytes.Buffer{}
+	assert.NoError(t, printActionOutputs(out, results))
+	assert.Equal(t, "Conclusion      success\nOutput digest   sha256:abc\nOutput version  1.2.3\n", out.String())
+
+	out.Reset()
+	assert.NoError(t, printActionOutputs(out, &runner.Results{}))
+	assert.Empty(t, out.String())
+}
@@ -1,9 +1,13 @@
 package cmd
 
 import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
 	"fmt"
 	"os"
 	"strings"
+	"sync"
 
 	log "github.com/sirupsen/logrus"
 	"golang.org/x/term"
@@ -11,7 +15,84 @@ import (
 
 type secrets map[string]string
 
-func newSecrets(secretList []string) secrets {
+// Values of --secret-cache
+const (
+	secretCacheNone    = "none"    // the secrets are prompted for on every run
+	secretCacheSession = "session" // the secrets are prompted for once per process, e.g. for all runs of --watch
+)
+
+// secretCache keeps the secrets entered at the prompt for the session of the process, the values are encrypted
+// with a key which only exists in the memory of the process
+type secretCache struct {
+	mu     sync.Mutex
+	aead   cipher.AEAD
+	values map[string][]byte // nonce followed by the sealed value
+}
+
+func newSecretCache() (*secretCache, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &secretCache{aead: aead, values: map[string][]byte{}}, nil
+}
+
+func (c *secretCache) get(name string) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	sealed, ok := c.values[name]
+	if !ok {
+		return "", false
+	}
+	nonceSize := c.aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return "", false
+	}
+	value, err := c.aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], []byte(name))
+	if err != nil {
+		return "", false
+	}
+	return string(value), true
+}
+
+func (c *secretCache) put(name string, value string) {
+	if c == nil {
+		return
+	}
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.values[name] = c.aead.Seal(nonce, nonce, []byte(value), []byte(name))
+}
+
+// clear drops the cached secrets at the end of the session
+func (c *secretCache) clear() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.values = map[string][]byte{}
+}
+
+// newSecrets reads the secrets of -s, a secret without a value is read from the environment or prompted for,
+// the prompted values are kept in the cache if it isn't nil
+func newSecrets(secretList []string, cache *secretCache) secrets {
 	s := make(map[string]string)
 	for _, secretPair := range secretList {
 		secretPairParts := strings.SplitN(secretPair, "=", 2)
@@ -23,6 +104,9 @@ func newSecrets(secretList []string) secrets {
 			s[secretPairParts[0]] = secretPairParts[1]
 		} else if env, ok := os.LookupEnv(secretPairParts[0]); ok && env != "" {
 			s[secretPairParts[0]] = env
+		} else if cached, ok := cache.get(secretPairParts[0]); ok {
+			log.Debugf("Using the value of secret %s entered before in this session", secretPairParts[0])
+			s[secretPairParts[0]] = cached
 		} else {
 			fmt.Printf("Provide value for '%s': ", secretPairParts[0])
 			val, err := term.ReadPassword(int(os.Stdin.Fd()))
@@ -32,6 +116,7 @@ func newSecrets(secretList []string) secrets {
 				os.Exit(1)
 			}
 			s[secretPairParts[0]] = string(val)
+			cache.put(secretPairParts[0], string(val))
 		}
 	}
 	return s
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecretCache(t *testing.T) {
+	cache, err := newSecretCache()
+	assert.NoError(t, err)
+
+	cache.put("TOKEN", "s3cr3t")
+	value, ok := cache.get("TOKEN")
+	assert.True(t, ok)
+	assert.Equal(t, "s3cr3t", value)
+	assert.NotContains(t, string(cache.values["TOKEN"]), "s3cr3t", "the value is kept encrypted")
+
+	_, ok = cache.get("OTHER")
+	assert.False(t, ok)
+
+	// the cache reads the prompted secrets without prompting again
+	assert.Equal(t, secrets{"TOKEN": "s3cr3t"}, newSecrets([]string{"token"}, cache))
+
+	cache.clear()
+	_, ok = cache.get("TOKEN")
+	assert.False(t, ok)
+
+	var nilCache *secretCache
+	nilCache.put("TOKEN", "s3cr3t")
+	_, ok = nilCache.get("TOKEN")
+	assert.False(t, ok)
+}
+
+func TestSecretCacheWrongKey(t *testing.T) {
+	cache, err := newSecretCache()
+	assert.NoError(t, err)
+	other, err := newSecretCache()
+	assert.NoError(t, err)
+
+	cache.put("TOKEN", "s3cr3t")
+
+	// a value sealed with the key of another process doesn't open
+	other.values["TOKEN"] = cache.values["TOKEN"]
+	_, ok := other.get("TOKEN")
+	assert.False(t, ok)
+
+	// a value is bound to the name it was sealed for
+	cache.values["OTHER"] = cache.values["TOKEN"]
+	_, ok = cache.get("OTHER")
+	assert.False(t, ok)
+}
+
+func TestSecretCacheCorrupted(t *testing.T) {
+	cache, err := newSecretCache()
+	assert.NoError(t, err)
+
+	cache.put("TOKEN", "s3cr3t")
+	sealed := cache.values["TOKEN"]
+	sealed[len(sealed)-1] ^= 0xff
+	_, ok := cache.get("TOKEN")
+	assert.False(t, ok)
+
+	cache.put("TOKEN", "s3cr3t")
+	cache.values["TOKEN"] = cache.values["TOKEN"][:cache.aead.NonceSize()+1]
+	_, ok = cache.get("TOKEN")
+	assert.False(t, ok)
+
+	cache.values["TOKEN"] = []byte{1}
+	_, ok = cache.get("TOKEN")
+	assert.False(t, ok)
+}
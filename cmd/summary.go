@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/term"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/runner"
+)
+
+// newSummaryExecutor prints the summaries the steps wrote to GITHUB_STEP_SUMMARY after the run, also if it failed, or
+// writes them to the directory of --summary-path
+func newSummaryExecutor(input *Input, executor common.Executor) common.Executor {
+	return func(ctx context.Context) error {
+		results := runner.ResultsFrom(ctx)
+		if results == nil {
+			results = &runner.Results{}
+			ctx = runner.WithResults(ctx, results)
+		}
+		err := executor(ctx)
+		if common.Dryrun(ctx) {
+			return err
+		}
+
+		if input.SummaryPath() != "" {
+			if writeErr := writeSummaries(input.SummaryPath(), results.Jobs); writeErr != nil {
+				log.Errorf("Unable to write the step summaries: %v", writeErr)
+			}
+		} else {
+			printSummaries(os.Stdout, results.Jobs, term.IsTerminal(int(os.Stdout.Fd())))
+		}
+		return err
+	}
+}
+
+// summaryFileNamePattern matches the characters of the names of the jobs replaced in the names of the summary files
+var summaryFileNamePattern = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// writeSummaries writes the summary of every job with one to a markdown file named by the job in dir
+func writeSummaries(dir string, jobs []*runner.JobResult) error {
+	written := map[string]bool{}
+	for _, job := range jobs {
+		if job.Summary == "" {
+			continue
+		}
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+		name := strings.Trim(summaryFileNamePattern.ReplaceAllString(job.Name, "-"), "-")
+		file := name + ".md"
+		for i := 2; written[file]; i++ {
+			file = fmt.Sprintf("%s-%d.md", name, i)
+		}
+		written[file] = true
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(job.Summary), 0o644); err != nil {
+			return err
+		}
+		log.Infof("Wrote the summary of the job '%s' to %s", job.Name, filepath.Join(dir, file))
+	}
+	return nil
+}
+
+// printSummaries prints the summary of every job with one, rendered for the terminal
+func printSummaries(out io.Writer, jobs []*runner.JobResult, colored bool) {
+	for _, job := range jobs {
+		if job.Summary == "" {
+			continue
+		}
+		heading := fmt.Sprintf("Summary of %s", job.Name)
+		fmt.Fprintf(out, "\n%s\n%s\n", style(heading, colored, ansiBold), strings.Repeat("=", utf8.RuneCountInString(heading)))
+		fmt.Fprint(out, renderMarkdown(job.Summary, colored))
+	}
+}
+
+const (
+	ansiBold      = "\x1b[1m"
+	ansiDim       = "\x1b[2m"
+	ansiUnderline = "\x1b[4m"
+	ansiCyan      = "\x1b[36m"
+	ansiReset     = "\x1b[0m"
+)
+
+func style(text string, colored bool, codes ...string) string {
+	if !colored || text == "" {
+		return text
+	}
+	return strings.Join(codes, "") + text + ansiReset
+}
+
+var (
+	headingPattern    = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*$`)
+	listItemPattern   = regexp.MustCompile(`^(\s*)[-*+]\s+(.*)$`)
+	rulePattern       = regexp.MustCompile(`^\s*([-*_])(\s*([-*_])){2,}\s*$`)
+	tableRulePattern  = regexp.MustCompile(`^\s*\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?\s*$`)
+	breakPattern      = regexp.MustCompile(`(?i)<br\s*/?>`)
+	tagPattern        = regexp.MustCompile(`</?[A-Za-z][^>]*>`)
+	imagePattern      = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]+)[^)]*\)`)
+	linkPattern       = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)[^)]*\)`)
+	boldPattern       = regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__`)
+	inlineCodePattern = regexp.MustCompile("`([^`]+)`")
+)
+
+// renderMarkdown renders the markdown of a summary for the terminal: the headings, the emphasis and the code are
+// styled if colored, the lists get bullets, the tables are aligned and the HTML tags are removed
+func renderMarkdown(markdown string, colored bool) string {
+	var b strings.Builder
+	lines := strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n")
+	inFence := false
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			inFence = !inFence
+		case inFence:
+			b.WriteString("    " + style(line, colored, ansiDim) + "\n")
+		case strings.HasPrefix(trimmed, "|"):
+			rows := make([][]string, 0)
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), "|"); i++ {
+				if !tableRulePattern.MatchString(lines[i]) {
+					rows = append(rows, tableCells(lines[i]))
+				}
+			}
+			i--
+			b.WriteString(renderTable(rows, colored))
+		case headingPattern.MatchString(line):
+			m := headingPattern.FindStringSubmatch(line)
+			if len(m[1]) <= 2 {
+				b.WriteString(style(renderInline(m[2], false), colored, ansiBold, ansiUnderline) + "\n")
+			} else {
+				b.WriteString(style(renderInline(m[2], false), colored, ansiBold) + "\n")
+			}
+		case rulePattern.MatchString(line):
+			b.WriteString(strings.Repeat("─", 40) + "\n")
+		case listItemPattern.MatchString(line):
+			m := listItemPattern.FindStringSubmatch(line)
+			b.WriteString(m[1] + "• " + renderInline(m[2], colored) + "\n")
+		case strings.HasPrefix(trimmed, ">"):
+			b.WriteString("│ " + renderInline(strings.TrimSpace(strings.TrimPrefix(trimmed, ">")), colored) + "\n")
+		default:
+			b.WriteString(renderInline(line, colored) + "\n")
+		}
+	}
+	return b.String()
+}
+
+// renderInline renders the links, the emphasis, the code and the HTML of a line
+func renderInline(text string, colored bool) string {
+	text = breakPattern.ReplaceAllString(text, "\n")
+	text = tagPattern.ReplaceAllString(text, "")
+	text = imagePattern.ReplaceAllString(text, "$1 ($2)")
+	text = linkPattern.ReplaceAllString(text, "$1 ($2)")
+	text = inlineCodePattern.ReplaceAllStringFunc(text, func(code string) string {
+		return style(strings.Trim(code, "`"), colored, ansiCyan)
+	})
+	text = boldPattern.ReplaceAllStringFunc(text, func(bold string) string {
+		return style(bold[2:len(bold)-2], colored, ansiBold)
+	})
+	return html.UnescapeString(text)
+}
+
+// tableCells returns the cells of a row of a table
+func tableCells(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimSuffix(strings.TrimPrefix(line, "|"), "|")
+	cells := strings.Split(line, "|")
+	for i, cell := range cells {
+		cells[i] = renderInline(breakPattern.ReplaceAllString(strings.TrimSpace(cell), " "), false)
+	}
+	return cells
+}
+
+// renderTable aligns the columns of the rows, the first row is the header
+func renderTable(rows [][]string, colored bool) string {
+	widths := make([]int, 0)
+	for _, row := range rows {
+		for i, cell := range row {
+			if i == len(widths) {
+				widths = append(widths, 0)
+			}
+			if n := utf8.RuneCountInString(cell); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+	var b strings.Builder
+	for r, row := range rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = cell + strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell))
+			if r == 0 {
+				cells[i] = style(cells[i], colored, ansiBold)
+			}
+		}
+		b.WriteString(strings.TrimRight(strings.Join(cells, "  "), " ") + "\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nektos/act/pkg/runner"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	markdown := "## Test results :rocket:\n" +
+		"\n" +
+		"| Suite | Passed |\n" +
+		"|:------|-------:|\n" +
+		"| **unit** | 42 |\n" +
+		"| e2e | 7 |\n" +
+		"\n" +
+		"- see [the report](https://example.com/report)\n" +
+		"```\n" +
+		"go test ./...\n" +
+		"```\n" +
+		"Ran `act` &amp; <b>passed</b><br>done\n"
+
+	assert.Equal(t, "Test results :rocket:\n"+
+		"\n"+
+		"Suite  Passed\n"+
+		"unit   42\n"+
+		"e2e    7\n"+
+		"\n"+
+		"• see the report (https://example.com/report)\n"+
+		"    go test ./...\n"+
+		"Ran act & passed\n"+
+		"done\n"+
+		"\n", renderMarkdown(markdown, false))
+
+	assert.Contains(t, renderMarkdown("Ran `act`", true), "\x1b[36mact\x1b[0m")
+}
+
+func TestWriteSummaries(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "summaries")
+	jobs := []*runner.JobResult{
+		{Name: "build (ubuntu-latest)", Summary: "# ubuntu\n"},
+		{Name: "build (ubuntu-latest)", Summary: "# again\n"},
+		{Name: "lint"},
+	}
+	require.NoError(t, writeSummaries(dir, jobs))
+
+	content, err := os.ReadFile(filepath.Join(dir, "build-ubuntu-latest.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "# ubuntu\n", string(content))
+	content, err = os.ReadFile(filepath.Join(dir, "build-ubuntu-latest-2.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "# again\n", string(content))
+	_, err = os.Stat(filepath.Join(dir, "lint.md"))
+	assert.True(t, os.IsNotExist(err))
+
+	var out bytes.Buffer
+	printSummaries(&out, jobs, false)
+	assert.Equal(t, "\nSummary of build (ubuntu-latest)\n================================\nubuntu\n\n"+
+		"\nSummary of build (ubuntu-latest)\n================================\nagain\n\n", out.String())
+}
@@ -0,0 +1,369 @@
+package cmd
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+
+	"github.com/Masterminds/semver"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/blake2b"
+
+	"github.com/nektos/act/pkg/common"
+)
+
+// ReleaseURL is the GitHub API endpoint of the latest release of act
+const ReleaseURL = "https://api.github.com/repos/nektos/act/releases/latest"
+
+// releasePublicKey is the minisign public key the checksums of the releases are signed with, it is set at build time
+// with -ldflags "-X github.com/nektos/act/cmd.releasePublicKey=<key>", act upgrade refuses to install a release without it
+var releasePublicKey = ""
+
+type release struct {
+	TagName string         `json:"tag_name"`
+	Assets  []releaseAsset `json:"assets"`
+}
+
+type releaseAsset struct {
+	Name               string `json:"name"`
+	BrowserDownloadURL string `json:"browser_download_url"`
+}
+
+func newUpgradeCommand(ctx context.Context, input *Input) *cobra.Command {
+	upgradeCmd := &cobra.Command{
+		Use:   "upgrade",
+		Short: "Upgrade act to the latest release",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			check, err := cmd.Flags().GetBool("check")
+			if err != nil {
+				return err
+			}
+			return upgrade(ctx, input, cmd.Root().Version, check)
+		},
+	}
+	upgradeCmd.Flags().Bool("check", false, "only report whether a newer release is available")
+	return upgradeCmd
+}
+
+func upgrade(ctx context.Context, input *Input, version string, check bool) error {
+	latest, err := getLatestRelease(ctx)
+	if err != nil {
+		return err
+	}
+	current, next, err := releaseVersions(version, latest)
+	if err != nil {
+		return err
+	}
+
+	noticeLogger := newNoticeLogger(input)
+	if !current.LessThan(next) {
+		noticeLogger.Infof("act %s is the latest release", current)
+		return nil
+	}
+	if check {
+		noticeLogger.Warnf("act %s is available (current: %s), run 'act upgrade' to install it", next, current)
+		return nil
+	}
+	if releasePublicKey == "" {
+		return fmt.Errorf("this build of act has no release signing key to authenticate act %s, download it from https://github.com/nektos/act/releases", next)
+	}
+
+	archiveName := releaseArchiveName(runtime.GOOS, runtime.GOARCH, buildSetting("GOARM"))
+	archive, err := latest.download(ctx, archiveName)
+	if err != nil {
+		return err
+	}
+	checksums, err := latest.download(ctx, "checksums.txt")
+	if err != nil {
+		return err
+	}
+	signature, err := latest.download(ctx, "checksums.txt.minisig")
+	if err != nil {
+		return err
+	}
+	// the signature authenticates the checksums, which authenticate the archive
+	if err := verifySignature(releasePublicKey, checksums, signature); err != nil {
+		return fmt.Errorf("invalid signature of the checksums of act %s: %w", next, err)
+	}
+	if err := verifyChecksum(archiveName, archive, checksums); err != nil {
+		return err
+	}
+
+	binaryName := "act"
+	if runtime.GOOS == "windows" {
+		binaryName = "act.exe"
+	}
+	binary, err := extractBinary(archiveName, archive, binaryName)
+	if err != nil {
+		return err
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	if executable, err = filepath.EvalSymlinks(executable); err != nil {
+		return err
+	}
+	if err := replaceBinary(executable, binary); err != nil {
+		return err
+	}
+
+	noticeLogger.Infof("act upgraded from %s to %s", current, next)
+	return nil
+}
+
+// releaseVersions parses the version of act and the version of the release
+func releaseVersions(version string, latest *release) (*semver.Version, *semver.Version, error) {
+	current, err := semver.NewVersion(strings.TrimSpace(version))
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to parse current version '%s': %w", version, err)
+	}
+	next, err := semver.NewVersion(latest.TagName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to parse release version '%s': %w", latest.TagName, err)
+	}
+	return current, next, nil
+}
+
+// upgradeNotice returns the notice about a newer release than version, nil if version is the latest one
+func upgradeNotice(version string, latest *release) *Notice {
+	current, next, err := releaseVersions(version, latest)
+	if err != nil {
+		log.Debug(err)
+		return nil
+	}
+	if !current.LessThan(next) {
+		return nil
+	}
+	return &Notice{
+		Level:   "info",
+		Message: fmt.Sprintf("act %s is available (current: %s), run 'act upgrade' to install it", next, current),
+	}
+}
+
+func getLatestRelease(ctx context.Context) (*release, error) {
+	body, err := httpGet(ctx, ReleaseURL)
+	if err != nil {
+		return nil, err
+	}
+	latest := &release{}
+	if err := json.Unmarshal(body, latest); err != nil {
+		return nil, err
+	}
+	return latest, nil
+}
+
+func (r *release) download(ctx context.Context, name string) ([]byte, error) {
+	for _, asset := range r.Assets {
+		if asset.Name == name {
+			log.Debugf("Downloading %s", asset.BrowserDownloadURL)
+			return httpGet(ctx, asset.BrowserDownloadURL)
+		}
+	}
+	return nil, fmt.Errorf("release %s has no asset '%s'", r.TagName, name)
+}
+
+func httpGet(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	client := &http.Client{Transport: common.NewRetryTransport(http.DefaultTransport)}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to get %s: %s", url, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+func buildSetting(key string) string {
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range info.Settings {
+			if setting.Key == key {
+				return setting.Value
+			}
+		}
+	}
+	return ""
+}
+
+// releaseArchiveName mirrors the archive name_template of .goreleaser.yml
+func releaseArchiveName(goos, goarch, goarm string) string {
+	osName := map[string]string{"darwin": "Darwin", "linux": "Linux", "windows": "Windows"}[goos]
+	arch := goarch
+	switch goarch {
+	case "386":
+		arch = "i386"
+	case "amd64":
+		arch = "x86_64"
+	case "arm":
+		if goarm == "" {
+			goarm = "7"
+		}
+		arch = fmt.Sprintf("armv%s", goarm)
+	}
+
+	ext := "tar.gz"
+	if goos == "windows" {
+		ext = "zip"
+	}
+	return fmt.Sprintf("act_%s_%s.%s", osName, arch, ext)
+}
+
+// verifySignature verifies the minisign signature of content with the minisign public key, the key may be given
+// as the base64 encoded key or as the content of the .pub file
+func verifySignature(publicKey string, content []byte, signature []byte) error {
+	keyLines := strings.Split(strings.TrimSpace(publicKey), "\n")
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(keyLines[len(keyLines)-1]))
+	if err != nil || len(key) != 2+8+ed25519.PublicKeySize || string(key[:2]) != "Ed" {
+		return fmt.Errorf("invalid minisign public key")
+	}
+
+	// untrusted comment, signature, trusted comment and global signature
+	lines := strings.Split(strings.TrimSpace(string(signature)), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[2], "trusted comment: ") {
+		return fmt.Errorf("invalid minisign signature")
+	}
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[1]))
+	if err != nil || len(sig) != 2+8+ed25519.SignatureSize {
+		return fmt.Errorf("invalid minisign signature")
+	}
+	globalSig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[3]))
+	if err != nil || len(globalSig) != ed25519.SignatureSize {
+		return fmt.Errorf("invalid minisign signature")
+	}
+	if !bytes.Equal(sig[2:10], key[2:10]) {
+		return fmt.Errorf("signed with the key %X instead of %X", sig[2:10], key[2:10])
+	}
+
+	message := content
+	switch string(sig[:2]) {
+	case "Ed":
+	case "ED":
+		// prehashed, the default of minisign
+		sum := blake2b.Sum512(content)
+		message = sum[:]
+	default:
+		return fmt.Errorf("unsupported minisign signature algorithm '%s'", sig[:2])
+	}
+	pub := ed25519.PublicKey(key[10:])
+	if !ed25519.Verify(pub, message, sig[10:]) {
+		return fmt.Errorf("signature verification failed")
+	}
+	trustedComment := strings.TrimPrefix(strings.TrimRight(lines[2], "\r"), "trusted comment: ")
+	if !ed25519.Verify(pub, append(append([]byte{}, sig[10:]...), trustedComment...), globalSig) {
+		return fmt.Errorf("trusted comment verification failed")
+	}
+	return nil
+}
+
+func verifyChecksum(name string, content []byte, checksums []byte) error {
+	sum := sha256.Sum256(content)
+	actual := hex.EncodeToString(sum[:])
+
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[1] == name {
+			if fields[0] != actual {
+				return fmt.Errorf("checksum mismatch for %s: expected %s, got %s", name, fields[0], actual)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("no checksum found for %s", name)
+}
+
+func extractBinary(archiveName string, archive []byte, binaryName string) ([]byte, error) {
+	if strings.HasSuffix(archiveName, ".zip") {
+		zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range zr.File {
+			if f.Name == binaryName {
+				rc, err := f.Open()
+				if err != nil {
+					return nil, err
+				}
+				defer rc.Close()
+				return io.ReadAll(rc)
+			}
+		}
+		return nil, fmt.Errorf("%s not found in %s", binaryName, archiveName)
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, err
+	}
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("%s not found in %s", binaryName, archiveName)
+		} else if err != nil {
+			return nil, err
+		}
+		if header.Name == binaryName {
+			return io.ReadAll(tr)
+		}
+	}
+}
+
+// replaceBinary writes the new binary next to the executable and renames it over the executable,
+// so the executable is never left in a partially written state
+func replaceBinary(executable string, binary []byte) error {
+	dir := filepath.Dir(executable)
+	f, err := os.CreateTemp(dir, ".act-upgrade-*")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	defer os.Remove(tmp)
+
+	if _, err := f.Write(binary); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp, 0o755); err != nil {
+		return err
+	}
+
+	if runtime.GOOS == "windows" {
+		// a running executable can't be overwritten on windows, but it can be renamed
+		old := executable + ".old"
+		_ = os.Remove(old)
+		if err := os.Rename(executable, old); err != nil {
+			return err
+		}
+	}
+	return os.Rename(tmp, executable)
+}
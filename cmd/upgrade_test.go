@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/blake2b"
+)
+
+func TestReleaseArchiveName(t *testing.T) {
+	tables := []struct {
+		goos, goarch, goarm string
+		expected            string
+	}{
+		{"linux", "amd64", "", "act_Linux_x86_64.tar.gz"},
+		{"linux", "386", "", "act_Linux_i386.tar.gz"},
+		{"linux", "arm64", "", "act_Linux_arm64.tar.gz"},
+		{"linux", "arm", "6", "act_Linux_armv6.tar.gz"},
+		{"linux", "arm", "", "act_Linux_armv7.tar.gz"},
+		{"darwin", "arm64", "", "act_Darwin_arm64.tar.gz"},
+		{"windows", "amd64", "", "act_Windows_x86_64.zip"},
+	}
+	for _, table := range tables {
+		assert.Equal(t, table.expected, releaseArchiveName(table.goos, table.goarch, table.goarm))
+	}
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	sum := sha256.Sum256([]byte("archive"))
+	checksums := []byte(fmt.Sprintf("%s  act_Linux_x86_64.tar.gz\n%s  act_Linux_arm64.tar.gz\n", hex.EncodeToString(sum[:]), hex.EncodeToString(make([]byte, 32))))
+
+	tables := []struct {
+		name    string
+		content string
+		err     string
+	}{
+		{"act_Linux_x86_64.tar.gz", "archive", ""},
+		{"act_Linux_arm64.tar.gz", "archive", "checksum mismatch for act_Linux_arm64.tar.gz"},
+		{"act_Linux_x86_64.tar.gz", "tampered", "checksum mismatch for act_Linux_x86_64.tar.gz"},
+		{"act_Darwin_arm64.tar.gz", "archive", "no checksum found for act_Darwin_arm64.tar.gz"},
+	}
+	for _, table := range tables {
+		err := verifyChecksum(table.name, []byte(table.content), checksums)
+		if table.err == "" {
+			assert.NoError(t, err, table.name)
+		} else {
+			assert.ErrorContains(t, err, table.err, table.name)
+		}
+	}
+}
+
+// minisign signs content like minisign -S, prehashed as by default or legacy
+func minisign(priv ed25519.PrivateKey, keyID []byte, content []byte, prehashed bool, trustedComment string) []byte {
+	alg, message := "Ed", content
+	if prehashed {
+		sum := blake2b.Sum512(content)
+		alg, message = "ED", sum[:]
+	}
+	sig := ed25519.Sign(priv, message)
+	globalSig := ed25519.Sign(priv, append(append([]byte{}, sig...), trustedComment...))
+	return []byte(fmt.Sprintf("untrusted comment: signature from minisign secret key\n%s\ntrusted comment: %s\n%s\n",
+		base64.StdEncoding.EncodeToString(append(append([]byte(alg), keyID...), sig...)),
+		trustedComment,
+		base64.StdEncoding.EncodeToString(globalSig)))
+}
+
+func TestVerifySignature(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	_, otherPriv, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	keyID := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+	publicKey := "untrusted comment: minisign public key 0807060504030201\n" + base64.StdEncoding.EncodeToString(append(append([]byte("Ed"), keyID...), pub...))
+	checksums := []byte("0123  act_Linux_x86_64.tar.gz\n")
+
+	tables := []struct {
+		name      string
+		publicKey string
+		content   []byte
+		signature []byte
+		err       string
+	}{
+		{"prehashed", publicKey, checksums, minisign(priv, keyID, checksums, true, "timestamp:1 file:checksums.txt"), ""},
+		{"legacy", publicKey, checksums, minisign(priv, keyID, checksums, false, "timestamp:1"), ""},
+		{"base64 key", base64.StdEncoding.EncodeToString(append(append([]byte("Ed"), keyID...), pub...)), checksums, minisign(priv, keyID, checksums, true, ""), ""},
+		{"tampered", publicKey, []byte("4567  act_Linux_x86_64.tar.gz\n"), minisign(priv, keyID, checksums, true, ""), "signature verification failed"},
+		{"other key", publicKey, checksums, minisign(otherPriv, keyID, checksums, true, ""), "signature verification failed"},
+		{"other key id", publicKey, checksums, minisign(priv, []byte{8, 7, 6, 5, 4, 3, 2, 1}, checksums, true, ""), "signed with the key"},
+		{"tampered trusted comment", publicKey, checksums, bytes.Replace(minisign(priv, keyID, checksums, true, "timestamp:1"), []byte("timestamp:1"), []byte("timestamp:2"), 1), "trusted comment verification failed"},
+		{"invalid key", "not a key", checksums, minisign(priv, keyID, checksums, true, ""), "invalid minisign public key"},
+		{"invalid signature", publicKey, checksums, []byte("untrusted comment: x\nnot a signature\n"), "invalid minisign signature"},
+	}
+	for _, table := range tables {
+		err := verifySignature(table.publicKey, table.content, table.signature)
+		if table.err == "" {
+			assert.NoError(t, err, table.name)
+		} else {
+			assert.ErrorContains(t, err, table.err, table.name)
+		}
+	}
+}
+
+func TestExtractBinary(t *testing.T) {
+	targz := &bytes.Buffer{}
+	gz := gzip.NewWriter(targz)
+	tw := tar.NewWriter(gz)
+	for name, content := range map[string]string{"README.md": "readme", "act": "binary"} {
+		assert.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0o755, Size: int64(len(content))}))
+		_, err := tw.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, tw.Close())
+	assert.NoError(t, gz.Close())
+
+	zipped := &bytes.Buffer{}
+	zw := zip.NewWriter(zipped)
+	w, err := zw.Create("act.exe")
+	assert.NoError(t, err)
+	_, err = w.Write([]byte("windows binary"))
+	assert.NoError(t, err)
+	assert.NoError(t, zw.Close())
+
+	tables := []struct {
+		archiveName string
+		archive     []byte
+		binaryName  string
+		expected    string
+		err         string
+	}{
+		{"act_Linux_x86_64.tar.gz", targz.Bytes(), "act", "binary", ""},
+		{"act_Linux_x86_64.tar.gz", targz.Bytes(), "act.exe", "", "act.exe not found in act_Linux_x86_64.tar.gz"},
+		{"act_Windows_x86_64.zip", zipped.Bytes(), "act.exe", "windows binary", ""},
+		{"act_Windows_x86_64.zip", zipped.Bytes(), "act", "", "act not found in act_Windows_x86_64.zip"},
+		{"act_Linux_x86_64.tar.gz", []byte("not an archive"), "act", "", "gzip"},
+	}
+	for _, table := range tables {
+		binary, err := extractBinary(table.archiveName, table.archive, table.binaryName)
+		if table.err == "" {
+			assert.NoError(t, err)
+			assert.Equal(t, table.expected, string(binary))
+		} else {
+			assert.ErrorContains(t, err, table.err)
+		}
+	}
+}
+
+func TestReplaceBinary(t *testing.T) {
+	dir := t.TempDir()
+	executable := filepath.Join(dir, "act")
+	assert.NoError(t, os.WriteFile(executable, []byte("old"), 0o755))
+
+	assert.NoError(t, replaceBinary(executable, []byte("new")))
+	content, err := os.ReadFile(executable)
+	assert.NoError(t, err)
+	assert.Equal(t, "new", string(content))
+
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	for _, entry := range entries {
+		assert.NotContains(t, entry.Name(), ".act-upgrade-", "the temporary file is removed")
+	}
+
+	assert.Error(t, replaceBinary(filepath.Join(dir, "missing", "act"), []byte("new")))
+}
+
+func TestUpgradeNotice(t *testing.T) {
+	latest := &release{TagName: "v0.2.40"}
+	assert.Equal(t, &Notice{Level: "info", Message: "act 0.2.40 is available (current: 0.2.39), run 'act upgrade' to install it"}, upgradeNotice("0.2.39\n", latest))
+	assert.Nil(t, upgradeNotice("0.2.40", latest))
+	assert.Nil(t, upgradeNotice("0.2.41", latest))
+	assert.Nil(t, upgradeNotice("dev", latest))
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/nektos/act/pkg/model"
+)
+
+func newValidateCommand(ctx context.Context, input *Input) *cobra.Command {
+	var format string
+	validateCmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Check the workflows against the workflow syntax of GitHub, the expressions, the needs of the jobs and the events, and exit non-zero on problems",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != "text" && format != "json" {
+				return fmt.Errorf("invalid format '%s', expected text or json", format)
+			}
+			files, err := workflowFiles(input.WorkflowsPath(), input.noWorkflowRecurse)
+			if err != nil {
+				return err
+			}
+
+			// the platforms of -P are the labels of the self-hosted runners
+			labels := make([]string, 0)
+			for label := range input.newPlatforms() {
+				labels = append(labels, label)
+			}
+			sort.Strings(labels)
+
+			diagnostics := make([]*model.Diagnostic, 0)
+			for _, file := range files {
+				content, err := os.ReadFile(file)
+				if err != nil {
+					return err
+				}
+				name := file
+				if rel, err := filepath.Rel(input.Workdir(), file); err == nil {
+					name = rel
+				}
+				diagnostics = append(diagnostics, model.ValidateWorkflow(name, content, labels)...)
+			}
+
+			if err := printDiagnostics(cmd.OutOrStdout(), diagnostics, format); err != nil {
+				return err
+			}
+			if len(diagnostics) > 0 {
+				return fmt.Errorf("%d problem(s) found in the %d workflow(s)", len(diagnostics), len(files))
+			}
+			if format == "text" {
+				fmt.Fprintf(cmd.OutOrStdout(), "the %d workflow(s) are valid\n", len(files))
+			}
+			return nil
+		},
+	}
+	validateCmd.Flags().StringVar(&format, "format", "text", "format of the diagnostics: text (file:line:column: message [rule]) or json (an array of objects with file, line, column, rule and message)")
+	validateCmd.Flags().StringArrayVarP(&input.platforms, "platform", "P", []string{}, "custom image to use per platform, the platforms are valid labels of runs-on (e.g. -P ubuntu-18.04=nektos/act-environments-ubuntu:18.04)")
+	return validateCmd
+}
+
+func printDiagnostics(w io.Writer, diagnostics []*model.Diagnostic, format string) error {
+	if format == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(diagnostics)
+	}
+	for _, diagnostic := range diagnostics {
+		fmt.Fprintln(w, diagnostic)
+	}
+	return nil
+}
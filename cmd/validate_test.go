@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nektos/act/pkg/model"
+)
+
+func TestPrintDiagnostics(t *testing.T) {
+	diagnostics := []*model.Diagnostic{
+		{File: ".github/workflows/ci.yml", Line: 3, Column: 5, Rule: "job-needs", Message: `job "test" needs job "deploy" which does not exist in this workflow`},
+	}
+
+	out := &bytes.Buffer{}
+	assert.NoError(t, printDiagnostics(out, diagnostics, "text"))
+	assert.Equal(t, ".github/workflows/ci.yml:3:5: job \"test\" needs job \"deploy\" which does not exist in this workflow [job-needs]\n", out.String())
+
+	out.Reset()
+	assert.NoError(t, printDiagnostics(out, diagnostics, "json"))
+	var decoded []*model.Diagnostic
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+	assert.Equal(t, diagnostics, decoded)
+
+	out.Reset()
+	assert.NoError(t, printDiagnostics(out, []*model.Diagnostic{}, "json"))
+	assert.Equal(t, "[]\n", out.String())
+}
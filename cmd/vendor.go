@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/nektos/act/pkg/runner"
+)
+
+func newVendorCommand(ctx context.Context, input *Input) *cobra.Command {
+	vendorCmd := &cobra.Command{
+		Use:   "vendor",
+		Short: "Copy the repositories of the remote actions and reusable workflows of the workflows into " + runner.VendorDir + ", the runs use the vendored copies instead of cloning them",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			planner, err := newWorkflowPlanner(input)
+			if err != nil {
+				return err
+			}
+
+			secrets := newSecrets(input.secrets, input.secretCache)
+			_ = readEnvs(input.Secretfile(), secrets)
+			config := &runner.Config{
+				Workdir:                            input.Workdir(),
+				Token:                              secrets["GITHUB_TOKEN"],
+				GitHubInstance:                     input.githubInstance,
+				ReplaceGheActionWithGithubCom:      input.replaceGheActionWithGithubCom,
+				ReplaceGheActionTokenWithGithubCom: input.replaceGheActionTokenWithGithubCom,
+			}
+			vendored, err := runner.VendorPlan(ctx, config, planner.PlanAll())
+			if err != nil {
+				return err
+			}
+			for _, repository := range vendored {
+				fmt.Println(repository)
+			}
+			if len(vendored) == 0 {
+				log.Infof("The workflows use no remote actions or reusable workflows")
+			}
+			return nil
+		},
+	}
+	vendorCmd.Flags().StringArrayVarP(&input.secrets, "secret", "s", []string{}, "secret to clone the repositories with, GITHUB_TOKEN for private repositories (e.g. -s GITHUB_TOKEN)")
+	vendorCmd.Flags().StringArrayVarP(&input.replaceGheActionWithGithubCom, "replace-ghe-action-with-github-com", "", []string{}, "actions of GitHub Enterprise Server cloned from github.com (e.g. --replace-ghe-action-with-github-com github/super-linter)")
+	vendorCmd.Flags().StringVar(&input.replaceGheActionTokenWithGithubCom, "replace-ghe-action-token-with-github-com", "", "token to clone the actions of --replace-ghe-action-with-github-com with")
+	return vendorCmd
+}
@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/mitchellh/go-homedir"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/nektos/act/pkg/runner"
+)
+
+// dockerVM is a virtual machine providing the docker daemon, like Colima or Lima on macOS
+type dockerVM struct {
+	kind     string // colima or lima
+	instance string // colima profile or lima instance
+}
+
+func parseDockerVM(value string) (*dockerVM, error) {
+	kind, instance, _ := strings.Cut(value, ":")
+	switch kind {
+	case "colima":
+		if instance == "" {
+			instance = "default"
+		}
+	case "lima":
+		if instance == "" {
+			instance = "docker"
+		}
+	default:
+		return nil, fmt.Errorf("unsupported VM '%s', expected colima[:<profile>] or lima[:<instance>]", value)
+	}
+	return &dockerVM{kind: kind, instance: instance}, nil
+}
+
+func (vm *dockerVM) String() string {
+	return fmt.Sprintf("%s instance '%s'", vm.kind, vm.instance)
+}
+
+func (vm *dockerVM) command(ctx context.Context, action string) *exec.Cmd {
+	if vm.kind == "colima" {
+		return exec.CommandContext(ctx, "colima", action, "--profile", vm.instance)
+	}
+	if action == "status" {
+		return exec.CommandContext(ctx, "limactl", "list", "--format", "{{.Status}}", vm.instance)
+	}
+	return exec.CommandContext(ctx, "limactl", action, vm.instance)
+}
+
+func (vm *dockerVM) running(ctx context.Context) (bool, error) {
+	out, err := vm.command(ctx, "status").CombinedOutput()
+	if vm.kind == "colima" {
+		// colima status exits with an error if the VM is not running
+		var exitErr *exec.ExitError
+		if err != nil && !errors.As(err, &exitErr) {
+			return false, err
+		}
+		return err == nil, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("%s: %s", err, strings.TrimSpace(string(out)))
+	}
+	return strings.TrimSpace(string(out)) == "Running", nil
+}
+
+func (vm *dockerVM) run(ctx context.Context, action string) error {
+	cmd := vm.command(ctx, action)
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// socket returns the docker socket forwarded from the VM
+func (vm *dockerVM) socket() string {
+	home, err := homedir.Dir()
+	if err != nil {
+		return ""
+	}
+	if vm.kind == "colima" {
+		return filepath.Join(home, ".colima", vm.instance, "docker.sock")
+	}
+	return filepath.Join(home, ".lima", vm.instance, "sock", "docker.sock")
+}
+
+// startDockerVM starts the VM if it is stopped, the returned function stops the VM again if it was started
+func startDockerVM(ctx context.Context, vm *dockerVM) (func(), error) {
+	running, err := vm.running(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the status of the %s: %w", vm, err)
+	}
+
+	stop := func() {}
+	if !running {
+		log.Infof("Starting the stopped %s", vm)
+		if err := vm.run(ctx, "start"); err != nil {
+			return nil, fmt.Errorf("unable to start the %s: %w", vm, err)
+		}
+		stop = func() {
+			log.Infof("Stopping the %s", vm)
+			// stop the VM even if the run was cancelled
+			if err := vm.run(context.Background(), "stop"); err != nil {
+				log.Errorf("Unable to stop the %s: %v", vm, err)
+			}
+		}
+	}
+	return stop, nil
+}
+
+// dockerHost returns the docker host of the socket forwarded from the VM, empty if the VM has no socket
+func (vm *dockerVM) dockerHost() string {
+	socket := vm.socket()
+	if socket == "" {
+		return ""
+	}
+	if _, err := os.Stat(socket); err != nil {
+		return ""
+	}
+	return "unix://" + socket
+}
+
+// startEngineVMs starts the stopped VMs of --engine, the returned function stops the VMs which were started
+func startEngineVMs(ctx context.Context, input *Input) (func(), error) {
+	routes, err := runner.ParseEngineRoutes(input.engines)
+	if err != nil {
+		return nil, err
+	}
+	var stops []func()
+	stopAll := func() {
+		for _, stop := range stops {
+			stop()
+		}
+	}
+	started := map[string]bool{}
+	for _, route := range routes {
+		if route.VM == "" || started[route.VM] {
+			continue
+		}
+		started[route.VM] = true
+		vm, err := parseDockerVM(route.VM)
+		if err != nil {
+			stopAll()
+			return nil, err
+		}
+		stop, err := startDockerVM(ctx, vm)
+		if err != nil {
+			stopAll()
+			return nil, err
+		}
+		stops = append(stops, stop)
+	}
+	return stopAll, nil
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDockerVM(t *testing.T) {
+	tables := []struct {
+		value    string
+		kind     string
+		instance string
+		err      string
+	}{
+		{"colima", "colima", "default", ""},
+		{"colima:work", "colima", "work", ""},
+		{"lima", "lima", "docker", ""},
+		{"lima:podman", "lima", "podman", ""},
+		{"docker-desktop", "", "", "unsupported VM 'docker-desktop'"},
+		{"", "", "", "unsupported VM ''"},
+	}
+	for _, table := range tables {
+		vm, err := parseDockerVM(table.value)
+		if table.err != "" {
+			assert.ErrorContains(t, err, table.err, table.value)
+			continue
+		}
+		assert.NoError(t, err, table.value)
+		assert.Equal(t, &dockerVM{kind: table.kind, instance: table.instance}, vm, table.value)
+	}
+}
+
+func TestDockerVMCommand(t *testing.T) {
+	colima := &dockerVM{kind: "colima", instance: "work"}
+	assert.Equal(t, []string{"colima", "start", "--profile", "work"}, colima.command(context.Background(), "start").Args)
+	assert.Equal(t, []string{"colima", "status", "--profile", "work"}, colima.command(context.Background(), "status").Args)
+
+	lima := &dockerVM{kind: "lima", instance: "docker"}
+	assert.Equal(t, []string{"limactl", "stop", "docker"}, lima.command(context.Background(), "stop").Args)
+	assert.Equal(t, []string{"limactl", "list", "--format", "{{.Status}}", "docker"}, lima.command(context.Background(), "status").Args)
+}
+
+func TestDockerVMDockerHost(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	// homedir caches the home directory of the first call
+	homedir.DisableCache = true
+	defer func() { homedir.DisableCache = false }()
+
+	colima := &dockerVM{kind: "colima", instance: "default"}
+	lima := &dockerVM{kind: "lima", instance: "docker"}
+	assert.Equal(t, filepath.Join(home, ".colima", "default", "docker.sock"), colima.socket())
+	assert.Equal(t, filepath.Join(home, ".lima", "docker", "sock", "docker.sock"), lima.socket())
+
+	assert.Equal(t, "", colima.dockerHost(), "the VM has no socket")
+
+	assert.NoError(t, os.MkdirAll(filepath.Dir(colima.socket()), 0o755))
+	assert.NoError(t, os.WriteFile(colima.socket(), nil, 0o600))
+	assert.Equal(t, "unix://"+colima.socket(), colima.dockerHost())
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/nektos/act/pkg/common/git"
+)
+
+// currentBranch returns the checked-out branch of the working directory, the tag if a tag is checked out and empty
+// if the branch can't be found, like without a repository or with a detached HEAD
+func currentBranch(ctx context.Context, workdir string) string {
+	branch, err := git.FindGitBranch(ctx, workdir)
+	if err == nil {
+		return branch
+	} else if !errors.Is(err, git.ErrDetachedHead) {
+		log.Debugf("Unable to find the checked-out branch: %v", err)
+		return ""
+	}
+	ref, err := git.FindGitRef(ctx, workdir)
+	if err != nil || !strings.HasPrefix(ref, "refs/tags/") {
+		log.Debugf("Unable to find the checked-out branch: HEAD is detached")
+		return ""
+	}
+	return strings.TrimPrefix(ref, "refs/tags/")
+}
+
+// watchBranchScope returns the branch isolating the state of the runs of --watch, the containers kept by --reuse
+// and the caches of the cache server, so switching branches doesn't reuse the state of another branch. The runs
+// of the default branch share the state of the runs without --watch.
+func watchBranchScope(ctx context.Context, input *Input, defaultBranch string) string {
+	branch := currentBranch(ctx, input.Workdir())
+	if input.watchBranch != "" && branch != input.watchBranch {
+		log.Infof("Switched from the branch '%s' to '%s', the run doesn't reuse the containers and the caches of '%s'", input.watchBranch, branch, input.watchBranch)
+	}
+	input.watchBranch = branch
+
+	if defaultBranch == "" {
+		defaultBranch = "master"
+	}
+	if branch == defaultBranch {
+		return ""
+	}
+	return branch
+}
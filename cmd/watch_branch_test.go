@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"context"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchBranchScope(t *testing.T) {
+	dir := t.TempDir()
+	git := func(args ...string) {
+		out, err := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=Mona", "-c", "user.email=mona@example.com"}, args...)...).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	git("init", "--initial-branch=main")
+	git("commit", "--allow-empty", "-m", "first")
+	input := &Input{workdir: dir}
+
+	// the default branch shares the state of the runs without --watch
+	assert.Equal(t, "", watchBranchScope(context.Background(), input, "main"))
+	assert.Equal(t, "main", input.watchBranch)
+
+	git("checkout", "-b", "feature/login")
+	assert.Equal(t, "feature/login", watchBranchScope(context.Background(), input, "main"))
+	assert.Equal(t, "feature/login", input.watchBranch)
+
+	// without --defaultbranch the default branch is master
+	assert.Equal(t, "feature/login", watchBranchScope(context.Background(), input, ""))
+
+	// a checked-out tag is the scope, a detached HEAD is not
+	git("tag", "v1")
+	git("checkout", "--detach", "v1")
+	assert.Equal(t, "v1", watchBranchScope(context.Background(), input, "main"))
+	git("commit", "--allow-empty", "-m", "second")
+	assert.Equal(t, "", watchBranchScope(context.Background(), input, "main"))
+
+	assert.Equal(t, "", watchBranchScope(context.Background(), &Input{workdir: t.TempDir()}, "main"))
+}
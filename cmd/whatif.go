@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/nektos/act/pkg/runner"
+)
+
+func newWhatifCommand(ctx context.Context, input *Input) *cobra.Command {
+	whatifCmd := &cobra.Command{
+		Use:   "whatif [event name]",
+		Short: "Report, without running them, the jobs and steps whose if: evaluates differently for an event payload than for another payload or the default one",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			eventPath, err := cmd.Flags().GetString("event-json")
+			if err != nil {
+				return err
+			}
+			comparePath, err := cmd.Flags().GetString("compare")
+			if err != nil {
+				return err
+			}
+			all, err := cmd.Flags().GetBool("all")
+			if err != nil {
+				return err
+			}
+			eventName := "push"
+			if len(args) > 0 {
+				eventName = args[0]
+			}
+
+			planner, err := newWorkflowPlanner(input)
+			if err != nil {
+				return err
+			}
+			plan := planner.PlanEvent(eventName)
+
+			evaluate := func(path string) ([]runner.Condition, error) {
+				config, err := newWhatifConfig(input, eventName, input.resolve(path))
+				if err != nil {
+					return nil, err
+				}
+				return runner.EvaluateConditions(ctx, config, plan)
+			}
+			conditions, err := evaluate(eventPath)
+			if err != nil {
+				return err
+			}
+			compared, err := evaluate(comparePath)
+			if err != nil {
+				return err
+			}
+
+			compareName := "default"
+			if comparePath != "" {
+				compareName = filepath.Base(comparePath)
+			}
+			return printWhatif(os.Stdout, filepath.Base(eventPath), compareName, conditions, compared, all)
+		},
+	}
+	whatifCmd.Flags().String("event-json", "", "path to the event JSON file whose conditions are reported")
+	whatifCmd.Flags().String("compare", "", "path to the event JSON file to compare with, the default event if it isn't set")
+	whatifCmd.Flags().Bool("all", false, "report all the conditions, not only the ones which differ")
+	whatifCmd.Flags().StringArrayVarP(&input.inputs, "input", "", []string{}, "action input to make available to actions (e.g. --input myinput=foo)")
+	whatifCmd.Flags().StringArrayVar(&input.matrix, "matrix", []string{}, "report only the legs of the matrix jobs with the value of a matrix key, <key>:<value> (e.g. --matrix os:ubuntu-latest)")
+	_ = whatifCmd.MarkFlagRequired("event-json")
+	return whatifCmd
+}
+
+func newWhatifConfig(input *Input, eventName string, eventPath string) (*runner.Config, error) {
+	envs := make(map[string]string)
+	_ = parseEnvs(input.envs, envs)
+	_ = readEnvs(input.Envfile(), envs)
+
+	inputs := make(map[string]string)
+	_ = parseEnvs(input.inputs, inputs)
+	_ = readEnvs(input.Inputfile(), inputs)
+
+	secrets := newSecrets(nil, nil)
+	_ = readEnvs(input.Secretfile(), secrets)
+
+	matrix, err := input.Matrix()
+	if err != nil {
+		return nil, err
+	}
+	contextOverrides, err := input.ContextOverrides()
+	if err != nil {
+		return nil, err
+	}
+
+	return &runner.Config{
+		Actor:            input.actor,
+		EventName:        eventName,
+		EventPath:        eventPath,
+		Workdir:          input.Workdir(),
+		Env:              envs,
+		Secrets:          secrets,
+		Inputs:           inputs,
+		Token:            secrets["GITHUB_TOKEN"],
+		GitHubInstance:   input.githubInstance,
+		GitHubServerURL:  input.githubServerURL,
+		GitHubAPIURL:     input.githubAPIURL,
+		GitHubGraphQLURL: input.githubGraphQLURL,
+		RemoteName:       input.remoteName,
+		ContextOverrides: contextOverrides,
+		Matrix:           matrix,
+	}, nil
+}
+
+// printWhatif prints the conditions evaluated for the payload next to the ones evaluated for the compared payload,
+// the conditions which differ are marked with a *. A job or step evaluated for only one of the payloads, e.g. the
+// steps of a job which only runs for one of them, is reported as not evaluated (-) for the other.
+func printWhatif(out io.Writer, name string, compareName string, conditions []runner.Condition, compared []runner.Condition, all bool) error {
+	type key struct{ workflow, job, step string }
+	keys := make([]key, 0, len(conditions))
+	byPayload := [2]map[key]runner.Condition{{}, {}}
+	for i, list := range [][]runner.Condition{conditions, compared} {
+		for _, c := range list {
+			k := key{c.Workflow, c.Job, c.Step}
+			if _, ok := byPayload[0][k]; !ok {
+				if _, ok := byPayload[1][k]; !ok {
+					keys = append(keys, k)
+				}
+			}
+			byPayload[i][k] = c
+			if c.Err != nil {
+				log.Warnf("%s %s: %v", c.Job, c.Step, c.Err)
+			}
+		}
+	}
+
+	outcome := func(c runner.Condition, ok bool) string {
+		switch {
+		case !ok:
+			return "-"
+		case c.Err != nil:
+			return "error"
+		case c.Runs:
+			return "run"
+		}
+		return "skip"
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "  JOB\tSTEP\tIF\t%s\t%s\n", name, compareName)
+	differ := 0
+	for _, k := range keys {
+		c, ok := byPayload[0][k]
+		other, otherOk := byPayload[1][k]
+		marker := " "
+		if outcome(c, ok) != outcome(other, otherOk) {
+			marker = "*"
+			differ++
+		} else if !all {
+			continue
+		}
+		condition := c.If
+		if !ok {
+			condition = other.If
+		}
+		if condition == "" {
+			condition = "success()"
+		}
+		fmt.Fprintf(w, "%s %s\t%s\t%s\t%s\t%s\n", marker, k.job, k.step, condition, outcome(c, ok), outcome(other, otherOk))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(out, "%d of the %d conditions differ\n", differ, len(keys))
+	return err
+}
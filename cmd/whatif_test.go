@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nektos/act/pkg/runner"
+)
+
+func TestPrintWhatif(t *testing.T) {
+	conditions := []runner.Condition{
+		{Job: "CI/build", If: "success()", Runs: true},
+		{Job: "CI/build", Step: "make docs", If: "github.event.label.name == 'docs'", Runs: true},
+		{Job: "CI/deploy", If: "github.event.pull_request.merged", Runs: true},
+		{Job: "CI/deploy", Step: "make deploy", Runs: true},
+	}
+	compared := []runner.Condition{
+		{Job: "CI/build", If: "success()", Runs: true},
+		{Job: "CI/build", Step: "make docs", If: "github.event.label.name == 'docs'", Err: errors.New("invalid")},
+		{Job: "CI/deploy", If: "github.event.pull_request.merged", Runs: false},
+	}
+
+	out := &bytes.Buffer{}
+	assert.NoError(t, printWhatif(out, "merged.json", "default", conditions, compared, false))
+	assert.Equal(t, `  JOB        STEP         IF                                 merged.json  default
+* CI/build   make docs    github.event.label.name == 'docs'  run          error
+* CI/deploy               github.event.pull_request.merged   run          skip
+* CI/deploy  make deploy  success()                          run          -
+3 of the 4 conditions differ
+`, out.String())
+
+	out.Reset()
+	assert.NoError(t, printWhatif(out, "merged.json", "default", conditions, conditions, true))
+	assert.Equal(t, `  JOB        STEP         IF                                 merged.json  default
+  CI/build                success()                          run          run
+  CI/build   make docs    github.event.label.name == 'docs'  run          run
+  CI/deploy               github.event.pull_request.merged   run          run
+  CI/deploy  make deploy  success()                          run          run
+0 of the 4 conditions differ
+`, out.String())
+}
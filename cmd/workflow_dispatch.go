@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/AlecAivazis/survey/v2"
+	"golang.org/x/term"
+
+	"github.com/nektos/act/pkg/model"
+)
+
+// dispatchInput is an input of workflow_dispatch declared by a workflow of the plan
+type dispatchInput struct {
+	name     string
+	workflow string
+	input    model.WorkflowDispatchInput
+}
+
+// missingDispatchInputs returns the required inputs without a default the workflows of the plan declare for
+// workflow_dispatch and the inputs don't set, by workflow file and name. An input declared by several workflows is
+// returned once.
+func missingDispatchInputs(plan *model.Plan, inputs map[string]string) []dispatchInput {
+	missing := make([]dispatchInput, 0)
+	seen := map[string]bool{}
+	workflows := map[*model.Workflow]bool{}
+	for _, stage := range plan.Stages {
+		for _, run := range stage.Runs {
+			if workflows[run.Workflow] {
+				continue
+			}
+			workflows[run.Workflow] = true
+			config := run.Workflow.WorkflowDispatchConfig()
+			if config == nil {
+				continue
+			}
+			names := make([]string, 0, len(config.Inputs))
+			for name := range config.Inputs {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				input := config.Inputs[name]
+				if !input.Required || input.Default != "" || inputs[name] != "" || seen[name] {
+					continue
+				}
+				seen[name] = true
+				missing = append(missing, dispatchInput{name: name, workflow: run.Workflow.File, input: input})
+			}
+		}
+	}
+	sort.SliceStable(missing, func(i, j int) bool {
+		return missing[i].workflow < missing[j].workflow
+	})
+	return missing
+}
+
+// promptDispatchInputs asks for the required inputs of workflow_dispatch which --input doesn't set when act runs in a
+// terminal, like the form of GitHub: a confirmation for the booleans, a selection for the choices. Without a
+// terminal the run fails with the missing inputs.
+func promptDispatchInputs(plan *model.Plan, inputs map[string]string) error {
+	missing := missingDispatchInputs(plan, inputs)
+	if len(missing) == 0 || !term.IsTerminal(int(os.Stdin.Fd())) {
+		return nil
+	}
+	for _, m := range missing {
+		message := fmt.Sprintf("Input '%s' of %s:", m.name, m.workflow)
+		if m.input.Description != "" {
+			message = fmt.Sprintf("%s (input '%s' of %s):", m.input.Description, m.name, m.workflow)
+		}
+		var answer string
+		var err error
+		switch {
+		case m.input.Type == "boolean":
+			var confirmed bool
+			err = survey.AskOne(&survey.Confirm{Message: message}, &confirmed)
+			answer = fmt.Sprint(confirmed)
+		case m.input.Type == "choice" && len(m.input.Options) > 0:
+			err = survey.AskOne(&survey.Select{Message: message, Options: m.input.Options}, &answer)
+		default:
+			err = survey.AskOne(&survey.Input{Message: message}, &answer, survey.WithValidator(survey.Required))
+		}
+		if err != nil {
+			return fmt.Errorf("unable to read the input '%s': %w", m.name, err)
+		}
+		inputs[m.name] = answer
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nektos/act/pkg/model"
+)
+
+func TestMissingDispatchInputs(t *testing.T) {
+	readWorkflow := func(file string, inputs string) *model.Workflow {
+		workflow, err := model.ReadWorkflow(strings.NewReader("on:\n  workflow_dispatch:\n    inputs:\n" + inputs + "jobs:\n  job:\n    runs-on: ubuntu-latest\n    steps:\n      - run: echo\n"))
+		assert.NoError(t, err)
+		workflow.File = file
+		return workflow
+	}
+	deploy := readWorkflow("deploy.yml", `      version:
+        required: true
+      environment:
+        type: choice
+        required: true
+        options: [staging, production]
+      replicas:
+        required: true
+        default: "2"
+      dry-run:
+        type: boolean
+`)
+	release := readWorkflow("release.yml", `      version:
+        required: true
+      tag:
+        required: true
+`)
+	plan := &model.Plan{Stages: []*model.Stage{{Runs: []*model.Run{
+		{Workflow: release, JobID: "job"},
+		{Workflow: deploy, JobID: "job"},
+	}}}}
+
+	missing := missingDispatchInputs(plan, map[string]string{"tag": "v1"})
+	names := make([]string, 0, len(missing))
+	for _, m := range missing {
+		names = append(names, m.workflow+":"+m.name)
+	}
+	assert.Equal(t, []string{"deploy.yml:environment", "release.yml:version"}, names)
+	assert.Equal(t, []string{"staging", "production"}, missing[0].input.Options)
+
+	assert.Empty(t, missingDispatchInputs(plan, map[string]string{"tag": "v1", "version": "1", "environment": "staging"}))
+}
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// stdinWorkflowFile is the name of the workflow read from stdin, the default name of the workflow and its file in
+// the github context
+const stdinWorkflowFile = "stdin.yml"
+
+// readWorkflowStdin writes the workflow read from r to a temporary file the run loads as its only workflow, the
+// returned function removes it
+func readWorkflowStdin(r io.Reader, input *Input) (func(), error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the workflow from stdin: %w", err)
+	}
+	if len(content) == 0 {
+		return nil, fmt.Errorf("unable to read the workflow from stdin: stdin is empty")
+	}
+
+	dir, err := os.MkdirTemp("", "act-workflow-stdin")
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, stdinWorkflowFile)
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		_ = os.RemoveAll(dir)
+		return nil, err
+	}
+	input.workflowsPath = path
+	return func() { _ = os.RemoveAll(dir) }, nil
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nektos/act/pkg/model"
+)
+
+func TestReadWorkflowStdin(t *testing.T) {
+	input := &Input{workdir: t.TempDir(), workflowsPath: "./.github/workflows/"}
+	removeWorkflow, err := readWorkflowStdin(strings.NewReader(`
+on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo stdin
+`), input)
+	assert.NoError(t, err)
+
+	path := input.WorkflowsPath()
+	assert.Equal(t, stdinWorkflowFile, filepath.Base(path))
+	planner, err := model.NewWorkflowPlanner(path, input.noWorkflowRecurse)
+	assert.NoError(t, err)
+	plan := planner.PlanEvent("push")
+	if assert.Len(t, plan.Stages, 1) {
+		assert.Equal(t, "test", plan.Stages[0].Runs[0].JobID)
+	}
+
+	removeWorkflow()
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+
+	_, err = readWorkflowStdin(strings.NewReader(""), input)
+	assert.EqualError(t, err, "unable to read the workflow from stdin: stdin is empty")
+}
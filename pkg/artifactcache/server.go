@@ -0,0 +1,441 @@
+package artifactcache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/nektos/act/pkg/common"
+	"github.com/sirupsen/logrus"
+)
+
+// urlBase is the prefix of the cache API appended to ACTIONS_CACHE_URL by actions/cache
+const urlBase = "/_apis/artifactcache"
+
+// scopePrefix prefixes the cache URL of the jobs of a branch, their caches aren't restored by the jobs of the other
+// branches
+const scopePrefix = "/_scopes/"
+
+// indexFile keeps the committed entries in the cache path, the entries of uploads which weren't committed are lost
+// when the server stops
+const indexFile = "index.json"
+
+// Entry is a cache entry saved by actions/cache, its archive is stored in the cache path under its id
+type Entry struct {
+	ID       int64     `json:"id"`
+	Key      string    `json:"key"`
+	Version  string    `json:"version"`
+	Size     int64     `json:"size"`
+	Created  time.Time `json:"created"`
+	Scope    string    `json:"scope,omitempty"` // the branch which saved the entry, empty if any branch restores it
+	Complete bool      `json:"-"`
+}
+
+type ArtifactCacheEntry struct {
+	CacheKey        string `json:"cacheKey"`
+	CreationTime    string `json:"creationTime"`
+	ArchiveLocation string `json:"archiveLocation"`
+}
+
+type ReserveCacheRequest struct {
+	Key       string `json:"key"`
+	Version   string `json:"version"`
+	CacheSize int64  `json:"cacheSize"`
+}
+
+type ReserveCacheResponse struct {
+	CacheID int64 `json:"cacheId"`
+}
+
+type CommitCacheRequest struct {
+	Size int64 `json:"size"`
+}
+
+type ResponseMessage struct {
+	Message string `json:"message"`
+}
+
+// storage is the index of the entries of the cache path
+type storage struct {
+	dir     string
+	mu      sync.Mutex
+	entries []*Entry
+	nextID  int64
+}
+
+func openStorage(dir string) (*storage, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	s := &storage{dir: dir, nextID: 1}
+	content, err := os.ReadFile(filepath.Join(dir, indexFile))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	} else if err == nil {
+		if err := json.Unmarshal(content, &s.entries); err != nil {
+			return nil, fmt.Errorf("invalid cache index %s: %w", filepath.Join(dir, indexFile), err)
+		}
+	}
+	for _, entry := range s.entries {
+		entry.Complete = true
+		if entry.ID >= s.nextID {
+			s.nextID = entry.ID + 1
+		}
+	}
+	return s, nil
+}
+
+// archive returns the path of the archive of the entry
+func (s *storage) archive(id int64) string {
+	return filepath.Join(s.dir, strconv.FormatInt(id, 10))
+}
+
+// save writes the index of the committed entries, s.mu must be held
+func (s *storage) save() error {
+	entries := make([]*Entry, 0, len(s.entries))
+	for _, entry := range s.entries {
+		if entry.Complete {
+			entries = append(entries, entry)
+		}
+	}
+	content, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := filepath.Join(s.dir, indexFile+".tmp")
+	if err := os.WriteFile(tmp, content, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(s.dir, indexFile))
+}
+
+// find returns the committed entry matching the keys like on GitHub: the entry of the first key, the primary key,
+// otherwise the newest entry whose key starts with one of the keys, tried in order. The entries of the scope are
+// tried before the unscoped ones, like the caches of a branch before the ones of the default branch.
+func (s *storage) find(keys []string, version string, scope string) *Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	candidates := make([]*Entry, 0, len(s.entries))
+	for _, entry := range s.entries {
+		if entry.Complete && entry.Version == version && (entry.Scope == scope || entry.Scope == "") {
+			candidates = append(candidates, entry)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Scope != candidates[j].Scope {
+			return candidates[i].Scope == scope
+		}
+		return candidates[i].Created.After(candidates[j].Created)
+	})
+
+	for _, entry := range candidates {
+		if entry.Key == keys[0] {
+			return entry
+		}
+	}
+	for _, key := range keys {
+		for _, entry := range candidates {
+			if strings.HasPrefix(entry.Key, key) {
+				return entry
+			}
+		}
+	}
+	return nil
+}
+
+// reserve adds an entry of the scope whose archive is being uploaded, nil if the key already has an entry in the scope
+func (s *storage) reserve(key string, version string, scope string) *Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, entry := range s.entries {
+		if entry.Key == key && entry.Version == version && entry.Scope == scope {
+			return nil
+		}
+	}
+	entry := &Entry{ID: s.nextID, Key: key, Version: version, Scope: scope}
+	s.nextID++
+	s.entries = append(s.entries, entry)
+	return entry
+}
+
+// get returns the entry of the id if it is committed, or if it isn't with complete false
+func (s *storage) get(id int64, complete bool) *Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, entry := range s.entries {
+		if entry.ID == id && entry.Complete == complete {
+			return entry
+		}
+	}
+	return nil
+}
+
+// commit marks the entry as complete once its archive has the size of the upload
+func (s *storage) commit(entry *Entry, size int64) error {
+	info, err := os.Stat(s.archive(entry.ID))
+	if err != nil {
+		return err
+	}
+	if info.Size() != size {
+		return fmt.Errorf("the archive of cache %d has %d bytes, expected %d", entry.ID, info.Size(), size)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if entry.Complete {
+		return fmt.Errorf("cache %d is already committed", entry.ID)
+	}
+	entry.Size = size
+	entry.Created = time.Now()
+	entry.Complete = true
+	return s.save()
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+func writeError(w http.ResponseWriter, status int, format string, args ...interface{}) {
+	writeJSON(w, status, ResponseMessage{Message: fmt.Sprintf(format, args...)})
+}
+
+// parseUploadRange returns the offset of a chunk of an archive, its Content-Range is "bytes <start>-<end>/*"
+func parseUploadRange(header string) (int64, error) {
+	var start, end int64
+	if _, err := fmt.Sscanf(header, "bytes %d-%d/", &start, &end); err != nil || start > end {
+		return 0, fmt.Errorf("invalid Content-Range '%s'", header)
+	}
+	return start, nil
+}
+
+func routes(router *httprouter.Router, s *storage, logger logrus.FieldLogger) {
+	router.GET(urlBase+"/cache", func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+		keys := strings.Split(req.URL.Query().Get("keys"), ",")
+		version := req.URL.Query().Get("version")
+		if keys[0] == "" {
+			writeError(w, http.StatusBadRequest, "no keys given")
+			return
+		}
+
+		entry := s.find(keys, version, requestScope(req))
+		if entry == nil {
+			logger.Debugf("Cache miss for the keys %s", strings.Join(keys, ", "))
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		logger.Debugf("Cache hit for the keys %s: %s", strings.Join(keys, ", "), entry.Key)
+		writeJSON(w, http.StatusOK, ArtifactCacheEntry{
+			CacheKey:        entry.Key,
+			CreationTime:    entry.Created.Format(time.RFC3339),
+			ArchiveLocation: fmt.Sprintf("http://%s%s/artifacts/%d", req.Host, urlBase, entry.ID),
+		})
+	})
+
+	router.POST(urlBase+"/caches", func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+		var body ReserveCacheRequest
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil || body.Key == "" {
+			writeError(w, http.StatusBadRequest, "invalid request")
+			return
+		}
+
+		entry := s.reserve(body.Key, body.Version, requestScope(req))
+		if entry == nil {
+			writeError(w, http.StatusConflict, "cache already exists for the key '%s'", body.Key)
+			return
+		}
+		// a previous server may have left the archive of an upload which wasn't committed
+		_ = os.Remove(s.archive(entry.ID))
+		writeJSON(w, http.StatusCreated, ReserveCacheResponse{CacheID: entry.ID})
+	})
+
+	router.PATCH(urlBase+"/caches/:id", func(w http.ResponseWriter, req *http.Request, params httprouter.Params) {
+		id, _ := strconv.ParseInt(params.ByName("id"), 10, 64)
+		entry := s.get(id, false)
+		if entry == nil {
+			writeError(w, http.StatusNotFound, "no upload reserved for the cache %s", params.ByName("id"))
+			return
+		}
+		offset, err := parseUploadRange(req.Header.Get("Content-Range"))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "%s", err)
+			return
+		}
+
+		// the chunks are uploaded concurrently, each request writes through its own file
+		file, err := os.OpenFile(s.archive(id), os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "%s", err)
+			return
+		}
+		defer file.Close()
+		if _, err := file.Seek(offset, io.SeekStart); err != nil {
+			writeError(w, http.StatusInternalServerError, "%s", err)
+			return
+		}
+		if _, err := io.Copy(file, req.Body); err != nil {
+			writeError(w, http.StatusInternalServerError, "%s", err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	router.POST(urlBase+"/caches/:id", func(w http.ResponseWriter, req *http.Request, params httprouter.Params) {
+		id, _ := strconv.ParseInt(params.ByName("id"), 10, 64)
+		entry := s.get(id, false)
+		if entry == nil {
+			writeError(w, http.StatusNotFound, "no upload reserved for the cache %s", params.ByName("id"))
+			return
+		}
+		var body CommitCacheRequest
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request")
+			return
+		}
+
+		if err := s.commit(entry, body.Size); err != nil {
+			writeError(w, http.StatusBadRequest, "%s", err)
+			return
+		}
+		logger.Infof("Saved the cache '%s' (%d bytes)", entry.Key, entry.Size)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	router.GET(urlBase+"/artifacts/:id", func(w http.ResponseWriter, req *http.Request, params httprouter.Params) {
+		id, _ := strconv.ParseInt(params.ByName("id"), 10, 64)
+		entry := s.get(id, true)
+		if entry == nil {
+			writeError(w, http.StatusNotFound, "no cache %s", params.ByName("id"))
+			return
+		}
+		file, err := os.Open(s.archive(id))
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "%s", err)
+			return
+		}
+		defer file.Close()
+		http.ServeContent(w, req, "", entry.Created, file)
+	})
+}
+
+type scopeKey struct{}
+
+// requestScope returns the scope of the cache URL of the request, empty if it isn't scoped
+func requestScope(req *http.Request) string {
+	scope, _ := req.Context().Value(scopeKey{}).(string)
+	return scope
+}
+
+// ScopedURL returns the cache URL (ACTIONS_CACHE_URL) of the jobs of a scope, like a branch
+func ScopedURL(cacheURL string, scope string) string {
+	if scope == "" {
+		return cacheURL
+	}
+	return strings.TrimSuffix(cacheURL, "/") + scopePrefix + url.PathEscape(scope) + "/"
+}
+
+// scoper routes the requests of a scoped cache URL to the cache API with the scope in their context
+type scoper struct {
+	handler http.Handler
+}
+
+func (sc scoper) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if rest := strings.TrimPrefix(req.URL.EscapedPath(), scopePrefix); rest != req.URL.EscapedPath() {
+		parts := strings.SplitN(rest, "/", 2)
+		scope, err := url.PathUnescape(parts[0])
+		if len(parts) < 2 || scope == "" || err != nil {
+			http.NotFound(w, req)
+			return
+		}
+		req = req.WithContext(context.WithValue(req.Context(), scopeKey{}, scope))
+		req.URL.Path = "/" + parts[1]
+		req.URL.RawPath = ""
+	}
+	sc.handler.ServeHTTP(w, req)
+}
+
+// authorizer checks the runtime token of the requests, any job may restore the caches saved by the jobs of other
+// runs like the jobs of the branches of a repository on GitHub. The archives are downloaded without a token.
+type authorizer struct {
+	tokens  *common.RuntimeTokens
+	handler http.Handler
+}
+
+func (a *authorizer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if !strings.HasPrefix(req.URL.Path, urlBase+"/artifacts/") {
+		if _, err := a.tokens.Validate(strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")); err != nil {
+			writeError(w, http.StatusUnauthorized, "%s", err)
+			return
+		}
+	}
+	a.handler.ServeHTTP(w, req)
+}
+
+// Serve starts the cache server of actions/cache storing the entries in cachePath, the requests have to carry a
+// runtime token issued by tokens unless it is nil
+func Serve(ctx context.Context, cachePath string, addr string, port string, tokens *common.RuntimeTokens) context.CancelFunc {
+	serverContext, cancel := context.WithCancel(ctx)
+	logger := common.Logger(serverContext)
+
+	if cachePath == "" {
+		return cancel
+	}
+
+	s, err := openStorage(cachePath)
+	if err != nil {
+		logger.Fatal(err)
+	}
+	logger.Debugf("Cache server path '%s'", cachePath)
+
+	router := httprouter.New()
+	routes(router, s, logger)
+
+	var handler http.Handler = router
+	if tokens != nil {
+		handler = &authorizer{tokens: tokens, handler: router}
+	}
+	handler = scoper{handler: handler}
+
+	server := &http.Server{
+		Addr:              net.JoinHostPort(addr, port),
+		ReadHeaderTimeout: 2 * time.Second,
+		Handler:           handler,
+	}
+
+	// run server
+	go func() {
+		logger.Infof("Start cache server on http://%s", server.Addr)
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logger.Fatal(err)
+		}
+	}()
+
+	// wait for cancel to gracefully shutdown server
+	go func() {
+		<-serverContext.Done()
+
+		if err := server.Shutdown(ctx); err != nil {
+			logger.Errorf("Failed shutdown gracefully - force shutdown: %v", err)
+			server.Close()
+		}
+	}()
+
+	return cancel
+}
@@ -0,0 +1,185 @@
+package artifactcache
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nektos/act/pkg/common"
+)
+
+func newTestServer(t *testing.T, dir string) *httptest.Server {
+	s, err := openStorage(dir)
+	assert.NoError(t, err)
+	router := httprouter.New()
+	routes(router, s, log.StandardLogger())
+	server := httptest.NewServer(scoper{handler: router})
+	t.Cleanup(server.Close)
+	return server
+}
+
+func request(t *testing.T, method string, url string, body []byte, header map[string]string) *http.Response {
+	req, err := http.NewRequest(method, url, bytes.NewReader(body))
+	assert.NoError(t, err)
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func saveCache(t *testing.T, url string, key string, content string) {
+	body, _ := json.Marshal(ReserveCacheRequest{Key: key, Version: "v1", CacheSize: int64(len(content))})
+	resp := request(t, http.MethodPost, url+urlBase+"/caches", body, nil)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	var reserved ReserveCacheResponse
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&reserved))
+
+	// the chunks may arrive in any order
+	half := len(content) / 2
+	resp = request(t, http.MethodPatch, fmt.Sprintf("%s%s/caches/%d", url, urlBase, reserved.CacheID), []byte(content[half:]), map[string]string{
+		"Content-Range": fmt.Sprintf("bytes %d-%d/*", half, len(content)-1),
+	})
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	resp = request(t, http.MethodPatch, fmt.Sprintf("%s%s/caches/%d", url, urlBase, reserved.CacheID), []byte(content[:half]), map[string]string{
+		"Content-Range": fmt.Sprintf("bytes 0-%d/*", half-1),
+	})
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	body, _ = json.Marshal(CommitCacheRequest{Size: int64(len(content))})
+	resp = request(t, http.MethodPost, fmt.Sprintf("%s%s/caches/%d", url, urlBase, reserved.CacheID), body, nil)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+}
+
+func restoreCache(t *testing.T, url string, keys string) (string, string) {
+	resp := request(t, http.MethodGet, url+urlBase+"/cache?version=v1&keys="+keys, nil, nil)
+	if resp.StatusCode == http.StatusNoContent {
+		return "", ""
+	}
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var entry ArtifactCacheEntry
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&entry))
+
+	resp = request(t, http.MethodGet, entry.ArchiveLocation, nil, nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	content, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	return entry.CacheKey, string(content)
+}
+
+func TestCacheServer(t *testing.T) {
+	dir := t.TempDir()
+	server := newTestServer(t, dir)
+
+	saveCache(t, server.URL, "go-linux-abc", "archive of abc")
+	saveCache(t, server.URL, "go-linux-def", "archive of def")
+
+	key, content := restoreCache(t, server.URL, "go-linux-abc,go-linux-")
+	assert.Equal(t, "go-linux-abc", key)
+	assert.Equal(t, "archive of abc", content)
+
+	// the newest entry matching a restore key
+	key, content = restoreCache(t, server.URL, "go-linux-123,go-linux-")
+	assert.Equal(t, "go-linux-def", key)
+	assert.Equal(t, "archive of def", content)
+
+	key, _ = restoreCache(t, server.URL, "go-windows-123,go-windows-")
+	assert.Equal(t, "", key)
+
+	// a version is a different entry
+	resp := request(t, http.MethodGet, server.URL+urlBase+"/cache?version=v2&keys=go-linux-abc", nil, nil)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	body, _ := json.Marshal(ReserveCacheRequest{Key: "go-linux-abc", Version: "v1"})
+	resp = request(t, http.MethodPost, server.URL+urlBase+"/caches", body, nil)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+	// the committed entries are kept by the next server
+	server = newTestServer(t, dir)
+	key, content = restoreCache(t, server.URL, "go-linux-abc")
+	assert.Equal(t, "go-linux-abc", key)
+	assert.Equal(t, "archive of abc", content)
+}
+
+func TestCacheServerScope(t *testing.T) {
+	server := newTestServer(t, t.TempDir())
+	feature := strings.TrimSuffix(ScopedURL(server.URL+"/", "feature/login"), "/")
+	other := strings.TrimSuffix(ScopedURL(server.URL+"/", "fix"), "/")
+	assert.Equal(t, server.URL+"/_scopes/feature%2Flogin/", ScopedURL(server.URL+"/", "feature/login"))
+	assert.Equal(t, server.URL+"/", ScopedURL(server.URL+"/", ""))
+
+	saveCache(t, server.URL, "deps-abc", "archive of the default branch")
+	saveCache(t, feature, "deps-abc", "archive of feature/login")
+	saveCache(t, feature, "deps-def", "archive of def")
+
+	// the branch restores its own entries first, then the unscoped ones
+	key, content := restoreCache(t, feature, "deps-abc")
+	assert.Equal(t, "deps-abc", key)
+	assert.Equal(t, "archive of feature/login", content)
+	key, content = restoreCache(t, other, "deps-abc")
+	assert.Equal(t, "deps-abc", key)
+	assert.Equal(t, "archive of the default branch", content)
+
+	// the entries of a branch are not restored by the other branches
+	key, _ = restoreCache(t, other, "deps-def,deps-d")
+	assert.Equal(t, "", key)
+	key, _ = restoreCache(t, server.URL, "deps-def")
+	assert.Equal(t, "", key)
+	key, content = restoreCache(t, feature, "deps-xyz,deps-")
+	assert.Equal(t, "deps-def", key)
+	assert.Equal(t, "archive of def", content)
+}
+
+func TestCacheServerCommitSize(t *testing.T) {
+	server := newTestServer(t, t.TempDir())
+
+	body, _ := json.Marshal(ReserveCacheRequest{Key: "key", Version: "v1"})
+	resp := request(t, http.MethodPost, server.URL+urlBase+"/caches", body, nil)
+	var reserved ReserveCacheResponse
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&reserved))
+
+	resp = request(t, http.MethodPatch, fmt.Sprintf("%s%s/caches/%d", server.URL, urlBase, reserved.CacheID), []byte("abc"), map[string]string{
+		"Content-Range": "bytes 0-2/*",
+	})
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	body, _ = json.Marshal(CommitCacheRequest{Size: 10})
+	resp = request(t, http.MethodPost, fmt.Sprintf("%s%s/caches/%d", server.URL, urlBase, reserved.CacheID), body, nil)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	key, _ := restoreCache(t, server.URL, "key")
+	assert.Equal(t, "", key)
+}
+
+func TestCacheServerAuthorizer(t *testing.T) {
+	tokens, err := common.NewRuntimeTokens()
+	assert.NoError(t, err)
+	s, err := openStorage(t.TempDir())
+	assert.NoError(t, err)
+	router := httprouter.New()
+	routes(router, s, log.StandardLogger())
+	server := httptest.NewServer(&authorizer{tokens: tokens, handler: router})
+	defer server.Close()
+
+	resp := request(t, http.MethodGet, server.URL+urlBase+"/cache?version=v1&keys=key", nil, nil)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	token := tokens.Issue(common.RuntimeTokenClaims{RunID: "1", Job: "build"})
+	resp = request(t, http.MethodGet, server.URL+urlBase+"/cache?version=v1&keys=key", nil, map[string]string{"Authorization": "Bearer " + token})
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	// the archives are downloaded without the token
+	resp = request(t, http.MethodGet, server.URL+urlBase+"/artifacts/1", nil, nil)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
@@ -0,0 +1,49 @@
+package artifacts
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/nektos/act/pkg/common"
+)
+
+// authorizer checks the runtime token of the requests, a job only reaches the artifacts of its run.
+// The jobs of a run may append to the same artifact, as the matrix legs of a job do on GitHub.
+type authorizer struct {
+	tokens  *common.RuntimeTokens
+	handler http.Handler
+}
+
+func newAuthorizer(tokens *common.RuntimeTokens, handler http.Handler) *authorizer {
+	return &authorizer{
+		tokens:  tokens,
+		handler: handler,
+	}
+}
+
+func (a *authorizer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	claims, err := a.tokens.Validate(strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer "))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	runID := requestRunID(req.URL.Path)
+	if runID != "" && runID != claims.RunID {
+		http.Error(w, fmt.Sprintf("the runtime token of job '%s' is not valid for run %s", claims.Job, runID), http.StatusForbidden)
+		return
+	}
+
+	a.handler.ServeHTTP(w, req)
+}
+
+// requestRunID returns the run the request refers to
+func requestRunID(path string) string {
+	for _, prefix := range []string{"/_apis/pipelines/workflows/", "/upload/", "/download/", "/artifact/"} {
+		if strings.HasPrefix(path, prefix) {
+			return strings.SplitN(strings.TrimPrefix(path, prefix), "/", 2)[0]
+		}
+	}
+	return ""
+}
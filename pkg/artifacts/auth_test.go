@@ -0,0 +1,53 @@
+package artifacts
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthorizer(t *testing.T) {
+	tokens, err := common.NewRuntimeTokens()
+	assert.NoError(t, err)
+	build := tokens.Issue(common.RuntimeTokenClaims{RunID: "1", Job: "build"})
+	test := tokens.Issue(common.RuntimeTokenClaims{RunID: "1", Job: "test"})
+	otherRun := tokens.Issue(common.RuntimeTokenClaims{RunID: "2", Job: "build"})
+
+	handler := newAuthorizer(tokens, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tables := []struct {
+		method string
+		url    string
+		token  string
+		status int
+	}{
+		{"GET", "/_apis/pipelines/workflows/1/artifacts", "", http.StatusUnauthorized},
+		{"GET", "/_apis/pipelines/workflows/1/artifacts", "token", http.StatusUnauthorized},
+		{"GET", "/_apis/pipelines/workflows/1/artifacts", build, http.StatusOK},
+		{"GET", "/_apis/pipelines/workflows/1/artifacts", otherRun, http.StatusForbidden},
+		{"PUT", "/upload/1?itemPath=dist/app.tar", build, http.StatusOK},
+		{"PUT", "/upload/1?itemPath=dist/app.sum", build, http.StatusOK},
+		{"PUT", "/upload/1?itemPath=dist/app-test.tar", test, http.StatusOK},
+		{"PUT", "/upload/1?itemPath=report/junit.xml", test, http.StatusOK},
+		{"PUT", "/upload/2?itemPath=dist/app.tar", build, http.StatusForbidden},
+		{"GET", "/download/1?itemPath=dist", test, http.StatusOK},
+		{"GET", "/artifact/1/dist/app.tar", test, http.StatusOK},
+		{"GET", "/artifact/2/dist/app.tar", test, http.StatusForbidden},
+	}
+
+	for _, table := range tables {
+		req := httptest.NewRequest(table.method, table.url, strings.NewReader(""))
+		if table.token != "" {
+			req.Header.Set("Authorization", "Bearer "+table.token)
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, table.status, rr.Code, "%s %s", table.method, table.url)
+	}
+}
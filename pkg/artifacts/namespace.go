@@ -0,0 +1,36 @@
+package artifacts
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/nektos/act/pkg/common"
+)
+
+const workflowsPrefix = "/_apis/pipelines/workflows/"
+
+// namespacer routes the requests of a namespaced runtime URL to the run of the namespace, the URLs returned to
+// the jobs already point to that run
+type namespacer struct {
+	handler http.Handler
+}
+
+func (n namespacer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if rest := strings.TrimPrefix(req.URL.Path, common.ArtifactNamespacePrefix); rest != req.URL.Path {
+		parts := strings.SplitN(rest, "/", 2)
+		if len(parts) < 2 || parts[0] == "" || !strings.HasPrefix("/"+parts[1], workflowsPrefix) {
+			http.NotFound(w, req)
+			return
+		}
+		ids := strings.SplitN(strings.TrimPrefix("/"+parts[1], workflowsPrefix), "/", 2)
+		path := workflowsPrefix + common.NamespacedRunID(ids[0], parts[0])
+		if len(ids) == 2 {
+			path += "/" + ids[1]
+		}
+
+		req = req.Clone(req.Context())
+		req.URL.Path = path
+		req.URL.RawPath = ""
+	}
+	n.handler.ServeHTTP(w, req)
+}
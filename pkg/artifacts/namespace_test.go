@@ -0,0 +1,37 @@
+package artifacts
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nektos/act/pkg/common"
+)
+
+func TestNamespacer(t *testing.T) {
+	router := httprouter.New()
+	uploads(router, t.TempDir(), readWriteFSImpl{})
+	server := httptest.NewServer(namespacer{handler: router})
+	defer server.Close()
+
+	runtimeURL := common.NamespacedRuntimeURL(server.URL+"/", "ci")
+	assert.Equal(t, server.URL+"/namespace/ci/", runtimeURL)
+	assert.Equal(t, server.URL+"/", common.NamespacedRuntimeURL(server.URL+"/", ""))
+
+	res, err := http.Post(runtimeURL+"_apis/pipelines/workflows/1/artifacts", "application/json", nil)
+	assert.NoError(t, err)
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	var body FileContainerResourceURL
+	assert.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+	assert.Equal(t, "http://"+res.Request.URL.Host+"/upload/1-ci", body.FileContainerResourceURL)
+
+	res, err = http.Post(runtimeURL+"upload/1", "application/json", nil)
+	assert.NoError(t, err)
+	res.Body.Close()
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+}
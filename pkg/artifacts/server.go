@@ -1,16 +1,20 @@
 package artifacts
 
 import (
+	"compress/gzip"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"io/fs"
+	"net"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/julienschmidt/httprouter"
@@ -51,7 +55,9 @@ type WritableFile interface {
 
 type WriteFS interface {
 	OpenWritable(name string) (WritableFile, error)
-	OpenAppendable(name string) (WritableFile, error)
+	OpenAt(name string, offset int64) (WritableFile, error)
+	Rename(oldname string, newname string) error
+	Remove(name string) error
 }
 
 type readWriteFSImpl struct {
@@ -68,30 +74,220 @@ func (fwfs readWriteFSImpl) OpenWritable(name string) (WritableFile, error) {
 	return os.OpenFile(name, os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0644)
 }
 
-func (fwfs readWriteFSImpl) OpenAppendable(name string) (WritableFile, error) {
+func (fwfs readWriteFSImpl) OpenAt(name string, offset int64) (WritableFile, error) {
 	if err := os.MkdirAll(filepath.Dir(name), os.ModePerm); err != nil {
 		return nil, err
 	}
-	file, err := os.OpenFile(name, os.O_CREATE|os.O_RDWR, 0644)
-
+	file, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY, 0644)
 	if err != nil {
 		return nil, err
 	}
 
-	_, err = file.Seek(0, os.SEEK_END)
+	_, err = file.Seek(offset, io.SeekStart)
 	if err != nil {
+		file.Close()
 		return nil, err
 	}
 	return file, nil
 }
 
+func (fwfs readWriteFSImpl) Rename(oldname string, newname string) error {
+	return os.Rename(oldname, newname)
+}
+
+func (fwfs readWriteFSImpl) Remove(name string) error {
+	return os.Remove(name)
+}
+
 var gzipExtension = ".gz__"
 
+// partialExtension marks a file whose upload is not complete, it's renamed once the last chunk is written
+var partialExtension = ".upload__"
+
+// maxConcurrentUploads is the number of chunks written at the same time, further uploads are throttled
+const maxConcurrentUploads = 32
+
+// fileLocks serializes the writes to the same file, a lock is dropped once no upload holds or waits for it
+type fileLocks struct {
+	mu    sync.Mutex
+	locks map[string]*fileLock
+}
+
+type fileLock struct {
+	sync.Mutex
+	refs int
+}
+
+func (l *fileLocks) lock(name string) func() {
+	l.mu.Lock()
+	lock, ok := l.locks[name]
+	if !ok {
+		lock = &fileLock{}
+		l.locks[name] = lock
+	}
+	lock.refs++
+	l.mu.Unlock()
+
+	lock.Lock()
+	return func() {
+		lock.Unlock()
+
+		l.mu.Lock()
+		lock.refs--
+		if lock.refs == 0 {
+			delete(l.locks, name)
+		}
+		l.mu.Unlock()
+	}
+}
+
+// contentRange is the chunk of a file sent by an upload request
+type contentRange struct {
+	start int64
+	end   int64
+	total int64 // -1 if the file is sent in a single request
+}
+
+// parseContentRange parses a Content-Range header of an upload, "bytes <start>-<end>/<total>". The total is required,
+// a file whose size is unknown ("*") would never be complete
+func parseContentRange(header string) (*contentRange, error) {
+	r := &contentRange{}
+	var total string
+	if _, err := fmt.Sscanf(header, "bytes %d-%d/%s", &r.start, &r.end, &total); err != nil || r.start < 0 || r.end < r.start {
+		return nil, fmt.Errorf("invalid Content-Range '%s'", header)
+	}
+	n, err := strconv.ParseInt(total, 10, 64)
+	if err != nil || n <= r.end {
+		return nil, fmt.Errorf("invalid Content-Range '%s', expected bytes <start>-<end>/<total>", header)
+	}
+	r.total = n
+	return r, nil
+}
+
 func safeResolve(baseDir string, relPath string) string {
 	return filepath.Join(baseDir, filepath.Clean(filepath.Join(string(os.PathSeparator), relPath)))
 }
 
+// ListArtifacts returns the names of the artifacts uploaded by a run, including the artifacts of previous attempts of the run
+func ListArtifacts(artifactPath string, runID string) ([]string, error) {
+	entries, err := os.ReadDir(safeResolve(artifactPath, runID))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return []string{}, nil
+		}
+		return nil, err
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	return names, nil
+}
+
+// ReadFile returns the content of a file of an uploaded artifact, files uploaded with gzip compression are decompressed
+func ReadFile(artifactPath string, runID string, artifactName string, file string) ([]byte, error) {
+	r, err := openFile(artifactPath, runID, artifactName, file)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+type gzipFile struct {
+	*gzip.Reader
+	file *os.File
+}
+
+func (f *gzipFile) Close() error {
+	f.Reader.Close()
+	return f.file.Close()
+}
+
+// openFile opens a file of an uploaded artifact, files uploaded with gzip compression are decompressed
+func openFile(artifactPath string, runID string, artifactName string, file string) (io.ReadCloser, error) {
+	safePath := safeResolve(safeResolve(artifactPath, runID), filepath.Join(artifactName, file))
+	f, err := os.Open(safePath)
+	if err == nil {
+		return f, nil
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	f, err = os.Open(safePath + gzipExtension)
+	if err != nil {
+		return nil, err
+	}
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &gzipFile{Reader: gz, file: f}, nil
+}
+
+// Extract copies the artifacts of a run into dir/<artifact name>, only the named artifacts unless names is empty.
+// Files uploaded with gzip compression are decompressed, incomplete uploads are skipped. It returns the names of the
+// extracted artifacts.
+func Extract(artifactPath string, runID string, names []string, dir string) ([]string, error) {
+	available, err := ListArtifacts(artifactPath, runID)
+	if err != nil {
+		return nil, err
+	}
+
+	extracted := make([]string, 0, len(available))
+	for _, name := range available {
+		if len(names) > 0 && !common.ContainsString(names, name) {
+			continue
+		}
+		src := safeResolve(safeResolve(artifactPath, runID), name)
+		err := filepath.WalkDir(src, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil || entry.IsDir() || strings.HasSuffix(path, partialExtension) {
+				return err
+			}
+			rel, err := filepath.Rel(src, path)
+			if err != nil {
+				return err
+			}
+			rel = strings.TrimSuffix(rel, gzipExtension)
+			return extractFile(artifactPath, runID, name, rel, safeResolve(dir, filepath.Join(name, rel)))
+		})
+		if err != nil {
+			return extracted, fmt.Errorf("unable to extract artifact '%s': %w", name, err)
+		}
+		extracted = append(extracted, name)
+	}
+	return extracted, nil
+}
+
+func extractFile(artifactPath string, runID string, artifactName string, file string, dest string) error {
+	r, err := openFile(artifactPath, runID, artifactName, file)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return err
+	}
+	w, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, r)
+	if closeErr := w.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
 func uploads(router *httprouter.Router, baseDir string, fsys WriteFS) {
+	locks := &fileLocks{locks: map[string]*fileLock{}}
+	slots := make(chan struct{}, maxConcurrentUploads)
+
 	router.POST("/_apis/pipelines/workflows/:runId/artifacts", func(w http.ResponseWriter, req *http.Request, params httprouter.Params) {
 		runID := params.ByName("runId")
 
@@ -109,6 +305,16 @@ func uploads(router *httprouter.Router, baseDir string, fsys WriteFS) {
 	})
 
 	router.PUT("/upload/:runId", func(w http.ResponseWriter, req *http.Request, params httprouter.Params) {
+		// the clients retry throttled uploads after the delay of Retry-After
+		select {
+		case slots <- struct{}{}:
+			defer func() { <-slots }()
+		default:
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, "too many concurrent uploads", http.StatusTooManyRequests)
+			return
+		}
+
 		itemPath := req.URL.Query().Get("itemPath")
 		runID := params.ByName("runId")
 
@@ -119,32 +325,58 @@ func uploads(router *httprouter.Router, baseDir string, fsys WriteFS) {
 		safeRunPath := safeResolve(baseDir, runID)
 		safePath := safeResolve(safeRunPath, itemPath)
 
+		// the chunks are written at their offset, so a retried chunk doesn't corrupt the file
+		chunk := &contentRange{start: 0, end: -1, total: -1}
+		if header := req.Header.Get("Content-Range"); header != "" {
+			var err error
+			if chunk, err = parseContentRange(header); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+
+		if req.Body == nil {
+			panic(errors.New("No body given"))
+		}
+
+		unlock := locks.lock(safePath)
+		defer unlock()
+
+		partialPath := safePath + partialExtension
 		file, err := func() (WritableFile, error) {
-			contentRange := req.Header.Get("Content-Range")
-			if contentRange != "" && !strings.HasPrefix(contentRange, "bytes 0-") {
-				return fsys.OpenAppendable(safePath)
+			if chunk.start > 0 {
+				return fsys.OpenAt(partialPath, chunk.start)
 			}
-			return fsys.OpenWritable(safePath)
+			return fsys.OpenWritable(partialPath)
 		}()
-
 		if err != nil {
 			panic(err)
 		}
-		defer file.Close()
-
-		writer, ok := file.(io.Writer)
-		if !ok {
-			panic(errors.New("File is not writable"))
-		}
 
-		if req.Body == nil {
-			panic(errors.New("No body given"))
+		n, err := io.Copy(file, req.Body)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
 		}
-
-		_, err = io.Copy(writer, req.Body)
 		if err != nil {
 			panic(err)
 		}
+		if chunk.end >= 0 && n != chunk.end-chunk.start+1 {
+			if chunk.start == 0 {
+				// nothing of the file was received yet
+				if err := fsys.Remove(partialPath); err != nil {
+					panic(err)
+				}
+			}
+			http.Error(w, fmt.Sprintf("received %d bytes of chunk %d-%d", n, chunk.start, chunk.end), http.StatusBadRequest)
+			return
+		}
+
+		// the file appears once it's complete, the downloads never see a partial file
+		if chunk.end < 0 || chunk.end+1 == chunk.total {
+			if err := fsys.Rename(partialPath, safePath); err != nil {
+				panic(err)
+			}
+		}
 
 		json, err := json.Marshal(ResponseMessage{
 			Message: "success",
@@ -214,7 +446,7 @@ func downloads(router *httprouter.Router, baseDir string, fsys fs.FS) {
 
 		var files []ContainerItem
 		err := fs.WalkDir(fsys, safePath, func(path string, entry fs.DirEntry, err error) error {
-			if !entry.IsDir() {
+			if !entry.IsDir() && !strings.HasSuffix(path, partialExtension) {
 				rel, err := filepath.Rel(safePath, path)
 				if err != nil {
 					panic(err)
@@ -262,6 +494,7 @@ func downloads(router *httprouter.Router, baseDir string, fsys fs.FS) {
 			}
 			w.Header().Add("Content-Encoding", "gzip")
 		}
+		defer file.Close()
 
 		_, err = io.Copy(w, file)
 		if err != nil {
@@ -270,7 +503,8 @@ func downloads(router *httprouter.Router, baseDir string, fsys fs.FS) {
 	})
 }
 
-func Serve(ctx context.Context, artifactPath string, addr string, port string) context.CancelFunc {
+// Serve starts the artifact server, the requests have to carry a runtime token issued by tokens unless it is nil
+func Serve(ctx context.Context, artifactPath string, addr string, port string, tokens *common.RuntimeTokens) context.CancelFunc {
 	serverContext, cancel := context.WithCancel(ctx)
 	logger := common.Logger(serverContext)
 
@@ -285,15 +519,21 @@ func Serve(ctx context.Context, artifactPath string, addr string, port string) c
 	uploads(router, artifactPath, fsys)
 	downloads(router, artifactPath, fsys)
 
+	var handler http.Handler = router
+	if tokens != nil {
+		handler = newAuthorizer(tokens, router)
+	}
+	handler = namespacer{handler: handler}
+
 	server := &http.Server{
-		Addr:              fmt.Sprintf("%s:%s", addr, port),
+		Addr:              net.JoinHostPort(addr, port),
 		ReadHeaderTimeout: 2 * time.Second,
-		Handler:           router,
+		Handler:           handler,
 	}
 
 	// run server
 	go func() {
-		logger.Infof("Start server on http://%s:%s", addr, port)
+		logger.Infof("Start server on http://%s", server.Addr)
 		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			logger.Fatal(err)
 		}
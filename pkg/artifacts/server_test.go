@@ -1,19 +1,26 @@
 package artifacts
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"io/fs"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"testing/fstest"
+	"time"
 
 	"github.com/julienschmidt/httprouter"
+	"github.com/nektos/act/pkg/common"
 	"github.com/nektos/act/pkg/model"
 	"github.com/nektos/act/pkg/runner"
 	log "github.com/sirupsen/logrus"
@@ -48,7 +55,7 @@ func (fsys writeMapFS) OpenWritable(name string) (WritableFile, error) {
 	return file, nil
 }
 
-func (fsys writeMapFS) OpenAppendable(name string) (WritableFile, error) {
+func (fsys writeMapFS) OpenAt(name string, offset int64) (WritableFile, error) {
 	var file = &writableMapFile{
 		MapFile: fstest.MapFile{
 			Data: []byte("content2"),
@@ -59,6 +66,21 @@ func (fsys writeMapFS) OpenAppendable(name string) (WritableFile, error) {
 	return file, nil
 }
 
+func (fsys writeMapFS) Rename(oldname string, newname string) error {
+	file, ok := fsys.MapFS[oldname]
+	if !ok {
+		return fs.ErrNotExist
+	}
+	delete(fsys.MapFS, oldname)
+	fsys.MapFS[newname] = file
+	return nil
+}
+
+func (fsys writeMapFS) Remove(name string) error {
+	delete(fsys.MapFS, name)
+	return nil
+}
+
 func TestNewArtifactUploadPrepare(t *testing.T) {
 	assert := assert.New(t)
 
@@ -249,7 +271,9 @@ func TestArtifactFlow(t *testing.T) {
 
 	ctx := context.Background()
 
-	cancel := Serve(ctx, artifactsPath, artifactsAddr, artifactsPort)
+	tokens, err := common.NewRuntimeTokens()
+	assert.Nil(t, err)
+	cancel := Serve(ctx, artifactsPath, artifactsAddr, artifactsPort, tokens)
 	defer cancel()
 
 	platforms := map[string]string{
@@ -263,11 +287,11 @@ func TestArtifactFlow(t *testing.T) {
 	log.SetLevel(log.DebugLevel)
 
 	for _, table := range tables {
-		runTestJobFile(ctx, t, table)
+		runTestJobFile(ctx, t, table, tokens)
 	}
 }
 
-func runTestJobFile(ctx context.Context, t *testing.T, tjfi TestJobFileInfo) {
+func runTestJobFile(ctx context.Context, t *testing.T, tjfi TestJobFileInfo, tokens *common.RuntimeTokens) {
 	t.Run(tjfi.workflowPath, func(t *testing.T) {
 		fmt.Printf("::group::%s\n", tjfi.workflowPath)
 
@@ -289,6 +313,7 @@ func runTestJobFile(ctx context.Context, t *testing.T, tjfi TestJobFileInfo) {
 			ArtifactServerPath:    artifactsPath,
 			ArtifactServerAddr:    artifactsAddr,
 			ArtifactServerPort:    artifactsPort,
+			RuntimeTokens:         tokens,
 		}
 
 		runner, err := runner.New(runnerConfig)
@@ -387,3 +412,245 @@ func TestArtifactUploadBlobUnsafePath(t *testing.T) {
 	assert.Equal("success", response.Message)
 	assert.Equal("content", string(memfs["artifact/server/path/1/some/file"].Data))
 }
+
+func TestReadFile(t *testing.T) {
+	assert := assert.New(t)
+
+	artifactPath := t.TempDir()
+	assert.NoError(os.MkdirAll(filepath.Join(artifactPath, "1", "artifact", "dir"), os.ModePerm))
+	assert.NoError(os.WriteFile(filepath.Join(artifactPath, "1", "artifact", "file.txt"), []byte("plain"), 0o600))
+
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	_, err := gz.Write([]byte("compressed"))
+	assert.NoError(err)
+	assert.NoError(gz.Close())
+	assert.NoError(os.WriteFile(filepath.Join(artifactPath, "1", "artifact", "dir", "file.txt"+gzipExtension), compressed.Bytes(), 0o600))
+
+	content, err := ReadFile(artifactPath, "1", "artifact", "file.txt")
+	assert.NoError(err)
+	assert.Equal("plain", string(content))
+
+	content, err = ReadFile(artifactPath, "1", "artifact", "dir/file.txt")
+	assert.NoError(err)
+	assert.Equal("compressed", string(content))
+
+	_, err = ReadFile(artifactPath, "1", "artifact", "missing.txt")
+	assert.ErrorIs(err, fs.ErrNotExist)
+}
+
+func TestListArtifactsOfRun(t *testing.T) {
+	assert := assert.New(t)
+
+	artifactPath := t.TempDir()
+	assert.NoError(os.MkdirAll(filepath.Join(artifactPath, "1", "build"), os.ModePerm))
+	assert.NoError(os.MkdirAll(filepath.Join(artifactPath, "1", "coverage"), os.ModePerm))
+
+	names, err := ListArtifacts(artifactPath, "1")
+	assert.NoError(err)
+	assert.Equal([]string{"build", "coverage"}, names)
+
+	names, err = ListArtifacts(artifactPath, "2")
+	assert.NoError(err)
+	assert.Empty(names)
+}
+
+// putChunk uploads a chunk of a file, throttled uploads are retried
+func putChunk(t *testing.T, url string, itemPath string, data []byte, start int, total int) {
+	for {
+		req, _ := http.NewRequest("PUT", fmt.Sprintf("%s/upload/1?itemPath=%s", url, itemPath), bytes.NewReader(data[start:]))
+		if total >= 0 {
+			req.Header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, len(data)-1, total))
+		}
+		res, err := http.DefaultClient.Do(req)
+		if !assert.NoError(t, err) {
+			return
+		}
+		res.Body.Close()
+		if res.StatusCode == http.StatusTooManyRequests {
+			time.Sleep(10 * time.Millisecond)
+			continue
+		}
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		return
+	}
+}
+
+func TestArtifactUploadChunks(t *testing.T) {
+	dir := t.TempDir()
+	router := httprouter.New()
+	uploads(router, dir, readWriteFSImpl{})
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	content := []byte("0123456789")
+	file := filepath.Join(dir, "1", "artifact", "file")
+
+	putChunk(t, server.URL, "artifact/file", content[:4], 0, len(content))
+	assert.NoFileExists(t, file, "a partial file is not visible")
+
+	// a retried chunk is written at its offset again
+	putChunk(t, server.URL, "artifact/file", content[:8], 4, len(content))
+	putChunk(t, server.URL, "artifact/file", content[:8], 4, len(content))
+	assert.NoFileExists(t, file)
+
+	putChunk(t, server.URL, "artifact/file", content, 8, len(content))
+	data, err := os.ReadFile(file)
+	assert.NoError(t, err)
+	assert.Equal(t, content, data)
+	assert.NoFileExists(t, file+partialExtension)
+
+	// a chunk which doesn't match its Content-Range is rejected
+	req, _ := http.NewRequest("PUT", server.URL+"/upload/1?itemPath=artifact/other", strings.NewReader("012"))
+	req.Header.Set("Content-Range", "bytes 0-9/10")
+	res, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	res.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+
+	// a chunk of a file of unknown size is rejected, the file would never be complete
+	req, _ = http.NewRequest("PUT", server.URL+"/upload/1?itemPath=artifact/other", strings.NewReader("012"))
+	req.Header.Set("Content-Range", "bytes 0-2/*")
+	res, err = http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	res.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	assert.NoFileExists(t, filepath.Join(dir, "1", "artifact", "other"+partialExtension))
+}
+
+func TestParseContentRange(t *testing.T) {
+	tables := []struct {
+		header   string
+		expected *contentRange
+	}{
+		{"bytes 0-9/10", &contentRange{start: 0, end: 9, total: 10}},
+		{"bytes 4-7/10", &contentRange{start: 4, end: 7, total: 10}},
+		{"bytes 0-9/*", nil},
+		{"bytes 0-9/9", nil},
+		{"bytes 9-0/10", nil},
+		{"0-9/10", nil},
+	}
+	for _, table := range tables {
+		r, err := parseContentRange(table.header)
+		if table.expected == nil {
+			assert.Error(t, err, table.header)
+		} else {
+			assert.NoError(t, err, table.header)
+			assert.Equal(t, table.expected, r, table.header)
+		}
+	}
+}
+
+func TestArtifactUploadConcurrent(t *testing.T) {
+	dir := t.TempDir()
+	router := httprouter.New()
+	uploads(router, dir, readWriteFSImpl{})
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	const jobs, files, chunkSize = 16, 8, 1024
+	var wg sync.WaitGroup
+	for job := 0; job < jobs; job++ {
+		for file := 0; file < files; file++ {
+			wg.Add(1)
+			go func(job int, file int) {
+				defer wg.Done()
+				content := []byte(strings.Repeat(fmt.Sprintf("job %d file %d;", job, file), 500))
+				itemPath := fmt.Sprintf("artifact-%d/file-%d", job, file)
+				for start := 0; start < len(content); start += chunkSize {
+					end := start + chunkSize
+					if end > len(content) {
+						end = len(content)
+					}
+					putChunk(t, server.URL, itemPath, content[:end], start, len(content))
+				}
+			}(job, file)
+		}
+	}
+	wg.Wait()
+
+	for job := 0; job < jobs; job++ {
+		for file := 0; file < files; file++ {
+			data, err := os.ReadFile(filepath.Join(dir, "1", fmt.Sprintf("artifact-%d", job), fmt.Sprintf("file-%d", file)))
+			assert.NoError(t, err)
+			assert.Equal(t, strings.Repeat(fmt.Sprintf("job %d file %d;", job, file), 500), string(data))
+		}
+	}
+}
+
+func TestArtifactUploadThrottled(t *testing.T) {
+	dir := t.TempDir()
+	router := httprouter.New()
+	uploads(router, dir, readWriteFSImpl{})
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	// uploads whose body never ends hold their slot
+	writers := make([]*io.PipeWriter, 0, maxConcurrentUploads)
+	var wg sync.WaitGroup
+	for i := 0; i < maxConcurrentUploads; i++ {
+		r, w := io.Pipe()
+		writers = append(writers, w)
+		req, _ := http.NewRequest("PUT", fmt.Sprintf("%s/upload/1?itemPath=artifact/file-%d", server.URL, i), r)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if res, err := http.DefaultClient.Do(req); err == nil {
+				res.Body.Close()
+			}
+		}()
+	}
+	defer func() {
+		for _, w := range writers {
+			w.Close()
+		}
+		wg.Wait()
+	}()
+
+	assert.Eventually(t, func() bool {
+		req, _ := http.NewRequest("PUT", server.URL+"/upload/1?itemPath=artifact/throttled", strings.NewReader("content"))
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return false
+		}
+		res.Body.Close()
+		return res.StatusCode == http.StatusTooManyRequests && res.Header.Get("Retry-After") != ""
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestExtract(t *testing.T) {
+	assert := assert.New(t)
+
+	artifactPath := t.TempDir()
+	assert.NoError(os.MkdirAll(filepath.Join(artifactPath, "1", "build", "bin"), os.ModePerm))
+	assert.NoError(os.MkdirAll(filepath.Join(artifactPath, "1", "coverage"), os.ModePerm))
+	assert.NoError(os.WriteFile(filepath.Join(artifactPath, "1", "build", "bin", "app"), []byte("binary"), 0o600))
+	assert.NoError(os.WriteFile(filepath.Join(artifactPath, "1", "build", "incomplete"+partialExtension), []byte("part"), 0o600))
+	assert.NoError(os.WriteFile(filepath.Join(artifactPath, "1", "coverage", "report.txt"), []byte("100%"), 0o600))
+
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	_, err := gz.Write([]byte("compressed"))
+	assert.NoError(err)
+	assert.NoError(gz.Close())
+	assert.NoError(os.WriteFile(filepath.Join(artifactPath, "1", "build", "notes.txt"+gzipExtension), compressed.Bytes(), 0o600))
+
+	dir := t.TempDir()
+	extracted, err := Extract(artifactPath, "1", []string{"build"}, dir)
+	assert.NoError(err)
+	assert.Equal([]string{"build"}, extracted)
+
+	content, err := os.ReadFile(filepath.Join(dir, "build", "bin", "app"))
+	assert.NoError(err)
+	assert.Equal("binary", string(content))
+	content, err = os.ReadFile(filepath.Join(dir, "build", "notes.txt"))
+	assert.NoError(err)
+	assert.Equal("compressed", string(content))
+	assert.NoFileExists(filepath.Join(dir, "build", "incomplete"+partialExtension))
+	assert.NoDirExists(filepath.Join(dir, "coverage"))
+
+	extracted, err = Extract(artifactPath, "1", nil, dir)
+	assert.NoError(err)
+	assert.Equal([]string{"build", "coverage"}, extracted)
+	assert.FileExists(filepath.Join(dir, "coverage", "report.txt"))
+}
@@ -0,0 +1,23 @@
+package common
+
+import "strings"
+
+// ArtifactNamespacePrefix is the path of the runtime URL of the jobs namespaced by their workflow, e.g.
+// /namespace/ci/. The artifacts of every namespace are kept apart while the jobs share GITHUB_RUN_ID.
+const ArtifactNamespacePrefix = "/namespace/"
+
+// NamespacedRunID returns the run the artifacts of a namespace are stored under, the run itself without a namespace
+func NamespacedRunID(runID string, namespace string) string {
+	if namespace == "" {
+		return runID
+	}
+	return runID + "-" + namespace
+}
+
+// NamespacedRuntimeURL returns the runtime URL (ACTIONS_RUNTIME_URL) of the jobs of a namespace
+func NamespacedRuntimeURL(runtimeURL string, namespace string) string {
+	if namespace == "" {
+		return runtimeURL
+	}
+	return strings.TrimSuffix(runtimeURL, "/") + ArtifactNamespacePrefix + namespace + "/"
+}
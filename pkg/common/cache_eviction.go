@@ -0,0 +1,150 @@
+package common
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CacheEntry is a file or directory of a cache directory, which is evicted as a whole
+type CacheEntry struct {
+	Path     string
+	Size     int64
+	LastUsed time.Time
+}
+
+// TouchCacheEntry marks an entry of a cache directory as used, the entries used least recently are evicted first
+func TouchCacheEntry(path string) {
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+}
+
+// EvictCache removes the least recently used entries of the cache directories until their total size is at most
+// maxSize. A cache directory nested in another one is not an entry of it. Entries used within minAge are kept,
+// as another run may be using them. The removed entries are returned.
+func EvictCache(dirs []string, maxSize int64, minAge time.Duration) ([]*CacheEntry, error) {
+	isCacheDir := map[string]bool{}
+	for _, dir := range dirs {
+		isCacheDir[filepath.Clean(dir)] = true
+	}
+
+	var entries []*CacheEntry
+	var total int64
+	for _, dir := range dirs {
+		children, err := os.ReadDir(dir)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		for _, child := range children {
+			path := filepath.Join(dir, child.Name())
+			if isCacheDir[filepath.Clean(path)] {
+				continue
+			}
+			info, err := child.Info()
+			if err != nil {
+				continue
+			}
+			entry := &CacheEntry{Path: path, LastUsed: info.ModTime()}
+			if entry.Size, err = diskUsage(path); err != nil {
+				return nil, err
+			}
+			entries = append(entries, entry)
+			total += entry.Size
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].LastUsed.Before(entries[j].LastUsed)
+	})
+
+	evicted := make([]*CacheEntry, 0)
+	for _, entry := range entries {
+		if total <= maxSize {
+			break
+		}
+		if time.Since(entry.LastUsed) < minAge {
+			break
+		}
+		if err := os.RemoveAll(entry.Path); err != nil {
+			return evicted, err
+		}
+		total -= entry.Size
+		evicted = append(evicted, entry)
+	}
+	return evicted, nil
+}
+
+func diskUsage(path string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// the entry may be removed while walking it
+			return nil
+		}
+		if d.Type().IsRegular() {
+			if info, err := d.Info(); err == nil {
+				size += info.Size()
+			}
+		}
+		return nil
+	})
+	return size, err
+}
+
+var sizePattern = regexp.MustCompile(`^(\d+(?:\.\d+)?)\s*([a-zA-Z]*)$`)
+
+var sizeUnits = map[string]float64{
+	"":    1,
+	"b":   1,
+	"kb":  1e3,
+	"mb":  1e6,
+	"gb":  1e9,
+	"tb":  1e12,
+	"k":   1 << 10,
+	"m":   1 << 20,
+	"g":   1 << 30,
+	"t":   1 << 40,
+	"kib": 1 << 10,
+	"mib": 1 << 20,
+	"gib": 1 << 30,
+	"tib": 1 << 40,
+}
+
+// ParseSize parses a size in bytes with an optional unit, e.g. 20GB, 512MiB or 1073741824
+func ParseSize(s string) (int64, error) {
+	match := sizePattern.FindStringSubmatch(strings.TrimSpace(s))
+	if match == nil {
+		return 0, fmt.Errorf("invalid size '%s', expected e.g. 20GB or 512MiB", s)
+	}
+	unit, ok := sizeUnits[strings.ToLower(match[2])]
+	if !ok {
+		return 0, fmt.Errorf("invalid unit of size '%s', expected B, KB, MB, GB, TB, KiB, MiB, GiB or TiB", s)
+	}
+	value, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return 0, err
+	}
+	return int64(value * unit), nil
+}
+
+// FormatSize formats a size in bytes with a binary unit
+func FormatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,62 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvictCache(t *testing.T) {
+	dir := t.TempDir()
+	workflows := filepath.Join(dir, "workflows")
+	write := func(path string, size int, lastUsed time.Time) {
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		assert.NoError(t, os.WriteFile(path, make([]byte, size), 0o644))
+		assert.NoError(t, os.Chtimes(path, lastUsed, lastUsed))
+	}
+	now := time.Now()
+	write(filepath.Join(dir, "actions-checkout@v3", "action.yml"), 100, now)
+	assert.NoError(t, os.Chtimes(filepath.Join(dir, "actions-checkout@v3"), now.Add(-3*time.Hour), now.Add(-3*time.Hour)))
+	write(filepath.Join(workflows, "old"), 100, now.Add(-2*time.Hour))
+	write(filepath.Join(workflows, "recent"), 100, now.Add(-time.Hour))
+	write(filepath.Join(workflows, "current"), 100, now)
+
+	evicted, err := EvictCache([]string{dir, workflows}, 250, 30*time.Minute)
+	assert.NoError(t, err)
+	paths := make([]string, 0, len(evicted))
+	for _, entry := range evicted {
+		paths = append(paths, entry.Path)
+	}
+	assert.Equal(t, []string{filepath.Join(dir, "actions-checkout@v3"), filepath.Join(workflows, "old")}, paths)
+	assert.NoDirExists(t, filepath.Join(dir, "actions-checkout@v3"))
+	assert.FileExists(t, filepath.Join(workflows, "recent"))
+
+	// the entries used recently are kept even if the cache is too large
+	TouchCacheEntry(filepath.Join(workflows, "recent"))
+	evicted, err = EvictCache([]string{dir, workflows}, 0, 30*time.Minute)
+	assert.NoError(t, err)
+	assert.Empty(t, evicted)
+}
+
+func TestParseSize(t *testing.T) {
+	for s, size := range map[string]int64{
+		"1024":   1024,
+		"20GB":   20e9,
+		"1.5 gb": 1.5e9,
+		"512MiB": 512 << 20,
+		"2G":     2 << 30,
+	} {
+		parsed, err := ParseSize(s)
+		assert.NoError(t, err, s)
+		assert.Equal(t, size, parsed, s)
+	}
+	for _, s := range []string{"", "GB", "20XB", "-1GB"} {
+		_, err := ParseSize(s)
+		assert.Error(t, err, s)
+	}
+	assert.Equal(t, "1.5 GiB", FormatSize(3<<29))
+	assert.Equal(t, "100 B", FormatSize(100))
+}
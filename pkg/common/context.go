@@ -0,0 +1,32 @@
+package common
+
+import (
+	"context"
+	"time"
+)
+
+// WithoutCancel returns a context with the values of ctx which is neither cancelled nor times out with it,
+// the cleanup after a cancelled job still needs the logger and the job error of its context
+func WithoutCancel(ctx context.Context) context.Context {
+	return detachedContext{parent: ctx}
+}
+
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
+
+func (c detachedContext) Value(key interface{}) interface{} {
+	return c.parent.Value(key)
+}
@@ -10,12 +10,14 @@ import (
 	"regexp"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/nektos/act/pkg/common"
 
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/go-git/go-git/v5/plumbing/storer"
 	"github.com/go-git/go-git/v5/plumbing/transport/http"
 	"github.com/mattn/go-isatty"
@@ -30,8 +32,9 @@ var (
 
 	cloneLock sync.Mutex
 
-	ErrShortRef = errors.New("short SHA references are not supported")
-	ErrNoRepo   = errors.New("unable to find git repo")
+	ErrShortRef     = errors.New("short SHA references are not supported")
+	ErrNoRepo       = errors.New("unable to find git repo")
+	ErrDetachedHead = errors.New("HEAD doesn't refer to a branch")
 )
 
 type Error struct {
@@ -160,6 +163,107 @@ func FindGitRef(ctx context.Context, file string) (string, error) {
 	return "", fmt.Errorf("failed to identify reference (tag/branch) for the checked-out revision '%s'", ref)
 }
 
+// FindGitBranch returns the branch checked out in the worktree containing file, the branch HEAD refers to rather than
+// a branch pointing to the same commit. The error is ErrDetachedHead without a checked-out branch.
+func FindGitBranch(ctx context.Context, file string) (string, error) {
+	repo, err := git.PlainOpenWithOptions(
+		file,
+		&git.PlainOpenOptions{
+			DetectDotGit:          true,
+			EnableDotGitCommonDir: true,
+		},
+	)
+	if err != nil {
+		return "", err
+	}
+	head, err := repo.Storer.Reference(plumbing.HEAD)
+	if err != nil {
+		return "", err
+	}
+	if head.Type() != plumbing.SymbolicReference || !head.Target().IsBranch() {
+		return "", ErrDetachedHead
+	}
+	return head.Target().Short(), nil
+}
+
+// FindGitRoot returns the root of the worktree of the git repository containing file
+func FindGitRoot(ctx context.Context, file string) (string, error) {
+	repo, err := git.PlainOpenWithOptions(
+		file,
+		&git.PlainOpenOptions{
+			DetectDotGit:          true,
+			EnableDotGitCommonDir: true,
+		},
+	)
+	if err != nil {
+		return "", err
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return "", err
+	}
+	return worktree.Filesystem.Root(), nil
+}
+
+// CommitInfo is a commit of the history of the checked-out revision
+type CommitInfo struct {
+	SHA       string
+	Parent    string // the first parent, empty for a root commit
+	Tree      string
+	Message   string
+	Author    string
+	Email     string
+	Timestamp time.Time
+}
+
+// FindGitCommits returns the last n commits of the history of the checked-out revision, the most recent first
+func FindGitCommits(ctx context.Context, file string, n int) ([]*CommitInfo, error) {
+	repo, err := git.PlainOpenWithOptions(
+		file,
+		&git.PlainOpenOptions{
+			DetectDotGit:          true,
+			EnableDotGitCommonDir: true,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return nil, err
+	}
+	iter, err := repo.Log(&git.LogOptions{From: head.Hash()})
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	commits := make([]*CommitInfo, 0, n)
+	err = iter.ForEach(func(c *object.Commit) error {
+		if len(commits) >= n {
+			return storer.ErrStop
+		}
+		commit := &CommitInfo{
+			SHA:       c.Hash.String(),
+			Tree:      c.TreeHash.String(),
+			Message:   strings.TrimSpace(c.Message),
+			Author:    c.Author.Name,
+			Email:     c.Author.Email,
+			Timestamp: c.Author.When,
+		}
+		if len(c.ParentHashes) > 0 {
+			commit.Parent = c.ParentHashes[0].String()
+		}
+		commits = append(commits, commit)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	common.Logger(ctx).Debugf("Found %d commit(s) from %s", len(commits), head.Hash())
+	return commits, nil
+}
+
 // FindGithubRepo get the repo
 func FindGithubRepo(ctx context.Context, file, githubInstance, remoteName string) (string, error) {
 	if remoteName == "" {
@@ -254,7 +358,10 @@ func CloneIfRequired(ctx context.Context, refName plumbing.ReferenceName, input
 			}
 		}
 
-		r, err = git.PlainCloneContext(ctx, input.Dir, false, &cloneOptions)
+		err = retryRemote(ctx, fmt.Sprintf("git clone '%s'", input.URL), func(ctx context.Context) error {
+			r, err = git.PlainCloneContext(ctx, input.Dir, false, &cloneOptions)
+			return err
+		})
 		if err != nil {
 			logger.Errorf("Unable to clone %v %s: %v", input.URL, refName, err)
 			return nil, err
@@ -268,6 +375,43 @@ func CloneIfRequired(ctx context.Context, refName plumbing.ReferenceName, input
 	return r, nil
 }
 
+// retryRemote retries a clone or fetch which is rate limited or fails temporarily. go-git only takes the transport of
+// a protocol from its global registry, so the operation is retried instead of its requests.
+func retryRemote(ctx context.Context, what string, op func(ctx context.Context) error) error {
+	return common.DefaultBackoff.Retry(ctx, what, func(ctx context.Context) error {
+		err := op(ctx)
+		if temporary := temporaryRemoteError(err); temporary != nil {
+			return temporary
+		}
+		return err
+	})
+}
+
+// temporaryRemoteError returns a TemporaryError if err is a temporary failure of the remote, nil otherwise
+func temporaryRemoteError(err error) *common.TemporaryError {
+	if err == nil {
+		return nil
+	}
+	// the unexpected errors of go-git don't unwrap
+	cause := err
+	var unexpected *plumbing.UnexpectedError
+	if errors.As(err, &unexpected) {
+		cause = unexpected.Err
+	}
+	var httpErr *http.Err
+	if errors.As(cause, &httpErr) && httpErr.Response != nil {
+		temporary := common.TemporaryResponseError(httpErr.Response)
+		if temporary != nil {
+			temporary.Err = err
+		}
+		return temporary
+	}
+	if common.IsTemporaryNetError(cause) {
+		return &common.TemporaryError{Err: err}
+	}
+	return nil
+}
+
 func gitOptions(token string) (fetchOptions git.FetchOptions, pullOptions git.PullOptions) {
 	fetchOptions.RefSpecs = []config.RefSpec{"refs/*:refs/*", "HEAD:refs/heads/HEAD"}
 	pullOptions.Force = true
@@ -305,7 +449,9 @@ func NewGitCloneExecutor(input NewGitCloneExecutorInput) common.Executor {
 		// fetch latest changes
 		fetchOptions, pullOptions := gitOptions(input.Token)
 
-		err = r.Fetch(&fetchOptions)
+		err = retryRemote(ctx, fmt.Sprintf("git fetch '%s'", input.URL), func(ctx context.Context) error {
+			return r.FetchContext(ctx, &fetchOptions)
+		})
 		if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
 			return err
 		}
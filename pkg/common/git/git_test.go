@@ -2,13 +2,19 @@ package git
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	nethttp "net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"syscall"
 	"testing"
 
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -41,6 +47,12 @@ func TestFindGitSlug(t *testing.T) {
 		assert.Equal(tt.provider, provider)
 		assert.Equal(tt.slug, slug)
 	}
+
+	// the host of a GitHub Enterprise Server instance, with its port
+	provider, slug, err := findGitSlug("http://ghe.local:8080/owner/repo.git", "ghe.local:8080")
+	assert.NoError(err)
+	assert.Equal("GitHubEnterprise", provider)
+	assert.Equal("owner/repo", slug)
 }
 
 func testDir(t *testing.T) string {
@@ -97,6 +109,63 @@ func TestFindGitRemoteURL(t *testing.T) {
 	assert.Equal(remoteURL, u)
 }
 
+func TestFindGitRoot(t *testing.T) {
+	basedir := testDir(t)
+	gitConfig()
+	assert.NoError(t, gitCmd("init", basedir))
+	assert.NoError(t, os.MkdirAll(filepath.Join(basedir, "sub", "project"), 0o755))
+
+	root, err := FindGitRoot(context.Background(), filepath.Join(basedir, "sub", "project"))
+	assert.NoError(t, err)
+	assert.Equal(t, basedir, root)
+
+	_, err = FindGitRoot(context.Background(), t.TempDir())
+	assert.Error(t, err)
+}
+
+func TestFindGitCommits(t *testing.T) {
+	basedir := testDir(t)
+	gitConfig()
+	require.NoError(t, gitCmd("init", "--initial-branch=master", basedir))
+	require.NoError(t, cleanGitHooks(basedir))
+	for _, msg := range []string{"first", "second", "third"} {
+		require.NoError(t, gitCmd("-C", basedir, "commit", "--allow-empty", "-m", msg))
+	}
+
+	commits, err := FindGitCommits(context.Background(), basedir, 2)
+	require.NoError(t, err)
+	require.Len(t, commits, 2)
+	assert.Equal(t, "third", commits[0].Message)
+	assert.Equal(t, "second", commits[1].Message)
+	assert.Equal(t, commits[1].SHA, commits[0].Parent)
+	_, sha, err := FindGitRevision(context.Background(), basedir)
+	require.NoError(t, err)
+	assert.Equal(t, sha, commits[0].SHA)
+
+	commits, err = FindGitCommits(context.Background(), basedir, 10)
+	require.NoError(t, err)
+	require.Len(t, commits, 3)
+	assert.Empty(t, commits[2].Parent)
+}
+
+func TestFindGitBranch(t *testing.T) {
+	basedir := testDir(t)
+	gitConfig()
+	require.NoError(t, gitCmd("init", "--initial-branch=master", basedir))
+	require.NoError(t, cleanGitHooks(basedir))
+	require.NoError(t, gitCmd("-C", basedir, "commit", "--allow-empty", "-m", "first"))
+	require.NoError(t, gitCmd("-C", basedir, "checkout", "-b", "feature/login"))
+
+	// master points to the same commit
+	branch, err := FindGitBranch(context.Background(), basedir)
+	assert.NoError(t, err)
+	assert.Equal(t, "feature/login", branch)
+
+	require.NoError(t, gitCmd("-C", basedir, "checkout", "--detach"))
+	_, err = FindGitBranch(context.Background(), basedir)
+	assert.ErrorIs(t, err, ErrDetachedHead)
+}
+
 func TestGitFindRef(t *testing.T) {
 	basedir := testDir(t)
 	gitConfig()
@@ -247,3 +316,29 @@ func gitCmd(args ...string) error {
 	}
 	return nil
 }
+
+func TestTemporaryRemoteError(t *testing.T) {
+	response := func(status int) error {
+		return plumbing.NewUnexpectedError(&http.Err{Response: &nethttp.Response{StatusCode: status, Status: nethttp.StatusText(status), Header: nethttp.Header{}}})
+	}
+
+	tables := []struct {
+		err       error
+		temporary bool
+	}{
+		{nil, false},
+		{response(nethttp.StatusServiceUnavailable), true},
+		{response(nethttp.StatusTooManyRequests), true},
+		{response(nethttp.StatusInternalServerError), false},
+		{transport.ErrRepositoryNotFound, false},
+		{plumbing.NewUnexpectedError(io.ErrUnexpectedEOF), true},
+		{errors.New("reference not found"), false},
+	}
+	for _, table := range tables {
+		temporary := temporaryRemoteError(table.err)
+		assert.Equal(t, table.temporary, temporary != nil, fmt.Sprint(table.err))
+		if temporary != nil {
+			assert.ErrorIs(t, temporary, table.err, "the temporary error wraps the error of go-git")
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// Backoff describes how a remote fetch (action clones, image pulls, API calls) failing temporarily is retried
+type Backoff struct {
+	Attempts int           // attempts including the first one
+	Min      time.Duration // delay before the first retry, doubled for every further retry and jittered
+	Max      time.Duration // longest delay, a rate limit lasting longer isn't waited for
+}
+
+// DefaultBackoff is the Backoff of the remote fetches
+var DefaultBackoff = Backoff{Attempts: 5, Min: 2 * time.Second, Max: 2 * time.Minute}
+
+// TemporaryError is a failure of a remote fetch which is retried, after RetryAfter if the remote asked for it
+type TemporaryError struct {
+	Err         error
+	RateLimited bool
+	RetryAfter  time.Duration
+}
+
+func (e *TemporaryError) Error() string {
+	if e.RateLimited {
+		return fmt.Sprintf("rate limited: %v", e.Err)
+	}
+	return e.Err.Error()
+}
+
+func (e *TemporaryError) Unwrap() error {
+	return e.Err
+}
+
+// Retry calls fetch until it succeeds, fails with an error which isn't a TemporaryError or the attempts are exhausted,
+// what names the fetch in the log
+func (b Backoff) Retry(ctx context.Context, what string, fetch func(ctx context.Context) error) error {
+	logger := Logger(ctx)
+	for attempt := 1; ; attempt++ {
+		err := fetch(ctx)
+		var temporary *TemporaryError
+		if err == nil || !errors.As(err, &temporary) || attempt >= b.Attempts {
+			return err
+		}
+		if temporary.RetryAfter > b.Max {
+			logger.Warnf("%s: rate limited for %s, not retrying", what, temporary.RetryAfter.Round(time.Second))
+			return err
+		}
+
+		delay := b.delay(attempt, temporary.RetryAfter)
+		if temporary.RateLimited {
+			logger.Warnf("%s: rate limited, retrying in %s", what, delay.Round(time.Second))
+		} else {
+			logger.Warnf("%s: %v, retrying in %s", what, temporary.Err, delay.Round(time.Second))
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+func (b Backoff) delay(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return retryAfter
+	}
+	delay := b.Min << (attempt - 1)
+	if delay <= 0 || delay > b.Max {
+		delay = b.Max
+	}
+	// spread the retries of the jobs running in parallel
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1)) //nolint:gosec
+}
+
+// IsTemporaryNetError reports if a network error is likely to go away when retried
+func IsTemporaryNetError(err error) bool {
+	var netErr net.Error
+	return (errors.As(err, &netErr) && netErr.Timeout()) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// RetryTransport retries the requests of the Base transport which fail temporarily, it honors the rate limits of
+// GitHub (Retry-After and X-RateLimit-Reset). Requests with a body which can't be replayed are not retried.
+type RetryTransport struct {
+	Base    http.RoundTripper
+	Backoff Backoff
+}
+
+// NewRetryTransport wraps the transport with the DefaultBackoff
+func NewRetryTransport(base http.RoundTripper) *RetryTransport {
+	return &RetryTransport{Base: base, Backoff: DefaultBackoff}
+}
+
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return t.Base.RoundTrip(req)
+	}
+
+	var resp *http.Response
+	attempt := 0
+	err := t.Backoff.Retry(req.Context(), fmt.Sprintf("%s %s", req.Method, req.URL.Redacted()), func(ctx context.Context) error {
+		attempt++
+		r := req
+		if attempt > 1 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return err
+			}
+			r = req.Clone(ctx)
+			r.Body = body
+		}
+
+		var err error
+		resp, err = t.Base.RoundTrip(r)
+		if err != nil {
+			if IsTemporaryNetError(err) {
+				return &TemporaryError{Err: err}
+			}
+			return err
+		}
+		if temporary := TemporaryResponseError(resp); temporary != nil {
+			if attempt < t.Backoff.Attempts && temporary.RetryAfter <= t.Backoff.Max {
+				_, _ = io.Copy(io.Discard, resp.Body)
+				resp.Body.Close()
+			}
+			return temporary
+		}
+		return nil
+	})
+
+	if err != nil && req.Context().Err() != nil {
+		return nil, req.Context().Err()
+	}
+	var temporary *TemporaryError
+	if errors.As(err, &temporary) && resp != nil {
+		// the caller gets the last response once the attempts are exhausted
+		return resp, nil
+	}
+	return resp, err
+}
+
+// TemporaryResponseError returns a TemporaryError for a rate limited response or a temporary failure of the server
+func TemporaryResponseError(resp *http.Response) *TemporaryError {
+	err := fmt.Errorf("%s", resp.Status)
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests,
+		resp.StatusCode == http.StatusForbidden && resp.Header.Get("Retry-After") != "":
+		return &TemporaryError{Err: err, RateLimited: true, RetryAfter: retryAfter(resp.Header)}
+	case resp.StatusCode == http.StatusForbidden && resp.Header.Get("X-RateLimit-Remaining") == "0":
+		return &TemporaryError{Err: err, RateLimited: true, RetryAfter: rateLimitReset(resp.Header)}
+	case resp.StatusCode == http.StatusBadGateway, resp.StatusCode == http.StatusServiceUnavailable, resp.StatusCode == http.StatusGatewayTimeout:
+		return &TemporaryError{Err: err, RetryAfter: retryAfter(resp.Header)}
+	}
+	return nil
+}
+
+func retryAfter(header http.Header) time.Duration {
+	seconds, err := strconv.Atoi(header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+func rateLimitReset(header http.Header) time.Duration {
+	reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return 0
+	}
+	if wait := time.Until(time.Unix(reset, 0)); wait > 0 {
+		return wait
+	}
+	return 0
+}
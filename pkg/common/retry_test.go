@@ -0,0 +1,99 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryTransport(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, "payload", string(body))
+		switch requests {
+		case 1:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(http.StatusBadGateway)
+		default:
+			_, _ = w.Write([]byte("ok"))
+		}
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &RetryTransport{
+		Base:    http.DefaultTransport,
+		Backoff: Backoff{Attempts: 3, Min: time.Millisecond, Max: time.Second},
+	}}
+	resp, err := client.Post(server.URL, "text/plain", strings.NewReader("payload"))
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, 3, requests)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "ok", string(body))
+}
+
+func TestRetryTransportRateLimitReset(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte("API rate limit exceeded"))
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &RetryTransport{
+		Base:    http.DefaultTransport,
+		Backoff: Backoff{Attempts: 3, Min: time.Millisecond, Max: time.Second},
+	}}
+	resp, err := client.Get(server.URL)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, 1, requests, "a rate limit lasting longer than the backoff isn't waited for")
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	assert.Equal(t, "API rate limit exceeded", string(body))
+}
+
+func TestBackoffRetry(t *testing.T) {
+	backoff := Backoff{Attempts: 3, Min: time.Millisecond, Max: time.Second}
+	permanent := errors.New("not found")
+
+	calls := 0
+	err := backoff.Retry(context.Background(), "fetch", func(ctx context.Context) error {
+		calls++
+		return permanent
+	})
+	assert.ErrorIs(t, err, permanent)
+	assert.Equal(t, 1, calls)
+
+	calls = 0
+	err = backoff.Retry(context.Background(), "fetch", func(ctx context.Context) error {
+		calls++
+		return &TemporaryError{Err: permanent, RateLimited: true}
+	})
+	assert.ErrorIs(t, err, permanent)
+	assert.EqualError(t, err, "rate limited: not found")
+	assert.Equal(t, 3, calls)
+
+	for attempt := 1; attempt <= 20; attempt++ {
+		delay := backoff.delay(attempt, 0)
+		assert.True(t, delay > 0 && delay <= backoff.Max, delay)
+	}
+}
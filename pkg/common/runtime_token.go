@@ -0,0 +1,67 @@
+package common
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// RuntimeTokenClaims identify the job an ACTIONS_RUNTIME_TOKEN was issued to
+type RuntimeTokenClaims struct {
+	RunID string `json:"run_id"`
+	Job   string `json:"job"`
+}
+
+// RuntimeTokens issues and validates the ACTIONS_RUNTIME_TOKEN of the jobs, which the servers of act
+// (e.g. the artifact server) use to tell the jobs apart. The tokens are signed with a key which lives
+// as long as the RuntimeTokens.
+type RuntimeTokens struct {
+	key []byte
+}
+
+// NewRuntimeTokens creates the issuer of the runtime tokens with a random key
+func NewRuntimeTokens() (*RuntimeTokens, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	return &RuntimeTokens{key: key}, nil
+}
+
+// Issue returns a token for the claims
+func (t *RuntimeTokens) Issue(claims RuntimeTokenClaims) string {
+	payload, _ := json.Marshal(claims)
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(t.sign(encoded))
+}
+
+// Validate checks the signature of the token and returns its claims
+func (t *RuntimeTokens) Validate(token string) (*RuntimeTokenClaims, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, errors.New("malformed runtime token")
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(sig, t.sign(encoded)) {
+		return nil, errors.New("invalid signature of runtime token")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	claims := &RuntimeTokenClaims{}
+	if err := json.Unmarshal(payload, claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+func (t *RuntimeTokens) sign(payload string) []byte {
+	mac := hmac.New(sha256.New, t.key)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
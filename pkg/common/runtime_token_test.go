@@ -0,0 +1,28 @@
+package common
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRuntimeTokens(t *testing.T) {
+	tokens, err := NewRuntimeTokens()
+	assert.NoError(t, err)
+
+	token := tokens.Issue(RuntimeTokenClaims{RunID: "1", Job: "build"})
+	claims, err := tokens.Validate(token)
+	assert.NoError(t, err)
+	assert.Equal(t, &RuntimeTokenClaims{RunID: "1", Job: "build"}, claims)
+
+	other, err := NewRuntimeTokens()
+	assert.NoError(t, err)
+	_, err = other.Validate(token)
+	assert.Error(t, err)
+
+	for _, invalid := range []string{"", "token", token + "x", "e30." + token[strings.Index(token, ".")+1:]} {
+		_, err = tokens.Validate(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
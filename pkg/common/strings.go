@@ -0,0 +1,11 @@
+package common
+
+// ContainsString reports whether value is one of values
+func ContainsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Backend is the container engine running the containers of the jobs, selected with --container-backend
+type Backend string
+
+const (
+	BackendDocker Backend = "docker" // the docker daemon of DOCKER_HOST
+	BackendPodman Backend = "podman" // the API service of podman, rootless for a user other than root
+)
+
+// ParseBackend returns the backend of the name, docker if it is empty
+func ParseBackend(name string) (Backend, error) {
+	switch Backend(name) {
+	case "", BackendDocker:
+		return BackendDocker, nil
+	case BackendPodman:
+		return BackendPodman, nil
+	}
+	return "", fmt.Errorf("invalid container backend '%s', expected %s or %s", name, BackendDocker, BackendPodman)
+}
+
+type backendContextKey string
+
+const backendContextKeyVal = backendContextKey("container.backend")
+
+// WithBackend returns a context whose containers run on the backend
+func WithBackend(ctx context.Context, backend Backend) context.Context {
+	return context.WithValue(ctx, backendContextKeyVal, backend)
+}
+
+// BackendFrom returns the backend of the context, docker if the context has none
+func BackendFrom(ctx context.Context) Backend {
+	if backend, ok := ctx.Value(backendContextKeyVal).(Backend); ok && backend != "" {
+		return backend
+	}
+	return BackendDocker
+}
+
+// Socket returns the API socket of the backend on the host, which is mounted in the job containers as
+// /var/run/docker.sock. It is the address of the API if the API of podman isn't a local socket.
+func (b Backend) Socket() string {
+	if b == BackendPodman {
+		return strings.TrimPrefix(podmanHost(), "unix://")
+	}
+	return "/var/run/docker.sock"
+}
+
+// cli returns the command line interface of the backend
+func (b Backend) cli() string {
+	if b == BackendPodman {
+		return "podman"
+	}
+	return "docker"
+}
+
+// podmanHost returns the address of the API service of podman: CONTAINER_HOST, which the podman cli connects to as
+// well, or the socket the service of the user listens on, rootless, or the one of the service of root
+func podmanHost() string {
+	if host := os.Getenv("CONTAINER_HOST"); host != "" {
+		return host
+	}
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" && os.Geteuid() != 0 {
+		return "unix://" + filepath.Join(dir, "podman", "podman.sock")
+	}
+	return "unix:///run/podman/podman.sock"
+}
+
+// checkPodmanHost returns an error explaining how to start the API service of podman if nothing listens on the
+// socket of host, the service is usually started on demand by systemd
+func checkPodmanHost(host string) error {
+	socket := strings.TrimPrefix(host, "unix://")
+	if socket == host {
+		return nil
+	}
+	if _, err := os.Stat(socket); err != nil {
+		unit := "systemctl --user enable --now podman.socket"
+		if os.Geteuid() == 0 {
+			unit = "systemctl enable --now podman.socket"
+		}
+		return fmt.Errorf("the API service of podman isn't listening on %s, start it with '%s' or 'podman system service --time=0 %s'", socket, unit, host)
+	}
+	return nil
+}
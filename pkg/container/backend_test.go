@@ -0,0 +1,58 @@
+package container
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseBackend(t *testing.T) {
+	backend, err := ParseBackend("")
+	assert.NoError(t, err)
+	assert.Equal(t, BackendDocker, backend)
+
+	backend, err = ParseBackend("podman")
+	assert.NoError(t, err)
+	assert.Equal(t, BackendPodman, backend)
+
+	_, err = ParseBackend("containerd")
+	assert.EqualError(t, err, "invalid container backend 'containerd', expected docker or podman")
+}
+
+func TestDockerHostBackend(t *testing.T) {
+	t.Setenv("DOCKER_HOST", "unix:///var/run/docker.sock")
+	t.Setenv("CONTAINER_HOST", "unix:///run/user/1000/podman/podman.sock")
+
+	ctx := context.Background()
+	assert.Equal(t, BackendDocker, BackendFrom(ctx))
+	assert.Equal(t, "unix:///var/run/docker.sock", DockerHost(ctx))
+
+	ctx = WithBackend(ctx, BackendPodman)
+	assert.Equal(t, "unix:///run/user/1000/podman/podman.sock", DockerHost(ctx))
+	assert.Equal(t, "/run/user/1000/podman/podman.sock", BackendPodman.Socket())
+	assert.Equal(t, "/var/run/docker.sock", BackendDocker.Socket())
+
+	// the host of --container-daemon-socket is used with any backend
+	assert.Equal(t, "tcp://127.0.0.1:2375", DockerHost(WithDockerHost(ctx, "tcp://127.0.0.1:2375")))
+}
+
+func TestPodmanHost(t *testing.T) {
+	t.Setenv("CONTAINER_HOST", "")
+	dir := t.TempDir()
+	t.Setenv("XDG_RUNTIME_DIR", dir)
+
+	socket := filepath.Join(dir, "podman", "podman.sock")
+	if os.Geteuid() == 0 {
+		socket = "/run/podman/podman.sock"
+	}
+	assert.Equal(t, "unix://"+socket, podmanHost())
+
+	assert.ErrorContains(t, checkPodmanHost("unix://"+filepath.Join(dir, "missing.sock")), "the API service of podman isn't listening on")
+	assert.NoError(t, checkPodmanHost("ssh://core@localhost:2222/run/podman/podman.sock"))
+
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "podman.sock"), nil, 0o600))
+	assert.NoError(t, checkPodmanHost("unix://"+filepath.Join(dir, "podman.sock")))
+}
@@ -2,7 +2,10 @@ package container
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
+	"time"
 
 	"github.com/nektos/act/pkg/common"
 )
@@ -26,6 +29,64 @@ type NewContainerInput struct {
 	UsernsMode  string
 	Platform    string
 	Options     string
+	ExtraHosts  []string
+	Labels      map[string]string
+	User        string // user the container runs as, <name|uid>[:<group|gid>], the user of the image if empty
+
+	NetworkAliases []string     // names of the container in the network of NetworkMode
+	Ports          []string     // ports published on the host, [[<ip>:]<host port>:]<container port>[/<protocol>]
+	Runtime        string       // OCI runtime of the container, like runsc (gVisor) or kata, the default runtime of the engine if empty
+	CreateHooks    []CreateHook // change the request creating the container, in order
+}
+
+// Labels of the containers act reuses with --reuse
+const (
+	LabelReuseKey = "com.github.nektos.act.reuse-key" // hash of the workdir, workflow, job and matrix of the container
+	LabelConfig   = "com.github.nektos.act.config"    // hash of the configuration the container was created with
+	LabelWorkflow = "com.github.nektos.act.workflow"
+	LabelJob      = "com.github.nektos.act.job"
+	LabelWorkdir  = "com.github.nektos.act.workdir"
+	LabelBranch   = "com.github.nektos.act.branch" // the branch of the runs of --watch, missing on the default branch
+)
+
+// ReusableContainer is a container kept by --reuse
+type ReusableContainer struct {
+	ID       string
+	Name     string
+	Key      string
+	Workflow string
+	Job      string
+	Workdir  string
+	Branch   string
+	Image    string
+	State    string
+	Created  time.Time
+}
+
+// Labels of the cache volumes managed by act
+const (
+	LabelCacheVolume = "com.github.nektos.act.cache-volume" // name of the cache
+	LabelCachePath   = "com.github.nektos.act.cache-path"   // path the volume was created for in the job containers
+)
+
+// CacheVolume is a volume keeping a language cache (e.g. the go build cache) across the job containers
+type CacheVolume struct {
+	Name    string // name of the cache, the volume is named CacheVolumeName(Name)
+	Volume  string
+	Path    string
+	Created time.Time
+}
+
+// CacheVolumeName returns the name of the volume of the cache
+func CacheVolumeName(name string) string {
+	return "act-cache-" + name
+}
+
+// HostAddress is how the containers reach the host of the container engine
+type HostAddress struct {
+	Host       string   // name of the host inside the containers
+	ExtraHosts []string // host:ip entries the containers need to resolve Host
+	Loopback   bool     // whether the loopback interface of the host is reachable through Host, as with Docker Desktop
 }
 
 // FileEntry is a file to copy to a container
@@ -35,6 +96,10 @@ type FileEntry struct {
 	Body string
 }
 
+// ErrCommandNotFound is wrapped by the errors of the commands exiting with 127, the shell of the step or a command of
+// its script isn't installed
+var ErrCommandNotFound = errors.New("command not found")
+
 // Container for managing docker run containers
 type Container interface {
 	Create(capAdd []string, capDrop []string) common.Executor
@@ -52,6 +117,23 @@ type Container interface {
 	ReplaceLogWriter(io.Writer, io.Writer) (io.Writer, io.Writer)
 }
 
+// ProcessSampler samples the processes of a container, to tell the steps which hang from the ones which are busy
+type ProcessSampler interface {
+	// CPUTicks returns the CPU time used by the processes of the container in clock ticks
+	CPUTicks(ctx context.Context) (int64, error)
+	// ProcessDump returns a listing of the processes of the container with their state and kernel stack
+	ProcessDump(ctx context.Context) (string, error)
+}
+
+// InteractiveShell opens the interactive shells of --step-debug in a container
+type InteractiveShell interface {
+	// Shell runs an interactive shell with the env in the working directory, attached to the terminal of act
+	Shell(ctx context.Context, env map[string]string, workdir string) error
+}
+
+// interactiveShellScript runs bash if the container has it, sh otherwise
+const interactiveShellScript = "if command -v bash >/dev/null 2>&1; then exec bash; fi; exec sh"
+
 // NewDockerBuildExecutorInput the input for the NewDockerBuildExecutor function
 type NewDockerBuildExecutorInput struct {
 	ContextDir string
@@ -60,6 +142,27 @@ type NewDockerBuildExecutorInput struct {
 	Platform   string
 }
 
+// NewDockerComposeExecutorInput the input for the docker compose executors
+type NewDockerComposeExecutorInput struct {
+	File    string
+	Project string
+}
+
+// ComposeNetworkName returns the name of the default network created for a docker-compose project
+func (input NewDockerComposeExecutorInput) ComposeNetworkName() string {
+	return fmt.Sprintf("%s_default", input.Project)
+}
+
+// ServiceContainer is a running container of a service
+type ServiceContainer struct {
+	ID      string
+	Network string
+	Ports   map[string]string // published ports of the container by container port
+}
+
+// LocalRegistryImage is the image of the local registry the images act builds are pushed to
+const LocalRegistryImage = "registry:2"
+
 // NewDockerPullExecutorInput the input for the NewDockerPullExecutor function
 type NewDockerPullExecutorInput struct {
 	Image     string
@@ -0,0 +1,93 @@
+package container
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+)
+
+// CreateRequest is the request creating a container with the bodies of the Docker engine API, the hooks of
+// NewContainerInput.CreateHooks change it before it's sent
+type CreateRequest struct {
+	Name             string                    `json:"-"`
+	Config           *container.Config         `json:"Config"`
+	HostConfig       *container.HostConfig     `json:"HostConfig"`
+	NetworkingConfig *network.NetworkingConfig `json:"NetworkingConfig"`
+}
+
+// CreateHook changes the request creating a container, like the runtime, the devices or the annotations a sandboxed
+// runtime needs
+type CreateHook func(ctx context.Context, req *CreateRequest) error
+
+// NewCreatePatchHook returns a hook applying a JSON merge patch (RFC 7396) to the request, an object with the
+// Config, HostConfig and NetworkingConfig of the Docker engine API, e.g. {"HostConfig": {"Runtime": "runsc"}}.
+// A null removes the field.
+func NewCreatePatchHook(patch []byte) (CreateHook, error) {
+	var parsed map[string]interface{}
+	if err := json.Unmarshal(patch, &parsed); err != nil {
+		return nil, fmt.Errorf("the patch isn't a JSON object: %w", err)
+	}
+	for key := range parsed {
+		if key != "Config" && key != "HostConfig" && key != "NetworkingConfig" {
+			return nil, fmt.Errorf("the patch changes '%s', expected Config, HostConfig or NetworkingConfig", key)
+		}
+	}
+	return func(ctx context.Context, req *CreateRequest) error {
+		content, err := json.Marshal(req)
+		if err != nil {
+			return err
+		}
+		var target interface{}
+		if err := json.Unmarshal(content, &target); err != nil {
+			return err
+		}
+		content, err = json.Marshal(mergePatch(target, parsed))
+		if err != nil {
+			return err
+		}
+		patched := &CreateRequest{Name: req.Name}
+		if err := json.Unmarshal(content, patched); err != nil {
+			return fmt.Errorf("the patched request of the container %s is invalid: %w", req.Name, err)
+		}
+		*req = *patched
+		return nil
+	}, nil
+}
+
+// ReadCreatePatchHook returns the hook applying the JSON merge patch of the file, see NewCreatePatchHook
+func ReadCreatePatchHook(file string) (CreateHook, error) {
+	patch, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	hook, err := NewCreatePatchHook(patch)
+	if err != nil {
+		return nil, fmt.Errorf("invalid container create patch %s: %w", file, err)
+	}
+	return hook, nil
+}
+
+// mergePatch merges the patch into the target like RFC 7396: the objects are merged, a null removes the member and
+// any other value replaces the target
+func mergePatch(target interface{}, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	}
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+			continue
+		}
+		targetObject[key] = mergePatch(targetObject[key], value)
+	}
+	return targetObject
+}
@@ -0,0 +1,39 @@
+package container
+
+import (
+	"context"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreatePatchHook(t *testing.T) {
+	hook, err := NewCreatePatchHook([]byte(`{
+  "Config": {"Labels": {"sandbox": "gvisor", "drop": null}},
+  "HostConfig": {"Runtime": "runsc", "Privileged": false, "Memory": 536870912, "Devices": [{"PathOnHost": "/dev/kvm", "PathInContainer": "/dev/kvm", "CgroupPermissions": "rwm"}]}
+}`))
+	assert.NoError(t, err)
+
+	req := &CreateRequest{
+		Name:       "act-test-build",
+		Config:     &container.Config{Image: "node:16", Env: []string{"A=1"}, Labels: map[string]string{"drop": "x", "keep": "y"}},
+		HostConfig: &container.HostConfig{Privileged: true, Binds: []string{"/src:/src"}},
+	}
+	assert.NoError(t, hook(context.Background(), req))
+	assert.Equal(t, "act-test-build", req.Name)
+	assert.Equal(t, "node:16", req.Config.Image)
+	assert.Equal(t, []string{"A=1"}, req.Config.Env)
+	assert.Equal(t, map[string]string{"sandbox": "gvisor", "keep": "y"}, req.Config.Labels)
+	assert.Equal(t, "runsc", req.HostConfig.Runtime)
+	assert.False(t, req.HostConfig.Privileged)
+	assert.Equal(t, int64(536870912), req.HostConfig.Memory)
+	assert.Equal(t, []string{"/src:/src"}, req.HostConfig.Binds)
+	assert.Equal(t, "/dev/kvm", req.HostConfig.Devices[0].PathOnHost)
+	assert.Nil(t, req.NetworkingConfig)
+
+	_, err = NewCreatePatchHook([]byte(`{"Name": "other"}`))
+	assert.EqualError(t, err, "the patch changes 'Name', expected Config, HostConfig or NetworkingConfig")
+	_, err = NewCreatePatchHook([]byte(`[]`))
+	assert.Error(t, err)
+}
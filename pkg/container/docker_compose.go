@@ -0,0 +1,70 @@
+//go:build !(WITHOUT_DOCKER || !(linux || darwin || windows))
+
+package container
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/nektos/act/pkg/common"
+)
+
+// NewDockerComposeUpExecutor starts all services of a docker-compose project in the background
+func NewDockerComposeUpExecutor(input NewDockerComposeExecutorInput) common.Executor {
+	return func(ctx context.Context) error {
+		common.Logger(ctx).Infof("%sdocker compose -f %s -p %s up -d", logPrefix, input.File, input.Project)
+		if common.Dryrun(ctx) {
+			return nil
+		}
+		return runDockerCompose(ctx, input, "up", "-d")
+	}
+}
+
+// NewDockerComposeDownExecutor stops and removes all services, networks and anonymous volumes of a docker-compose project
+func NewDockerComposeDownExecutor(input NewDockerComposeExecutorInput) common.Executor {
+	return func(ctx context.Context) error {
+		common.Logger(ctx).Infof("%sdocker compose -f %s -p %s down", logPrefix, input.File, input.Project)
+		if common.Dryrun(ctx) {
+			return nil
+		}
+		return runDockerCompose(ctx, input, "down", "--volumes", "--remove-orphans")
+	}
+}
+
+func runDockerCompose(ctx context.Context, input NewDockerComposeExecutorInput, args ...string) error {
+	logger := common.Logger(ctx)
+
+	// prefer the compose command of the cli of the backend, fall back to the standalone binary
+	name := BackendFrom(ctx).cli()
+	cmdArgs := []string{"compose"}
+	if err := dockerCommand(ctx, name, "compose", "version").Run(); err != nil {
+		name += "-compose"
+		cmdArgs = []string{}
+	}
+	cmdArgs = append(cmdArgs, "-f", input.File, "-p", input.Project)
+	cmdArgs = append(cmdArgs, args...)
+
+	logger.Debugf("Running %s %s", name, strings.Join(cmdArgs, " "))
+	out, err := dockerCommand(ctx, name, cmdArgs...).CombinedOutput()
+	logger.Debugf("%s", out)
+	if err != nil {
+		return fmt.Errorf("failed to run '%s %s': %w\n%s", name, strings.Join(cmdArgs, " "), err, out)
+	}
+	return nil
+}
+
+// dockerCommand runs a command of the docker cli against the docker host of the context, the podman cli connects to
+// CONTAINER_HOST and runs a compose provider which connects to DOCKER_HOST
+func dockerCommand(ctx context.Context, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	if host := DockerHost(ctx); host != "" {
+		cmd.Env = append(os.Environ(), "DOCKER_HOST="+host)
+		if BackendFrom(ctx) == BackendPodman {
+			cmd.Env = append(cmd.Env, "CONTAINER_HOST="+host)
+		}
+	}
+	return cmd
+}
@@ -0,0 +1,27 @@
+package container
+
+import (
+	"context"
+	"os"
+)
+
+type dockerHostContextKey string
+
+const dockerHostContextKeyVal = dockerHostContextKey("docker.host")
+
+// WithDockerHost returns a context whose docker clients connect to host instead of DOCKER_HOST
+func WithDockerHost(ctx context.Context, host string) context.Context {
+	return context.WithValue(ctx, dockerHostContextKeyVal, host)
+}
+
+// DockerHost returns the docker host of the context, DOCKER_HOST if the context has none or the API service of podman
+// with the podman backend
+func DockerHost(ctx context.Context) string {
+	if host, ok := ctx.Value(dockerHostContextKeyVal).(string); ok && host != "" {
+		return host
+	}
+	if BackendFrom(ctx) == BackendPodman {
+		return podmanHost()
+	}
+	return os.Getenv("DOCKER_HOST")
+}
@@ -4,10 +4,14 @@ package container
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/client"
+
+	"github.com/nektos/act/pkg/common"
 )
 
 // ImageExistsLocally returns a boolean indicating if an image with the
@@ -58,3 +62,40 @@ func RemoveImage(ctx context.Context, imageName string, force bool, pruneChildre
 
 	return true, nil
 }
+
+// ImageDigest resolves the image in its registry to the digest of its manifest (list), which is the same for all
+// architectures of the image
+func ImageDigest(ctx context.Context, imageName string) (string, error) {
+	cli, err := GetDockerClient(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer cli.Close()
+
+	authConfig, err := LoadDockerAuthConfig(ctx, imageName)
+	if err != nil {
+		return "", err
+	}
+	encodedAuth := ""
+	if authConfig.Username != "" || authConfig.Password != "" {
+		encodedJSON, err := json.Marshal(authConfig)
+		if err != nil {
+			return "", err
+		}
+		encodedAuth = base64.URLEncoding.EncodeToString(encodedJSON)
+	}
+
+	var digest string
+	err = common.DefaultBackoff.Retry(ctx, fmt.Sprintf("docker manifest inspect %s", imageName), func(ctx context.Context) error {
+		inspect, err := cli.DistributionInspect(ctx, cleanImage(ctx, imageName), encodedAuth)
+		if err != nil {
+			return pullError(err)
+		}
+		digest = inspect.Descriptor.Digest.String()
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("unable to resolve the digest of image '%s': %w", imageName, err)
+	}
+	return digest, nil
+}
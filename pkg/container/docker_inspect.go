@@ -0,0 +1,59 @@
+//go:build !(WITHOUT_DOCKER || !(linux || darwin || windows))
+
+package container
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+)
+
+// GetContainerID returns the id of the container with the given name
+func GetContainerID(ctx context.Context, name string) (string, error) {
+	cli, err := GetDockerClient(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer cli.Close()
+
+	inspect, err := cli.ContainerInspect(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	return inspect.ID, nil
+}
+
+// GetComposeServices returns the running containers of the services of a docker-compose project by service name
+func GetComposeServices(ctx context.Context, input NewDockerComposeExecutorInput) (map[string]*ServiceContainer, error) {
+	cli, err := GetDockerClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cli.Close()
+
+	containers, err := cli.ContainerList(ctx, types.ContainerListOptions{
+		Filters: filters.NewArgs(filters.Arg("label", fmt.Sprintf("com.docker.compose.project=%s", input.Project))),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	services := map[string]*ServiceContainer{}
+	for _, c := range containers {
+		service := &ServiceContainer{
+			ID:      c.ID,
+			Network: input.ComposeNetworkName(),
+			Ports:   map[string]string{},
+		}
+		for _, port := range c.Ports {
+			if port.PublicPort != 0 {
+				service.Ports[strconv.Itoa(int(port.PrivatePort))] = strconv.Itoa(int(port.PublicPort))
+			}
+		}
+		services[c.Labels["com.docker.compose.service"]] = service
+	}
+	return services, nil
+}
@@ -0,0 +1,161 @@
+//go:build !(WITHOUT_DOCKER || !(linux || darwin || windows))
+
+package container
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/nektos/act/pkg/common"
+)
+
+// NewDockerNetworkCreateExecutor creates the bridge network of the service containers of a job unless it exists
+func NewDockerNetworkCreateExecutor(name string) common.Executor {
+	return func(ctx context.Context) error {
+		logger := common.Logger(ctx)
+		logger.Debugf("%sdocker network create %s", logPrefix, name)
+
+		if common.Dryrun(ctx) {
+			return nil
+		}
+
+		cli, err := GetDockerClient(ctx)
+		if err != nil {
+			return err
+		}
+		defer cli.Close()
+
+		networks, err := cli.NetworkList(ctx, types.NetworkListOptions{
+			Filters: filters.NewArgs(filters.Arg("name", name)),
+		})
+		if err != nil {
+			return err
+		}
+		for _, n := range networks {
+			// the filter matches on a part of the name
+			if n.Name == name {
+				return nil
+			}
+		}
+
+		_, err = cli.NetworkCreate(ctx, name, types.NetworkCreate{
+			CheckDuplicate: true,
+			Driver:         "bridge",
+		})
+		return err
+	}
+}
+
+// NewDockerNetworkRemoveExecutor removes the network of the service containers of a job, a missing network is not
+// an error
+func NewDockerNetworkRemoveExecutor(name string) common.Executor {
+	return func(ctx context.Context) error {
+		logger := common.Logger(ctx)
+		logger.Debugf("%sdocker network rm %s", logPrefix, name)
+
+		if common.Dryrun(ctx) {
+			return nil
+		}
+
+		cli, err := GetDockerClient(ctx)
+		if err != nil {
+			return err
+		}
+		defer cli.Close()
+
+		if err := cli.NetworkRemove(ctx, name); err != nil && !strings.Contains(err.Error(), "not found") {
+			return err
+		}
+		return nil
+	}
+}
+
+// NewDockerHealthyExecutor waits until the health check of the container passes. A container without a health check
+// is healthy once it runs.
+func NewDockerHealthyExecutor(name string, timeout time.Duration) common.Executor {
+	return func(ctx context.Context) error {
+		logger := common.Logger(ctx)
+		if common.Dryrun(ctx) {
+			return nil
+		}
+
+		cli, err := GetDockerClient(ctx)
+		if err != nil {
+			return err
+		}
+		defer cli.Close()
+
+		deadline := time.Now().Add(timeout)
+		for wait := 500 * time.Millisecond; ; {
+			inspect, err := cli.ContainerInspect(ctx, name)
+			if err != nil {
+				return err
+			}
+			state := inspect.State
+			if state == nil || !state.Running {
+				return fmt.Errorf("the container %s exited", name)
+			}
+			if state.Health == nil || state.Health.Status == types.Healthy {
+				return nil
+			}
+			if state.Health.Status == types.Unhealthy {
+				return fmt.Errorf("the container %s is unhealthy%s", name, lastHealthcheckOutput(state.Health))
+			}
+			if time.Now().After(deadline) {
+				return fmt.Errorf("the container %s is not healthy after %s%s", name, timeout, lastHealthcheckOutput(state.Health))
+			}
+
+			logger.Debugf("Waiting for the health check of the container %s", name)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(wait):
+			}
+			if wait < 5*time.Second {
+				wait *= 2
+			}
+		}
+	}
+}
+
+func lastHealthcheckOutput(health *types.Health) string {
+	if len(health.Log) == 0 {
+		return ""
+	}
+	return ": " + strings.TrimSpace(health.Log[len(health.Log)-1].Output)
+}
+
+// GetServiceContainer returns the running container of a service with the ports it publishes on the host
+func GetServiceContainer(ctx context.Context, name string, network string) (*ServiceContainer, error) {
+	cli, err := GetDockerClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cli.Close()
+
+	inspect, err := cli.ContainerInspect(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	service := &ServiceContainer{
+		ID:      inspect.ID,
+		Network: network,
+		Ports:   map[string]string{},
+	}
+	if inspect.NetworkSettings != nil {
+		for port, bindings := range inspect.NetworkSettings.Ports {
+			for _, binding := range bindings {
+				if binding.HostPort != "" {
+					service.Ports[port.Port()] = binding.HostPort
+					break
+				}
+			}
+		}
+	}
+	return service, nil
+}
@@ -6,10 +6,13 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/docker/distribution/reference"
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/errdefs"
 
 	"github.com/nektos/act/pkg/common"
 )
@@ -55,14 +58,42 @@ func NewDockerPullExecutor(input NewDockerPullExecutorInput) common.Executor {
 			return err
 		}
 
-		reader, err := cli.ImagePull(ctx, imageRef, imagePullOptions)
-
-		_ = logDockerResponse(logger, reader, err != nil)
-		if err != nil {
-			return err
+		// a failure reported while the image is pulled doesn't fail the job, the image may be present already
+		var streamErr error
+		err = common.DefaultBackoff.Retry(ctx, fmt.Sprintf("docker pull %s", imageRef), func(ctx context.Context) error {
+			reader, err := cli.ImagePull(ctx, imageRef, imagePullOptions)
+			if err != nil {
+				_ = logDockerResponse(logger, reader, true)
+				return pullError(err)
+			}
+			streamErr = logDockerResponse(logger, reader, false)
+			if isRateLimited(streamErr) {
+				return &common.TemporaryError{Err: streamErr, RateLimited: true}
+			}
+			return nil
+		})
+		if err != nil && streamErr != nil && errors.Is(err, streamErr) {
+			logger.Warnf("Unable to pull image '%s': %v", imageRef, streamErr)
+			return nil
 		}
-		return nil
+		return err
+	}
+}
+
+// pullError marks the failures of a pull which are retried
+func pullError(err error) error {
+	if isRateLimited(err) {
+		return &common.TemporaryError{Err: err, RateLimited: true}
 	}
+	if errdefs.IsUnavailable(err) || common.IsTemporaryNetError(err) {
+		return &common.TemporaryError{Err: err}
+	}
+	return err
+}
+
+// isRateLimited reports if the registry rejected the pull because of its rate limit
+func isRateLimited(err error) bool {
+	return err != nil && (strings.Contains(err.Error(), "toomanyrequests") || strings.Contains(strings.ToLower(err.Error()), "rate limit"))
 }
 
 func getImagePullOptions(ctx context.Context, input NewDockerPullExecutorInput) (types.ImagePullOptions, error) {
@@ -2,12 +2,16 @@ package container
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/docker/cli/cli/config"
+	"github.com/docker/docker/errdefs"
 
 	log "github.com/sirupsen/logrus"
 	assert "github.com/stretchr/testify/assert"
+
+	"github.com/nektos/act/pkg/common"
 )
 
 func init() {
@@ -59,3 +63,18 @@ func TestGetImagePullOptions(t *testing.T) {
 	assert.Nil(t, err, "Failed to create ImagePullOptions")
 	assert.Equal(t, "eyJ1c2VybmFtZSI6InVzZXJuYW1lIiwicGFzc3dvcmQiOiJwYXNzd29yZFxuIiwic2VydmVyYWRkcmVzcyI6Imh0dHBzOi8vaW5kZXguZG9ja2VyLmlvL3YxLyJ9", options.RegistryAuth, "RegistryAuth should be taken from local docker config")
 }
+
+func TestPullError(t *testing.T) {
+	var temporary *common.TemporaryError
+
+	err := pullError(errors.New("toomanyrequests: You have reached your pull rate limit"))
+	assert.ErrorAs(t, err, &temporary)
+	assert.True(t, temporary.RateLimited)
+
+	err = pullError(errdefs.Unavailable(errors.New("registry unavailable")))
+	assert.ErrorAs(t, err, &temporary)
+	assert.False(t, temporary.RateLimited)
+
+	err = pullError(errors.New("manifest unknown"))
+	assert.False(t, errors.As(err, &temporary))
+}
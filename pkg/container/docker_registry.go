@@ -0,0 +1,111 @@
+//go:build !(WITHOUT_DOCKER || !(linux || darwin || windows))
+
+package container
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+	"github.com/docker/go-connections/nat"
+
+	"github.com/nektos/act/pkg/common"
+)
+
+// NewDockerRegistryStartExecutor starts a throwaway registry publishing its port on port of the host, the registry
+// keeps the images in the container and loses them when it is removed
+func NewDockerRegistryStartExecutor(name string, port string) common.Executor {
+	return func(ctx context.Context) error {
+		logger := common.Logger(ctx)
+		logger.Infof("%sdocker run -d --name %s -p %s:5000 %s", logPrefix, name, port, LocalRegistryImage)
+		if common.Dryrun(ctx) {
+			return nil
+		}
+
+		if err := NewDockerPullExecutor(NewDockerPullExecutorInput{Image: LocalRegistryImage})(ctx); err != nil {
+			return err
+		}
+
+		cli, err := GetDockerClient(ctx)
+		if err != nil {
+			return err
+		}
+		defer cli.Close()
+
+		// a registry left by a run which was killed is replaced
+		if err := cli.ContainerRemove(ctx, name, types.ContainerRemoveOptions{Force: true, RemoveVolumes: true}); err != nil && !client.IsErrNotFound(err) {
+			return err
+		}
+
+		resp, err := cli.ContainerCreate(ctx, &container.Config{
+			Image:        LocalRegistryImage,
+			ExposedPorts: nat.PortSet{"5000/tcp": {}},
+			Healthcheck: &container.HealthConfig{
+				Test:     []string{"CMD", "wget", "-q", "--spider", "http://localhost:5000/v2/"},
+				Interval: 500 * time.Millisecond,
+				Retries:  20,
+			},
+		}, &container.HostConfig{
+			PortBindings: nat.PortMap{"5000/tcp": []nat.PortBinding{{HostPort: port}}},
+		}, nil, nil, name)
+		if err != nil {
+			return fmt.Errorf("unable to create the local registry: %w", err)
+		}
+		if err := cli.ContainerStart(ctx, resp.ID, types.ContainerStartOptions{}); err != nil {
+			return fmt.Errorf("unable to start the local registry: %w", err)
+		}
+		return NewDockerHealthyExecutor(name, time.Minute)(ctx)
+	}
+}
+
+// NewDockerRegistryRemoveExecutor removes the registry with the images pushed to it
+func NewDockerRegistryRemoveExecutor(name string) common.Executor {
+	return func(ctx context.Context) error {
+		logger := common.Logger(ctx)
+		logger.Debugf("%sdocker rm -f -v %s", logPrefix, name)
+		if common.Dryrun(ctx) {
+			return nil
+		}
+
+		cli, err := GetDockerClient(ctx)
+		if err != nil {
+			return err
+		}
+		defer cli.Close()
+
+		if err := cli.ContainerRemove(ctx, name, types.ContainerRemoveOptions{Force: true, RemoveVolumes: true}); err != nil && !client.IsErrNotFound(err) {
+			return err
+		}
+		return nil
+	}
+}
+
+// NewDockerPushExecutor tags the image as ref, an image of a registry, and pushes it to the registry
+func NewDockerPushExecutor(image string, ref string) common.Executor {
+	return func(ctx context.Context) error {
+		logger := common.Logger(ctx)
+		logger.Infof("%sdocker push %s", logPrefix, ref)
+		if common.Dryrun(ctx) {
+			return nil
+		}
+
+		cli, err := GetDockerClient(ctx)
+		if err != nil {
+			return err
+		}
+		defer cli.Close()
+
+		if err := cli.ImageTag(ctx, image, ref); err != nil {
+			return err
+		}
+		// the local registry has no authentication, the daemon requires credentials anyway
+		reader, err := cli.ImagePush(ctx, ref, types.ImagePushOptions{RegistryAuth: "e30="})
+		if err != nil {
+			return err
+		}
+		return logDockerResponse(logger, reader, false)
+	}
+}
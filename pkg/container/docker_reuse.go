@@ -0,0 +1,115 @@
+//go:build !(WITHOUT_DOCKER || !(linux || darwin || windows))
+
+package container
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+
+	"github.com/nektos/act/pkg/common"
+)
+
+// configHash returns the hash of the configuration of the container, a reused container is recreated if it changed
+func configHash(input *NewContainerInput, capAdd []string, capDrop []string) string {
+	mounts := make([]string, 0, len(input.Mounts))
+	for source, target := range input.Mounts {
+		mounts = append(mounts, source+":"+target)
+	}
+	sort.Strings(mounts)
+
+	values := []interface{}{
+		input.Image,
+		input.Entrypoint,
+		input.Cmd,
+		input.WorkingDir,
+		input.Env,
+		input.Binds,
+		mounts,
+		input.NetworkMode,
+		input.Privileged,
+		input.UsernsMode,
+		input.Platform,
+		input.Options,
+		input.ExtraHosts,
+		input.User,
+		input.NetworkAliases,
+		input.Ports,
+		capAdd,
+		capDrop,
+	}
+	// the containers created before the runtime was configurable keep their hash
+	if input.Runtime != "" {
+		values = append(values, input.Runtime)
+	}
+	b, _ := json.Marshal(values)
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// removeIfStale removes the existing container if it was created with another configuration, e.g. another image
+func (cr *containerReference) removeIfStale(capAdd []string, capDrop []string) common.Executor {
+	return func(ctx context.Context) error {
+		if cr.id == "" {
+			return nil
+		}
+		inspect, err := cr.cli.ContainerInspect(ctx, cr.id)
+		if err != nil {
+			return err
+		}
+		if inspect.Config != nil && inspect.Config.Labels[LabelConfig] == configHash(cr.input, capAdd, capDrop) {
+			common.Logger(ctx).Debugf("Reusing container %s", cr.input.Name)
+			return nil
+		}
+		common.Logger(ctx).Infof("Recreating container %s, its configuration changed", cr.input.Name)
+		return cr.remove()(ctx)
+	}
+}
+
+// ListReusableContainers returns the containers kept by --reuse
+func ListReusableContainers(ctx context.Context) ([]*ReusableContainer, error) {
+	cli, err := GetDockerClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cli.Close()
+
+	containers, err := cli.ContainerList(ctx, types.ContainerListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("label", LabelReuseKey)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	reusable := make([]*ReusableContainer, 0, len(containers))
+	for _, c := range containers {
+		name := ""
+		if len(c.Names) > 0 {
+			name = strings.TrimPrefix(c.Names[0], "/")
+		}
+		reusable = append(reusable, &ReusableContainer{
+			ID:       c.ID,
+			Name:     name,
+			Key:      c.Labels[LabelReuseKey],
+			Workflow: c.Labels[LabelWorkflow],
+			Job:      c.Labels[LabelJob],
+			Workdir:  c.Labels[LabelWorkdir],
+			Branch:   c.Labels[LabelBranch],
+			Image:    c.Image,
+			State:    c.State,
+			Created:  time.Unix(c.Created, 0),
+		})
+	}
+	sort.Slice(reusable, func(i, j int) bool {
+		return reusable[i].Created.After(reusable[j].Created)
+	})
+	return reusable, nil
+}
@@ -16,6 +16,7 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-git/go-billy/v5/helper/polyfill"
 	"github.com/go-git/go-billy/v5/osfs"
@@ -30,8 +31,10 @@ import (
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/client"
 	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/docker/go-connections/nat"
 	specs "github.com/opencontainers/image-spec/specs-go/v1"
 
 	"github.com/Masterminds/semver"
@@ -72,6 +75,7 @@ func (cr *containerReference) Create(capAdd []string, capDrop []string) common.E
 			common.NewPipelineExecutor(
 				cr.connect(),
 				cr.find(),
+				cr.removeIfStale(capAdd, capDrop),
 				cr.create(capAdd, capDrop),
 			).IfNot(common.Dryrun),
 		)
@@ -92,10 +96,11 @@ func (cr *containerReference) Start(attach bool) common.Executor {
 				func(ctx context.Context) error {
 					// If this fails, then folders have wrong permissions on non root container
 					if cr.UID != 0 || cr.GID != 0 {
-						_ = cr.Exec([]string{"chown", "-R", fmt.Sprintf("%d:%d", cr.UID, cr.GID), cr.input.WorkingDir}, nil, "0", "")(ctx)
+						_ = cr.Exec([]string{"chown", "-R", fmt.Sprintf("%d:%d", cr.UID, cr.GID), cr.input.WorkingDir, cr.GetActPath()}, nil, "0", "")(ctx)
 					}
 					return nil
 				},
+				cr.setupHome(),
 			).IfNot(common.Dryrun),
 		)
 }
@@ -190,6 +195,7 @@ type containerReference struct {
 	input *NewContainerInput
 	UID   int
 	GID   int
+	home  string // HOME of the commands when the one of the user isn't writable
 	LinuxContainerEnvironmentExtensions
 }
 
@@ -197,7 +203,12 @@ func GetDockerClient(ctx context.Context) (cli client.APIClient, err error) {
 	// TODO: this should maybe need to be a global option, not hidden in here?
 	//       though i'm not sure how that works out when there's another Executor :D
 	//		 I really would like something that works on OSX native for eg
-	dockerHost := os.Getenv("DOCKER_HOST")
+	dockerHost := DockerHost(ctx)
+	if BackendFrom(ctx) == BackendPodman {
+		if err := checkPodmanHost(dockerHost); err != nil {
+			return nil, err
+		}
+	}
 
 	if strings.HasPrefix(dockerHost, "ssh://") {
 		var helper *connhelper.ConnectionHelper
@@ -210,6 +221,8 @@ func GetDockerClient(ctx context.Context) (cli client.APIClient, err error) {
 			client.WithHost(helper.Host),
 			client.WithDialContext(helper.Dialer),
 		)
+	} else if dockerHost != "" {
+		cli, err = client.NewClientWithOpts(client.FromEnv, client.WithHost(dockerHost))
 	} else {
 		cli, err = client.NewClientWithOpts(client.FromEnv)
 	}
@@ -370,14 +383,17 @@ func (cr *containerReference) mergeContainerConfigs(ctx context.Context, config
 
 	hostConfig.Binds = append(hostConfig.Binds, containerConfig.HostConfig.Binds...)
 	hostConfig.Mounts = append(hostConfig.Mounts, containerConfig.HostConfig.Mounts...)
+	hostConfig.ExtraHosts = append(hostConfig.ExtraHosts, containerConfig.HostConfig.ExtraHosts...)
 	binds := hostConfig.Binds
 	mounts := hostConfig.Mounts
+	extraHosts := hostConfig.ExtraHosts
 	err = mergo.Merge(hostConfig, containerConfig.HostConfig, mergo.WithOverride)
 	if err != nil {
 		return nil, nil, fmt.Errorf("Cannot merge container.HostConfig options: '%s': '%w'", input.Options, err)
 	}
 	hostConfig.Binds = binds
 	hostConfig.Mounts = mounts
+	hostConfig.ExtraHosts = extraHosts
 	logger.Debugf("Merged container.HostConfig ==> %+v", hostConfig)
 
 	return config, hostConfig, nil
@@ -392,11 +408,18 @@ func (cr *containerReference) create(capAdd []string, capDrop []string) common.E
 		isTerminal := term.IsTerminal(int(os.Stdout.Fd()))
 		input := cr.input
 
+		labels := map[string]string{LabelConfig: configHash(input, capAdd, capDrop)}
+		for k, v := range input.Labels {
+			labels[k] = v
+		}
+
 		config := &container.Config{
 			Image:      input.Image,
+			User:       input.User,
 			WorkingDir: input.WorkingDir,
 			Env:        input.Env,
 			Tty:        isTerminal,
+			Labels:     labels,
 		}
 		logger.Debugf("Common container.Config ==> %+v", config)
 
@@ -439,15 +462,46 @@ func (cr *containerReference) create(capAdd []string, capDrop []string) common.E
 			NetworkMode: container.NetworkMode(input.NetworkMode),
 			Privileged:  input.Privileged,
 			UsernsMode:  container.UsernsMode(input.UsernsMode),
+			ExtraHosts:  input.ExtraHosts,
+		}
+		if len(input.Ports) > 0 {
+			exposedPorts, portBindings, err := nat.ParsePortSpecs(input.Ports)
+			if err != nil {
+				return fmt.Errorf("invalid ports %v: %w", input.Ports, err)
+			}
+			config.ExposedPorts, hostConfig.PortBindings = exposedPorts, portBindings
 		}
 		logger.Debugf("Common container.HostConfig ==> %+v", hostConfig)
 
+		var networkingConfig *network.NetworkingConfig
+		if len(input.NetworkAliases) > 0 {
+			networkingConfig = &network.NetworkingConfig{
+				EndpointsConfig: map[string]*network.EndpointSettings{
+					input.NetworkMode: {Aliases: input.NetworkAliases},
+				},
+			}
+		}
+
 		config, hostConfig, err := cr.mergeContainerConfigs(ctx, config, hostConfig)
 		if err != nil {
 			return err
 		}
+		if input.Runtime != "" {
+			hostConfig.Runtime = input.Runtime
+		}
+
+		req := &CreateRequest{Name: input.Name, Config: config, HostConfig: hostConfig, NetworkingConfig: networkingConfig}
+		for _, hook := range input.CreateHooks {
+			if err := hook(ctx, req); err != nil {
+				return fmt.Errorf("the create hook of the container %s failed: %w", input.Name, err)
+			}
+		}
+		if len(input.CreateHooks) > 0 {
+			logger.Debugf("Hooked container.Config ==> %+v", req.Config)
+			logger.Debugf("Hooked container.HostConfig ==> %+v", req.HostConfig)
+		}
 
-		resp, err := cr.cli.ContainerCreate(ctx, config, hostConfig, nil, platSpecs, input.Name)
+		resp, err := cr.cli.ContainerCreate(ctx, req.Config, req.HostConfig, req.NetworkingConfig, platSpecs, input.Name)
 		if err != nil {
 			return fmt.Errorf("failed to create container: '%w'", err)
 		}
@@ -535,6 +589,12 @@ func (cr *containerReference) exec(cmd []string, env map[string]string, user, wo
 		for k, v := range env {
 			envList = append(envList, fmt.Sprintf("%s=%s", k, v))
 		}
+		if _, ok := env["HOME"]; !ok && cr.home != "" && user == "" {
+			envList = append(envList, "HOME="+cr.home)
+		}
+		// the processes of the command inherit the marker, they are killed by it if the command outlives its context
+		marker := strconv.FormatInt(time.Now().UnixNano(), 36)
+		envList = append(envList, execMarkerEnv+"="+marker)
 
 		var wd string
 		if workdir != "" {
@@ -571,6 +631,9 @@ func (cr *containerReference) exec(cmd []string, env map[string]string, user, wo
 
 		err = cr.waitForCommand(ctx, isTerminal, resp, idResp, user, workdir)
 		if err != nil {
+			if ctx.Err() != nil {
+				cr.killExec(ctx, marker)
+			}
 			return err
 		}
 
@@ -583,13 +646,158 @@ func (cr *containerReference) exec(cmd []string, env map[string]string, user, wo
 		case 0:
 			return nil
 		case 127:
-			return fmt.Errorf("exitcode '%d': command not found, please refer to https://github.com/nektos/act/issues/107 for more information", inspectResp.ExitCode)
+			return fmt.Errorf("exitcode '%d': %w, please refer to https://github.com/nektos/act/issues/107 for more information", inspectResp.ExitCode, ErrCommandNotFound)
 		default:
 			return fmt.Errorf("exitcode '%d': failure", inspectResp.ExitCode)
 		}
 	}
 }
 
+// execMarkerEnv is the variable marking the processes of an exec, the docker API can't signal the processes of an exec
+const execMarkerEnv = "ACT_EXEC_MARKER"
+
+// killExecScript returns the script killing the processes of the container whose environment has the marker
+func killExecScript(marker string) string {
+	return fmt.Sprintf(`for p in /proc/[0-9]*; do if tr '\0' '\n' 2>/dev/null < "$p/environ" | grep -qxF '%s=%s'; then kill -KILL "${p#/proc/}" 2>/dev/null; fi; done`, execMarkerEnv, marker)
+}
+
+// killExec kills the processes of an exec whose step was cancelled or timed out, so they don't keep running while the
+// next steps of the job, like the post steps, run in the container
+func (cr *containerReference) killExec(ctx context.Context, marker string) {
+	logger := common.Logger(ctx)
+	ctx, cancel := context.WithTimeout(common.WithoutCancel(ctx), 30*time.Second)
+	defer cancel()
+	idResp, err := cr.cli.ContainerExecCreate(ctx, cr.id, types.ExecConfig{
+		User: "0",
+		Cmd:  []string{"sh", "-c", killExecScript(marker)},
+	})
+	if err == nil {
+		err = cr.cli.ContainerExecStart(ctx, idResp.ID, types.ExecStartCheck{Detach: true})
+	}
+	for err == nil {
+		var inspect types.ContainerExecInspect
+		if inspect, err = cr.cli.ContainerExecInspect(ctx, idResp.ID); err == nil && !inspect.Running {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+	if err != nil {
+		logger.Warnf("Unable to kill the processes of the cancelled command: %v", err)
+		return
+	}
+	logger.Debugf("Killed the processes of the cancelled command")
+}
+
+// execOutput runs a command as root and returns its output, which isn't written to the logs of the step
+func (cr *containerReference) execOutput(ctx context.Context, cmd []string) (string, error) {
+	idResp, err := cr.cli.ContainerExecCreate(ctx, cr.id, types.ExecConfig{
+		User:         "0",
+		Cmd:          cmd,
+		AttachStdout: true,
+		AttachStderr: true,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create exec: %w", err)
+	}
+	resp, err := cr.cli.ContainerExecAttach(ctx, idResp.ID, types.ExecStartCheck{})
+	if err != nil {
+		return "", fmt.Errorf("failed to attach to exec: %w", err)
+	}
+	defer resp.Close()
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	if _, err := stdcopy.StdCopy(stdout, stderr, resp.Reader); err != nil {
+		return "", err
+	}
+	inspect, err := cr.cli.ContainerExecInspect(ctx, idResp.ID)
+	if err != nil {
+		return "", fmt.Errorf("failed to inspect exec: %w", err)
+	}
+	if inspect.ExitCode != 0 {
+		return "", fmt.Errorf("exitcode '%d': %s", inspect.ExitCode, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+func (cr *containerReference) CPUTicks(ctx context.Context) (int64, error) {
+	stat, err := cr.execOutput(ctx, []string{"sh", "-c", "cat /proc/[0-9]*/stat 2>/dev/null; true"})
+	if err != nil {
+		return 0, err
+	}
+	return parseCPUTicks(stat), nil
+}
+
+// parseCPUTicks sums the user and system times of the processes and of their waited-for children of /proc/*/stat
+func parseCPUTicks(stat string) int64 {
+	var total int64
+	for _, line := range strings.Split(stat, "\n") {
+		// the name of the command in parentheses may contain spaces
+		i := strings.LastIndex(line, ")")
+		if i < 0 {
+			continue
+		}
+		// utime, stime, cutime and cstime are the fields 14 to 17, the fields after the name start at 3
+		fields := strings.Fields(line[i+1:])
+		if len(fields) < 15 {
+			continue
+		}
+		for _, field := range fields[11:15] {
+			ticks, _ := strconv.ParseInt(field, 10, 64)
+			total += ticks
+		}
+	}
+	return total
+}
+
+// processDumpScript lists the processes with ps if the image has it, and their state, wait channel and kernel stack
+// from /proc, the stack is only readable with CAP_SYS_ADMIN
+const processDumpScript = `ps -eo pid,ppid,stat,etime,time,args 2>/dev/null || ps 2>/dev/null
+for p in /proc/[0-9]*; do
+  [ -r "$p/cmdline" ] || continue
+  printf '\n%s: %s wchan=%s\n' "${p#/proc/}" "$(tr '\0' ' ' 2>/dev/null < "$p/cmdline")" "$(cat "$p/wchan" 2>/dev/null)"
+  grep -E '^(State|Threads|voluntary_ctxt_switches|nonvoluntary_ctxt_switches):' "$p/status" 2>/dev/null
+  cat "$p/stack" 2>/dev/null
+done
+true`
+
+func (cr *containerReference) ProcessDump(ctx context.Context) (string, error) {
+	return cr.execOutput(ctx, []string{"sh", "-c", processDumpScript})
+}
+
+// Shell runs the shell with the cli of the backend, which passes the terminal of act through to the exec
+func (cr *containerReference) Shell(ctx context.Context, env map[string]string, workdir string) error {
+	if cr.id == "" {
+		return fmt.Errorf("the container isn't running")
+	}
+	wd := cr.input.WorkingDir
+	if strings.HasPrefix(workdir, "/") {
+		wd = workdir
+	} else if workdir != "" {
+		wd = fmt.Sprintf("%s/%s", cr.input.WorkingDir, workdir)
+	}
+	args := []string{"exec", "-i", "-w", wd}
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		args = append(args, "-t")
+	}
+	if _, ok := env["HOME"]; !ok && cr.home != "" {
+		args = append(args, "-e", "HOME="+cr.home)
+	}
+	for k, v := range env {
+		args = append(args, "-e", k+"="+v)
+	}
+	args = append(args, cr.id, "sh", "-c", interactiveShellScript)
+
+	cmd := dockerCommand(ctx, BackendFrom(ctx).cli(), args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
 func (cr *containerReference) tryReadID(opt string, cbk func(id int)) common.Executor {
 	return func(ctx context.Context) error {
 		idResp, err := cr.cli.ContainerExecCreate(ctx, cr.id, types.ExecConfig{
@@ -623,6 +831,53 @@ func (cr *containerReference) tryReadID(opt string, cbk func(id int)) common.Exe
 	}
 }
 
+// homeCheck prints ok if the HOME of the user is writable
+const homeCheck = `if [ -n "$HOME" ] && [ "$HOME" != / ] && [ -w "$HOME" ]; then echo ok; fi`
+
+// setupHome gives a non-root user without a writable HOME, e.g. a uid of --container-user missing in /etc/passwd
+// whose HOME is /, a HOME in the act path
+func (cr *containerReference) setupHome() common.Executor {
+	return func(ctx context.Context) error {
+		cr.home = ""
+		if cr.UID == 0 {
+			return nil
+		}
+		if out, err := cr.readExecOutput(ctx, []string{"sh", "-c", homeCheck}); err != nil || strings.TrimSpace(out) == "ok" {
+			return nil
+		}
+		home := cr.GetActPath() + "/home"
+		common.Logger(ctx).Debugf("HOME of uid %d isn't writable, using %s", cr.UID, home)
+		if err := cr.Exec([]string{"sh", "-c", fmt.Sprintf("mkdir -p %[1]s && chown %d:%d %[1]s", home, cr.UID, cr.GID)}, nil, "0", "")(ctx); err != nil {
+			return err
+		}
+		cr.home = home
+		return nil
+	}
+}
+
+// readExecOutput returns the output of a command run by the user of the container
+func (cr *containerReference) readExecOutput(ctx context.Context, cmd []string) (string, error) {
+	idResp, err := cr.cli.ContainerExecCreate(ctx, cr.id, types.ExecConfig{
+		Cmd:          cmd,
+		AttachStdout: true,
+		AttachStderr: true,
+	})
+	if err != nil {
+		return "", err
+	}
+	resp, err := cr.cli.ContainerExecAttach(ctx, idResp.ID, types.ExecStartCheck{})
+	if err != nil {
+		return "", err
+	}
+	defer resp.Close()
+
+	stdout := &bytes.Buffer{}
+	if _, err := stdcopy.StdCopy(stdout, io.Discard, resp.Reader); err != nil {
+		return "", err
+	}
+	return stdout.String(), nil
+}
+
 func (cr *containerReference) tryReadUID() common.Executor {
 	return cr.tryReadID("-u", func(id int) { cr.UID = id })
 }
@@ -5,6 +5,9 @@ import (
 	"context"
 	"io"
 	"net"
+	"os"
+	"os/exec"
+	"runtime"
 	"strings"
 	"testing"
 	"time"
@@ -166,3 +169,54 @@ func TestDockerExecFailure(t *testing.T) {
 
 // Type assert containerReference implements ExecutionsEnvironment
 var _ ExecutionsEnvironment = &containerReference{}
+
+func TestConfigHash(t *testing.T) {
+	input := &NewContainerInput{
+		Image:  "node:16-buster-slim",
+		Mounts: map[string]string{"act-toolcache": "/toolcache", "act-job": "/var/run/act"},
+	}
+	hash := configHash(input, nil, nil)
+	assert.Equal(t, hash, configHash(input, nil, nil))
+
+	changed := *input
+	changed.Image = "node:18-buster-slim"
+	assert.NotEqual(t, hash, configHash(&changed, nil, nil))
+
+	changed = *input
+	changed.Options = "--cpus 2"
+	assert.NotEqual(t, hash, configHash(&changed, nil, nil))
+
+	assert.NotEqual(t, hash, configHash(input, []string{"SYS_PTRACE"}, nil))
+
+	changed = *input
+	changed.Runtime = "runsc"
+	assert.NotEqual(t, hash, configHash(&changed, nil, nil))
+}
+
+func TestKillExecScript(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("the processes are looked up in /proc")
+	}
+	marked := exec.Command("sleep", "30")
+	marked.Env = append(os.Environ(), execMarkerEnv+"=marker")
+	other := exec.Command("sleep", "30")
+	other.Env = append(os.Environ(), execMarkerEnv+"=other")
+	assert.NoError(t, marked.Start())
+	assert.NoError(t, other.Start())
+	defer func() {
+		_ = other.Process.Kill()
+		_ = other.Wait()
+	}()
+
+	assert.NoError(t, exec.Command("sh", "-c", killExecScript("marker")).Run())
+	assert.EqualError(t, marked.Wait(), "signal: killed")
+	assert.Nil(t, other.ProcessState)
+}
+
+func TestParseCPUTicks(t *testing.T) {
+	stat := `1 (sleep) S 0 1 1 0 -1 4194560 120 0 0 0 3 4 5 6 20 0 1 0 1000 2600960 200 18446744073709551615
+42 (my (odd) cmd) R 1 42 1 0 -1 4194304 90 0 0 0 100 20 0 0 20 0 1 0 2000 2600960 300 18446744073709551615
+43 (truncated) R 1
+`
+	assert.Equal(t, int64(3+4+5+6+100+20), parseCPUTicks(stat))
+}
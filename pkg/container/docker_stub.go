@@ -5,6 +5,7 @@ package container
 import (
 	"context"
 	"runtime"
+	"time"
 
 	"github.com/docker/docker/api/types"
 	"github.com/nektos/act/pkg/common"
@@ -23,6 +24,12 @@ func RemoveImage(ctx context.Context, imageName string, force bool, pruneChildre
 	return false, errors.New("Unsupported Operation")
 }
 
+// ImageDigest resolves the image in its registry to the digest of its manifest (list), which is the same for all
+// architectures of the image
+func ImageDigest(ctx context.Context, imageName string) (string, error) {
+	return "", errors.New("Unsupported Operation")
+}
+
 // NewDockerBuildExecutor function to create a run executor for the container
 func NewDockerBuildExecutor(input NewDockerBuildExecutorInput) common.Executor {
 	return func(ctx context.Context) error {
@@ -37,6 +44,20 @@ func NewDockerPullExecutor(input NewDockerPullExecutorInput) common.Executor {
 	}
 }
 
+// NewDockerComposeUpExecutor starts all services of a docker-compose project in the background
+func NewDockerComposeUpExecutor(input NewDockerComposeExecutorInput) common.Executor {
+	return func(ctx context.Context) error {
+		return errors.New("Unsupported Operation")
+	}
+}
+
+// NewDockerComposeDownExecutor stops and removes all services, networks and anonymous volumes of a docker-compose project
+func NewDockerComposeDownExecutor(input NewDockerComposeExecutorInput) common.Executor {
+	return func(ctx context.Context) error {
+		return nil
+	}
+}
+
 // NewContainer creates a reference to a container
 func NewContainer(input *NewContainerInput) ExecutionsEnvironment {
 	return nil
@@ -50,8 +71,77 @@ func GetHostInfo(ctx context.Context) (info types.Info, err error) {
 	return types.Info{}, nil
 }
 
+func GetHostAddress(ctx context.Context) (*HostAddress, error) {
+	return nil, errors.New("Unsupported Operation")
+}
+
+func GetContainerID(ctx context.Context, name string) (string, error) {
+	return "", errors.New("Unsupported Operation")
+}
+
+func GetComposeServices(ctx context.Context, input NewDockerComposeExecutorInput) (map[string]*ServiceContainer, error) {
+	return nil, errors.New("Unsupported Operation")
+}
+
+func ListReusableContainers(ctx context.Context) ([]*ReusableContainer, error) {
+	return nil, errors.New("Unsupported Operation")
+}
+
 func NewDockerVolumeRemoveExecutor(volume string, force bool) common.Executor {
 	return func(ctx context.Context) error {
 		return nil
 	}
 }
+
+func NewDockerCacheVolumeExecutor(name string, path string) common.Executor {
+	return func(ctx context.Context) error {
+		return errors.New("Unsupported Operation")
+	}
+}
+
+func ListCacheVolumes(ctx context.Context) ([]*CacheVolume, error) {
+	return nil, errors.New("Unsupported Operation")
+}
+
+func NewDockerNetworkCreateExecutor(name string) common.Executor {
+	return func(ctx context.Context) error {
+		return errors.New("Unsupported Operation")
+	}
+}
+
+func NewDockerNetworkRemoveExecutor(name string) common.Executor {
+	return func(ctx context.Context) error {
+		return nil
+	}
+}
+
+func NewDockerHealthyExecutor(name string, timeout time.Duration) common.Executor {
+	return func(ctx context.Context) error {
+		return errors.New("Unsupported Operation")
+	}
+}
+
+func GetServiceContainer(ctx context.Context, name string, network string) (*ServiceContainer, error) {
+	return nil, errors.New("Unsupported Operation")
+}
+
+// NewDockerRegistryStartExecutor starts a throwaway registry publishing its port on port of the host
+func NewDockerRegistryStartExecutor(name string, port string) common.Executor {
+	return func(ctx context.Context) error {
+		return errors.New("Unsupported Operation")
+	}
+}
+
+// NewDockerRegistryRemoveExecutor removes the registry with the images pushed to it
+func NewDockerRegistryRemoveExecutor(name string) common.Executor {
+	return func(ctx context.Context) error {
+		return nil
+	}
+}
+
+// NewDockerPushExecutor tags the image as ref, an image of a registry, and pushes it to the registry
+func NewDockerPushExecutor(image string, ref string) common.Executor {
+	return func(ctx context.Context) error {
+		return errors.New("Unsupported Operation")
+	}
+}
@@ -4,8 +4,11 @@ package container
 
 import (
 	"context"
+	"sort"
+	"time"
 
 	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/volume"
 	"github.com/nektos/act/pkg/common"
 )
 
@@ -51,3 +54,61 @@ func removeExecutor(volume string, force bool) common.Executor {
 		return cli.VolumeRemove(ctx, volume, force)
 	}
 }
+
+// NewDockerCacheVolumeExecutor creates the volume of the cache mounted at path unless it exists
+func NewDockerCacheVolumeExecutor(name string, path string) common.Executor {
+	return func(ctx context.Context) error {
+		if common.Dryrun(ctx) {
+			return nil
+		}
+
+		cli, err := GetDockerClient(ctx)
+		if err != nil {
+			return err
+		}
+		defer cli.Close()
+
+		volumeName := CacheVolumeName(name)
+		if _, err := cli.VolumeInspect(ctx, volumeName); err == nil {
+			return nil
+		}
+		common.Logger(ctx).Debugf("%sdocker volume create %s", logPrefix, volumeName)
+		_, err = cli.VolumeCreate(ctx, volume.CreateOptions{
+			Name: volumeName,
+			Labels: map[string]string{
+				LabelCacheVolume: name,
+				LabelCachePath:   path,
+			},
+		})
+		return err
+	}
+}
+
+// ListCacheVolumes returns the cache volumes managed by act
+func ListCacheVolumes(ctx context.Context) ([]*CacheVolume, error) {
+	cli, err := GetDockerClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cli.Close()
+
+	list, err := cli.VolumeList(ctx, filters.NewArgs(filters.Arg("label", LabelCacheVolume)))
+	if err != nil {
+		return nil, err
+	}
+
+	volumes := make([]*CacheVolume, 0, len(list.Volumes))
+	for _, vol := range list.Volumes {
+		created, _ := time.Parse(time.RFC3339, vol.CreatedAt)
+		volumes = append(volumes, &CacheVolume{
+			Name:    vol.Labels[LabelCacheVolume],
+			Volume:  vol.Name,
+			Path:    vol.Labels[LabelCachePath],
+			Created: created,
+		})
+	}
+	sort.Slice(volumes, func(i, j int) bool {
+		return volumes[i].Name < volumes[j].Name
+	})
+	return volumes, nil
+}
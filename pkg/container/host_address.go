@@ -0,0 +1,46 @@
+//go:build !(WITHOUT_DOCKER || !(linux || darwin || windows))
+
+package container
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker/api/types/versions"
+)
+
+// GetHostAddress returns how the containers reach the host, the name differs between the engines
+func GetHostAddress(ctx context.Context) (*HostAddress, error) {
+	cli, err := GetDockerClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cli.Close()
+
+	version, err := cli.ServerVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, component := range version.Components {
+		if strings.HasPrefix(component.Name, "Podman") {
+			// podman adds the name to /etc/hosts of every container
+			return &HostAddress{Host: "host.containers.internal"}, nil
+		}
+	}
+
+	info, err := cli.Info(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if info.OperatingSystem == "Docker Desktop" {
+		return &HostAddress{Host: "host.docker.internal", Loopback: true}, nil
+	}
+	if versions.LessThan(version.APIVersion, "1.41") {
+		return nil, fmt.Errorf("docker %s does not support host-gateway, docker 20.10 or newer is required", version.Version)
+	}
+	return &HostAddress{
+		Host:       "host.docker.internal",
+		ExtraHosts: []string{"host.docker.internal:host-gateway"},
+	}, nil
+}
@@ -53,6 +53,8 @@ func (e *HostEnvironment) Copy(destPath string, files ...*FileEntry) common.Exec
 			if err := os.MkdirAll(filepath.Dir(filepath.Join(destPath, f.Name)), 0777); err != nil {
 				return err
 			}
+			// the file is replaced, WriteFile keeps the mode of an existing file and fails on a read-only one
+			_ = os.Remove(filepath.Join(destPath, f.Name))
 			if err := os.WriteFile(filepath.Join(destPath, f.Name), []byte(f.Body), fs.FileMode(f.Mode)); err != nil {
 				return err
 			}
@@ -198,7 +200,7 @@ func lookupPathHost(cmd string, env map[string]string, writer io.Writer) (string
 		if _, _err := writer.Write([]byte(err + "\n")); _err != nil {
 			return "", fmt.Errorf("%v: %w", err, _err)
 		}
-		return "", errors.New(err)
+		return "", fmt.Errorf("%s: %w", err, ErrCommandNotFound)
 	}
 	return f, nil
 }
@@ -307,7 +309,28 @@ func (e *HostEnvironment) exec(ctx context.Context, command []string, cmdline st
 	if ppty != nil {
 		go writeKeepAlive(ppty)
 	}
-	err = cmd.Run()
+	if err = cmd.Start(); err != nil {
+		return err
+	}
+	// the context only kills the command, its children in the process group of the command are killed as well
+	exited := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			killProcessGroup(cmd.Process.Pid)
+		case <-exited:
+		}
+	}()
+	err = cmd.Wait()
+	close(exited)
+	if ctx.Err() != nil {
+		// the command may have been killed by the context before the children
+		killProcessGroup(cmd.Process.Pid)
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 127 {
+		return fmt.Errorf("%v: %w", err, ErrCommandNotFound)
+	}
 	if err != nil {
 		return err
 	}
@@ -326,6 +349,30 @@ func (e *HostEnvironment) exec(ctx context.Context, command []string, cmdline st
 	return err
 }
 
+// Shell runs the shell of SHELL, sh if it isn't set or the one of COMSPEC on windows
+func (e *HostEnvironment) Shell(ctx context.Context, env map[string]string, workdir string) error {
+	wd := e.Path
+	if filepath.IsAbs(workdir) {
+		wd = workdir
+	} else if workdir != "" {
+		wd = filepath.Join(e.Path, workdir)
+	}
+	shell := os.Getenv("SHELL")
+	if runtime.GOOS == "windows" {
+		shell = os.Getenv("COMSPEC")
+	}
+	if shell == "" {
+		shell = "sh"
+	}
+	cmd := exec.CommandContext(ctx, shell)
+	cmd.Env = getEnvListFromMap(env)
+	cmd.Dir = wd
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
 func (e *HostEnvironment) Exec(command []string /*cmdline string, */, env map[string]string, user, workdir string) common.Executor {
 	return func(ctx context.Context) error {
 		if err := e.exec(ctx, command, "" /*cmdline*/, env, user, workdir); err != nil {
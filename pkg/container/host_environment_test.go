@@ -1,4 +1,35 @@
 package container
 
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
 // Type assert HostEnvironment implements ExecutionsEnvironment
 var _ ExecutionsEnvironment = &HostEnvironment{}
+
+func TestHostEnvironmentExecKillsChildren(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "plan9" {
+		t.Skip("the command is a shell script")
+	}
+	dir := t.TempDir()
+	e := &HostEnvironment{Path: dir, StdOut: io.Discard}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	started := time.Now()
+	err := e.Exec([]string{"sh", "-c", "(sleep 1; touch survived) & sleep 30"}, map[string]string{"PATH": os.Getenv("PATH")}, "", "")(ctx)
+	assert.Error(t, err)
+	assert.Less(t, time.Since(started), 5*time.Second)
+
+	// the child of the cancelled command is killed with it
+	time.Sleep(1500 * time.Millisecond)
+	assert.NoFileExists(t, filepath.Join(dir, "survived"))
+}
@@ -21,6 +21,11 @@ func getSysProcAttr(cmdLine string, tty bool) *syscall.SysProcAttr {
 	}
 }
 
+// killProcessGroup kills the process group of a command started with getSysProcAttr, whose id is the one of the command
+func killProcessGroup(pid int) {
+	_ = syscall.Kill(-pid, syscall.SIGKILL)
+}
+
 func openPty() (*os.File, *os.File, error) {
 	return pty.Open()
 }
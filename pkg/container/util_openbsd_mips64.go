@@ -12,6 +12,10 @@ func getSysProcAttr(cmdLine string, tty bool) *syscall.SysProcAttr {
 	}
 }
 
+func killProcessGroup(pid int) {
+	_ = syscall.Kill(-pid, syscall.SIGKILL)
+}
+
 func openPty() (*os.File, *os.File, error) {
 	return nil, nil, errors.New("Unsupported")
 }
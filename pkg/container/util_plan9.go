@@ -12,6 +12,10 @@ func getSysProcAttr(cmdLine string, tty bool) *syscall.SysProcAttr {
 	}
 }
 
+func killProcessGroup(pid int) {
+	// the note group of the command is killed with the command
+}
+
 func openPty() (*os.File, *os.File, error) {
 	return nil, nil, errors.New("Unsupported")
 }
@@ -3,6 +3,8 @@ package container
 import (
 	"errors"
 	"os"
+	"os/exec"
+	"strconv"
 	"syscall"
 )
 
@@ -10,6 +12,11 @@ func getSysProcAttr(cmdLine string, tty bool) *syscall.SysProcAttr {
 	return &syscall.SysProcAttr{CmdLine: cmdLine, CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP}
 }
 
+func killProcessGroup(pid int) {
+	// the process groups of Windows can't be killed, the process tree of the command is
+	_ = exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(pid)).Run()
+}
+
 func openPty() (*os.File, *os.File, error) {
 	return nil, nil, errors.New("Unsupported")
 }
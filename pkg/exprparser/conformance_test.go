@@ -0,0 +1,61 @@
+package exprparser
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+
+	"github.com/nektos/act/pkg/model"
+)
+
+type conformanceCase struct {
+	Expr   string      `yaml:"expr"`
+	Result interface{} `yaml:"result"`
+	Error  string      `yaml:"error"`
+}
+
+func TestConformance(t *testing.T) {
+	content, err := os.ReadFile("testdata/conformance.yml")
+	assert.NoError(t, err)
+	var cases []conformanceCase
+	assert.NoError(t, yaml.Unmarshal(content, &cases))
+
+	env := &EvaluationEnvironment{
+		Github: &model.GithubContext{
+			Event: map[string]interface{}{
+				"commits": []interface{}{
+					map[string]interface{}{"id": "a", "author": map[string]interface{}{"name": "Mona"}},
+					map[string]interface{}{"id": "b", "author": map[string]interface{}{"name": "Hubot"}},
+				},
+				"labels": []interface{}{"bug", "docs"},
+				"nested": map[string]interface{}{
+					"a": []interface{}{1.0, 2.0},
+					"b": map[string]interface{}{"c": 3.0},
+				},
+			},
+		},
+		Needs: map[string]Needs{
+			"build": {Result: "success"},
+			"alpha": {Result: "failure"},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.Expr, func(t *testing.T) {
+			output, err := NewInterpeter(env, Config{}).Evaluate(tt.Expr, DefaultStatusCheckNone)
+			if tt.Error != "" {
+				assert.EqualError(t, err, tt.Error)
+				return
+			}
+			assert.NoError(t, err)
+			expected, err := json.Marshal(tt.Result)
+			assert.NoError(t, err)
+			actual, err := json.Marshal(output)
+			assert.NoError(t, err)
+			assert.JSONEq(t, string(expected), string(actual))
+		})
+	}
+}
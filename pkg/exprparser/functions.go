@@ -1,6 +1,7 @@
 package exprparser
 
 import (
+	"bytes"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
@@ -8,12 +9,12 @@ import (
 	"io"
 	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
 	"reflect"
 	"strconv"
 	"strings"
 
-	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
 	"github.com/nektos/act/pkg/model"
 	"github.com/rhysd/actionlint"
 )
@@ -28,7 +29,7 @@ func (impl *interperterImpl) contains(search, item reflect.Value) (bool, error)
 
 	case reflect.Slice:
 		for i := 0; i < search.Len(); i++ {
-			arrayItem := search.Index(i).Elem()
+			arrayItem := sliceItem(search, i)
 			result, err := impl.compareValues(arrayItem, item, actionlint.CompareOpNodeKindEq)
 			if err != nil {
 				return false, err
@@ -57,6 +58,15 @@ func (impl *interperterImpl) endsWith(searchString, searchValue reflect.Value) (
 	), nil
 }
 
+// sliceItem returns the item of an array, the items of the arrays of the contexts and of fromJSON are interfaces
+func sliceItem(array reflect.Value, i int) reflect.Value {
+	item := array.Index(i)
+	if item.Kind() == reflect.Interface {
+		return item.Elem()
+	}
+	return item
+}
+
 const (
 	passThrough = iota
 	bracketOpen
@@ -91,8 +101,9 @@ func (impl *interperterImpl) format(str reflect.Value, replaceValue ...reflect.V
 				state = passThrough
 
 			case '}':
+				// like on GitHub the index is only made of digits, e.g. not '{-1}' nor '{ 0 }'
 				index, err := strconv.ParseInt(replacementIndex, 10, 32)
-				if err != nil {
+				if err != nil || strings.Trim(replacementIndex, "0123456789") != "" {
 					return "", fmt.Errorf("The following format string is invalid: '%s'", input)
 				}
 
@@ -118,7 +129,7 @@ func (impl *interperterImpl) format(str reflect.Value, replaceValue ...reflect.V
 				state = passThrough
 
 			default:
-				panic("Invalid format parser state")
+				return "", fmt.Errorf("Closing bracket without opening one. The following format string is invalid: '%s'", input)
 			}
 		}
 	}
@@ -142,7 +153,7 @@ func (impl *interperterImpl) join(array reflect.Value, sep reflect.Value) (strin
 	case reflect.Slice:
 		var items []string
 		for i := 0; i < array.Len(); i++ {
-			items = append(items, impl.coerceToString(array.Index(i).Elem()).String())
+			items = append(items, impl.coerceToString(sliceItem(array, i)).String())
 		}
 
 		return strings.Join(items, separator), nil
@@ -156,22 +167,28 @@ func (impl *interperterImpl) toJSON(value reflect.Value) (string, error) {
 		return "null", nil
 	}
 
-	json, err := json.MarshalIndent(value.Interface(), "", "  ")
-	if err != nil {
+	// like on GitHub the characters <, > and & aren't escaped
+	buffer := &bytes.Buffer{}
+	encoder := json.NewEncoder(buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value.Interface()); err != nil {
 		return "", fmt.Errorf("Cannot convert value to JSON. Cause: %v", err)
 	}
 
-	return string(json), nil
+	return strings.TrimSuffix(buffer.String(), "\n"), nil
 }
 
 func (impl *interperterImpl) fromJSON(value reflect.Value) (interface{}, error) {
-	if value.Kind() != reflect.String {
+	switch value.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Struct, reflect.Ptr:
 		return nil, fmt.Errorf("Cannot parse non-string type %v as JSON", value.Kind())
 	}
 
+	// like on GitHub the primitives are converted to a string, e.g. fromJSON(1) is 1
 	var data interface{}
 
-	err := json.Unmarshal([]byte(value.String()), &data)
+	err := json.Unmarshal([]byte(impl.coerceToString(value).String()), &data)
 	if err != nil {
 		return nil, fmt.Errorf("Invalid JSON: %v", err)
 	}
@@ -179,38 +196,134 @@ func (impl *interperterImpl) fromJSON(value reflect.Value) (interface{}, error)
 	return data, nil
 }
 
-func (impl *interperterImpl) hashFiles(paths ...reflect.Value) (string, error) {
-	var ps []gitignore.Pattern
-
-	const cwdPrefix = "." + string(filepath.Separator)
-	const excludeCwdPrefix = "!" + cwdPrefix
-	for _, path := range paths {
-		if path.Kind() == reflect.String {
-			cleanPath := path.String()
-			if strings.HasPrefix(cleanPath, cwdPrefix) {
-				cleanPath = cleanPath[len(cwdPrefix):]
-			} else if strings.HasPrefix(cleanPath, excludeCwdPrefix) {
-				cleanPath = "!" + cleanPath[len(excludeCwdPrefix):]
+// hashFilesPattern is a pattern of hashFiles relative to the workspace, split into its path segments
+type hashFilesPattern struct {
+	negate   bool
+	segments []string
+}
+
+// match reports whether the pattern matches the file or one of its parent directories, like on GitHub the files of
+// a matched directory are matched too
+func (p hashFilesPattern) match(file []string) bool {
+	for i := 1; i <= len(file); i++ {
+		if matchSegments(p.segments, file[:i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchSegments matches the segments of a path with the segments of a glob, ** matches any number of segments
+func matchSegments(pattern []string, name []string) bool {
+	if len(pattern) == 0 {
+		return len(name) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(name); i++ {
+			if matchSegments(pattern[1:], name[i:]) {
+				return true
 			}
-			ps = append(ps, gitignore.ParsePattern(cleanPath, nil))
-		} else {
-			return "", fmt.Errorf("Non-string path passed to hashFiles")
 		}
+		return false
+	}
+	if len(name) == 0 {
+		return false
 	}
+	matched, err := path.Match(pattern[0], name[0])
+	return err == nil && matched && matchSegments(pattern[1:], name[1:])
+}
 
-	matcher := gitignore.NewMatcher(ps)
+// hashFilesPatterns parses the patterns of hashFiles, an argument may hold a pattern per line. The patterns are
+// relative to the workspace, an absolute pattern must be in the workspace.
+func (impl *interperterImpl) hashFilesPatterns(paths ...reflect.Value) ([]hashFilesPattern, error) {
+	var prefixes []string
+	if impl.config.WorkingDir != "" {
+		prefixes = append(prefixes, strings.TrimSuffix(filepath.ToSlash(impl.config.WorkingDir), "/")+"/")
+	}
+	if impl.env.Github != nil && impl.env.Github.Workspace != "" {
+		prefixes = append(prefixes, strings.TrimSuffix(impl.env.Github.Workspace, "/")+"/")
+	}
+
+	var patterns []hashFilesPattern
+	for _, arg := range paths {
+		if arg.Kind() != reflect.String {
+			return nil, fmt.Errorf("Non-string path passed to hashFiles")
+		}
+		for _, line := range strings.Split(arg.String(), "\n") {
+			pattern := hashFilesPattern{}
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			if strings.HasPrefix(line, "!") {
+				pattern.negate = true
+				line = line[1:]
+			}
+			line = filepath.ToSlash(line)
+			for _, prefix := range prefixes {
+				if strings.HasPrefix(line, prefix) {
+					line = line[len(prefix):]
+					break
+				}
+			}
+			if path.IsAbs(line) {
+				return nil, fmt.Errorf("hashFiles('%s') failed, the pattern isn't in the workspace", arg.String())
+			}
+			pattern.segments = strings.Split(path.Clean(line), "/")
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns, nil
+}
+
+// workspace returns the files hashFiles looks up, the files of the container of the job if it runs in one
+func (impl *interperterImpl) workspace() (fs.FS, error) {
+	if impl.config.Workspace != nil {
+		return impl.config.Workspace()
+	}
+	return os.DirFS(impl.config.WorkingDir), nil
+}
+
+// hashFiles hashes the files of the workspace matching the patterns like GitHub: the SHA-256 of the SHA-256 of every
+// file, in the order of their paths. It is empty if no file matches.
+func (impl *interperterImpl) hashFiles(paths ...reflect.Value) (string, error) {
+	patterns, err := impl.hashFilesPatterns(paths...)
+	if err != nil {
+		return "", err
+	}
+
+	workspace, err := impl.workspace()
+	if err != nil {
+		return "", fmt.Errorf("hashFiles failed, unable to read the workspace: %v", err)
+	}
 
 	var files []string
-	if err := filepath.Walk(impl.config.WorkingDir, func(path string, fi fs.FileInfo, err error) error {
-		sansPrefix := strings.TrimPrefix(path, impl.config.WorkingDir+string(filepath.Separator))
-		parts := strings.Split(sansPrefix, string(filepath.Separator))
-		if fi.IsDir() || !matcher.Match(parts, fi.IsDir()) {
+	if err := fs.WalkDir(workspace, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if name == "." || entry.IsDir() {
 			return nil
 		}
-		files = append(files, path)
+		if entry.Type()&fs.ModeSymlink != 0 {
+			// the symlinks are followed, except to directories
+			if info, err := fs.Stat(workspace, name); err != nil || info.IsDir() {
+				return nil
+			}
+		}
+		matched := false
+		segments := strings.Split(name, "/")
+		for _, pattern := range patterns {
+			if pattern.match(segments) {
+				matched = !pattern.negate
+			}
+		}
+		if matched {
+			files = append(files, name)
+		}
 		return nil
 	}); err != nil {
-		return "", fmt.Errorf("Unable to filepath.Walk: %v", err)
+		return "", fmt.Errorf("Unable to walk the workspace: %v", err)
 	}
 
 	if len(files) == 0 {
@@ -220,18 +333,21 @@ func (impl *interperterImpl) hashFiles(paths ...reflect.Value) (string, error) {
 	hasher := sha256.New()
 
 	for _, file := range files {
-		f, err := os.Open(file)
+		f, err := workspace.Open(file)
 		if err != nil {
-			return "", fmt.Errorf("Unable to os.Open: %v", err)
+			return "", fmt.Errorf("Unable to open %s: %v", file, err)
 		}
 
-		if _, err := io.Copy(hasher, f); err != nil {
-			return "", fmt.Errorf("Unable to io.Copy: %v", err)
+		fileHasher := sha256.New()
+		if _, err := io.Copy(fileHasher, f); err != nil {
+			f.Close()
+			return "", fmt.Errorf("Unable to read %s: %v", file, err)
 		}
 
 		if err := f.Close(); err != nil {
 			return "", fmt.Errorf("Unable to Close file: %v", err)
 		}
+		hasher.Write(fileHasher.Sum(nil))
 	}
 
 	return hex.EncodeToString(hasher.Sum(nil)), nil
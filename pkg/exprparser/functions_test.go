@@ -187,15 +187,21 @@ func TestFunctionHashFiles(t *testing.T) {
 	}{
 		{"hashFiles('**/non-extant-files') }}", "", "hash-non-existing-file"},
 		{"hashFiles('**/non-extant-files', '**/more-non-extant-files') }}", "", "hash-multiple-non-existing-files"},
-		{"hashFiles('./for-hashing-1.txt') }}", "66a045b452102c59d840ec097d59d9467e13a3f34f6494e539ffd32c1bb35f18", "hash-single-file"},
-		{"hashFiles('./for-hashing-*.txt') }}", "8e5935e7e13368cd9688fe8f48a0955293676a021562582c7e848dafe13fb046", "hash-multiple-files"},
-		{"hashFiles('./for-hashing-*.txt', '!./for-hashing-2.txt') }}", "66a045b452102c59d840ec097d59d9467e13a3f34f6494e539ffd32c1bb35f18", "hash-negative-pattern"},
-		{"hashFiles('./for-hashing-**') }}", "c418ba693753c84115ced0da77f876cddc662b9054f4b129b90f822597ee2f94", "hash-multiple-files-and-directories"},
-		{"hashFiles('./for-hashing-3/**') }}", "6f5696b546a7a9d6d42a449dc9a56bef244aaa826601ef27466168846139d2c2", "hash-nested-directories"},
-		{"hashFiles('./for-hashing-3/**/nested-data.txt') }}", "8ecadfb49f7f978d0a9f3a957e9c8da6cc9ab871f5203b5d9f9d1dc87d8af18c", "hash-nested-directories-2"},
+		{"hashFiles('./for-hashing-1.txt') }}", "31ff3fcb19566e855efbe0c4eb393d1a7807e08c4f1cf4f1a89e29d9d55968c5", "hash-single-file"},
+		{"hashFiles('./for-hashing-*.txt') }}", "56c352d06ebcf622658fb248292304a432b204d29e11ba76c96dbb647d3b73ad", "hash-multiple-files"},
+		{"hashFiles('./for-hashing-*.txt', '!./for-hashing-2.txt') }}", "31ff3fcb19566e855efbe0c4eb393d1a7807e08c4f1cf4f1a89e29d9d55968c5", "hash-negative-pattern"},
+		{"hashFiles('./for-hashing-**') }}", "9af859a89b56aaf2c1bcc457fccd56b85a70d827b9ad588a8929971432580979", "hash-multiple-files-and-directories"},
+		{"hashFiles('./for-hashing-3/**') }}", "971c07fd4bb8d8afd1ba0a410a3326c1afc51185e262a5b7416308464874eb9c", "hash-nested-directories"},
+		{"hashFiles('./for-hashing-3/**/nested-data.txt') }}", "f5b93541229f40ca0a8a59ec7776bdc80fdb955b5e258c07717efc9b39527d5a", "hash-nested-directories-2"},
+		{"hashFiles('./for-hashing-3') }}", "971c07fd4bb8d8afd1ba0a410a3326c1afc51185e262a5b7416308464874eb9c", "hash-directory"},
+		{"hashFiles('*.txt') }}", "56c352d06ebcf622658fb248292304a432b204d29e11ba76c96dbb647d3b73ad", "hash-top-level-files"},
+		{"hashFiles('for-hashing-1.txt\nfor-hashing-2.txt') }}", "56c352d06ebcf622658fb248292304a432b204d29e11ba76c96dbb647d3b73ad", "hash-patterns-per-line"},
+		{"hashFiles('/workspace/for-hashing-1.txt') }}", "31ff3fcb19566e855efbe0c4eb393d1a7807e08c4f1cf4f1a89e29d9d55968c5", "hash-absolute-path"},
 	}
 
-	env := &EvaluationEnvironment{}
+	env := &EvaluationEnvironment{
+		Github: &model.GithubContext{Workspace: "/workspace"},
+	}
 
 	for _, tt := range table {
 		t.Run(tt.name, func(t *testing.T) {
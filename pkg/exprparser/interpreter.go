@@ -2,15 +2,22 @@ package exprparser
 
 import (
 	"encoding"
+	"errors"
 	"fmt"
+	"io/fs"
 	"math"
 	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/nektos/act/pkg/model"
 	"github.com/rhysd/actionlint"
 )
 
+// ErrUnsupported is wrapped by the errors of the expressions using a context or a function act doesn't support
+var ErrUnsupported = errors.New("not supported by act")
+
 type EvaluationEnvironment struct {
 	Github   *model.GithubContext
 	Env      map[string]string
@@ -21,7 +28,9 @@ type EvaluationEnvironment struct {
 	Strategy map[string]interface{}
 	Matrix   map[string]interface{}
 	Needs    map[string]Needs
+	Jobs     map[string]Needs // the jobs of a reusable workflow, only available to the values of its outputs
 	Inputs   map[string]interface{}
+	Vars     map[string]string
 }
 
 type Needs struct {
@@ -33,8 +42,16 @@ type Config struct {
 	Run        *model.Run
 	WorkingDir string
 	Context    string
+	Trace      TraceFunc // receives the values of the context references and function calls of the expressions, if set
+	// Workspace returns the files hashFiles looks up, the files of WorkingDir if nil. The job container has the
+	// workspace of a job which isn't bound to the working directory.
+	Workspace func() (fs.FS, error)
 }
 
+// filteredArray is the array of an object filter, e.g. needs.*.result. Unlike an array its properties are the
+// properties of its items.
+type filteredArray []interface{}
+
 type DefaultStatusCheck int
 
 const (
@@ -66,6 +83,8 @@ type Interpreter interface {
 type interperterImpl struct {
 	env    *EvaluationEnvironment
 	config Config
+	traced map[actionlint.ExprNode]bool // the nodes of the expression passed to config.Trace
+	source string                       // the expression the traced nodes are written in
 }
 
 func NewInterpeter(env *EvaluationEnvironment, config Config) Interpreter {
@@ -109,12 +128,36 @@ func (impl *interperterImpl) Evaluate(input string, defaultStatusCheck DefaultSt
 		}
 	}
 
-	result, err2 := impl.evaluateNode(exprNode)
+	evaluator := impl
+	if impl.config.Trace != nil {
+		// a copy of the interpreter, which may evaluate other expressions concurrently
+		tracer := *impl
+		tracer.traced = tracedNodes(exprNode)
+		tracer.source = input
+		evaluator = &tracer
+	}
+
+	result, err2 := evaluator.evaluateNode(exprNode)
+	if err2 != nil {
+		return nil, err2
+	}
 
-	return result, err2
+	// a filtered array is an array to the callers
+	if filtered, ok := result.(filteredArray); ok {
+		return []interface{}(filtered), nil
+	}
+	return result, nil
 }
 
 func (impl *interperterImpl) evaluateNode(exprNode actionlint.ExprNode) (interface{}, error) {
+	value, err := impl.evaluateNodeValue(exprNode)
+	if err == nil && impl.traced[exprNode] {
+		impl.config.Trace(sourceString(impl.source, exprNode), value)
+	}
+	return value, err
+}
+
+func (impl *interperterImpl) evaluateNodeValue(exprNode actionlint.ExprNode) (interface{}, error) {
 	switch node := exprNode.(type) {
 	case *actionlint.VariableNode:
 		return impl.evaluateVariable(node)
@@ -167,14 +210,18 @@ func (impl *interperterImpl) evaluateVariable(variableNode *actionlint.VariableN
 		return impl.env.Matrix, nil
 	case "needs":
 		return impl.env.Needs, nil
+	case "jobs":
+		return impl.env.Jobs, nil
 	case "inputs":
 		return impl.env.Inputs, nil
+	case "vars":
+		return impl.env.Vars, nil
 	case "infinity":
 		return math.Inf(1), nil
 	case "nan":
 		return math.NaN(), nil
 	default:
-		return nil, fmt.Errorf("Unavailable context: %s", variableNode.Name)
+		return nil, fmt.Errorf("Unavailable context: %s (%w)", variableNode.Name, ErrUnsupported)
 	}
 }
 
@@ -228,7 +275,39 @@ func (impl *interperterImpl) evaluateArrayDeref(arrayDerefNode *actionlint.Array
 		return nil, err
 	}
 
-	return impl.getSafeValue(reflect.ValueOf(left)), nil
+	return impl.filter(reflect.ValueOf(left)), nil
+}
+
+// filter applies the object filter: it returns the values of an object, the items of an array and the items of the
+// arrays and objects of a filtered array. The filter of any other value is empty.
+func (impl *interperterImpl) filter(value reflect.Value) filteredArray {
+	result := filteredArray{}
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return impl.filter(value.Elem())
+
+	case reflect.Slice:
+		if filtered, ok := value.Interface().(filteredArray); ok {
+			for _, item := range filtered {
+				result = append(result, impl.filter(reflect.ValueOf(item))...)
+			}
+			return result
+		}
+		for i := 0; i < value.Len(); i++ {
+			result = append(result, value.Index(i).Interface())
+		}
+
+	case reflect.Map:
+		// the keys are sorted as the order of the keys of an object is lost
+		keys := value.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+		for _, key := range keys {
+			result = append(result, value.MapIndex(key).Interface())
+		}
+	}
+	return result
 }
 
 func (impl *interperterImpl) getPropertyValue(left reflect.Value, property string) (value interface{}, err error) {
@@ -285,15 +364,22 @@ func (impl *interperterImpl) getPropertyValue(left reflect.Value, property strin
 		return nil, nil
 
 	case reflect.Slice:
-		var values []interface{}
+		// the property of the items of a filtered array, an array has no properties
+		filtered, ok := left.Interface().(filteredArray)
+		if !ok {
+			return nil, nil
+		}
+		values := filteredArray{}
 
-		for i := 0; i < left.Len(); i++ {
-			value, err := impl.getPropertyValue(left.Index(i).Elem(), property)
+		for _, item := range filtered {
+			value, err := impl.getPropertyValue(reflect.ValueOf(item), property)
 			if err != nil {
 				return nil, err
 			}
 
-			values = append(values, value)
+			if value != nil {
+				values = append(values, value)
+			}
 		}
 
 		return values, nil
@@ -366,6 +452,28 @@ func (impl *interperterImpl) compareValues(leftValue reflect.Value, rightValue r
 
 		return impl.compareNumber(leftValue.Float(), rightValue.Float(), kind)
 
+	case reflect.Invalid:
+		// null == null
+		return impl.compareNumber(0, 0, kind)
+
+	case reflect.Map, reflect.Slice, reflect.Ptr, reflect.Struct:
+		// like on GitHub objects and arrays are only equal to themselves and aren't ordered
+		same := false
+		switch leftValue.Kind() {
+		case reflect.Map, reflect.Slice:
+			same = leftValue.Pointer() == rightValue.Pointer() && leftValue.Len() == rightValue.Len()
+		case reflect.Ptr:
+			same = leftValue.Pointer() == rightValue.Pointer()
+		}
+		switch kind {
+		case actionlint.CompareOpNodeKindEq:
+			return same, nil
+		case actionlint.CompareOpNodeKindNotEq:
+			return !same, nil
+		default:
+			return false, nil
+		}
+
 	default:
 		return nil, fmt.Errorf("TODO: evaluateCompare not implemented! left: %+v, right: %+v", leftValue.Kind(), rightValue.Kind())
 	}
@@ -385,21 +493,43 @@ func (impl *interperterImpl) coerceToNumber(value reflect.Value) reflect.Value {
 		}
 
 	case reflect.String:
-		if value.String() == "" {
-			return reflect.ValueOf(0)
-		}
+		return parseNumber(value.String())
+	}
 
-		// try to parse the string as a number
-		evaluated, err := impl.Evaluate(value.String(), DefaultStatusCheckNone)
-		if err != nil {
-			return reflect.ValueOf(math.NaN())
-		}
+	return reflect.ValueOf(math.NaN())
+}
 
-		if value := reflect.ValueOf(evaluated); impl.isNumber(value) {
-			return value
+// parseNumber converts a string to a number like GitHub: surrounding whitespace is ignored, an empty string is 0,
+// hexadecimal (0x) and octal (0o) integers, exponents and Infinity are parsed, anything else is NaN. A string isn't
+// evaluated as an expression.
+func parseNumber(s string) reflect.Value {
+	s = strings.TrimSpace(s)
+	switch {
+	case s == "":
+		return reflect.ValueOf(0)
+	case s == "Infinity", s == "+Infinity":
+		return reflect.ValueOf(math.Inf(1))
+	case s == "-Infinity":
+		return reflect.ValueOf(math.Inf(-1))
+	case strings.HasPrefix(s, "0x"), strings.HasPrefix(s, "0o"):
+		base := 16
+		if s[1] == 'o' {
+			base = 8
+		}
+		if n, err := strconv.ParseInt(s[2:], base, 64); err == nil && !strings.ContainsAny(s[2:], "+-_") {
+			return reflect.ValueOf(int(n))
 		}
+		return reflect.ValueOf(math.NaN())
+	case strings.ContainsAny(s, "_xXpPnNiI"):
+		// the underscores, hexadecimal floats, nan and inf of strconv
+		return reflect.ValueOf(math.NaN())
+	}
+	if n, err := strconv.Atoi(s); err == nil {
+		return reflect.ValueOf(n)
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return reflect.ValueOf(f)
 	}
-
 	return reflect.ValueOf(math.NaN())
 }
 
@@ -616,6 +746,6 @@ func (impl *interperterImpl) evaluateFuncCall(funcCallNode *actionlint.FuncCallN
 	case "cancelled":
 		return impl.cancelled()
 	default:
-		return nil, fmt.Errorf("TODO: '%s' not implemented", funcCallNode.Callee)
+		return nil, fmt.Errorf("TODO: '%s' not implemented (%w)", funcCallNode.Callee, ErrUnsupported)
 	}
 }
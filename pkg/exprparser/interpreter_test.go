@@ -530,7 +530,7 @@ func TestContexts(t *testing.T) {
 		{"github.action", "push", "github-context"},
 		{"github.event.commits[0].message", nil, "github-context-noexist-prop"},
 		{"fromjson('{\"commits\":[]}').commits[0].message", nil, "github-context-noexist-prop"},
-		{"github.event.pull_request.labels.*.name", nil, "github-context-noexist-prop"},
+		{"github.event.pull_request.labels.*.name", []interface{}{}, "github-context-noexist-prop"},
 		{"env.TEST", "value", "env-context"},
 		{"job.status", "success", "job-context"},
 		{"steps.step-id.outputs.name", "value", "steps-context"},
@@ -546,16 +546,19 @@ func TestContexts(t *testing.T) {
 		{"steps['step-id']['outcome'] && true", true, "steps-context-outcome"},
 		{"steps.step-id2.outcome", "failure", "steps-context-outcome"},
 		{"steps.step-id2.outcome && true", true, "steps-context-outcome"},
-		// Disabled, since the interpreter is still too broken
-		// {"contains(steps.*.outcome, 'success')", true, "steps-context-array-outcome"},
-		// {"contains(steps.*.outcome, 'failure')", true, "steps-context-array-outcome"},
-		// {"contains(steps.*.outputs.name, 'value')", true, "steps-context-array-outputs"},
+		{"contains(steps.*.outcome, 'success')", true, "steps-context-array-outcome"},
+		{"contains(steps.*.outcome, 'failure')", true, "steps-context-array-outcome"},
+		{"contains(steps.*.outputs.name, 'value')", true, "steps-context-array-outputs"},
+		{"steps.*.conclusion", []interface{}{"success", "skipped"}, "steps-context-array-conclusion"},
+		{"needs.*.result", []interface{}{"success"}, "needs-context-array-result"},
 		{"runner.os", "Linux", "runner-context"},
 		{"secrets.name", "value", "secrets-context"},
 		{"strategy.fail-fast", true, "strategy-context"},
 		{"matrix.os", "Linux", "matrix-context"},
 		{"needs.job-id.outputs.output-name", "value", "needs-context"},
 		{"needs.job-id.result", "success", "needs-context"},
+		{"jobs.job-id.outputs.output-name", "value", "jobs-context"},
+		{"jobs.job-id.result", "failure", "jobs-context"},
 		{"inputs.name", "value", "inputs-context"},
 	}
 
@@ -602,6 +605,14 @@ func TestContexts(t *testing.T) {
 				Result: "success",
 			},
 		},
+		Jobs: map[string]Needs{
+			"job-id": {
+				Outputs: map[string]string{
+					"output-name": "value",
+				},
+				Result: "failure",
+			},
+		},
 		Inputs: map[string]interface{}{
 			"name": "value",
 		},
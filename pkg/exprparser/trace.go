@@ -0,0 +1,102 @@
+package exprparser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rhysd/actionlint"
+)
+
+// TraceFunc receives the source and the value of a context reference or function call evaluated by an expression
+type TraceFunc func(expr string, value interface{})
+
+var compareOperators = map[actionlint.CompareOpNodeKind]string{
+	actionlint.CompareOpNodeKindLess:      "<",
+	actionlint.CompareOpNodeKindLessEq:    "<=",
+	actionlint.CompareOpNodeKindGreater:   ">",
+	actionlint.CompareOpNodeKindGreaterEq: ">=",
+	actionlint.CompareOpNodeKindEq:        "==",
+	actionlint.CompareOpNodeKindNotEq:     "!=",
+}
+
+// tracedNodes returns the nodes of the expression passed to Config.Trace: the function calls and the context
+// references which aren't the receiver of a longer reference, github.event.action is traced but not github.event
+func tracedNodes(exprNode actionlint.ExprNode) map[actionlint.ExprNode]bool {
+	traced := map[actionlint.ExprNode]bool{}
+	actionlint.VisitExprNode(exprNode, func(node, parent actionlint.ExprNode, entering bool) {
+		if !entering {
+			return
+		}
+		switch node.(type) {
+		case *actionlint.FuncCallNode:
+			traced[node] = true
+		case *actionlint.VariableNode, *actionlint.ObjectDerefNode, *actionlint.ArrayDerefNode, *actionlint.IndexAccessNode:
+			traced[node] = !isReceiver(node, parent)
+		}
+	})
+	return traced
+}
+
+func isReceiver(node actionlint.ExprNode, parent actionlint.ExprNode) bool {
+	switch parent := parent.(type) {
+	case *actionlint.ObjectDerefNode:
+		return parent.Receiver == node
+	case *actionlint.ArrayDerefNode:
+		return parent.Receiver == node
+	case *actionlint.IndexAccessNode:
+		return parent.Operand == node
+	}
+	return false
+}
+
+// sourceString returns the source of the node as written in the expression, e.g. secrets.MY_SECRET rather than the
+// secrets.my_secret of the parser, or the normalized source if the node isn't written like it
+func sourceString(source string, exprNode actionlint.ExprNode) string {
+	normalized := exprString(exprNode)
+	token := exprNode.Token()
+	if token == nil {
+		return normalized
+	}
+	start, end := token.Offset, token.Offset+len(normalized)
+	if start >= 0 && end <= len(source) && strings.EqualFold(source[start:end], normalized) {
+		return source[start:end]
+	}
+	return normalized
+}
+
+// exprString returns the source of the node, normalized
+func exprString(exprNode actionlint.ExprNode) string {
+	switch node := exprNode.(type) {
+	case *actionlint.VariableNode:
+		return node.Name
+	case *actionlint.BoolNode:
+		return fmt.Sprintf("%t", node.Value)
+	case *actionlint.NullNode:
+		return "null"
+	case *actionlint.IntNode:
+		return fmt.Sprintf("%d", node.Value)
+	case *actionlint.FloatNode:
+		return fmt.Sprintf("%g", node.Value)
+	case *actionlint.StringNode:
+		return "'" + strings.ReplaceAll(node.Value, "'", "''") + "'"
+	case *actionlint.ObjectDerefNode:
+		return exprString(node.Receiver) + "." + node.Property
+	case *actionlint.ArrayDerefNode:
+		return exprString(node.Receiver) + ".*"
+	case *actionlint.IndexAccessNode:
+		return exprString(node.Operand) + "[" + exprString(node.Index) + "]"
+	case *actionlint.NotOpNode:
+		return "!" + exprString(node.Operand)
+	case *actionlint.CompareOpNode:
+		return exprString(node.Left) + " " + compareOperators[node.Kind] + " " + exprString(node.Right)
+	case *actionlint.LogicalOpNode:
+		return exprString(node.Left) + " " + node.Kind.String() + " " + exprString(node.Right)
+	case *actionlint.FuncCallNode:
+		args := make([]string, 0, len(node.Args))
+		for _, arg := range node.Args {
+			args = append(args, exprString(arg))
+		}
+		return node.Callee + "(" + strings.Join(args, ", ") + ")"
+	}
+	return fmt.Sprintf("%v", exprNode)
+}
@@ -0,0 +1,49 @@
+package exprparser
+
+import (
+	"testing"
+
+	"github.com/nektos/act/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrace(t *testing.T) {
+	env := &EvaluationEnvironment{
+		Github: &model.GithubContext{
+			EventName: "pull_request",
+			Ref:       "refs/heads/main",
+		},
+		Matrix: map[string]interface{}{"os": "ubuntu-latest"},
+	}
+
+	traced := []string{}
+	values := []interface{}{}
+	output, err := NewInterpeter(env, Config{
+		Trace: func(expr string, value interface{}) {
+			traced = append(traced, expr)
+			values = append(values, value)
+		},
+	}).Evaluate("github.event_name == 'push' && !startsWith(github.ref, 'refs/tags/') || matrix['os'] == format('{0}-latest', 'macos')", DefaultStatusCheckNone)
+	assert.NoError(t, err)
+	assert.Equal(t, false, output)
+
+	// the receivers of the references, github and matrix, aren't traced
+	assert.Equal(t, []string{
+		"github.event_name",
+		"github.ref",
+		"startsWith(github.ref, 'refs/tags/')",
+		"matrix['os']",
+		"format('{0}-latest', 'macos')",
+	}, traced)
+	assert.Equal(t, []interface{}{"pull_request", "refs/heads/main", false, "ubuntu-latest", "macos-latest"}, values)
+
+	// the references are traced as written, the parser normalizes them to lower case
+	traced = traced[:0]
+	_, err = NewInterpeter(env, Config{
+		Trace: func(expr string, value interface{}) {
+			traced = append(traced, expr)
+		},
+	}).Evaluate("${{ GitHub.Event_Name == 'push' }}", DefaultStatusCheckNone)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"GitHub.Event_Name"}, traced)
+}
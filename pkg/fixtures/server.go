@@ -0,0 +1,75 @@
+// Package fixtures serves a directory of the host to the jobs, e.g. the seed data of the integration tests of a
+// workflow
+package fixtures
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/sirupsen/logrus"
+)
+
+// URLEnv is the variable of the jobs with the URL of the fixtures server
+const URLEnv = "ACT_FIXTURES_URL"
+
+// Handler returns the handler serving the files of dir read-only, a directory lists its files
+func Handler(dir string, logger logrus.FieldLogger) http.Handler {
+	files := http.FileServer(http.Dir(dir))
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet && req.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "the fixtures server is read-only", http.StatusMethodNotAllowed)
+			return
+		}
+		logger.Debugf("Fixtures server %s %s", req.Method, req.URL.Path)
+		files.ServeHTTP(w, req)
+	})
+}
+
+// Serve starts the server of the fixtures in dir, the jobs find it at ACT_FIXTURES_URL. It doesn't start if dir is
+// empty, the returned function stops it.
+func Serve(ctx context.Context, dir string, addr string, port string) context.CancelFunc {
+	serverContext, cancel := context.WithCancel(ctx)
+	logger := common.Logger(serverContext)
+
+	if dir == "" {
+		return cancel
+	}
+
+	if info, err := os.Stat(dir); err != nil {
+		logger.Fatal(err)
+	} else if !info.IsDir() {
+		logger.Fatalf("The fixtures %s are not a directory", dir)
+	}
+	logger.Debugf("Fixtures server path '%s'", dir)
+
+	server := &http.Server{
+		Addr:              net.JoinHostPort(addr, port),
+		ReadHeaderTimeout: 2 * time.Second,
+		Handler:           Handler(dir, logger),
+	}
+
+	// run server
+	go func() {
+		logger.Infof("Start fixtures server on http://%s", server.Addr)
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logger.Fatal(err)
+		}
+	}()
+
+	// wait for cancel to gracefully shutdown server
+	go func() {
+		<-serverContext.Done()
+
+		if err := server.Shutdown(ctx); err != nil {
+			logger.Errorf("Failed shutdown gracefully - force shutdown: %v", err)
+			server.Close()
+		}
+	}()
+
+	return cancel
+}
@@ -0,0 +1,47 @@
+package fixtures
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "seed"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "seed", "users.json"), []byte(`[{"name":"mona"}]`), 0o644))
+	server := httptest.NewServer(Handler(dir, log.StandardLogger()))
+	t.Cleanup(server.Close)
+
+	resp, err := http.Get(server.URL + "/seed/users.json")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, `[{"name":"mona"}]`, string(body))
+
+	resp, err = http.Get(server.URL + "/seed/missing.json")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	// the files are outside the reach of the jobs
+	resp, err = http.Get(server.URL + "/../../etc/passwd")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp, err = http.Post(server.URL+"/seed/users.json", "application/json", strings.NewReader("[]"))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
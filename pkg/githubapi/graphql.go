@@ -0,0 +1,224 @@
+package githubapi
+
+import (
+	"strings"
+)
+
+// graphQLMutationScopes maps the words of the names of the GraphQL mutations to the permission scope they need,
+// e.g. addLabelsToLabelable needs issues and mergePullRequest needs pull-requests
+var graphQLMutationScopes = []struct {
+	word  string
+	scope string
+}{
+	{"PullRequest", "pull-requests"},
+	{"Review", "pull-requests"},
+	{"Discussion", "discussions"},
+	{"Project", "repository-projects"},
+	{"Issue", "issues"},
+	{"Comment", "issues"},
+	{"Label", "issues"},
+	{"Assignees", "issues"},
+	{"Milestone", "issues"},
+	{"Reaction", "issues"},
+	{"CommitOnBranch", "contents"},
+	{"Ref", "contents"},
+	{"Branch", "contents"},
+	{"Deployment", "deployments"},
+	{"Environment", "deployments"},
+	{"CheckRun", "checks"},
+	{"CheckSuite", "checks"},
+}
+
+// graphQLMutationScope returns the scope needed by a mutation, empty if it is unknown
+func graphQLMutationScope(field string) string {
+	for _, s := range graphQLMutationScopes {
+		if strings.Contains(field, s.word) {
+			return s.scope
+		}
+	}
+	return ""
+}
+
+// graphQLOperation is an operation (or fragment) of a GraphQL document with its top-level fields
+type graphQLOperation struct {
+	Type   string // query, mutation, subscription or fragment
+	Name   string // empty for anonymous operations
+	Fields []graphQLField
+}
+
+// graphQLField is a top-level field of an operation, "..." stands for a fragment spread, Fragment is the name of a
+// named fragment spread and empty for an inline fragment
+type graphQLField struct {
+	Name     string
+	Alias    string
+	Fragment string
+}
+
+// key returns the key of the field in the data of the response
+func (f graphQLField) key() string {
+	if f.Alias != "" {
+		return f.Alias
+	}
+	return f.Name
+}
+
+// graphQLOperations returns the operations of a GraphQL document, the arguments, directives and nested selections
+// are skipped
+func graphQLOperations(document string) []*graphQLOperation {
+	operations := make([]*graphQLOperation, 0)
+	var operation *graphQLOperation
+	depth, parens := 0, 0
+	alias := ""
+	for i := 0; i < len(document); i++ {
+		c := document[i]
+		switch {
+		case c == '#':
+			for i < len(document) && document[i] != '\n' {
+				i++
+			}
+		case c == '"':
+			if strings.HasPrefix(document[i:], `"""`) {
+				end := strings.Index(document[i+3:], `"""`)
+				if end < 0 {
+					return operations
+				}
+				i += end + 5
+				continue
+			}
+			for i++; i < len(document) && document[i] != '"'; i++ {
+				if document[i] == '\\' {
+					i++
+				}
+			}
+		case c == '(':
+			parens++
+		case c == ')':
+			parens--
+		case c == '{':
+			if depth == 0 && operation == nil {
+				// shorthand query
+				operation = &graphQLOperation{Type: "query"}
+				operations = append(operations, operation)
+			}
+			depth++
+		case c == '}':
+			depth--
+			if depth == 0 {
+				operation = nil
+			}
+		case c == '.' && strings.HasPrefix(document[i:], "..."):
+			i += 2
+			// skip the name of the fragment, or the type condition of an inline fragment
+			fragment := ""
+			for skip := 0; skip < 2; skip++ {
+				for i+1 < len(document) && strings.IndexByte(" \t\r\n,", document[i+1]) >= 0 {
+					i++
+				}
+				start := i + 1
+				for i+1 < len(document) && isGraphQLNameChar(document[i+1]) {
+					i++
+				}
+				if document[start:i+1] != "on" {
+					if skip == 0 {
+						fragment = document[start : i+1]
+					}
+					break
+				}
+			}
+			if depth == 1 && parens == 0 && operation != nil {
+				operation.Fields = append(operation.Fields, graphQLField{Name: "...", Fragment: fragment})
+			}
+		case c == '@':
+			// directive, skip its name
+			for i+1 < len(document) && isGraphQLNameChar(document[i+1]) {
+				i++
+			}
+		case isGraphQLNameChar(c):
+			start := i
+			for i+1 < len(document) && isGraphQLNameChar(document[i+1]) {
+				i++
+			}
+			name := document[start : i+1]
+			if parens > 0 {
+				continue
+			}
+			switch {
+			case depth == 0 && operation == nil:
+				switch name {
+				case "query", "mutation", "subscription", "fragment":
+					operation = &graphQLOperation{Type: name}
+					operations = append(operations, operation)
+				}
+			case depth == 0 && operation.Name == "":
+				operation.Name = name
+			case depth == 1 && operation != nil:
+				rest := strings.TrimLeft(document[i+1:], " \t\r\n,")
+				if strings.HasPrefix(rest, ":") {
+					alias = name
+					continue
+				}
+				operation.Fields = append(operation.Fields, graphQLField{Name: name, Alias: alias})
+				alias = ""
+			}
+		}
+	}
+	return operations
+}
+
+// graphQLMutations returns the top-level fields of the mutations (and subscriptions) of a GraphQL document, the
+// spreads of the fragments of the document are replaced by their fields. "..." stands for an inline fragment or the
+// spread of an unknown fragment, whose fields are unknown. Queries are not returned.
+func graphQLMutations(document string) []string {
+	operations := graphQLOperations(document)
+	fragments := map[string]*graphQLOperation{}
+	for _, operation := range operations {
+		if operation.Type == "fragment" {
+			fragments[operation.Name] = operation
+		}
+	}
+
+	fields := make([]string, 0)
+	var add func(operation *graphQLOperation, spread map[string]bool)
+	add = func(operation *graphQLOperation, spread map[string]bool) {
+		for _, field := range operation.Fields {
+			fragment, ok := fragments[field.Fragment]
+			switch {
+			case field.Name != "...":
+				fields = append(fields, field.Name)
+			case ok && !spread[field.Fragment]:
+				spread[field.Fragment] = true
+				add(fragment, spread)
+			case !ok:
+				fields = append(fields, field.Name)
+			}
+		}
+	}
+	for _, operation := range operations {
+		if operation.Type == "mutation" || operation.Type == "subscription" {
+			add(operation, map[string]bool{})
+		}
+	}
+	return fields
+}
+
+// findGraphQLOperation returns the operation of the document run by a request, the named operation or the only one
+func findGraphQLOperation(document string, operationName string) *graphQLOperation {
+	var found *graphQLOperation
+	for _, operation := range graphQLOperations(document) {
+		if operation.Type == "fragment" {
+			continue
+		}
+		if operationName == "" || operation.Name == operationName {
+			if found != nil {
+				// an anonymous request of a document with several operations is invalid
+				return nil
+			}
+			found = operation
+		}
+	}
+	return found
+}
+
+func isGraphQLNameChar(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
@@ -0,0 +1,173 @@
+package githubapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+)
+
+// GraphQLFixture is a response of the GraphQL API the proxy serves instead of GitHub, to the requests of the
+// operation whose variables contain the variables of the fixture
+type GraphQLFixture struct {
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables,omitempty"` // unset variables match any value
+	Response      *GraphQLResponse       `json:"response"`
+}
+
+// GraphQLResponse is the body of a response of the GraphQL API
+type GraphQLResponse struct {
+	Data       map[string]json.RawMessage `json:"data"`
+	Errors     []json.RawMessage          `json:"errors,omitempty"`
+	Extensions json.RawMessage            `json:"extensions,omitempty"`
+}
+
+// ReadGraphQLFixtures reads the fixtures of a JSON file, or of the JSON files of a directory in the order of their
+// names. A file contains a fixture or an array of fixtures.
+func ReadGraphQLFixtures(path string) ([]*GraphQLFixture, error) {
+	files := []string{path}
+	if info, err := os.Stat(path); err != nil {
+		return nil, err
+	} else if info.IsDir() {
+		if files, err = filepath.Glob(filepath.Join(path, "*.json")); err != nil {
+			return nil, err
+		}
+		sort.Strings(files)
+	}
+
+	fixtures := make([]*GraphQLFixture, 0)
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		content = bytes.TrimSpace(content)
+		if !bytes.HasPrefix(content, []byte("[")) {
+			content = append(append([]byte("["), content...), ']')
+		}
+		read := make([]*GraphQLFixture, 0)
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&read); err != nil {
+			return nil, fmt.Errorf("unable to read the GraphQL fixtures %s: %w", file, err)
+		}
+		for i, fixture := range read {
+			if fixture.OperationName == "" {
+				return nil, fmt.Errorf("unable to read the GraphQL fixtures %s: fixture %d has no operationName", file, i)
+			}
+			if fixture.Response == nil || (fixture.Response.Data == nil && len(fixture.Response.Errors) == 0) {
+				return nil, fmt.Errorf("unable to read the GraphQL fixtures %s: the response of %s has neither data nor errors", file, fixture.OperationName)
+			}
+		}
+		fixtures = append(fixtures, read...)
+	}
+	return fixtures, nil
+}
+
+// matches reports whether the fixture answers the operation with the variables
+func (f *GraphQLFixture) matches(operationName string, variables map[string]interface{}) bool {
+	if f.OperationName != operationName {
+		return false
+	}
+	for name, value := range f.Variables {
+		if !reflect.DeepEqual(value, variables[name]) {
+			return false
+		}
+	}
+	return true
+}
+
+// findGraphQLFixture returns the fixture matching most variables of the operation, the first one of equal fixtures
+func findGraphQLFixture(fixtures []*GraphQLFixture, operationName string, variables map[string]interface{}) *GraphQLFixture {
+	var found *GraphQLFixture
+	for _, fixture := range fixtures {
+		if fixture.matches(operationName, variables) && (found == nil || len(fixture.Variables) > len(found.Variables)) {
+			found = fixture
+		}
+	}
+	return found
+}
+
+// respond returns the response of the fixture to the operation, with the data of the top-level fields selected by
+// the operation. A field missing in the fixture is an error, the fixture doesn't answer the query.
+func (f *GraphQLFixture) respond(operation *graphQLOperation) (*GraphQLResponse, error) {
+	if f.Response.Data == nil {
+		return f.Response, nil
+	}
+	data := make(map[string]json.RawMessage, len(operation.Fields))
+	for _, field := range operation.Fields {
+		if field.Name == "..." {
+			// the fields of the fragment are unknown, respond with all data of the fixture
+			for key, value := range f.Response.Data {
+				data[key] = value
+			}
+			continue
+		}
+		value, ok := f.Response.Data[field.key()]
+		if !ok {
+			return nil, fmt.Errorf("the GraphQL fixture of %s has no data for the field '%s'", f.OperationName, field.key())
+		}
+		data[field.key()] = value
+	}
+	return &GraphQLResponse{Data: data, Errors: f.Response.Errors, Extensions: f.Response.Extensions}, nil
+}
+
+// serveGraphQLFixture responds to a GraphQL request with a fixture, it returns false if no fixture matches the
+// request and the request is to be forwarded to GitHub
+func (p *Proxy) serveGraphQLFixture(w http.ResponseWriter, req *http.Request, g *grant) bool {
+	var request struct {
+		Query         string                 `json:"query"`
+		OperationName string                 `json:"operationName"`
+		Variables     map[string]interface{} `json:"variables"`
+	}
+	if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, "Problems parsing JSON")
+		return true
+	}
+	if req.GetBody != nil {
+		// restore the body to forward the request
+		if body, err := req.GetBody(); err == nil {
+			req.Body = body
+		}
+	}
+
+	operation := findGraphQLOperation(request.Query, request.OperationName)
+	if operation == nil {
+		writeGraphQLError(w, "No operation named '%s' in the query", request.OperationName)
+		return true
+	}
+	fixture := findGraphQLFixture(p.fixtures, operation.Name, request.Variables)
+	if fixture == nil {
+		if p.token != "" {
+			return false
+		}
+		g.logger.Warnf("⚠ No GraphQL fixture for the %s %s with the variables %v", operation.Type, operation.Name, request.Variables)
+		writeGraphQLError(w, "act has no GraphQL fixture for the %s '%s' with these variables", operation.Type, operation.Name)
+		return true
+	}
+	response, err := fixture.respond(operation)
+	if err != nil {
+		g.logger.Warnf("⚠ %v", err)
+		writeGraphQLError(w, "%v", err)
+		return true
+	}
+	g.logger.Debugf("Serving the GraphQL fixture of the %s %s", operation.Type, operation.Name)
+	writeGraphQLResponse(w, response)
+	return true
+}
+
+// writeGraphQLError responds with a GraphQL error, which the GraphQL API returns with status 200
+func writeGraphQLError(w http.ResponseWriter, format string, args ...interface{}) {
+	message, _ := json.Marshal(fmt.Sprintf(format, args...))
+	writeGraphQLResponse(w, &GraphQLResponse{Errors: []json.RawMessage{json.RawMessage(`{"message":` + string(message) + `}`)}})
+}
+
+func writeGraphQLResponse(w http.ResponseWriter, response *GraphQLResponse) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,79 @@
+package githubapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadGraphQLFixtures(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "b.json"), []byte(`[
+  {"operationName": "Labels", "response": {"data": {"repository": {"labels": {"nodes": []}}}}},
+  {"operationName": "Labels", "variables": {"owner": "nektos"}, "response": {"data": {"repository": {"labels": {"nodes": [{"name": "bug"}]}}}}}
+]`), 0o600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "a.json"), []byte(`{"operationName": "Viewer", "response": {"data": {"viewer": {"login": "nektos"}}}}`), 0o600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte(`not a fixture`), 0o600))
+
+	fixtures, err := ReadGraphQLFixtures(dir)
+	assert.NoError(t, err)
+	assert.Len(t, fixtures, 3)
+	assert.Equal(t, "Viewer", fixtures[0].OperationName)
+	assert.Equal(t, map[string]interface{}{"owner": "nektos"}, fixtures[2].Variables)
+
+	fixtures, err = ReadGraphQLFixtures(filepath.Join(dir, "a.json"))
+	assert.NoError(t, err)
+	assert.Len(t, fixtures, 1)
+
+	invalid := filepath.Join(dir, "invalid.json")
+	assert.NoError(t, os.WriteFile(invalid, []byte(`{"operationName": "Viewer", "response": {"viewer": {"login": "nektos"}}}`), 0o600))
+	_, err = ReadGraphQLFixtures(invalid)
+	assert.ErrorContains(t, err, `unknown field "viewer"`)
+
+	assert.NoError(t, os.WriteFile(invalid, []byte(`{"response": {"data": {}}}`), 0o600))
+	_, err = ReadGraphQLFixtures(invalid)
+	assert.ErrorContains(t, err, "fixture 0 has no operationName")
+}
+
+func TestProxyServesGraphQLFixtures(t *testing.T) {
+	fixtures, err := ReadGraphQLFixtures(writeFixtures(t, `[
+  {"operationName": "Labels", "response": {"data": {"repository": {"labels": []}, "viewer": {"login": "nektos"}}}},
+  {"operationName": "Labels", "variables": {"owner": "nektos", "first": 10}, "response": {"data": {"repository": {"labels": ["bug"]}}}},
+  {"operationName": "Missing", "response": {"data": null, "errors": [{"type": "NOT_FOUND", "message": "Could not resolve to a Repository"}]}}
+]`))
+	assert.NoError(t, err)
+	proxy := &Proxy{grants: map[string]*grant{}, fixtures: fixtures}
+	token, err := proxy.Register(context.Background(), nil)
+	assert.NoError(t, err)
+
+	table := []struct {
+		body     string
+		response string
+	}{
+		{`{"query": "query Labels($owner: String!) { repository(owner: $owner) { labels } }", "variables": {"owner": "github"}}`, `{"data":{"repository":{"labels":[]}}}`},
+		{`{"query": "query Labels($owner: String!, $first: Int) { repository(owner: $owner) { labels } }", "variables": {"owner": "nektos", "first": 10}}`, `{"data":{"repository":{"labels":["bug"]}}}`},
+		{`{"query": "query Viewer { viewer { login } } query Labels { me: viewer { login } }", "operationName": "Labels"}`, `{"errors":[{"message":"the GraphQL fixture of Labels has no data for the field 'me'"}],"data":null}`},
+		{`{"query": "query Missing { repository(owner: \"nektos\", name: \"none\") { id } }"}`, `{"data":null,"errors":[{"type":"NOT_FOUND","message":"Could not resolve to a Repository"}]}`},
+		{`{"query": "query Unknown { viewer { login } }"}`, `{"data":null,"errors":[{"message":"act has no GraphQL fixture for the query 'Unknown' with these variables"}]}`},
+	}
+	for _, tt := range table {
+		req := httptest.NewRequest("POST", "/graphql", strings.NewReader(tt.body))
+		req.Header.Set("Authorization", "bearer "+token)
+		rec := httptest.NewRecorder()
+		proxy.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code, tt.body)
+		assert.JSONEq(t, tt.response, rec.Body.String(), tt.body)
+	}
+}
+
+func writeFixtures(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "fixtures.json")
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
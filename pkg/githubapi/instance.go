@@ -0,0 +1,64 @@
+package githubapi
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// URLs are the URLs of a GitHub instance exposed to the jobs
+type URLs struct {
+	ServerURL  string // GITHUB_SERVER_URL, the web interface and git
+	APIURL     string // GITHUB_API_URL, the REST API
+	GraphQLURL string // GITHUB_GRAPHQL_URL, the GraphQL API
+}
+
+// NewURLs derives the URLs of the GitHub instance, a GitHub Enterprise Server instance may have a scheme,
+// a port and a path prefix (e.g. ghe.example.com:8443/github)
+func NewURLs(instance string) (*URLs, error) {
+	raw := instance
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" || (u.Scheme != "https" && u.Scheme != "http") {
+		return nil, fmt.Errorf("invalid GitHub instance '%s', expected <host>[:<port>][/<path>]", instance)
+	}
+
+	path := strings.TrimSuffix(u.Path, "/")
+	if u.Host == "github.com" && path == "" {
+		return &URLs{
+			ServerURL:  "https://github.com",
+			APIURL:     "https://api.github.com",
+			GraphQLURL: "https://api.github.com/graphql",
+		}, nil
+	}
+
+	server := fmt.Sprintf("%s://%s%s", u.Scheme, u.Host, path)
+	return &URLs{
+		ServerURL:  server,
+		APIURL:     server + "/api/v3",
+		GraphQLURL: server + "/api/graphql",
+	}, nil
+}
+
+// Override replaces the URLs which are not empty, for instances serving the API on another host than the web interface
+func (u *URLs) Override(serverURL string, apiURL string, graphQLURL string) error {
+	for _, override := range []struct {
+		value  string
+		target *string
+	}{
+		{serverURL, &u.ServerURL},
+		{apiURL, &u.APIURL},
+		{graphQLURL, &u.GraphQLURL},
+	} {
+		if override.value == "" {
+			continue
+		}
+		if parsed, err := url.Parse(override.value); err != nil || parsed.Host == "" {
+			return fmt.Errorf("invalid URL '%s' of the GitHub instance", override.value)
+		}
+		*override.target = strings.TrimSuffix(override.value, "/")
+	}
+	return nil
+}
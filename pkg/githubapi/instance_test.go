@@ -0,0 +1,40 @@
+package githubapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewURLs(t *testing.T) {
+	table := []struct {
+		instance string
+		urls     *URLs
+	}{
+		{"github.com", &URLs{"https://github.com", "https://api.github.com", "https://api.github.com/graphql"}},
+		{"https://github.com/", &URLs{"https://github.com", "https://api.github.com", "https://api.github.com/graphql"}},
+		{"ghe.example.com", &URLs{"https://ghe.example.com", "https://ghe.example.com/api/v3", "https://ghe.example.com/api/graphql"}},
+		{"ghe.example.com:8443/github/", &URLs{"https://ghe.example.com:8443/github", "https://ghe.example.com:8443/github/api/v3", "https://ghe.example.com:8443/github/api/graphql"}},
+		{"http://localhost:3000", &URLs{"http://localhost:3000", "http://localhost:3000/api/v3", "http://localhost:3000/api/graphql"}},
+	}
+	for _, tt := range table {
+		t.Run(tt.instance, func(t *testing.T) {
+			urls, err := NewURLs(tt.instance)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.urls, urls)
+		})
+	}
+
+	_, err := NewURLs("ftp://ghe.example.com")
+	assert.Error(t, err)
+}
+
+func TestURLsOverride(t *testing.T) {
+	urls, err := NewURLs("ghe.example.com")
+	assert.NoError(t, err)
+
+	assert.NoError(t, urls.Override("", "https://api.ghe.example.com/", ""))
+	assert.Equal(t, &URLs{"https://ghe.example.com", "https://api.ghe.example.com", "https://ghe.example.com/api/graphql"}, urls)
+
+	assert.Error(t, urls.Override("", "", "not a url"))
+}
@@ -0,0 +1,292 @@
+package githubapi
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/model"
+)
+
+// Proxy forwards the GitHub API calls of the jobs to GitHub, every job gets its own token which
+// is only allowed to call the API endpoints covered by the `permissions:` of the job
+type Proxy struct {
+	URL string // URL of the proxy as seen from the containers
+
+	token    string
+	apiURL   *url.URL
+	graphURL *url.URL
+	server   *http.Server
+
+	fixtures []*GraphQLFixture
+
+	mu     sync.Mutex
+	grants map[string]*grant
+}
+
+type grant struct {
+	permissions model.Permissions
+	logger      logrus.FieldLogger
+}
+
+// NewProxy starts a proxy for the API of the GitHub instance on the given address, which the containers
+// reach through host. The calls are forwarded with the given token, the GraphQL requests matching a fixture are
+// answered by the fixture. Without a token the GraphQL requests matching no fixture fail.
+func NewProxy(ctx context.Context, urls *URLs, token string, fixtures []*GraphQLFixture, addr string, host string) (*Proxy, error) {
+	proxy := &Proxy{
+		token:    token,
+		fixtures: fixtures,
+		grants:   map[string]*grant{},
+	}
+	var err error
+	if proxy.apiURL, err = url.Parse(urls.APIURL); err != nil {
+		return nil, err
+	}
+	if proxy.graphURL, err = url.Parse(urls.GraphQLURL); err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("tcp", net.JoinHostPort(addr, "0"))
+	if err != nil {
+		return nil, err
+	}
+	_, port, err := net.SplitHostPort(listener.Addr().String())
+	if err != nil {
+		return nil, err
+	}
+	proxy.URL = fmt.Sprintf("http://%s", net.JoinHostPort(host, port))
+	proxy.server = &http.Server{
+		ReadHeaderTimeout: 2 * time.Second,
+		Handler:           proxy,
+	}
+
+	logger := common.Logger(ctx)
+	go func() {
+		logger.Debugf("Start GitHub API proxy on %s", proxy.URL)
+		if err := proxy.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			logger.Errorf("GitHub API proxy failed: %v", err)
+		}
+	}()
+
+	return proxy, nil
+}
+
+// Close stops the proxy
+func (p *Proxy) Close() error {
+	return p.server.Close()
+}
+
+// Register issues a token for a job, the token grants the given permissions until it is revoked. Denied calls are
+// logged with the logger of the context.
+func (p *Proxy) Register(ctx context.Context, permissions model.Permissions) (string, error) {
+	b := make([]byte, 20)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("unable to issue a GitHub API token: %w", err)
+	}
+	token := "ghs_" + hex.EncodeToString(b)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.grants[token] = &grant{
+		permissions: permissions,
+		logger:      common.Logger(ctx),
+	}
+	return token, nil
+}
+
+// Revoke revokes a token issued by Register, the calls with it are rejected afterwards
+func (p *Proxy) Revoke(token string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.grants, token)
+}
+
+// maxGraphQLRequestSize limits the size of the GraphQL requests read to check their operations
+const maxGraphQLRequestSize = 10 << 20
+
+// allowsGraphQL checks the mutations of a GraphQL request against the permissions, queries are allowed.
+// The body of the request is restored to be forwarded.
+func (g *grant) allowsGraphQL(w http.ResponseWriter, req *http.Request) bool {
+	body, err := io.ReadAll(io.LimitReader(req.Body, maxGraphQLRequestSize+1))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Unable to read the request")
+		return false
+	}
+	if len(body) > maxGraphQLRequestSize {
+		writeError(w, http.StatusRequestEntityTooLarge, "Request too large")
+		return false
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+
+	var request struct {
+		Query string `json:"query"`
+	}
+	if err := json.Unmarshal(body, &request); err != nil {
+		writeError(w, http.StatusBadRequest, "Problems parsing JSON")
+		return false
+	}
+	for _, field := range graphQLMutations(request.Query) {
+		if scope := graphQLMutationScope(field); scope != "" {
+			if !g.permissions.Allows(scope, model.PermissionWrite) {
+				g.logger.Warnf("⚠ GitHub GraphQL mutation %s requires '%s: %s' which is not granted by the permissions of the job", field, scope, model.PermissionWrite)
+				writeError(w, http.StatusForbidden, "Resource not accessible by integration")
+				return false
+			}
+			continue
+		}
+		// the resources changed by the mutation are unknown, it may change anything the job could change
+		for _, scope := range model.PermissionScopes {
+			if !g.permissions.Allows(scope, model.PermissionWrite) {
+				g.logger.Warnf("⚠ GitHub GraphQL mutation %s requires write access to every scope, its scope is unknown to act", field)
+				writeError(w, http.StatusForbidden, "Resource not accessible by integration")
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (p *Proxy) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	p.mu.Lock()
+	g, ok := p.grants[requestToken(req)]
+	p.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "Bad credentials")
+		return
+	}
+
+	upstream := p.apiURL
+	if req.URL.Path == "/graphql" {
+		upstream = p.graphURL
+		if !g.allowsGraphQL(w, req) {
+			return
+		}
+		if len(p.fixtures) > 0 && p.serveGraphQLFixture(w, req, g) {
+			return
+		}
+	} else {
+		scope, access := Scope(req.Method, req.URL.Path)
+		if !g.permissions.Allows(scope, access) {
+			g.logger.Warnf("⚠ GitHub API call %s %s requires '%s: %s' which is not granted by the permissions of the job", req.Method, req.URL.Path, scope, access)
+			writeError(w, http.StatusForbidden, "Resource not accessible by integration")
+			return
+		}
+	}
+
+	proxy := &httputil.ReverseProxy{
+		Transport: common.NewRetryTransport(http.DefaultTransport),
+		Director: func(r *http.Request) {
+			r.URL.Scheme = upstream.Scheme
+			r.URL.Host = upstream.Host
+			if upstream == p.graphURL {
+				r.URL.Path = upstream.Path
+			} else {
+				r.URL.Path = strings.TrimSuffix(upstream.Path, "/") + r.URL.Path
+			}
+			r.Host = upstream.Host
+			if p.token != "" {
+				r.Header.Set("Authorization", "token "+p.token)
+			} else {
+				r.Header.Del("Authorization")
+			}
+		},
+	}
+	proxy.ServeHTTP(w, req)
+}
+
+// Scope returns the permission scope and access level required by a call of the REST API
+func Scope(method string, path string) (scope string, access string) {
+	access = model.PermissionWrite
+	if method == http.MethodGet || method == http.MethodHead {
+		access = model.PermissionRead
+	}
+
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(segments) > 0 && segments[0] == "projects" {
+		return "repository-projects", access
+	}
+	if len(segments) >= 3 && (segments[0] == "orgs" || segments[0] == "users" || segments[0] == "user") {
+		for _, s := range segments {
+			if s == "packages" {
+				return "packages", access
+			}
+		}
+	}
+	if len(segments) < 4 || segments[0] != "repos" {
+		return "metadata", access
+	}
+
+	switch segments[3] {
+	case "actions":
+		scope = "actions"
+	case "check-runs", "check-suites":
+		scope = "checks"
+	case "commits":
+		scope = "contents"
+		if len(segments) >= 6 && (segments[5] == "status" || segments[5] == "statuses") {
+			scope = "statuses"
+		} else if len(segments) >= 6 && (segments[5] == "check-runs" || segments[5] == "check-suites") {
+			scope = "checks"
+		}
+	case "contents", "git", "branches", "tags", "releases", "compare", "tarball", "zipball", "merges", "dispatches", "readme":
+		scope = "contents"
+	case "deployments", "environments":
+		scope = "deployments"
+	case "discussions":
+		scope = "discussions"
+	case "issues", "labels", "milestones", "assignees":
+		scope = "issues"
+	case "pulls":
+		scope = "pull-requests"
+	case "pages":
+		scope = "pages"
+	case "statuses":
+		scope = "statuses"
+	case "packages":
+		scope = "packages"
+	case "code-scanning", "secret-scanning", "dependabot":
+		scope = "security-events"
+	case "projects":
+		scope = "repository-projects"
+	default:
+		scope = "metadata"
+	}
+	return scope, access
+}
+
+func requestToken(req *http.Request) string {
+	if _, password, ok := req.BasicAuth(); ok {
+		return password
+	}
+	auth := req.Header.Get("Authorization")
+	if i := strings.IndexByte(auth, ' '); i >= 0 {
+		return strings.TrimSpace(auth[i+1:])
+	}
+	return auth
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{
+		"message":           message,
+		"documentation_url": "https://docs.github.com/rest",
+	})
+}
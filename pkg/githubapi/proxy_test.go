@@ -0,0 +1,149 @@
+package githubapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nektos/act/pkg/model"
+)
+
+func TestScope(t *testing.T) {
+	table := []struct {
+		method string
+		path   string
+		scope  string
+		access string
+	}{
+		{"GET", "/repos/nektos/act/contents/README.md", "contents", model.PermissionRead},
+		{"POST", "/repos/nektos/act/git/refs", "contents", model.PermissionWrite},
+		{"POST", "/repos/nektos/act/issues/1/comments", "issues", model.PermissionWrite},
+		{"PATCH", "/repos/nektos/act/pulls/1", "pull-requests", model.PermissionWrite},
+		{"POST", "/repos/nektos/act/statuses/abc", "statuses", model.PermissionWrite},
+		{"GET", "/repos/nektos/act/commits/abc/status", "statuses", model.PermissionRead},
+		{"GET", "/repos/nektos/act/commits/abc/check-runs", "checks", model.PermissionRead},
+		{"GET", "/repos/nektos/act/commits/abc", "contents", model.PermissionRead},
+		{"DELETE", "/repos/nektos/act/actions/caches", "actions", model.PermissionWrite},
+		{"GET", "/orgs/nektos/packages/container/act", "packages", model.PermissionRead},
+		{"GET", "/repos/nektos/act", "metadata", model.PermissionRead},
+		{"GET", "/rate_limit", "metadata", model.PermissionRead},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			scope, access := Scope(tt.method, tt.path)
+			assert.Equal(t, tt.scope, scope)
+			assert.Equal(t, tt.access, access)
+		})
+	}
+}
+
+func TestProxyRejectsCalls(t *testing.T) {
+	proxy := &Proxy{grants: map[string]*grant{}}
+	token, err := proxy.Register(context.Background(), model.NewPermissions(model.PermissionNone))
+	assert.NoError(t, err)
+
+	req := httptest.NewRequest("GET", "/repos/nektos/act/contents/README.md", nil)
+	rec := httptest.NewRecorder()
+	proxy.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	req.Header.Set("Authorization", "token "+token)
+	rec = httptest.NewRecorder()
+	proxy.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Contains(t, rec.Body.String(), "Resource not accessible by integration")
+
+	proxy.Revoke(token)
+	rec = httptest.NewRecorder()
+	proxy.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestProxyRejectsGraphQLMutations(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer upstream.Close()
+	graphURL, err := url.Parse(upstream.URL + "/graphql")
+	assert.NoError(t, err)
+
+	proxy := &Proxy{grants: map[string]*grant{}, graphURL: graphURL}
+	permissions := model.NewPermissions(model.PermissionRead)
+	permissions["issues"] = model.PermissionWrite
+	token, err := proxy.Register(context.Background(), permissions)
+	assert.NoError(t, err)
+
+	table := []struct {
+		query string
+		code  int
+	}{
+		{`mutation { mergePullRequest(input: {pullRequestId: "1"}) { clientMutationId } }`, http.StatusForbidden},
+		{`mutation Update { deleteRepository: deleteRef(input: {refId: "1"}) { clientMutationId } }`, http.StatusForbidden},
+		{`mutation { archiveRepository(input: {repositoryId: "1"}) { clientMutationId } }`, http.StatusForbidden},
+		{`mutation($id: ID!) { ...Fields }`, http.StatusForbidden},
+		{`fragment Merge on Mutation { mergePullRequest(input: {pullRequestId: "1"}) { clientMutationId } } mutation { ...Merge }`, http.StatusForbidden},
+		{`fragment Comment on Mutation { addComment(input: {subjectId: "1", body: ""}) { clientMutationId } } mutation { ...Comment }`, http.StatusOK},
+		{`mutation { addComment(input: {subjectId: "1", body: "mutation { mergePullRequest }"}) { clientMutationId } }`, http.StatusOK},
+		{`query { repository(owner: "nektos", name: "act") { name } }`, http.StatusOK},
+		{`{ viewer { login } }`, http.StatusOK},
+		{`not json`, http.StatusBadRequest},
+	}
+	for _, tt := range table {
+		body := `{"query": ` + strconv.Quote(tt.query) + `}`
+		if tt.query == "not json" {
+			body = tt.query
+		}
+		req := httptest.NewRequest("POST", "/graphql", strings.NewReader(body))
+		req.Header.Set("Authorization", "bearer "+token)
+		rec := httptest.NewRecorder()
+		proxy.ServeHTTP(rec, req)
+		assert.Equal(t, tt.code, rec.Code, tt.query)
+	}
+}
+
+func TestGraphQLMutations(t *testing.T) {
+	table := []struct {
+		document string
+		fields   []string
+	}{
+		{`query { viewer { login } }`, []string{}},
+		{`{ viewer { login } }`, []string{}},
+		{`mutation { addComment(input: {body: "{ deleteRef }"}) { subject { id } } }`, []string{"addComment"}},
+		{`mutation M($input: CreateIssueInput!) @deprecated { issue: createIssue(input: $input) { issue { id } } closeIssue(input: {}) { clientMutationId } }`, []string{"createIssue", "closeIssue"}},
+		{"# mutation { deleteRef }\nquery { viewer { login } }", []string{}},
+		{`fragment F on Mutation { deleteRef(input: {}) { clientMutationId } ...G } fragment G on Mutation { ...F } mutation { ...F }`, []string{"deleteRef"}},
+		{`mutation { ...Unknown ... on Mutation { deleteRef(input: {}) { clientMutationId } } }`, []string{"...", "..."}},
+		{`mutation { createIssue(input: {body: """a "quoted" body"""}) { issue { id } } }`, []string{"createIssue"}},
+	}
+	for _, tt := range table {
+		assert.Equal(t, tt.fields, graphQLMutations(tt.document), tt.document)
+	}
+
+	assert.Equal(t, "pull-requests", graphQLMutationScope("addPullRequestReviewComment"))
+	assert.Equal(t, "issues", graphQLMutationScope("addLabelsToLabelable"))
+	assert.Equal(t, "contents", graphQLMutationScope("createCommitOnBranch"))
+	assert.Equal(t, "", graphQLMutationScope("archiveRepository"))
+}
+
+func TestGraphQLOperations(t *testing.T) {
+	operations := graphQLOperations(`fragment F on Issue { id }
+query Labels($owner: String!) @cached { repository(owner: $owner) { labels { nodes { name } } } me: viewer { login } ...F ... on Query { rateLimit { cost } } }
+mutation { addComment(input: {}) { clientMutationId } }`)
+	assert.Equal(t, []*graphQLOperation{
+		{Type: "fragment", Name: "F", Fields: []graphQLField{{Name: "id"}}},
+		{Type: "query", Name: "Labels", Fields: []graphQLField{{Name: "repository"}, {Name: "viewer", Alias: "me"}, {Name: "...", Fragment: "F"}, {Name: "..."}}},
+		{Type: "mutation", Fields: []graphQLField{{Name: "addComment"}}},
+	}, operations)
+
+	assert.Equal(t, "Labels", findGraphQLOperation(`query Viewer { viewer { login } } query Labels { viewer { login } }`, "Labels").Name)
+	assert.Equal(t, "query", findGraphQLOperation(`{ viewer { login } }`, "").Type)
+	assert.Nil(t, findGraphQLOperation(`query Viewer { viewer { login } } query Labels { viewer { login } }`, ""))
+	assert.Nil(t, findGraphQLOperation(`query Viewer { viewer { login } }`, "Labels"))
+}
@@ -0,0 +1,58 @@
+package githubapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/nektos/act/pkg/common"
+)
+
+// States of a commit status
+const (
+	StatusSuccess = "success"
+	StatusFailure = "failure"
+	StatusError   = "error"
+	StatusPending = "pending"
+)
+
+// CommitStatus is a status of a commit, https://docs.github.com/en/rest/commits/statuses
+type CommitStatus struct {
+	State       string `json:"state"`
+	Context     string `json:"context"` // the statuses of a commit are unique by their context
+	Description string `json:"description,omitempty"`
+	TargetURL   string `json:"target_url,omitempty"`
+}
+
+// CreateCommitStatus posts the status on the commit of the repository (owner/name) with the token
+func CreateCommitStatus(ctx context.Context, apiURL string, token string, repository string, sha string, status *CommitStatus) error {
+	body, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+	url := fmt.Sprintf("%s/repos/%s/statuses/%s", strings.TrimSuffix(apiURL, "/"), repository, sha)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{Transport: common.NewRetryTransport(http.DefaultTransport)}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("unable to create the status '%s' of %s@%s: %s: %s", status.Context, repository, sha, resp.Status, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
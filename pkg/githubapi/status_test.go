@@ -0,0 +1,33 @@
+package githubapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateCommitStatus(t *testing.T) {
+	var received *CommitStatus
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/nektos/act/statuses/abc" || r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message": "Not Found"}`))
+			return
+		}
+		received = &CommitStatus{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(received))
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	status := &CommitStatus{State: StatusSuccess, Context: "act (local) / ci.yml / test", Description: "Succeeded locally with act"}
+	assert.NoError(t, CreateCommitStatus(context.Background(), server.URL+"/", "token", "nektos/act", "abc", status))
+	assert.Equal(t, status, received)
+
+	err := CreateCommitStatus(context.Background(), server.URL, "other", "nektos/act", "abc", status)
+	assert.ErrorContains(t, err, `unable to create the status 'act (local) / ci.yml / test' of nektos/act@abc: 404 Not Found: {"message": "Not Found"}`)
+}
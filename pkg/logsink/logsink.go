@@ -0,0 +1,102 @@
+// Package logsink provides destinations for the log of a run in addition to the terminal
+package logsink
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Sink receives every log entry, it has to be closed to flush buffered entries
+type Sink interface {
+	logrus.Hook
+	io.Closer
+}
+
+// New creates a sink from its specification:
+//
+//	terminal            the terminal, which always receives the log
+//	file:<path>         text log appended to a file
+//	json:<path>         JSON lines appended to a file, json:- writes to stdout and json:stderr to stderr
+//	syslog[:<address>]  the local syslog or a remote one (e.g. syslog:udp://logs:514)
+//	loki:<url>          the push API of Grafana Loki (e.g. loki:http://localhost:3100)
+func New(spec string) (Sink, error) {
+	kind, target, _ := strings.Cut(spec, ":")
+	switch kind {
+	case "terminal":
+		return nopSink{}, nil
+	case "file":
+		if target == "" {
+			return nil, fmt.Errorf("log sink '%s' needs a path", spec)
+		}
+		return newWriterSink(target, &logrus.TextFormatter{DisableColors: true, FullTimestamp: true})
+	case "json":
+		if target == "" {
+			return nil, fmt.Errorf("log sink '%s' needs a path", spec)
+		}
+		return newWriterSink(target, &logrus.JSONFormatter{})
+	case "syslog":
+		return newSyslogSink(target)
+	case "loki":
+		if target == "" {
+			return nil, fmt.Errorf("log sink '%s' needs the URL of Loki", spec)
+		}
+		return newLokiSink(target)
+	}
+	return nil, fmt.Errorf("unknown log sink '%s', expected terminal, file:<path>, json:<path>, syslog[:<address>] or loki:<url>", spec)
+}
+
+type nopSink struct{}
+
+func (nopSink) Levels() []logrus.Level   { return nil }
+func (nopSink) Fire(*logrus.Entry) error { return nil }
+func (nopSink) Close() error             { return nil }
+
+// writerSink formats the entries and writes them to a file or a standard stream
+type writerSink struct {
+	mu        sync.Mutex
+	out       io.Writer
+	closer    io.Closer
+	formatter logrus.Formatter
+}
+
+func newWriterSink(target string, formatter logrus.Formatter) (*writerSink, error) {
+	switch target {
+	case "-", "stdout":
+		return &writerSink{out: os.Stdout, formatter: formatter}, nil
+	case "stderr":
+		return &writerSink{out: os.Stderr, formatter: formatter}, nil
+	}
+
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &writerSink{out: f, closer: f, formatter: formatter}, nil
+}
+
+func (s *writerSink) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (s *writerSink) Fire(entry *logrus.Entry) error {
+	b, err := s.formatter.Format(entry)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.out.Write(b)
+	return err
+}
+
+func (s *writerSink) Close() error {
+	if s.closer == nil {
+		return nil
+	}
+	return s.closer.Close()
+}
@@ -0,0 +1,68 @@
+package logsink
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewInvalid(t *testing.T) {
+	for _, spec := range []string{"", "file", "json:", "loki:", "loki:localhost:3100", "elastic:http://localhost"} {
+		_, err := New(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestJSONSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "act.log")
+	sink, err := New("json:" + path)
+	assert.NoError(t, err)
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	logger.AddHook(sink)
+	logger.WithField("job", "build").Info("hello")
+	assert.NoError(t, sink.Close())
+
+	b, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	entry := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(b, &entry))
+	assert.Equal(t, "hello", entry["msg"])
+	assert.Equal(t, "build", entry["job"])
+}
+
+func TestLokiSink(t *testing.T) {
+	pushed := make(chan map[string][]lokiStream, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/loki/api/v1/push", r.URL.Path)
+		body := map[string][]lokiStream{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		pushed <- body
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	sink, err := New("loki:" + server.URL)
+	assert.NoError(t, err)
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	logger.AddHook(sink)
+	logger.WithFields(logrus.Fields{"job": "build", "step": "test"}).Info("hello")
+	assert.NoError(t, sink.Close())
+
+	body := <-pushed
+	assert.Len(t, body["streams"], 1)
+	stream := body["streams"][0]
+	assert.Equal(t, map[string]string{"source": "act", "level": "info", "job": "build"}, stream.Stream)
+	assert.Len(t, stream.Values, 1)
+	assert.Equal(t, "[test] hello", stream.Values[0][1])
+}
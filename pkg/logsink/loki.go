@@ -0,0 +1,158 @@
+package logsink
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	lokiBatchSize     = 100
+	lokiFlushInterval = time.Second
+)
+
+// lokiSink pushes the entries in batches to the push API of Loki, the job and the level are used as labels
+type lokiSink struct {
+	url    string
+	client *http.Client
+
+	mu      sync.Mutex
+	streams map[string]*lokiStream
+	size    int
+	done    chan struct{}
+	wg      sync.WaitGroup
+}
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+func newLokiSink(url string) (*lokiSink, error) {
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		return nil, fmt.Errorf("invalid Loki URL '%s'", url)
+	}
+	s := &lokiSink{
+		url:     strings.TrimSuffix(url, "/") + "/loki/api/v1/push",
+		client:  &http.Client{Timeout: 10 * time.Second},
+		streams: map[string]*lokiStream{},
+		done:    make(chan struct{}),
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(lokiFlushInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.flush()
+			case <-s.done:
+				return
+			}
+		}
+	}()
+	return s, nil
+}
+
+func (s *lokiSink) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (s *lokiSink) Fire(entry *logrus.Entry) error {
+	labels := map[string]string{
+		"source": "act",
+		"level":  entry.Level.String(),
+	}
+	if job, ok := entry.Data["job"].(string); ok {
+		labels["job"] = strings.TrimSpace(job)
+	}
+
+	line := entry.Message
+	if step, ok := entry.Data["step"].(string); ok {
+		line = fmt.Sprintf("[%s] %s", step, line)
+	}
+
+	key := lokiStreamKey(labels)
+	s.mu.Lock()
+	stream, ok := s.streams[key]
+	if !ok {
+		stream = &lokiStream{Stream: labels}
+		s.streams[key] = stream
+	}
+	stream.Values = append(stream.Values, [2]string{strconv.FormatInt(entry.Time.UnixNano(), 10), line})
+	s.size++
+	full := s.size >= lokiBatchSize
+	s.mu.Unlock()
+
+	if full {
+		s.flush()
+	}
+	return nil
+}
+
+func (s *lokiSink) flush() {
+	s.mu.Lock()
+	if s.size == 0 {
+		s.mu.Unlock()
+		return
+	}
+	streams := make([]*lokiStream, 0, len(s.streams))
+	for _, stream := range s.streams {
+		streams = append(streams, stream)
+	}
+	s.streams = map[string]*lokiStream{}
+	s.size = 0
+	s.mu.Unlock()
+
+	if err := s.push(streams); err != nil {
+		// the sink must not log to the logger it is a hook of
+		fmt.Fprintf(os.Stderr, "unable to push the log to Loki: %v\n", err)
+	}
+}
+
+func (s *lokiSink) push(streams []*lokiStream) error {
+	body, err := json.Marshal(map[string]interface{}{"streams": streams})
+	if err != nil {
+		return err
+	}
+	resp, err := s.client.Post(s.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s responded with %s", s.url, resp.Status)
+	}
+	return nil
+}
+
+func (s *lokiSink) Close() error {
+	close(s.done)
+	s.wg.Wait()
+	s.flush()
+	return nil
+}
+
+func lokiStreamKey(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, k := range keys {
+		fmt.Fprintf(&b, "%s=%q,", k, labels[k])
+	}
+	return b.String()
+}
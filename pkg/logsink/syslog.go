@@ -0,0 +1,63 @@
+//go:build !(windows || plan9)
+
+package logsink
+
+import (
+	"fmt"
+	"log/syslog"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// syslogSink sends the entries to syslog with the priority matching their level
+type syslogSink struct {
+	writer    *syslog.Writer
+	formatter logrus.Formatter
+}
+
+func newSyslogSink(address string) (Sink, error) {
+	network := ""
+	if address != "" {
+		var ok bool
+		if network, address, ok = strings.Cut(address, "://"); !ok {
+			return nil, fmt.Errorf("invalid syslog address '%s', expected <network>://<address>", address)
+		}
+	}
+	writer, err := syslog.Dial(network, address, syslog.LOG_INFO|syslog.LOG_USER, "act")
+	if err != nil {
+		return nil, err
+	}
+	return &syslogSink{
+		writer:    writer,
+		formatter: &logrus.TextFormatter{DisableColors: true, DisableTimestamp: true},
+	}, nil
+}
+
+func (s *syslogSink) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (s *syslogSink) Fire(entry *logrus.Entry) error {
+	b, err := s.formatter.Format(entry)
+	if err != nil {
+		return err
+	}
+	line := string(b)
+	switch entry.Level {
+	case logrus.PanicLevel, logrus.FatalLevel:
+		return s.writer.Crit(line)
+	case logrus.ErrorLevel:
+		return s.writer.Err(line)
+	case logrus.WarnLevel:
+		return s.writer.Warning(line)
+	case logrus.InfoLevel:
+		return s.writer.Info(line)
+	default:
+		return s.writer.Debug(line)
+	}
+}
+
+func (s *syslogSink) Close() error {
+	return s.writer.Close()
+}
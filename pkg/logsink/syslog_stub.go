@@ -0,0 +1,11 @@
+//go:build windows || plan9
+
+package logsink
+
+import (
+	"errors"
+)
+
+func newSyslogSink(address string) (Sink, error) {
+	return nil, errors.New("syslog is not supported on this platform")
+}
@@ -0,0 +1,29 @@
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadActionNode20(t *testing.T) {
+	action, err := ReadAction(strings.NewReader(`
+runs:
+  using: node20
+  pre: setup.js
+  main: index.js
+  post: cleanup.js
+  post-if: success()
+`))
+	require.NoError(t, err)
+	assert.Equal(t, ActionRunsUsing(ActionRunsUsingNode20), action.Runs.Using)
+	assert.True(t, action.Runs.Using.IsNode())
+	assert.Equal(t, "always()", action.Runs.PreIf)
+	assert.Equal(t, "success()", action.Runs.PostIf)
+
+	_, err = ReadAction(strings.NewReader("runs:\n  using: node8\n  main: index.js\n"))
+	assert.Error(t, err)
+	assert.False(t, ActionRunsUsing(ActionRunsUsingComposite).IsNode())
+}
@@ -0,0 +1,138 @@
+package model
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CompatFeature is a construct of the workflow syntax which act does not support (yet)
+type CompatFeature struct {
+	ID          string
+	Paths       []string       // paths of the construct in the workflow, * matches any key or index, e.g. jobs.*.environment
+	Value       *regexp.Regexp // the value (or an item of a list) has to match, any value matches if nil
+	Description string
+	Issue       string // issue tracking the support in act, if there is one
+	Link        string // documentation of the construct
+}
+
+// CompatMatrix lists the constructs of the workflow syntax which act does not support, act compat reports their use
+var CompatMatrix = []*CompatFeature{
+	{
+		ID:          "runner-os",
+		Paths:       []string{"jobs.*.runs-on"},
+		Value:       regexp.MustCompile(`(?i)^(windows|macos)-`),
+		Description: "Windows and macOS runners have no container image, they run with -P <platform>=-self-hosted on a host of the platform or best-effort in a Linux image (pwsh as the default shell of Windows jobs, runner.os stays Linux)",
+		Issue:       "nektos/act#97",
+		Link:        "https://docs.github.com/en/actions/using-github-hosted-runners/about-github-hosted-runners",
+	},
+	{
+		ID:          "concurrency",
+		Paths:       []string{"concurrency", "jobs.*.concurrency.cancel-in-progress"},
+		Description: "cancel-in-progress only cancels the runs of --watch and of the invocations of act sharing a --concurrency-dir, the jobs of a run queue without cancelling each other",
+		Link:        "https://docs.github.com/en/actions/using-jobs/using-concurrency",
+	},
+	{
+		ID:          "environment",
+		Paths:       []string{"jobs.*.environment"},
+		Description: "deployment environments are ignored, their protection rules, secrets and variables are not applied",
+		Link:        "https://docs.github.com/en/actions/deployment/targeting-different-environments/using-environments-for-deployment",
+	},
+	{
+		ID:          "oidc",
+		Paths:       []string{"permissions.id-token", "jobs.*.permissions.id-token"},
+		Value:       regexp.MustCompile(`^write$`),
+		Description: "OpenID Connect tokens cannot be requested, ACTIONS_ID_TOKEN_REQUEST_URL is not set",
+		Link:        "https://docs.github.com/en/actions/deployment/security-hardening-your-deployments/about-security-hardening-with-openid-connect",
+	},
+	{
+		ID:          "snapshot",
+		Paths:       []string{"jobs.*.snapshot"},
+		Description: "custom images are not created from the runner of the job",
+		Link:        "https://docs.github.com/en/actions/using-github-hosted-runners/using-larger-runners/using-custom-images",
+	},
+}
+
+// CompatFinding is the use of an unsupported construct in a workflow
+type CompatFinding struct {
+	Feature *CompatFeature
+	Path    string // path of the construct in the workflow, e.g. jobs.deploy.environment
+	Line    int
+}
+
+func (f *CompatFinding) String() string {
+	ref := f.Feature.Link
+	if f.Feature.Issue != "" {
+		ref = f.Feature.Issue
+	}
+	return fmt.Sprintf("%d: %s: %s (%s)", f.Line, f.Path, f.Feature.Description, ref)
+}
+
+// CheckCompat reports the constructs of the CompatMatrix used by the workflow, in the order of the matrix
+func CheckCompat(in io.Reader) ([]*CompatFinding, error) {
+	var doc yaml.Node
+	if err := yaml.NewDecoder(in).Decode(&doc); err != nil && err != io.EOF {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+
+	findings := make([]*CompatFinding, 0)
+	for _, feature := range CompatMatrix {
+		for _, path := range feature.Paths {
+			findings = append(findings, feature.find(doc.Content[0], strings.Split(path, "."), nil, 0)...)
+		}
+	}
+	return findings, nil
+}
+
+// find matches the remaining segments of a path against the node, prefix is the path of the node and line the line
+// of its key
+func (cf *CompatFeature) find(node *yaml.Node, segments []string, prefix []string, line int) []*CompatFinding {
+	if len(segments) == 0 {
+		if !cf.matches(node) {
+			return nil
+		}
+		return []*CompatFinding{{Feature: cf, Path: strings.Join(prefix, "."), Line: line}}
+	}
+
+	findings := make([]*CompatFinding, 0)
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			if segments[0] == "*" || segments[0] == key {
+				findings = append(findings, cf.find(node.Content[i+1], segments[1:], append(prefix[:len(prefix):len(prefix)], key), node.Content[i].Line)...)
+			}
+		}
+	case yaml.SequenceNode:
+		if segments[0] == "*" {
+			for i, item := range node.Content {
+				findings = append(findings, cf.find(item, segments[1:], append(prefix[:len(prefix):len(prefix)], strconv.Itoa(i)), item.Line)...)
+			}
+		}
+	}
+	return findings
+}
+
+func (cf *CompatFeature) matches(node *yaml.Node) bool {
+	if cf.Value == nil {
+		return true
+	}
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return cf.Value.MatchString(node.Value)
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			if item.Kind == yaml.ScalarNode && cf.Value.MatchString(item.Value) {
+				return true
+			}
+		}
+	}
+	return false
+}
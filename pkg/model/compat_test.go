@@ -0,0 +1,64 @@
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckCompat(t *testing.T) {
+	yaml := `
+name: deploy
+on: push
+concurrency: deploy
+permissions:
+  id-token: write
+jobs:
+  test:
+    runs-on: [self-hosted, windows-latest]
+    services:
+      db:
+        image: postgres
+    steps:
+      - run: echo
+  deploy:
+    runs-on: ubuntu-latest
+    environment: prod
+    permissions:
+      id-token: read
+    steps:
+      - run: echo
+`
+	findings, err := CheckCompat(strings.NewReader(yaml))
+	assert.NoError(t, err)
+
+	actual := make([]string, 0, len(findings))
+	for _, f := range findings {
+		actual = append(actual, f.Feature.ID+" "+f.String())
+	}
+	assert.Equal(t, []string{
+		"runner-os 9: jobs.test.runs-on: Windows and macOS runners have no container image, they run with -P <platform>=-self-hosted on a host of the platform or best-effort in a Linux image (pwsh as the default shell of Windows jobs, runner.os stays Linux) (nektos/act#97)",
+		"concurrency 4: concurrency: cancel-in-progress only cancels the runs of --watch and of the invocations of act sharing a --concurrency-dir, the jobs of a run queue without cancelling each other (https://docs.github.com/en/actions/using-jobs/using-concurrency)",
+		"environment 17: jobs.deploy.environment: deployment environments are ignored, their protection rules, secrets and variables are not applied (https://docs.github.com/en/actions/deployment/targeting-different-environments/using-environments-for-deployment)",
+		"oidc 6: permissions.id-token: OpenID Connect tokens cannot be requested, ACTIONS_ID_TOKEN_REQUEST_URL is not set (https://docs.github.com/en/actions/deployment/security-hardening-your-deployments/about-security-hardening-with-openid-connect)",
+	}, actual)
+}
+
+func TestCheckCompatSupported(t *testing.T) {
+	yaml := `
+on: push
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v3
+`
+	findings, err := CheckCompat(strings.NewReader(yaml))
+	assert.NoError(t, err)
+	assert.Empty(t, findings)
+
+	findings, err = CheckCompat(strings.NewReader(""))
+	assert.NoError(t, err)
+	assert.Empty(t, findings)
+}
@@ -0,0 +1,220 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nektos/act/pkg/common/git"
+)
+
+// GeneratedEvents are the events whose payloads GenerateEvent builds from the state of the git repository
+var GeneratedEvents = []string{"push", "pull_request", "release", "issue_comment"}
+
+// EventSource is the state of the git repository the payload of an event is generated from
+type EventSource struct {
+	Repository    string            // owner/name, the repository fields are left out when empty
+	ServerURL     string            // the URL of the GitHub instance, like https://github.com
+	DefaultBranch string            // the default branch of the repository, and the base of the pull requests
+	Ref           string            // the checked-out ref, like refs/heads/main or refs/tags/v1.0.0
+	SHA           string            // the checked-out revision
+	BaseRef       string            // the base branch of the pull request, the default branch if empty
+	Tag           string            // the tag of the release, the checked-out tag if empty
+	Body          string            // the body of the pull request, the release or the comment
+	Actor         string            // the login of the sender
+	Commits       []*git.CommitInfo // the pushed commits, the most recent first
+}
+
+var generatedEvents = map[string]func(source *EventSource) (map[string]interface{}, error){
+	"push": func(source *EventSource) (map[string]interface{}, error) {
+		commits := make([]interface{}, 0, len(source.Commits))
+		// the commits of a push are listed from the oldest to the most recent
+		for i := len(source.Commits) - 1; i >= 0; i-- {
+			commits = append(commits, source.commit(source.Commits[i]))
+		}
+		before := strings.Repeat("0", 40)
+		var headCommit interface{}
+		if len(source.Commits) > 0 {
+			headCommit = source.commit(source.Commits[0])
+			if parent := source.Commits[len(source.Commits)-1].Parent; parent != "" {
+				before = parent
+			}
+		}
+		return map[string]interface{}{
+			"ref":         source.Ref,
+			"before":      before,
+			"after":       source.SHA,
+			"created":     false,
+			"deleted":     false,
+			"forced":      false,
+			"base_ref":    nil,
+			"compare":     fmt.Sprintf("%s/compare/%s...%s", source.htmlURL(), before[:12], source.SHA[:12]),
+			"commits":     commits,
+			"head_commit": headCommit,
+			"pusher":      source.pusher(),
+		}, nil
+	},
+	"pull_request": func(source *EventSource) (map[string]interface{}, error) {
+		base := source.BaseRef
+		if base == "" {
+			base = source.DefaultBranch
+		}
+		head := shortRef(source.Ref)
+		title := head
+		if len(source.Commits) > 0 {
+			title = strings.SplitN(source.Commits[0].Message, "\n", 2)[0]
+		}
+		owner := source.owner()
+		return map[string]interface{}{
+			"action": "opened",
+			"number": 1,
+			"pull_request": map[string]interface{}{
+				"number":   1,
+				"state":    "open",
+				"title":    title,
+				"body":     source.Body,
+				"draft":    false,
+				"merged":   false,
+				"html_url": source.htmlURL() + "/pull/1",
+				"user":     source.sender(),
+				"head": map[string]interface{}{
+					"ref":   head,
+					"sha":   source.SHA,
+					"label": fmt.Sprintf("%s:%s", owner, head),
+					"repo":  source.repository(),
+				},
+				"base": map[string]interface{}{
+					"ref":   base,
+					"label": fmt.Sprintf("%s:%s", owner, base),
+					"repo":  source.repository(),
+				},
+			},
+		}, nil
+	},
+	"release": func(source *EventSource) (map[string]interface{}, error) {
+		tag := source.Tag
+		if tag == "" && strings.HasPrefix(source.Ref, "refs/tags/") {
+			tag = shortRef(source.Ref)
+		}
+		if tag == "" {
+			return nil, fmt.Errorf("a release needs a tag, check out a tag or pass one with --tag")
+		}
+		target := shortRef(source.Ref)
+		if strings.HasPrefix(source.Ref, "refs/tags/") {
+			target = source.SHA
+		}
+		return map[string]interface{}{
+			"action": "published",
+			"release": map[string]interface{}{
+				"id":               1,
+				"tag_name":         tag,
+				"target_commitish": target,
+				"name":             tag,
+				"body":             source.Body,
+				"draft":            false,
+				"prerelease":       false,
+				"html_url":         fmt.Sprintf("%s/releases/tag/%s", source.htmlURL(), tag),
+				"author":           source.sender(),
+			},
+		}, nil
+	},
+	"issue_comment": func(source *EventSource) (map[string]interface{}, error) {
+		return map[string]interface{}{
+			"action": "created",
+			"issue": map[string]interface{}{
+				"number":   1,
+				"title":    "",
+				"body":     "",
+				"state":    "open",
+				"html_url": source.htmlURL() + "/issues/1",
+				"user":     source.sender(),
+			},
+			"comment": map[string]interface{}{
+				"id":       1,
+				"body":     source.Body,
+				"html_url": source.htmlURL() + "/issues/1#issuecomment-1",
+				"user":     source.sender(),
+			},
+		}, nil
+	},
+}
+
+// GenerateEvent returns a payload of the event built from the state of the git repository, like the payloads of
+// GitHub, instead of an event file written by hand
+func GenerateEvent(eventName string, source *EventSource) (map[string]interface{}, error) {
+	generate, ok := generatedEvents[eventName]
+	if !ok {
+		return nil, fmt.Errorf("unable to generate the payload of the event '%s', expected one of %s", eventName, strings.Join(GeneratedEvents, ", "))
+	}
+	if source.DefaultBranch == "" {
+		source.DefaultBranch = "master"
+	}
+	if source.ServerURL == "" {
+		source.ServerURL = "https://github.com"
+	}
+	if len(source.SHA) < 40 {
+		return nil, fmt.Errorf("the revision '%s' isn't a full SHA", source.SHA)
+	}
+	event, err := generate(source)
+	if err != nil {
+		return nil, err
+	}
+	event["repository"] = source.repository()
+	event["sender"] = source.sender()
+	return event, nil
+}
+
+func shortRef(ref string) string {
+	return strings.TrimPrefix(strings.TrimPrefix(ref, "refs/heads/"), "refs/tags/")
+}
+
+func (source *EventSource) owner() string {
+	if source.Repository != "" {
+		return strings.Split(source.Repository, "/")[0]
+	}
+	return source.Actor
+}
+
+func (source *EventSource) htmlURL() string {
+	return strings.TrimSuffix(source.ServerURL, "/") + "/" + source.Repository
+}
+
+func (source *EventSource) repository() map[string]interface{} {
+	repository := map[string]interface{}{"default_branch": source.DefaultBranch}
+	if source.Repository == "" {
+		return repository
+	}
+	repository["name"] = strings.TrimPrefix(source.Repository, source.owner()+"/")
+	repository["full_name"] = source.Repository
+	repository["owner"] = map[string]interface{}{"login": source.owner()}
+	repository["private"] = false
+	repository["html_url"] = source.htmlURL()
+	repository["clone_url"] = source.htmlURL() + ".git"
+	return repository
+}
+
+func (source *EventSource) sender() map[string]interface{} {
+	return map[string]interface{}{"login": source.Actor, "type": "User"}
+}
+
+func (source *EventSource) pusher() map[string]interface{} {
+	pusher := map[string]interface{}{"name": source.Actor}
+	if len(source.Commits) > 0 {
+		pusher["email"] = source.Commits[0].Email
+	}
+	return pusher
+}
+
+func (source *EventSource) commit(commit *git.CommitInfo) map[string]interface{} {
+	author := map[string]interface{}{"name": commit.Author, "email": commit.Email}
+	return map[string]interface{}{
+		"id":        commit.SHA,
+		"tree_id":   commit.Tree,
+		"distinct":  true,
+		"message":   commit.Message,
+		"timestamp": commit.Timestamp.Format(time.RFC3339),
+		"url":       fmt.Sprintf("%s/commit/%s", source.htmlURL(), commit.SHA),
+		"author":    author,
+		"committer": author,
+	}
+}
@@ -0,0 +1,107 @@
+package model
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nektos/act/pkg/common/git"
+)
+
+func TestGenerateEvent(t *testing.T) {
+	sha := strings.Repeat("c", 40)
+	parent := strings.Repeat("b", 40)
+	root := strings.Repeat("a", 40)
+	newSource := func() *EventSource {
+		return &EventSource{
+			Repository: "nektos/act",
+			Ref:        "refs/heads/feature",
+			SHA:        sha,
+			Actor:      "octocat",
+			Body:       "LGTM",
+			Commits: []*git.CommitInfo{
+				{SHA: sha, Parent: parent, Message: "Fix the tests\n\nmore", Author: "Mona", Email: "mona@example.com", Timestamp: time.Unix(0, 0).UTC()},
+				{SHA: parent, Parent: root, Message: "Add the feature", Author: "Mona", Email: "mona@example.com", Timestamp: time.Unix(0, 0).UTC()},
+			},
+		}
+	}
+
+	t.Run("push", func(t *testing.T) {
+		event, err := GenerateEvent("push", newSource())
+		assert.NoError(t, err)
+		assert.Equal(t, "refs/heads/feature", event["ref"])
+		assert.Equal(t, root, event["before"])
+		assert.Equal(t, sha, event["after"])
+		commits := event["commits"].([]interface{})
+		assert.Len(t, commits, 2)
+		assert.Equal(t, parent, commits[0].(map[string]interface{})["id"])
+		assert.Equal(t, sha, nestedMapLookup(event, "head_commit", "id"))
+		assert.Equal(t, "https://github.com/nektos/act/commit/"+sha, nestedMapLookup(event, "head_commit", "url"))
+		assert.Equal(t, "mona@example.com", nestedMapLookup(event, "pusher", "email"))
+		assert.Equal(t, "nektos/act", nestedMapLookup(event, "repository", "full_name"))
+		assert.Equal(t, "nektos", nestedMapLookup(event, "repository", "owner", "login"))
+		assert.Equal(t, "master", nestedMapLookup(event, "repository", "default_branch"))
+		assert.Equal(t, "octocat", nestedMapLookup(event, "sender", "login"))
+	})
+
+	t.Run("pull_request", func(t *testing.T) {
+		source := newSource()
+		source.DefaultBranch = "main"
+		event, err := GenerateEvent("pull_request", source)
+		assert.NoError(t, err)
+		assert.Equal(t, "opened", event["action"])
+		assert.Equal(t, "Fix the tests", nestedMapLookup(event, "pull_request", "title"))
+		assert.Equal(t, "feature", nestedMapLookup(event, "pull_request", "head", "ref"))
+		assert.Equal(t, sha, nestedMapLookup(event, "pull_request", "head", "sha"))
+		assert.Equal(t, "main", nestedMapLookup(event, "pull_request", "base", "ref"))
+
+		source = newSource()
+		source.BaseRef = "develop"
+		event, err = GenerateEvent("pull_request", source)
+		assert.NoError(t, err)
+		assert.Equal(t, "develop", nestedMapLookup(event, "pull_request", "base", "ref"))
+	})
+
+	t.Run("release", func(t *testing.T) {
+		_, err := GenerateEvent("release", newSource())
+		assert.Error(t, err)
+
+		source := newSource()
+		source.Ref = "refs/tags/v1.0.0"
+		event, err := GenerateEvent("release", source)
+		assert.NoError(t, err)
+		assert.Equal(t, "published", event["action"])
+		assert.Equal(t, "v1.0.0", nestedMapLookup(event, "release", "tag_name"))
+		assert.Equal(t, sha, nestedMapLookup(event, "release", "target_commitish"))
+
+		source = newSource()
+		source.Tag = "v2.0.0"
+		event, err = GenerateEvent("release", source)
+		assert.NoError(t, err)
+		assert.Equal(t, "v2.0.0", nestedMapLookup(event, "release", "tag_name"))
+		assert.Equal(t, "feature", nestedMapLookup(event, "release", "target_commitish"))
+	})
+
+	t.Run("issue_comment", func(t *testing.T) {
+		event, err := GenerateEvent("issue_comment", newSource())
+		assert.NoError(t, err)
+		assert.Equal(t, "created", event["action"])
+		assert.Equal(t, "LGTM", nestedMapLookup(event, "comment", "body"))
+		assert.EqualValues(t, 1, nestedMapLookup(event, "issue", "number"))
+	})
+
+	t.Run("without remote", func(t *testing.T) {
+		source := newSource()
+		source.Repository = ""
+		event, err := GenerateEvent("push", source)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"default_branch": "master"}, event["repository"])
+	})
+
+	t.Run("unknown event", func(t *testing.T) {
+		_, err := GenerateEvent("schedule", newSource())
+		assert.EqualError(t, err, "unable to generate the payload of the event 'schedule', expected one of push, pull_request, release, issue_comment")
+	})
+}
@@ -0,0 +1,139 @@
+package model
+
+import (
+	"fmt"
+)
+
+// synthesizedEvents are the more recent events whose payloads act synthesizes when no event file is passed
+var synthesizedEvents = map[string]func(defaultBranch string, sha string) map[string]interface{}{
+	"merge_group": func(defaultBranch string, sha string) map[string]interface{} {
+		return map[string]interface{}{
+			"action": "checks_requested",
+			"merge_group": map[string]interface{}{
+				"head_sha":    sha,
+				"head_ref":    fmt.Sprintf("refs/heads/gh-readonly-queue/%s/pr-1-%s", defaultBranch, sha),
+				"base_sha":    sha,
+				"base_ref":    "refs/heads/" + defaultBranch,
+				"head_commit": map[string]interface{}{"id": sha, "tree_id": sha, "message": "Merge pull request #1"},
+			},
+		}
+	},
+	"workflow_job": func(defaultBranch string, sha string) map[string]interface{} {
+		return map[string]interface{}{
+			"action": "queued",
+			"workflow_job": map[string]interface{}{
+				"id":          1,
+				"run_id":      1,
+				"run_attempt": 1,
+				"head_sha":    sha,
+				"head_branch": defaultBranch,
+				"status":      "queued",
+				"conclusion":  nil,
+				"labels":      []interface{}{"ubuntu-latest"},
+				"steps":       []interface{}{},
+			},
+		}
+	},
+	"workflow_run": func(defaultBranch string, sha string) map[string]interface{} {
+		return map[string]interface{}{
+			"action": "completed",
+			"workflow_run": map[string]interface{}{
+				"id":          1,
+				"run_number":  1,
+				"run_attempt": 1,
+				"event":       "push",
+				"head_sha":    sha,
+				"head_branch": defaultBranch,
+				"status":      "completed",
+				"conclusion":  "success",
+			},
+		}
+	},
+	"discussion": func(defaultBranch string, sha string) map[string]interface{} {
+		return map[string]interface{}{
+			"action":     "created",
+			"discussion": synthesizedDiscussion(),
+		}
+	},
+	"discussion_comment": func(defaultBranch string, sha string) map[string]interface{} {
+		return map[string]interface{}{
+			"action":     "created",
+			"discussion": synthesizedDiscussion(),
+			"comment": map[string]interface{}{
+				"id":   1,
+				"body": "",
+			},
+		}
+	},
+}
+
+func synthesizedDiscussion() map[string]interface{} {
+	return map[string]interface{}{
+		"number":   1,
+		"title":    "",
+		"body":     "",
+		"state":    "open",
+		"category": map[string]interface{}{"name": "General", "slug": "general", "is_answerable": false},
+	}
+}
+
+// defaultEventTypes are the activity types triggering a workflow whose on.<event> has no types, all types if missing
+var defaultEventTypes = map[string][]string{
+	"merge_group": {"checks_requested"},
+}
+
+// SynthesizeEvent returns a payload of the event for the default branch at sha, nil if act doesn't synthesize the
+// payloads of the event
+func SynthesizeEvent(eventName string, defaultBranch string, sha string) map[string]interface{} {
+	synthesize, ok := synthesizedEvents[eventName]
+	if !ok {
+		return nil
+	}
+	if defaultBranch == "" {
+		defaultBranch = "master"
+	}
+	event := synthesize(defaultBranch, sha)
+	event["repository"] = map[string]interface{}{"default_branch": defaultBranch}
+	return event
+}
+
+// EventTypes returns the activity types of the event the workflow runs for (on.<event>.types), nil if it has none
+func (w *Workflow) EventTypes(eventName string) []string {
+	on, ok := w.OnEvent(eventName).(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	switch types := on["types"].(type) {
+	case string:
+		return []string{types}
+	case []interface{}:
+		result := make([]string, 0, len(types))
+		for _, t := range types {
+			result = append(result, fmt.Sprint(t))
+		}
+		return result
+	}
+	return nil
+}
+
+// RunsForActivityType reports whether the workflow runs for the activity type (the action) of the payload of the event,
+// the types of every event declaring them (on.<event>.types) are checked
+func (w *Workflow) RunsForActivityType(eventName string, event map[string]interface{}) bool {
+	action, ok := event["action"].(string)
+	if !ok {
+		return true
+	}
+	types := w.EventTypes(eventName)
+	if types == nil {
+		types = defaultEventTypes[eventName]
+	}
+	if types == nil {
+		return true
+	}
+	for _, t := range types {
+		if t == action {
+			return true
+		}
+	}
+	return false
+}
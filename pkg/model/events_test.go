@@ -0,0 +1,71 @@
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSynthesizeEvent(t *testing.T) {
+	event := SynthesizeEvent("merge_group", "main", "abc")
+	assert.Equal(t, "checks_requested", event["action"])
+	assert.Equal(t, "refs/heads/main", nestedMapLookup(event, "merge_group", "base_ref"))
+	assert.Equal(t, "refs/heads/gh-readonly-queue/main/pr-1-abc", nestedMapLookup(event, "merge_group", "head_ref"))
+	assert.Equal(t, "abc", nestedMapLookup(event, "merge_group", "head_sha"))
+	assert.Equal(t, "main", nestedMapLookup(event, "repository", "default_branch"))
+
+	for _, eventName := range []string{"workflow_job", "workflow_run", "discussion", "discussion_comment"} {
+		event := SynthesizeEvent(eventName, "", "abc")
+		assert.NotEmpty(t, event["action"], eventName)
+		assert.Equal(t, "master", nestedMapLookup(event, "repository", "default_branch"), eventName)
+	}
+	assert.Equal(t, "abc", nestedMapLookup(SynthesizeEvent("workflow_run", "main", "abc"), "workflow_run", "head_sha"))
+
+	assert.Nil(t, SynthesizeEvent("push", "main", "abc"))
+}
+
+func TestWorkflowRunsForActivityType(t *testing.T) {
+	workflow, err := ReadWorkflow(strings.NewReader(`
+on:
+  discussion:
+    types: [created, answered]
+  workflow_run:
+    workflows: [CI]
+  merge_group:
+  pull_request:
+    types: [opened]
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"created", "answered"}, workflow.EventTypes("discussion"))
+	assert.Nil(t, workflow.EventTypes("workflow_run"))
+
+	tables := []struct {
+		eventName string
+		action    interface{}
+		expected  bool
+	}{
+		{"discussion", "created", true},
+		{"discussion", "deleted", false},
+		{"workflow_run", "requested", true},
+		{"merge_group", "checks_requested", true},
+		{"merge_group", "destroyed", false},
+		{"discussion", nil, true},
+		{"pull_request", "opened", true},
+		{"pull_request", "closed", false},
+		// an event without types runs for every activity type
+		{"issues", "closed", true},
+	}
+	for _, table := range tables {
+		event := map[string]interface{}{}
+		if table.action != nil {
+			event["action"] = table.action
+		}
+		assert.Equal(t, table.expected, workflow.RunsForActivityType(table.eventName, event), "%s %v", table.eventName, table.action)
+	}
+}
@@ -14,6 +14,7 @@ type GithubContext struct {
 	Workflow         string                 `json:"workflow"`
 	RunID            string                 `json:"run_id"`
 	RunNumber        string                 `json:"run_number"`
+	RunAttempt       string                 `json:"run_attempt"`
 	Actor            string                 `json:"actor"`
 	Repository       string                 `json:"repository"`
 	EventName        string                 `json:"event_name"`
@@ -35,6 +36,9 @@ type GithubContext struct {
 	RetentionDays    string                 `json:"retention_days"`
 	RunnerPerflog    string                 `json:"runner_perflog"`
 	RunnerTrackingID string                 `json:"runner_tracking_id"`
+	ServerURL        string                 `json:"server_url"`
+	APIURL           string                 `json:"api_url"`
+	GraphQLURL       string                 `json:"graphql_url"`
 }
 
 func asString(v interface{}) string {
@@ -99,6 +103,9 @@ func (ghc *GithubContext) SetRefAndSha(ctx context.Context, defaultBranch string
 		ghc.Sha = asString(nestedMapLookup(ghc.Event, "pull_request", "base", "sha"))
 	case "pull_request", "pull_request_review", "pull_request_review_comment":
 		ghc.Ref = fmt.Sprintf("refs/pull/%.0f/merge", ghc.Event["number"])
+	case "merge_group":
+		ghc.Ref = asString(nestedMapLookup(ghc.Event, "merge_group", "head_ref"))
+		ghc.Sha = asString(nestedMapLookup(ghc.Event, "merge_group", "head_sha"))
 	case "deployment", "deployment_status":
 		ghc.Ref = asString(nestedMapLookup(ghc.Event, "deployment", "ref"))
 		ghc.Sha = asString(nestedMapLookup(ghc.Event, "deployment", "sha"))
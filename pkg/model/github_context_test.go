@@ -62,6 +62,17 @@ func TestSetRefAndSha(t *testing.T) {
 			ref: "refs/heads/somebranch",
 			sha: "deployment-sha",
 		},
+		{
+			eventName: "merge_group",
+			event: map[string]interface{}{
+				"merge_group": map[string]interface{}{
+					"head_ref": "refs/heads/gh-readonly-queue/main/pr-1-abc",
+					"head_sha": "merge-group-sha",
+				},
+			},
+			ref: "refs/heads/gh-readonly-queue/main/pr-1-abc",
+			sha: "merge-group-sha",
+		},
 		{
 			eventName: "release",
 			event: map[string]interface{}{
@@ -6,7 +6,12 @@ type JobContext struct {
 		ID      string `json:"id"`
 		Network string `json:"network"`
 	} `json:"container"`
-	Services map[string]struct {
-		ID string `json:"id"`
-	} `json:"services"`
+	Services map[string]*JobServiceContext `json:"services"`
+}
+
+// JobServiceContext is a service container of the job
+type JobServiceContext struct {
+	ID      string            `json:"id"`
+	Network string            `json:"network"`
+	Ports   map[string]string `json:"ports"`
 }
@@ -0,0 +1,99 @@
+package model
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Access levels of a GITHUB_TOKEN permission scope
+const (
+	PermissionNone  = "none"
+	PermissionRead  = "read"
+	PermissionWrite = "write"
+)
+
+// PermissionScopes are the scopes which can be granted to the GITHUB_TOKEN
+var PermissionScopes = []string{
+	"actions",
+	"checks",
+	"contents",
+	"deployments",
+	"discussions",
+	"id-token",
+	"issues",
+	"metadata",
+	"packages",
+	"pages",
+	"pull-requests",
+	"repository-projects",
+	"security-events",
+	"statuses",
+}
+
+// Permissions maps every permission scope of the GITHUB_TOKEN to its access level
+type Permissions map[string]string
+
+// NewPermissions creates permissions granting the same access to every scope
+func NewPermissions(access string) Permissions {
+	permissions := make(Permissions, len(PermissionScopes))
+	for _, scope := range PermissionScopes {
+		permissions[scope] = access
+	}
+	// the metadata scope is always readable
+	if access == PermissionNone {
+		permissions["metadata"] = PermissionRead
+	}
+	return permissions
+}
+
+// ParsePermissions decodes a `permissions:` block, it returns nil if the block is not set
+func ParsePermissions(node yaml.Node) (Permissions, error) {
+	switch node.Kind {
+	case 0:
+		return nil, nil
+	case yaml.ScalarNode:
+		switch node.Value {
+		case "read-all":
+			return NewPermissions(PermissionRead), nil
+		case "write-all":
+			return NewPermissions(PermissionWrite), nil
+		}
+		return nil, fmt.Errorf("invalid permissions '%s', expected read-all, write-all or a map of scopes", node.Value)
+	case yaml.MappingNode:
+		var val map[string]string
+		if err := node.Decode(&val); err != nil {
+			return nil, err
+		}
+		// scopes which are not listed have no access
+		permissions := NewPermissions(PermissionNone)
+		for scope, access := range val {
+			if _, ok := permissions[scope]; !ok {
+				return nil, fmt.Errorf("unknown permission scope '%s'", scope)
+			}
+			if access != PermissionNone && access != PermissionRead && access != PermissionWrite {
+				return nil, fmt.Errorf("invalid access '%s' for permission scope '%s'", access, scope)
+			}
+			permissions[scope] = access
+		}
+		permissions["metadata"] = maxAccess(permissions["metadata"], PermissionRead)
+		return permissions, nil
+	}
+	return nil, fmt.Errorf("invalid permissions at line %d", node.Line)
+}
+
+// Allows returns true if the permissions grant the access to the scope, write access includes read access
+func (p Permissions) Allows(scope string, access string) bool {
+	if p == nil {
+		return true
+	}
+	return maxAccess(p[scope], access) == p[scope]
+}
+
+func maxAccess(a string, b string) string {
+	levels := map[string]int{PermissionNone: 0, PermissionRead: 1, PermissionWrite: 2}
+	if levels[a] >= levels[b] {
+		return a
+	}
+	return b
+}
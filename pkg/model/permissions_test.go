@@ -0,0 +1,68 @@
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobPermissions(t *testing.T) {
+	yaml := `
+name: permissions
+on: push
+permissions: read-all
+jobs:
+  inherit:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo
+  scoped:
+    runs-on: ubuntu-latest
+    permissions:
+      issues: write
+    steps:
+      - run: echo
+  empty:
+    runs-on: ubuntu-latest
+    permissions: {}
+    steps:
+      - run: echo
+`
+	workflow, err := ReadWorkflow(strings.NewReader(yaml))
+	assert.NoError(t, err, "read workflow should succeed")
+
+	permissions, err := workflow.Jobs["inherit"].Permissions(workflow)
+	assert.NoError(t, err)
+	assert.True(t, permissions.Allows("contents", PermissionRead))
+	assert.False(t, permissions.Allows("contents", PermissionWrite))
+
+	permissions, err = workflow.Jobs["scoped"].Permissions(workflow)
+	assert.NoError(t, err)
+	assert.True(t, permissions.Allows("issues", PermissionWrite))
+	assert.True(t, permissions.Allows("metadata", PermissionRead))
+	assert.False(t, permissions.Allows("contents", PermissionRead))
+
+	permissions, err = workflow.Jobs["empty"].Permissions(workflow)
+	assert.NoError(t, err)
+	assert.True(t, permissions.Allows("metadata", PermissionRead))
+	assert.False(t, permissions.Allows("pull-requests", PermissionRead))
+
+	permissions, err = workflow.Jobs["inherit"].Permissions(&Workflow{})
+	assert.NoError(t, err)
+	assert.Nil(t, permissions)
+	assert.True(t, permissions.Allows("contents", PermissionWrite))
+}
+
+func TestParsePermissionsInvalid(t *testing.T) {
+	for _, yaml := range []string{
+		"permissions: write-some",
+		"permissions:\n  contents: admin",
+		"permissions:\n  code: read",
+	} {
+		workflow, err := ReadWorkflow(strings.NewReader(yaml + "\njobs: {}\n"))
+		assert.NoError(t, err)
+		_, err = ParsePermissions(workflow.RawPermissions)
+		assert.Error(t, err, yaml)
+	}
+}
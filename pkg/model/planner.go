@@ -56,9 +56,18 @@ type WorkflowFiles struct {
 }
 
 // NewWorkflowPlanner will load a specific workflow, all workflows from a directory or all workflows from a directory and its subdirectories
-//
-//nolint:gocyclo
 func NewWorkflowPlanner(path string, noWorkflowRecurse bool) (WorkflowPlanner, error) {
+	return newWorkflowPlanner(path, noWorkflowRecurse, nil, nil)
+}
+
+// NewCachedWorkflowPlanner works like NewWorkflowPlanner, but only parses the workflows which are not in the cache
+// and reuses the cached plans of unchanged workflows. The placeholders of the workflow templates in a workflow-templates directory are replaced by templateValues unless it is nil.
+func NewCachedWorkflowPlanner(path string, noWorkflowRecurse bool, cache *WorkflowCache, templateValues *WorkflowTemplateValues) (WorkflowPlanner, error) {
+	return newWorkflowPlanner(path, noWorkflowRecurse, cache, templateValues)
+}
+
+//nolint:gocyclo
+func newWorkflowPlanner(path string, noWorkflowRecurse bool, cache *WorkflowCache, templateValues *WorkflowTemplateValues) (WorkflowPlanner, error) {
 	path, err := filepath.Abs(path)
 	if err != nil {
 		return nil, err
@@ -119,17 +128,29 @@ func NewWorkflowPlanner(path string, noWorkflowRecurse bool) (WorkflowPlanner, e
 		return nil, err
 	}
 
-	wp := new(workflowPlanner)
+	wp := &workflowPlanner{cache: cache}
 	for _, wf := range workflows {
 		ext := filepath.Ext(wf.workflowDirEntry.Name())
-		if ext == ".yml" || ext == ".yaml" {
+		// a single workflow file is read whatever its extension, e.g. a workflow generated from a template
+		if !fi.IsDir() || ext == ".yml" || ext == ".yaml" {
 			f, err := os.Open(filepath.Join(wf.dirPath, wf.workflowDirEntry.Name()))
 			if err != nil {
 				return nil, err
 			}
 
 			log.Debugf("Reading workflow '%s'", f.Name())
-			workflow, err := ReadWorkflow(f)
+			var r io.Reader = f
+			if templateValues != nil && isWorkflowTemplate(wf.dirPath) {
+				log.Debugf("Replacing the placeholders of workflow template '%s'", f.Name())
+				r, err = templateValues.render(f)
+			}
+			var workflow *Workflow
+			var hash string
+			if err == nil && cache != nil {
+				workflow, hash, err = cache.readWorkflow(r)
+			} else if err == nil {
+				workflow, err = ReadWorkflow(r)
+			}
 			if err != nil {
 				_ = f.Close()
 				if err == io.EOF {
@@ -157,6 +178,7 @@ func NewWorkflowPlanner(path string, noWorkflowRecurse bool) (WorkflowPlanner, e
 			}
 
 			wp.workflows = append(wp.workflows, workflow)
+			wp.hashes = append(wp.hashes, workflow.File+":"+hash)
 			_ = f.Close()
 		}
 	}
@@ -166,50 +188,73 @@ func NewWorkflowPlanner(path string, noWorkflowRecurse bool) (WorkflowPlanner, e
 
 type workflowPlanner struct {
 	workflows []*Workflow
+	hashes    []string // file and content hash of the workflows, to key the cached plans
+	cache     *WorkflowCache
+}
+
+// plan returns the cached plan of the selection if the workflows didn't change, otherwise it creates the plan
+func (wp *workflowPlanner) plan(selection string, create func() *Plan) *Plan {
+	if wp.cache == nil {
+		return create()
+	}
+	key := planKey(wp.hashes, selection)
+	if plan, ok := wp.cache.loadPlan(key, wp.workflows); ok {
+		log.Debugf("Using the cached plan of %s", selection)
+		return plan
+	}
+	plan := create()
+	wp.cache.storePlan(key, plan, wp.workflows)
+	return plan
 }
 
 // PlanEvent builds a new list of runs to execute in parallel for an event name
 func (wp *workflowPlanner) PlanEvent(eventName string) *Plan {
-	plan := new(Plan)
-	if len(wp.workflows) == 0 {
-		log.Debugf("no events found for workflow: %s", eventName)
-	}
+	return wp.plan("event "+eventName, func() *Plan {
+		plan := new(Plan)
+		if len(wp.workflows) == 0 {
+			log.Debugf("no events found for workflow: %s", eventName)
+		}
 
-	for _, w := range wp.workflows {
-		for _, e := range w.On() {
-			if e == eventName {
-				plan.mergeStages(createStages(w, w.GetJobIDs()...))
+		for _, w := range wp.workflows {
+			for _, e := range w.On() {
+				if e == eventName {
+					plan.mergeStages(createStages(w, w.GetJobIDs()...))
+				}
 			}
 		}
-	}
-	return plan
+		return plan
+	})
 }
 
 // PlanJob builds a new run to execute in parallel for a job name
 func (wp *workflowPlanner) PlanJob(jobName string) *Plan {
-	plan := new(Plan)
-	if len(wp.workflows) == 0 {
-		log.Debugf("no jobs found for workflow: %s", jobName)
-	}
+	return wp.plan("job "+jobName, func() *Plan {
+		plan := new(Plan)
+		if len(wp.workflows) == 0 {
+			log.Debugf("no jobs found for workflow: %s", jobName)
+		}
 
-	for _, w := range wp.workflows {
-		plan.mergeStages(createStages(w, jobName))
-	}
-	return plan
+		for _, w := range wp.workflows {
+			plan.mergeStages(createStages(w, jobName))
+		}
+		return plan
+	})
 }
 
 // PlanAll builds a new run to execute in parallel all
 func (wp *workflowPlanner) PlanAll() *Plan {
-	plan := new(Plan)
-	if len(wp.workflows) == 0 {
-		log.Debugf("no jobs found for loaded workflows")
-	}
+	return wp.plan("all", func() *Plan {
+		plan := new(Plan)
+		if len(wp.workflows) == 0 {
+			log.Debugf("no jobs found for loaded workflows")
+		}
 
-	for _, w := range wp.workflows {
-		plan.mergeStages(createStages(w, w.GetJobIDs()...))
-	}
+		for _, w := range wp.workflows {
+			plan.mergeStages(createStages(w, w.GetJobIDs()...))
+		}
 
-	return plan
+		return plan
+	})
 }
 
 // GetEvents gets all the events in the workflows file
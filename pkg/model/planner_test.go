@@ -25,6 +25,7 @@ func TestPlanner(t *testing.T) {
 		{"empty-workflow", "unable to read workflow 'push.yml': file is empty: EOF", false},
 		{"nested", "unable to read workflow 'fail.yml': file is empty: EOF", false},
 		{"nested", "", true},
+		{"single-file/ci.yml.generated", "", false},
 	}
 
 	workdir, err := filepath.Abs("testdata")
@@ -39,3 +40,14 @@ func TestPlanner(t *testing.T) {
 		}
 	}
 }
+
+func TestPlannerSingleFile(t *testing.T) {
+	planner, err := NewWorkflowPlanner(filepath.Join("testdata", "single-file", "ci.yml.generated"), false)
+	assert.NoError(t, err)
+
+	plan := planner.PlanEvent("push")
+	if assert.Len(t, plan.Stages, 1) && assert.Len(t, plan.Stages[0].Runs, 1) {
+		assert.Equal(t, "ci.yml.generated", plan.Stages[0].Runs[0].Workflow.File)
+		assert.Equal(t, "generated", plan.Stages[0].Runs[0].Workflow.Name)
+	}
+}
@@ -0,0 +1,71 @@
+package model
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/rhysd/actionlint"
+)
+
+// Diagnostic is a problem of a workflow reported by ValidateWorkflow
+type Diagnostic struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Rule    string `json:"rule"` // kind of the problem, e.g. syntax-check, expression, job-needs or events, act for the errors of the parser of act
+	Message string `json:"message"`
+}
+
+func (d *Diagnostic) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s [%s]", d.File, d.Line, d.Column, d.Message, d.Rule)
+}
+
+// ValidateWorkflow checks the workflow against the workflow syntax of GitHub with the rules of actionlint: the unknown
+// keys and the values of the wrong type, the syntax and the contexts of the expressions, the needs of the jobs, the
+// events and their filters. The labels of runs-on are checked against the labels of the GitHub-hosted runners and
+// labels, e.g. the platforms of -P. The workflow is also read like act runs it, for the errors act would fail with.
+// The diagnostics are sorted by position.
+func ValidateWorkflow(file string, content []byte, labels []string) []*Diagnostic {
+	diagnostics := make([]*Diagnostic, 0)
+	if _, err := ReadWorkflow(bytes.NewReader(content)); err != nil {
+		diagnostics = append(diagnostics, &Diagnostic{File: file, Rule: "act", Message: fmt.Sprintf("act can't read the workflow: %v", err)})
+	}
+
+	workflow, errs := actionlint.Parse(content)
+	if workflow != nil {
+		rules := []actionlint.Rule{
+			actionlint.NewRuleMatrix(),
+			actionlint.NewRuleShellName(),
+			actionlint.NewRuleRunnerLabel(labels),
+			actionlint.NewRuleEvents(),
+			actionlint.NewRuleJobNeeds(),
+			actionlint.NewRuleEnvVar(),
+			actionlint.NewRuleID(),
+			actionlint.NewRuleGlob(),
+			actionlint.NewRulePermissions(),
+			actionlint.NewRuleExpression(actionlint.NewLocalActionsCache(nil, nil), actionlint.NewLocalReusableWorkflowCache(nil, "", nil)),
+		}
+		visitor := actionlint.NewVisitor()
+		for _, rule := range rules {
+			visitor.AddPass(rule)
+		}
+		if err := visitor.Visit(workflow); err != nil {
+			diagnostics = append(diagnostics, &Diagnostic{File: file, Rule: "act", Message: fmt.Sprintf("unable to check the workflow: %v", err)})
+		}
+		for _, rule := range rules {
+			errs = append(errs, rule.Errs()...)
+		}
+	}
+	for _, err := range errs {
+		diagnostics = append(diagnostics, &Diagnostic{File: file, Line: err.Line, Column: err.Column, Rule: err.Kind, Message: err.Message})
+	}
+
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		if diagnostics[i].Line != diagnostics[j].Line {
+			return diagnostics[i].Line < diagnostics[j].Line
+		}
+		return diagnostics[i].Column < diagnostics[j].Column
+	})
+	return diagnostics
+}
@@ -0,0 +1,57 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateWorkflow(t *testing.T) {
+	valid := `
+on: push
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo ${{ github.sha }}
+  test:
+    needs: build
+    runs-on: custom-label
+    steps:
+      - run: make test
+`
+	assert.Empty(t, ValidateWorkflow("valid.yml", []byte(valid), []string{"custom-label"}))
+
+	invalid := `
+on: pushed
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    timeout: 10
+    steps:
+      - run: echo ${{ github.sha }
+  test:
+    needs: deploy
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo ${{ unknown.value }}
+`
+	diagnostics := ValidateWorkflow("invalid.yml", []byte(invalid), nil)
+	rules := map[string]int{}
+	for _, diagnostic := range diagnostics {
+		assert.Equal(t, "invalid.yml", diagnostic.File)
+		rules[diagnostic.Rule] = diagnostic.Line
+	}
+	assert.Equal(t, 2, rules["events"], "invalid event name")
+	assert.Equal(t, 6, rules["syntax-check"], "unknown key")
+	assert.Equal(t, 9, rules["job-needs"], "undefined needs, reported at the job")
+	assert.Contains(t, rules, "expression")
+	for i := 1; i < len(diagnostics); i++ {
+		assert.LessOrEqual(t, diagnostics[i-1].Line, diagnostics[i].Line, "sorted by position")
+	}
+
+	diagnostics = ValidateWorkflow("broken.yml", []byte("jobs: [\n"), nil)
+	if assert.NotEmpty(t, diagnostics) {
+		assert.Equal(t, "act", diagnostics[0].Rule)
+	}
+}
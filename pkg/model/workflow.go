@@ -15,12 +15,20 @@ import (
 
 // Workflow is the structure of the files in .github/workflows
 type Workflow struct {
-	File     string
-	Name     string            `yaml:"name"`
-	RawOn    yaml.Node         `yaml:"on"`
-	Env      map[string]string `yaml:"env"`
-	Jobs     map[string]*Job   `yaml:"jobs"`
-	Defaults Defaults          `yaml:"defaults"`
+	File           string
+	Name           string            `yaml:"name"`
+	RunName        string            `yaml:"run-name"`
+	RawOn          yaml.Node         `yaml:"on"`
+	Env            map[string]string `yaml:"env"`
+	Jobs           map[string]*Job   `yaml:"jobs"`
+	Defaults       Defaults          `yaml:"defaults"`
+	RawPermissions yaml.Node         `yaml:"permissions"`
+	RawConcurrency yaml.Node         `yaml:"concurrency"`
+}
+
+// Concurrency returns the (unevaluated) concurrency of the runs of the workflow, the group is empty if it has none
+func (w *Workflow) Concurrency() Concurrency {
+	return parseConcurrency(&w.RawConcurrency)
 }
 
 // On events for the workflow
@@ -112,9 +120,15 @@ type WorkflowCallOutput struct {
 	Value       string `yaml:"value"`
 }
 
+type WorkflowCallSecret struct {
+	Description string `yaml:"description"`
+	Required    bool   `yaml:"required"`
+}
+
 type WorkflowCall struct {
 	Inputs  map[string]WorkflowCallInput  `yaml:"inputs"`
 	Outputs map[string]WorkflowCallOutput `yaml:"outputs"`
+	Secrets map[string]WorkflowCallSecret `yaml:"secrets"`
 }
 
 func (w *Workflow) WorkflowCallConfig() *WorkflowCall {
@@ -140,22 +154,25 @@ func (w *Workflow) WorkflowCallConfig() *WorkflowCall {
 
 // Job is the structure of one job in a workflow
 type Job struct {
-	Name           string                    `yaml:"name"`
-	RawNeeds       yaml.Node                 `yaml:"needs"`
-	RawRunsOn      yaml.Node                 `yaml:"runs-on"`
-	Env            yaml.Node                 `yaml:"env"`
-	If             yaml.Node                 `yaml:"if"`
-	Steps          []*Step                   `yaml:"steps"`
-	TimeoutMinutes string                    `yaml:"timeout-minutes"`
-	Services       map[string]*ContainerSpec `yaml:"services"`
-	Strategy       *Strategy                 `yaml:"strategy"`
-	RawContainer   yaml.Node                 `yaml:"container"`
-	Defaults       Defaults                  `yaml:"defaults"`
-	Outputs        map[string]string         `yaml:"outputs"`
-	Uses           string                    `yaml:"uses"`
-	With           map[string]interface{}    `yaml:"with"`
-	RawSecrets     yaml.Node                 `yaml:"secrets"`
-	Result         string
+	Name               string                    `yaml:"name"`
+	RawNeeds           yaml.Node                 `yaml:"needs"`
+	RawRunsOn          yaml.Node                 `yaml:"runs-on"`
+	Env                yaml.Node                 `yaml:"env"`
+	If                 yaml.Node                 `yaml:"if"`
+	Steps              []*Step                   `yaml:"steps"`
+	TimeoutMinutes     string                    `yaml:"timeout-minutes"`
+	Services           map[string]*ContainerSpec `yaml:"services"`
+	Strategy           *Strategy                 `yaml:"strategy"`
+	RawContainer       yaml.Node                 `yaml:"container"`
+	Defaults           Defaults                  `yaml:"defaults"`
+	Outputs            map[string]string         `yaml:"outputs"`
+	Uses               string                    `yaml:"uses"`
+	With               map[string]interface{}    `yaml:"with"`
+	RawSecrets         yaml.Node                 `yaml:"secrets"`
+	RawContinueOnError string                    `yaml:"continue-on-error"`
+	RawPermissions     yaml.Node                 `yaml:"permissions"`
+	RawConcurrency     yaml.Node                 `yaml:"concurrency"`
+	Result             string
 }
 
 // Strategy for the job
@@ -239,17 +256,22 @@ func (j *Job) Secrets() map[string]string {
 
 // Container details for the job
 func (j *Job) Container() *ContainerSpec {
+	return DecodeContainer(&j.RawContainer)
+}
+
+// DecodeContainer decodes the container of a job, an image or a mapping, nil if the node is neither
+func DecodeContainer(node *yaml.Node) *ContainerSpec {
 	var val *ContainerSpec
-	switch j.RawContainer.Kind {
+	switch node.Kind {
 	case yaml.ScalarNode:
 		val = new(ContainerSpec)
-		err := j.RawContainer.Decode(&val.Image)
+		err := node.Decode(&val.Image)
 		if err != nil {
 			log.Fatal(err)
 		}
 	case yaml.MappingNode:
 		val = new(ContainerSpec)
-		err := j.RawContainer.Decode(val)
+		err := node.Decode(val)
 		if err != nil {
 			log.Fatal(err)
 		}
@@ -257,6 +279,36 @@ func (j *Job) Container() *ContainerSpec {
 	return val
 }
 
+// Concurrency is the concurrency group of a workflow or a job, its expressions are not evaluated
+type Concurrency struct {
+	Group            string `yaml:"group"`
+	CancelInProgress string `yaml:"cancel-in-progress"` // a boolean or an expression, false if empty
+}
+
+func parseConcurrency(node *yaml.Node) Concurrency {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return Concurrency{Group: node.Value}
+	case yaml.MappingNode:
+		var val Concurrency
+		if err := node.Decode(&val); err != nil {
+			log.Fatal(err)
+		}
+		return val
+	}
+	return Concurrency{}
+}
+
+// Concurrency returns the (unevaluated) concurrency of the job, the group is empty if it has none
+func (j *Job) Concurrency() Concurrency {
+	return parseConcurrency(&j.RawConcurrency)
+}
+
+// ConcurrencyGroup returns the (unevaluated) concurrency group of the job, empty if it has none
+func (j *Job) ConcurrencyGroup() string {
+	return j.Concurrency().Group
+}
+
 // Needs list for Job
 func (j *Job) Needs() []string {
 	switch j.RawNeeds.Kind {
@@ -278,19 +330,50 @@ func (j *Job) Needs() []string {
 	return nil
 }
 
-// RunsOn list for Job
+// RunsOn list for Job, the labels of the object form runs-on: { group: ..., labels: [...] } without its group
 func (j *Job) RunsOn() []string {
-	switch j.RawRunsOn.Kind {
+	if j.RawRunsOn.Kind == yaml.MappingNode {
+		var val struct {
+			Labels yaml.Node `yaml:"labels"`
+		}
+		if err := j.RawRunsOn.Decode(&val); err != nil {
+			log.Fatal(err)
+		}
+		if labels := runsOnLabels(val.Labels); labels != nil {
+			return labels
+		}
+		// a job running on any runner of its group
+		return []string{}
+	}
+	return runsOnLabels(j.RawRunsOn)
+}
+
+// RunsOnGroup returns the runner group of the object form runs-on: { group: ..., labels: [...] }, empty if there is none
+func (j *Job) RunsOnGroup() string {
+	if j.RawRunsOn.Kind != yaml.MappingNode {
+		return ""
+	}
+	var val struct {
+		Group string `yaml:"group"`
+	}
+	if err := j.RawRunsOn.Decode(&val); err != nil {
+		log.Fatal(err)
+	}
+	return val.Group
+}
+
+func runsOnLabels(node yaml.Node) []string {
+	switch node.Kind {
 	case yaml.ScalarNode:
 		var val string
-		err := j.RawRunsOn.Decode(&val)
+		err := node.Decode(&val)
 		if err != nil {
 			log.Fatal(err)
 		}
 		return []string{val}
 	case yaml.SequenceNode:
 		var val []string
-		err := j.RawRunsOn.Decode(&val)
+		err := node.Decode(&val)
 		if err != nil {
 			log.Fatal(err)
 		}
@@ -309,6 +392,15 @@ func environment(yml yaml.Node) map[string]string {
 	return env
 }
 
+// Permissions returns the permissions of the GITHUB_TOKEN for the job, the job inherits the permissions
+// of the workflow. It returns nil if neither sets permissions, in which case every scope is writable.
+func (j *Job) Permissions(w *Workflow) (Permissions, error) {
+	if j.RawPermissions.Kind != 0 || w == nil {
+		return ParsePermissions(j.RawPermissions)
+	}
+	return ParsePermissions(w.RawPermissions)
+}
+
 // Environments returns string-based key=value map for a job
 func (j *Job) Environment() map[string]string {
 	return environment(j.Env)
@@ -337,38 +429,18 @@ func (j *Job) GetMatrixes() []map[string]interface{} {
 		j.Strategy.MaxParallel = j.Strategy.GetMaxParallel()
 
 		if m := j.Matrix(); m != nil {
+			// like on GitHub the values of an include are added to every combination whose original values they don't
+			// change, an include changing the original values of all combinations is a combination of its own
 			includes := make([]map[string]interface{}, 0)
 			extraIncludes := make([]map[string]interface{}, 0)
 			for _, v := range m["include"] {
 				switch t := v.(type) {
 				case []interface{}:
 					for _, i := range t {
-						i := i.(map[string]interface{})
-						extraInclude := true
-						for k := range i {
-							if _, ok := m[k]; ok {
-								includes = append(includes, i)
-								extraInclude = false
-								break
-							}
-						}
-						if extraInclude {
-							extraIncludes = append(extraIncludes, i)
-						}
+						includes = append(includes, i.(map[string]interface{}))
 					}
 				case interface{}:
-					v := v.(map[string]interface{})
-					extraInclude := true
-					for k := range v {
-						if _, ok := m[k]; ok {
-							includes = append(includes, v)
-							extraInclude = false
-							break
-						}
-					}
-					if extraInclude {
-						extraIncludes = append(extraIncludes, v)
-					}
+					includes = append(includes, v.(map[string]interface{}))
 				}
 			}
 			delete(m, "include")
@@ -542,28 +614,72 @@ func (s *Step) GetEnv() map[string]string {
 	return env
 }
 
+// Shell describes how a run step runs its script with a shell
+type Shell struct {
+	Command   string // the invocation, {0} is the path of the script
+	Extension string // the extension of the script, some interpreters require it
+	Prepend   string // the line before the script
+	Append    string // the line after the script
+	ToolCache string // the directory of the interpreter in the tool cache, empty if it isn't provisioned from it
+}
+
+// Shells are the shell keywords of the run steps with the invocations of GitHub
+// Reference: https://github.com/actions/runner/blob/8109c962f09d9acc473d92c595ff43afceddb347/src/Runner.Worker/Handlers/ScriptHandlerHelpers.cs#L9-L64
+var Shells = map[string]Shell{
+	"bash": {Command: "bash --noprofile --norc -e -o pipefail {0}", Extension: ".sh"},
+	"sh":   {Command: "sh -e {0}", Extension: ".sh"},
+	"pwsh": {
+		Command:   "pwsh -command . '{0}'",
+		Extension: ".ps1",
+		Prepend:   "$ErrorActionPreference = 'stop'",
+		Append:    "if ((Test-Path -LiteralPath variable:/LASTEXITCODE)) { exit $LASTEXITCODE }",
+	},
+	"powershell": {
+		Command:   "powershell -command . '{0}'",
+		Extension: ".ps1",
+		Prepend:   "$ErrorActionPreference = 'stop'",
+		Append:    "if ((Test-Path -LiteralPath variable:/LASTEXITCODE)) { exit $LASTEXITCODE }",
+	},
+	"cmd":    {Command: "%ComSpec% /D /E:ON /V:OFF /S /C \"CALL \"{0}\"\"", Extension: ".cmd", Prepend: "@echo off"},
+	"python": {Command: "python {0}", Extension: ".py", ToolCache: "Python"},
+	"node":   {Command: "node {0}", Extension: ".js", ToolCache: "node"},
+}
+
+// LookupShell returns how the shell runs the script. A custom shell is a template like "perl -w {0}", the script
+// is appended if it has no {0}, and it runs the script like the shell keyword of its interpreter.
+func LookupShell(shell string) Shell {
+	if shell == "" {
+		shell = "bash"
+	}
+	if known, ok := Shells[shell]; ok {
+		return known
+	}
+	custom := Shell{Command: shell}
+	if fields := strings.Fields(shell); len(fields) > 0 {
+		interpreter := fields[0]
+		if i := strings.LastIndexAny(interpreter, `/\`); i >= 0 {
+			interpreter = interpreter[i+1:]
+		}
+		interpreter = strings.TrimSuffix(strings.ToLower(interpreter), ".exe")
+		known, ok := Shells[interpreter]
+		if !ok {
+			// a versioned interpreter like python3
+			known = Shells[strings.TrimRight(interpreter, "0123456789.")]
+		}
+		custom.Extension = known.Extension
+		custom.Prepend = known.Prepend
+		custom.Append = known.Append
+		custom.ToolCache = known.ToolCache
+	}
+	if !strings.Contains(shell, "{0}") {
+		custom.Command += " {0}"
+	}
+	return custom
+}
+
 // ShellCommand returns the command for the shell
 func (s *Step) ShellCommand() string {
-	shellCommand := ""
-
-	//Reference: https://github.com/actions/runner/blob/8109c962f09d9acc473d92c595ff43afceddb347/src/Runner.Worker/Handlers/ScriptHandlerHelpers.cs#L9-L17
-	switch s.Shell {
-	case "", "bash":
-		shellCommand = "bash --noprofile --norc -e -o pipefail {0}"
-	case "pwsh":
-		shellCommand = "pwsh -command . '{0}'"
-	case "python":
-		shellCommand = "python {0}"
-	case "sh":
-		shellCommand = "sh -e {0}"
-	case "cmd":
-		shellCommand = "%ComSpec% /D /E:ON /V:OFF /S /C \"CALL \"{0}\"\""
-	case "powershell":
-		shellCommand = "powershell -command . '{0}'"
-	default:
-		shellCommand = s.Shell
-	}
-	return shellCommand
+	return LookupShell(s.Shell).Command
 }
 
 // StepType describes what type of step we are about to run
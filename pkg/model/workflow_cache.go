@@ -0,0 +1,214 @@
+package model
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+
+	"github.com/nektos/act/pkg/common"
+)
+
+// WorkflowCache stores the parsed YAML of workflows keyed by the hash of their content, so unchanged
+// workflows don't have to be parsed again. The plans of the workflows are stored keyed by the hashes of the
+// workflows and the planned event or job. The entries are kept in memory and persisted to a directory.
+type WorkflowCache struct {
+	dir     string
+	mu      sync.Mutex
+	entries map[string][]byte
+}
+
+// NewWorkflowCache creates a cache persisted to dir, the cache is only kept in memory if dir is empty
+func NewWorkflowCache(dir string) *WorkflowCache {
+	return &WorkflowCache{
+		dir:     dir,
+		entries: map[string][]byte{},
+	}
+}
+
+// ReadWorkflow reads a workflow, the workflow is only parsed if its content is not in the cache.
+// Every call returns a new Workflow, which can be modified without affecting the cache.
+func (c *WorkflowCache) ReadWorkflow(in io.Reader) (*Workflow, error) {
+	w, _, err := c.readWorkflow(in)
+	return w, err
+}
+
+// readWorkflow works like ReadWorkflow and returns the hash of the content of the workflow as well
+func (c *WorkflowCache) readWorkflow(in io.Reader) (*Workflow, string, error) {
+	content, err := io.ReadAll(in)
+	if err != nil {
+		return nil, "", err
+	}
+	sum := sha256.Sum256(content)
+	key := hex.EncodeToString(sum[:])
+
+	node := new(yaml.Node)
+	if !c.load(key, node) {
+		node = new(yaml.Node)
+		if err := yaml.NewDecoder(bytes.NewReader(content)).Decode(node); err != nil {
+			return nil, "", err
+		}
+		c.store(key, node)
+	}
+
+	w := new(Workflow)
+	err = node.Decode(w)
+	return w, key, err
+}
+
+// cachedRun is a run of a cached plan, the workflow is the index of the workflow in the planner
+type cachedRun struct {
+	Workflow int
+	JobID    string
+}
+
+// planKey returns the key of the plan of the workflows with the given hashes, selection is the planned event or job
+func planKey(hashes []string, selection string) string {
+	h := sha256.New()
+	for _, hash := range hashes {
+		_, _ = io.WriteString(h, hash+"\n")
+	}
+	_, _ = io.WriteString(h, selection)
+	return "plan-" + hex.EncodeToString(h.Sum(nil))
+}
+
+// loadPlan returns the cached plan of the workflows
+func (c *WorkflowCache) loadPlan(key string, workflows []*Workflow) (*Plan, bool) {
+	var stages [][]cachedRun
+	if !c.load(key, &stages) {
+		return nil, false
+	}
+	plan := &Plan{Stages: make([]*Stage, 0, len(stages))}
+	for _, runs := range stages {
+		stage := &Stage{Runs: make([]*Run, 0, len(runs))}
+		for _, run := range runs {
+			if run.Workflow < 0 || run.Workflow >= len(workflows) || workflows[run.Workflow].GetJob(run.JobID) == nil {
+				log.Debugf("Ignoring stale plan cache entry '%s'", key)
+				return nil, false
+			}
+			stage.Runs = append(stage.Runs, &Run{Workflow: workflows[run.Workflow], JobID: run.JobID})
+		}
+		plan.Stages = append(plan.Stages, stage)
+	}
+	return plan, true
+}
+
+// storePlan stores a plan of the workflows
+func (c *WorkflowCache) storePlan(key string, plan *Plan, workflows []*Workflow) {
+	index := make(map[*Workflow]int, len(workflows))
+	for i, w := range workflows {
+		index[w] = i
+	}
+	stages := make([][]cachedRun, 0, len(plan.Stages))
+	for _, stage := range plan.Stages {
+		runs := make([]cachedRun, 0, len(stage.Runs))
+		for _, run := range stage.Runs {
+			i, ok := index[run.Workflow]
+			if !ok {
+				return
+			}
+			runs = append(runs, cachedRun{Workflow: i, JobID: run.JobID})
+		}
+		stages = append(stages, runs)
+	}
+	c.store(key, stages)
+}
+
+// Prune removes the persisted entries which were not used within maxAge, afterwards the entries used least
+// recently are removed until the entries take at most maxSize
+func (c *WorkflowCache) Prune(maxSize int64, maxAge time.Duration) error {
+	if c.dir == "" {
+		return nil
+	}
+	entries, err := os.ReadDir(c.dir)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || time.Since(info.ModTime()) < maxAge {
+			continue
+		}
+		log.Debugf("Removing stale workflow cache entry '%s'", entry.Name())
+		if err := os.Remove(filepath.Join(c.dir, entry.Name())); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	_, err = common.EvictCache([]string{c.dir}, maxSize, 0)
+	return err
+}
+
+// load decodes the entry of the key into v
+func (c *WorkflowCache) load(key string, v interface{}) bool {
+	c.mu.Lock()
+	encoded, ok := c.entries[key]
+	c.mu.Unlock()
+
+	if !ok && c.dir != "" {
+		var err error
+		if encoded, err = os.ReadFile(filepath.Join(c.dir, key)); err != nil {
+			return false
+		}
+		common.TouchCacheEntry(filepath.Join(c.dir, key))
+	}
+	if encoded == nil {
+		return false
+	}
+
+	if err := gob.NewDecoder(bytes.NewReader(encoded)).Decode(v); err != nil {
+		log.Debugf("Ignoring invalid workflow cache entry '%s': %v", key, err)
+		return false
+	}
+
+	c.mu.Lock()
+	c.entries[key] = encoded
+	c.mu.Unlock()
+	return true
+}
+
+func (c *WorkflowCache) store(key string, v interface{}) {
+	var encoded bytes.Buffer
+	if err := gob.NewEncoder(&encoded).Encode(v); err != nil {
+		log.Debugf("Unable to cache workflow '%s': %v", key, err)
+		return
+	}
+
+	c.mu.Lock()
+	c.entries[key] = encoded.Bytes()
+	c.mu.Unlock()
+
+	if c.dir == "" {
+		return
+	}
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		log.Debugf("Unable to create workflow cache '%s': %v", c.dir, err)
+		return
+	}
+	// write to a temporary file first, so that concurrent invocations never read a partial entry
+	tmp, err := os.CreateTemp(c.dir, key+".*")
+	if err != nil {
+		log.Debugf("Unable to cache workflow '%s': %v", key, err)
+		return
+	}
+	_, err = tmp.Write(encoded.Bytes())
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), filepath.Join(c.dir, key))
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		log.Debugf("Unable to cache workflow '%s': %v", key, err)
+	}
+}
@@ -0,0 +1,131 @@
+package model
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkflowCache(t *testing.T) {
+	yaml := `
+name: cached
+on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    outputs:
+      version: ${{ steps.version.outputs.version }}
+    steps:
+      - id: version
+        run: echo "::set-output name=version::1.0.0"
+`
+	dir := t.TempDir()
+	cache := NewWorkflowCache(dir)
+
+	workflow, err := cache.ReadWorkflow(strings.NewReader(yaml))
+	assert.NoError(t, err)
+	assert.Equal(t, "cached", workflow.Name)
+
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	// modifications of a workflow don't affect the cache
+	workflow.Jobs["test"].Outputs["version"] = "1.0.0"
+
+	for _, c := range []*WorkflowCache{cache, NewWorkflowCache(dir)} {
+		cached, err := c.ReadWorkflow(strings.NewReader(yaml))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"push"}, cached.On())
+		assert.Equal(t, "${{ steps.version.outputs.version }}", cached.Jobs["test"].Outputs["version"])
+		assert.Equal(t, "version", cached.Jobs["test"].Steps[0].ID)
+	}
+
+	_, err = cache.ReadWorkflow(strings.NewReader("jobs: ["))
+	assert.Error(t, err)
+}
+
+func TestWorkflowCachePlans(t *testing.T) {
+	dir := t.TempDir()
+	workflows := filepath.Join(dir, "workflows")
+	assert.NoError(t, os.MkdirAll(workflows, 0o755))
+	write := func(content string) {
+		assert.NoError(t, os.WriteFile(filepath.Join(workflows, "ci.yml"), []byte(content), 0o600))
+	}
+	write(`
+on: push
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps: [{run: echo}]
+  test:
+    needs: build
+    runs-on: ubuntu-latest
+    steps: [{run: echo}]
+`)
+	cacheDir := filepath.Join(dir, "cache")
+
+	planner, err := NewCachedWorkflowPlanner(workflows, true, NewWorkflowCache(cacheDir), nil)
+	assert.NoError(t, err)
+	plan := planner.PlanEvent("push")
+	assert.Len(t, plan.Stages, 2)
+
+	entries, err := os.ReadDir(cacheDir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2, "the workflow and its plan are cached")
+
+	// a new invocation uses the cached plan, the runs refer to the workflows of the new planner
+	planner, err = NewCachedWorkflowPlanner(workflows, true, NewWorkflowCache(cacheDir), nil)
+	assert.NoError(t, err)
+	cached := planner.PlanEvent("push")
+	assert.Len(t, cached.Stages, 2)
+	assert.Equal(t, []string{"build"}, cached.Stages[0].GetJobIDs())
+	assert.Equal(t, []string{"test"}, cached.Stages[1].GetJobIDs())
+	assert.Equal(t, "build", cached.Stages[1].Runs[0].Job().Needs()[0])
+	assert.Len(t, planner.PlanEvent("pull_request").Stages, 0)
+
+	// changed workflows are planned again
+	write(`
+on: push
+jobs:
+  lint:
+    runs-on: ubuntu-latest
+    steps: [{run: echo}]
+`)
+	planner, err = NewCachedWorkflowPlanner(workflows, true, NewWorkflowCache(cacheDir), nil)
+	assert.NoError(t, err)
+	plan = planner.PlanEvent("push")
+	assert.Len(t, plan.Stages, 1)
+	assert.Equal(t, []string{"lint"}, plan.Stages[0].GetJobIDs())
+}
+
+func TestWorkflowCachePrune(t *testing.T) {
+	dir := t.TempDir()
+	cache := NewWorkflowCache(dir)
+	for _, content := range []string{"on: push", "on: pull_request", "on: schedule"} {
+		_, err := cache.ReadWorkflow(strings.NewReader(content))
+		assert.NoError(t, err)
+	}
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 3)
+
+	stale := time.Now().Add(-48 * time.Hour)
+	assert.NoError(t, os.Chtimes(filepath.Join(dir, entries[0].Name()), stale, stale))
+	assert.NoError(t, cache.Prune(1<<20, 24*time.Hour))
+	remaining, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, remaining, 2, "the stale entry is removed")
+
+	assert.NoError(t, cache.Prune(0, 24*time.Hour))
+	remaining, err = os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, remaining, 0, "the entries are removed until they fit into the size")
+
+	assert.NoError(t, NewWorkflowCache("").Prune(0, 0))
+	assert.NoError(t, NewWorkflowCache(filepath.Join(dir, "missing")).Prune(0, 0))
+}
@@ -0,0 +1,67 @@
+package model
+
+import (
+	"bytes"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// workflowTemplatesDir is the directory of the .github repository of an organization holding the workflow templates
+const workflowTemplatesDir = "workflow-templates"
+
+// WorkflowTemplateValues are the values of the placeholders GitHub replaces when a workflow is created from a template
+type WorkflowTemplateValues struct {
+	DefaultBranch     string   // replaces $default-branch
+	ProtectedBranches []string // replaces $protected-branches, an item of a list is replaced by all branches
+	CronDaily         string   // replaces $cron-daily
+}
+
+// isWorkflowTemplate returns true if the workflow file is a template of an organization
+func isWorkflowTemplate(dirPath string) bool {
+	return filepath.Base(dirPath) == workflowTemplatesDir
+}
+
+// render replaces the placeholders of the workflow template
+func (v *WorkflowTemplateValues) render(r io.Reader) (io.Reader, error) {
+	var node yaml.Node
+	if err := yaml.NewDecoder(r).Decode(&node); err != nil {
+		return nil, err
+	}
+	v.renderNode(&node)
+
+	var b bytes.Buffer
+	encoder := yaml.NewEncoder(&b)
+	if err := encoder.Encode(&node); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+func (v *WorkflowTemplateValues) renderNode(node *yaml.Node) {
+	if node.Kind == yaml.ScalarNode {
+		node.Value = strings.NewReplacer(
+			"$default-branch", v.DefaultBranch,
+			"$cron-daily", v.CronDaily,
+		).Replace(node.Value)
+		return
+	}
+
+	content := make([]*yaml.Node, 0, len(node.Content))
+	for _, child := range node.Content {
+		if node.Kind == yaml.SequenceNode && child.Kind == yaml.ScalarNode && child.Value == "$protected-branches" {
+			for _, branch := range v.ProtectedBranches {
+				content = append(content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: branch})
+			}
+			continue
+		}
+		v.renderNode(child)
+		content = append(content, child)
+	}
+	node.Content = content
+}
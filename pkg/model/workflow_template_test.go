@@ -0,0 +1,53 @@
+package model
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkflowTemplate(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "workflow-templates")
+	assert.NoError(t, os.Mkdir(dir, 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "ci.yml"), []byte(`
+name: CI
+on:
+  push:
+    branches: [ $default-branch ]
+  pull_request:
+    branches:
+      - $protected-branches
+      - 'feature/**'
+  schedule:
+    - cron: $cron-daily
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo building $default-branch
+`), 0o644))
+
+	values := &WorkflowTemplateValues{
+		DefaultBranch:     "main",
+		ProtectedBranches: []string{"main", "release"},
+		CronDaily:         "0 0 * * *",
+	}
+	planner, err := NewCachedWorkflowPlanner(dir, true, NewWorkflowCache(""), values)
+	assert.NoError(t, err)
+
+	plan := planner.PlanEvent("push")
+	assert.Len(t, plan.Stages, 1)
+	workflow := plan.Stages[0].Runs[0].Workflow
+	var on struct {
+		Push        map[string][]string `yaml:"push"`
+		PullRequest map[string][]string `yaml:"pull_request"`
+		Schedule    []map[string]string `yaml:"schedule"`
+	}
+	assert.NoError(t, workflow.RawOn.Decode(&on))
+	assert.Equal(t, []string{"main"}, on.Push["branches"])
+	assert.Equal(t, []string{"main", "release", "feature/**"}, on.PullRequest["branches"])
+	assert.Equal(t, "0 0 * * *", on.Schedule[0]["cron"])
+	assert.Equal(t, "echo building main", workflow.Jobs["build"].Steps[0].Run)
+}
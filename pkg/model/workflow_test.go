@@ -46,6 +46,33 @@ jobs:
 	assert.Contains(t, workflow.On(), "pull_request")
 }
 
+func TestReadWorkflow_RunName(t *testing.T) {
+	yaml := `
+name: deploy
+run-name: Deploy to ${{ inputs.environment }} by @${{ github.actor }}
+on: workflow_dispatch
+env:
+  STAGE: prod
+permissions:
+  contents: read
+
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+    - run: echo
+`
+
+	workflow, err := ReadWorkflow(strings.NewReader(yaml))
+	assert.NoError(t, err, "read workflow should succeed")
+
+	assert.Equal(t, "Deploy to ${{ inputs.environment }} by @${{ github.actor }}", workflow.RunName)
+	assert.Equal(t, map[string]string{"STAGE": "prod"}, workflow.Env)
+	permissions, err := ParsePermissions(workflow.RawPermissions)
+	assert.NoError(t, err)
+	assert.Equal(t, PermissionRead, permissions["contents"])
+}
+
 func TestReadWorkflow_MapEvent(t *testing.T) {
 	yaml := `
 name: local-action-docker-url
@@ -99,6 +126,84 @@ jobs:
 	assert.Contains(t, workflow.Jobs["test2"].Container().Env["foo"], "bar")
 }
 
+func TestReadWorkflow_RunsOnGroup(t *testing.T) {
+	yaml := `
+name: runs-on-group
+
+jobs:
+  labels:
+    runs-on: [self-hosted, linux]
+    steps:
+    - run: echo
+  group:
+    runs-on:
+      group: ubuntu-runners
+      labels: [ubuntu-latest, large]
+    steps:
+    - run: echo
+  group-label:
+    runs-on:
+      group: ubuntu-runners
+      labels: ubuntu-latest
+    steps:
+    - run: echo
+  group-only:
+    runs-on:
+      group: ubuntu-runners
+    steps:
+    - run: echo
+`
+
+	workflow, err := ReadWorkflow(strings.NewReader(yaml))
+	assert.NoError(t, err, "read workflow should succeed")
+	assert.Equal(t, []string{"self-hosted", "linux"}, workflow.Jobs["labels"].RunsOn())
+	assert.Equal(t, "", workflow.Jobs["labels"].RunsOnGroup())
+	assert.Equal(t, []string{"ubuntu-latest", "large"}, workflow.Jobs["group"].RunsOn())
+	assert.Equal(t, "ubuntu-runners", workflow.Jobs["group"].RunsOnGroup())
+	assert.Equal(t, []string{"ubuntu-latest"}, workflow.Jobs["group-label"].RunsOn())
+	assert.Equal(t, []string{}, workflow.Jobs["group-only"].RunsOn())
+	assert.Equal(t, "ubuntu-runners", workflow.Jobs["group-only"].RunsOnGroup())
+}
+
+func TestReadWorkflow_ConcurrencyGroup(t *testing.T) {
+	yaml := `
+name: deploy
+concurrency:
+  group: ${{ github.workflow }}-${{ github.ref }}
+  cancel-in-progress: ${{ github.ref != 'refs/heads/main' }}
+
+jobs:
+  staging:
+    concurrency: deploy-${{ github.ref }}
+    runs-on: ubuntu-latest
+    steps:
+    - run: echo
+  prod:
+    concurrency:
+      group: prod
+      cancel-in-progress: true
+    runs-on: ubuntu-latest
+    steps:
+    - run: echo
+  test:
+    runs-on: ubuntu-latest
+    steps:
+    - run: echo
+`
+
+	workflow, err := ReadWorkflow(strings.NewReader(yaml))
+	assert.NoError(t, err, "read workflow should succeed")
+	assert.Equal(t, "deploy-${{ github.ref }}", workflow.Jobs["staging"].ConcurrencyGroup())
+	assert.Equal(t, "prod", workflow.Jobs["prod"].ConcurrencyGroup())
+	assert.Equal(t, "", workflow.Jobs["test"].ConcurrencyGroup())
+
+	assert.Equal(t, Concurrency{Group: "prod", CancelInProgress: "true"}, workflow.Jobs["prod"].Concurrency())
+	assert.Equal(t, Concurrency{
+		Group:            "${{ github.workflow }}-${{ github.ref }}",
+		CancelInProgress: "${{ github.ref != 'refs/heads/main' }}",
+	}, workflow.Concurrency())
+}
+
 func TestReadWorkflow_ObjectContainer(t *testing.T) {
 	yaml := `
 name: local-action-docker-url
@@ -269,10 +374,9 @@ func TestReadWorkflow_Strategy(t *testing.T) {
 	job = wf.Jobs["strategy-all"]
 	assert.Equal(t, job.GetMatrixes(),
 		[]map[string]interface{}{
-			{"datacenter": "site-c", "node-version": "14.x", "site": "staging"},
-			{"datacenter": "site-c", "node-version": "16.x", "site": "staging"},
-			{"datacenter": "site-d", "node-version": "16.x", "site": "staging"},
-			{"php-version": 5.4},
+			{"datacenter": "site-c", "node-version": "14.x", "site": "staging", "php-version": 5.4},
+			{"datacenter": "site-c", "node-version": "16.x", "site": "staging", "php-version": 5.4},
+			{"datacenter": "site-d", "node-version": "16.x", "site": "staging", "php-version": 5.4},
 			{"datacenter": "site-a", "node-version": "10.x", "site": "prod"},
 			{"datacenter": "site-b", "node-version": "12.x", "site": "dev"},
 		},
@@ -304,6 +408,10 @@ func TestStep_ShellCommand(t *testing.T) {
 		{"pwsh -v '. {0}'", "pwsh -v '. {0}'"},
 		{"pwsh", "pwsh -command . '{0}'"},
 		{"powershell", "powershell -command . '{0}'"},
+		{"", "bash --noprofile --norc -e -o pipefail {0}"},
+		{"python", "python {0}"},
+		{"node", "node {0}"},
+		{"perl -w", "perl -w {0}"},
 	}
 	for _, tt := range tests {
 		t.Run(tt.shell, func(t *testing.T) {
@@ -312,3 +420,29 @@ func TestStep_ShellCommand(t *testing.T) {
 		})
 	}
 }
+
+func TestLookupShell(t *testing.T) {
+	tests := []struct {
+		shell     string
+		extension string
+		prepend   string
+		toolCache string
+	}{
+		{"bash", ".sh", "", ""},
+		{"bash -x {0}", ".sh", "", ""},
+		{"pwsh -noprofile -command . '{0}'", ".ps1", "$ErrorActionPreference = 'stop'", ""},
+		{"/usr/bin/python3 -u {0}", ".py", "", "Python"},
+		{"python -u {0}", ".py", "", "Python"},
+		{"node --enable-source-maps {0}", ".js", "", "node"},
+		{"cmd", ".cmd", "@echo off", ""},
+		{"perl {0}", "", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.shell, func(t *testing.T) {
+			shell := LookupShell(tt.shell)
+			assert.Equal(t, tt.extension, shell.Extension)
+			assert.Equal(t, tt.prepend, shell.Prepend)
+			assert.Equal(t, tt.toolCache, shell.ToolCache)
+		})
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"sort"
 	"strings"
 
 	"github.com/kballard/go-shellquote"
@@ -147,7 +148,7 @@ func runActionImpl(step actionStep, actionDir string, remoteAction *remoteAction
 		logger.Debugf("type=%v actionDir=%s actionPath=%s workdir=%s actionCacheDir=%s actionName=%s containerActionDir=%s", stepModel.Type(), actionDir, actionPath, rc.Config.Workdir, rc.ActionCacheDir(), actionName, containerActionDir)
 
 		switch action.Runs.Using {
-		case model.ActionRunsUsingNode12, model.ActionRunsUsingNode16:
+		case model.ActionRunsUsingNode12, model.ActionRunsUsingNode16, model.ActionRunsUsingNode20:
 			if err := maybeCopyToActionDir(ctx, step, actionDir, actionPath, containerActionDir); err != nil {
 				return err
 			}
@@ -169,11 +170,22 @@ func runActionImpl(step actionStep, actionDir string, remoteAction *remoteAction
 			}
 
 			return execAsComposite(step)(ctx)
+		case model.ActionRunsUsingGo:
+			if !rc.Config.ExperimentalGoActions {
+				return fmt.Errorf("the action '%s' runs using 'go', which is experimental, enable it with --experimental-go-actions", stepModel.Uses)
+			}
+			if err := maybeCopyToActionDir(ctx, step, actionDir, actionPath, containerActionDir); err != nil {
+				return err
+			}
+
+			return execAsGo(step, actionName, containerActionDir)(ctx)
 		default:
 			return fmt.Errorf(fmt.Sprintf("The runs.using key must be one of: %v, got %s", []string{
 				model.ActionRunsUsingDocker,
+				model.ActionRunsUsingGo,
 				model.ActionRunsUsingNode12,
 				model.ActionRunsUsingNode16,
+				model.ActionRunsUsingNode20,
 				model.ActionRunsUsingComposite,
 			}, action.Runs.Using))
 		}
@@ -189,7 +201,10 @@ func setupActionEnv(ctx context.Context, step actionStep, remoteAction *remoteAc
 	// the action
 	rc.withGithubEnv(ctx, step.getGithubContext(ctx), *step.getEnv())
 	populateEnvsFromSavedState(step.getEnv(), step, rc)
-	populateEnvsFromInput(ctx, step.getEnv(), step.getActionModel(), rc)
+	if err := checkRequiredInputs(step); err != nil {
+		return err
+	}
+	populateEnvsFromInput(ctx, step)
 
 	return nil
 }
@@ -222,7 +237,8 @@ func execAsDocker(ctx context.Context, step actionStep, actionName string, based
 	var prepImage common.Executor
 	var image string
 	if strings.HasPrefix(action.Runs.Image, "docker://") {
-		image = strings.TrimPrefix(action.Runs.Image, "docker://")
+		// the images of the actions aren't known to 'act lock', they are pinned if the lockfile has them
+		image, _ = rc.Config.ImageLock.pin(strings.TrimPrefix(action.Runs.Image, "docker://"))
 	} else {
 		// "-dockeraction" enshures that "./", "./test " won't get converted to "act-:latest", "act-test-:latest" which are invalid docker image names
 		image = fmt.Sprintf("%s-dockeraction:%s", regexp.MustCompile("[^a-zA-Z0-9]").ReplaceAllString(actionName, "-"), "latest")
@@ -265,6 +281,12 @@ func execAsDocker(ctx context.Context, step actionStep, actionName string, based
 		} else {
 			logger.Debugf("image '%s' for architecture '%s' already exists", image, rc.Config.ContainerArchitecture)
 		}
+		if rc.Config.LocalRegistry != "" {
+			// the step container is created from the image of the registry, which the registry loses with every run
+			ref := localRegistryImage(rc.Config.LocalRegistry, image)
+			prepImage = common.NewPipelineExecutor(prepImage, container.NewDockerPushExecutor(image, ref))
+			image = ref
+		}
 	}
 	eval := rc.NewStepExpressionEvaluator(ctx, step)
 	cmd, err := shellquote.Split(eval.Interpolate(ctx, step.getStepModel().With["args"]))
@@ -298,6 +320,28 @@ func execAsDocker(ctx context.Context, step actionStep, actionName string, based
 	).Finally(stepContainer.Close())(ctx)
 }
 
+// execAsGo builds the main package of a Go action with the toolchain of the job container and runs the binary,
+// the inputs are passed in the env like for the node actions
+func execAsGo(step actionStep, actionName string, containerActionDir string) common.Executor {
+	rc := step.getRunContext()
+	action := step.getActionModel()
+
+	return func(ctx context.Context) error {
+		logger := common.Logger(ctx)
+		rc.ApplyExtraPath(step.getEnv())
+
+		binary := path.Join(rc.JobContainer.GetActPath(), "go-actions", strings.Trim(strings.NewReplacer("/", "-", "\\", "-", ".", "-").Replace(actionName), "-"))
+		buildArgs := []string{"go", "build", "-o", binary, "./" + path.Clean(action.Runs.Main)}
+		logger.Debugf("building go action in %s: %s", containerActionDir, buildArgs)
+		if err := rc.execJobContainer(buildArgs, *step.getEnv(), "", containerActionDir)(ctx); err != nil {
+			return fmt.Errorf("failed to build the go action '%s': %w", actionName, err)
+		}
+
+		logger.Debugf("executing go action: %s", binary)
+		return rc.execJobContainer([]string{binary}, *step.getEnv(), "", "")(ctx)
+	}
+}
+
 func evalDockerArgs(ctx context.Context, step step, action *model.Action, cmd *[]string) {
 	rc := step.getRunContext()
 	stepModel := step.getStepModel()
@@ -358,7 +402,7 @@ func newStepContainer(ctx context.Context, step step, image string, cmd []string
 		Image:       image,
 		Username:    rc.Config.Secrets["DOCKER_USERNAME"],
 		Password:    rc.Config.Secrets["DOCKER_PASSWORD"],
-		Name:        createContainerName(rc.jobContainerName(), stepModel.ID),
+		Name:        rc.stepContainerName(stepModel.ID),
 		Env:         envList,
 		Mounts:      mounts,
 		NetworkMode: fmt.Sprintf("container:%s", rc.jobContainerName()),
@@ -369,6 +413,9 @@ func newStepContainer(ctx context.Context, step step, image string, cmd []string
 		UsernsMode:  rc.Config.UsernsMode,
 		Platform:    rc.Config.ContainerArchitecture,
 		Options:     rc.Config.ContainerOptions,
+		Labels:      rc.containerLabels(),
+		Runtime:     rc.Config.ContainerRuntime,
+		CreateHooks: rc.Config.ContainerCreateHooks,
 	})
 	return stepContainer
 }
@@ -383,15 +430,52 @@ func populateEnvsFromSavedState(env *map[string]string, step actionStep, rc *Run
 	}
 }
 
-func populateEnvsFromInput(ctx context.Context, env *map[string]string, action *model.Action, rc *RunContext) {
-	eval := rc.NewExpressionEvaluator(ctx)
-	for inputID, input := range action.Inputs {
+// populateEnvsFromInput sets the inputs the step doesn't provide to their defaults, which are evaluated against the
+// context of the step. In a composite action the env has the inputs of the composite action, like on GitHub they
+// aren't the inputs of the actions it uses.
+func populateEnvsFromInput(ctx context.Context, step actionStep) {
+	env := step.getEnv()
+	rc := step.getRunContext()
+	eval := rc.NewStepExpressionEvaluator(ctx, step)
+	provided := step.getStepModel().GetEnv()
+	names := make([]string, 0, len(step.getActionModel().Inputs))
+	for inputID, input := range step.getActionModel().Inputs {
 		envKey := regexp.MustCompile("[^A-Z0-9-]").ReplaceAllString(strings.ToUpper(inputID), "_")
 		envKey = fmt.Sprintf("INPUT_%s", envKey)
-		if _, ok := (*env)[envKey]; !ok {
+		_, ok := (*env)[envKey]
+		_, withInput := provided[envKey]
+		if !ok || (!withInput && rc.Parent != nil) {
 			(*env)[envKey] = eval.Interpolate(ctx, input.Default)
 		}
+		names = append(names, fmt.Sprintf("%s=%s", inputID, (*env)[envKey]))
+	}
+	sort.Strings(names)
+	common.Logger(ctx).Debugf("Inputs of '%s': %s", step.getStepModel().Uses, strings.Join(names, ", "))
+}
+
+// checkRequiredInputs returns an error naming the first required input of the action without a default which the
+// step doesn't provide
+func checkRequiredInputs(step actionStep) error {
+	stepModel := step.getStepModel()
+	provided := map[string]bool{}
+	for k := range stepModel.With {
+		provided[strings.ToLower(k)] = true
+	}
+	missing := make([]string, 0)
+	for inputID, input := range step.getActionModel().Inputs {
+		if input.Required && input.Default == "" && !provided[strings.ToLower(inputID)] {
+			missing = append(missing, inputID)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	sort.Strings(missing)
+	callSite := stepModel.Name
+	if callSite == "" {
+		callSite = stepModel.ID
 	}
+	return fmt.Errorf("input '%s' of action '%s' is required, it isn't provided by the 'with' of step '%s' of job '%s'", missing[0], stepModel.Uses, callSite, step.getRunContext())
 }
 
 func getContainerActionPaths(step *model.Step, actionDir string, rc *RunContext) (string, string) {
@@ -400,6 +484,11 @@ func getContainerActionPaths(step *model.Step, actionDir string, rc *RunContext)
 	if step.Type() != model.StepTypeUsesActionRemote {
 		actionName = getOsSafeRelativePath(actionDir, rc.Config.Workdir)
 		containerActionDir = rc.JobContainer.ToContainerPath(rc.Config.Workdir) + "/" + actionName
+		if rel, outside := relativeOutside(rc.Config.Workdir, actionDir); outside {
+			// a local action of the repository outside the workdir, at its path relative to the workdir
+			actionName = filepath.ToSlash(rel)
+			containerActionDir = path.Clean(rc.JobContainer.ToContainerPath(rc.Config.Workdir) + "/" + actionName)
+		}
 		actionName = "./" + actionName
 	} else if step.Type() == model.StepTypeUsesActionRemote {
 		actionName = getOsSafeRelativePath(actionDir, rc.ActionCacheDir())
@@ -415,6 +504,15 @@ func getContainerActionPaths(step *model.Step, actionDir string, rc *RunContext)
 	return actionName, containerActionDir
 }
 
+// relativeOutside returns the path relative to dir if it is outside of dir
+func relativeOutside(dir string, p string) (string, bool) {
+	rel, err := filepath.Rel(dir, p)
+	if err != nil || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))) {
+		return "", false
+	}
+	return rel, true
+}
+
 func getOsSafeRelativePath(s, prefix string) string {
 	actionName := strings.TrimPrefix(s, prefix)
 	if runtime.GOOS == "windows" {
@@ -438,13 +536,27 @@ func shouldRunPreStep(step actionStep) common.Conditional {
 	}
 }
 
+// getActionDir returns the directory of the action on the host and the path of the action in it. The directory of a
+// local action is the one its main step resolved, the root of the git repository or the workdir.
+func getActionDir(step actionStep) (string, string) {
+	rc := step.getRunContext()
+	stepModel := step.getStepModel()
+	switch s := step.(type) {
+	case *stepActionRemote:
+		return fmt.Sprintf("%s/%s", rc.ActionCacheDir(), strings.ReplaceAll(stepModel.Uses, "/", "-")), newRemoteAction(stepModel.Uses).Path
+	case *stepActionLocal:
+		if s.actionDir != "" {
+			return s.actionDir, ""
+		}
+	}
+	return filepath.Join(rc.Config.Workdir, stepModel.Uses), ""
+}
+
 func hasPreStep(step actionStep) common.Conditional {
 	return func(ctx context.Context) bool {
 		action := step.getActionModel()
 		return action.Runs.Using == model.ActionRunsUsingComposite ||
-			((action.Runs.Using == model.ActionRunsUsingNode12 ||
-				action.Runs.Using == model.ActionRunsUsingNode16) &&
-				action.Runs.Pre != "")
+			(action.Runs.Using.IsNode() && action.Runs.Pre != "")
 	}
 }
 
@@ -458,19 +570,13 @@ func runPreStep(step actionStep) common.Executor {
 		action := step.getActionModel()
 
 		switch action.Runs.Using {
-		case model.ActionRunsUsingNode12, model.ActionRunsUsingNode16:
-			// defaults in pre steps were missing, however provided inputs are available
-			populateEnvsFromInput(ctx, step.getEnv(), action, rc)
-			// todo: refactor into step
-			var actionDir string
-			var actionPath string
-			if _, ok := step.(*stepActionRemote); ok {
-				actionPath = newRemoteAction(stepModel.Uses).Path
-				actionDir = fmt.Sprintf("%s/%s", rc.ActionCacheDir(), strings.ReplaceAll(stepModel.Uses, "/", "-"))
-			} else {
-				actionDir = filepath.Join(rc.Config.Workdir, stepModel.Uses)
-				actionPath = ""
+		case model.ActionRunsUsingNode12, model.ActionRunsUsingNode16, model.ActionRunsUsingNode20:
+			// like the main step, the pre step has the defaults of the inputs and the env of the action, e.g.
+			// GITHUB_ACTION_PATH
+			if err := setupActionEnv(ctx, step, nil); err != nil {
+				return err
 			}
+			actionDir, actionPath := getActionDir(step)
 
 			actionLocation := ""
 			if actionPath != "" {
@@ -534,9 +640,7 @@ func hasPostStep(step actionStep) common.Conditional {
 	return func(ctx context.Context) bool {
 		action := step.getActionModel()
 		return action.Runs.Using == model.ActionRunsUsingComposite ||
-			((action.Runs.Using == model.ActionRunsUsingNode12 ||
-				action.Runs.Using == model.ActionRunsUsingNode16) &&
-				action.Runs.Post != "")
+			(action.Runs.Using.IsNode() && action.Runs.Post != "")
 	}
 }
 
@@ -549,16 +653,7 @@ func runPostStep(step actionStep) common.Executor {
 		stepModel := step.getStepModel()
 		action := step.getActionModel()
 
-		// todo: refactor into step
-		var actionDir string
-		var actionPath string
-		if _, ok := step.(*stepActionRemote); ok {
-			actionPath = newRemoteAction(stepModel.Uses).Path
-			actionDir = fmt.Sprintf("%s/%s", rc.ActionCacheDir(), strings.ReplaceAll(stepModel.Uses, "/", "-"))
-		} else {
-			actionDir = filepath.Join(rc.Config.Workdir, stepModel.Uses)
-			actionPath = ""
-		}
+		actionDir, actionPath := getActionDir(step)
 
 		actionLocation := ""
 		if actionPath != "" {
@@ -570,8 +665,7 @@ func runPostStep(step actionStep) common.Executor {
 		_, containerActionDir := getContainerActionPaths(stepModel, actionLocation, rc)
 
 		switch action.Runs.Using {
-		case model.ActionRunsUsingNode12, model.ActionRunsUsingNode16:
-
+		case model.ActionRunsUsingNode12, model.ActionRunsUsingNode16, model.ActionRunsUsingNode20:
 			populateEnvsFromSavedState(step.getEnv(), step, rc)
 
 			containerArgs := []string{"node", path.Join(containerActionDir, action.Runs.Post)}
@@ -22,6 +22,8 @@ func evaluateCompositeInputAndEnv(ctx context.Context, parent *RunContext, step
 	}
 
 	ee := parent.NewStepExpressionEvaluator(ctx, step)
+	// like on GitHub the names of the inputs are case insensitive
+	provided := step.getStepModel().GetEnv()
 
 	for inputID, input := range step.getActionModel().Inputs {
 		envKey := regexp.MustCompile("[^A-Z0-9-]").ReplaceAllString(strings.ToUpper(inputID), "_")
@@ -29,7 +31,7 @@ func evaluateCompositeInputAndEnv(ctx context.Context, parent *RunContext, step
 
 		// lookup if key is defined in the step but the the already
 		// evaluated value from the environment
-		_, defined := step.getStepModel().With[inputID]
+		_, defined := provided[envKey]
 		if value, ok := stepEnv[envKey]; defined && ok {
 			env[envKey] = value
 		} else {
@@ -61,15 +63,24 @@ func newCompositeRunContext(ctx context.Context, parent *RunContext, step action
 				},
 			},
 		},
-		Config:       &configCopy,
-		StepResults:  map[string]*model.StepResult{},
-		JobContainer: parent.JobContainer,
-		ActionPath:   actionPath,
-		Env:          env,
-		Masks:        parent.Masks,
-		ExtraPath:    parent.ExtraPath,
-		Parent:       parent,
-		EventJSON:    parent.EventJSON,
+		Config:            &configCopy,
+		StepResults:       map[string]*model.StepResult{},
+		JobContainer:      parent.JobContainer,
+		ActionPath:        actionPath,
+		Env:               env,
+		Masks:             parent.Masks,
+		ExtraPath:         parent.ExtraPath,
+		Parent:            parent,
+		EventJSON:         parent.EventJSON,
+		apiProxy:          parent.apiProxy,
+		apiToken:          parent.apiToken,
+		permissions:       parent.permissions,
+		hostAddress:       parent.hostAddress,
+		jobIndex:          parent.jobIndex,
+		jobTotal:          parent.jobTotal,
+		jobContainerID:    parent.jobContainerID,
+		services:          parent.services,
+		workflowNamespace: parent.workflowNamespace,
 	}
 	compositerc.ExprEval = compositerc.NewExpressionEvaluator(ctx)
 
@@ -85,7 +96,7 @@ func execAsComposite(step actionStep) common.Executor {
 
 		steps := step.getCompositeSteps()
 
-		ctx = WithCompositeLogger(ctx, &compositeRC.Masks)
+		ctx = WithCompositeLogger(ctx)
 
 		err := steps.main(ctx)
 
@@ -97,7 +108,6 @@ func execAsComposite(step actionStep) common.Executor {
 			}, eval.Interpolate(ctx, output.Value))
 		}
 
-		rc.Masks = append(rc.Masks, compositeRC.Masks...)
 		rc.ExtraPath = compositeRC.ExtraPath
 
 		return err
@@ -114,7 +124,9 @@ type compositeSteps struct {
 func (rc *RunContext) compositeExecutor(action *model.Action) *compositeSteps {
 	steps := make([]common.Executor, 0)
 	preSteps := make([]common.Executor, 0)
-	var postExecutor common.Executor
+	// a composite action without steps has nothing to clean up
+	postExecutor := common.Executor(func(ctx context.Context) error { return nil })
+	hasPost := false
 
 	sf := &stepFactoryImpl{}
 
@@ -154,12 +166,13 @@ func (rc *RunContext) compositeExecutor(action *model.Action) *compositeSteps {
 		})
 
 		// run the post executor in reverse order
-		if postExecutor != nil {
+		if hasPost {
 			stepPost := rc.newCompositeCommandExecutor(step.post())
 			postExecutor = newCompositeStepLogExecutor(stepPost.Finally(postExecutor), stepID)
 		} else {
 			stepPost := rc.newCompositeCommandExecutor(step.post())
 			postExecutor = newCompositeStepLogExecutor(stepPost, stepID)
+			hasPost = true
 		}
 	}
 
@@ -177,7 +190,7 @@ func (rc *RunContext) compositeExecutor(action *model.Action) *compositeSteps {
 
 func (rc *RunContext) newCompositeCommandExecutor(executor common.Executor) common.Executor {
 	return func(ctx context.Context) error {
-		ctx = WithCompositeLogger(ctx, &rc.Masks)
+		ctx = WithCompositeLogger(ctx)
 
 		// We need to inject a composite RunContext related command
 		// handler into the current running job container
@@ -4,6 +4,7 @@ import (
 	"context"
 	"io"
 	"io/fs"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -247,3 +248,127 @@ func TestActionRunner(t *testing.T) {
 		})
 	}
 }
+
+func TestActionRunnerGo(t *testing.T) {
+	newStep := func(config *Config) *stepActionRemote {
+		return &stepActionRemote{
+			Step: &model.Step{
+				Uses: "org/repo/path@ref",
+			},
+			RunContext: &RunContext{
+				Config: config,
+				Run: &model.Run{
+					JobID: "job",
+					Workflow: &model.Workflow{
+						Jobs: map[string]*model.Job{
+							"job": {
+								Name: "job",
+							},
+						},
+					},
+				},
+			},
+			action: &model.Action{
+				Inputs: map[string]model.Input{
+					"key": {
+						Default: "default value",
+					},
+				},
+				Runs: model.ActionRuns{
+					Using: model.ActionRunsUsingGo,
+					Main:  "./cmd/action",
+				},
+			},
+			env: map[string]string{},
+		}
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		step := newStep(&Config{})
+		step.RunContext.JobContainer = &containerMock{}
+
+		err := runActionImpl(step, "dir", newRemoteAction("org/repo/path@ref"))(context.Background())
+
+		assert.ErrorContains(t, err, "--experimental-go-actions")
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		step := newStep(&Config{ExperimentalGoActions: true})
+		cm := &containerMock{}
+		step.RunContext.JobContainer = cm
+
+		envMatcher := mock.MatchedBy(func(env map[string]string) bool {
+			return env["INPUT_KEY"] == "default value"
+		})
+		cm.On("CopyDir", "/var/run/act/actions/dir/", "dir/", false).Return(func(ctx context.Context) error { return nil })
+		cm.On("Exec", []string{"go", "build", "-o", "/var/run/act/go-actions/dir-path", "./cmd/action"}, envMatcher, "", "/var/run/act/actions/dir/path").Return(func(ctx context.Context) error { return nil })
+		cm.On("Exec", []string{"/var/run/act/go-actions/dir-path"}, envMatcher, "", "").Return(func(ctx context.Context) error { return nil })
+
+		err := runActionImpl(step, "dir", newRemoteAction("org/repo/path@ref"))(context.Background())
+
+		assert.Nil(t, err)
+		cm.AssertExpectations(t)
+	})
+}
+
+func TestActionInputs(t *testing.T) {
+	newStep := func(with map[string]string, env map[string]string) *stepActionRemote {
+		return &stepActionRemote{
+			Step: &model.Step{
+				ID:   "deploy",
+				Uses: "org/repo/path@ref",
+				With: with,
+			},
+			RunContext: &RunContext{
+				Name:   "job",
+				Config: &Config{},
+				Run: &model.Run{
+					JobID: "job",
+					Workflow: &model.Workflow{
+						Name: "workflow",
+						Jobs: map[string]*model.Job{
+							"job": {
+								Name: "job",
+							},
+						},
+					},
+				},
+			},
+			action: &model.Action{
+				Inputs: map[string]model.Input{
+					"token":  {Required: true},
+					"region": {Required: true, Default: "${{ env.DEFAULT_REGION }}"},
+				},
+			},
+			env: env,
+		}
+	}
+
+	step := newStep(nil, map[string]string{})
+	assert.EqualError(t, checkRequiredInputs(step), "input 'token' of action 'org/repo/path@ref' is required, it isn't provided by the 'with' of step 'deploy' of job 'workflow/job'")
+
+	step = newStep(map[string]string{"Token": "secret"}, map[string]string{"INPUT_TOKEN": "secret", "DEFAULT_REGION": "eu-west-1"})
+	assert.NoError(t, checkRequiredInputs(step))
+	populateEnvsFromInput(context.Background(), step)
+	assert.Equal(t, "secret", step.env["INPUT_TOKEN"])
+	assert.Equal(t, "eu-west-1", step.env["INPUT_REGION"])
+
+	// in a composite action the inputs of the composite action aren't the inputs of the action
+	step = newStep(map[string]string{"token": "secret"}, map[string]string{"INPUT_TOKEN": "secret", "INPUT_REGION": "us-east-1", "DEFAULT_REGION": "eu-west-1"})
+	step.RunContext.Parent = &RunContext{}
+	populateEnvsFromInput(context.Background(), step)
+	assert.Equal(t, "secret", step.env["INPUT_TOKEN"])
+	assert.Equal(t, "eu-west-1", step.env["INPUT_REGION"])
+}
+
+func TestGetActionDir(t *testing.T) {
+	rc := &RunContext{Config: &Config{Workdir: "/workdir"}}
+
+	dir, actionPath := getActionDir(&stepActionLocal{Step: &model.Step{Uses: "./actions/build"}, RunContext: rc})
+	assert.Equal(t, filepath.Join("/workdir", "actions", "build"), dir)
+	assert.Equal(t, "", actionPath)
+
+	// the local action found at the root of the git repository
+	dir, _ = getActionDir(&stepActionLocal{Step: &model.Step{Uses: "./actions/build"}, RunContext: rc, actionDir: "/repo/actions/build"})
+	assert.Equal(t, "/repo/actions/build", dir)
+}
@@ -0,0 +1,148 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/nektos/act/pkg/common"
+)
+
+// Assertions describe the expected results of a run
+type Assertions struct {
+	Jobs      map[string]*JobAssertion     `yaml:"jobs"`
+	Artifacts map[string]map[string]string `yaml:"artifacts"` // artifact name => file path => expected content
+}
+
+// JobAssertion describes the expected result of a job, it has to match every leg of a matrix job
+type JobAssertion struct {
+	Result  string                    `yaml:"result"`
+	Outputs map[string]string         `yaml:"outputs"`
+	Steps   map[string]*StepAssertion `yaml:"steps"`
+	Env     map[string]string         `yaml:"env"`
+}
+
+// StepAssertion describes the expected result of a step
+type StepAssertion struct {
+	Outcome    string            `yaml:"outcome"`
+	Conclusion string            `yaml:"conclusion"`
+	Outputs    map[string]string `yaml:"outputs"`
+}
+
+// ReadAssertions reads the assertions from a YAML file
+func ReadAssertions(path string) (*Assertions, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	assertions := new(Assertions)
+	decoder := yaml.NewDecoder(f)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(assertions); err != nil {
+		return nil, fmt.Errorf("unable to read assertions from %s: %w", path, err)
+	}
+	return assertions, nil
+}
+
+// Verify compares the assertions with the results of a run, the returned error lists every mismatch
+func (a *Assertions) Verify(results *Results, readArtifact func(runID string, name string, file string) ([]byte, error)) error {
+	diffs := make([]string, 0)
+	expect := func(key string, expected string, actual string) {
+		if expected != actual {
+			diffs = append(diffs, fmt.Sprintf("%s:\n  - expected: %q\n  + actual:   %q", key, expected, actual))
+		}
+	}
+
+	// the workflows of a plan namespaced by their file have a run each
+	runIDs := make([]string, 0)
+	for _, job := range results.Jobs {
+		if !common.ContainsString(runIDs, job.ArtifactRunID) {
+			runIDs = append(runIDs, job.ArtifactRunID)
+		}
+	}
+	if len(runIDs) == 0 {
+		runIDs = append(runIDs, "1")
+	}
+
+	for _, jobID := range sortedKeys(a.Jobs) {
+		jobAssertion := a.Jobs[jobID]
+		jobs := results.Job(jobID)
+		if len(jobs) == 0 {
+			diffs = append(diffs, fmt.Sprintf("jobs.%s: job did not run", jobID))
+			continue
+		}
+
+		for _, job := range jobs {
+			prefix := fmt.Sprintf("jobs.%s", jobID)
+			if len(job.Matrix) > 0 || len(jobs) > 1 {
+				prefix = fmt.Sprintf("jobs.%s (%s)", jobID, job.Name)
+			}
+
+			if jobAssertion.Result != "" {
+				expect(prefix+".result", jobAssertion.Result, job.Result)
+			}
+			for _, k := range sortedKeys(jobAssertion.Outputs) {
+				expect(fmt.Sprintf("%s.outputs.%s", prefix, k), jobAssertion.Outputs[k], job.Outputs[k])
+			}
+			for _, k := range sortedKeys(jobAssertion.Env) {
+				expect(fmt.Sprintf("%s.env.%s", prefix, k), jobAssertion.Env[k], job.Env[k])
+			}
+			for _, stepID := range sortedKeys(jobAssertion.Steps) {
+				stepAssertion := jobAssertion.Steps[stepID]
+				stepPrefix := fmt.Sprintf("%s.steps.%s", prefix, stepID)
+				step, ok := job.Steps[stepID]
+				if !ok {
+					diffs = append(diffs, fmt.Sprintf("%s: step did not run", stepPrefix))
+					continue
+				}
+				if stepAssertion.Outcome != "" {
+					expect(stepPrefix+".outcome", stepAssertion.Outcome, step.Outcome.String())
+				}
+				if stepAssertion.Conclusion != "" {
+					expect(stepPrefix+".conclusion", stepAssertion.Conclusion, step.Conclusion.String())
+				}
+				for _, k := range sortedKeys(stepAssertion.Outputs) {
+					expect(fmt.Sprintf("%s.outputs.%s", stepPrefix, k), stepAssertion.Outputs[k], step.Outputs[k])
+				}
+			}
+		}
+	}
+
+	for _, name := range sortedKeys(a.Artifacts) {
+		files := a.Artifacts[name]
+		for _, file := range sortedKeys(files) {
+			key := fmt.Sprintf("artifacts.%s.%s", name, file)
+			var content []byte
+			var err error
+			for _, runID := range runIDs {
+				if content, err = readArtifact(runID, name, file); err == nil {
+					break
+				}
+			}
+			if err != nil {
+				diffs = append(diffs, fmt.Sprintf("%s: %v", key, err))
+				continue
+			}
+			expect(key, files[file], string(content))
+		}
+	}
+
+	if len(diffs) > 0 {
+		return fmt.Errorf("%d assertion(s) failed:\n%s", len(diffs), strings.Join(diffs, "\n"))
+	}
+	return nil
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
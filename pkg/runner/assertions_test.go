@@ -0,0 +1,100 @@
+package runner
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nektos/act/pkg/model"
+)
+
+func TestReadAssertions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "assertions.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte(`
+jobs:
+  build:
+    result: success
+    steps:
+      version:
+        outputs:
+          version: 1.2.3
+artifacts:
+  dist:
+    version.txt: 1.2.3
+`), 0o600))
+
+	assertions, err := ReadAssertions(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "success", assertions.Jobs["build"].Result)
+	assert.Equal(t, "1.2.3", assertions.Jobs["build"].Steps["version"].Outputs["version"])
+	assert.Equal(t, "1.2.3", assertions.Artifacts["dist"]["version.txt"])
+
+	assert.NoError(t, os.WriteFile(path, []byte("jobs:\n  build:\n    conclusion: success\n"), 0o600))
+	_, err = ReadAssertions(path)
+	assert.Error(t, err)
+}
+
+func TestAssertionsVerify(t *testing.T) {
+	results := &Results{
+		Jobs: []*JobResult{
+			{
+				JobID:         "build",
+				Name:          "build",
+				RunID:         "1",
+				ArtifactRunID: "1",
+				Result:        "success",
+				Outputs:       map[string]string{"version": "1.2.3"},
+				Steps: map[string]*model.StepResult{
+					"version": {
+						Outputs:    map[string]string{"version": "1.2.3"},
+						Conclusion: model.StepStatusSuccess,
+						Outcome:    model.StepStatusFailure,
+					},
+				},
+				Env: map[string]string{"DEPLOY_ENV": "staging"},
+			},
+		},
+	}
+	readArtifact := func(runID string, name string, file string) ([]byte, error) {
+		if runID == "1" && name == "dist" && file == "version.txt" {
+			return []byte("1.2.3"), nil
+		}
+		return nil, errors.New("not found")
+	}
+
+	assertions := &Assertions{
+		Jobs: map[string]*JobAssertion{
+			"build": {
+				Result:  "success",
+				Outputs: map[string]string{"version": "1.2.3"},
+				Steps: map[string]*StepAssertion{
+					"version": {Outcome: "failure", Conclusion: "success", Outputs: map[string]string{"version": "1.2.3"}},
+				},
+				Env: map[string]string{"DEPLOY_ENV": "staging"},
+			},
+		},
+		Artifacts: map[string]map[string]string{"dist": {"version.txt": "1.2.3"}},
+	}
+	assert.NoError(t, assertions.Verify(results, readArtifact))
+
+	assertions = &Assertions{
+		Jobs: map[string]*JobAssertion{
+			"build": {
+				Result: "failure",
+				Steps:  map[string]*StepAssertion{"missing": {}},
+			},
+			"deploy": {},
+		},
+		Artifacts: map[string]map[string]string{"dist": {"missing.txt": ""}},
+	}
+	assert.EqualError(t, assertions.Verify(results, readArtifact), `4 assertion(s) failed:
+jobs.build.result:
+  - expected: "failure"
+  + actual:   "success"
+jobs.build.steps.missing: step did not run
+jobs.deploy: job did not run
+artifacts.dist.missing.txt: not found`)
+}
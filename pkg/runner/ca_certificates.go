@@ -0,0 +1,89 @@
+package runner
+
+import (
+	"context"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/container"
+)
+
+// installCAScript adds $ACT_CA_CERTS to the trust store of the container and writes the bundle of the trusted
+// certificates to $ACT_CA_BUNDLE, images without update-ca-certificates or update-ca-trust only get the bundle
+const installCAScript = `
+if command -v update-ca-certificates >/dev/null 2>&1; then
+	mkdir -p /usr/local/share/ca-certificates && cp "$ACT_CA_CERTS" /usr/local/share/ca-certificates/act.crt && update-ca-certificates >/dev/null
+elif command -v update-ca-trust >/dev/null 2>&1; then
+	cp "$ACT_CA_CERTS" /etc/pki/ca-trust/source/anchors/act.crt && update-ca-trust extract
+fi
+for bundle in /etc/ssl/certs/ca-certificates.crt /etc/pki/tls/certs/ca-bundle.crt /etc/ssl/cert.pem; do
+	if [ -f "$bundle" ]; then
+		cat "$bundle" > "$ACT_CA_BUNDLE"
+		break
+	fi
+done
+cat "$ACT_CA_CERTS" >> "$ACT_CA_BUNDLE"
+`
+
+// ReadCACertificates reads the PEM encoded certificates of the files into a single bundle
+func ReadCACertificates(paths []string) (string, error) {
+	certs := make([]string, 0, len(paths))
+	for _, p := range paths {
+		content, err := os.ReadFile(p)
+		if err != nil {
+			return "", err
+		}
+		found := false
+		for rest := content; ; {
+			var block *pem.Block
+			if block, rest = pem.Decode(rest); block == nil {
+				break
+			}
+			if block.Type != "CERTIFICATE" {
+				return "", fmt.Errorf("unable to read the CA certificates %s: unexpected %s", p, block.Type)
+			}
+			certs = append(certs, string(pem.EncodeToMemory(block)))
+			found = true
+		}
+		if !found {
+			return "", fmt.Errorf("unable to read the CA certificates %s: no PEM encoded certificate found", p)
+		}
+	}
+	return strings.Join(certs, ""), nil
+}
+
+// installCACertificates installs Config.CACertificates into the trust store of the job container and points
+// NODE_EXTRA_CA_CERTS and GIT_SSL_CAINFO of the steps at them
+func (rc *RunContext) installCACertificates() common.Executor {
+	return func(ctx context.Context) error {
+		if rc.Config.CACertificates == "" || common.Dryrun(ctx) {
+			return nil
+		}
+		common.Logger(ctx).Infof("  \U0001f510  Installing the CA certificates")
+
+		dir := path.Join(rc.JobContainer.GetActPath(), "ca-certificates")
+		env := map[string]string{
+			"ACT_CA_CERTS":  path.Join(dir, "act.crt"),
+			"ACT_CA_BUNDLE": path.Join(dir, "bundle.crt"),
+		}
+		err := common.NewPipelineExecutor(
+			rc.JobContainer.Copy(dir+"/", &container.FileEntry{
+				Name: "act.crt",
+				Mode: 0644,
+				Body: rc.Config.CACertificates,
+			}),
+			rc.JobContainer.Exec([]string{"sh", "-c", installCAScript}, env, "root", ""),
+		)(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to install the CA certificates: %w", err)
+		}
+
+		rc.Env["NODE_EXTRA_CA_CERTS"] = env["ACT_CA_CERTS"]
+		rc.Env["GIT_SSL_CAINFO"] = env["ACT_CA_BUNDLE"]
+		return nil
+	}
+}
@@ -0,0 +1,83 @@
+package runner
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nektos/act/pkg/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestReadCACertificates(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "Corp Root CA"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+		IsCA:         true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+
+	dir := t.TempDir()
+	write := func(name string, content []byte) string {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.WriteFile(path, content, 0o600))
+		return path
+	}
+
+	bundle, err := ReadCACertificates([]string{
+		write("corp-root.pem", append([]byte("Corp Root CA\n"), cert...)),
+		write("proxy.pem", cert),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, string(cert)+string(cert), bundle)
+
+	_, err = ReadCACertificates([]string{write("empty.pem", []byte("no certificate"))})
+	assert.ErrorContains(t, err, "no PEM encoded certificate found")
+
+	_, err = ReadCACertificates([]string{write("key.pem", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte{1}}))})
+	assert.ErrorContains(t, err, "unexpected PRIVATE KEY")
+}
+
+func TestInstallCACertificates(t *testing.T) {
+	cm := &containerMock{}
+	rc := &RunContext{
+		Config: &Config{
+			CACertificates: "-----BEGIN CERTIFICATE-----\n-----END CERTIFICATE-----\n",
+		},
+		Env:          map[string]string{},
+		JobContainer: cm,
+	}
+
+	ok := func(ctx context.Context) error { return nil }
+	cm.On("Copy", "/var/run/act/ca-certificates/", []*container.FileEntry{{
+		Name: "act.crt",
+		Mode: 0644,
+		Body: rc.Config.CACertificates,
+	}}).Return(ok)
+	cm.On("Exec", []string{"sh", "-c", installCAScript}, mock.MatchedBy(func(env map[string]string) bool {
+		return env["ACT_CA_CERTS"] == "/var/run/act/ca-certificates/act.crt"
+	}), "root", "").Return(ok)
+
+	err := rc.installCACertificates()(context.Background())
+
+	assert.NoError(t, err)
+	cm.AssertExpectations(t)
+	assert.Equal(t, "/var/run/act/ca-certificates/act.crt", rc.Env["NODE_EXTRA_CA_CERTS"])
+	assert.Equal(t, "/var/run/act/ca-certificates/bundle.crt", rc.Env["GIT_SSL_CAINFO"])
+}
@@ -0,0 +1,52 @@
+package runner
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/container"
+)
+
+var cacheVolumeNamePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// ParseCacheVolumes parses the name:path specs of the cache volumes (e.g. go-build:/root/.cache/go-build) into a
+// map of the names to the paths they are mounted at in the job containers
+func ParseCacheVolumes(specs []string) (map[string]string, error) {
+	volumes := make(map[string]string, len(specs))
+	paths := make(map[string]string, len(specs))
+	for _, spec := range specs {
+		name, dir, ok := strings.Cut(spec, ":")
+		if !ok || name == "" || dir == "" {
+			return nil, fmt.Errorf("invalid cache volume '%s': expected name:path, e.g. go-build:/root/.cache/go-build", spec)
+		}
+		if !cacheVolumeNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("invalid cache volume '%s': the name may only contain letters, digits, '_', '.' and '-'", spec)
+		}
+		if !path.IsAbs(dir) {
+			return nil, fmt.Errorf("invalid cache volume '%s': the path has to be absolute", spec)
+		}
+		dir = path.Clean(dir)
+		if other, ok := paths[dir]; ok && other != name {
+			return nil, fmt.Errorf("invalid cache volume '%s': %s is already the path of the cache volume %s", spec, dir, other)
+		}
+		if other, ok := volumes[name]; ok && other != dir {
+			return nil, fmt.Errorf("invalid cache volume '%s': %s is already mounted at %s", spec, name, other)
+		}
+		volumes[name] = dir
+		paths[dir] = name
+	}
+	return volumes, nil
+}
+
+// createCacheVolumes creates the volumes of Config.CacheVolumes missing before the job container is created,
+// docker would create them on its own but without the labels act finds them with
+func (rc *RunContext) createCacheVolumes() common.Executor {
+	executors := make([]common.Executor, 0, len(rc.Config.CacheVolumes))
+	for name, dir := range rc.Config.CacheVolumes {
+		executors = append(executors, container.NewDockerCacheVolumeExecutor(name, dir))
+	}
+	return common.NewPipelineExecutor(executors...)
+}
@@ -0,0 +1,29 @@
+package runner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCacheVolumes(t *testing.T) {
+	volumes, err := ParseCacheVolumes([]string{"go-build:/root/.cache/go-build", "npm:/root/.npm/", "npm:/root/.npm"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"go-build": "/root/.cache/go-build", "npm": "/root/.npm"}, volumes)
+
+	for _, spec := range []string{
+		"go-build",
+		":/root/.cache/go-build",
+		"go-build:",
+		"go build:/root/.cache/go-build",
+		"go-build:.cache/go-build",
+	} {
+		_, err := ParseCacheVolumes([]string{spec})
+		assert.Error(t, err, spec)
+	}
+
+	_, err = ParseCacheVolumes([]string{"go-build:/root/.cache/go-build", "go-build:/go/cache"})
+	assert.ErrorContains(t, err, "is already mounted at /root/.cache/go-build")
+	_, err = ParseCacheVolumes([]string{"go-build:/root/.cache/go-build", "go:/root/.cache/go-build"})
+	assert.ErrorContains(t, err, "is already the path of the cache volume go-build")
+}
@@ -148,7 +148,7 @@ func captureOutput(t *testing.T, f func()) string {
 }
 
 func TestAddmaskUsemask(t *testing.T) {
-	rc := new(RunContext)
+	rc := &RunContext{Masks: NewMasker()}
 	rc.StepResults = make(map[string]*model.StepResult)
 	rc.CurrentStep = "my-step"
 	rc.StepResults[rc.CurrentStep] = &model.StepResult{
@@ -164,7 +164,7 @@ func TestAddmaskUsemask(t *testing.T) {
 
 	re := captureOutput(t, func() {
 		ctx := context.Background()
-		ctx = WithJobLogger(ctx, "0", "testjob", config, &rc.Masks, map[string]interface{}{})
+		ctx = WithJobLogger(ctx, "0", "testjob", config, rc.Masks, map[string]interface{}{})
 
 		handler := rc.commandHandler(ctx)
 		handler("::add-mask::secret\n")
@@ -0,0 +1,43 @@
+package runner
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/container"
+)
+
+// composeProject returns the name of the docker-compose project of a run, the random suffix keeps concurrent runs
+// in the same directory (or in directories with the same name) from sharing and tearing down the services of each other
+func composeProject(config *Config) string {
+	suffix := make([]byte, 4)
+	_, _ = rand.Read(suffix)
+	return strings.ToLower(createContainerName("act", filepath.Base(config.Workdir), "services")) + "-" + hex.EncodeToString(suffix)
+}
+
+func composeInput(config *Config, project string) container.NewDockerComposeExecutorInput {
+	return container.NewDockerComposeExecutorInput{
+		File:    config.ComposeServices,
+		Project: project,
+	}
+}
+
+// newComposeServicesExecutor starts the docker-compose project before the executor
+// and tears it down afterwards, job containers join the network of the project
+func newComposeServicesExecutor(config *Config, project string, executor common.Executor) common.Executor {
+	input := composeInput(config, project)
+
+	return container.NewDockerComposeUpExecutor(input).
+		Then(executor).
+		Finally(func(ctx context.Context) error {
+			// always allow 1 min for tearing down the services, even if we were cancelled
+			downCtx, cancel := context.WithTimeout(common.WithDryrun(common.WithLogger(context.Background(), common.Logger(ctx)), common.Dryrun(ctx)), time.Minute)
+			defer cancel()
+			return container.NewDockerComposeDownExecutor(input)(downCtx)
+		})
+}
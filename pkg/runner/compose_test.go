@@ -0,0 +1,41 @@
+package runner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nektos/act/pkg/model"
+)
+
+func TestComposeInput(t *testing.T) {
+	config := &Config{
+		Workdir:         "/home/user/My Project",
+		ComposeServices: "/home/user/My Project/docker-compose.yml",
+	}
+	project := composeProject(config)
+	assert.Regexp(t, `^act-my-projec-services-[0-9a-f]{8}$`, project)
+	assert.NotEqual(t, project, composeProject(config), "concurrent runs in the same directory need their own project")
+
+	input := composeInput(config, project)
+	assert.Equal(t, "/home/user/My Project/docker-compose.yml", input.File)
+	assert.Equal(t, project, input.Project)
+	assert.Equal(t, project+"_default", input.ComposeNetworkName())
+}
+
+func TestRunContextNetworkName(t *testing.T) {
+	rc := &RunContext{
+		Config: &Config{
+			Workdir: "/repo",
+		},
+		Run: &model.Run{
+			Workflow: &model.Workflow{Jobs: map[string]*model.Job{"job": createJob(t, "runs-on: ubuntu-latest", "")}},
+			JobID:    "job",
+		},
+	}
+	assert.Equal(t, "host", rc.networkName())
+
+	rc.Config.ComposeServices = "/repo/docker-compose.yml"
+	rc.composeProject = "act-repo-services-0a1b2c3d"
+	assert.Equal(t, "act-repo-services-0a1b2c3d_default", rc.networkName())
+}
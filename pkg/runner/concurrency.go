@@ -0,0 +1,304 @@
+package runner
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/exprparser"
+	"github.com/nektos/act/pkg/model"
+)
+
+// errConcurrencyCancelled is returned to a job or workflow waiting for its concurrency group when a newer run queues
+var errConcurrencyCancelled = errors.New("cancelled by a newer run queued in the concurrency group")
+
+// concurrencyHold is the claim of a job or a workflow on a concurrency group, pending until the group is free
+type concurrencyHold struct {
+	group            string
+	run              string // run of act claiming the group, the claims of a run queue without cancelling each other
+	cancelInProgress bool
+	cancelled        chan struct{} // closed when a newer run cancels the claim, pending or in progress
+	once             sync.Once
+}
+
+func newConcurrencyHold(group string, run string, cancelInProgress bool) *concurrencyHold {
+	return &concurrencyHold{
+		group:            group,
+		run:              run,
+		cancelInProgress: cancelInProgress,
+		cancelled:        make(chan struct{}),
+	}
+}
+
+func (h *concurrencyHold) cancel() {
+	h.once.Do(func() { close(h.cancelled) })
+}
+
+// concurrencyLocker queues the claims on the concurrency groups. Like on GitHub a claim of a newer run cancels the
+// pending claims of the older runs and, with cancel-in-progress, the claim in progress.
+type concurrencyLocker interface {
+	// acquire blocks until the hold gets its group or the context is done, errConcurrencyCancelled is returned if
+	// a newer run cancels it while pending. The returned func releases the group.
+	acquire(ctx context.Context, hold *concurrencyHold) (func(), error)
+}
+
+// processConcurrency queues the claims of the runs of the process, e.g. the runs of --watch, unless the runs share
+// a concurrency dir
+var processConcurrency = newConcurrencyGroups()
+
+// concurrencyGroups queues the claims on the concurrency groups in memory
+type concurrencyGroups struct {
+	mu     sync.Mutex
+	groups map[string]*concurrencyQueue
+}
+
+type concurrencyQueue struct {
+	holder  *concurrencyHold
+	pending []*concurrencyHold
+	changed chan struct{} // closed and replaced when the holder or the pending claims change
+}
+
+func (q *concurrencyQueue) notify() {
+	close(q.changed)
+	q.changed = make(chan struct{})
+}
+
+func (q *concurrencyQueue) remove(hold *concurrencyHold) {
+	for i, pending := range q.pending {
+		if pending == hold {
+			q.pending = append(q.pending[:i], q.pending[i+1:]...)
+			return
+		}
+	}
+}
+
+func newConcurrencyGroups() *concurrencyGroups {
+	return &concurrencyGroups{groups: map[string]*concurrencyQueue{}}
+}
+
+func (c *concurrencyGroups) acquire(ctx context.Context, hold *concurrencyHold) (func(), error) {
+	c.mu.Lock()
+	queue, ok := c.groups[hold.group]
+	if !ok {
+		queue = &concurrencyQueue{changed: make(chan struct{})}
+		c.groups[hold.group] = queue
+	}
+	pending := make([]*concurrencyHold, 0, len(queue.pending)+1)
+	for _, other := range queue.pending {
+		if other.run != hold.run {
+			other.cancel()
+		} else {
+			pending = append(pending, other)
+		}
+	}
+	queue.pending = append(pending, hold)
+	if hold.cancelInProgress && queue.holder != nil && queue.holder.run != hold.run {
+		queue.holder.cancel()
+	}
+	queue.notify()
+	c.mu.Unlock()
+
+	for {
+		c.mu.Lock()
+		select {
+		case <-hold.cancelled:
+			// removed from the pending claims by the newer run
+			c.mu.Unlock()
+			return nil, errConcurrencyCancelled
+		default:
+		}
+		if queue.holder == nil && queue.pending[0] == hold {
+			queue.holder = hold
+			queue.pending = queue.pending[1:]
+			c.mu.Unlock()
+			return func() {
+				c.mu.Lock()
+				queue.holder = nil
+				queue.notify()
+				c.mu.Unlock()
+			}, nil
+		}
+		changed := queue.changed
+		c.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-hold.cancelled:
+		case <-ctx.Done():
+			c.mu.Lock()
+			queue.remove(hold)
+			queue.notify()
+			c.mu.Unlock()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// heldConcurrency are the claims in progress of the runs of the process, see CancelInProgress
+var heldConcurrency = struct {
+	sync.Mutex
+	holds map[*concurrencyHold]bool
+}{holds: map[*concurrencyHold]bool{}}
+
+// holdConcurrency waits for the group of the hold, the returned func releases it
+func holdConcurrency(ctx context.Context, locker concurrencyLocker, hold *concurrencyHold) (func(), error) {
+	release, err := locker.acquire(ctx, hold)
+	if err != nil {
+		return nil, err
+	}
+	heldConcurrency.Lock()
+	heldConcurrency.holds[hold] = true
+	heldConcurrency.Unlock()
+	return func() {
+		heldConcurrency.Lock()
+		delete(heldConcurrency.holds, hold)
+		heldConcurrency.Unlock()
+		release()
+	}, nil
+}
+
+// withConcurrencyCancel returns a context which is cancelled once a newer run cancels the hold in progress
+func withConcurrencyCancel(ctx context.Context, hold *concurrencyHold) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		select {
+		case <-hold.cancelled:
+			common.Logger(ctx).Warnf("Cancelling since a newer run is queued in the concurrency group '%s'", hold.group)
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
+
+// CancelInProgress cancels the jobs and workflows of the runs of the process holding a concurrency group with
+// cancel-in-progress, which a newer run of the same workflows would cancel. It returns the number of cancelled claims.
+func CancelInProgress() int {
+	heldConcurrency.Lock()
+	defer heldConcurrency.Unlock()
+	cancelled := 0
+	for hold := range heldConcurrency.holds {
+		if hold.cancelInProgress {
+			hold.cancel()
+			cancelled++
+		}
+	}
+	return cancelled
+}
+
+// newConcurrencyRun returns the identifier of a run of act claiming concurrency groups
+func newConcurrencyRun() string {
+	randBytes := make([]byte, 4)
+	_, _ = rand.Read(randBytes)
+	return fmt.Sprintf("%d-%s", os.Getpid(), hex.EncodeToString(randBytes))
+}
+
+// evalConcurrency evaluates the concurrency of a workflow or a job into a hold of the run, nil if it has no group.
+// Like on GitHub the groups are scoped to the repository.
+func (rc *RunContext) evalConcurrency(ctx context.Context, concurrency model.Concurrency) (*concurrencyHold, error) {
+	if concurrency.Group == "" {
+		return nil, nil
+	}
+	group := rc.ExprEval.Interpolate(ctx, concurrency.Group)
+	if group == "" {
+		return nil, nil
+	}
+	cancelInProgress := false
+	if strings.TrimSpace(concurrency.CancelInProgress) != "" {
+		var err error
+		if cancelInProgress, err = EvalBool(ctx, rc.ExprEval, concurrency.CancelInProgress, exprparser.DefaultStatusCheckNone); err != nil {
+			return nil, fmt.Errorf("invalid cancel-in-progress of the concurrency group '%s': %w", group, err)
+		}
+	}
+	return newConcurrencyHold(rc.getGithubContext(ctx).Repository+"/"+group, rc.concurrencyRun, cancelInProgress), nil
+}
+
+// workflowConcurrency is the concurrency group of a workflow of the plan, it is claimed by the first job of the
+// workflow and released once the plan is done
+type workflowConcurrency struct {
+	once    sync.Once
+	hold    *concurrencyHold
+	release func()
+	err     error
+}
+
+// acquireConcurrency waits for the concurrency group of the workflow of the job, then for the one of the job. The
+// returned context is cancelled when a newer run cancels either, the returned func releases the group of the job.
+func (runner *runnerImpl) acquireConcurrency(ctx context.Context, rc *RunContext) (context.Context, func(), error) {
+	ctx, cancelWorkflow, err := runner.acquireWorkflowConcurrency(ctx, rc)
+	if err != nil {
+		return ctx, nil, err
+	}
+
+	hold, err := rc.evalConcurrency(ctx, rc.Run.Job().Concurrency())
+	if err != nil || hold == nil {
+		return ctx, cancelWorkflow, err
+	}
+	common.Logger(ctx).Debugf("Waiting for concurrency group '%s'", hold.group)
+	runner.progress.legQueued(rc.Run, hold.group)
+	release, err := holdConcurrency(ctx, runner.concurrency, hold)
+	if err != nil {
+		cancelWorkflow()
+		return ctx, nil, err
+	}
+	ctx, cancel := withConcurrencyCancel(ctx, hold)
+	return ctx, func() {
+		cancel()
+		release()
+		cancelWorkflow()
+	}, nil
+}
+
+// acquireWorkflowConcurrency waits for the concurrency group of the workflow of the job, the reusable workflows
+// belong to the run of their caller
+func (runner *runnerImpl) acquireWorkflowConcurrency(ctx context.Context, rc *RunContext) (context.Context, context.CancelFunc, error) {
+	if runner.caller != nil {
+		return ctx, func() {}, nil
+	}
+	runner.workflowConcurrencyMu.Lock()
+	if runner.workflowConcurrency == nil {
+		runner.workflowConcurrency = map[string]*workflowConcurrency{}
+	}
+	wc, ok := runner.workflowConcurrency[rc.Run.Workflow.File]
+	if !ok {
+		wc = &workflowConcurrency{}
+		runner.workflowConcurrency[rc.Run.Workflow.File] = wc
+	}
+	runner.workflowConcurrencyMu.Unlock()
+
+	wc.once.Do(func() {
+		wc.hold, wc.err = rc.evalConcurrency(ctx, rc.Run.Workflow.Concurrency())
+		if wc.err != nil || wc.hold == nil {
+			return
+		}
+		common.Logger(ctx).Debugf("Waiting for concurrency group '%s' of the workflow", wc.hold.group)
+		wc.release, wc.err = holdConcurrency(ctx, runner.concurrency, wc.hold)
+	})
+	if wc.err != nil {
+		return ctx, nil, wc.err
+	}
+	if wc.hold == nil {
+		return ctx, func() {}, nil
+	}
+	ctx, cancel := withConcurrencyCancel(ctx, wc.hold)
+	return ctx, cancel, nil
+}
+
+// releaseWorkflowConcurrency releases the concurrency groups of the workflows of the plan
+func (runner *runnerImpl) releaseWorkflowConcurrency(ctx context.Context) error {
+	runner.workflowConcurrencyMu.Lock()
+	defer runner.workflowConcurrencyMu.Unlock()
+	for file, wc := range runner.workflowConcurrency {
+		if wc.release != nil {
+			wc.release()
+		}
+		delete(runner.workflowConcurrency, file)
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+package runner
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// concurrencyDirPoll is the interval the claims of a concurrency dir are checked at
+	concurrencyDirPoll = 250 * time.Millisecond
+	// concurrencyDirStale is the age of a claim which isn't refreshed anymore, its act process is gone
+	concurrencyDirStale = 10 * time.Second
+)
+
+// concurrencyDir queues the claims on the concurrency groups through a directory shared by the invocations of act.
+// A group is a directory holding a file per claim, named by the time it was queued, and the file 'holder' naming the
+// claim holding the group. A claim is cancelled by creating its .cancel file. The act processes refresh the
+// modification time of the files of their claims, the claims of processes which are gone are dropped.
+type concurrencyDir struct {
+	dir string
+}
+
+func newConcurrencyDir(dir string) *concurrencyDir {
+	return &concurrencyDir{dir: dir}
+}
+
+// groupDir returns the directory of the group, its name is hashed as a group may contain any character
+func (c *concurrencyDir) groupDir(group string) string {
+	sum := sha256.Sum256([]byte(group))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:8]))
+}
+
+// concurrencyClaim is a claim queued in the directory of a group
+type concurrencyClaim struct {
+	name string // <queued time>-<random>, the claims are ordered by their name
+	run  string
+}
+
+// claims returns the claims of the group which are alive in queue order, the stale ones are removed
+func (c *concurrencyDir) claims(dir string) ([]concurrencyClaim, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	claims := make([]concurrencyClaim, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasSuffix(name, ".claim") {
+			continue
+		}
+		name = strings.TrimSuffix(name, ".claim")
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		if time.Since(info.ModTime()) > concurrencyDirStale {
+			removeConcurrencyClaim(dir, name)
+			continue
+		}
+		run, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		claims = append(claims, concurrencyClaim{name: name, run: string(run)})
+	}
+	sort.Slice(claims, func(i, j int) bool {
+		return claims[i].name < claims[j].name
+	})
+	return claims, nil
+}
+
+// holder returns the claim holding the group, empty if the group is free or its holder is gone
+func (c *concurrencyDir) holder(dir string, claims []concurrencyClaim) string {
+	holder, err := os.ReadFile(filepath.Join(dir, "holder"))
+	if err != nil {
+		return ""
+	}
+	for _, claim := range claims {
+		if claim.name == string(holder) {
+			return claim.name
+		}
+	}
+	// the process holding the group is gone
+	_ = os.Remove(filepath.Join(dir, "holder"))
+	return ""
+}
+
+func removeConcurrencyClaim(dir string, name string) {
+	_ = os.Remove(filepath.Join(dir, name+".claim"))
+	_ = os.Remove(filepath.Join(dir, name+".cancel"))
+}
+
+func (c *concurrencyDir) acquire(ctx context.Context, hold *concurrencyHold) (func(), error) {
+	dir := c.groupDir(hold.group)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	randBytes := make([]byte, 4)
+	_, _ = rand.Read(randBytes)
+	name := fmt.Sprintf("%020d-%s", time.Now().UnixNano(), hex.EncodeToString(randBytes))
+	claimFile := filepath.Join(dir, name+".claim")
+	if err := os.WriteFile(claimFile, []byte(hold.run), 0o644); err != nil {
+		return nil, err
+	}
+
+	// the claim is refreshed while it is queued or holds the group, its .cancel file cancels the hold
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(concurrencyDirPoll)
+		defer ticker.Stop()
+		refreshed := time.Now()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+			if _, err := os.Stat(filepath.Join(dir, name+".cancel")); err == nil {
+				hold.cancel()
+			}
+			if time.Since(refreshed) > concurrencyDirStale/4 {
+				refreshed = time.Now()
+				_ = os.Chtimes(claimFile, refreshed, refreshed)
+			}
+		}
+	}()
+	stop := func() {
+		close(done)
+		removeConcurrencyClaim(dir, name)
+	}
+
+	claims, err := c.claims(dir)
+	if err != nil {
+		stop()
+		return nil, err
+	}
+	holder := c.holder(dir, claims)
+	for _, claim := range claims {
+		if claim.run == hold.run || claim.name >= name || (claim.name == holder && !hold.cancelInProgress) {
+			continue
+		}
+		_ = os.WriteFile(filepath.Join(dir, claim.name+".cancel"), nil, 0o644)
+	}
+
+	for {
+		select {
+		case <-hold.cancelled:
+			stop()
+			return nil, errConcurrencyCancelled
+		default:
+		}
+
+		claims, err := c.claims(dir)
+		if err != nil {
+			stop()
+			return nil, err
+		}
+		if c.holder(dir, claims) == "" && firstPendingClaim(dir, claims) == name {
+			file, err := os.OpenFile(filepath.Join(dir, "holder"), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+			if err == nil {
+				_, err = file.WriteString(name)
+				file.Close()
+				if err != nil {
+					stop()
+					return nil, err
+				}
+				return func() {
+					_ = os.Remove(filepath.Join(dir, "holder"))
+					stop()
+				}, nil
+			} else if !errors.Is(err, os.ErrExist) {
+				stop()
+				return nil, err
+			}
+		}
+
+		select {
+		case <-time.After(concurrencyDirPoll):
+		case <-hold.cancelled:
+		case <-ctx.Done():
+			stop()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// firstPendingClaim returns the oldest claim which isn't cancelled
+func firstPendingClaim(dir string, claims []concurrencyClaim) string {
+	for _, claim := range claims {
+		if _, err := os.Stat(filepath.Join(dir, claim.name+".cancel")); err == nil {
+			continue
+		}
+		return claim.name
+	}
+	return ""
+}
@@ -0,0 +1,165 @@
+package runner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func assertAcquired(t *testing.T, acquired <-chan struct{}) {
+	t.Helper()
+	select {
+	case <-acquired:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the group was not released")
+	}
+}
+
+func assertCancelled(t *testing.T, hold *concurrencyHold) {
+	t.Helper()
+	select {
+	case <-hold.cancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the hold was not cancelled")
+	}
+}
+
+func testConcurrencyLocker(t *testing.T, locker concurrencyLocker) {
+	t.Run("run", func(t *testing.T) {
+		release, err := locker.acquire(context.Background(), newConcurrencyHold("deploy", "run", false))
+		assert.NoError(t, err)
+
+		// other groups don't wait
+		releaseOther, err := locker.acquire(context.Background(), newConcurrencyHold("test", "run", false))
+		assert.NoError(t, err)
+		releaseOther()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		_, err = locker.acquire(ctx, newConcurrencyHold("deploy", "run", false))
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		// the jobs of a run queue without cancelling each other
+		acquired := make(chan struct{})
+		go func() {
+			releaseNext, err := locker.acquire(context.Background(), newConcurrencyHold("deploy", "run", true))
+			assert.NoError(t, err)
+			releaseNext()
+			close(acquired)
+		}()
+		release()
+		assertAcquired(t, acquired)
+	})
+
+	t.Run("newer run", func(t *testing.T) {
+		holder := newConcurrencyHold("deploy", "older", false)
+		release, err := locker.acquire(context.Background(), holder)
+		assert.NoError(t, err)
+
+		pending := make(chan error)
+		go func() {
+			_, err := locker.acquire(context.Background(), newConcurrencyHold("deploy", "older", false))
+			pending <- err
+		}()
+		time.Sleep(2 * concurrencyDirPoll)
+
+		acquired := make(chan struct{})
+		go func() {
+			releaseNewer, err := locker.acquire(context.Background(), newConcurrencyHold("deploy", "newer", false))
+			assert.NoError(t, err)
+			releaseNewer()
+			close(acquired)
+		}()
+		select {
+		case err := <-pending:
+			assert.ErrorIs(t, err, errConcurrencyCancelled)
+		case <-time.After(5 * time.Second):
+			t.Fatal("the pending hold of the older run was not cancelled")
+		}
+
+		// the holder is only cancelled with cancel-in-progress
+		select {
+		case <-holder.cancelled:
+			t.Fatal("the hold in progress was cancelled")
+		default:
+		}
+		release()
+		assertAcquired(t, acquired)
+	})
+
+	t.Run("cancel-in-progress", func(t *testing.T) {
+		holder := newConcurrencyHold("deploy", "older", false)
+		release, err := locker.acquire(context.Background(), holder)
+		assert.NoError(t, err)
+
+		acquired := make(chan struct{})
+		go func() {
+			releaseNewer, err := locker.acquire(context.Background(), newConcurrencyHold("deploy", "newer", true))
+			assert.NoError(t, err)
+			releaseNewer()
+			close(acquired)
+		}()
+		assertCancelled(t, holder)
+		release()
+		assertAcquired(t, acquired)
+	})
+}
+
+func TestConcurrencyGroups(t *testing.T) {
+	testConcurrencyLocker(t, newConcurrencyGroups())
+}
+
+func TestConcurrencyDir(t *testing.T) {
+	testConcurrencyLocker(t, newConcurrencyDir(t.TempDir()))
+}
+
+func TestConcurrencyDirStaleHolder(t *testing.T) {
+	locker := newConcurrencyDir(t.TempDir())
+	_, err := locker.acquire(context.Background(), newConcurrencyHold("deploy", "run", false))
+	assert.NoError(t, err)
+
+	// the claims of an act process which is gone
+	dir := locker.groupDir("deploy")
+	claims, err := locker.claims(dir)
+	assert.NoError(t, err)
+	assert.Len(t, claims, 1)
+	stale := time.Now().Add(-2 * concurrencyDirStale)
+	assert.NoError(t, os.Chtimes(filepath.Join(dir, claims[0].name+".claim"), stale, stale))
+
+	claims, err = locker.claims(dir)
+	assert.NoError(t, err)
+	assert.Empty(t, claims)
+	assert.Equal(t, "", locker.holder(dir, claims))
+}
+
+func TestCancelInProgress(t *testing.T) {
+	locker := newConcurrencyGroups()
+	hold := newConcurrencyHold("deploy", "run", true)
+	release, err := holdConcurrency(context.Background(), locker, hold)
+	assert.NoError(t, err)
+	queued := newConcurrencyHold("test", "run", false)
+	releaseQueued, err := holdConcurrency(context.Background(), locker, queued)
+	assert.NoError(t, err)
+
+	ctx, cancel := withConcurrencyCancel(context.Background(), hold)
+	defer cancel()
+	assert.Equal(t, 1, CancelInProgress())
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("the context was not cancelled")
+	}
+	select {
+	case <-queued.cancelled:
+		t.Fatal("the hold without cancel-in-progress was cancelled")
+	default:
+	}
+
+	release()
+	releaseQueued()
+	assert.Equal(t, 0, CancelInProgress())
+}
@@ -0,0 +1,44 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var containerUserPattern = regexp.MustCompile(`^[a-zA-Z0-9_][a-zA-Z0-9_.-]*(:[a-zA-Z0-9_][a-zA-Z0-9_.-]*)?$`)
+
+// ParseContainerUsers parses the [<platform>=]<user>[:<group>] specs of the users of the job containers (e.g.
+// 1001:1001 or ubuntu-latest=runner) into a map of the platforms to the users, "" is the user of all platforms
+func ParseContainerUsers(specs []string) (map[string]string, error) {
+	users := make(map[string]string, len(specs))
+	for _, spec := range specs {
+		platform, user, ok := strings.Cut(spec, "=")
+		if !ok {
+			platform, user = "", spec
+		}
+		if ok && platform == "" {
+			return nil, fmt.Errorf("invalid container user '%s': expected [<platform>=]<user>[:<group>], e.g. ubuntu-latest=1001:1001", spec)
+		}
+		if !containerUserPattern.MatchString(user) {
+			return nil, fmt.Errorf("invalid container user '%s': expected a name or uid, optionally followed by :<group> or :<gid>", spec)
+		}
+		users[strings.ToLower(platform)] = user
+	}
+	return users, nil
+}
+
+// containerUser returns the user the job container runs as, the user of the platform of runs-on takes precedence.
+// The user of a job container of `container:` is the one of all platforms, its options may override it with --user.
+func (rc *RunContext) containerUser(ctx context.Context) string {
+	if rc.jobContainer(ctx) == nil {
+		for _, runnerLabel := range rc.Run.Job().RunsOn() {
+			platformName := rc.ExprEval.Interpolate(ctx, runnerLabel)
+			if user, ok := rc.Config.ContainerUsers[strings.ToLower(platformName)]; ok && platformName != "" {
+				return user
+			}
+		}
+	}
+	return rc.Config.ContainerUsers[""]
+}
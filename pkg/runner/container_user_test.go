@@ -0,0 +1,52 @@
+package runner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nektos/act/pkg/model"
+)
+
+func TestParseContainerUsers(t *testing.T) {
+	users, err := ParseContainerUsers([]string{"1001:1001", "Ubuntu-Latest=runner", "self-hosted=root:wheel"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"": "1001:1001", "ubuntu-latest": "runner", "self-hosted": "root:wheel"}, users)
+
+	_, err = ParseContainerUsers([]string{"=1001"})
+	assert.ErrorContains(t, err, "expected [<platform>=]<user>[:<group>]")
+	_, err = ParseContainerUsers([]string{"ubuntu-latest=1001:"})
+	assert.ErrorContains(t, err, "expected a name or uid")
+	_, err = ParseContainerUsers([]string{"runner; rm -rf /"})
+	assert.ErrorContains(t, err, "expected a name or uid")
+}
+
+func TestRunContextContainerUser(t *testing.T) {
+	users := map[string]string{"": "1001:1001", "ubuntu-22.04": "runner"}
+	tables := []struct {
+		name     string
+		job      string
+		users    map[string]string
+		expected string
+	}{
+		{"platform", "runs-on: ubuntu-22.04", users, "runner"},
+		{"all platforms", "runs-on: ubuntu-latest", users, "1001:1001"},
+		{"job container", "runs-on: ubuntu-22.04\ncontainer: node:16-bullseye", users, "1001:1001"},
+		{"image user", "runs-on: ubuntu-latest", map[string]string{"ubuntu-22.04": "runner"}, ""},
+	}
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			workflow := &model.Workflow{
+				Name: "test",
+				Jobs: map[string]*model.Job{"job": createJob(t, table.job, "")},
+			}
+			rc := &RunContext{
+				Config: &Config{ContainerUsers: table.users},
+				Run:    &model.Run{Workflow: workflow, JobID: "job"},
+			}
+			rc.ExprEval = rc.NewExpressionEvaluator(context.Background())
+			assert.Equal(t, table.expected, rc.containerUser(context.Background()))
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package runner
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/nektos/act/pkg/model"
+)
+
+// ContextOverrides are merged into the contexts of the expressions, to test a workflow against other shapes of
+// the contexts without constructing a full event payload
+type ContextOverrides struct {
+	Github map[string]interface{} `json:"github"` // e.g. {"ref": "refs/tags/v1.0.0", "event": {"pull_request": {"draft": true}}}
+	Runner map[string]interface{} `json:"runner"` // e.g. {"os": "Windows"}
+	Vars   map[string]string      `json:"vars"`   // the configuration variables of the repository
+}
+
+// ReadContextOverrides reads the overrides from a JSON file, the objects are merged into the contexts
+// and the other values replace the ones of the contexts
+func ReadContextOverrides(path string) (*ContextOverrides, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	overrides := new(ContextOverrides)
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(overrides); err != nil {
+		return nil, fmt.Errorf("unable to read the contexts from %s, expected an object with github, runner and vars: %w", path, err)
+	}
+	// report values of the wrong type before the jobs run
+	if err := overrides.applyGithub(&model.GithubContext{}); err != nil {
+		return nil, fmt.Errorf("unable to read the contexts from %s: %w", path, err)
+	}
+	return overrides, nil
+}
+
+// applyGithub merges the overrides into the github context
+func (o *ContextOverrides) applyGithub(ghc *model.GithubContext) error {
+	if o == nil || len(o.Github) == 0 {
+		return nil
+	}
+	content, err := json.Marshal(ghc)
+	if err != nil {
+		return err
+	}
+	values := map[string]interface{}{}
+	if err := json.Unmarshal(content, &values); err != nil {
+		return err
+	}
+	mergeContext(values, o.Github)
+	if content, err = json.Marshal(values); err != nil {
+		return err
+	}
+	merged := &model.GithubContext{}
+	if err := json.Unmarshal(content, merged); err != nil {
+		return fmt.Errorf("invalid github context: %w", err)
+	}
+	*ghc = *merged
+
+	_, hasRefName := o.Github["ref_name"]
+	_, hasRefType := o.Github["ref_type"]
+	if _, ok := o.Github["ref"]; ok && !hasRefName && !hasRefType {
+		setRefTypeAndName(ghc)
+	}
+	return nil
+}
+
+// applyRunner merges the overrides into the runner context, the context may be nil
+func (o *ContextOverrides) applyRunner(runner map[string]interface{}) map[string]interface{} {
+	if o == nil || len(o.Runner) == 0 {
+		return runner
+	}
+	if runner == nil {
+		runner = map[string]interface{}{}
+	}
+	mergeContext(runner, o.Runner)
+	return runner
+}
+
+// vars returns the vars context
+func (o *ContextOverrides) vars() map[string]string {
+	if o == nil || o.Vars == nil {
+		return map[string]string{}
+	}
+	return o.Vars
+}
+
+// mergeContext merges the objects of the overrides into the ones of the context, other values are replaced
+func mergeContext(context map[string]interface{}, overrides map[string]interface{}) {
+	for k, v := range overrides {
+		override, isObject := v.(map[string]interface{})
+		current, hasObject := context[k].(map[string]interface{})
+		if isObject && hasObject {
+			mergeContext(current, override)
+			continue
+		}
+		context[k] = v
+	}
+}
@@ -0,0 +1,66 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nektos/act/pkg/model"
+)
+
+func TestReadContextOverrides(t *testing.T) {
+	dir := t.TempDir()
+	write := func(content string) string {
+		path := filepath.Join(dir, "context.json")
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+
+	overrides, err := ReadContextOverrides(write(`{"github": {"ref": "refs/tags/v1.0.0"}, "vars": {"STAGE": "prod"}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"STAGE": "prod"}, overrides.vars())
+
+	_, err = ReadContextOverrides(write(`{"gihub": {}}`))
+	assert.ErrorContains(t, err, `unknown field "gihub"`)
+
+	_, err = ReadContextOverrides(write(`{"github": {"run_id": 42}}`))
+	assert.ErrorContains(t, err, "invalid github context")
+}
+
+func TestContextOverridesApply(t *testing.T) {
+	overrides := &ContextOverrides{
+		Github: map[string]interface{}{
+			"ref": "refs/tags/v1.0.0",
+			"event": map[string]interface{}{
+				"pull_request": map[string]interface{}{"draft": true},
+			},
+		},
+		Runner: map[string]interface{}{"os": "Windows"},
+	}
+
+	ghc := &model.GithubContext{
+		Ref:        "refs/heads/main",
+		RefName:    "main",
+		RefType:    "branch",
+		Repository: "nektos/act",
+		Event: map[string]interface{}{
+			"pull_request": map[string]interface{}{"number": 1.0, "draft": false},
+		},
+	}
+	assert.NoError(t, overrides.applyGithub(ghc))
+	assert.Equal(t, "refs/tags/v1.0.0", ghc.Ref)
+	assert.Equal(t, "v1.0.0", ghc.RefName)
+	assert.Equal(t, "tag", ghc.RefType)
+	assert.Equal(t, "nektos/act", ghc.Repository)
+	assert.Equal(t, map[string]interface{}{"number": 1.0, "draft": true}, ghc.Event["pull_request"])
+
+	assert.Equal(t, map[string]interface{}{"os": "Windows", "arch": "X64"}, overrides.applyRunner(map[string]interface{}{"os": "Linux", "arch": "X64"}))
+	assert.Equal(t, map[string]interface{}{"os": "Windows"}, overrides.applyRunner(nil))
+
+	var none *ContextOverrides
+	assert.NoError(t, none.applyGithub(ghc))
+	assert.Nil(t, none.applyRunner(nil))
+	assert.Equal(t, map[string]string{}, none.vars())
+}
@@ -0,0 +1,222 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/nektos/act/pkg/common"
+)
+
+// diagnosticsOutputLines is the number of log lines of a job kept for the diagnostics
+const diagnosticsOutputLines = 200
+
+// Failure describes a failed job for the diagnostics
+type Failure struct {
+	Err            error
+	Output         []string // last lines of the job log
+	Image          string   // image of the job container
+	Architecture   string   // architecture of the containers requested with --container-architecture
+	MissingSecrets []string // secrets the job uses which are not set
+}
+
+// contains returns true if the error or the output of the job contains one of the texts, ignoring case
+func (f *Failure) contains(texts ...string) bool {
+	haystack := make([]string, 0, len(f.Output)+1)
+	if f.Err != nil {
+		haystack = append(haystack, f.Err.Error())
+	}
+	haystack = append(haystack, f.Output...)
+	for _, line := range haystack {
+		line = strings.ToLower(line)
+		for _, text := range texts {
+			if strings.Contains(line, strings.ToLower(text)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Diagnostic recognizes a known cause of failed jobs and suggests a fix
+type Diagnostic struct {
+	Name  string
+	Match func(f *Failure) bool
+	Hint  func(f *Failure) string
+}
+
+var (
+	diagnosticsMu sync.Mutex
+	diagnostics   []*Diagnostic
+)
+
+// RegisterDiagnostic adds a diagnostic, which is checked for every failed job
+func RegisterDiagnostic(d *Diagnostic) {
+	diagnosticsMu.Lock()
+	defer diagnosticsMu.Unlock()
+	diagnostics = append(diagnostics, d)
+}
+
+// Diagnose returns the matching diagnostics and their hints for the failure
+func Diagnose(f *Failure) map[string]string {
+	diagnosticsMu.Lock()
+	defer diagnosticsMu.Unlock()
+
+	hints := map[string]string{}
+	for _, d := range diagnostics {
+		if d.Match(f) {
+			hints[d.Name] = d.Hint(f)
+		}
+	}
+	return hints
+}
+
+func init() {
+	RegisterDiagnostic(&Diagnostic{
+		Name: "image architecture mismatch",
+		Match: func(f *Failure) bool {
+			return f.contains("exec format error", "does not match the specified platform", "no matching manifest for")
+		},
+		Hint: func(f *Failure) string {
+			if f.Architecture != "" {
+				return fmt.Sprintf("image %s is not available for %s, pick another image or --container-architecture", f.Image, f.Architecture)
+			}
+			return fmt.Sprintf("image %s was built for another architecture, run with --container-architecture linux/amd64 (needs emulation, e.g. qemu or Rosetta) or pick an image for your architecture", f.Image)
+		},
+	})
+	RegisterDiagnostic(&Diagnostic{
+		Name: "node missing in image",
+		Match: func(f *Failure) bool {
+			return f.contains("node: not found", "node: command not found", `"node": executable file not found`)
+		},
+		Hint: func(f *Failure) string {
+			return fmt.Sprintf("image %s has no node, which JavaScript actions need, use an image with node (e.g. -P ubuntu-latest=catthehacker/ubuntu:act-latest)", f.Image)
+		},
+	})
+	RegisterDiagnostic(&Diagnostic{
+		Name: "rate limited image pull",
+		Match: func(f *Failure) bool {
+			return f.contains("toomanyrequests", "pull rate limit")
+		},
+		Hint: func(f *Failure) string {
+			return "the registry rate limited the pull, log in with docker login or use the local image with --pull=false"
+		},
+	})
+	RegisterDiagnostic(&Diagnostic{
+		Name: "missing secret",
+		Match: func(f *Failure) bool {
+			return len(f.MissingSecrets) > 0
+		},
+		Hint: func(f *Failure) string {
+			return fmt.Sprintf("the job uses secrets which are not set: %s, pass them with -s NAME=value or --secret-file", strings.Join(f.MissingSecrets, ", "))
+		},
+	})
+	RegisterDiagnostic(&Diagnostic{
+		Name: "DNS failure in container",
+		Match: func(f *Failure) bool {
+			return f.contains("could not resolve host", "temporary failure in name resolution", "getaddrinfo eai_again", "getaddrinfo enotfound")
+		},
+		Hint: func(f *Failure) string {
+			return `the container can't resolve host names, check the DNS of the docker daemon (e.g. "dns" in /etc/docker/daemon.json, often broken by VPNs) or pass --container-options "--dns 1.1.1.1"`
+		},
+	})
+}
+
+// newDiagnosticsExecutor prints the hints of the diagnostics matching a failure of the job
+func (rc *RunContext) newDiagnosticsExecutor(executor common.Executor) common.Executor {
+	return func(ctx context.Context) error {
+		recorder := &outputRecorder{}
+		if entry, ok := common.Logger(ctx).(*logrus.Entry); ok {
+			entry.Logger.AddHook(recorder)
+		}
+
+		err := executor(ctx)
+		jobErr := err
+		if jobErr == nil {
+			jobErr = common.JobError(ctx)
+		}
+		if jobErr == nil || common.Dryrun(ctx) {
+			return err
+		}
+
+		hints := Diagnose(&Failure{
+			Err:            jobErr,
+			Output:         recorder.output(),
+			Image:          rc.platformImage(ctx),
+			Architecture:   rc.Config.ContainerArchitecture,
+			MissingSecrets: rc.missingSecrets(),
+		})
+		names := make([]string, 0, len(hints))
+		for name := range hints {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		logger := common.Logger(ctx)
+		for _, name := range names {
+			logger.Warnf("\U0001F4A1  Possible cause: %s", name)
+			logger.Warnf("    %s", hints[name])
+		}
+		return err
+	}
+}
+
+var secretReferencePattern = regexp.MustCompile(`secrets\.([A-Za-z_][A-Za-z0-9_]*)|secrets\[\s*'([^']+)'\s*\]`)
+
+// missingSecrets returns the secrets the job refers to which are not set
+func (rc *RunContext) missingSecrets() []string {
+	// the raw yaml nodes of the job are encoded as well
+	b, err := json.Marshal(rc.Run.Job())
+	if err != nil {
+		return nil
+	}
+
+	// secrets are case insensitive
+	secrets := map[string]bool{"GITHUB_TOKEN": true}
+	for name := range rc.Config.Secrets {
+		secrets[strings.ToUpper(name)] = true
+	}
+
+	missing := make([]string, 0)
+	for _, match := range secretReferencePattern.FindAllStringSubmatch(string(b), -1) {
+		name := strings.ToUpper(match[1] + match[2])
+		if secrets[name] || common.ContainsString(missing, name) {
+			continue
+		}
+		missing = append(missing, name)
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// outputRecorder keeps the last lines of a job log
+type outputRecorder struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (r *outputRecorder) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (r *outputRecorder) Fire(entry *logrus.Entry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lines = append(r.lines, entry.Message)
+	if len(r.lines) > diagnosticsOutputLines {
+		r.lines = r.lines[len(r.lines)-diagnosticsOutputLines:]
+	}
+	return nil
+}
+
+func (r *outputRecorder) output() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string{}, r.lines...)
+}
@@ -0,0 +1,59 @@
+package runner
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+
+	"github.com/nektos/act/pkg/model"
+)
+
+func TestDiagnose(t *testing.T) {
+	tables := []struct {
+		failure *Failure
+		causes  []string
+	}{
+		{&Failure{Err: errors.New("exit with `FAILURE`: 1")}, []string{}},
+		{&Failure{Err: errors.New("standard_init_linux.go:228: exec user process caused: exec format error")}, []string{"image architecture mismatch"}},
+		{&Failure{Output: []string{"/bin/sh: 1: node: not found"}}, []string{"node missing in image"}},
+		{&Failure{Err: errors.New("Error response from daemon: toomanyrequests: You have reached your pull rate limit.")}, []string{"rate limited image pull"}},
+		{&Failure{MissingSecrets: []string{"NPM_TOKEN"}}, []string{"missing secret"}},
+		{&Failure{Output: []string{"curl: (6) Could not resolve host: github.com"}, MissingSecrets: []string{"NPM_TOKEN"}}, []string{"DNS failure in container", "missing secret"}},
+	}
+
+	for _, table := range tables {
+		hints := Diagnose(table.failure)
+		causes := make([]string, 0, len(hints))
+		for cause, hint := range hints {
+			assert.NotEmpty(t, hint)
+			causes = append(causes, cause)
+		}
+		assert.ElementsMatch(t, table.causes, causes)
+	}
+}
+
+func TestMissingSecrets(t *testing.T) {
+	var workflow model.Workflow
+	assert.NoError(t, yaml.Unmarshal([]byte(`
+jobs:
+  publish:
+    runs-on: ubuntu-latest
+    steps:
+      - run: npm publish
+        env:
+          NODE_AUTH_TOKEN: ${{ secrets.npm_token }}
+          GITHUB_TOKEN: ${{ secrets.GITHUB_TOKEN }}
+      - uses: some/action@v1
+        with:
+          key: ${{ secrets['DEPLOY_KEY'] }}
+          password: ${{ secrets.password }}
+`), &workflow))
+
+	rc := &RunContext{
+		Config: &Config{Secrets: map[string]string{"Password": "secret"}},
+		Run:    &model.Run{JobID: "publish", Workflow: &workflow},
+	}
+	assert.Equal(t, []string{"DEPLOY_KEY", "NPM_TOKEN"}, rc.missingSecrets())
+}
@@ -0,0 +1,141 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/model"
+)
+
+// Operating systems of the runners a job mapped to a Linux image with -P may target
+const (
+	emulatedWindows = "Windows"
+	emulatedMacOS   = "macOS"
+)
+
+// emulatedTools are the commands of the operating systems which are not available in a Linux image
+var emulatedTools = map[string]*regexp.Regexp{
+	emulatedWindows: regexp.MustCompile(`(?i)(^|[\s;&|(])(choco|msbuild|vswhere|vcvarsall\.bat|[\w-]+\.exe)(\s|$)`),
+	emulatedMacOS:   regexp.MustCompile(`(^|[\s;&|(])(brew|xcodebuild|xcrun|xcode-select|codesign|launchctl|hdiutil|sw_vers)(\s|$)`),
+}
+
+var runnerOSPattern = regexp.MustCompile(`(?i)runner\.os\s*[!=]=\s*'(windows|macos)'`)
+
+// emulatedOS returns the operating system of the runner the job targets if it runs in a Linux container in its
+// place (e.g. with -P windows-latest=node:16-bullseye), empty if the job targets Linux or runs on the host
+func (rc *RunContext) emulatedOS(ctx context.Context) string {
+	job := rc.Run.Job()
+	if job == nil || rc.jobContainer(ctx) != nil {
+		return ""
+	}
+	image := rc.platformImage(ctx)
+	if image == "" || strings.EqualFold(image, "-self-hosted") {
+		return ""
+	}
+	for _, runnerLabel := range job.RunsOn() {
+		label := strings.ToLower(rc.ExprEval.Interpolate(ctx, runnerLabel))
+		switch {
+		case strings.HasPrefix(label, "windows"):
+			return emulatedWindows
+		case strings.HasPrefix(label, "macos"):
+			return emulatedMacOS
+		}
+	}
+	return ""
+}
+
+// emulatedShell returns the shell running a step of a job emulating the operating system, pwsh is the default
+// shell of the Windows runners and replaces Windows PowerShell. Without pwsh in the image the steps default to bash.
+func emulatedShell(targetOS string, shell string, hasPwsh bool) string {
+	if targetOS != emulatedWindows {
+		return shell
+	}
+	switch {
+	case shell == "" && !hasPwsh:
+		return "bash"
+	case shell == "" || shell == "powershell":
+		return "pwsh"
+	}
+	return shell
+}
+
+// probeEmulatedShell checks if the job container emulating Windows has pwsh, the run steps default to bash without it
+func (rc *RunContext) probeEmulatedShell() common.Executor {
+	return func(ctx context.Context) error {
+		if common.Dryrun(ctx) || rc.emulatedOS(ctx) != emulatedWindows {
+			return nil
+		}
+		probe := rc.JobContainer.Exec([]string{"sh", "-c", "command -v pwsh >/dev/null 2>&1"}, map[string]string{}, "", "")
+		if err := probe(ctx); err != nil {
+			rc.noPwsh = true
+			common.Logger(ctx).Warnf("\U0001F6A7  pwsh is not available in the image %s, run steps without a shell default to bash", rc.platformImage(ctx))
+		}
+		return nil
+	}
+}
+
+// emulatedPath translates the path separators of a Windows path, e.g. of a working-directory
+func emulatedPath(targetOS string, path string) string {
+	if targetOS == emulatedWindows {
+		return strings.ReplaceAll(path, `\`, "/")
+	}
+	return path
+}
+
+// emulatedOSWarnings lists the constructs of the job which can't be emulated in a Linux container
+func emulatedOSWarnings(targetOS string, job *model.Job) []string {
+	warnings := make([]string, 0)
+	if job.If.Value != "" && runnerOSPattern.MatchString(job.If.Value) {
+		warnings = append(warnings, fmt.Sprintf("the job condition '%s' checks runner.os, which is Linux", job.If.Value))
+	}
+	for i, step := range job.Steps {
+		name := step.String()
+		if name == "" {
+			name = fmt.Sprintf("#%d", i+1)
+		}
+		if runnerOSPattern.MatchString(step.If.Value) {
+			warnings = append(warnings, fmt.Sprintf("step '%s': the condition '%s' checks runner.os, which is Linux", name, step.If.Value))
+		}
+		if step.Run == "" {
+			continue
+		}
+		shell := step.Shell
+		if shell == "" {
+			shell = job.Defaults.Run.Shell
+		}
+		switch {
+		case targetOS == emulatedWindows && shell == "cmd":
+			warnings = append(warnings, fmt.Sprintf("step '%s': 'shell: cmd' is not available, the step fails", name))
+		case targetOS == emulatedWindows && shell == "powershell":
+			warnings = append(warnings, fmt.Sprintf("step '%s': Windows PowerShell is replaced by pwsh", name))
+		}
+		if match := emulatedTools[targetOS].FindStringSubmatch(step.Run); match != nil {
+			warnings = append(warnings, fmt.Sprintf("step '%s': '%s' is not available on Linux", name, match[2]))
+		}
+	}
+	return warnings
+}
+
+// warnEmulatedOS warns that the job targeting Windows or macOS runs in a Linux container, with the constructs
+// which can't be emulated
+func (rc *RunContext) warnEmulatedOS() common.Executor {
+	return func(ctx context.Context) error {
+		targetOS := rc.emulatedOS(ctx)
+		if targetOS == "" {
+			return nil
+		}
+		logger := common.Logger(ctx)
+		if targetOS == emulatedWindows {
+			logger.Warnf("\U0001F6A7  The job targets %s but runs in the Linux image %s: run steps default to pwsh (bash if the image has no pwsh) and the backslashes of working-directory are translated", targetOS, rc.platformImage(ctx))
+		} else {
+			logger.Warnf("\U0001F6A7  The job targets %s but runs in the Linux image %s", targetOS, rc.platformImage(ctx))
+		}
+		for _, warning := range emulatedOSWarnings(targetOS, rc.Run.Job()) {
+			logger.Warnf("\U0001F6A7  Not emulated: %s", warning)
+		}
+		return nil
+	}
+}
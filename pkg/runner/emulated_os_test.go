@@ -0,0 +1,146 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nektos/act/pkg/model"
+)
+
+func TestEmulatedOS(t *testing.T) {
+	workflow, err := model.ReadWorkflow(strings.NewReader(`
+on: push
+jobs:
+  windows:
+    runs-on: windows-latest
+    steps:
+    - run: echo
+  macos:
+    runs-on: [macos-12]
+    steps:
+    - run: echo
+  linux:
+    runs-on: ubuntu-latest
+    steps:
+    - run: echo
+  container:
+    runs-on: windows-latest
+    container: node:16-bullseye
+    steps:
+    - run: echo
+  host:
+    runs-on: self-hosted
+    steps:
+    - run: echo
+`))
+	assert.NoError(t, err)
+
+	config := &Config{Platforms: map[string]string{
+		"windows-latest": "node:16-bullseye",
+		"macos-12":       "node:16-bullseye",
+		"ubuntu-latest":  "node:16-bullseye",
+		"self-hosted":    "-self-hosted",
+	}}
+	for jobID, want := range map[string]string{
+		"windows":   emulatedWindows,
+		"macos":     emulatedMacOS,
+		"linux":     "",
+		"container": "",
+		"host":      "",
+	} {
+		rc := &RunContext{
+			Config:   config,
+			Run:      &model.Run{Workflow: workflow, JobID: jobID},
+			ExprEval: &expressionEvaluator{},
+		}
+		assert.Equal(t, want, rc.emulatedOS(context.Background()), jobID)
+	}
+}
+
+func TestEmulatedShellAndPath(t *testing.T) {
+	assert.Equal(t, "pwsh", emulatedShell(emulatedWindows, "", true))
+	assert.Equal(t, "pwsh", emulatedShell(emulatedWindows, "powershell", true))
+	assert.Equal(t, "bash", emulatedShell(emulatedWindows, "bash", true))
+	assert.Equal(t, "", emulatedShell(emulatedMacOS, "", true))
+	assert.Equal(t, "", emulatedShell("", "", true))
+
+	// the images without pwsh run the steps without a shell with bash, an explicit shell is kept
+	assert.Equal(t, "bash", emulatedShell(emulatedWindows, "", false))
+	assert.Equal(t, "pwsh", emulatedShell(emulatedWindows, "powershell", false))
+	assert.Equal(t, "", emulatedShell(emulatedMacOS, "", false))
+
+	assert.Equal(t, "src/app", emulatedPath(emulatedWindows, `src\app`))
+	assert.Equal(t, `src\app`, emulatedPath(emulatedMacOS, `src\app`))
+}
+
+func TestProbeEmulatedShell(t *testing.T) {
+	workflow, err := model.ReadWorkflow(strings.NewReader(`
+on: push
+jobs:
+  windows:
+    runs-on: windows-latest
+    steps:
+    - run: echo
+`))
+	assert.NoError(t, err)
+
+	for _, hasPwsh := range []bool{true, false} {
+		cm := &containerMock{}
+		cm.On("Exec", []string{"sh", "-c", "command -v pwsh >/dev/null 2>&1"}, map[string]string{}, "", "").Return(func(ctx context.Context) error {
+			if hasPwsh {
+				return nil
+			}
+			return errors.New("exit code 1")
+		})
+		rc := &RunContext{
+			Config:       &Config{Platforms: map[string]string{"windows-latest": "node:16-bullseye"}},
+			Run:          &model.Run{Workflow: workflow, JobID: "windows"},
+			ExprEval:     &expressionEvaluator{},
+			JobContainer: cm,
+		}
+		assert.NoError(t, rc.probeEmulatedShell()(context.Background()))
+		assert.Equal(t, !hasPwsh, rc.noPwsh)
+		cm.AssertExpectations(t)
+	}
+}
+
+func TestEmulatedOSWarnings(t *testing.T) {
+	workflow, err := model.ReadWorkflow(strings.NewReader(`
+on: push
+jobs:
+  build:
+    runs-on: windows-latest
+    if: runner.os == 'Windows'
+    steps:
+    - name: install
+      run: choco install ninja
+    - name: legacy
+      shell: cmd
+      run: echo %PATH%
+    - name: ps
+      shell: powershell
+      run: Write-Output hello
+    - name: only on windows
+      if: runner.os == 'Windows'
+      uses: actions/checkout@v3
+    - name: test
+      run: go test ./...
+`))
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"the job condition 'runner.os == 'Windows'' checks runner.os, which is Linux",
+		"step 'install': 'choco' is not available on Linux",
+		"step 'legacy': 'shell: cmd' is not available, the step fails",
+		"step 'ps': Windows PowerShell is replaced by pwsh",
+		"step 'only on windows': the condition 'runner.os == 'Windows'' checks runner.os, which is Linux",
+	}, emulatedOSWarnings(emulatedWindows, workflow.Jobs["build"]))
+
+	assert.Equal(t, []string{
+		"step 'install': 'xcodebuild' is not available on Linux",
+	}, emulatedOSWarnings(emulatedMacOS, &model.Job{Steps: []*model.Step{{Name: "install", Run: "xcodebuild -scheme App build"}}}))
+}
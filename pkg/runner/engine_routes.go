@@ -0,0 +1,116 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/container"
+)
+
+// EngineRoute is an engine the jobs whose runs-on has all the labels of the route run on, see --engine
+type EngineRoute struct {
+	Labels     []string          // glob patterns of the labels, lower case
+	Engine     string            // the engine as given, logged with the entries of the jobs
+	Backend    container.Backend // container engine of the jobs
+	DockerHost string            // docker host of the engine, the one of the backend if empty
+	VM         string            // VM providing the docker daemon, colima[:<profile>] or lima[:<instance>]
+	Host       bool              // the jobs run on the host without a container engine
+}
+
+// ParseEngineRoutes parses the routes of <label>[,<label>...]=<engine>, the engine is docker, podman, host, a docker
+// host or a VM
+func ParseEngineRoutes(specs []string) ([]*EngineRoute, error) {
+	routes := make([]*EngineRoute, 0, len(specs))
+	for _, spec := range specs {
+		labels, engine, ok := strings.Cut(spec, "=")
+		if !ok || labels == "" || engine == "" {
+			return nil, fmt.Errorf("invalid engine '%s': expected <label>[,<label>...]=<engine>, e.g. self-hosted,gpu=ssh://gpu-host", spec)
+		}
+		route := &EngineRoute{Engine: engine, Backend: container.BackendDocker}
+		for _, label := range strings.Split(labels, ",") {
+			label = strings.ToLower(strings.TrimSpace(label))
+			if _, err := path.Match(label, ""); err != nil || label == "" {
+				return nil, fmt.Errorf("invalid engine '%s': invalid label '%s'", spec, label)
+			}
+			route.Labels = append(route.Labels, label)
+		}
+		kind, _, _ := strings.Cut(engine, ":")
+		switch {
+		case engine == "host":
+			route.Host = true
+		case strings.Contains(engine, "://"):
+			route.DockerHost = engine
+		case kind == "colima" || kind == "lima":
+			route.VM = engine
+		default:
+			backend, err := container.ParseBackend(engine)
+			if err != nil {
+				return nil, fmt.Errorf("invalid engine '%s': expected docker, podman, host, a docker host like ssh://user@host or a VM colima[:<profile>] or lima[:<instance>]", spec)
+			}
+			route.Backend = backend
+		}
+		routes = append(routes, route)
+	}
+	return routes, nil
+}
+
+// matches reports whether every label of the route matches a label of runs-on
+func (route *EngineRoute) matches(runsOn []string) bool {
+	for _, pattern := range route.Labels {
+		matched := false
+		for _, label := range runsOn {
+			if ok, _ := path.Match(pattern, label); ok {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// socket returns the API socket of the engine on the host mounted in the job containers
+func (route *EngineRoute) socket() string {
+	if route.DockerHost != "" {
+		// the daemon listens on its default socket on the remote host or in the VM
+		return "/var/run/docker.sock"
+	}
+	return route.Backend.Socket()
+}
+
+// engineRoute returns the first route of Config.EngineRoutes matching the labels of runs-on, nil if the job runs on
+// the engine of the run
+func (rc *RunContext) engineRoute(ctx context.Context) *EngineRoute {
+	var runsOn []string
+	if group := rc.runsOnGroupLabel(ctx); group != "" {
+		runsOn = append(runsOn, group)
+	}
+	for _, label := range rc.Run.Job().RunsOn() {
+		runsOn = append(runsOn, strings.ToLower(rc.ExprEval.Interpolate(ctx, label)))
+	}
+	for _, route := range rc.Config.EngineRoutes {
+		if route.matches(runsOn) {
+			return route
+		}
+	}
+	return nil
+}
+
+// withEngine returns the context the job runs in on its engine, the entries of its logs tell the engine it runs on
+func (rc *RunContext) withEngine(ctx context.Context) context.Context {
+	rc.engine = rc.engineRoute(ctx)
+	if rc.engine == nil {
+		return ctx
+	}
+	ctx = common.WithLogger(ctx, common.Logger(ctx).WithField("engine", rc.engine.Engine))
+	if rc.engine.Host {
+		return ctx
+	}
+	// an empty docker host falls back to the one of the backend rather than the one of the run
+	return container.WithDockerHost(container.WithBackend(ctx, rc.engine.Backend), rc.engine.DockerHost)
+}
@@ -0,0 +1,107 @@
+package runner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/container"
+	"github.com/nektos/act/pkg/model"
+)
+
+func TestParseEngineRoutes(t *testing.T) {
+	routes, err := ParseEngineRoutes([]string{"ubuntu-*=docker", "Self-Hosted, GPU=ssh://user@gpu-host", "macos-*=colima:macos", "windows-*=host", "fedora-*=podman"})
+	assert.NoError(t, err)
+	assert.Equal(t, []*EngineRoute{
+		{Labels: []string{"ubuntu-*"}, Engine: "docker", Backend: container.BackendDocker},
+		{Labels: []string{"self-hosted", "gpu"}, Engine: "ssh://user@gpu-host", Backend: container.BackendDocker, DockerHost: "ssh://user@gpu-host"},
+		{Labels: []string{"macos-*"}, Engine: "colima:macos", Backend: container.BackendDocker, VM: "colima:macos"},
+		{Labels: []string{"windows-*"}, Engine: "host", Backend: container.BackendDocker, Host: true},
+		{Labels: []string{"fedora-*"}, Engine: "podman", Backend: container.BackendPodman},
+	}, routes)
+
+	_, err = ParseEngineRoutes([]string{"ubuntu-latest"})
+	assert.ErrorContains(t, err, "expected <label>[,<label>...]=<engine>")
+	_, err = ParseEngineRoutes([]string{"ubuntu-[=docker"})
+	assert.ErrorContains(t, err, "invalid label 'ubuntu-['")
+	_, err = ParseEngineRoutes([]string{"ubuntu-latest,=docker"})
+	assert.ErrorContains(t, err, "invalid label ''")
+	_, err = ParseEngineRoutes([]string{"ubuntu-latest=kubernetes"})
+	assert.ErrorContains(t, err, "expected docker, podman, host")
+}
+
+func TestRunContextEngineRoute(t *testing.T) {
+	routes, err := ParseEngineRoutes([]string{"self-hosted,gpu=tcp://gpu-host:2376", "ubuntu-*=podman", "macos-*=host", "group:build-*=host"})
+	assert.NoError(t, err)
+	tables := []struct {
+		name     string
+		runsOn   string
+		expected *EngineRoute
+	}{
+		{"all labels", "[self-hosted, linux, gpu]", routes[0]},
+		{"missing label", "[self-hosted, linux]", nil},
+		{"glob", "ubuntu-22.04", routes[1]},
+		{"case insensitive", "MacOS-latest", routes[2]},
+		{"no route", "windows-latest", nil},
+		{"runner group", "{group: Build-Runners, labels: [linux]}", routes[3]},
+		{"labels of a runner group", "{group: deploy, labels: [ubuntu-latest]}", routes[1]},
+	}
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			workflow := &model.Workflow{
+				Name: "test",
+				Jobs: map[string]*model.Job{"job": createJob(t, "runs-on: "+table.runsOn, "")},
+			}
+			rc := &RunContext{
+				Config: &Config{EngineRoutes: routes},
+				Run:    &model.Run{Workflow: workflow, JobID: "job"},
+			}
+			rc.ExprEval = rc.NewExpressionEvaluator(context.Background())
+			assert.Equal(t, table.expected, rc.engineRoute(context.Background()))
+		})
+	}
+}
+
+func TestRunContextWithEngine(t *testing.T) {
+	routes, err := ParseEngineRoutes([]string{"self-hosted=tcp://gpu-host:2376", "ubuntu-*=podman", "macos-*=host"})
+	assert.NoError(t, err)
+	newRunContext := func(runsOn string) *RunContext {
+		workflow := &model.Workflow{
+			Name: "test",
+			Jobs: map[string]*model.Job{"job": createJob(t, "runs-on: "+runsOn, "")},
+		}
+		rc := &RunContext{
+			Config: &Config{EngineRoutes: routes, Platforms: map[string]string{"macos-latest": "node:16-buster-slim"}},
+			Run:    &model.Run{Workflow: workflow, JobID: "job"},
+		}
+		rc.ExprEval = rc.NewExpressionEvaluator(context.Background())
+		return rc
+	}
+	ctx := container.WithDockerHost(context.Background(), "tcp://docker-host:2376")
+
+	rc := newRunContext("self-hosted")
+	routed := rc.withEngine(ctx)
+	assert.Equal(t, routes[0], rc.engine)
+	assert.Equal(t, "tcp://gpu-host:2376", container.DockerHost(routed))
+	assert.Equal(t, "tcp://gpu-host:2376", common.Logger(routed).(*logrus.Entry).Data["engine"])
+	assert.Equal(t, "/var/run/docker.sock", rc.engine.socket())
+
+	// the docker host of the run isn't the one of another backend
+	rc = newRunContext("ubuntu-latest")
+	routed = rc.withEngine(ctx)
+	assert.Equal(t, container.BackendPodman, container.BackendFrom(routed))
+	assert.NotEqual(t, "tcp://docker-host:2376", container.DockerHost(routed))
+
+	rc = newRunContext("macos-latest")
+	assert.Equal(t, "-self-hosted", rc.platformImage(ctx))
+	routed = rc.withEngine(ctx)
+	assert.Equal(t, "tcp://docker-host:2376", container.DockerHost(routed))
+
+	rc = newRunContext("windows-latest")
+	routed = rc.withEngine(ctx)
+	assert.Nil(t, rc.engine)
+	assert.Equal(t, ctx, routed)
+}
@@ -0,0 +1,63 @@
+package runner
+
+import (
+	"archive/tar"
+	"context"
+	"io"
+	"path"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/container"
+)
+
+// eventPayloadFile is the event payload GITHUB_EVENT_PATH points to, in the act path of the job container
+const eventPayloadFile = "workflow/event.json"
+
+// eventPayloadEntry is the event payload of the job, read-only for the steps
+func (rc *RunContext) eventPayloadEntry() *container.FileEntry {
+	return &container.FileEntry{
+		Name: eventPayloadFile,
+		Mode: 0444,
+		Body: rc.EventJSON,
+	}
+}
+
+// checkEventPayload warns when the step modified or removed the event payload and restores it for the next steps.
+// The permissions don't prevent a step running as root from writing the payload, the next steps and actions would
+// read a payload the event never had.
+func (rc *RunContext) checkEventPayload(ctx context.Context) error {
+	if common.Dryrun(ctx) {
+		return nil
+	}
+	actPath := rc.JobContainer.GetActPath()
+	payload, err := rc.readContainerFile(ctx, path.Join(actPath, eventPayloadFile))
+	if err == nil && payload == rc.EventJSON {
+		return nil
+	}
+
+	logger := common.Logger(ctx)
+	if err != nil {
+		logger.Warnf("  \U000026A0  The step removed the event payload of GITHUB_EVENT_PATH, it is read-only and restored for the next steps")
+	} else {
+		logger.Warnf("  \U000026A0  The step modified the event payload of GITHUB_EVENT_PATH, it is read-only and restored for the next steps")
+	}
+	return rc.JobContainer.Copy(actPath+"/", rc.eventPayloadEntry())(ctx)
+}
+
+// readContainerFile returns the content of a file of the job container
+func (rc *RunContext) readContainerFile(ctx context.Context, file string) (string, error) {
+	archive, err := rc.JobContainer.GetContainerArchive(ctx, file)
+	if err != nil {
+		return "", err
+	}
+	defer archive.Close()
+
+	reader := tar.NewReader(archive)
+	if _, err := reader.Next(); err == io.EOF {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	content, err := io.ReadAll(reader)
+	return string(content), err
+}
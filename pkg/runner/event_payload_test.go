@@ -0,0 +1,53 @@
+package runner
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nektos/act/pkg/container"
+)
+
+func eventPayloadArchive(t *testing.T, payload string) io.ReadCloser {
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	assert.NoError(t, tw.WriteHeader(&tar.Header{Name: "event.json", Mode: 0444, Size: int64(len(payload))}))
+	_, err := tw.Write([]byte(payload))
+	assert.NoError(t, err)
+	assert.NoError(t, tw.Close())
+	return io.NopCloser(buf)
+}
+
+func TestCheckEventPayload(t *testing.T) {
+	ctx := context.Background()
+	payload := `{"ref": "refs/heads/main"}`
+	cm := &containerMock{}
+	rc := &RunContext{EventJSON: payload, JobContainer: cm}
+
+	cm.On("GetContainerArchive", ctx, "/var/run/act/workflow/event.json").Return(eventPayloadArchive(t, payload), nil).Once()
+	assert.NoError(t, rc.checkEventPayload(ctx))
+	cm.AssertExpectations(t)
+
+	// a modified or removed payload is restored
+	restored := 0
+	cm.On("Copy", "/var/run/act/", []*container.FileEntry{{
+		Name: "workflow/event.json",
+		Mode: 0444,
+		Body: payload,
+	}}).Return(func(ctx context.Context) error {
+		restored++
+		return nil
+	})
+	cm.On("GetContainerArchive", ctx, "/var/run/act/workflow/event.json").Return(eventPayloadArchive(t, `{"ref": "refs/heads/hacked"}`), nil).Once()
+	assert.NoError(t, rc.checkEventPayload(ctx))
+	cm.On("GetContainerArchive", ctx, "/var/run/act/workflow/event.json").Return(io.NopCloser(&bytes.Buffer{}), errors.New("no such file")).Once()
+	assert.NoError(t, rc.checkEventPayload(ctx))
+
+	assert.Equal(t, 2, restored)
+	cm.AssertExpectations(t)
+}
@@ -0,0 +1,100 @@
+package runner
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/nektos/act/pkg/common"
+)
+
+// exportWorkspace copies the workspace of the job container into Config.ExportWorkspace once the steps of the job
+// are done, in a directory per job and matrix leg replacing the one of a previous run. A failed export only warns.
+func (rc *RunContext) exportWorkspace(ctx context.Context) {
+	if rc.Config.ExportWorkspace == "" || rc.JobContainer == nil || common.Dryrun(ctx) {
+		return
+	}
+	logger := common.Logger(ctx)
+	dest := filepath.Join(rc.Config.ExportWorkspace, exportWorkspaceDir(rc.String()))
+	archive, err := rc.JobContainer.GetContainerArchive(ctx, rc.JobContainer.ToContainerPath(rc.Config.Workdir))
+	if err != nil {
+		logger.Warnf("Unable to export the workspace of the job: %v", err)
+		return
+	}
+	defer archive.Close()
+	if err := os.RemoveAll(dest); err != nil {
+		logger.Warnf("Unable to export the workspace of the job: %v", err)
+		return
+	}
+	if err := extractWorkspace(archive, dest); err != nil {
+		logger.Warnf("Unable to export the workspace of the job to %s: %v", dest, err)
+		return
+	}
+	logger.Infof("\U0001F4E6  Exported the workspace to %s", dest)
+}
+
+// exportWorkspaceDir returns the directory of the exported workspace of a job from its name, e.g. CI-build-1
+func exportWorkspaceDir(job string) string {
+	return strings.Trim(strings.NewReplacer("/", "-", "\\", "-", " ", "-", ":", "-").Replace(job), "-.")
+}
+
+// extractWorkspace extracts the tar archive of a directory into dest, without the directory itself. The directories,
+// regular files and symbolic links are extracted.
+func extractWorkspace(r io.Reader, dest string) error {
+	if err := os.MkdirAll(dest, 0o755); err != nil {
+		return err
+	}
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		// the paths are cleaned from the root, so the entries can't be extracted out of dest
+		parts := strings.SplitN(strings.Trim(path.Clean("/"+header.Name), "/"), "/", 2)
+		if len(parts) < 2 {
+			continue
+		}
+		target := filepath.Join(dest, filepath.FromSlash(parts[1]))
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, os.FileMode(header.Mode).Perm()|0o700); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := extractWorkspaceFile(tr, target, os.FileMode(header.Mode).Perm()); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return err
+			}
+			if err := os.Symlink(header.Linkname, target); err != nil {
+				return fmt.Errorf("unable to create the link %s: %w", parts[1], err)
+			}
+		}
+	}
+}
+
+func extractWorkspaceFile(r io.Reader, target string, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
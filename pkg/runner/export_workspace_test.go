@@ -0,0 +1,53 @@
+package runner
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportWorkspaceDir(t *testing.T) {
+	assert.Equal(t, "CI-build-1", exportWorkspaceDir("CI/build-1"))
+	assert.Equal(t, "My-workflow-test", exportWorkspaceDir("My workflow/test"))
+	assert.Equal(t, "caller-job", exportWorkspaceDir("../caller/job"))
+}
+
+func TestExtractWorkspace(t *testing.T) {
+	archive := &bytes.Buffer{}
+	tw := tar.NewWriter(archive)
+	for _, entry := range []struct {
+		header  *tar.Header
+		content string
+	}{
+		{&tar.Header{Name: "workspace/", Typeflag: tar.TypeDir, Mode: 0o755}, ""},
+		{&tar.Header{Name: "workspace/dist/", Typeflag: tar.TypeDir, Mode: 0o755}, ""},
+		{&tar.Header{Name: "workspace/dist/app", Typeflag: tar.TypeReg, Mode: 0o755}, "binary"},
+		{&tar.Header{Name: "workspace/config.json", Typeflag: tar.TypeReg, Mode: 0o644}, "{}"},
+		{&tar.Header{Name: "workspace/../../escaped", Typeflag: tar.TypeReg, Mode: 0o644}, "escaped"},
+		{&tar.Header{Name: "workspace/latest", Typeflag: tar.TypeSymlink, Linkname: "dist/app"}, ""},
+	} {
+		entry.header.Size = int64(len(entry.content))
+		assert.NoError(t, tw.WriteHeader(entry.header))
+		_, err := tw.Write([]byte(entry.content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, tw.Close())
+
+	dest := filepath.Join(t.TempDir(), "CI-build")
+	assert.NoError(t, extractWorkspace(archive, dest))
+
+	content, err := os.ReadFile(filepath.Join(dest, "dist", "app"))
+	assert.NoError(t, err)
+	assert.Equal(t, "binary", string(content))
+	content, err = os.ReadFile(filepath.Join(dest, "config.json"))
+	assert.NoError(t, err)
+	assert.Equal(t, "{}", string(content))
+	link, err := os.Readlink(filepath.Join(dest, "latest"))
+	assert.NoError(t, err)
+	assert.Equal(t, "dist/app", link)
+	assert.NoFileExists(t, filepath.Join(dest, "..", "escaped"))
+}
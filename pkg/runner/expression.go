@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/nektos/act/pkg/common"
@@ -27,14 +28,7 @@ func (rc *RunContext) NewExpressionEvaluator(ctx context.Context) ExpressionEval
 func (rc *RunContext) NewExpressionEvaluatorWithEnv(ctx context.Context, env map[string]string) ExpressionEvaluator {
 	// todo: cleanup EvaluationEnvironment creation
 	using := make(map[string]exprparser.Needs)
-	strategy := make(map[string]interface{})
 	if rc.Run != nil {
-		job := rc.Run.Job()
-		if job != nil && job.Strategy != nil {
-			strategy["fail-fast"] = job.Strategy.FailFast
-			strategy["max-parallel"] = job.Strategy.MaxParallel
-		}
-
 		jobs := rc.Run.Workflow.Jobs
 		jobNeeds := rc.Run.Job().Needs()
 
@@ -57,33 +51,66 @@ func (rc *RunContext) NewExpressionEvaluatorWithEnv(ctx context.Context, env map
 		// but required to interpolate/evaluate the step outputs on the job
 		Steps:    rc.getStepsContext(),
 		Secrets:  getWorkflowSecrets(ctx, rc),
-		Strategy: strategy,
+		Strategy: rc.getStrategyContext(),
 		Matrix:   rc.Matrix,
 		Needs:    using,
 		Inputs:   inputs,
+		Runner:   rc.getRunnerContext(ctx),
+		Vars:     rc.Config.ContextOverrides.vars(),
 	}
-	if rc.JobContainer != nil {
-		ee.Runner = rc.JobContainer.GetRunnerContext(ctx)
-	}
+	trace := rc.newExpressionTrace()
 	return expressionEvaluator{
 		interpreter: exprparser.NewInterpeter(ee, exprparser.Config{
 			Run:        rc.Run,
 			WorkingDir: rc.Config.Workdir,
 			Context:    "job",
+			Trace:      trace.traceFunc(),
+			Workspace:  rc.hashFilesWorkspace(ctx),
 		}),
+		trace: trace,
+	}
+}
+
+// NewJobExpressionEvaluator creates an evaluator for the contexts of a job without running the job,
+// values which depend on the job container (e.g. the runner context) are not available
+func NewJobExpressionEvaluator(ctx context.Context, config *Config, run *model.Run, matrix map[string]interface{}) (ExpressionEvaluator, error) {
+	runner := &runnerImpl{
+		config: config,
 	}
+	if _, err := runner.configure(); err != nil {
+		return nil, err
+	}
+	return runner.newRunContext(ctx, run, matrix).ExprEval, nil
+}
+
+// runName evaluates the run-name of the workflow, the title of the run, with the github and inputs contexts
+// it is limited to. Without a run-name, or if it is empty, the title is the name of the workflow.
+func (rc *RunContext) runName(ctx context.Context) string {
+	if rc.Run.Workflow.RunName == "" {
+		return rc.Run.Workflow.Name
+	}
+
+	ghc := rc.getGithubContext(ctx)
+	ee := &exprparser.EvaluationEnvironment{
+		Github: ghc,
+		Inputs: getEvaluatorInputs(ctx, rc, nil, ghc),
+	}
+	name := expressionEvaluator{
+		interpreter: exprparser.NewInterpeter(ee, exprparser.Config{
+			Run:        rc.Run,
+			WorkingDir: rc.Config.Workdir,
+			Context:    "workflow",
+		}),
+	}.Interpolate(ctx, rc.Run.Workflow.RunName)
+	if strings.TrimSpace(name) == "" {
+		return rc.Run.Workflow.Name
+	}
+	return name
 }
 
 // NewExpressionEvaluator creates a new evaluator
 func (rc *RunContext) NewStepExpressionEvaluator(ctx context.Context, step step) ExpressionEvaluator {
 	// todo: cleanup EvaluationEnvironment creation
-	job := rc.Run.Job()
-	strategy := make(map[string]interface{})
-	if job.Strategy != nil {
-		strategy["fail-fast"] = job.Strategy.FailFast
-		strategy["max-parallel"] = job.Strategy.MaxParallel
-	}
-
 	jobs := rc.Run.Workflow.Jobs
 	jobNeeds := rc.Run.Job().Needs()
 
@@ -104,33 +131,39 @@ func (rc *RunContext) NewStepExpressionEvaluator(ctx context.Context, step step)
 		Job:      rc.getJobContext(),
 		Steps:    rc.getStepsContext(),
 		Secrets:  getWorkflowSecrets(ctx, rc),
-		Strategy: strategy,
+		Strategy: rc.getStrategyContext(),
 		Matrix:   rc.Matrix,
 		Needs:    using,
 		// todo: should be unavailable
 		// but required to interpolate/evaluate the inputs in actions/composite
 		Inputs: inputs,
+		Runner: rc.getRunnerContext(ctx),
+		Vars:   rc.Config.ContextOverrides.vars(),
 	}
-	if rc.JobContainer != nil {
-		ee.Runner = rc.JobContainer.GetRunnerContext(ctx)
-	}
+	trace := rc.newExpressionTrace()
 	return expressionEvaluator{
 		interpreter: exprparser.NewInterpeter(ee, exprparser.Config{
 			Run:        rc.Run,
 			WorkingDir: rc.Config.Workdir,
 			Context:    "step",
+			Trace:      trace.traceFunc(),
+			Workspace:  rc.hashFilesWorkspace(ctx),
 		}),
+		trace: trace,
 	}
 }
 
 type expressionEvaluator struct {
 	interpreter exprparser.Interpreter
+	trace       *expressionTrace // nil if the expressions aren't traced
 }
 
 func (ee expressionEvaluator) evaluate(ctx context.Context, in string, defaultStatusCheck exprparser.DefaultStatusCheck) (interface{}, error) {
 	logger := common.Logger(ctx)
 	logger.Debugf("evaluating expression '%s'", in)
+	ee.trace.reset()
 	evaluated, err := ee.interpreter.Evaluate(in, defaultStatusCheck)
+	ee.trace.log(ctx, in, evaluated, err)
 
 	printable := regexp.MustCompile(`::add-mask::.*`).ReplaceAllString(fmt.Sprintf("%t", evaluated), "::add-mask::***)")
 	logger.Debugf("expression '%s' evaluated to '%s'", in, printable)
@@ -340,11 +373,21 @@ func getEvaluatorInputs(ctx context.Context, rc *RunContext, step step, ghc *mod
 				if value == nil {
 					value = v.Default
 				}
-				if v.Type == "boolean" {
-					inputs[k] = value == "true"
-				} else {
-					inputs[k] = value
+				inputs[k] = convertWorkflowCallInput(v.Type, value)
+			}
+		}
+	}
+
+	if ghc.EventName == "workflow_call" && rc.caller == nil {
+		// the reusable workflow is run standalone, the inputs are passed with the event
+		config := rc.Run.Workflow.WorkflowCallConfig()
+		if config != nil && config.Inputs != nil {
+			for k, v := range config.Inputs {
+				value := nestedMapLookup(ghc.Event, "inputs", k)
+				if value == nil {
+					value = v.Default
 				}
+				inputs[k] = convertWorkflowCallInput(v.Type, value)
 			}
 		}
 	}
@@ -352,6 +395,22 @@ func getEvaluatorInputs(ctx context.Context, rc *RunContext, step step, ghc *mod
 	return inputs
 }
 
+func convertWorkflowCallInput(inputType string, value interface{}) interface{} {
+	str, ok := value.(string)
+	if !ok {
+		return value
+	}
+	switch inputType {
+	case "boolean":
+		return str == "true"
+	case "number":
+		if f, err := strconv.ParseFloat(str, 64); err == nil {
+			return f
+		}
+	}
+	return value
+}
+
 func setupWorkflowInputs(ctx context.Context, inputs *map[string]interface{}, rc *RunContext) {
 	if rc.caller != nil {
 		config := rc.Run.Workflow.WorkflowCallConfig()
@@ -375,7 +434,7 @@ func setupWorkflowInputs(ctx context.Context, inputs *map[string]interface{}, rc
 				}
 			}
 
-			(*inputs)[name] = value
+			(*inputs)[name] = convertWorkflowCallInput(input.Type, value)
 		}
 	}
 }
@@ -400,5 +459,14 @@ func getWorkflowSecrets(ctx context.Context, rc *RunContext) map[string]string {
 		return secrets
 	}
 
+	if rc.apiToken != "" && rc.Config.Secrets != nil {
+		secrets := make(map[string]string, len(rc.Config.Secrets)+1)
+		for k, v := range rc.Config.Secrets {
+			secrets[k] = v
+		}
+		secrets["GITHUB_TOKEN"] = rc.apiToken
+		return secrets
+	}
+
 	return rc.Config.Secrets
 }
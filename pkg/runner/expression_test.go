@@ -8,8 +8,10 @@ import (
 	"sort"
 	"testing"
 
+	"github.com/nektos/act/pkg/common"
 	"github.com/nektos/act/pkg/exprparser"
 	"github.com/nektos/act/pkg/model"
+	"github.com/sirupsen/logrus/hooks/test"
 	assert "github.com/stretchr/testify/assert"
 	yaml "gopkg.in/yaml.v3"
 )
@@ -364,3 +366,50 @@ func TestRewriteSubExpressionForceFormat(t *testing.T) {
 		})
 	}
 }
+
+func TestNewJobExpressionEvaluator(t *testing.T) {
+	ee, err := NewJobExpressionEvaluator(context.Background(), &Config{
+		EventName: "workflow_dispatch",
+		Workdir:   ".",
+		Env:       map[string]string{"key": "value"},
+		Inputs:    map[string]string{"name": "world"},
+	}, &model.Run{
+		JobID: "expr",
+		Workflow: &model.Workflow{
+			Name: "expr",
+			Jobs: map[string]*model.Job{"expr": {Name: "expr"}},
+		},
+	}, map[string]interface{}{"os": "ubuntu"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "workflow_dispatch-ubuntu", ee.Interpolate(context.Background(), "${{ github.event_name }}-${{ matrix.os }}"))
+	assert.Equal(t, "value", ee.Interpolate(context.Background(), "${{ env.key }}"))
+	assert.Equal(t, "world", ee.Interpolate(context.Background(), "${{ github.event.inputs.name }}"))
+}
+
+func TestTraceExpressions(t *testing.T) {
+	rc := createRunContext(t)
+	rc.Config.TraceExpressions = true
+	logger, hook := test.NewNullLogger()
+	ctx := common.WithLogger(context.Background(), logger)
+	ee := rc.NewExpressionEvaluator(ctx)
+	hook.Reset()
+
+	ok, err := EvalBool(ctx, ee, "matrix.os == 'Windows' && secrets.CASE_INSENSITIVE_SECRET != ''", exprparser.DefaultStatusCheckNone)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	messages := []string{}
+	for _, entry := range hook.AllEntries() {
+		messages = append(messages, entry.Message)
+	}
+	assert.Equal(t, []string{
+		"  \U0001F50E  ${{ matrix.os == 'Windows' && secrets.CASE_INSENSITIVE_SECRET != '' }} => false",
+		"  \U0001F50E      matrix.os = 'Linux'",
+		"  \U0001F50E      secrets.CASE_INSENSITIVE_SECRET = ***",
+	}, messages)
+
+	assert.Equal(t, "'Linux'", tracedValue("Linux"))
+	assert.Equal(t, `{"os":"Linux"}`, tracedValue(map[string]interface{}{"os": "Linux"}))
+	assert.Equal(t, "null", tracedValue(nil))
+}
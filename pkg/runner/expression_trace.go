@@ -0,0 +1,88 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/exprparser"
+)
+
+// maxTracedValueLength is the length the values of the traced expressions are truncated to, github.event would
+// flood the log
+const maxTracedValueLength = 200
+
+// expressionTrace collects the context references and function calls of the expression being evaluated, which are
+// logged with its result, see Config.TraceExpressions
+type expressionTrace struct {
+	inputs []string
+}
+
+// newExpressionTrace returns the trace of the expressions of the run context, nil if they aren't traced
+func (rc *RunContext) newExpressionTrace() *expressionTrace {
+	if !rc.Config.TraceExpressions {
+		return nil
+	}
+	return &expressionTrace{}
+}
+
+func (t *expressionTrace) traceFunc() exprparser.TraceFunc {
+	if t == nil {
+		return nil
+	}
+	return func(expr string, value interface{}) {
+		traced := tracedValue(value)
+		if expr == "secrets" || strings.HasPrefix(expr, "secrets.") || strings.HasPrefix(expr, "secrets[") {
+			traced = "***"
+		}
+		t.inputs = append(t.inputs, fmt.Sprintf("%s = %s", expr, traced))
+	}
+}
+
+func (t *expressionTrace) reset() {
+	if t != nil {
+		t.inputs = nil
+	}
+}
+
+// log logs the expression with its result and inputs, the job logger masks the secrets they contain
+func (t *expressionTrace) log(ctx context.Context, expr string, value interface{}, err error) {
+	if t == nil {
+		return
+	}
+	logger := common.Logger(ctx)
+	if err != nil {
+		logger.Infof("  \U0001F50E  ${{ %s }} failed: %v", expr, err)
+	} else {
+		logger.Infof("  \U0001F50E  ${{ %s }} => %s", expr, tracedValue(value))
+	}
+	for _, input := range t.inputs {
+		logger.Infof("  \U0001F50E      %s", input)
+	}
+}
+
+// tracedValue returns the value like an expression literal, objects and arrays as JSON
+func tracedValue(value interface{}) string {
+	var s string
+	switch value := value.(type) {
+	case nil:
+		s = "null"
+	case string:
+		s = "'" + strings.ReplaceAll(value, "'", "''") + "'"
+	case bool, int, float64:
+		s = fmt.Sprintf("%v", value)
+	default:
+		content, err := json.Marshal(value)
+		if err != nil {
+			s = fmt.Sprintf("%v", value)
+		} else {
+			s = string(content)
+		}
+	}
+	if len(s) > maxTracedValueLength {
+		s = s[:maxTracedValueLength] + "..."
+	}
+	return s
+}
@@ -0,0 +1,41 @@
+package runner
+
+import (
+	"context"
+	"sync"
+
+	"github.com/nektos/act/pkg/common"
+)
+
+// failFast cancels the legs of a matrix when one of them fails, like strategy.fail-fast on GitHub. The legs which
+// are still queued don't start, the running ones are cancelled and run their post steps.
+type failFast struct {
+	enabled bool
+	cancel  context.CancelFunc // cancels the context of the legs
+	mu      sync.Mutex
+	failed  string // the leg which failed first
+	err     error  // the error of its executor, nil when the failure is only reported by the job error
+}
+
+// fail cancels the other legs after the failure of the leg, unless the leg continued on error
+func (ff *failFast) fail(ctx context.Context, rc *RunContext, err error) {
+	if !ff.enabled || rc.continuedOnError || rc.cancelled {
+		return
+	}
+	ff.mu.Lock()
+	defer ff.mu.Unlock()
+	if ff.failed != "" {
+		return
+	}
+	ff.failed = rc.String()
+	ff.err = err
+	common.Logger(ctx).Infof("Cancelling the other legs of the matrix, fail-fast is enabled")
+	ff.cancel()
+}
+
+// failedLeg returns the leg which failed first and its error, an empty leg if none failed
+func (ff *failFast) failedLeg() (string, error) {
+	ff.mu.Lock()
+	defer ff.mu.Unlock()
+	return ff.failed, ff.err
+}
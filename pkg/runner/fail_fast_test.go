@@ -0,0 +1,41 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nektos/act/pkg/model"
+)
+
+func TestFailFast(t *testing.T) {
+	newLeg := func(name string) *RunContext {
+		workflow := &model.Workflow{Name: "test", Jobs: map[string]*model.Job{"job": createJob(t, "runs-on: ubuntu-latest", "")}}
+		return &RunContext{Name: name, Config: &Config{}, Run: &model.Run{Workflow: workflow, JobID: "job"}}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ff := &failFast{enabled: true, cancel: cancel}
+
+	continued := newLeg("continued")
+	continued.continuedOnError = true
+	ff.fail(ctx, continued, errors.New("continued"))
+	leg, _ := ff.failedLeg()
+	assert.Empty(t, leg)
+	assert.NoError(t, ctx.Err())
+
+	ff.fail(ctx, newLeg("first"), errors.New("first"))
+	ff.fail(ctx, newLeg("second"), errors.New("second"))
+	leg, err := ff.failedLeg()
+	assert.Equal(t, "test/first", leg)
+	assert.EqualError(t, err, "first")
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+
+	disabled := &failFast{cancel: func() { t.Fatal("cancelled without fail-fast") }}
+	disabled.fail(ctx, newLeg("first"), errors.New("first"))
+	leg, _ = disabled.failedLeg()
+	assert.Empty(t, leg)
+}
@@ -0,0 +1,62 @@
+package runner
+
+import (
+	_ "embed"
+
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+
+	"github.com/nektos/act/pkg/model"
+)
+
+//go:embed res/github-script.js
+var githubScriptShim string
+
+// githubScriptMode returns how the steps of actions/github-script run, see Config.GithubScript
+func (rc *RunContext) githubScriptMode() string {
+	switch rc.Config.GithubScript {
+	case "", GithubScriptAuto:
+		if rc.Config.SimulatePermissions || len(rc.Config.GraphQLFixtures) > 0 {
+			return GithubScriptAPI
+		}
+		return GithubScriptAction
+	}
+	return rc.Config.GithubScript
+}
+
+// githubScriptStep replaces a step of actions/github-script with a run step running its script with the shim of the
+// mode, so the action doesn't need to be cloned. The inputs of the step are passed as INPUT_ variables like to the
+// action and the result is the output of the step.
+func githubScriptStep(stepModel *model.Step, rc *RunContext) *model.Step {
+	if rc.Config == nil || stepModel.Type() != model.StepTypeUsesActionRemote || !matchesUses("actions/github-script", stepModel.Uses) {
+		return stepModel
+	}
+	mode := rc.githubScriptMode()
+	if mode == GithubScriptAction {
+		return stepModel
+	}
+
+	shimmed := *stepModel
+	if shimmed.Name == "" {
+		shimmed.Name = "Run " + stepModel.Uses
+	}
+	shimmed.Uses = ""
+	shimmed.Run = githubScriptShim
+	shimmed.Shell = "node {0}"
+	// the action runs in the workspace regardless of the defaults of the run steps
+	shimmed.WorkingDirectory = "${{ github.workspace }}"
+	shimmed.With = map[string]string{"github-token": "${{ github.token }}"}
+	for k, v := range stepModel.With {
+		shimmed.With[k] = v
+	}
+	shimmed.Env = yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	if stepModel.Env.Kind == yaml.MappingNode {
+		shimmed.Env.Content = append(shimmed.Env.Content, stepModel.Env.Content...)
+	}
+	shimmed.Env.Content = append(shimmed.Env.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "ACT_GITHUB_SCRIPT"},
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: mode},
+	)
+	log.Debugf("Running the script of '%s' with the %s shim of github-script", stepModel.Uses, mode)
+	return &shimmed
+}
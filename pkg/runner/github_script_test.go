@@ -0,0 +1,61 @@
+package runner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+
+	"github.com/nektos/act/pkg/githubapi"
+	"github.com/nektos/act/pkg/model"
+)
+
+func TestGithubScriptStep(t *testing.T) {
+	var step model.Step
+	assert.NoError(t, yaml.Unmarshal([]byte(`
+id: script
+uses: actions/github-script@v7
+env:
+  NUMBER: 1
+with:
+  script: return context.issue.number
+`), &step))
+
+	tables := []struct {
+		name     string
+		config   *Config
+		expected string
+	}{
+		{"auto without proxy", &Config{}, ""},
+		{"auto with permissions", &Config{SimulatePermissions: true}, GithubScriptAPI},
+		{"auto with fixtures", &Config{GraphQLFixtures: []*githubapi.GraphQLFixture{{}}}, GithubScriptAPI},
+		{"action", &Config{GithubScript: GithubScriptAction, SimulatePermissions: true}, ""},
+		{"api", &Config{GithubScript: GithubScriptAPI}, GithubScriptAPI},
+		{"noop", &Config{GithubScript: GithubScriptNoop}, GithubScriptNoop},
+	}
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			shimmed := githubScriptStep(&step, &RunContext{Config: table.config})
+			if table.expected == "" {
+				assert.Same(t, &step, shimmed)
+				return
+			}
+			assert.Equal(t, model.StepTypeRun, shimmed.Type())
+			assert.Equal(t, "Run actions/github-script@v7", shimmed.String())
+			assert.Equal(t, "node {0}", shimmed.Shell)
+			assert.Equal(t, map[string]string{
+				"NUMBER":             "1",
+				"ACT_GITHUB_SCRIPT":  table.expected,
+				"INPUT_SCRIPT":       "return context.issue.number",
+				"INPUT_GITHUB-TOKEN": "${{ github.token }}",
+			}, shimmed.GetEnv())
+			// the step of the workflow is left as is
+			assert.Equal(t, "actions/github-script@v7", step.Uses)
+			assert.Equal(t, map[string]string{"NUMBER": "1"}, step.Environment())
+		})
+	}
+
+	other := &model.Step{Uses: "actions/github-script-fork@v1"}
+	assert.Same(t, other, githubScriptStep(other, &RunContext{Config: &Config{GithubScript: GithubScriptNoop}}))
+	assert.NotContains(t, githubScriptShim, "${{")
+}
@@ -0,0 +1,122 @@
+package runner
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/container"
+)
+
+// hangCPUTicks is the CPU time in clock ticks the processes of the job container have to use between two samples to
+// be busy, more than the processes sampling them
+const hangCPUTicks = 2
+
+// activityWriter records the time of the last output of the steps of the job, see watchHang
+type activityWriter struct {
+	io.Writer
+	rc *RunContext
+}
+
+func (w *activityWriter) Write(p []byte) (int, error) {
+	w.rc.jobRunContext().outputAt.Store(time.Now())
+	return w.Writer.Write(p)
+}
+
+// jobRunContext returns the RunContext of the job, the steps of composite actions run in RunContexts of their own
+func (rc *RunContext) jobRunContext() *RunContext {
+	for rc.Parent != nil {
+		rc = rc.Parent
+	}
+	return rc
+}
+
+// lastOutput returns the time of the last output of the steps of the job
+func (rc *RunContext) lastOutput() time.Time {
+	output, _ := rc.jobRunContext().outputAt.Load().(time.Time)
+	return output
+}
+
+// watchHang watches a step for Config.HangTimeout without output nor CPU activity in the job container. It warns of
+// a possible hang with a dump of the processes of the container, and with Config.KillHungSteps cancels the returned
+// context to kill the step. The returned function stops the watch and returns for how long the killed step hung, 0
+// if it wasn't killed. Only the output is watched on the host.
+func (rc *RunContext) watchHang(ctx context.Context) (context.Context, func() time.Duration) {
+	timeout := rc.Config.HangTimeout
+	if timeout <= 0 || rc.Parent != nil {
+		// the steps of composite actions are watched by the step of the action
+		return ctx, func() time.Duration { return 0 }
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	exited := make(chan struct{})
+	var killedAfter time.Duration
+
+	interval := timeout / 4
+	if interval > 30*time.Second {
+		interval = 30 * time.Second
+	}
+	sampler, _ := rc.JobContainer.(container.ProcessSampler)
+	go func() {
+		defer close(exited)
+		logger := common.Logger(ctx)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		lastActivity := time.Now()
+		lastTicks := int64(-1)
+		warned := false
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			now := time.Now()
+			if output := rc.lastOutput(); output.After(lastActivity) {
+				lastActivity = output
+			}
+			if sampler != nil {
+				ticks, err := sampler.CPUTicks(ctx)
+				if err != nil {
+					logger.Debugf("Unable to sample the CPU time of the job container: %v", err)
+				} else {
+					if lastTicks >= 0 && ticks-lastTicks > hangCPUTicks {
+						lastActivity = now
+					}
+					lastTicks = ticks
+				}
+			}
+			idle := now.Sub(lastActivity)
+			if idle < timeout {
+				warned = false
+				continue
+			}
+			if warned {
+				continue
+			}
+			warned = true
+			logger.Warnf("⚠  Possible hang: the step has written no output and used no CPU for %s", idle.Round(time.Second))
+			if sampler != nil {
+				if dump, err := sampler.ProcessDump(ctx); err != nil {
+					logger.Warnf("Unable to list the processes of the job container: %v", err)
+				} else {
+					logger.Warnf("Processes of the job container:\n%s", dump)
+				}
+			}
+			if rc.Config.KillHungSteps {
+				killedAfter = idle
+				cancel()
+				return
+			}
+		}
+	}()
+	return ctx, func() time.Duration {
+		close(done)
+		<-exited
+		cancel()
+		return killedAfter
+	}
+}
@@ -0,0 +1,85 @@
+package runner
+
+import (
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type samplingContainerMock struct {
+	containerMock
+	mu    sync.Mutex
+	ticks int64
+	busy  bool
+	dumps int
+}
+
+func (cm *samplingContainerMock) CPUTicks(ctx context.Context) (int64, error) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	if cm.busy {
+		cm.ticks += 10
+	}
+	return cm.ticks, nil
+}
+
+func (cm *samplingContainerMock) ProcessDump(ctx context.Context) (string, error) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	cm.dumps++
+	return "1: sleep infinity wchan=hrtimer_nanosleep", nil
+}
+
+func TestWatchHang(t *testing.T) {
+	config := &Config{HangTimeout: 40 * time.Millisecond, KillHungSteps: true}
+
+	t.Run("idle", func(t *testing.T) {
+		cm := &samplingContainerMock{}
+		rc := &RunContext{Config: config, JobContainer: cm}
+		ctx, stop := rc.watchHang(context.Background())
+		select {
+		case <-ctx.Done():
+		case <-time.After(5 * time.Second):
+			t.Fatal("the idle step wasn't killed")
+		}
+		assert.GreaterOrEqual(t, stop(), 40*time.Millisecond)
+		assert.Equal(t, 1, cm.dumps)
+	})
+
+	t.Run("busy", func(t *testing.T) {
+		cm := &samplingContainerMock{busy: true}
+		rc := &RunContext{Config: config, JobContainer: cm}
+		ctx, stop := rc.watchHang(context.Background())
+		time.Sleep(200 * time.Millisecond)
+		assert.NoError(t, ctx.Err())
+		assert.Equal(t, time.Duration(0), stop())
+		assert.Equal(t, 0, cm.dumps)
+	})
+
+	t.Run("output", func(t *testing.T) {
+		rc := &RunContext{Config: config, JobContainer: &containerMock{}}
+		child := &RunContext{Config: config, Parent: rc}
+		ctx, stop := rc.watchHang(context.Background())
+		// the output of the steps of composite actions is the output of the step
+		writer := &activityWriter{Writer: io.Discard, rc: child}
+		for i := 0; i < 40; i++ {
+			_, err := writer.Write([]byte("."))
+			assert.NoError(t, err)
+			time.Sleep(5 * time.Millisecond)
+		}
+		assert.NoError(t, ctx.Err())
+		assert.Equal(t, time.Duration(0), stop())
+	})
+
+	t.Run("composite", func(t *testing.T) {
+		rc := &RunContext{Config: config, Parent: &RunContext{Config: config}}
+		ctx := context.Background()
+		watched, stop := rc.watchHang(ctx)
+		assert.Equal(t, ctx, watched)
+		assert.Equal(t, time.Duration(0), stop())
+	})
+}
@@ -0,0 +1,172 @@
+package runner
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// hashFilesWorkspace returns the workspace of the job container the hashFiles of the expressions look up, nil if
+// the workspace is the working directory of act. The files of a job which isn't bound to the working directory are
+// copied into its container, where its steps change them.
+func (rc *RunContext) hashFilesWorkspace(ctx context.Context) func() (fs.FS, error) {
+	if rc.JobContainer == nil || rc.Config.BindWorkdir {
+		return nil
+	}
+	return func() (fs.FS, error) {
+		archive, err := rc.JobContainer.GetContainerArchive(ctx, rc.JobContainer.ToContainerPath(rc.Config.Workdir))
+		if err != nil {
+			return nil, err
+		}
+		defer archive.Close()
+		return readArchiveFS(archive)
+	}
+}
+
+// archiveFS is the read-only file system of the directories and regular files of a tar archive of a directory
+type archiveFS map[string]*archiveFile
+
+type archiveFile struct {
+	name    string
+	mode    fs.FileMode
+	modTime time.Time
+	content []byte
+	entries []fs.DirEntry // of a directory, sorted by name
+}
+
+func (f *archiveFile) Name() string               { return f.name }
+func (f *archiveFile) Size() int64                { return int64(len(f.content)) }
+func (f *archiveFile) Mode() fs.FileMode          { return f.mode }
+func (f *archiveFile) ModTime() time.Time         { return f.modTime }
+func (f *archiveFile) IsDir() bool                { return f.mode.IsDir() }
+func (f *archiveFile) Sys() interface{}           { return nil }
+func (f *archiveFile) Type() fs.FileMode          { return f.mode.Type() }
+func (f *archiveFile) Info() (fs.FileInfo, error) { return f, nil }
+
+type openArchiveFile struct {
+	file   *archiveFile
+	reader *bytes.Reader
+	offset int // of the entries of a directory
+}
+
+func (f *openArchiveFile) Stat() (fs.FileInfo, error) { return f.file, nil }
+func (f *openArchiveFile) Close() error               { return nil }
+
+func (f *openArchiveFile) Read(b []byte) (int, error) {
+	if f.file.IsDir() {
+		return 0, &fs.PathError{Op: "read", Path: f.file.name, Err: errors.New("is a directory")}
+	}
+	return f.reader.Read(b)
+}
+
+func (f *openArchiveFile) ReadDir(n int) ([]fs.DirEntry, error) {
+	if !f.file.IsDir() {
+		return nil, &fs.PathError{Op: "readdir", Path: f.file.name, Err: errors.New("not a directory")}
+	}
+	entries := f.file.entries[f.offset:]
+	if n > 0 {
+		if len(entries) == 0 {
+			return nil, io.EOF
+		}
+		if n < len(entries) {
+			entries = entries[:n]
+		}
+	}
+	f.offset += len(entries)
+	return entries, nil
+}
+
+func (a archiveFS) Open(name string) (fs.File, error) {
+	file, err := a.lookup("open", name)
+	if err != nil {
+		return nil, err
+	}
+	return &openArchiveFile{file: file, reader: bytes.NewReader(file.content)}, nil
+}
+
+func (a archiveFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	file, err := a.lookup("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+	if !file.IsDir() {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: errors.New("not a directory")}
+	}
+	return file.entries, nil
+}
+
+func (a archiveFS) lookup(op string, name string) (*archiveFile, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	file, ok := a[name]
+	if !ok {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	}
+	return file, nil
+}
+
+// add adds a file and its parent directories
+func (a archiveFS) add(name string, file *archiveFile) {
+	if existing, ok := a[name]; ok {
+		if existing.IsDir() && file.IsDir() {
+			existing.mode = file.mode
+			existing.modTime = file.modTime
+			return
+		}
+	}
+	file.name = path.Base(name)
+	parent := path.Dir(name)
+	if _, ok := a[parent]; !ok {
+		a.add(parent, &archiveFile{mode: fs.ModeDir | 0o755})
+	}
+	if _, ok := a[name]; !ok {
+		a[parent].entries = append(a[parent].entries, file)
+	}
+	a[name] = file
+}
+
+// readArchiveFS reads the archive of a directory, whose entries are prefixed by the name of the directory like the
+// archives of the containers. Only the directories and regular files are read.
+func readArchiveFS(r io.Reader) (archiveFS, error) {
+	fsys := archiveFS{".": &archiveFile{name: ".", mode: fs.ModeDir | 0o755}}
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		parts := strings.SplitN(strings.Trim(path.Clean("/"+header.Name), "/"), "/", 2)
+		if len(parts) < 2 {
+			continue
+		}
+		file := &archiveFile{modTime: header.ModTime}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			file.mode = fs.ModeDir | fs.FileMode(header.Mode).Perm()
+		case tar.TypeReg:
+			if file.content, err = io.ReadAll(tr); err != nil {
+				return nil, err
+			}
+			file.mode = fs.FileMode(header.Mode).Perm()
+		default:
+			continue
+		}
+		fsys.add(parts[1], file)
+	}
+	for _, file := range fsys {
+		sort.Slice(file.entries, func(i, j int) bool {
+			return file.entries[i].Name() < file.entries[j].Name()
+		})
+	}
+	return fsys, nil
+}
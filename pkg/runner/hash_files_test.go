@@ -0,0 +1,47 @@
+package runner
+
+import (
+	"archive/tar"
+	"bytes"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadArchiveFS(t *testing.T) {
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	for _, header := range []*tar.Header{
+		{Name: "workspace/", Typeflag: tar.TypeDir, Mode: 0o755},
+		{Name: "workspace/go.sum", Typeflag: tar.TypeReg, Mode: 0o644, Size: 3},
+		{Name: "workspace/pkg/b/go.sum", Typeflag: tar.TypeReg, Mode: 0o644, Size: 3},
+		{Name: "workspace/pkg/a/go.sum", Typeflag: tar.TypeReg, Mode: 0o644, Size: 3},
+		{Name: "workspace/link", Typeflag: tar.TypeSymlink, Linkname: "go.sum"},
+	} {
+		assert.NoError(t, tw.WriteHeader(header))
+		if header.Typeflag == tar.TypeReg {
+			_, err := tw.Write([]byte("sum"))
+			assert.NoError(t, err)
+		}
+	}
+	assert.NoError(t, tw.Close())
+
+	fsys, err := readArchiveFS(buf)
+	assert.NoError(t, err)
+	assert.NoError(t, fstest.TestFS(fsys, "go.sum", "pkg/a/go.sum", "pkg/b/go.sum"))
+
+	// the directories are listed in order, the symlinks are left out
+	files := []string{}
+	assert.NoError(t, fs.WalkDir(fsys, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err == nil && !entry.IsDir() {
+			files = append(files, name)
+		}
+		return err
+	}))
+	assert.Equal(t, []string{"go.sum", "pkg/a/go.sum", "pkg/b/go.sum"}, files)
+	content, err := fs.ReadFile(fsys, "pkg/a/go.sum")
+	assert.NoError(t, err)
+	assert.Equal(t, "sum", string(content))
+}
@@ -0,0 +1,52 @@
+package runner
+
+import (
+	"context"
+	"net"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/container"
+)
+
+// newHostAddressExecutor looks up how the containers reach the servers act starts on the host,
+// the artifact server, the cache server and the GitHub API proxy
+func (runner *runnerImpl) newHostAddressExecutor(executor common.Executor) common.Executor {
+	return func(ctx context.Context) error {
+		runner.hostAddress = resolveHostAddress(ctx, runner.config.ArtifactServerAddr)
+		common.Logger(ctx).Debugf("Containers reach the host as %s", runner.hostAddress.Host)
+		return executor(ctx)
+	}
+}
+
+// resolveHostAddress returns how the containers reach a server bound to addr, a hostname or the IP of
+// an interface is used as is. All interfaces and the loopback interface are reached through the name the
+// container engine provides for the host.
+func resolveHostAddress(ctx context.Context, addr string) *container.HostAddress {
+	logger := common.Logger(ctx)
+
+	ip := net.ParseIP(addr)
+	loopback := addr == "localhost" || (ip != nil && ip.IsLoopback())
+	if addr != "" && !loopback && (ip == nil || !ip.IsUnspecified()) {
+		return &container.HostAddress{Host: addr}
+	}
+
+	if !common.Dryrun(ctx) {
+		hostAddress, err := container.GetHostAddress(ctx)
+		if err == nil {
+			if loopback && !hostAddress.Loopback {
+				logger.Warnf("The containers can't reach %s of the host, bind the artifact server to 0.0.0.0 or a hostname instead", addr)
+			}
+			return hostAddress
+		}
+		logger.Debugf("Unable to look up the address of the host in the containers: %v", err)
+	}
+	return &container.HostAddress{Host: common.GetOutboundIP().String()}
+}
+
+// extraHosts returns the host entries the containers need to reach the host
+func (rc *RunContext) extraHosts() []string {
+	if rc.hostAddress == nil {
+		return nil
+	}
+	return rc.hostAddress.ExtraHosts
+}
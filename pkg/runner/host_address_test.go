@@ -0,0 +1,48 @@
+package runner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nektos/act/pkg/container"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveHostAddress(t *testing.T) {
+	for _, addr := range []string{"artifacts.local", "192.168.1.5", "fd00::1"} {
+		assert.Equal(t, &container.HostAddress{Host: addr}, resolveHostAddress(context.Background(), addr), addr)
+	}
+}
+
+func TestSetActionRuntimeVars(t *testing.T) {
+	t.Setenv("ACTIONS_RUNTIME_URL", "")
+
+	tables := []struct {
+		addr        string
+		hostAddress *container.HostAddress
+		url         string
+	}{
+		{"192.168.1.5", nil, "http://192.168.1.5:34567/"},
+		{"fd00::1", &container.HostAddress{Host: "fd00::1"}, "http://[fd00::1]:34567/"},
+		{"", &container.HostAddress{Host: "host.docker.internal"}, "http://host.docker.internal:34567/"},
+	}
+	for _, table := range tables {
+		rc := &RunContext{
+			Config:      &Config{ArtifactServerAddr: table.addr, ArtifactServerPort: "34567"},
+			hostAddress: table.hostAddress,
+		}
+		env := map[string]string{}
+		setActionRuntimeVars(rc, env)
+		assert.Equal(t, table.url, env["ACTIONS_RUNTIME_URL"], table.addr)
+	}
+}
+
+func TestSetFixturesVars(t *testing.T) {
+	rc := &RunContext{
+		Config:      &Config{FixturesServerPath: "/testdata", FixturesServerPort: "34569"},
+		hostAddress: &container.HostAddress{Host: "host.docker.internal"},
+	}
+	env := map[string]string{}
+	setFixturesVars(rc, env)
+	assert.Equal(t, "http://host.docker.internal:34569/", env["ACT_FIXTURES_URL"])
+}
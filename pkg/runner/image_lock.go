@@ -0,0 +1,118 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/model"
+)
+
+// ImageLockFile is the file of the working directory 'act lock' pins the images of the workflows in
+const ImageLockFile = "act.lock"
+
+// ImageLock pins the images of the workflows to the digests 'act lock' resolved them to
+type ImageLock struct {
+	Images map[string]string `json:"images"` // digest of the manifest (list) of every image, keyed by the image as used by the workflows and -P
+}
+
+// ReadImageLock reads the lockfile written by 'act lock'
+func ReadImageLock(path string) (*ImageLock, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	lock := new(ImageLock)
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(lock); err != nil {
+		return nil, fmt.Errorf("unable to read the image lock %s: %w", path, err)
+	}
+	for image, digest := range lock.Images {
+		if !strings.Contains(digest, ":") {
+			return nil, fmt.Errorf("unable to read the image lock %s: invalid digest '%s' of image '%s'", path, digest, image)
+		}
+	}
+	return lock, nil
+}
+
+// Write writes the lockfile, the images are sorted to keep the diffs of the file reviewable
+func (l *ImageLock) Write(path string) error {
+	content, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(content, '\n'), 0o644)
+}
+
+// pin returns the image pinned to its digest, the image is returned as is if it isn't locked or already has a digest
+func (l *ImageLock) pin(image string) (string, bool) {
+	if strings.Contains(image, "@") {
+		return image, true
+	}
+	if l == nil {
+		return image, false
+	}
+	digest, ok := l.Images[image]
+	if !ok {
+		return image, false
+	}
+	return image + "@" + digest, true
+}
+
+// pinImage pins an image of the workflow to the digest of Config.ImageLock, with Config.FrozenImageLock an image which
+// isn't locked fails the job as the workflow drifted from the lockfile
+func (rc *RunContext) pinImage(ctx context.Context, image string) (string, error) {
+	if image == "" || strings.EqualFold(image, "-self-hosted") {
+		return image, nil
+	}
+	pinned, ok := rc.Config.ImageLock.pin(image)
+	if ok {
+		common.Logger(ctx).Debugf("Pinned image %s to %s", image, pinned)
+	} else if rc.Config.FrozenImageLock {
+		return "", fmt.Errorf("image '%s' is not pinned in %s, run 'act lock' to update it", image, ImageLockFile)
+	}
+	return pinned, nil
+}
+
+// PlanImages returns the images of the plan pinned by 'act lock': the images the jobs run on, the images of their
+// services and of their docker:// steps. The images of the actions used by the jobs are not known before they run.
+func PlanImages(ctx context.Context, config *Config, plan *model.Plan) []string {
+	runner := &runnerImpl{config: config, eventJSON: "{}"}
+
+	images := make([]string, 0)
+	add := func(image string) {
+		if image != "" && !strings.EqualFold(image, "-self-hosted") && !strings.Contains(image, "@") && !common.ContainsString(images, image) {
+			images = append(images, image)
+		}
+	}
+	for _, stage := range plan.Stages {
+		for _, run := range stage.Runs {
+			job := run.Job()
+			if job.Type() != model.JobTypeDefault {
+				continue
+			}
+			for _, matrix := range job.GetMatrixes() {
+				rc := runner.newRunContext(ctx, run, matrix)
+				add(rc.platformImage(ctx))
+				for _, service := range job.Services {
+					if service != nil {
+						add(rc.ExprEval.Interpolate(ctx, service.Image))
+					}
+				}
+			}
+			for _, step := range job.Steps {
+				if step != nil && step.Type() == model.StepTypeUsesDockerURL {
+					add(strings.TrimPrefix(step.Uses, "docker://"))
+				}
+			}
+		}
+	}
+	sort.Strings(images)
+	return images
+}
@@ -0,0 +1,112 @@
+package runner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nektos/act/pkg/model"
+)
+
+func TestImageLockReadWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ImageLockFile)
+	lock := &ImageLock{Images: map[string]string{
+		"node:16-buster-slim": "sha256:2f1c7e3b",
+		"alpine:3.17":         "sha256:8914eb54",
+	}}
+	assert.NoError(t, lock.Write(path))
+
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "{\n  \"images\": {\n    \"alpine:3.17\": \"sha256:8914eb54\",\n    \"node:16-buster-slim\": \"sha256:2f1c7e3b\"\n  }\n}\n", string(content))
+
+	read, err := ReadImageLock(path)
+	assert.NoError(t, err)
+	assert.Equal(t, lock, read)
+
+	assert.NoError(t, os.WriteFile(path, []byte(`{"images": {"alpine:3.17": "latest"}}`), 0o600))
+	_, err = ReadImageLock(path)
+	assert.ErrorContains(t, err, "invalid digest 'latest' of image 'alpine:3.17'")
+
+	_, err = ReadImageLock(filepath.Join(t.TempDir(), ImageLockFile))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestRunContextPinImage(t *testing.T) {
+	lock := &ImageLock{Images: map[string]string{"alpine:3.17": "sha256:8914eb54"}}
+	ctx := context.Background()
+
+	tables := []struct {
+		image    string
+		frozen   bool
+		expected string
+		err      string
+	}{
+		{"alpine:3.17", false, "alpine:3.17@sha256:8914eb54", ""},
+		{"alpine:3.17", true, "alpine:3.17@sha256:8914eb54", ""},
+		{"alpine:3.16", false, "alpine:3.16", ""},
+		{"alpine:3.16", true, "", "image 'alpine:3.16' is not pinned in act.lock"},
+		{"alpine@sha256:0123", true, "alpine@sha256:0123", ""},
+		{"-self-hosted", true, "-self-hosted", ""},
+	}
+	for _, table := range tables {
+		rc := &RunContext{Config: &Config{ImageLock: lock, FrozenImageLock: table.frozen}}
+		image, err := rc.pinImage(ctx, table.image)
+		if table.err == "" {
+			assert.NoError(t, err, table.image)
+			assert.Equal(t, table.expected, image, table.image)
+		} else {
+			assert.ErrorContains(t, err, table.err, table.image)
+		}
+	}
+
+	rc := &RunContext{Config: &Config{}}
+	image, err := rc.pinImage(ctx, "alpine:3.17")
+	assert.NoError(t, err)
+	assert.Equal(t, "alpine:3.17", image, "the images aren't pinned without a lockfile")
+}
+
+func TestPlanImages(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	workflow, err := model.ReadWorkflow(strings.NewReader(`
+on: push
+jobs:
+  test:
+    runs-on: ${{ matrix.os }}
+    strategy:
+      matrix:
+        os: [ubuntu-latest, ubuntu-22.04, self-hosted]
+    services:
+      db:
+        image: postgres:15
+    steps:
+      - uses: docker://alpine:3.17
+      - uses: docker://alpine@sha256:0123
+      - run: make test
+  build:
+    runs-on: ubuntu-latest
+    container:
+      image: golang:1.20
+    steps:
+      - run: go build
+`))
+	assert.NoError(t, err)
+	plan := &model.Plan{Stages: []*model.Stage{{Runs: []*model.Run{
+		{Workflow: workflow, JobID: "test"},
+		{Workflow: workflow, JobID: "build"},
+	}}}}
+	config := &Config{
+		Workdir: t.TempDir(),
+		Platforms: map[string]string{
+			"ubuntu-latest": "node:16-buster-slim",
+			"ubuntu-22.04":  "node:16-bullseye-slim",
+			"self-hosted":   "-self-hosted",
+		},
+	}
+
+	assert.Equal(t, []string{"alpine:3.17", "golang:1.20", "node:16-bullseye-slim", "node:16-buster-slim", "postgres:15"}, PlanImages(context.Background(), config, plan))
+}
@@ -0,0 +1,90 @@
+package runner
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nektos/act/pkg/model"
+)
+
+// InjectStep is a step spliced into the jobs at run time, before or after the steps matching the target
+type InjectStep struct {
+	After  bool
+	Target string // name or id of the step, '*' matches every step
+	Step   model.Step
+}
+
+// ParseInjectStep parses an injected step in the form '<before|after>:<step name or id>:<run|uses>=<value>'
+func ParseInjectStep(value string) (*InjectStep, error) {
+	position, rest, ok := strings.Cut(value, ":")
+	if !ok {
+		return nil, fmt.Errorf("invalid injected step '%s', expected <before|after>:<step>:<run|uses>=<value>", value)
+	}
+
+	inject := &InjectStep{}
+	switch position {
+	case "before":
+	case "after":
+		inject.After = true
+	default:
+		return nil, fmt.Errorf("invalid position '%s' of injected step '%s', expected 'before' or 'after'", position, value)
+	}
+
+	runIndex := strings.Index(rest, ":run=")
+	usesIndex := strings.Index(rest, ":uses=")
+	switch {
+	case runIndex >= 0 && (usesIndex < 0 || runIndex < usesIndex):
+		inject.Target = rest[:runIndex]
+		inject.Step.Run = rest[runIndex+len(":run="):]
+	case usesIndex >= 0:
+		inject.Target = rest[:usesIndex]
+		inject.Step.Uses = rest[usesIndex+len(":uses="):]
+	default:
+		return nil, fmt.Errorf("invalid injected step '%s', expected <before|after>:<step>:<run|uses>=<value>", value)
+	}
+	if inject.Target == "" {
+		return nil, fmt.Errorf("invalid injected step '%s', the step name or id is empty", value)
+	}
+
+	return inject, nil
+}
+
+func (is *InjectStep) matches(step *model.Step) bool {
+	return is.Target == "*" || step.Name == is.Target || step.ID == is.Target
+}
+
+// injectSteps returns the steps of a job with the injected steps spliced in
+func injectSteps(steps []*model.Step, injects []*InjectStep) []*model.Step {
+	if len(injects) == 0 {
+		return steps
+	}
+
+	result := make([]*model.Step, 0, len(steps))
+	count := 0
+	inject := func(step *model.Step, after bool) {
+		for _, is := range injects {
+			if is.After == after && is.matches(step) {
+				injected := is.Step
+				injected.ID = fmt.Sprintf("__inject_%d", count)
+				count++
+				result = append(result, &injected)
+			}
+		}
+	}
+
+	for i, step := range steps {
+		if step == nil {
+			result = append(result, step)
+			continue
+		}
+		// keep the ids of the steps without id stable, they are derived from the position
+		if step.ID == "" {
+			step.ID = fmt.Sprintf("%d", i)
+		}
+
+		inject(step, false)
+		result = append(result, step)
+		inject(step, true)
+	}
+	return result
+}
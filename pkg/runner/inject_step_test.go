@@ -0,0 +1,59 @@
+package runner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nektos/act/pkg/model"
+)
+
+func TestParseInjectStep(t *testing.T) {
+	table := []struct {
+		value  string
+		inject *InjectStep
+		err    bool
+	}{
+		{"before:Run tests:run=env | sort", &InjectStep{Target: "Run tests", Step: model.Step{Run: "env | sort"}}, false},
+		{"after:build:uses=actions/upload-artifact@v3", &InjectStep{After: true, Target: "build", Step: model.Step{Uses: "actions/upload-artifact@v3"}}, false},
+		{"after:*:run=echo a:run=b", &InjectStep{After: true, Target: "*", Step: model.Step{Run: "echo a:run=b"}}, false},
+		{"during:build:run=env", nil, true},
+		{"before:build", nil, true},
+		{"before::run=env", nil, true},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.value, func(t *testing.T) {
+			inject, err := ParseInjectStep(tt.value)
+			if tt.err {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.inject, inject)
+			}
+		})
+	}
+}
+
+func TestInjectSteps(t *testing.T) {
+	steps := []*model.Step{
+		{Name: "Checkout", Uses: "actions/checkout@v3"},
+		{ID: "test", Name: "Run tests", Run: "make test"},
+	}
+	injects := []*InjectStep{
+		{Target: "Run tests", Step: model.Step{Run: "env | sort"}},
+		{After: true, Target: "*", Step: model.Step{Run: "ls"}},
+	}
+
+	result := injectSteps(steps, injects)
+
+	assert.Len(t, result, 5)
+	assert.Equal(t, "0", result[0].ID)
+	assert.Equal(t, "__inject_0", result[1].ID)
+	assert.Equal(t, "ls", result[1].Run)
+	assert.Equal(t, "__inject_1", result[2].ID)
+	assert.Equal(t, "env | sort", result[2].Run)
+	assert.Equal(t, "test", result[3].ID)
+	assert.Equal(t, "ls", result[4].Run)
+	assert.Empty(t, injects[0].Step.ID)
+}
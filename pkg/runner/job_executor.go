@@ -3,9 +3,13 @@ package runner
 import (
 	"context"
 	"fmt"
+	"io"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/exprparser"
 	"github.com/nektos/act/pkg/model"
 )
 
@@ -43,12 +47,10 @@ func newJobExecutor(info jobInfo, sf stepFactory, rc *RunContext) common.Executo
 		if rc.Run == nil {
 			return nil
 		}
-		rc.ExprEval = rc.NewExpressionEvaluator(ctx)
 		// evaluate environment variables since they can contain
 		// GitHub's special environment variables.
-		for k, v := range rc.GetEnv() {
-			rc.Env[k] = rc.ExprEval.Interpolate(ctx, v)
-		}
+		rc.evaluateEnv(ctx)
+		rc.ExprEval = rc.NewExpressionEvaluator(ctx)
 		return nil
 	})
 
@@ -75,6 +77,10 @@ func newJobExecutor(info jobInfo, sf stepFactory, rc *RunContext) common.Executo
 		steps = append(steps, useStepLogger(rc, stepModel, stepStageMain, func(ctx context.Context) error {
 			logger := common.Logger(ctx)
 			err := stepExec(ctx)
+			if ctx.Err() != nil {
+				// the steps left only run with always() or cancelled(), like the post steps
+				rc.cancelled = true
+			}
 			if err != nil {
 				logger.Errorf("%v", err)
 				common.SetJobError(ctx, err)
@@ -96,15 +102,21 @@ func newJobExecutor(info jobInfo, sf stepFactory, rc *RunContext) common.Executo
 
 	postExecutor = postExecutor.Finally(func(ctx context.Context) error {
 		jobError := common.JobError(ctx)
+		jobResult := newJobResult(ctx, rc)
+		if rc.Config.PropagateEnvToNeeds && rc.Run != nil {
+			rc.propagateEnv(rc.readExportedEnv(ctx))
+		}
+		rc.exportWorkspace(ctx)
 		var err error
 		if rc.Config.AutoRemove || jobError == nil {
 			// always allow 1 min for stopping and removing the runner, even if we were cancelled
-			ctx, cancel := context.WithTimeout(common.WithLogger(context.Background(), common.Logger(ctx)), time.Minute)
+			ctx, cancel := context.WithTimeout(common.WithoutCancel(ctx), time.Minute)
 			defer cancel()
 			err = info.stopContainer()(ctx)
 		}
+		rc.logUnsupportedSteps(ctx)
 		setJobResult(ctx, info, rc, jobError == nil)
-		setJobOutputs(ctx, rc)
+		jobResult.collect(ctx, rc)
 
 		return err
 	})
@@ -113,19 +125,62 @@ func newJobExecutor(info jobInfo, sf stepFactory, rc *RunContext) common.Executo
 	pipeline = append(pipeline, preSteps...)
 	pipeline = append(pipeline, steps...)
 
-	return common.NewPipelineExecutor(info.startContainer(), common.NewPipelineExecutor(pipeline...).
-		Finally(func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		var timeout time.Duration
+		if rc.Run != nil && rc.Run.Job().TimeoutMinutes != "" {
+			var err error
+			if timeout, err = timeoutMinutes(ctx, rc.NewExpressionEvaluator(ctx), rc.Run.Job().TimeoutMinutes); err != nil {
+				return err
+			}
+		}
+		jobCtx := ctx
+		if timeout > 0 {
 			var cancel context.CancelFunc
-			if ctx.Err() == context.Canceled {
-				// in case of an aborted run, we still should execute the
-				// post steps to allow cleanup.
-				ctx, cancel = context.WithTimeout(common.WithLogger(context.Background(), common.Logger(ctx)), 5*time.Minute)
+			jobCtx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+
+		err := common.NewPipelineExecutor(info.startContainer(), common.NewPipelineExecutor(pipeline...).
+			Finally(func(ctx context.Context) error {
+				if jobCtx.Err() == nil {
+					return postExecutor(ctx)
+				}
+				// in case of an aborted run or an exceeded timeout-minutes, we still should execute the
+				// post steps to allow cleanup, they see the job as cancelled
+				rc.cancelled = true
+				if jobCtx.Err() == context.DeadlineExceeded {
+					err := fmt.Errorf("the job has exceeded the maximum execution time of %s", timeout)
+					common.Logger(ctx).Errorf("%v", err)
+					common.SetJobError(ctx, err)
+				}
+				ctx, cancel := context.WithTimeout(common.WithoutCancel(ctx), 5*time.Minute)
 				defer cancel()
-			}
-			return postExecutor(ctx)
-		}).
-		Finally(info.interpolateOutputs()).
-		Finally(info.closeContainer()))
+				return postExecutor(ctx)
+			}).
+			Finally(info.interpolateOutputs()).
+			Finally(info.closeContainer()))(jobCtx)
+		if err == context.DeadlineExceeded && ctx.Err() == nil {
+			// the exceeded timeout is reported by the result of the job
+			return nil
+		}
+		return err
+	}
+}
+
+// timeoutMinutes evaluates the timeout-minutes of a job or a step, 0 if there is none
+func timeoutMinutes(ctx context.Context, eval ExpressionEvaluator, value string) (time.Duration, error) {
+	if strings.TrimSpace(value) == "" {
+		return 0, nil
+	}
+	value = strings.TrimSpace(eval.Interpolate(ctx, value))
+	if value == "" {
+		return 0, nil
+	}
+	minutes, err := strconv.ParseFloat(value, 64)
+	if err != nil || minutes <= 0 {
+		return 0, fmt.Errorf("invalid timeout-minutes '%s', expected a positive number of minutes", value)
+	}
+	return time.Duration(minutes * float64(time.Minute)), nil
 }
 
 func setJobResult(ctx context.Context, info jobInfo, rc *RunContext, success bool) {
@@ -138,36 +193,49 @@ func setJobResult(ctx context.Context, info jobInfo, rc *RunContext, success boo
 		jobResult = rc.Run.Job().Result
 	}
 
-	if !success {
-		jobResult = "failure"
+	continuedOnError := false
+	if rc.cancelled {
+		// a cancelled job doesn't continue on error, its dependent jobs are skipped unless they run always(). A leg
+		// cancelled by fail-fast doesn't hide the failure of the matrix.
+		if jobResult != "failure" {
+			jobResult = "cancelled"
+		}
+	} else if !success {
+		if isJobContinueOnError(ctx, rc) {
+			// like on GitHub the job is reported as successful, so the dependent jobs still run
+			continuedOnError = true
+		} else {
+			jobResult = "failure"
+		}
 	}
 
+	rc.continuedOnError = continuedOnError
 	info.result(jobResult)
-	if rc.caller != nil {
-		// set reusable workflow job result
-		rc.caller.runContext.result(jobResult)
-	}
 
 	jobResultMessage := "succeeded"
-	if jobResult != "success" {
+	if jobResult == "cancelled" {
+		jobResultMessage = "cancelled"
+	} else if jobResult != "success" {
 		jobResultMessage = "failed"
+	} else if continuedOnError {
+		jobResultMessage = "succeeded (with errors)"
 	}
 
-	logger.WithField("jobResult", jobResult).Infof("\U0001F3C1  Job %s", jobResultMessage)
+	logger.WithField("jobResult", jobResult).WithField("jobContinuedOnError", continuedOnError).Infof("\U0001F3C1  Job %s", jobResultMessage)
 }
 
-func setJobOutputs(ctx context.Context, rc *RunContext) {
-	if rc.caller != nil {
-		// map outputs for reusable workflows
-		callerOutputs := make(map[string]string)
-
-		ee := rc.NewExpressionEvaluator(ctx)
-		for k, v := range rc.Run.Job().Outputs {
-			callerOutputs[k] = ee.Interpolate(ctx, v)
-		}
+func isJobContinueOnError(ctx context.Context, rc *RunContext) bool {
+	expr := rc.Run.Job().RawContinueOnError
+	if len(strings.TrimSpace(expr)) == 0 {
+		return false
+	}
 
-		rc.caller.runContext.Run.Job().Outputs = callerOutputs
+	continueOnError, err := EvalBool(ctx, rc.NewExpressionEvaluator(ctx), expr, exprparser.DefaultStatusCheckNone)
+	if err != nil {
+		common.Logger(ctx).Errorf("  \u274C  Error in continue-on-error-expression: \"continue-on-error: %s\" (%s)", expr, err)
+		return false
 	}
+	return continueOnError
 }
 
 func useStepLogger(rc *RunContext, stepModel *model.Step, stage stepStage, executor common.Executor) common.Executor {
@@ -184,7 +252,11 @@ func useStepLogger(rc *RunContext, stepModel *model.Step, stage stepStage, execu
 			return true
 		})
 
-		oldout, olderr := rc.JobContainer.ReplaceLogWriter(logWriter, logWriter)
+		var out io.Writer = logWriter
+		if rc.Config.HangTimeout > 0 {
+			out = &activityWriter{Writer: logWriter, rc: rc}
+		}
+		oldout, olderr := rc.JobContainer.ReplaceLogWriter(out, out)
 		defer rc.JobContainer.ReplaceLogWriter(oldout, olderr)
 
 		return executor(ctx)
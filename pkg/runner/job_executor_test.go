@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"testing"
+	"time"
 
 	"github.com/nektos/act/pkg/common"
 	"github.com/nektos/act/pkg/container"
@@ -339,3 +340,87 @@ func TestNewJobExecutor(t *testing.T) {
 		})
 	}
 }
+
+func TestTimeoutMinutes(t *testing.T) {
+	rc := &RunContext{
+		Config: &Config{},
+		Matrix: map[string]interface{}{"timeout": 1.5},
+		Run: &model.Run{
+			JobID: "job",
+			Workflow: &model.Workflow{
+				Jobs: map[string]*model.Job{"job": {}},
+			},
+		},
+	}
+	ctx := context.Background()
+	eval := rc.NewExpressionEvaluator(ctx)
+
+	for value, expected := range map[string]time.Duration{
+		"":                        0,
+		"10":                      10 * time.Minute,
+		"0.5":                     30 * time.Second,
+		"${{ matrix.timeout }}":   90 * time.Second,
+		"${{ matrix.undefined }}": 0,
+	} {
+		timeout, err := timeoutMinutes(ctx, eval, value)
+		assert.NoError(t, err, value)
+		assert.Equal(t, expected, timeout, value)
+	}
+
+	_, err := timeoutMinutes(ctx, eval, "-1")
+	assert.ErrorContains(t, err, "invalid timeout-minutes '-1'")
+
+	assert.Equal(t, "success", rc.getJobContext().Status)
+	rc.cancelled = true
+	assert.Equal(t, "cancelled", rc.getJobContext().Status)
+}
+
+func TestSetJobResult(t *testing.T) {
+	table := []struct {
+		name            string
+		continueOnError string
+		success         bool
+		cancelled       bool
+		result          string
+		continued       bool
+	}{
+		{"success", "", true, false, "success", false},
+		{"failure", "", false, false, "failure", false},
+		{"continue-on-error", "true", false, false, "success", true},
+		{"continue-on-error expression", "${{ matrix.experimental }}", false, false, "success", true},
+		{"continue-on-error false", "false", false, false, "failure", false},
+		{"continue-on-error without an error", "true", true, false, "success", false},
+		{"cancelled", "true", false, true, "cancelled", false},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			rc := &RunContext{
+				Config: &Config{},
+				Matrix: map[string]interface{}{"experimental": true},
+				Run: &model.Run{
+					JobID: "test",
+					Workflow: &model.Workflow{
+						Jobs: map[string]*model.Job{"test": {RawContinueOnError: tt.continueOnError}},
+					},
+				},
+				cancelled: tt.cancelled,
+			}
+			jim := &jobInfoMock{}
+			jim.On("matrix").Return(map[string]interface{}{})
+			jim.On("result", tt.result).Run(func(args mock.Arguments) {
+				rc.result(args.String(0))
+			})
+
+			ctx := WithResults(context.Background(), &Results{})
+			setJobResult(ctx, jim, rc, tt.success)
+			jim.AssertExpectations(t)
+			assert.Equal(t, tt.continued, rc.continuedOnError)
+
+			jr := &JobResult{JobID: "test"}
+			jr.collect(ctx, rc)
+			assert.Equal(t, tt.result, jr.Result)
+			assert.Equal(t, tt.continued, jr.Continued)
+		})
+	}
+}
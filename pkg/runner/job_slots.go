@@ -0,0 +1,37 @@
+package runner
+
+import (
+	"context"
+
+	"github.com/nektos/act/pkg/common"
+)
+
+// jobSlots limits the number of jobs running at once across the stages, the matrixes and the reusable workflows of
+// the run, see Config.MaxParallelJobs. A nil jobSlots doesn't limit the jobs.
+type jobSlots chan struct{}
+
+func newJobSlots(n int) jobSlots {
+	if n <= 0 {
+		return nil
+	}
+	return make(jobSlots, n)
+}
+
+// acquire waits for a free slot or the cancellation of ctx, and returns the function releasing the slot
+func (s jobSlots) acquire(ctx context.Context) (func(), error) {
+	if s == nil {
+		return func() {}, nil
+	}
+	select {
+	case s <- struct{}{}:
+		return func() { <-s }, nil
+	default:
+	}
+	common.Logger(ctx).Debugf("Waiting for one of the %d jobs running at once to finish", cap(s))
+	select {
+	case s <- struct{}{}:
+		return func() { <-s }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
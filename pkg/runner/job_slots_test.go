@@ -0,0 +1,29 @@
+package runner
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobSlots(t *testing.T) {
+	release, err := newJobSlots(0).acquire(context.Background())
+	assert.NoError(t, err)
+	release()
+
+	slots := newJobSlots(1)
+	release, err = slots.acquire(context.Background())
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = slots.acquire(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	release()
+	release, err = slots.acquire(context.Background())
+	assert.NoError(t, err)
+	release()
+}
@@ -0,0 +1,118 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/nektos/act/pkg/common"
+)
+
+// Limits of the hosted runners of GitHub
+const (
+	DefaultOutputSizeLimit = 1024 * 1024 // an output of a step and all outputs of a job, 1 MiB
+	DefaultEnvSizeLimit    = 128 * 1024  // a variable exported through GITHUB_ENV, the longest argument of exec on Linux
+)
+
+// Limits of the event payloads GitHub delivers
+const (
+	maxEventPayloadSize           = 25 * 1024 * 1024 // the payload of an event
+	maxWorkflowDispatchInputs     = 25               // the inputs of workflow_dispatch
+	maxWorkflowDispatchInputsSize = 65535            // the inputs of workflow_dispatch as JSON
+	maxClientPayloadProperties    = 10               // the top-level properties of client_payload of repository_dispatch
+)
+
+// checkEventPayloadLimits returns an error if GitHub wouldn't deliver the payload of the event, unlike the limits of
+// the runners these can't be exceeded on GitHub at all
+func checkEventPayloadLimits(eventName string, payload string) error {
+	if len(payload) > maxEventPayloadSize {
+		return fmt.Errorf("the event payload is %d bytes, GitHub doesn't deliver payloads larger than %d bytes", len(payload), maxEventPayloadSize)
+	}
+
+	var event struct {
+		Inputs        map[string]json.RawMessage `json:"inputs"`
+		ClientPayload map[string]json.RawMessage `json:"client_payload"`
+	}
+	if err := json.Unmarshal([]byte(payload), &event); err != nil {
+		// the payload doesn't have the fields of the events with limits
+		return nil
+	}
+	switch eventName {
+	case "workflow_dispatch":
+		if len(event.Inputs) > maxWorkflowDispatchInputs {
+			return fmt.Errorf("the workflow_dispatch event has %d inputs, GitHub accepts at most %d", len(event.Inputs), maxWorkflowDispatchInputs)
+		}
+		if inputs, err := json.Marshal(event.Inputs); err == nil && len(inputs) > maxWorkflowDispatchInputsSize {
+			return fmt.Errorf("the inputs of the workflow_dispatch event are %d characters, GitHub accepts at most %d", len(inputs), maxWorkflowDispatchInputsSize)
+		}
+	case "repository_dispatch":
+		if len(event.ClientPayload) > maxClientPayloadProperties {
+			return fmt.Errorf("the client_payload of the repository_dispatch event has %d top-level properties, GitHub accepts at most %d", len(event.ClientPayload), maxClientPayloadProperties)
+		}
+	}
+	return nil
+}
+
+// checkSizeLimit warns about a value larger than the limit, with --strict-limits it's an error
+func (rc *RunContext) checkSizeLimit(ctx context.Context, what string, size int, limit int) error {
+	if limit <= 0 || size <= limit {
+		return nil
+	}
+	msg := fmt.Sprintf("%s: %d bytes exceed the limit of %d bytes of the GitHub runners", what, size, limit)
+	if rc.Config.StrictLimits {
+		return errors.New(msg)
+	}
+	common.Logger(ctx).Warnf("  \U000026A0  %s, it works locally but fails on GitHub", msg)
+	return nil
+}
+
+// checkStepOutputsLimit checks the outputs of the step
+func (rc *RunContext) checkStepOutputsLimit(ctx context.Context, stepID string) error {
+	if result, ok := rc.StepResults[stepID]; ok {
+		for _, name := range sortedKeys(result.Outputs) {
+			what := fmt.Sprintf("output '%s' of step '%s'", name, stepID)
+			if err := rc.checkSizeLimit(ctx, what, len(result.Outputs[name]), rc.Config.OutputSizeLimit); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkExportedEnv checks the variables the step exported through GITHUB_ENV when it finishes, the variables
+// exported by the earlier steps of the job are not checked again
+func (rc *RunContext) checkExportedEnv(ctx context.Context, stepID string) error {
+	if rc.Config.EnvSizeLimit <= 0 {
+		return nil
+	}
+	// the steps of a composite action export to the GITHUB_ENV of the job
+	job := rc
+	for job.Parent != nil {
+		job = job.Parent
+	}
+	exported := rc.readExportedEnv(ctx)
+	previous := job.exportedEnv
+	job.exportedEnv = exported
+
+	for _, name := range sortedKeys(exported) {
+		if value, ok := previous[name]; ok && value == exported[name] {
+			// exported by an earlier step
+			continue
+		}
+		what := fmt.Sprintf("variable '%s' exported by step '%s'", name, stepID)
+		if err := rc.checkSizeLimit(ctx, what, len(exported[name]), rc.Config.EnvSizeLimit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkJobOutputsLimit checks the total size of the outputs of the job
+func (rc *RunContext) checkJobOutputsLimit(ctx context.Context, outputs map[string]string) error {
+	size := 0
+	for name, value := range outputs {
+		size += len(name) + len(value)
+	}
+	return rc.checkSizeLimit(ctx, fmt.Sprintf("outputs of job '%s'", rc.Run.JobID), size, rc.Config.OutputSizeLimit)
+}
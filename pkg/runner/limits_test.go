@@ -0,0 +1,81 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/nektos/act/pkg/model"
+)
+
+func TestCheckStepLimits(t *testing.T) {
+	ctx := context.Background()
+	cm := &containerMock{}
+	rc := &RunContext{
+		JobContainer: cm,
+		Config:       &Config{OutputSizeLimit: 8, EnvSizeLimit: 4},
+		Run: &model.Run{
+			JobID:    "build",
+			Workflow: &model.Workflow{Jobs: map[string]*model.Job{"build": {}}},
+		},
+		StepResults: map[string]*model.StepResult{
+			"step": {Outputs: map[string]string{"small": "1234", "large": strings.Repeat("x", 9)}},
+		},
+	}
+	exported := map[string]string{}
+	cm.On("UpdateFromEnv", "/var/run/act/workflow/envs.txt", mock.AnythingOfType("*map[string]string")).Run(func(args mock.Arguments) {
+		for k, v := range exported {
+			(*args.Get(1).(*map[string]string))[k] = v
+		}
+	}).Return(func(ctx context.Context) error { return nil })
+
+	// without --strict-limits the steps only warn
+	assert.NoError(t, rc.checkStepOutputsLimit(ctx, "step"))
+	exported = map[string]string{"OLD": "exported before", "NEW": "12345"}
+	assert.NoError(t, rc.checkExportedEnv(ctx, "step"))
+	assert.NoError(t, rc.checkJobOutputsLimit(ctx, map[string]string{"version": "1.0.0.0"}))
+
+	rc.Config.StrictLimits = true
+	assert.EqualError(t, rc.checkStepOutputsLimit(ctx, "step"), "output 'large' of step 'step': 9 bytes exceed the limit of 8 bytes of the GitHub runners")
+	rc.StepResults["step"].Outputs["large"] = ""
+	assert.NoError(t, rc.checkStepOutputsLimit(ctx, "step"))
+
+	rc.exportedEnv = map[string]string{"OLD": "exported before"}
+	assert.EqualError(t, rc.checkExportedEnv(ctx, "step"), "variable 'NEW' exported by step 'step': 5 bytes exceed the limit of 4 bytes of the GitHub runners")
+	// the variables exported by earlier steps are not checked again, the ones of the steps of a composite action are
+	assert.NoError(t, rc.checkExportedEnv(ctx, "next"))
+	composite := &RunContext{JobContainer: cm, Config: rc.Config, Parent: rc}
+	exported["COMPOSITE"] = "12345"
+	assert.EqualError(t, composite.checkExportedEnv(ctx, "nested"), "variable 'COMPOSITE' exported by step 'nested': 5 bytes exceed the limit of 4 bytes of the GitHub runners")
+	assert.NoError(t, composite.checkExportedEnv(ctx, "nested"))
+	assert.EqualError(t, rc.checkJobOutputsLimit(ctx, map[string]string{"version": "1.0.0.0"}), "outputs of job 'build': 14 bytes exceed the limit of 8 bytes of the GitHub runners")
+
+	rc.Config.OutputSizeLimit = 0
+	assert.NoError(t, rc.checkJobOutputsLimit(ctx, map[string]string{"version": "1.0.0.0"}))
+}
+
+func TestCheckEventPayloadLimits(t *testing.T) {
+	assert.NoError(t, checkEventPayloadLimits("push", `{"ref": "refs/heads/main"}`))
+	assert.NoError(t, checkEventPayloadLimits("push", `[]`))
+	assert.EqualError(t, checkEventPayloadLimits("push", strings.Repeat(" ", maxEventPayloadSize+1)),
+		"the event payload is 26214401 bytes, GitHub doesn't deliver payloads larger than 26214400 bytes")
+
+	inputs := make([]string, 0, maxWorkflowDispatchInputs+1)
+	for i := 0; i <= maxWorkflowDispatchInputs; i++ {
+		inputs = append(inputs, fmt.Sprintf(`"input%d": "value"`, i))
+	}
+	payload := fmt.Sprintf(`{"inputs": {%s}}`, strings.Join(inputs, ","))
+	assert.EqualError(t, checkEventPayloadLimits("workflow_dispatch", payload), "the workflow_dispatch event has 26 inputs, GitHub accepts at most 25")
+	// the limits of an event only apply to the event
+	assert.NoError(t, checkEventPayloadLimits("push", payload))
+	assert.EqualError(t, checkEventPayloadLimits("workflow_dispatch", fmt.Sprintf(`{"inputs": {"large": "%s"}}`, strings.Repeat("x", maxWorkflowDispatchInputsSize))),
+		"the inputs of the workflow_dispatch event are 65547 characters, GitHub accepts at most 65535")
+
+	clientPayload := `{"client_payload": {"a": 1, "b": 2, "c": 3, "d": 4, "e": 5, "f": 6, "g": 7, "h": 8, "i": 9, "j": 10, "k": 11}}`
+	assert.EqualError(t, checkEventPayloadLimits("repository_dispatch", clientPayload), "the client_payload of the repository_dispatch event has 11 top-level properties, GitHub accepts at most 10")
+	assert.NoError(t, checkEventPayloadLimits("repository_dispatch", `{"client_payload": {"nested": {"a": 1, "b": 2, "c": 3, "d": 4, "e": 5, "f": 6, "g": 7, "h": 8, "i": 9, "j": 10, "k": 11}}}`))
+}
@@ -0,0 +1,47 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/container"
+)
+
+// localRegistryName is the name of the container of the registry of Config.LocalRegistry
+const localRegistryName = "act-registry"
+
+// parseLocalRegistry returns the port of the address of the local registry, host:port
+func parseLocalRegistry(address string) (string, error) {
+	_, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return "", fmt.Errorf("invalid local registry '%s', expected host:port: %w", address, err)
+	}
+	if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+		return "", fmt.Errorf("invalid local registry '%s', the port isn't a number", address)
+	}
+	return port, nil
+}
+
+// localRegistryImage returns the reference of the image in the local registry, the image the containers of the
+// steps are created from so container engines other than the one which built it can pull it
+func localRegistryImage(registry string, image string) string {
+	return registry + "/" + image
+}
+
+// newLocalRegistryExecutor runs the throwaway registry the images built by act are pushed to while the plan runs
+func (runner *runnerImpl) newLocalRegistryExecutor(executor common.Executor) common.Executor {
+	port, _ := parseLocalRegistry(runner.config.LocalRegistry)
+
+	return container.NewDockerRegistryStartExecutor(localRegistryName, port).
+		Then(executor).
+		Finally(func(ctx context.Context) error {
+			// always allow 1 min for removing the registry, even if we were cancelled
+			ctx, cancel := context.WithTimeout(common.WithoutCancel(ctx), time.Minute)
+			defer cancel()
+			return container.NewDockerRegistryRemoveExecutor(localRegistryName)(ctx)
+		})
+}
@@ -0,0 +1,20 @@
+package runner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLocalRegistry(t *testing.T) {
+	port, err := parseLocalRegistry("localhost:5000")
+	assert.NoError(t, err)
+	assert.Equal(t, "5000", port)
+
+	_, err = parseLocalRegistry("localhost")
+	assert.ErrorContains(t, err, "invalid local registry 'localhost', expected host:port")
+	_, err = parseLocalRegistry("localhost:registry")
+	assert.EqualError(t, err, "invalid local registry 'localhost:registry', the port isn't a number")
+
+	assert.Equal(t, "192.168.1.10:5000/act-actions-hello-dockeraction:latest", localRegistryImage("192.168.1.10:5000", "act-actions-hello-dockeraction:latest"))
+}
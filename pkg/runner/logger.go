@@ -37,26 +37,6 @@ func init() {
 	}
 }
 
-type masksContextKey string
-
-const masksContextKeyVal = masksContextKey("logrus.FieldLogger")
-
-// Logger returns the appropriate logger for current context
-func Masks(ctx context.Context) *[]string {
-	val := ctx.Value(masksContextKeyVal)
-	if val != nil {
-		if masks, ok := val.(*[]string); ok {
-			return masks
-		}
-	}
-	return &[]string{}
-}
-
-// WithLogger adds a value to the context for the logger
-func WithMasks(ctx context.Context, masks *[]string) context.Context {
-	return context.WithValue(ctx, masksContextKeyVal, masks)
-}
-
 type JobLoggerFactory interface {
 	WithJobLogger() *logrus.Logger
 }
@@ -69,9 +49,13 @@ func WithJobLoggerFactory(ctx context.Context, factory JobLoggerFactory) context
 	return context.WithValue(ctx, jobLoggerFactoryContextKeyVal, factory)
 }
 
-// WithJobLogger attaches a new logger to context that is aware of steps
-func WithJobLogger(ctx context.Context, jobID string, jobName string, config *Config, masks *[]string, matrix map[string]interface{}) context.Context {
-	ctx = WithMasks(ctx, masks)
+// WithJobLogger attaches a new logger to context that is aware of steps,
+// masking the secrets of the config and the values of the masker, nil masks the secrets only
+func WithJobLogger(ctx context.Context, jobID string, jobName string, config *Config, masks *Masker, matrix map[string]interface{}) context.Context {
+	if masks == nil {
+		masks = NewMasker()
+	}
+	masks.Add(secretValues(config.Secrets)...)
 
 	var logger *logrus.Logger
 	if jobLoggerFactory, ok := ctx.Value(jobLoggerFactoryContextKeyVal).(JobLoggerFactory); ok && jobLoggerFactory != nil {
@@ -95,9 +79,17 @@ func WithJobLogger(ctx context.Context, jobID string, jobName string, config *Co
 		logger.SetFormatter(formatter)
 	}
 
+	// the sinks receive the entries of the job once the secrets are masked
+	for _, sink := range config.LogSinks {
+		logger.AddHook(&maskedHook{
+			Hook:   sink,
+			masker: valueMasker(config.InsecureSecrets, masks),
+		})
+	}
+
 	logger.SetFormatter(&maskedFormatter{
 		Formatter: logger.Formatter,
-		masker:    valueMasker(config.InsecureSecrets, config.Secrets),
+		masker:    valueMasker(config.InsecureSecrets, masks),
 	})
 	rtn := logger.WithFields(logrus.Fields{
 		"job":    jobName,
@@ -109,8 +101,7 @@ func WithJobLogger(ctx context.Context, jobID string, jobName string, config *Co
 	return common.WithLogger(ctx, rtn)
 }
 
-func WithCompositeLogger(ctx context.Context, masks *[]string) context.Context {
-	ctx = WithMasks(ctx, masks)
+func WithCompositeLogger(ctx context.Context) context.Context {
 	return common.WithLogger(ctx, common.Logger(ctx).WithFields(logrus.Fields{}).WithContext(ctx))
 }
 
@@ -142,30 +133,32 @@ func withStepLogger(ctx context.Context, stepID string, stepName string, stageNa
 
 type entryProcessor func(entry *logrus.Entry) *logrus.Entry
 
-func valueMasker(insecureSecrets bool, secrets map[string]string) entryProcessor {
+// valueMasker masks the message and the string fields of the entries, the fields end up in the JSON logs and sinks
+func valueMasker(insecureSecrets bool, masks *Masker) entryProcessor {
 	return func(entry *logrus.Entry) *logrus.Entry {
 		if insecureSecrets {
 			return entry
 		}
 
-		masks := Masks(entry.Context)
-
-		for _, v := range secrets {
-			if v != "" {
-				entry.Message = strings.ReplaceAll(entry.Message, v, "***")
-			}
-		}
-
-		for _, v := range *masks {
-			if v != "" {
-				entry.Message = strings.ReplaceAll(entry.Message, v, "***")
+		entry.Message = masks.Mask(entry.Message)
+		for k, v := range entry.Data {
+			if v, ok := v.(string); ok {
+				entry.Data[k] = masks.Mask(v)
 			}
 		}
-
 		return entry
 	}
 }
 
+// secretValues returns the values of the secrets
+func secretValues(secrets map[string]string) []string {
+	values := make([]string, 0, len(secrets))
+	for _, v := range secrets {
+		values = append(values, v)
+	}
+	return values
+}
+
 type maskedFormatter struct {
 	logrus.Formatter
 	masker entryProcessor
@@ -175,6 +168,16 @@ func (f *maskedFormatter) Format(entry *logrus.Entry) ([]byte, error) {
 	return f.Formatter.Format(f.masker(entry))
 }
 
+// maskedHook masks the secrets before passing the entry on to a log sink
+type maskedHook struct {
+	logrus.Hook
+	masker entryProcessor
+}
+
+func (h *maskedHook) Fire(entry *logrus.Entry) error {
+	return h.Hook.Fire(h.masker(entry))
+}
+
 type jobLogFormatter struct {
 	color int
 }
@@ -0,0 +1,44 @@
+package runner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/logsink"
+)
+
+type recordingSink struct {
+	messages []string
+}
+
+func (s *recordingSink) Levels() []logrus.Level { return logrus.AllLevels }
+func (s *recordingSink) Close() error           { return nil }
+func (s *recordingSink) Fire(entry *logrus.Entry) error {
+	s.messages = append(s.messages, entry.Message)
+	return nil
+}
+
+func TestWithJobLoggerSinks(t *testing.T) {
+	for name, ctx := range map[string]context.Context{
+		"default": context.Background(),
+		"factory": WithJobLoggerFactory(context.Background(), &maskJobLoggerFactory{}),
+	} {
+		t.Run(name, func(t *testing.T) {
+			sink := &recordingSink{}
+			config := &Config{
+				Secrets:  map[string]string{"TOKEN": "s3cret"},
+				LogSinks: []logsink.Sink{sink},
+			}
+			masks := NewMasker("masked-value")
+
+			ctx := WithJobLogger(ctx, "test", "test", config, masks, nil)
+			common.Logger(ctx).Infof("token s3cret and masked-value")
+
+			assert.Equal(t, []string{"token *** and ***"}, sink.messages)
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package runner
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Masker replaces the values masked in a run with *** in the logs of the run: the secrets and the values of
+// ::add-mask::. All jobs of a run share its masker, a value masked by one job is masked in the logs of the jobs
+// running concurrently as soon as it is added. It is safe for concurrent use.
+type Masker struct {
+	mu sync.RWMutex
+	// the values are never appended to in place, a copy is stored so the readers keep a consistent snapshot
+	values []string
+}
+
+// NewMasker creates a masker of the values
+func NewMasker(values ...string) *Masker {
+	m := &Masker{}
+	m.Add(values...)
+	return m
+}
+
+// Add masks the values from now on. The lines of a value are masked on their own, as the logs are written line by
+// line a multiline value never appears as a whole; blank lines are not masked.
+func (m *Masker) Add(values ...string) {
+	lines := make([]string, 0, len(values))
+	for _, value := range values {
+		for _, line := range strings.Split(value, "\n") {
+			line = strings.TrimSuffix(line, "\r")
+			if strings.TrimSpace(line) != "" {
+				lines = append(lines, line)
+			}
+		}
+	}
+	if len(lines) == 0 {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	updated := make([]string, len(m.values), len(m.values)+len(lines))
+	copy(updated, m.values)
+	for _, line := range lines {
+		if !containsValue(updated, line) {
+			updated = append(updated, line)
+		}
+	}
+	m.values = updated
+}
+
+func containsValue(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Masker) snapshot() []string {
+	if m == nil {
+		return nil
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.values
+}
+
+// Mask replaces the masked values in s with ***. Values which overlap, e.g. a token and a longer value containing
+// it, are replaced as a whole, replacing one value after the other would leave a part of the other value.
+func (m *Masker) Mask(s string) string {
+	type span struct{ start, end int }
+
+	spans := make([]span, 0)
+	for _, value := range m.snapshot() {
+		for i := 0; i+len(value) <= len(s); {
+			j := strings.Index(s[i:], value)
+			if j < 0 {
+				break
+			}
+			spans = append(spans, span{i + j, i + j + len(value)})
+			i += j + 1
+		}
+	}
+	if len(spans) == 0 {
+		return s
+	}
+	sort.Slice(spans, func(i, j int) bool {
+		return spans[i].start < spans[j].start
+	})
+
+	var b strings.Builder
+	last := 0
+	for i := 0; i < len(spans); {
+		start, end := spans[i].start, spans[i].end
+		for i++; i < len(spans) && spans[i].start < end; i++ {
+			if spans[i].end > end {
+				end = spans[i].end
+			}
+		}
+		b.WriteString(s[last:start])
+		b.WriteString("***")
+		last = end
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+// Contains reports whether s contains a masked value
+func (m *Masker) Contains(s string) bool {
+	for _, value := range m.snapshot() {
+		if strings.Contains(s, value) {
+			return true
+		}
+	}
+	return false
+}
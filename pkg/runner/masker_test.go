@@ -0,0 +1,167 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/logsink"
+)
+
+func TestMasker(t *testing.T) {
+	m := NewMasker("s3cret", "", "  ", "line1\r\nline2\n")
+
+	assert.Equal(t, "token *** and *** or ***", m.Mask("token s3cret and line1 or line2"))
+	assert.Equal(t, "******", m.Mask("s3crets3cret"))
+	assert.True(t, m.Contains("my s3cret"))
+	assert.False(t, m.Contains("nothing to mask"))
+
+	// blank values would mask every space of the logs
+	assert.Equal(t, "a  b", m.Mask("a  b"))
+
+	m.Add("s3cret")
+	assert.Len(t, m.snapshot(), 3)
+
+	var nilMasker *Masker
+	assert.Equal(t, "s3cret", nilMasker.Mask("s3cret"))
+	assert.False(t, nilMasker.Contains("s3cret"))
+}
+
+func TestMaskerOverlappingValues(t *testing.T) {
+	tables := []struct {
+		values   []string
+		message  string
+		expected string
+	}{
+		{[]string{"abc", "abcdef"}, "x abcdef x", "x *** x"},
+		{[]string{"abcdef", "abc"}, "x abcdef x", "x *** x"},
+		{[]string{"abcd", "cdef"}, "x abcdef x", "x *** x"},
+		{[]string{"xab", "abcdef"}, "xabcdef", "***"},
+		{[]string{"aa"}, "aaa", "***"},
+		{[]string{"ab"}, "ab ab", "*** ***"},
+	}
+	for _, table := range tables {
+		t.Run(strings.Join(table.values, ","), func(t *testing.T) {
+			assert.Equal(t, table.expected, NewMasker(table.values...).Mask(table.message))
+		})
+	}
+}
+
+func TestWithJobLoggerMasksFields(t *testing.T) {
+	var fields logrus.Fields
+	config := &Config{
+		Secrets:  map[string]string{"TOKEN": "s3cret"},
+		LogSinks: []logsink.Sink{&fieldsSink{fire: func(entry *logrus.Entry) { fields = entry.Data }}},
+	}
+
+	ctx := WithJobLogger(context.Background(), "test", "test", config, nil, nil)
+	common.Logger(ctx).WithField("step", "deploy with s3cret").Infof("done")
+
+	assert.Equal(t, "deploy with ***", fields["step"])
+}
+
+type fieldsSink struct {
+	fire func(entry *logrus.Entry)
+}
+
+func (s *fieldsSink) Levels() []logrus.Level { return logrus.AllLevels }
+func (s *fieldsSink) Close() error           { return nil }
+func (s *fieldsSink) Fire(entry *logrus.Entry) error {
+	s.fire(entry)
+	return nil
+}
+
+// lockedSink records the messages of the jobs writing concurrently
+type lockedSink struct {
+	mu      sync.Mutex
+	entries []lockedSinkEntry
+}
+
+type lockedSinkEntry struct {
+	message string
+	after   bool // written after the value was masked
+}
+
+func (s *lockedSink) Levels() []logrus.Level { return logrus.AllLevels }
+func (s *lockedSink) Close() error           { return nil }
+func (s *lockedSink) Fire(entry *logrus.Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	after, _ := entry.Data["after"].(bool)
+	s.entries = append(s.entries, lockedSinkEntry{message: entry.Message, after: after})
+	return nil
+}
+
+type discardLoggerFactory struct{}
+
+func (f *discardLoggerFactory) WithJobLogger() *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	logger.SetFormatter(&logrus.JSONFormatter{})
+	return logger
+}
+
+// FuzzMaskerInterleavedWrites writes the logs of concurrent jobs while one of them masks a value, no entry may
+// contain a secret and no entry written after the value was masked may contain the value
+func FuzzMaskerInterleavedWrites(f *testing.F) {
+	f.Add("s3cret", "tok-en", "line with ", uint8(4))
+	f.Add("abcd", "cdef", "xxabcdefxx", uint8(2))
+	f.Add("secret", "secret-value", "", uint8(7))
+	f.Add("pass\nword", "x", "x", uint8(1))
+
+	f.Fuzz(func(t *testing.T, secret string, mask string, message string, writers uint8) {
+		if strings.Contains(secret+mask, "*") || strings.TrimSpace(secret) == "" || strings.TrimSpace(mask) == "" {
+			t.Skip("the values have to be maskable and not part of ***")
+		}
+
+		sink := &lockedSink{}
+		config := &Config{
+			Secrets:  map[string]string{"SECRET": secret},
+			LogSinks: []logsink.Sink{sink},
+		}
+		masker := NewMasker()
+		ctx := WithJobLoggerFactory(context.Background(), &discardLoggerFactory{})
+
+		var masked int32
+		var wg sync.WaitGroup
+		for i := 0; i < 1+int(writers%8); i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				logger := common.Logger(WithJobLogger(ctx, fmt.Sprint(i), fmt.Sprintf("job-%d", i), config, masker, nil))
+				for k := 0; k < 20; k++ {
+					if i == 0 && k == 10 {
+						masker.Add(mask)
+						atomic.StoreInt32(&masked, 1)
+					}
+					after := atomic.LoadInt32(&masked) == 1
+					logger.WithField("after", after).Infof("%s%s%s%s", message, secret, message, mask)
+				}
+			}(i)
+		}
+		wg.Wait()
+
+		for _, entry := range sink.entries {
+			for _, line := range strings.Split(secret, "\n") {
+				if strings.TrimSpace(line) != "" {
+					assert.NotContains(t, entry.message, strings.TrimSuffix(line, "\r"))
+				}
+			}
+			if entry.after {
+				for _, line := range strings.Split(mask, "\n") {
+					if strings.TrimSpace(line) != "" {
+						assert.NotContains(t, entry.message, strings.TrimSuffix(line, "\r"))
+					}
+				}
+			}
+		}
+	})
+}
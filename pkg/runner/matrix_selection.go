@@ -0,0 +1,34 @@
+package runner
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseMatrixSelection parses the <key>:<value> specs selecting the legs of the matrix jobs to run (e.g. os:ubuntu-latest)
+// into a map of the keys to their selected values, a key given several times selects any of its values
+func ParseMatrixSelection(specs []string) (map[string]map[string]bool, error) {
+	selection := make(map[string]map[string]bool, len(specs))
+	for _, spec := range specs {
+		key, value, ok := strings.Cut(spec, ":")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid matrix selection '%s': expected <key>:<value>, e.g. os:ubuntu-latest", spec)
+		}
+		if selection[key] == nil {
+			selection[key] = map[string]bool{}
+		}
+		selection[key][value] = true
+	}
+	return selection, nil
+}
+
+// selectsMatrix reports whether the leg of a matrix is selected by Config.Matrix: all of its values of the selected keys
+// have to be selected. A leg without a selected key, e.g. a job without a matrix, is selected.
+func selectsMatrix(selection map[string]map[string]bool, matrix map[string]interface{}) bool {
+	for key, value := range matrix {
+		if values, ok := selection[key]; ok && !values[fmt.Sprint(value)] {
+			return false
+		}
+	}
+	return true
+}
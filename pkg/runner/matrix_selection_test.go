@@ -0,0 +1,50 @@
+package runner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMatrixSelection(t *testing.T) {
+	selection, err := ParseMatrixSelection([]string{"os:ubuntu-latest", "node:18", "node:20", "label:a:b", "empty:"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]map[string]bool{
+		"os":    {"ubuntu-latest": true},
+		"node":  {"18": true, "20": true},
+		"label": {"a:b": true},
+		"empty": {"": true},
+	}, selection)
+
+	_, err = ParseMatrixSelection([]string{"ubuntu-latest"})
+	assert.ErrorContains(t, err, "expected <key>:<value>")
+	_, err = ParseMatrixSelection([]string{":18"})
+	assert.ErrorContains(t, err, "expected <key>:<value>")
+}
+
+func TestSelectsMatrix(t *testing.T) {
+	selection := map[string]map[string]bool{
+		"os":   {"ubuntu-latest": true},
+		"node": {"18": true, "20": true},
+	}
+	tables := []struct {
+		name     string
+		matrix   map[string]interface{}
+		expected bool
+	}{
+		{"selected", map[string]interface{}{"os": "ubuntu-latest", "node": 18}, true},
+		{"any value of a key", map[string]interface{}{"os": "ubuntu-latest", "node": 20}, true},
+		{"other value", map[string]interface{}{"os": "windows-latest", "node": 18}, false},
+		{"other value of a key", map[string]interface{}{"os": "ubuntu-latest", "node": 16}, false},
+		{"unselected key", map[string]interface{}{"os": "ubuntu-latest", "node": "18", "arch": "arm64"}, true},
+		{"missing key", map[string]interface{}{"node": 18}, true},
+		{"no matrix", map[string]interface{}{}, true},
+	}
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			assert.Equal(t, table.expected, selectsMatrix(selection, table.matrix))
+		})
+	}
+
+	assert.True(t, selectsMatrix(nil, map[string]interface{}{"os": "windows-latest"}))
+}
@@ -0,0 +1,74 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/githubapi"
+	"github.com/nektos/act/pkg/model"
+)
+
+// newAPIProxyExecutor runs the GitHub API proxy for the duration of the executor, the jobs get a token which is
+// restricted to their `permissions:` with SimulatePermissions
+func (runner *runnerImpl) newAPIProxyExecutor(plan *model.Plan, executor common.Executor) common.Executor {
+	for _, stage := range plan.Stages {
+		for _, run := range stage.Runs {
+			if _, err := run.Job().Permissions(run.Workflow); err != nil {
+				return common.NewErrorExecutor(fmt.Errorf("invalid permissions of job '%s': %w", run.JobID, err))
+			}
+		}
+	}
+
+	return func(ctx context.Context) error {
+		urls, err := githubURLs(runner.config)
+		if err != nil {
+			return err
+		}
+		// the proxy binds to the address of the artifact server
+		proxy, err := githubapi.NewProxy(ctx, urls, runner.config.Token, runner.config.GraphQLFixtures, runner.config.ArtifactServerAddr, runner.hostAddress.Host)
+		if err != nil {
+			return fmt.Errorf("unable to start the GitHub API proxy: %w", err)
+		}
+		defer proxy.Close()
+
+		runner.apiProxy = proxy
+		return executor(ctx)
+	}
+}
+
+// registerAPIToken issues the GITHUB_TOKEN of an execution of the job, a job of a reusable workflow inherits
+// the permissions of the calling job unless it sets its own. The returned func revokes the token.
+func (rc *RunContext) registerAPIToken(ctx context.Context) (func(), error) {
+	if rc.apiProxy == nil {
+		return func() {}, nil
+	}
+	var permissions model.Permissions
+	if rc.Config.SimulatePermissions {
+		permissions, _ = rc.Run.Job().Permissions(rc.Run.Workflow)
+		if permissions == nil && rc.caller != nil {
+			permissions = rc.caller.runContext.permissions
+		}
+	}
+	token, err := rc.apiProxy.Register(ctx, permissions)
+	if err != nil {
+		return nil, err
+	}
+	rc.permissions = permissions
+	rc.apiToken = token
+	rc.AddMask(token)
+	// the expressions see the token as secrets.GITHUB_TOKEN
+	rc.ExprEval = rc.NewExpressionEvaluator(ctx)
+	return func() {
+		rc.apiProxy.Revoke(token)
+		rc.apiToken = ""
+	}, nil
+}
+
+// token returns the GITHUB_TOKEN of the job
+func (rc *RunContext) token() string {
+	if rc.apiToken != "" {
+		return rc.apiToken
+	}
+	return rc.Config.Token
+}
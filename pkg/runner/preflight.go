@@ -0,0 +1,240 @@
+package runner
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/container"
+	"github.com/nektos/act/pkg/model"
+)
+
+// preflightTools are the tools which are looked up in the images
+var preflightTools = []string{"bash", "git", "tar", "node", "python", "pwsh"}
+
+// imageRequirement is a tool which a step needs in the image of its job
+type imageRequirement struct {
+	tool   string
+	reason string // what fails without the tool
+}
+
+// newPreflightExecutor probes the images of the plan for the tools the jobs need and warns about the missing ones,
+// so an incompatible image is noticed before the jobs run. The probe never fails the run.
+func (runner *runnerImpl) newPreflightExecutor(plan *model.Plan) common.Executor {
+	return func(ctx context.Context) error {
+		if common.Dryrun(ctx) {
+			return nil
+		}
+		logger := common.Logger(ctx)
+
+		requirements := map[string][]imageRequirement{}
+		for _, stage := range plan.Stages {
+			for _, run := range stage.Runs {
+				job := run.Job()
+				if job.Type() != model.JobTypeDefault {
+					continue
+				}
+				// the legs of a matrix mostly run the same steps on a few images
+				probed := map[string]bool{}
+				for _, matrix := range job.GetMatrixes() {
+					rc := runner.newRunContext(ctx, run, matrix)
+					image := rc.platformImage(ctx)
+					if image == "" || strings.EqualFold(image, "-self-hosted") || probed[image] {
+						continue
+					}
+					probed[image] = true
+					requirements[image] = append(requirements[image], rc.imageRequirements(ctx)...)
+				}
+			}
+		}
+
+		images := make([]string, 0, len(requirements))
+		for image := range requirements {
+			images = append(images, image)
+		}
+		sort.Strings(images)
+
+		for _, image := range images {
+			tools, err := runner.probeImage(ctx, image)
+			if err != nil {
+				logger.Warnf("⚠  Unable to probe image %s: %v", image, err)
+				continue
+			}
+			for _, warning := range preflightWarnings(image, tools, requirements[image]) {
+				logger.Warnf("⚠  %s", warning)
+			}
+		}
+		return nil
+	}
+}
+
+// imageRequirements returns the tools the steps of the job need, it only looks at what is known before the job runs
+func (rc *RunContext) imageRequirements(ctx context.Context) []imageRequirement {
+	job := rc.Run.Job()
+	requirements := make([]imageRequirement, 0)
+	for i, step := range job.Steps {
+		if step == nil {
+			continue
+		}
+		step = githubScriptStep(step, rc)
+		name := step.String()
+		if name == "" {
+			name = strconv.Itoa(i)
+		}
+
+		switch step.Type() {
+		case model.StepTypeRun:
+			shell := step.Shell
+			if shell == "" {
+				shell = job.Defaults.Run.Shell
+			}
+			if shell == "" {
+				shell = rc.Run.Workflow.Defaults.Run.Shell
+			}
+			if shell == "" && job.Container() != nil && job.Container().Image != "" {
+				shell = "sh"
+			}
+			if fields := strings.Fields(rc.ExprEval.Interpolate(ctx, shell)); len(fields) > 0 {
+				shell = fields[0]
+			}
+			switch shell {
+			case "", "bash":
+				requirements = append(requirements, imageRequirement{"bash", fmt.Sprintf("run step '%s' will fail", name)})
+			case "python", "pwsh", "node":
+				requirements = append(requirements, imageRequirement{shell, fmt.Sprintf("run step '%s' will fail", name)})
+			}
+		case model.StepTypeUsesActionLocal:
+			if strings.HasPrefix(readActionUsing(filepath.Join(rc.Config.Workdir, step.Uses)), "node") {
+				requirements = append(requirements, imageRequirement{"node", fmt.Sprintf("JavaScript actions like %s will fail", step.Uses)})
+			}
+		case model.StepTypeUsesActionRemote:
+			remoteAction := newRemoteAction(step.Uses)
+			if remoteAction == nil {
+				continue
+			}
+			if remoteAction.IsCheckout() && isLocalCheckout(rc.getGithubContext(ctx), step) && !rc.Config.NoSkipCheckout {
+				// the workdir is copied instead
+				continue
+			}
+			if strings.HasPrefix(rc.remoteActionUsing(ctx, step, remoteAction), "node") {
+				requirements = append(requirements, imageRequirement{"node", fmt.Sprintf("JavaScript actions like %s will fail", step.Uses)})
+			}
+			if remoteAction.IsCheckout() {
+				requirements = append(requirements, imageRequirement{"git", "actions/checkout clone mode will fail"})
+			}
+			if remoteAction.Org == "actions" && remoteAction.Repo == "cache" {
+				requirements = append(requirements, imageRequirement{"tar", fmt.Sprintf("%s will fail", step.Uses)})
+			}
+		}
+	}
+	return requirements
+}
+
+// remoteActionUsing clones the action of the step into the action cache, where the job finds it later,
+// and returns its runs.using. It is empty if the action can't be read.
+func (rc *RunContext) remoteActionUsing(ctx context.Context, step *model.Step, remoteAction *remoteAction) string {
+	cloneInput := rc.actionCloneInput(step, remoteAction)
+	if err := rc.newActionCloneExecutor(step.Uses, remoteAction.Org, remoteAction.Repo, cloneInput)(ctx); err != nil {
+		common.Logger(ctx).Debugf("Unable to clone %s to look up its requirements: %v", step.Uses, err)
+		return ""
+	}
+	return readActionUsing(filepath.Join(cloneInput.Dir, remoteAction.Path))
+}
+
+// readActionUsing returns runs.using of the action in dir, it is empty if the action can't be read
+func readActionUsing(dir string) string {
+	for _, name := range []string{"action.yml", "action.yaml"} {
+		f, err := os.Open(filepath.Join(dir, name))
+		if err != nil {
+			continue
+		}
+		action, err := model.ReadAction(f)
+		f.Close()
+		if err != nil {
+			return ""
+		}
+		return string(action.Runs.Using)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "Dockerfile")); err == nil {
+		return "docker"
+	}
+	return ""
+}
+
+// probeImage starts a container of the image and returns the path of the tools found in it, and the node version as "node-version"
+func (runner *runnerImpl) probeImage(ctx context.Context, image string) (map[string]string, error) {
+	var output bytes.Buffer
+	probe := container.NewContainer(&container.NewContainerInput{
+		Entrypoint:  []string{"tail", "-f", "/dev/null"},
+		Image:       image,
+		Name:        createContainerName("act", "preflight", image),
+		NetworkMode: "none",
+		Stdout:      &output,
+		Stderr:      &output,
+		Platform:    runner.config.ContainerArchitecture,
+	})
+
+	script := fmt.Sprintf(`for tool in %s; do p=$(command -v $tool 2>/dev/null) && echo "$tool=$p"; done; `+
+		`command -v node >/dev/null 2>&1 && echo "node-version=$(node --version 2>/dev/null)"; true`, strings.Join(preflightTools, " "))
+	err := common.NewPipelineExecutor(
+		probe.Pull(runner.config.ForcePull),
+		probe.Create(nil, nil),
+		probe.Start(false),
+		probe.Exec([]string{"sh", "-c", script}, map[string]string{}, "", ""),
+	).Finally(probe.Remove()).Finally(probe.Close())(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tools := map[string]string{}
+	scanner := bufio.NewScanner(&output)
+	for scanner.Scan() {
+		if tool, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "="); ok {
+			tools[tool] = value
+		}
+	}
+	return tools, nil
+}
+
+// preflightWarnings returns a warning for every missing tool, the reasons of the same tool are combined
+func preflightWarnings(image string, tools map[string]string, requirements []imageRequirement) []string {
+	reasons := map[string][]string{}
+	missing := make([]string, 0)
+	for _, requirement := range requirements {
+		if _, ok := tools[requirement.tool]; ok {
+			continue
+		}
+		if _, ok := reasons[requirement.tool]; !ok {
+			missing = append(missing, requirement.tool)
+		}
+		if !common.ContainsString(reasons[requirement.tool], requirement.reason) {
+			reasons[requirement.tool] = append(reasons[requirement.tool], requirement.reason)
+		}
+	}
+
+	warnings := make([]string, 0, len(missing)+1)
+	for _, tool := range missing {
+		warnings = append(warnings, fmt.Sprintf("%s image lacks %s; %s", image, tool, strings.Join(reasons[tool], ", ")))
+	}
+
+	if version, ok := tools["node-version"]; ok {
+		for _, requirement := range requirements {
+			if requirement.tool != "node" {
+				continue
+			}
+			major, err := strconv.Atoi(strings.SplitN(strings.TrimPrefix(version, "v"), ".", 2)[0])
+			if err == nil && major < 16 {
+				warnings = append(warnings, fmt.Sprintf("%s image has node %s; actions running on node16 may fail", image, version))
+			}
+			break
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,103 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/common/git"
+	"github.com/nektos/act/pkg/model"
+)
+
+func TestImageRequirements(t *testing.T) {
+	workflow, err := model.ReadWorkflow(strings.NewReader(`
+on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v3
+        with:
+          repository: nektos/other
+      - uses: actions/checkout@v3
+      - uses: actions/cache@v3
+      - uses: nektos/docker-action@v1
+      - uses: nektos/unknown-action@v1
+      - uses: ./local-action
+      - run: make test
+      - run: print("hello")
+        shell: python
+      - run: echo
+        shell: sh
+`))
+	assert.NoError(t, err)
+
+	workdir := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	assert.NoError(t, os.MkdirAll(filepath.Join(workdir, "local-action"), 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(workdir, "local-action", "action.yml"), []byte("runs:\n  using: node16\n  main: index.js\n"), 0o600))
+
+	actions := map[string]string{
+		"actions/checkout":     "runs:\n  using: node16\n  main: index.js\n",
+		"actions/cache":        "runs:\n  using: node16\n  main: index.js\n",
+		"nektos/docker-action": "runs:\n  using: docker\n  image: Dockerfile\n",
+	}
+	origin := stepActionRemoteNewCloneExecutor
+	defer func() { stepActionRemoteNewCloneExecutor = origin }()
+	stepActionRemoteNewCloneExecutor = func(input git.NewGitCloneExecutorInput) common.Executor {
+		return func(ctx context.Context) error {
+			for repo, action := range actions {
+				if strings.HasSuffix(input.URL, repo) {
+					if err := os.MkdirAll(input.Dir, 0o755); err != nil {
+						return err
+					}
+					return os.WriteFile(filepath.Join(input.Dir, "action.yml"), []byte(action), 0o600)
+				}
+			}
+			return fmt.Errorf("repository not found")
+		}
+	}
+
+	rc := &RunContext{
+		Config:      &Config{Workdir: workdir, GitHubInstance: "github.com"},
+		Run:         &model.Run{JobID: "test", Workflow: workflow},
+		StepResults: map[string]*model.StepResult{},
+		Env:         map[string]string{},
+	}
+	rc.ExprEval = rc.NewExpressionEvaluator(context.Background())
+
+	tools := make([]string, 0)
+	for _, requirement := range rc.imageRequirements(context.Background()) {
+		tools = append(tools, requirement.tool)
+	}
+	// the local checkout copies the workdir, docker and unknown actions don't need node
+	assert.Equal(t, []string{"node", "git", "node", "tar", "node", "bash", "python"}, tools)
+}
+
+func TestPreflightWarnings(t *testing.T) {
+	requirements := []imageRequirement{
+		{"node", "JavaScript actions like actions/checkout@v3 will fail"},
+		{"git", "actions/checkout clone mode will fail"},
+		{"bash", "run step 'build' will fail"},
+		{"bash", "run step 'test' will fail"},
+	}
+
+	assert.Equal(t, []string{
+		"micro image lacks git; actions/checkout clone mode will fail",
+		"micro image lacks bash; run step 'build' will fail, run step 'test' will fail",
+		"micro image has node v12.22.1; actions running on node16 may fail",
+	}, preflightWarnings("micro", map[string]string{"node": "/usr/bin/node", "node-version": "v12.22.1"}, requirements))
+
+	assert.Empty(t, preflightWarnings("full", map[string]string{
+		"node":         "/usr/bin/node",
+		"node-version": "v16.20.0",
+		"git":          "/usr/bin/git",
+		"bash":         "/bin/bash",
+	}, requirements))
+}
@@ -0,0 +1,254 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/model"
+)
+
+// progress tracks the states of the jobs of a plan while it runs, to render why a job hasn't started yet
+type progress struct {
+	mu     sync.Mutex
+	plan   *model.Plan
+	jobs   map[*model.Run]*jobProgress
+	groups map[string]*model.Run // concurrency groups mapped to the job holding them
+	stage  int                   // index of the running stage, -1 before the first one
+	ncpu   int                   // jobs the scheduler runs in parallel
+	now    func() time.Time
+	logger func(ctx context.Context, format string, args ...interface{})
+}
+
+// jobProgress is the state of a job, a matrix job has several legs
+type jobProgress struct {
+	stage       int
+	maxParallel int
+	legs        int
+	running     int
+	done        int
+	skipped     int    // legs which were done without running, e.g. their if was false
+	group       string // concurrency group the job is queued in
+	queued      int    // legs waiting for the concurrency group
+	started     time.Time
+}
+
+func newProgress(plan *model.Plan) *progress {
+	p := &progress{
+		plan:   plan,
+		jobs:   map[*model.Run]*jobProgress{},
+		groups: map[string]*model.Run{},
+		stage:  -1,
+		now:    time.Now,
+		logger: func(ctx context.Context, format string, args ...interface{}) {
+			common.Logger(ctx).Infof(format, args...)
+		},
+	}
+	for i, stage := range plan.Stages {
+		for _, run := range stage.Runs {
+			p.jobs[run] = &jobProgress{stage: i}
+		}
+	}
+	return p
+}
+
+// stageStarted marks the stage as running, its jobs are scheduled
+func (p *progress) stageStarted(stage int, ncpu int) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.stage = stage
+	p.ncpu = ncpu
+}
+
+// jobScheduled records the legs of the matrix of the job and how many of them run in parallel
+func (p *progress) jobScheduled(run *model.Run, legs int, maxParallel int) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if job, ok := p.jobs[run]; ok {
+		job.legs = legs
+		job.maxParallel = maxParallel
+	}
+}
+
+// jobSkipped marks the job as done without running any of its legs
+func (p *progress) jobSkipped(run *model.Run) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if job, ok := p.jobs[run]; ok {
+		job.legs, job.done, job.skipped = 1, 1, 1
+	}
+}
+
+// legQueued marks a leg of the job as waiting for its concurrency group
+func (p *progress) legQueued(run *model.Run, group string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if job, ok := p.jobs[run]; ok {
+		job.group = group
+		job.queued++
+	}
+}
+
+// legStarted marks a leg of the job as running, holding the concurrency group it was queued in
+func (p *progress) legStarted(run *model.Run) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if job, ok := p.jobs[run]; ok {
+		if job.running == 0 && job.done == 0 {
+			job.started = p.now()
+		}
+		if job.queued > 0 {
+			job.queued--
+			p.groups[job.group] = run
+		}
+		job.running++
+	}
+}
+
+// legSkipped marks a running leg of the job as not run, it is finished with legFinished
+func (p *progress) legSkipped(run *model.Run) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if job, ok := p.jobs[run]; ok {
+		job.skipped++
+	}
+}
+
+// legFinished marks a leg of the job as done and releases its concurrency group
+func (p *progress) legFinished(run *model.Run) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if job, ok := p.jobs[run]; ok {
+		job.running--
+		job.done++
+		if job.group != "" && p.groups[job.group] == run {
+			delete(p.groups, job.group)
+		}
+	}
+}
+
+// state returns the state of the job and why it is in that state
+func (p *progress) state(run *model.Run) (string, string) {
+	job := p.jobs[run]
+	switch {
+	case job.legs > 0 && job.done == job.legs:
+		if job.skipped == job.legs {
+			return "done", "skipped"
+		}
+		if result := run.Job().Result; result != "" {
+			return "done", result
+		}
+		return "done", "finished without a result"
+	case job.running > 0:
+		reason := p.now().Sub(job.started).Round(time.Second).String()
+		if job.legs > 1 {
+			reason = fmt.Sprintf("%d/%d legs done, %d running, %s", job.done, job.legs, job.running, reason)
+		}
+		return "running", reason
+	case job.queued > 0:
+		if holder, ok := p.groups[job.group]; ok {
+			return "waiting", fmt.Sprintf("concurrency group %s held by %s/%s", job.group, holder.Workflow.File, holder.JobID)
+		}
+		return "waiting", fmt.Sprintf("concurrency group %s", job.group)
+	case job.stage == p.stage:
+		return "waiting", fmt.Sprintf("scheduler limits, max-parallel %d and %d jobs in parallel", job.maxParallel, p.ncpu)
+	}
+
+	pending := make([]string, 0)
+	for _, need := range run.Job().Needs() {
+		for other := range p.jobs {
+			if other.Workflow != run.Workflow || other.JobID != need {
+				continue
+			}
+			if state, _ := p.state(other); state != "done" {
+				pending = append(pending, fmt.Sprintf("%s (%s)", need, state))
+			}
+		}
+	}
+	if len(pending) > 0 {
+		sort.Strings(pending)
+		return "blocked", "needs " + strings.Join(pending, ", ")
+	}
+	return "queued", fmt.Sprintf("stage %d runs after stage %d", job.stage, job.stage-1)
+}
+
+// render logs the state of every job of the plan by stage
+func (p *progress) render(ctx context.Context) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	counts := map[string]int{}
+	lines := make([]string, 0, len(p.jobs))
+	for i, stage := range p.plan.Stages {
+		for _, run := range stage.Runs {
+			state, reason := p.state(run)
+			counts[state]++
+			lines = append(lines, fmt.Sprintf("  stage %d  %-8s %s/%s: %s", i, state, run.Workflow.File, run.JobID, reason))
+		}
+	}
+
+	summary := make([]string, 0, len(counts))
+	for _, state := range []string{"running", "waiting", "blocked", "queued", "done"} {
+		if counts[state] > 0 {
+			summary = append(summary, fmt.Sprintf("%d %s", counts[state], state))
+		}
+	}
+	p.logger(ctx, "\U0001F4CA  Jobs: %s", strings.Join(summary, ", "))
+	for _, line := range lines {
+		p.logger(ctx, "%s", line)
+	}
+}
+
+// newProgressExecutor renders the states of the jobs every Config.ProgressInterval while the plan runs
+func (runner *runnerImpl) newProgressExecutor(plan *model.Plan, executor common.Executor) common.Executor {
+	return func(ctx context.Context) error {
+		p := newProgress(plan)
+		runner.progress = p
+		defer func() {
+			runner.progress = nil
+		}()
+
+		done := make(chan struct{})
+		defer close(done)
+		go func() {
+			ticker := time.NewTicker(runner.config.ProgressInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-done:
+					return
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					p.render(ctx)
+				}
+			}
+		}()
+		return executor(ctx)
+	}
+}
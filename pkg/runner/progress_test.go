@@ -0,0 +1,131 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nektos/act/pkg/model"
+)
+
+func TestProgress(t *testing.T) {
+	workflow, err := model.ReadWorkflow(strings.NewReader(`
+on: push
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+    - run: echo
+  lint:
+    runs-on: ubuntu-latest
+    steps:
+    - run: echo
+  test:
+    runs-on: ubuntu-latest
+    needs: build
+    steps:
+    - run: echo
+  deploy:
+    runs-on: ubuntu-latest
+    needs: [test, lint]
+    steps:
+    - run: echo
+  docs:
+    runs-on: ubuntu-latest
+    needs: build
+    if: false
+    steps:
+    - run: echo
+  release:
+    runs-on: ubuntu-latest
+    needs: build
+    steps:
+    - run: echo
+`))
+	assert.NoError(t, err)
+	workflow.File = "ci.yml"
+	run := func(jobID string) *model.Run {
+		return &model.Run{Workflow: workflow, JobID: jobID}
+	}
+	build, lint, test, deploy := run("build"), run("lint"), run("test"), run("deploy")
+	docs, release := run("docs"), run("release")
+	plan := &model.Plan{Stages: []*model.Stage{
+		{Runs: []*model.Run{build, lint}},
+		{Runs: []*model.Run{test, docs, release}},
+		{Runs: []*model.Run{deploy}},
+	}}
+
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+	lines := []string{}
+	p := newProgress(plan)
+	p.now = func() time.Time { return now }
+	p.logger = func(ctx context.Context, format string, args ...interface{}) {
+		lines = append(lines, fmt.Sprintf(format, args...))
+	}
+
+	p.stageStarted(0, 2)
+	p.jobScheduled(build, 3, 2)
+	p.jobScheduled(lint, 1, 1)
+	p.legStarted(build)
+	p.legStarted(build)
+	now = start.Add(90 * time.Second)
+	p.render(context.Background())
+	assert.Equal(t, []string{
+		"\U0001F4CA  Jobs: 1 running, 1 waiting, 4 blocked",
+		"  stage 0  running  ci.yml/build: 0/3 legs done, 2 running, 1m30s",
+		"  stage 0  waiting  ci.yml/lint: scheduler limits, max-parallel 1 and 2 jobs in parallel",
+		"  stage 1  blocked  ci.yml/test: needs build (running)",
+		"  stage 1  blocked  ci.yml/docs: needs build (running)",
+		"  stage 1  blocked  ci.yml/release: needs build (running)",
+		"  stage 2  blocked  ci.yml/deploy: needs lint (waiting), test (blocked)",
+	}, lines)
+
+	for i := 0; i < 2; i++ {
+		p.legFinished(build)
+	}
+	p.legStarted(build)
+	p.legFinished(build)
+	p.legStarted(lint)
+	p.legFinished(lint)
+	build.Job().Result = "success"
+	lint.Job().Result = "failure"
+	p.stageStarted(1, 2)
+	p.jobScheduled(test, 1, 1)
+	p.jobScheduled(docs, 1, 1)
+	p.jobScheduled(release, 1, 1)
+	p.legQueued(test, "deploy")
+	p.legStarted(test)
+	p.legStarted(docs)
+	p.legSkipped(docs)
+	p.legFinished(docs)
+	p.legQueued(release, "deploy")
+
+	lines = []string{}
+	p.render(context.Background())
+	assert.Equal(t, []string{
+		"\U0001F4CA  Jobs: 1 running, 1 waiting, 1 blocked, 3 done",
+		"  stage 0  done     ci.yml/build: success",
+		"  stage 0  done     ci.yml/lint: failure",
+		"  stage 1  running  ci.yml/test: 0s",
+		"  stage 1  done     ci.yml/docs: skipped",
+		"  stage 1  waiting  ci.yml/release: concurrency group deploy held by ci.yml/test",
+		"  stage 2  blocked  ci.yml/deploy: needs test (running)",
+	}, lines)
+
+	p.legFinished(test)
+	lines = []string{}
+	p.render(context.Background())
+	assert.Equal(t, "  stage 1  done     ci.yml/test: finished without a result", lines[3])
+	assert.Equal(t, "  stage 1  waiting  ci.yml/release: concurrency group deploy", lines[5])
+
+	var nilProgress *progress
+	assert.NotPanics(t, func() {
+		nilProgress.stageStarted(0, 1)
+		nilProgress.legStarted(build)
+	})
+}
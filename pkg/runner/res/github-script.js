@@ -0,0 +1,426 @@
+// Runs the script of a step of actions/github-script without the action, so the workflows using it run offline.
+// ACT_GITHUB_SCRIPT is "api" to send the calls of octokit to GITHUB_API_URL, the GitHub API proxy of act if it runs,
+// or "noop" to only log them. The script gets the github, context, core, exec and require of the action.
+'use strict'
+const childProcess = require('child_process')
+const crypto = require('crypto')
+const fs = require('fs')
+const http = require('http')
+const https = require('https')
+const os = require('os')
+const path = require('path')
+
+const mode = process.env.ACT_GITHUB_SCRIPT || 'noop'
+
+function getInput (name, options) {
+  const value = process.env[`INPUT_${name.replace(/ /g, '_').toUpperCase()}`] || ''
+  if (options && options.required && !value) {
+    throw new Error(`Input required and not supplied: ${name}`)
+  }
+  if (options && options.trimWhitespace === false) {
+    return value
+  }
+  return value.trim()
+}
+
+function toCommandValue (value) {
+  if (value === undefined || value === null) {
+    return ''
+  }
+  if (typeof value === 'string' || value instanceof String) {
+    return String(value)
+  }
+  return JSON.stringify(value)
+}
+
+function escapeData (value) {
+  return toCommandValue(value).replace(/%/g, '%25').replace(/\r/g, '%0D').replace(/\n/g, '%0A')
+}
+
+function escapeProperty (value) {
+  return escapeData(value).replace(/:/g, '%3A').replace(/,/g, '%2C')
+}
+
+function issueCommand (command, message, properties) {
+  const props = Object.entries(properties || {})
+    .filter(([, value]) => value !== undefined && value !== null && value !== '')
+    .map(([key, value]) => `${key}=${escapeProperty(value)}`)
+    .join(',')
+  process.stdout.write(`::${command}${props ? ' ' + props : ''}::${escapeData(message)}${os.EOL}`)
+}
+
+// issueFileCommand appends the key and value to the file of the environment variable, it returns false without it
+function issueFileCommand (variable, key, value) {
+  const file = process.env[variable]
+  if (!file) {
+    return false
+  }
+  const delimiter = `ghadelimiter_${crypto.randomBytes(16).toString('hex')}`
+  fs.appendFileSync(file, `${key}<<${delimiter}${os.EOL}${toCommandValue(value)}${os.EOL}${delimiter}${os.EOL}`)
+  return true
+}
+
+function annotation (command) {
+  return (message, properties) => issueCommand(command, message instanceof Error ? message.toString() : message, properties)
+}
+
+const core = {
+  getInput,
+  getMultilineInput: (name, options) => getInput(name, options).split('\n').filter(line => line !== ''),
+  getBooleanInput (name, options) {
+    const value = getInput(name, options)
+    if (['true', 'True', 'TRUE'].includes(value)) {
+      return true
+    }
+    if (['false', 'False', 'FALSE'].includes(value)) {
+      return false
+    }
+    throw new TypeError(`Input does not meet YAML 1.2 "Core Schema" specification: ${name}`)
+  },
+  setOutput (name, value) {
+    if (!issueFileCommand('GITHUB_OUTPUT', name, value)) {
+      issueCommand('set-output', value, { name })
+    }
+  },
+  exportVariable (name, value) {
+    process.env[name] = toCommandValue(value)
+    if (!issueFileCommand('GITHUB_ENV', name, value)) {
+      issueCommand('set-env', value, { name })
+    }
+  },
+  addPath (dir) {
+    if (process.env.GITHUB_PATH) {
+      fs.appendFileSync(process.env.GITHUB_PATH, `${dir}${os.EOL}`)
+    } else {
+      issueCommand('add-path', dir)
+    }
+    process.env.PATH = `${dir}${path.delimiter}${process.env.PATH}`
+  },
+  saveState (name, value) {
+    if (!issueFileCommand('GITHUB_STATE', name, value)) {
+      issueCommand('save-state', value, { name })
+    }
+  },
+  getState: name => process.env[`STATE_${name}`] || '',
+  setSecret: secret => issueCommand('add-mask', secret),
+  setFailed (message) {
+    process.exitCode = 1
+    core.error(message)
+  },
+  isDebug: () => process.env.RUNNER_DEBUG === '1',
+  debug: message => issueCommand('debug', message),
+  info: message => process.stdout.write(`${message}${os.EOL}`),
+  notice: annotation('notice'),
+  warning: annotation('warning'),
+  error: annotation('error'),
+  startGroup: name => issueCommand('group', name),
+  endGroup: () => issueCommand('endgroup', ''),
+  async group (name, fn) {
+    core.startGroup(name)
+    try {
+      return await fn()
+    } finally {
+      core.endGroup()
+    }
+  }
+}
+
+function newContext () {
+  let payload = {}
+  if (process.env.GITHUB_EVENT_PATH && fs.existsSync(process.env.GITHUB_EVENT_PATH)) {
+    payload = JSON.parse(fs.readFileSync(process.env.GITHUB_EVENT_PATH, 'utf8'))
+  }
+  let owner = ''
+  let repo = ''
+  if (process.env.GITHUB_REPOSITORY) {
+    [owner, repo] = process.env.GITHUB_REPOSITORY.split('/')
+  } else if (payload.repository) {
+    owner = payload.repository.owner.login
+    repo = payload.repository.name
+  }
+  const apiUrl = process.env.GITHUB_API_URL || 'https://api.github.com'
+  return {
+    payload,
+    eventName: process.env.GITHUB_EVENT_NAME,
+    sha: process.env.GITHUB_SHA,
+    ref: process.env.GITHUB_REF,
+    workflow: process.env.GITHUB_WORKFLOW,
+    action: process.env.GITHUB_ACTION,
+    actor: process.env.GITHUB_ACTOR,
+    job: process.env.GITHUB_JOB,
+    runNumber: parseInt(process.env.GITHUB_RUN_NUMBER, 10),
+    runId: parseInt(process.env.GITHUB_RUN_ID, 10),
+    apiUrl,
+    serverUrl: process.env.GITHUB_SERVER_URL || 'https://github.com',
+    graphqlUrl: process.env.GITHUB_GRAPHQL_URL || `${apiUrl}/graphql`,
+    repo: { owner, repo },
+    issue: { owner, repo, number: (payload.issue || payload.pull_request || payload).number }
+  }
+}
+
+// routes of the methods of octokit the api mode sends, the other endpoints are called with github.request
+const routes = {
+  'actions.createWorkflowDispatch': 'POST /repos/{owner}/{repo}/actions/workflows/{workflow_id}/dispatches',
+  'actions.listWorkflowRunArtifacts': 'GET /repos/{owner}/{repo}/actions/runs/{run_id}/artifacts',
+  'checks.create': 'POST /repos/{owner}/{repo}/check-runs',
+  'git.createRef': 'POST /repos/{owner}/{repo}/git/refs',
+  'git.deleteRef': 'DELETE /repos/{owner}/{repo}/git/refs/{ref}',
+  'git.getRef': 'GET /repos/{owner}/{repo}/git/ref/{ref}',
+  'git.updateRef': 'PATCH /repos/{owner}/{repo}/git/refs/{ref}',
+  'issues.addAssignees': 'POST /repos/{owner}/{repo}/issues/{issue_number}/assignees',
+  'issues.addLabels': 'POST /repos/{owner}/{repo}/issues/{issue_number}/labels',
+  'issues.create': 'POST /repos/{owner}/{repo}/issues',
+  'issues.createComment': 'POST /repos/{owner}/{repo}/issues/{issue_number}/comments',
+  'issues.deleteComment': 'DELETE /repos/{owner}/{repo}/issues/comments/{comment_id}',
+  'issues.get': 'GET /repos/{owner}/{repo}/issues/{issue_number}',
+  'issues.listComments': 'GET /repos/{owner}/{repo}/issues/{issue_number}/comments',
+  'issues.listForRepo': 'GET /repos/{owner}/{repo}/issues',
+  'issues.listLabelsOnIssue': 'GET /repos/{owner}/{repo}/issues/{issue_number}/labels',
+  'issues.removeLabel': 'DELETE /repos/{owner}/{repo}/issues/{issue_number}/labels/{name}',
+  'issues.update': 'PATCH /repos/{owner}/{repo}/issues/{issue_number}',
+  'issues.updateComment': 'PATCH /repos/{owner}/{repo}/issues/comments/{comment_id}',
+  'pulls.create': 'POST /repos/{owner}/{repo}/pulls',
+  'pulls.createReview': 'POST /repos/{owner}/{repo}/pulls/{pull_number}/reviews',
+  'pulls.get': 'GET /repos/{owner}/{repo}/pulls/{pull_number}',
+  'pulls.list': 'GET /repos/{owner}/{repo}/pulls',
+  'pulls.listFiles': 'GET /repos/{owner}/{repo}/pulls/{pull_number}/files',
+  'pulls.requestReviewers': 'POST /repos/{owner}/{repo}/pulls/{pull_number}/requested_reviewers',
+  'pulls.update': 'PATCH /repos/{owner}/{repo}/pulls/{pull_number}',
+  'reactions.createForIssue': 'POST /repos/{owner}/{repo}/issues/{issue_number}/reactions',
+  'reactions.createForIssueComment': 'POST /repos/{owner}/{repo}/issues/comments/{comment_id}/reactions',
+  'repos.createCommitComment': 'POST /repos/{owner}/{repo}/commits/{commit_sha}/comments',
+  'repos.createCommitStatus': 'POST /repos/{owner}/{repo}/statuses/{sha}',
+  'repos.createDispatchEvent': 'POST /repos/{owner}/{repo}/dispatches',
+  'repos.createRelease': 'POST /repos/{owner}/{repo}/releases',
+  'repos.get': 'GET /repos/{owner}/{repo}',
+  'repos.getCollaboratorPermissionLevel': 'GET /repos/{owner}/{repo}/collaborators/{username}/permission',
+  'repos.getContent': 'GET /repos/{owner}/{repo}/contents/{path}',
+  'repos.listPullRequestsAssociatedWithCommit': 'GET /repos/{owner}/{repo}/commits/{commit_sha}/pulls',
+  'repos.listReleases': 'GET /repos/{owner}/{repo}/releases'
+}
+
+function send (method, url, headers, body) {
+  return new Promise((resolve, reject) => {
+    const target = new URL(url)
+    const client = target.protocol === 'https:' ? https : http
+    const req = client.request(target, { method, headers }, res => {
+      const chunks = []
+      res.on('data', chunk => chunks.push(chunk))
+      res.on('end', () => {
+        const text = Buffer.concat(chunks).toString('utf8')
+        let data = text
+        if ((res.headers['content-type'] || '').includes('json') && text !== '') {
+          data = JSON.parse(text)
+        }
+        const response = { status: res.statusCode, url, headers: res.headers, data }
+        if (res.statusCode >= 400) {
+          const err = new Error(`${method} ${url} failed with ${res.statusCode}: ${typeof data === 'string' ? data : data.message}`)
+          err.status = res.statusCode
+          err.response = response
+          reject(err)
+          return
+        }
+        resolve(response)
+      })
+    })
+    req.on('error', reject)
+    if (body !== undefined) {
+      req.write(body)
+    }
+    req.end()
+  })
+}
+
+function newOctokit (context) {
+  const token = getInput('github-token')
+  const baseUrl = (getInput('base-url') || context.apiUrl).replace(/\/$/, '')
+  const graphqlUrl = getInput('base-url') ? `${baseUrl}/graphql` : context.graphqlUrl
+  const headers = {
+    accept: 'application/vnd.github+json',
+    'content-type': 'application/json; charset=utf-8',
+    'user-agent': getInput('user-agent') || 'actions/github-script (act)'
+  }
+  if (token) {
+    headers.authorization = `token ${token}`
+  }
+
+  async function request (route, parameters) {
+    if (typeof route === 'object') {
+      parameters = route
+      route = `${route.method || 'GET'} ${route.url}`
+    }
+    let [method, url] = route.includes(' ') ? route.split(' ') : ['GET', route]
+    const params = Object.assign({}, parameters)
+    const requestHeaders = Object.assign({}, headers, params.headers)
+    for (const key of ['headers', 'request', 'mediaType', 'method', 'url']) {
+      delete params[key]
+    }
+    url = url.replace(/\{(\w+)\}/g, (_, name) => {
+      const value = params[name]
+      delete params[name]
+      return String(value).split('/').map(encodeURIComponent).join('/')
+    })
+    if (mode === 'noop') {
+      core.info(`github-script shim of act: ${method} ${url} is not sent`)
+      return { status: 200, url, headers: {}, data: {} }
+    }
+    const target = new URL(/^https?:/.test(url) ? url : `${baseUrl}${url}`)
+    let body
+    if (method === 'GET' || method === 'HEAD') {
+      for (const [key, value] of Object.entries(params)) {
+        target.searchParams.set(key, Array.isArray(value) ? value.join(',') : value)
+      }
+    } else {
+      const data = 'data' in params ? params.data : params
+      body = typeof data === 'string' ? data : JSON.stringify(data)
+    }
+    return send(method, target.toString(), requestHeaders, body)
+  }
+
+  async function graphql (query, parameters) {
+    if (typeof query === 'object') {
+      parameters = query
+      query = query.query
+    }
+    const variables = Object.assign({}, parameters)
+    for (const key of ['query', 'headers', 'request', 'mediaType']) {
+      delete variables[key]
+    }
+    if (mode === 'noop') {
+      core.info(`github-script shim of act: the GraphQL query is not sent${os.EOL}${query}`)
+      return {}
+    }
+    const response = await send('POST', graphqlUrl, headers, JSON.stringify({ query, variables }))
+    if (response.data.errors && response.data.errors.length > 0) {
+      const err = new Error(`Request failed due to following response errors:${os.EOL}${response.data.errors.map(e => ` - ${e.message}`).join(os.EOL)}`)
+      err.errors = response.data.errors
+      err.data = response.data.data
+      throw err
+    }
+    return response.data.data
+  }
+
+  function endpoint (name) {
+    return parameters => {
+      if (mode === 'noop') {
+        core.info(`github-script shim of act: github.rest.${name}(${JSON.stringify(parameters || {})}) is not sent`)
+        return Promise.resolve({ status: 200, url: '', headers: {}, data: {} })
+      }
+      if (!routes[name]) {
+        return Promise.reject(new Error(`github.rest.${name} isn't supported by the github-script shim of act, call it with github.request('<METHOD> <route>', parameters)`))
+      }
+      return request(routes[name], parameters)
+    }
+  }
+
+  // paginate follows the next links of the responses and returns the items of all the pages
+  async function paginate (method, parameters, map) {
+    if (typeof parameters === 'function') {
+      map = parameters
+      parameters = {}
+    }
+    const items = []
+    let response = await (typeof method === 'function' ? method(parameters) : request(method, parameters))
+    for (;;) {
+      const data = Array.isArray(response.data) ? response.data : Object.values(response.data).find(Array.isArray) || []
+      items.push(...(map ? map(Object.assign({}, response, { data })) : data))
+      const next = /<([^>]+)>;\s*rel="next"/.exec((response.headers && response.headers.link) || '')
+      if (!next) {
+        return items
+      }
+      response = await request(`GET ${next[1]}`)
+    }
+  }
+
+  const namespace = name => new Proxy({}, {
+    get: (_, method) => typeof method === 'string' && method !== 'then' ? endpoint(`${name}.${method}`) : undefined
+  })
+  const rest = new Proxy({}, {
+    get: (_, name) => typeof name === 'string' && name !== 'then' ? namespace(name) : undefined
+  })
+  const octokit = { request, graphql, paginate, rest, log: { debug: core.debug, info: core.info, warn: core.warning, error: core.error } }
+  // the methods of the namespaces are members of octokit too, like with octokit v4
+  return new Proxy(octokit, {
+    get: (target, name) => name in target || typeof name !== 'string' || name === 'then' ? target[name] : rest[name]
+  })
+}
+
+function getExecOutput (commandLine, args, options) {
+  args = args || []
+  options = options || {}
+  return new Promise((resolve, reject) => {
+    const child = childProcess.spawn(commandLine, args, {
+      cwd: options.cwd,
+      env: options.env || process.env,
+      shell: args.length === 0,
+      stdio: [options.input ? 'pipe' : 'inherit', 'pipe', 'pipe']
+    })
+    if (options.input) {
+      child.stdin.end(options.input)
+    }
+    let stdout = ''
+    let stderr = ''
+    child.stdout.on('data', data => {
+      stdout += data
+      if (!options.silent) {
+        process.stdout.write(data)
+      }
+    })
+    child.stderr.on('data', data => {
+      stderr += data
+      if (!options.silent) {
+        process.stderr.write(data)
+      }
+    })
+    child.on('error', reject)
+    child.on('close', exitCode => {
+      if (exitCode !== 0 && !options.ignoreReturnCode) {
+        reject(new Error(`The process '${commandLine}' failed with exit code ${exitCode}`))
+        return
+      }
+      resolve({ exitCode, stdout, stderr })
+    })
+  })
+}
+
+const exec = {
+  exec: (commandLine, args, options) => getExecOutput(commandLine, args, options).then(output => output.exitCode),
+  getExecOutput
+}
+
+function unsupported (name) {
+  return new Proxy({}, {
+    get: (_, member) => {
+      if (typeof member !== 'string' || member === 'then') {
+        return undefined
+      }
+      return () => {
+        throw new Error(`${name}.${member} isn't available in the github-script shim of act`)
+      }
+    }
+  })
+}
+
+// requireWorkspace resolves the modules like the action, relative to the working directory
+function requireWorkspace (id) {
+  return require(require.resolve(id, { paths: [process.cwd()] }))
+}
+
+async function main () {
+  const script = getInput('script', { required: true })
+  const encoding = getInput('result-encoding') || 'json'
+  if (encoding !== 'json' && encoding !== 'string') {
+    throw new Error('"result-encoding" must be either "string" or "json"')
+  }
+  const context = newContext()
+  const github = newOctokit(context)
+  const AsyncFunction = Object.getPrototypeOf(async () => null).constructor
+  const fn = new AsyncFunction('require', '__original_require__', 'github', 'octokit', 'context', 'core', 'exec', 'glob', 'io', script)
+  const result = await fn(requireWorkspace, require, github, github, context, core, exec, unsupported('glob'), unsupported('io'))
+  core.setOutput('result', encoding === 'string' ? String(result) : JSON.stringify(result))
+}
+
+main().catch(err => {
+  console.error(err)
+  core.setFailed(`Unhandled error: ${err}`)
+})
@@ -0,0 +1,122 @@
+package runner
+
+import (
+	"context"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/model"
+)
+
+// JobResult is the outcome of a single job (or matrix leg) of a run
+type JobResult struct {
+	JobID         string
+	Workflow      string // file of the workflow
+	Name          string
+	RunName       string // title of the run, the evaluated run-name of the workflow
+	LogPrefix     string // prefix of the log lines of the job, the jobName field of the JSON logs
+	RunID         string // GITHUB_RUN_ID
+	ArtifactRunID string // the run of the artifacts of the job, GITHUB_RUN_ID namespaced by --workflow-prefix file
+	Matrix        map[string]interface{}
+	Result        string
+	Continued     bool // the job failed but continued on error, its Result is success like on GitHub
+	Outputs       map[string]string
+	Steps         map[string]*model.StepResult
+	StepDurations map[string]time.Duration // how long the steps ran, up to the cancellation of the job for a cancelled step
+	Env           map[string]string        // variables exported through GITHUB_ENV
+	Unsupported   []*UnsupportedStep       // steps skipped by --skip-unsupported
+	Summary       string                   // the markdown the steps wrote to GITHUB_STEP_SUMMARY
+}
+
+// Results collects the JobResult of every job which was run with the context
+type Results struct {
+	mu   sync.Mutex
+	Jobs []*JobResult
+}
+
+type resultsContextKey string
+
+const resultsContextKeyVal = resultsContextKey("runner.results")
+
+// WithResults adds a Results collector to the context
+func WithResults(ctx context.Context, results *Results) context.Context {
+	return context.WithValue(ctx, resultsContextKeyVal, results)
+}
+
+// ResultsFrom returns the Results collector of the context, nil if the results of the jobs are not collected
+func ResultsFrom(ctx context.Context) *Results {
+	results, _ := ctx.Value(resultsContextKeyVal).(*Results)
+	return results
+}
+
+// Job returns the results of all runs of a job, a matrix job has a result for every leg
+func (r *Results) Job(jobID string) []*JobResult {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	jobs := make([]*JobResult, 0)
+	for _, job := range r.Jobs {
+		if job.JobID == jobID {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs
+}
+
+// newJobResult starts collecting the result of the job, it returns nil if no results are collected.
+// It has to be called while the job container is still running to read the exported variables.
+func newJobResult(ctx context.Context, rc *RunContext) *JobResult {
+	if _, ok := ctx.Value(resultsContextKeyVal).(*Results); !ok || rc.Run == nil {
+		return nil
+	}
+
+	runID := rc.getGithubContext(ctx).RunID
+	return &JobResult{
+		JobID:         rc.Run.JobID,
+		Workflow:      rc.Run.Workflow.File,
+		Name:          rc.String(),
+		RunName:       rc.runName(ctx),
+		LogPrefix:     rc.logPrefix(),
+		RunID:         runID,
+		ArtifactRunID: common.NamespacedRunID(runID, rc.artifactNamespace()),
+		Matrix:        rc.Matrix,
+		Steps:         rc.StepResults,
+		Env:           rc.readExportedEnv(ctx),
+	}
+}
+
+// readExportedEnv reads the variables exported through GITHUB_ENV by the steps of the job, the job container has to be
+// running
+func (rc *RunContext) readExportedEnv(ctx context.Context) map[string]string {
+	env := map[string]string{}
+	if rc.JobContainer != nil {
+		envPath := path.Join(rc.JobContainer.GetActPath(), "workflow", "envs.txt")
+		if err := rc.JobContainer.UpdateFromEnv(envPath, &env)(ctx); err != nil {
+			common.Logger(ctx).Debugf("unable to read exported variables: %v", err)
+		}
+	}
+	return env
+}
+
+// collect completes the result with the conclusion and outputs of the job and adds it to the results
+func (jr *JobResult) collect(ctx context.Context, rc *RunContext) {
+	if jr == nil {
+		return
+	}
+	results := ctx.Value(resultsContextKeyVal).(*Results)
+
+	job := rc.Run.Job()
+	jr.Result = job.Result
+	jr.Continued = rc.continuedOnError
+	jr.Outputs = rc.evaluateOutputs(ctx)
+	jr.StepDurations = rc.stepDurations
+	jr.Unsupported = rc.unsupportedSteps
+	jr.Summary = strings.Join(rc.stepSummaries, "\n")
+
+	results.mu.Lock()
+	defer results.mu.Unlock()
+	results.Jobs = append(results.Jobs, jr)
+}
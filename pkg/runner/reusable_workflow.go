@@ -8,11 +8,13 @@ import (
 	"os"
 	"path"
 	"regexp"
+	"strconv"
 	"strings"
 	"sync"
 
 	"github.com/nektos/act/pkg/common"
 	"github.com/nektos/act/pkg/common/git"
+	"github.com/nektos/act/pkg/exprparser"
 	"github.com/nektos/act/pkg/model"
 )
 
@@ -27,7 +29,7 @@ func newRemoteReusableWorkflowExecutor(rc *RunContext) common.Executor {
 	if remoteReusableWorkflow == nil {
 		return common.NewErrorExecutor(fmt.Errorf("expected format {owner}/{repo}/.github/workflows/{filename}@{ref}. Actual '%s' Input string was not in a correct format", uses))
 	}
-	remoteReusableWorkflow.URL = rc.Config.GitHubInstance
+	remoteReusableWorkflow.URL = githubServerURL(rc.Config)
 
 	workflowDir := fmt.Sprintf("%s/%s", rc.ActionCacheDir(), strings.ReplaceAll(uses, "/", "-"))
 
@@ -51,10 +53,17 @@ func newMutexExecutor(executor common.Executor) common.Executor {
 }
 
 func cloneIfRequired(rc *RunContext, remoteReusableWorkflow remoteReusableWorkflow, targetDirectory string) common.Executor {
+	if vendored := rc.vendored(remoteReusableWorkflow.Org, remoteReusableWorkflow.Repo, remoteReusableWorkflow.Ref); vendored != "" {
+		return newVendoredCopyExecutor(rc.Run.Job().Uses, vendored, targetDirectory)
+	}
 	return common.NewConditionalExecutor(
 		func(ctx context.Context) bool {
 			_, err := os.Stat(targetDirectory)
 			notExists := errors.Is(err, fs.ErrNotExist)
+			if _, err := os.Stat(path.Join(targetDirectory, vendoredMarker)); err == nil {
+				// the copy of a repository which isn't vendored anymore
+				return os.RemoveAll(targetDirectory) == nil
+			}
 			return notExists
 		},
 		git.NewGitCloneExecutor(git.NewGitCloneExecutorInput{
@@ -64,7 +73,10 @@ func cloneIfRequired(rc *RunContext, remoteReusableWorkflow remoteReusableWorkfl
 			Token: rc.Config.Token,
 		}),
 		nil,
-	)
+	).Finally(func(ctx context.Context) error {
+		common.TouchCacheEntry(targetDirectory)
+		return nil
+	})
 }
 
 func newReusableWorkflowExecutor(rc *RunContext, directory string, workflow string) common.Executor {
@@ -75,13 +87,135 @@ func newReusableWorkflowExecutor(rc *RunContext, directory string, workflow stri
 		}
 
 		plan := planner.PlanEvent("workflow_call")
+		called := planWorkflow(plan)
+		if called == nil {
+			return fmt.Errorf("the reusable workflow %s has no jobs", workflow)
+		}
+		if called.WorkflowCallConfig() == nil {
+			return fmt.Errorf("the workflow %s is not reusable, it has no 'on: workflow_call' trigger", workflow)
+		}
+		if err := validateWorkflowCall(called, rc.workflowCallInputs(ctx), rc.workflowCallSecrets(ctx)); err != nil {
+			return err
+		}
 
 		runner, err := NewReusableWorkflowRunner(rc)
 		if err != nil {
 			return err
 		}
 
-		return runner.NewPlanExecutor(plan)(ctx)
+		err = runner.NewPlanExecutor(plan)(ctx)
+		rc.setWorkflowCallResult(workflowCallResult(called))
+		rc.setWorkflowCallOutputs(ctx, called)
+		return err
+	}
+}
+
+// planWorkflow returns the workflow of the jobs of a plan of a single workflow, nil if the plan has no jobs
+func planWorkflow(plan *model.Plan) *model.Workflow {
+	for _, stage := range plan.Stages {
+		for _, run := range stage.Runs {
+			return run.Workflow
+		}
+	}
+	return nil
+}
+
+// workflowCallInputs returns the inputs the job passes to the reusable workflow with `with:`, evaluated by the job
+func (rc *RunContext) workflowCallInputs(ctx context.Context) map[string]interface{} {
+	inputs := make(map[string]interface{}, len(rc.Run.Job().With))
+	for name, value := range rc.Run.Job().With {
+		if str, ok := value.(string); ok {
+			value = rc.ExprEval.Interpolate(ctx, str)
+		}
+		inputs[name] = value
+	}
+	return inputs
+}
+
+// workflowCallSecrets returns the names of the secrets the job passes to the reusable workflow, all secrets of the
+// job with `secrets: inherit`
+func (rc *RunContext) workflowCallSecrets(ctx context.Context) map[string]string {
+	job := rc.Run.Job()
+	secrets := job.Secrets()
+	if secrets == nil && job.InheritSecrets() {
+		secrets = getWorkflowSecrets(ctx, rc)
+	}
+	if secrets == nil {
+		secrets = map[string]string{}
+	}
+	return secrets
+}
+
+// workflowCallResult returns the result of the job calling the reusable workflow: it failed if one of the jobs of
+// the workflow failed, it was cancelled if one of them was cancelled
+func workflowCallResult(workflow *model.Workflow) string {
+	result := "success"
+	for _, job := range workflow.Jobs {
+		switch job.Result {
+		case "failure":
+			return "failure"
+		case "cancelled":
+			result = "cancelled"
+		}
+	}
+	return result
+}
+
+// setWorkflowCallResult sets the result of the job calling the reusable workflow. Like the legs of a matrix job
+// running steps, a leg of a matrix calling the workflow doesn't hide the failure or the cancellation of another leg.
+func (rc *RunContext) setWorkflowCallResult(result string) {
+	current := rc.Run.Job().Result
+	if len(rc.Matrix) > 0 && (current == "failure" || current == "cancelled" && result == "success") {
+		return
+	}
+	rc.result(result)
+}
+
+// setWorkflowCallOutputs sets the outputs of the job calling the reusable workflow to the outputs the workflow
+// declares in on.workflow_call.outputs, their values are evaluated with the jobs context of the workflow. The legs of
+// a matrix calling the workflow merge their outputs like the legs of a matrix job running steps.
+func (rc *RunContext) setWorkflowCallOutputs(ctx context.Context, workflow *model.Workflow) {
+	config := workflow.WorkflowCallConfig()
+	jobs := make(map[string]exprparser.Needs, len(workflow.Jobs))
+	jobOutputsMutex.Lock()
+	for id, job := range workflow.Jobs {
+		outputs := make(map[string]string, len(job.Outputs))
+		for k, v := range job.Outputs {
+			outputs[k] = v
+		}
+		jobs[id] = exprparser.Needs{Outputs: outputs, Result: job.Result}
+	}
+	jobOutputsMutex.Unlock()
+
+	ee := expressionEvaluator{
+		interpreter: exprparser.NewInterpeter(&exprparser.EvaluationEnvironment{
+			Github: rc.getGithubContext(ctx),
+			Jobs:   jobs,
+			Inputs: rc.workflowCallInputs(ctx),
+			Vars:   rc.Config.ContextOverrides.vars(),
+		}, exprparser.Config{
+			Run:        rc.Run,
+			WorkingDir: rc.Config.Workdir,
+			Context:    "workflow",
+		}),
+	}
+	outputs := make(map[string]string, len(config.Outputs))
+	for name, output := range config.Outputs {
+		outputs[name] = ee.Interpolate(ctx, output.Value)
+	}
+
+	jobOutputsMutex.Lock()
+	defer jobOutputsMutex.Unlock()
+	job := rc.Run.Job()
+	if len(rc.Matrix) == 0 || job.Outputs == nil {
+		job.Outputs = outputs
+		return
+	}
+	for k, v := range outputs {
+		// like on GitHub an empty value doesn't overwrite the output set by another leg of the matrix
+		if _, ok := job.Outputs[k]; v != "" || !ok {
+			job.Outputs[k] = v
+		}
 	}
 }
 
@@ -92,6 +226,14 @@ func NewReusableWorkflowRunner(rc *RunContext) (Runner, error) {
 		caller: &caller{
 			runContext: rc,
 		},
+		apiProxy:       rc.apiProxy,
+		hostAddress:    rc.hostAddress,
+		sshAgentSocket: rc.sshAgentSocket,
+		concurrency:    rc.concurrency,
+		concurrencyRun: rc.concurrencyRun,
+		jobSlots:       rc.jobSlots,
+		composeProject: rc.composeProject,
+		masker:         rc.Masks,
 	}
 
 	return runner.configure()
@@ -105,8 +247,48 @@ type remoteReusableWorkflow struct {
 	Ref      string
 }
 
+// validateWorkflowCall checks the inputs and secrets passed to a reusable workflow which is run standalone
+func validateWorkflowCall(workflow *model.Workflow, inputs map[string]interface{}, secrets map[string]string) error {
+	config := workflow.WorkflowCallConfig()
+	if config == nil {
+		return nil
+	}
+
+	for name := range inputs {
+		if _, ok := config.Inputs[name]; !ok {
+			return fmt.Errorf("workflow '%s' does not declare the input '%s'", workflow.File, name)
+		}
+	}
+	for name, input := range config.Inputs {
+		value, ok := inputs[name]
+		if !ok {
+			if input.Required {
+				return fmt.Errorf("workflow '%s' requires the input '%s'", workflow.File, name)
+			}
+			continue
+		}
+		str := fmt.Sprint(value)
+		switch input.Type {
+		case "boolean":
+			if str != "true" && str != "false" {
+				return fmt.Errorf("input '%s' of workflow '%s' must be a boolean, got '%s'", name, workflow.File, str)
+			}
+		case "number":
+			if _, err := strconv.ParseFloat(str, 64); err != nil {
+				return fmt.Errorf("input '%s' of workflow '%s' must be a number, got '%s'", name, workflow.File, str)
+			}
+		}
+	}
+	for name, secret := range config.Secrets {
+		if _, ok := secrets[name]; !ok && secret.Required {
+			return fmt.Errorf("workflow '%s' requires the secret '%s'", workflow.File, name)
+		}
+	}
+	return nil
+}
+
 func (r *remoteReusableWorkflow) CloneURL() string {
-	return fmt.Sprintf("https://%s/%s/%s", r.URL, r.Org, r.Repo)
+	return fmt.Sprintf("%s/%s/%s", r.URL, r.Org, r.Repo)
 }
 
 func newRemoteReusableWorkflow(uses string) *remoteReusableWorkflow {
@@ -122,6 +304,6 @@ func newRemoteReusableWorkflow(uses string) *remoteReusableWorkflow {
 		Repo:     matches[2],
 		Filename: matches[3],
 		Ref:      matches[4],
-		URL:      "github.com",
+		URL:      "https://github.com",
 	}
 }
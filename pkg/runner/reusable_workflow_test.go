@@ -0,0 +1,173 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nektos/act/pkg/model"
+)
+
+func TestValidateWorkflowCall(t *testing.T) {
+	workflow, err := model.ReadWorkflow(strings.NewReader(`
+name: reusable
+on:
+  workflow_call:
+    inputs:
+      env:
+        type: string
+        required: true
+      dry:
+        type: boolean
+        default: false
+      replicas:
+        type: number
+    secrets:
+      token:
+        required: true
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo
+`))
+	assert.NoError(t, err)
+	workflow.File = "reusable.yml"
+
+	tables := []struct {
+		name    string
+		inputs  map[string]interface{}
+		secrets map[string]string
+		err     string
+	}{
+		{"valid", map[string]interface{}{"env": "staging", "dry": "true", "replicas": "2"}, map[string]string{"token": "t"}, ""},
+		{"missing input", map[string]interface{}{}, map[string]string{"token": "t"}, "workflow 'reusable.yml' requires the input 'env'"},
+		{"undeclared input", map[string]interface{}{"env": "staging", "foo": "bar"}, map[string]string{"token": "t"}, "workflow 'reusable.yml' does not declare the input 'foo'"},
+		{"invalid boolean", map[string]interface{}{"env": "staging", "dry": "yes"}, map[string]string{"token": "t"}, "input 'dry' of workflow 'reusable.yml' must be a boolean, got 'yes'"},
+		{"invalid number", map[string]interface{}{"env": "staging", "replicas": "two"}, map[string]string{"token": "t"}, "input 'replicas' of workflow 'reusable.yml' must be a number, got 'two'"},
+		{"missing secret", map[string]interface{}{"env": "staging"}, map[string]string{}, "workflow 'reusable.yml' requires the secret 'token'"},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			err := validateWorkflowCall(workflow, table.inputs, table.secrets)
+			if table.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, table.err)
+			}
+		})
+	}
+}
+
+func TestWorkflowCallOutputs(t *testing.T) {
+	called, err := model.ReadWorkflow(strings.NewReader(`
+name: reusable
+on:
+  workflow_call:
+    outputs:
+      version:
+        value: ${{ jobs.build.outputs.version }}
+      status:
+        value: ${{ jobs.build.result }}/${{ jobs.test.result }}
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo
+`))
+	assert.NoError(t, err)
+	called.Jobs["build"].Outputs = map[string]string{"version": "1.2.3"}
+	called.Jobs["build"].Result = "success"
+	called.Jobs["test"].Result = "failure"
+
+	workflow := &model.Workflow{
+		Name: "caller",
+		Jobs: map[string]*model.Job{"call": createJob(t, `uses: ./.github/workflows/reusable.yml
+with:
+  env: ${{ github.event_name }}
+secrets: inherit`, "")},
+	}
+	rc := &RunContext{
+		Config: &Config{Workdir: "/repo", EventName: "push", Secrets: map[string]string{"TOKEN": "t"}},
+		Run:    &model.Run{Workflow: workflow, JobID: "call"},
+	}
+	rc.ExprEval = rc.NewExpressionEvaluator(context.Background())
+
+	assert.Equal(t, map[string]interface{}{"env": "push"}, rc.workflowCallInputs(context.Background()))
+	assert.Equal(t, map[string]string{"TOKEN": "t"}, rc.workflowCallSecrets(context.Background()))
+
+	rc.setWorkflowCallOutputs(context.Background(), called)
+	assert.Equal(t, map[string]string{"version": "1.2.3", "status": "success/failure"}, rc.Run.Job().Outputs)
+	assert.Equal(t, "failure", workflowCallResult(called))
+
+	called.Jobs["test"].Result = "cancelled"
+	assert.Equal(t, "cancelled", workflowCallResult(called))
+	called.Jobs["test"].Result = "skipped"
+	assert.Equal(t, "success", workflowCallResult(called))
+}
+
+func TestWorkflowCallMatrix(t *testing.T) {
+	workflow := &model.Workflow{
+		Name: "caller",
+		Jobs: map[string]*model.Job{"call": createJob(t, `uses: ./.github/workflows/reusable.yml
+strategy:
+  matrix:
+    target: [linux, windows]
+with:
+  target: ${{ matrix.target }}`, "")},
+	}
+	newLeg := func(index int, target string) *RunContext {
+		rc := &RunContext{
+			Name:     fmt.Sprintf("call-%d", index+1),
+			Config:   &Config{Workdir: "/repo", EventName: "push"},
+			Run:      &model.Run{Workflow: workflow, JobID: "call"},
+			Matrix:   map[string]interface{}{"target": target},
+			jobIndex: index,
+			jobTotal: 2,
+		}
+		rc.ExprEval = rc.NewExpressionEvaluator(context.Background())
+		return rc
+	}
+	linux, windows := newLeg(0, "linux"), newLeg(1, "windows")
+	assert.Equal(t, map[string]interface{}{"target": "linux"}, linux.workflowCallInputs(context.Background()))
+	assert.Equal(t, map[string]interface{}{"target": "windows"}, windows.workflowCallInputs(context.Background()))
+
+	called := &model.Workflow{Name: "reusable", Jobs: map[string]*model.Job{"build": {}}}
+	assert.Equal(t, "call-2/reusable/build", (&RunContext{Name: "build", Run: &model.Run{Workflow: called, JobID: "build"}, caller: &caller{runContext: windows}}).String())
+
+	calledWorkflow := func(outputs string) *model.Workflow {
+		w, err := model.ReadWorkflow(strings.NewReader(`
+name: reusable
+on:
+  workflow_call:
+    outputs:
+      artifact:
+        value: ${{ jobs.build.outputs.artifact }}
+      log:
+        value: ${{ jobs.build.outputs.log }}
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo
+`))
+		assert.NoError(t, err)
+		w.Jobs["build"].Outputs = map[string]string{"artifact": outputs}
+		return w
+	}
+	linux.setWorkflowCallOutputs(context.Background(), calledWorkflow("app-linux"))
+	windows.setWorkflowCallOutputs(context.Background(), calledWorkflow(""))
+	assert.Equal(t, map[string]string{"artifact": "app-linux", "log": ""}, workflow.Jobs["call"].Outputs, "an empty output doesn't overwrite the one of another leg")
+
+	linux.setWorkflowCallResult("failure")
+	windows.setWorkflowCallResult("success")
+	assert.Equal(t, "failure", workflow.Jobs["call"].Result)
+}
@@ -5,25 +5,36 @@ import (
 	"bufio"
 	"context"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"os"
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/mitchellh/go-homedir"
 	"github.com/opencontainers/selinux/go-selinux"
 	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
 
+	"github.com/nektos/act/pkg/artifactcache"
 	"github.com/nektos/act/pkg/common"
 	"github.com/nektos/act/pkg/common/git"
 	"github.com/nektos/act/pkg/container"
 	"github.com/nektos/act/pkg/exprparser"
+	"github.com/nektos/act/pkg/fixtures"
+	"github.com/nektos/act/pkg/githubapi"
 	"github.com/nektos/act/pkg/model"
 )
 
@@ -45,13 +56,45 @@ type RunContext struct {
 	JobName             string
 	ActionPath          string
 	Parent              *RunContext
-	Masks               []string
+	Masks               *Masker // masks the values in the logs, shared by all jobs of the run
 	cleanUpJobContainer common.Executor
 	caller              *caller // job calling this RunContext (reusable workflows)
+	apiProxy            *githubapi.Proxy
+	apiToken            string                 // GITHUB_TOKEN issued by the apiProxy
+	permissions         model.Permissions      // permissions of apiToken
+	outputTemplates     map[string]string      // outputs of the job as written in the workflow
+	hostAddress         *container.HostAddress // how the containers reach the servers on the host
+	jobIndex            int                    // index of the matrix leg, strategy.job-index
+	jobTotal            int                    // number of matrix legs, strategy.job-total
+	sshAgentSocket      string                 // socket of the SSH agent on the host forwarded into the job containers
+	progress            *progress              // states of the jobs of the plan, nil unless rendered
+	concurrency         concurrencyLocker      // concurrency groups of the jobs and workflows
+	concurrencyRun      string                 // identifier of the run claiming the concurrency groups
+	jobSlots            jobSlots               // limits the jobs running at once, see Config.MaxParallelJobs
+	composeProject      string                 // docker-compose project of the run providing the services, see Config.ComposeServices
+	jobContainerID      string
+	services            map[string]*model.JobServiceContext
+	serviceContainers   map[string]container.Container
+	workflowNamespace   string // the workflow file when the jobs of the plan are namespaced by it
+	cancelled           bool   // the job was cancelled or exceeded its timeout-minutes, job.status is 'cancelled'
+	continuedOnError    bool   // the job failed but continue-on-error let it succeed
+	stepDurations       map[string]time.Duration
+	exportedEnv         map[string]string  // variables exported through GITHUB_ENV as of the last step checked by checkExportedEnv
+	noPwsh              bool               // the job container emulating Windows has no pwsh, run steps default to bash
+	shellPaths          map[string]string  // the tool cache directories of the interpreters of the shells, empty if on the PATH
+	engine              *EngineRoute       // engine the job runs on, the one of the run if nil
+	outputAt            atomic.Value       // time.Time of the last output of the steps, see activityWriter
+	unsupportedSteps    []*UnsupportedStep // steps of the job skipped by Config.SkipUnsupported
+	stepSummaries       []string           // the markdown the steps of the job wrote to GITHUB_STEP_SUMMARY
 }
 
+// AddMask masks the value in the logs of all jobs of the run
 func (rc *RunContext) AddMask(mask string) {
-	rc.Masks = append(rc.Masks, mask)
+	if rc.Masks == nil {
+		// a RunContext of a run always has the masker of the run
+		rc.Masks = NewMasker()
+	}
+	rc.Masks.Add(mask)
 }
 
 type MappableOutput struct {
@@ -60,15 +103,112 @@ type MappableOutput struct {
 }
 
 func (rc *RunContext) String() string {
-	name := fmt.Sprintf("%s/%s", rc.Run.Workflow.Name, rc.Name)
+	workflow := rc.Run.Workflow.Name
+	if rc.workflowNamespace != "" && rc.caller == nil {
+		workflow = rc.workflowNamespace
+	}
+	name := fmt.Sprintf("%s/%s", workflow, rc.Name)
 	if rc.caller != nil {
 		// prefix the reusable workflow with the caller job
 		// this is required to create unique container names
-		name = fmt.Sprintf("%s/%s", rc.caller.runContext.Run.JobID, name)
+		name = fmt.Sprintf("%s/%s", rc.caller.jobID(), name)
+		if rc.workflowNamespace != "" {
+			name = fmt.Sprintf("%s/%s", rc.workflowNamespace, name)
+		}
 	}
 	return name
 }
 
+// logPrefix is the prefix of the log lines of the job rendered from Config.LogPrefix, String() without a template,
+// the name of Config.Repository comes first
+func (rc *RunContext) logPrefix() string {
+	prefix := rc.String()
+	if rc.Config != nil && rc.Config.LogPrefix != "" {
+		prefix = rc.renderLogPrefix()
+	}
+	if rc.Config != nil && rc.Config.Repository != "" {
+		prefix = rc.Config.Repository + "/" + prefix
+	}
+	return prefix
+}
+
+// renderLogPrefix renders the template of Config.LogPrefix
+func (rc *RunContext) renderLogPrefix() string {
+	workflow := rc.Run.Workflow.Name
+	if rc.workflowNamespace != "" {
+		workflow = rc.workflowNamespace
+	}
+	job := rc.JobName
+	if job == "" {
+		job = rc.Name
+	}
+	if rc.caller != nil {
+		job = fmt.Sprintf("%s/%s", rc.caller.jobID(), job)
+	}
+	keys := make([]string, 0, len(rc.Matrix))
+	for key := range rc.Matrix {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	values := make([]string, 0, len(keys))
+	for _, key := range keys {
+		values = append(values, fmt.Sprint(rc.Matrix[key]))
+	}
+	index := ""
+	if rc.jobTotal > 1 {
+		index = strconv.Itoa(rc.jobIndex + 1)
+	}
+
+	placeholders := map[string]string{
+		"workflow": workflow,
+		"file":     rc.Run.Workflow.File,
+		"job":      job,
+		"jobID":    rc.Run.JobID,
+		"matrix":   strings.Join(values, ","),
+		"index":    index,
+	}
+
+	// the template is split into its literal text and the values of its placeholders
+	template := rc.Config.LogPrefix
+	parts := make([]string, 0)
+	empty := map[int]bool{}
+	last := 0
+	for _, match := range logPrefixPlaceholder.FindAllStringSubmatchIndex(template, -1) {
+		parts = append(parts, template[last:match[0]])
+		value := placeholders[template[match[2]:match[3]]]
+		empty[len(parts)] = value == ""
+		parts = append(parts, value)
+		last = match[1]
+	}
+	parts = append(parts, template[last:])
+
+	// drop a separator next to every empty placeholder, e.g. of {matrix} for a job without a matrix
+	for i := range parts {
+		if !empty[i] {
+			continue
+		}
+		if strings.ContainsAny(lastChar(parts[i-1]), logPrefixSeparators) {
+			parts[i-1] = parts[i-1][:len(parts[i-1])-1]
+		} else if parts[i+1] != "" && strings.ContainsRune(logPrefixSeparators, rune(parts[i+1][0])) {
+			parts[i+1] = parts[i+1][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// logPrefixPlaceholder is a placeholder of the template of Config.LogPrefix
+var logPrefixPlaceholder = regexp.MustCompile(`\{(workflow|file|job|jobID|matrix|index)\}`)
+
+// logPrefixSeparators separate the placeholders of Config.LogPrefix
+const logPrefixSeparators = "/- "
+
+func lastChar(s string) string {
+	if s == "" {
+		return ""
+	}
+	return s[len(s)-1:]
+}
+
 // GetEnv returns the env for the context
 func (rc *RunContext) GetEnv() map[string]string {
 	if rc.Env == nil {
@@ -84,8 +224,122 @@ func (rc *RunContext) GetEnv() map[string]string {
 	return rc.Env
 }
 
+// envContextPattern matches an expression using the env context
+var envContextPattern = regexp.MustCompile(`\$\{\{[^}]*\benv\.`)
+
+// evaluateEnv evaluates the env of the workflow, of the job and of --env in this order. Like on GitHub the env
+// context isn't available in them, so the env of the job can't use the env of the workflow.
+// https://docs.github.com/en/actions/learn-github-actions/contexts#context-availability
+func (rc *RunContext) evaluateEnv(ctx context.Context) {
+	exprEval := rc.NewExpressionEvaluatorWithEnv(ctx, map[string]string{})
+	evaluated := map[string]string{}
+	for _, level := range []struct {
+		name string
+		env  map[string]string
+	}{
+		{"workflow", rc.Run.Workflow.Env},
+		{"job", rc.Run.Job().Environment()},
+		{"", rc.Config.Env},
+	} {
+		for _, k := range sortedKeys(level.env) {
+			v := level.env[k]
+			if level.name != "" && envContextPattern.MatchString(v) {
+				common.Logger(ctx).Warnf("The env context is not available in the env of the %s, '%s' of %s is empty on GitHub", level.name, v, k)
+			}
+			evaluated[k] = exprEval.Interpolate(ctx, v)
+		}
+	}
+
+	env := rc.GetEnv()
+	for k, v := range rc.neededEnv() {
+		if _, ok := evaluated[k]; !ok {
+			env[k] = v
+		}
+	}
+	for k, v := range evaluated {
+		env[k] = v
+	}
+}
+
+// jobIfEnv returns the env context of the if of the job, GitHub doesn't provide it there. env.ACT and the
+// variables of --env are kept to allow skipping jobs when they run locally.
+func (rc *RunContext) jobIfEnv() map[string]string {
+	env := map[string]string{}
+	for k, v := range rc.Config.Env {
+		env[k] = v
+	}
+	env["ACT"] = "true"
+	return env
+}
+
 func (rc *RunContext) jobContainerName() string {
-	return createContainerName("act", rc.String())
+	return rc.reusableContainerName(createContainerName("act", rc.String()))
+}
+
+// stepContainerName returns the name of the container of a docker action or a docker:// step
+func (rc *RunContext) stepContainerName(stepID string) string {
+	return rc.reusableContainerName(createContainerName(createContainerName("act", rc.String()), stepID))
+}
+
+var workflowSlugPattern = regexp.MustCompile(`[^a-zA-Z0-9_.-]+`)
+
+// workflowSlug turns the file of a workflow into a path segment, e.g. ci/build.yml into ci-build
+func workflowSlug(file string) string {
+	file = strings.TrimSuffix(file, filepath.Ext(file))
+	return strings.Trim(workflowSlugPattern.ReplaceAllString(file, "-"), "-")
+}
+
+// artifactNamespace returns the namespace of the artifacts of the job, every workflow has its own run on GitHub
+// while the jobs of a plan share GITHUB_RUN_ID. It is empty unless the jobs are namespaced by their workflow file.
+func (rc *RunContext) artifactNamespace() string {
+	if rc.workflowNamespace == "" {
+		return ""
+	}
+	return workflowSlug(rc.workflowNamespace)
+}
+
+// reusableContainerName appends the reuse key to the name of a container kept by --reuse,
+// so jobs of other workflows or repositories with the same name don't pick it up
+func (rc *RunContext) reusableContainerName(name string) string {
+	if !rc.Config.ReuseContainers {
+		return name
+	}
+	return fmt.Sprintf("%s-%s", name, rc.reuseKey())
+}
+
+// reuseKey identifies the containers of a job by the working directory, the workflow, the job, the matrix and the
+// branch of Config.BranchScope
+func (rc *RunContext) reuseKey() string {
+	matrix, _ := json.Marshal(rc.Matrix)
+	parts := []string{
+		rc.Config.Workdir,
+		rc.Run.Workflow.File,
+		rc.String(),
+		rc.Run.JobID,
+		string(matrix),
+	}
+	if rc.Config.BranchScope != "" {
+		parts = append(parts, rc.Config.BranchScope)
+	}
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+// containerLabels returns the labels listed by act ps for the containers kept by --reuse
+func (rc *RunContext) containerLabels() map[string]string {
+	if !rc.Config.ReuseContainers {
+		return nil
+	}
+	labels := map[string]string{
+		container.LabelReuseKey: rc.reuseKey(),
+		container.LabelWorkflow: rc.Run.Workflow.File,
+		container.LabelJob:      rc.String(),
+		container.LabelWorkdir:  rc.Config.Workdir,
+	}
+	if rc.Config.BranchScope != "" {
+		labels[container.LabelBranch] = rc.Config.BranchScope
+	}
+	return labels
 }
 
 // Returns the binds and mounts for the container, resolving paths as appopriate
@@ -93,11 +347,20 @@ func (rc *RunContext) GetBindsAndMounts() ([]string, map[string]string) {
 	name := rc.jobContainerName()
 
 	if rc.Config.ContainerDaemonSocket == "" {
-		rc.Config.ContainerDaemonSocket = "/var/run/docker.sock"
+		backend, _ := container.ParseBackend(rc.Config.ContainerBackend)
+		rc.Config.ContainerDaemonSocket = backend.Socket()
+	}
+	daemonSocket := rc.Config.ContainerDaemonSocket
+	if rc.engine != nil {
+		daemonSocket = rc.engine.socket()
+	}
+	if strings.Contains(daemonSocket, "://") {
+		// the docker host may be forwarded from a VM, the daemon listens on its default socket
+		daemonSocket = "/var/run/docker.sock"
 	}
 
 	binds := []string{
-		fmt.Sprintf("%s:%s", rc.Config.ContainerDaemonSocket, "/var/run/docker.sock"),
+		fmt.Sprintf("%s:%s", daemonSocket, "/var/run/docker.sock"),
 	}
 
 	ext := container.LinuxContainerEnvironmentExtensions{}
@@ -106,6 +369,20 @@ func (rc *RunContext) GetBindsAndMounts() ([]string, map[string]string) {
 		"act-toolcache": "/toolcache",
 		name + "-env":   ext.GetActPath(),
 	}
+	if rc.Config.RunnerManifest != nil {
+		// keep the tools installed from the manifest for the next jobs
+		mounts[rc.Config.RunnerManifest.volumeName()] = "/opt/hostedtoolcache"
+	}
+	for name, dir := range rc.Config.CacheVolumes {
+		mounts[container.CacheVolumeName(name)] = dir
+	}
+	if rc.sshAgentSocket != "" {
+		socket := rc.sshAgentSocket
+		if runtime.GOOS == "darwin" {
+			socket = dockerDesktopSSHAgentSocket
+		}
+		binds = append(binds, fmt.Sprintf("%s:%s", socket, sshAgentSocketPath))
+	}
 
 	if job := rc.Run.Job(); job != nil {
 		if container := job.Container(); container != nil {
@@ -122,7 +399,7 @@ func (rc *RunContext) GetBindsAndMounts() ([]string, map[string]string) {
 		}
 	}
 
-	if rc.Config.BindWorkdir {
+	if rc.bindsWorkdir() {
 		bindModifiers := ""
 		if runtime.GOOS == "darwin" {
 			bindModifiers = ":delegated"
@@ -133,14 +410,50 @@ func (rc *RunContext) GetBindsAndMounts() ([]string, map[string]string) {
 		binds = append(binds, fmt.Sprintf("%s:%s%s", rc.Config.Workdir, ext.ToContainerPath(rc.Config.Workdir), bindModifiers))
 	} else {
 		mounts[name] = ext.ToContainerPath(rc.Config.Workdir)
+		if rc.Config.BindWorkdir && rc.Config.MatrixWorkspace == MatrixWorkspaceBase {
+			binds = append(binds, fmt.Sprintf("%s:%s:ro", rc.Config.Workdir, workspaceBasePath))
+		}
 	}
 
 	return binds, mounts
 }
 
+// workspaceBasePath is where the workdir is bound read-only for the legs of a matrix job with MatrixWorkspaceBase
+const workspaceBasePath = "/var/run/act-workspace"
+
+// bindsWorkdir reports if the workdir is bound as the workspace of the job, the legs of a matrix job get a copy of
+// it unless they share it through MatrixWorkspaceShared
+func (rc *RunContext) bindsWorkdir() bool {
+	return rc.Config.BindWorkdir && (rc.jobTotal <= 1 || rc.Config.MatrixWorkspace == MatrixWorkspaceShared)
+}
+
+// copyWorkspace fills the workspace of a leg of a matrix job with a copy of the bound workdir
+func (rc *RunContext) copyWorkspace() common.Executor {
+	return func(ctx context.Context) error {
+		if !rc.Config.BindWorkdir || rc.bindsWorkdir() {
+			return nil
+		}
+		workspace := rc.JobContainer.ToContainerPath(rc.Config.Workdir)
+		common.Logger(ctx).Debugf("Copying the workdir into the workspace of the leg %d of the matrix", rc.jobIndex+1)
+		if rc.Config.MatrixWorkspace == MatrixWorkspaceBase {
+			return rc.JobContainer.Exec([]string{"cp", "-a", workspaceBasePath + "/.", workspace}, nil, "", "")(ctx)
+		}
+		return rc.JobContainer.CopyDir(workspace, rc.Config.Workdir+string(filepath.Separator)+".", rc.Config.UseGitIgnore)(ctx)
+	}
+}
+
 func (rc *RunContext) startHostEnvironment() common.Executor {
 	return func(ctx context.Context) error {
 		logger := common.Logger(ctx)
+		if len(rc.Run.Job().Services) > 0 {
+			// like on GitHub, service containers need a job container to share their network with
+			logger.Warnf("⚠  The services of %s are not started, the job runs on the host", rc.String())
+		}
+		if rc.Config.UseHost && rc.Run.Job().Container() != nil {
+			logger.Warnf("⚠  The container of %s is not started, the job runs on the host with --use-host", rc.String())
+		} else if rc.engine != nil && rc.engine.Host && rc.Run.Job().Container() != nil {
+			logger.Warnf("⚠  The container of %s is not started, the job runs on the host of its --engine", rc.String())
+		}
 		rawLogger := logger.WithField("raw_output", true)
 		logWriter := common.NewLineWriter(rc.commandHandler(ctx), func(s string) bool {
 			if rc.Config.LogOutput {
@@ -150,7 +463,7 @@ func (rc *RunContext) startHostEnvironment() common.Executor {
 			}
 			return true
 		})
-		cacheDir := rc.ActionCacheDir()
+		cacheDir := rc.cacheDir()
 		randBytes := make([]byte, 8)
 		_, _ = rand.Read(randBytes)
 		miscpath := filepath.Join(cacheDir, hex.EncodeToString(randBytes))
@@ -190,13 +503,12 @@ func (rc *RunContext) startHostEnvironment() common.Executor {
 				rc.Env[env[0:i]] = env[i+1:]
 			}
 		}
+		if rc.sshAgentSocket != "" {
+			rc.Env["SSH_AUTH_SOCK"] = rc.sshAgentSocket
+		}
 
 		return common.NewPipelineExecutor(
-			rc.JobContainer.Copy(rc.JobContainer.GetActPath()+"/", &container.FileEntry{
-				Name: "workflow/event.json",
-				Mode: 0644,
-				Body: rc.EventJSON,
-			}, &container.FileEntry{
+			rc.JobContainer.Copy(rc.JobContainer.GetActPath()+"/", rc.eventPayloadEntry(), &container.FileEntry{
 				Name: "workflow/envs.txt",
 				Mode: 0666,
 				Body: "",
@@ -208,7 +520,10 @@ func (rc *RunContext) startHostEnvironment() common.Executor {
 func (rc *RunContext) startJobContainer() common.Executor {
 	return func(ctx context.Context) error {
 		logger := common.Logger(ctx)
-		image := rc.platformImage(ctx)
+		image, err := rc.pinImage(ctx, rc.platformImage(ctx))
+		if err != nil {
+			return err
+		}
 		rawLogger := logger.WithField("raw_output", true)
 		logWriter := common.NewLineWriter(rc.commandHandler(ctx), func(s string) bool {
 			if rc.Config.LogOutput {
@@ -237,12 +552,20 @@ func (rc *RunContext) startJobContainer() common.Executor {
 
 		ext := container.LinuxContainerEnvironmentExtensions{}
 		binds, mounts := rc.GetBindsAndMounts()
+		if rc.sshAgentSocket != "" {
+			rc.Env["SSH_AUTH_SOCK"] = sshAgentSocketPath
+		}
+
+		if err := rc.createServiceContainers(ctx); err != nil {
+			return err
+		}
 
 		rc.cleanUpJobContainer = func(ctx context.Context) error {
 			if rc.JobContainer != nil && !rc.Config.ReuseContainers {
 				return rc.JobContainer.Remove().
 					Then(container.NewDockerVolumeRemoveExecutor(rc.jobContainerName(), false)).
-					Then(container.NewDockerVolumeRemoveExecutor(rc.jobContainerName()+"-env", false))(ctx)
+					Then(container.NewDockerVolumeRemoveExecutor(rc.jobContainerName()+"-env", false)).
+					Then(rc.removeServiceContainers())(ctx)
 			}
 			return nil
 		}
@@ -257,7 +580,7 @@ func (rc *RunContext) startJobContainer() common.Executor {
 			Name:        name,
 			Env:         envList,
 			Mounts:      mounts,
-			NetworkMode: "host",
+			NetworkMode: rc.networkName(),
 			Binds:       binds,
 			Stdout:      logWriter,
 			Stderr:      logWriter,
@@ -265,6 +588,11 @@ func (rc *RunContext) startJobContainer() common.Executor {
 			UsernsMode:  rc.Config.UsernsMode,
 			Platform:    rc.Config.ContainerArchitecture,
 			Options:     rc.options(ctx),
+			ExtraHosts:  rc.extraHosts(),
+			Labels:      rc.containerLabels(),
+			User:        rc.containerUser(ctx),
+			Runtime:     rc.Config.ContainerRuntime,
+			CreateHooks: rc.Config.ContainerCreateHooks,
 		})
 		if rc.JobContainer == nil {
 			return errors.New("Failed to create job container")
@@ -273,15 +601,15 @@ func (rc *RunContext) startJobContainer() common.Executor {
 		return common.NewPipelineExecutor(
 			rc.JobContainer.Pull(rc.Config.ForcePull),
 			rc.stopJobContainer(),
+			rc.createCacheVolumes(),
+			rc.startServiceContainers(),
 			rc.JobContainer.Create(rc.Config.ContainerCapAdd, rc.Config.ContainerCapDrop),
 			rc.JobContainer.Start(false),
+			rc.inspectJobContainer(),
+			rc.copyWorkspace(),
 			rc.JobContainer.UpdateFromImageEnv(&rc.Env),
 			rc.JobContainer.UpdateFromEnv("/etc/environment", &rc.Env),
-			rc.JobContainer.Copy(rc.JobContainer.GetActPath()+"/", &container.FileEntry{
-				Name: "workflow/event.json",
-				Mode: 0644,
-				Body: rc.EventJSON,
-			}, &container.FileEntry{
+			rc.JobContainer.Copy(rc.JobContainer.GetActPath()+"/", rc.eventPayloadEntry(), &container.FileEntry{
 				Name: "workflow/envs.txt",
 				Mode: 0666,
 				Body: "",
@@ -290,6 +618,53 @@ func (rc *RunContext) startJobContainer() common.Executor {
 	}
 }
 
+// inspectJobContainer looks up the ids of the job container and of the services for the job context
+func (rc *RunContext) inspectJobContainer() common.Executor {
+	return func(ctx context.Context) error {
+		logger := common.Logger(ctx)
+
+		id, err := container.GetContainerID(ctx, rc.jobContainerName())
+		if err != nil {
+			logger.Debugf("Unable to inspect the job container: %v", err)
+		}
+		rc.jobContainerID = id
+
+		rc.services = make(map[string]*model.JobServiceContext, len(rc.serviceContainers))
+		rc.inspectServiceContainers(ctx)
+
+		if rc.Config.ComposeServices == "" {
+			return nil
+		}
+		services, err := container.GetComposeServices(ctx, composeInput(rc.Config, rc.composeProject))
+		if err != nil {
+			logger.Debugf("Unable to inspect the services: %v", err)
+			return nil
+		}
+		for name, service := range services {
+			if _, ok := rc.services[name]; ok {
+				// the services of the job take precedence over the ones of the docker-compose file
+				continue
+			}
+			rc.services[name] = &model.JobServiceContext{
+				ID:      service.ID,
+				Network: service.Network,
+				Ports:   service.Ports,
+			}
+		}
+		return nil
+	}
+}
+
+// networkName returns the network of the job container: the network of the docker-compose project providing the
+// services, the network of the job if service containers were created for it, otherwise the one of the host. A
+// service whose image evaluates to an empty string doesn't get a container, nor the job a network.
+func (rc *RunContext) networkName() string {
+	if rc.Config.ComposeServices != "" || len(rc.serviceContainers) > 0 {
+		return rc.serviceNetworkName()
+	}
+	return "host"
+}
+
 func (rc *RunContext) execJobContainer(cmd []string, env map[string]string, user, workdir string) common.Executor {
 	return func(ctx context.Context) error {
 		return rc.JobContainer.Exec(cmd, env, user, workdir)(ctx)
@@ -343,8 +718,13 @@ func (rc *RunContext) stopJobContainer() common.Executor {
 
 // Prepare the mounts and binds for the worker
 
-// ActionCacheDir is for rc
+// ActionCacheDir is for rc, the actions and reusable workflows are cloned into it
 func (rc *RunContext) ActionCacheDir() string {
+	return filepath.Join(rc.cacheDir(), "actions")
+}
+
+// cacheDir is the cache directory of act, only its actions and tool_cache subdirectories are evicted by --cache-max-size
+func (rc *RunContext) cacheDir() string {
 	var xdgCache string
 	var ok bool
 	if xdgCache, ok = os.LookupEnv("XDG_CACHE_HOME"); !ok || xdgCache == "" {
@@ -357,27 +737,117 @@ func (rc *RunContext) ActionCacheDir() string {
 	return filepath.Join(xdgCache, "act")
 }
 
+// jobOutputsMutex guards the outputs of the jobs, the legs of a matrix job share the outputs of the job
+var jobOutputsMutex sync.Mutex
+
 // Interpolate outputs after a job is done
 func (rc *RunContext) interpolateOutputs() common.Executor {
 	return func(ctx context.Context) error {
-		ee := rc.NewExpressionEvaluator(ctx)
-		for k, v := range rc.Run.Job().Outputs {
-			interpolated := ee.Interpolate(ctx, v)
-			if v != interpolated {
-				rc.Run.Job().Outputs[k] = interpolated
+		outputs := rc.evaluateOutputs(ctx)
+		if err := rc.checkJobOutputsLimit(ctx, outputs); err != nil {
+			return err
+		}
+
+		jobOutputsMutex.Lock()
+		defer jobOutputsMutex.Unlock()
+		job := rc.Run.Job()
+		if job.Outputs == nil {
+			job.Outputs = map[string]string{}
+		}
+		for k, v := range outputs {
+			// like on GitHub an empty value doesn't overwrite the output set by another leg of the matrix
+			if current, ok := job.Outputs[k]; v != "" || len(rc.Matrix) == 0 || !ok || current == rc.outputTemplates[k] {
+				job.Outputs[k] = v
 			}
 		}
 		return nil
 	}
 }
 
+// propagatedEnvPrefix prefixes the outputs of a job generated for its variables exported through GITHUB_ENV with
+// --propagate-env-to-needs, e.g. needs.build.outputs.ACT_ENV_VERSION
+const propagatedEnvPrefix = "ACT_ENV_"
+
+// propagateEnv adds the exported variables to the outputs of the job, the jobs needing it get them in their env
+func (rc *RunContext) propagateEnv(env map[string]string) {
+	jobOutputsMutex.Lock()
+	defer jobOutputsMutex.Unlock()
+	job := rc.Run.Job()
+	if job.Outputs == nil {
+		job.Outputs = map[string]string{}
+	}
+	for k, v := range env {
+		job.Outputs[propagatedEnvPrefix+k] = v
+	}
+}
+
+// neededEnv returns the variables propagated by the jobs the job needs, the env of the workflow, of the job and of
+// --env take precedence over them
+func (rc *RunContext) neededEnv() map[string]string {
+	env := map[string]string{}
+	if !rc.Config.PropagateEnvToNeeds || rc.Run == nil {
+		return env
+	}
+	jobOutputsMutex.Lock()
+	defer jobOutputsMutex.Unlock()
+	for _, need := range rc.Run.Job().Needs() {
+		job := rc.Run.Workflow.GetJob(need)
+		if job == nil {
+			continue
+		}
+		for k, v := range job.Outputs {
+			if name := strings.TrimPrefix(k, propagatedEnvPrefix); name != k && name != "" {
+				env[name] = v
+			}
+		}
+	}
+	return env
+}
+
+// evaluateOutputs evaluates the outputs of the job, outputs which may contain a secret are skipped
+func (rc *RunContext) evaluateOutputs(ctx context.Context) map[string]string {
+	logger := common.Logger(ctx)
+	templates := rc.outputTemplates
+	if templates == nil {
+		templates = rc.Run.Job().Outputs
+	}
+
+	ee := rc.NewExpressionEvaluator(ctx)
+	secrets := getWorkflowSecrets(ctx, rc)
+	outputs := make(map[string]string, len(templates))
+	for k, v := range templates {
+		interpolated := ee.Interpolate(ctx, v)
+		if rc.containsSecret(secrets, interpolated) {
+			logger.Warnf("Skip output '%s' since it may contain secret.", k)
+			interpolated = ""
+		}
+		outputs[k] = interpolated
+	}
+	return outputs
+}
+
+func (rc *RunContext) containsSecret(secrets map[string]string, value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, secret := range secrets {
+		if secret != "" && strings.Contains(value, secret) {
+			return true
+		}
+	}
+	return rc.Masks.Contains(value)
+}
+
 func (rc *RunContext) startContainer() common.Executor {
 	return func(ctx context.Context) error {
 		image := rc.platformImage(ctx)
 		if strings.EqualFold(image, "-self-hosted") {
-			return rc.startHostEnvironment()(ctx)
+			if rc.Config.GitIdentity != nil {
+				common.Logger(ctx).Warnf("The git identity is only configured in job containers, not on the host")
+			}
+			return rc.startHostEnvironment().Then(rc.installManifestTools())(ctx)
 		}
-		return rc.startJobContainer()(ctx)
+		return rc.warnEmulatedOS().Then(rc.startJobContainer()).Then(rc.probeEmulatedShell()).Then(rc.installCACertificates()).Then(rc.configureGitIdentity()).Then(rc.installManifestTools())(ctx)
 	}
 }
 
@@ -403,7 +873,10 @@ func (rc *RunContext) result(result string) {
 }
 
 func (rc *RunContext) steps() []*model.Step {
-	return rc.Run.Job().Steps
+	if rc.Config == nil {
+		return rc.Run.Job().Steps
+	}
+	return injectSteps(rc.Run.Job().Steps, rc.Config.InjectSteps)
 }
 
 // Executor returns a pipeline executor for all the steps in the job
@@ -427,22 +900,53 @@ func (rc *RunContext) Executor() common.Executor {
 		if res {
 			return executor(ctx)
 		}
+		rc.progress.legSkipped(rc.Run)
+		return nil
+	}
+}
+
+// jobContainer returns the container of the job with its image evaluated, the whole container may be an expression
+// evaluating to an image or an object, e.g. container: ${{ matrix.container }}. It is nil if the job has no container
+// or the image is empty, like on GitHub the job runs on the runner then.
+func (rc *RunContext) jobContainer(ctx context.Context) *model.ContainerSpec {
+	node := rc.Run.Job().RawContainer
+	if node.Kind == yaml.ScalarNode {
+		if err := rc.ExprEval.EvaluateYamlNode(ctx, &node); err != nil {
+			common.Logger(ctx).Errorf("Unable to evaluate the container of %s: %v", rc.String(), err)
+			return nil
+		}
+	}
+	c := model.DecodeContainer(&node)
+	if c == nil {
 		return nil
 	}
+	c.Image = rc.ExprEval.Interpolate(ctx, c.Image)
+	if c.Image == "" {
+		return nil
+	}
+	return c
 }
 
 func (rc *RunContext) platformImage(ctx context.Context) string {
 	job := rc.Run.Job()
 
-	c := job.Container()
-	if c != nil {
-		return rc.ExprEval.Interpolate(ctx, c.Image)
+	if route := rc.engineRoute(ctx); rc.Config.UseHost || (route != nil && route.Host) {
+		return "-self-hosted"
+	}
+
+	if c := rc.jobContainer(ctx); c != nil {
+		return c.Image
 	}
 
 	if job.RunsOn() == nil {
 		common.Logger(ctx).Errorf("'runs-on' key not defined in %s", rc.String())
 	}
 
+	if group := rc.runsOnGroupLabel(ctx); group != "" {
+		if image := rc.Config.Platforms[group]; image != "" {
+			return image
+		}
+	}
 	for _, runnerLabel := range job.RunsOn() {
 		platformName := rc.ExprEval.Interpolate(ctx, runnerLabel)
 		image := rc.Config.Platforms[strings.ToLower(platformName)]
@@ -455,8 +959,7 @@ func (rc *RunContext) platformImage(ctx context.Context) string {
 }
 
 func (rc *RunContext) options(ctx context.Context) string {
-	job := rc.Run.Job()
-	c := job.Container()
+	c := rc.jobContainer(ctx)
 	if c == nil {
 		return rc.Config.ContainerOptions
 	}
@@ -467,7 +970,11 @@ func (rc *RunContext) options(ctx context.Context) string {
 func (rc *RunContext) isEnabled(ctx context.Context) (bool, error) {
 	job := rc.Run.Job()
 	l := common.Logger(ctx)
-	runJob, err := EvalBool(ctx, rc.ExprEval, job.If.Value, exprparser.DefaultStatusCheckSuccess)
+	if ghc := rc.getGithubContext(ctx); !rc.Run.Workflow.RunsForActivityType(ghc.EventName, ghc.Event) {
+		l.WithField("jobResult", "skipped").Infof("Skipping job '%s', the workflow doesn't run for the activity type '%v' of %s", job.Name, ghc.Event["action"], ghc.EventName)
+		return false, nil
+	}
+	runJob, err := EvalBool(ctx, rc.NewExpressionEvaluatorWithEnv(ctx, rc.jobIfEnv()), job.If.Value, exprparser.DefaultStatusCheckSuccess)
 	if err != nil {
 		return false, fmt.Errorf("  \u274C  Error in if-expression: \"if: %s\" (%s)", job.If.Value, err)
 	}
@@ -486,6 +993,9 @@ func (rc *RunContext) isEnabled(ctx context.Context) (bool, error) {
 			l.Errorf("'runs-on' key not defined in %s", rc.String())
 		}
 
+		if group := rc.runsOnGroupLabel(ctx); group != "" {
+			l.Infof("\U0001F6A7  Skipping unsupported runner group -- Try running with `-P %+v=...`", group)
+		}
 		for _, runnerLabel := range job.RunsOn() {
 			platformName := rc.ExprEval.Interpolate(ctx, runnerLabel)
 			l.Infof("\U0001F6A7  Skipping unsupported platform -- Try running with `-P %+v=...`", platformName)
@@ -495,6 +1005,17 @@ func (rc *RunContext) isEnabled(ctx context.Context) (bool, error) {
 	return true, nil
 }
 
+// runsOnGroupLabel returns the platform of the runner group of runs-on: { group: ... }, which maps the group to an
+// image with -P group:<name>=<image> or to an engine with --engine group:<name>=<engine>. It is empty if the job
+// doesn't run on a group.
+func (rc *RunContext) runsOnGroupLabel(ctx context.Context) string {
+	group := rc.Run.Job().RunsOnGroup()
+	if group == "" {
+		return ""
+	}
+	return "group:" + strings.ToLower(rc.ExprEval.Interpolate(ctx, group))
+}
+
 func mergeMaps(maps ...map[string]string) map[string]string {
 	rtnMap := make(map[string]string)
 	for _, m := range maps {
@@ -546,9 +1067,43 @@ func (rc *RunContext) getJobContext() *model.JobContext {
 			break
 		}
 	}
-	return &model.JobContext{
-		Status: jobStatus,
+	if rc.cancelled {
+		jobStatus = "cancelled"
+	}
+	jobContext := &model.JobContext{
+		Status:   jobStatus,
+		Services: rc.services,
+	}
+	if rc.JobContainer != nil {
+		jobContext.Container.ID = rc.jobContainerID
+		jobContext.Container.Network = rc.networkName()
+	}
+	if jobContext.Services == nil {
+		jobContext.Services = map[string]*model.JobServiceContext{}
 	}
+	return jobContext
+}
+
+// getStrategyContext returns the strategy context, a job without a matrix is a single leg
+func (rc *RunContext) getStrategyContext() map[string]interface{} {
+	total := rc.jobTotal
+	if total < 1 {
+		total = 1
+	}
+	strategy := map[string]interface{}{
+		"fail-fast":    true,
+		"job-index":    rc.jobIndex,
+		"job-total":    total,
+		"max-parallel": total,
+	}
+	if rc.Run == nil {
+		return strategy
+	}
+	if job := rc.Run.Job(); job != nil && job.Strategy != nil {
+		strategy["fail-fast"] = job.Strategy.FailFast
+		strategy["max-parallel"] = job.Strategy.MaxParallel
+	}
+	return strategy
 }
 
 func (rc *RunContext) getStepsContext() map[string]*model.StepResult {
@@ -562,10 +1117,11 @@ func (rc *RunContext) getGithubContext(ctx context.Context) *model.GithubContext
 		Workflow:         rc.Run.Workflow.Name,
 		RunID:            rc.Config.Env["GITHUB_RUN_ID"],
 		RunNumber:        rc.Config.Env["GITHUB_RUN_NUMBER"],
+		RunAttempt:       rc.Config.Env["GITHUB_RUN_ATTEMPT"],
 		Actor:            rc.Config.Actor,
 		EventName:        rc.Config.EventName,
 		Action:           rc.CurrentStep,
-		Token:            rc.Config.Token,
+		Token:            rc.token(),
 		ActionPath:       rc.ActionPath,
 		RepositoryOwner:  rc.Config.Env["GITHUB_REPOSITORY_OWNER"],
 		RetentionDays:    rc.Config.Env["GITHUB_RETENTION_DAYS"],
@@ -573,7 +1129,7 @@ func (rc *RunContext) getGithubContext(ctx context.Context) *model.GithubContext
 		RunnerTrackingID: rc.Config.Env["RUNNER_TRACKING_ID"],
 	}
 	if rc.JobContainer != nil {
-		ghc.EventPath = rc.JobContainer.GetActPath() + "/workflow/event.json"
+		ghc.EventPath = rc.JobContainer.GetActPath() + "/" + eventPayloadFile
 		ghc.Workspace = rc.JobContainer.ToContainerPath(rc.Config.Workdir)
 	}
 
@@ -585,10 +1141,26 @@ func (rc *RunContext) getGithubContext(ctx context.Context) *model.GithubContext
 		ghc.RunNumber = "1"
 	}
 
+	if rc.Config.RunAttempt > 0 {
+		ghc.RunAttempt = strconv.Itoa(rc.Config.RunAttempt)
+	} else if ghc.RunAttempt == "" {
+		ghc.RunAttempt = "1"
+	}
+
 	if ghc.RetentionDays == "" {
 		ghc.RetentionDays = "0"
 	}
 
+	if urls, err := githubURLs(rc.Config); err != nil {
+		logger.Warningf("unable to get the URLs of the GitHub instance: %v", err)
+	} else {
+		ghc.ServerURL, ghc.APIURL, ghc.GraphQLURL = urls.ServerURL, urls.APIURL, urls.GraphQLURL
+	}
+	if rc.apiProxy != nil {
+		ghc.APIURL = rc.apiProxy.URL
+		ghc.GraphQLURL = rc.apiProxy.URL + "/graphql"
+	}
+
 	if ghc.RunnerPerflog == "" {
 		ghc.RunnerPerflog = "/dev/null"
 	}
@@ -600,7 +1172,10 @@ func (rc *RunContext) getGithubContext(ctx context.Context) *model.GithubContext
 	}
 
 	repoPath := rc.Config.Workdir
-	repo, err := git.FindGithubRepo(ctx, repoPath, rc.Config.GitHubInstance, rc.Config.RemoteName)
+	// the remote is matched against the host (and path prefix) of the instance, without its scheme
+	instance := githubServerURL(rc.Config)
+	instance = strings.TrimPrefix(strings.TrimPrefix(instance, "https://"), "http://")
+	repo, err := git.FindGithubRepo(ctx, repoPath, instance, rc.Config.RemoteName)
 	if err != nil {
 		logger.Warningf("unable to get git repo: %v", err)
 	} else {
@@ -624,7 +1199,17 @@ func (rc *RunContext) getGithubContext(ctx context.Context) *model.GithubContext
 
 	ghc.SetRefAndSha(ctx, rc.Config.DefaultBranch, repoPath)
 
-	// https://docs.github.com/en/actions/learn-github-actions/environment-variables
+	setRefTypeAndName(ghc)
+
+	if err := rc.Config.ContextOverrides.applyGithub(ghc); err != nil {
+		logger.Errorf("Unable to override the github context: %v", err)
+	}
+
+	return ghc
+}
+
+// https://docs.github.com/en/actions/learn-github-actions/environment-variables
+func setRefTypeAndName(ghc *model.GithubContext) {
 	if strings.HasPrefix(ghc.Ref, "refs/tags/") {
 		ghc.RefType = "tag"
 		ghc.RefName = ghc.Ref[len("refs/tags/"):]
@@ -632,8 +1217,15 @@ func (rc *RunContext) getGithubContext(ctx context.Context) *model.GithubContext
 		ghc.RefType = "branch"
 		ghc.RefName = ghc.Ref[len("refs/heads/"):]
 	}
+}
 
-	return ghc
+// getRunnerContext returns the runner context of the job container with the overrides of --context-file
+func (rc *RunContext) getRunnerContext(ctx context.Context) map[string]interface{} {
+	var runner map[string]interface{}
+	if rc.JobContainer != nil {
+		runner = rc.JobContainer.GetRunnerContext(ctx)
+	}
+	return rc.Config.ContextOverrides.applyRunner(runner)
 }
 
 func isLocalCheckout(ghc *model.GithubContext, step *model.Step) bool {
@@ -688,17 +1280,19 @@ func nestedMapLookup(m map[string]interface{}, ks ...string) (rval interface{})
 	}
 }
 
+// ciMarkers are the variables tools check to detect a CI environment, they are removed with Config.NoSimulateCI
+var ciMarkers = []string{"CI", "GITHUB_ACTIONS", "CONTINUOUS_INTEGRATION", "BUILD_ID", "BUILD_NUMBER", "CI_NAME", "RUN_ID"}
+
 func (rc *RunContext) withGithubEnv(ctx context.Context, github *model.GithubContext, env map[string]string) map[string]string {
-	env["CI"] = "true"
 	env["GITHUB_ENV"] = rc.JobContainer.GetActPath() + "/workflow/envs.txt"
 	env["GITHUB_WORKFLOW"] = github.Workflow
 	env["GITHUB_RUN_ID"] = github.RunID
 	env["GITHUB_RUN_NUMBER"] = github.RunNumber
+	env["GITHUB_RUN_ATTEMPT"] = github.RunAttempt
 	env["GITHUB_ACTION"] = github.Action
 	env["GITHUB_ACTION_PATH"] = github.ActionPath
 	env["GITHUB_ACTION_REPOSITORY"] = github.ActionRepository
 	env["GITHUB_ACTION_REF"] = github.ActionRef
-	env["GITHUB_ACTIONS"] = "true"
 	env["GITHUB_ACTOR"] = github.Actor
 	env["GITHUB_REPOSITORY"] = github.Repository
 	env["GITHUB_EVENT_NAME"] = github.EventName
@@ -709,9 +1303,9 @@ func (rc *RunContext) withGithubEnv(ctx context.Context, github *model.GithubCon
 	env["GITHUB_REF_NAME"] = github.RefName
 	env["GITHUB_REF_TYPE"] = github.RefType
 	env["GITHUB_TOKEN"] = github.Token
-	env["GITHUB_SERVER_URL"] = "https://github.com"
-	env["GITHUB_API_URL"] = "https://api.github.com"
-	env["GITHUB_GRAPHQL_URL"] = "https://api.github.com/graphql"
+	env["GITHUB_SERVER_URL"] = github.ServerURL
+	env["GITHUB_API_URL"] = github.APIURL
+	env["GITHUB_GRAPHQL_URL"] = github.GraphQLURL
 	env["GITHUB_BASE_REF"] = github.BaseRef
 	env["GITHUB_HEAD_REF"] = github.HeadRef
 	env["GITHUB_JOB"] = rc.JobName
@@ -719,15 +1313,26 @@ func (rc *RunContext) withGithubEnv(ctx context.Context, github *model.GithubCon
 	env["GITHUB_RETENTION_DAYS"] = github.RetentionDays
 	env["RUNNER_PERFLOG"] = github.RunnerPerflog
 	env["RUNNER_TRACKING_ID"] = github.RunnerTrackingID
-	if rc.Config.GitHubInstance != "github.com" {
-		env["GITHUB_SERVER_URL"] = fmt.Sprintf("https://%s", rc.Config.GitHubInstance)
-		env["GITHUB_API_URL"] = fmt.Sprintf("https://%s/api/v3", rc.Config.GitHubInstance)
-		env["GITHUB_GRAPHQL_URL"] = fmt.Sprintf("https://%s/api/graphql", rc.Config.GitHubInstance)
+
+	if rc.Config.NoSimulateCI {
+		// tools detecting a CI environment should behave as on a developer machine
+		for _, name := range ciMarkers {
+			delete(env, name)
+		}
+	} else {
+		env["CI"] = "true"
+		env["GITHUB_ACTIONS"] = "true"
 	}
 
 	if rc.Config.ArtifactServerPath != "" {
 		setActionRuntimeVars(rc, env)
 	}
+	if rc.Config.CacheServerPath != "" {
+		setActionCacheVars(rc, env)
+	}
+	if rc.Config.FixturesServerPath != "" {
+		setFixturesVars(rc, env)
+	}
 
 	job := rc.Run.Job()
 	if job.RunsOn() != nil {
@@ -751,12 +1356,53 @@ func (rc *RunContext) withGithubEnv(ctx context.Context, github *model.GithubCon
 func setActionRuntimeVars(rc *RunContext, env map[string]string) {
 	actionsRuntimeURL := os.Getenv("ACTIONS_RUNTIME_URL")
 	if actionsRuntimeURL == "" {
-		actionsRuntimeURL = fmt.Sprintf("http://%s:%s/", rc.Config.ArtifactServerAddr, rc.Config.ArtifactServerPort)
+		host := rc.Config.ArtifactServerAddr
+		if rc.hostAddress != nil {
+			host = rc.hostAddress.Host
+		}
+		actionsRuntimeURL = fmt.Sprintf("http://%s/", net.JoinHostPort(host, rc.Config.ArtifactServerPort))
+		actionsRuntimeURL = common.NamespacedRuntimeURL(actionsRuntimeURL, rc.artifactNamespace())
 	}
 	env["ACTIONS_RUNTIME_URL"] = actionsRuntimeURL
+	setActionRuntimeToken(rc, env)
+}
 
+// setActionCacheVars points actions/cache to the cache server
+func setActionCacheVars(rc *RunContext, env map[string]string) {
+	actionsCacheURL := os.Getenv("ACTIONS_CACHE_URL")
+	if actionsCacheURL == "" {
+		host := rc.Config.ArtifactServerAddr
+		if rc.hostAddress != nil {
+			host = rc.hostAddress.Host
+		}
+		actionsCacheURL = fmt.Sprintf("http://%s/", net.JoinHostPort(host, rc.Config.CacheServerPort))
+		actionsCacheURL = artifactcache.ScopedURL(actionsCacheURL, rc.Config.BranchScope)
+	}
+	env["ACTIONS_CACHE_URL"] = actionsCacheURL
+	if _, ok := env["ACTIONS_RUNTIME_TOKEN"]; !ok {
+		setActionRuntimeToken(rc, env)
+	}
+}
+
+// setFixturesVars points the jobs to the fixtures server
+func setFixturesVars(rc *RunContext, env map[string]string) {
+	host := rc.Config.ArtifactServerAddr
+	if rc.hostAddress != nil {
+		host = rc.hostAddress.Host
+	}
+	env[fixtures.URLEnv] = fmt.Sprintf("http://%s/", net.JoinHostPort(host, rc.Config.FixturesServerPort))
+}
+
+// setActionRuntimeToken sets the token of the job authenticating it to the artifact and cache servers
+func setActionRuntimeToken(rc *RunContext, env map[string]string) {
 	actionsRuntimeToken := os.Getenv("ACTIONS_RUNTIME_TOKEN")
-	if actionsRuntimeToken == "" {
+	if actionsRuntimeToken == "" && rc.Config.RuntimeTokens != nil {
+		actionsRuntimeToken = rc.Config.RuntimeTokens.Issue(common.RuntimeTokenClaims{
+			RunID: common.NamespacedRunID(env["GITHUB_RUN_ID"], rc.artifactNamespace()),
+			Job:   rc.String(),
+		})
+		rc.AddMask(actionsRuntimeToken)
+	} else if actionsRuntimeToken == "" {
 		actionsRuntimeToken = "token"
 	}
 	env["ACTIONS_RUNTIME_TOKEN"] = actionsRuntimeToken
@@ -767,7 +1413,7 @@ func (rc *RunContext) handleCredentials(ctx context.Context) (username, password
 	username = rc.Config.Secrets["DOCKER_USERNAME"]
 	password = rc.Config.Secrets["DOCKER_PASSWORD"]
 
-	container := rc.Run.Job().Container()
+	container := rc.jobContainer(ctx)
 	if container == nil || container.Credentials == nil {
 		return
 	}
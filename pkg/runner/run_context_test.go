@@ -10,10 +10,12 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/nektos/act/pkg/common"
 	"github.com/nektos/act/pkg/exprparser"
 	"github.com/nektos/act/pkg/model"
 
 	log "github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
 	assert "github.com/stretchr/testify/assert"
 	yaml "gopkg.in/yaml.v3"
 )
@@ -338,6 +340,92 @@ func TestRunContext_GetBindsAndMounts(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("MatrixWorkspaceTest", func(t *testing.T) {
+		tests := []struct {
+			matrixWorkspace string
+			jobTotal        int
+			wantbind        string
+			wantmount       bool
+		}{
+			{MatrixWorkspaceIsolated, 1, "/mnt/linux:/mnt/linux", false},
+			{MatrixWorkspaceIsolated, 2, "", true},
+			{MatrixWorkspaceBase, 2, "/mnt/linux:/var/run/act-workspace:ro", true},
+			{MatrixWorkspaceShared, 2, "/mnt/linux:/mnt/linux", false},
+		}
+
+		for _, testcase := range tests {
+			testcase := testcase
+			t.Run(fmt.Sprintf("%s-%d", testcase.matrixWorkspace, testcase.jobTotal), func(t *testing.T) {
+				if runtime.GOOS != "linux" {
+					t.Skip("the workdir is a linux path")
+				}
+				rc := &RunContext{
+					Name:     "TestRCName",
+					jobTotal: testcase.jobTotal,
+					Run: &model.Run{
+						Workflow: &model.Workflow{
+							Name: "TestWorkflowName",
+						},
+					},
+					Config: &Config{
+						Workdir:         "/mnt/linux",
+						BindWorkdir:     true,
+						MatrixWorkspace: testcase.matrixWorkspace,
+					},
+				}
+
+				gotbind, gotmount := rc.GetBindsAndMounts()
+
+				if testcase.wantbind != "" {
+					assert.Contains(t, gotbind, testcase.wantbind)
+				} else {
+					assert.Len(t, gotbind, 1, "only the docker socket is bound")
+				}
+				if testcase.wantmount {
+					assert.Equal(t, "/mnt/linux", gotmount[rc.jobContainerName()])
+				} else {
+					assert.NotContains(t, gotmount, rc.jobContainerName())
+				}
+			})
+		}
+	})
+
+	t.Run("CacheVolumesTest", func(t *testing.T) {
+		rc := &RunContext{
+			Name: "TestRCName",
+			Run: &model.Run{
+				Workflow: &model.Workflow{
+					Name: "TestWorkflowName",
+				},
+			},
+			Config: &Config{
+				CacheVolumes: map[string]string{"go-build": "/root/.cache/go-build"},
+			},
+		}
+
+		_, gotmount := rc.GetBindsAndMounts()
+
+		assert.Equal(t, "/root/.cache/go-build", gotmount["act-cache-go-build"])
+	})
+
+	t.Run("DockerHostTest", func(t *testing.T) {
+		rc := &RunContext{
+			Name: "TestRCName",
+			Run: &model.Run{
+				Workflow: &model.Workflow{
+					Name: "TestWorkflowName",
+				},
+			},
+			Config: &Config{
+				ContainerDaemonSocket: "unix:///Users/act/.colima/default/docker.sock",
+			},
+		}
+
+		gotbind, _ := rc.GetBindsAndMounts()
+
+		assert.Contains(t, gotbind, "/var/run/docker.sock:/var/run/docker.sock")
+	})
 }
 
 func TestGetGitHubContext(t *testing.T) {
@@ -392,6 +480,10 @@ func TestGetGitHubContext(t *testing.T) {
 	assert.Equal(t, ghc.RepositoryOwner, owner)
 	assert.Equal(t, ghc.RunnerPerflog, "/dev/null")
 	assert.Equal(t, ghc.Token, rc.Config.Secrets["GITHUB_TOKEN"])
+	assert.Equal(t, ghc.RunAttempt, "1")
+
+	rc.Config.RunAttempt = 3
+	assert.Equal(t, rc.getGithubContext(context.Background()).RunAttempt, "3")
 }
 
 func TestGetGithubContextRef(t *testing.T) {
@@ -569,6 +661,18 @@ if: always()`, ""),
 	})
 	rc.Run.JobID = "job2"
 	assertObject.True(rc.isEnabled(context.Background()))
+
+	// activity types
+	rc = createIfTestRunContext(map[string]*model.Job{
+		"job1": createJob(t, `runs-on: ubuntu-latest`, ""),
+	})
+	assertObject.NoError(yaml.Unmarshal([]byte("merge_group:\n  types: [checks_requested]\n"), &rc.Run.Workflow.RawOn))
+	rc.Config.EventName = "merge_group"
+	rc.EventJSON = `{"action": "checks_requested"}`
+	assertObject.True(rc.isEnabled(context.Background()))
+
+	rc.EventJSON = `{"action": "destroyed"}`
+	assertObject.False(rc.isEnabled(context.Background()))
 }
 
 func TestRunContextGetEnv(t *testing.T) {
@@ -621,3 +725,422 @@ func TestRunContextGetEnv(t *testing.T) {
 		})
 	}
 }
+
+func TestRunContextWithGithubEnvSimulateCI(t *testing.T) {
+	rc := &RunContext{
+		Config: &Config{},
+		Run: &model.Run{
+			Workflow: &model.Workflow{
+				Jobs: map[string]*model.Job{"test": {Name: "test"}},
+			},
+			JobID: "test",
+		},
+		JobContainer: &containerMock{},
+	}
+
+	env := rc.withGithubEnv(context.Background(), &model.GithubContext{}, map[string]string{})
+	assert.Equal(t, "true", env["CI"])
+	assert.Equal(t, "true", env["GITHUB_ACTIONS"])
+
+	rc.Config.NoSimulateCI = true
+	env = rc.withGithubEnv(context.Background(), &model.GithubContext{}, map[string]string{"GITHUB_ACTIONS": "true", "CI": "true", "CONTINUOUS_INTEGRATION": "1", "BUILD_NUMBER": "7", "HOME": "/root"})
+	assert.NotContains(t, env, "CI")
+	assert.NotContains(t, env, "GITHUB_ACTIONS")
+	assert.NotContains(t, env, "CONTINUOUS_INTEGRATION")
+	assert.NotContains(t, env, "BUILD_NUMBER")
+	assert.Equal(t, "/root", env["HOME"])
+}
+
+func TestRunContextInterpolateOutputs(t *testing.T) {
+	run := &model.Run{
+		Workflow: &model.Workflow{
+			Jobs: map[string]*model.Job{"test": {
+				Name: "test",
+				Outputs: map[string]string{
+					"version": "${{ steps.build.outputs.version }}",
+					"leak":    "token=${{ steps.build.outputs.token }}",
+				},
+			}},
+		},
+		JobID: "test",
+	}
+	newLeg := func(version string) *RunContext {
+		rc := &RunContext{
+			Config: &Config{Secrets: map[string]string{"TOKEN": "s3cr3t"}},
+			Run:    run,
+			Matrix: map[string]interface{}{"version": version},
+			StepResults: map[string]*model.StepResult{
+				"build": {Outputs: map[string]string{"version": version, "token": "s3cr3t"}},
+			},
+			outputTemplates: map[string]string{},
+		}
+		for k, v := range run.Job().Outputs {
+			rc.outputTemplates[k] = v
+		}
+		return rc
+	}
+	first, second := newLeg("1.0.0"), newLeg("")
+
+	assert.NoError(t, first.interpolateOutputs()(context.Background()))
+	assert.Equal(t, map[string]string{"version": "1.0.0", "leak": ""}, run.Job().Outputs)
+
+	// the second leg evaluates the original expressions and keeps the value of the first leg
+	assert.NoError(t, second.interpolateOutputs()(context.Background()))
+	assert.Equal(t, map[string]string{"version": "1.0.0", "leak": ""}, run.Job().Outputs)
+}
+
+func TestRunContextStrategyAndJobContext(t *testing.T) {
+	run := &model.Run{
+		Workflow: &model.Workflow{
+			Jobs: map[string]*model.Job{"test": {Name: "test"}},
+		},
+		JobID: "test",
+	}
+	rc := &RunContext{
+		Config:      &Config{Workdir: "."},
+		Run:         run,
+		StepResults: map[string]*model.StepResult{},
+		services: map[string]*model.JobServiceContext{
+			"db": {ID: "abc", Network: "act_default", Ports: map[string]string{"5432": "49153"}},
+		},
+	}
+	ctx := context.Background()
+	rc.ExprEval = rc.NewExpressionEvaluator(ctx)
+
+	// a job without a matrix is a single leg
+	assert.Equal(t, map[string]interface{}{
+		"fail-fast":    true,
+		"job-index":    0,
+		"job-total":    1,
+		"max-parallel": 1,
+	}, rc.getStrategyContext())
+
+	rc.jobIndex, rc.jobTotal = 2, 3
+	rc.ExprEval = rc.NewExpressionEvaluator(ctx)
+	for expr, want := range map[string]interface{}{
+		"strategy.job-index":            2,
+		"strategy.job-total":            3,
+		"job.services.db.id":            "abc",
+		"job.services.db.network":       "act_default",
+		"job.services.db.ports['5432']": "49153",
+	} {
+		out, err := rc.ExprEval.evaluate(ctx, expr, exprparser.DefaultStatusCheckNone)
+		assert.NoError(t, err, expr)
+		assert.EqualValues(t, want, out, expr)
+	}
+}
+
+func TestRunContextReusableContainerName(t *testing.T) {
+	workflow := &model.Workflow{
+		File: "test.yml",
+		Name: "test",
+		Jobs: map[string]*model.Job{"build": {Name: "build"}},
+	}
+	newRunContext := func(reuse bool, workdir string, matrix map[string]interface{}) *RunContext {
+		return &RunContext{
+			Name:   "build",
+			Config: &Config{Workdir: workdir, ReuseContainers: reuse},
+			Run:    &model.Run{Workflow: workflow, JobID: "build"},
+			Matrix: matrix,
+		}
+	}
+
+	rc := newRunContext(false, "/repo", nil)
+	assert.Equal(t, "act-test-build", rc.jobContainerName())
+	assert.Equal(t, "act-test-build-lint", rc.stepContainerName("lint"))
+	assert.Nil(t, rc.containerLabels())
+
+	rc = newRunContext(true, "/repo", map[string]interface{}{"node": 16})
+	assert.Equal(t, "act-test-build-"+rc.reuseKey(), rc.jobContainerName())
+	assert.Equal(t, rc.reuseKey(), rc.containerLabels()["com.github.nektos.act.reuse-key"])
+	assert.Equal(t, rc.reuseKey(), newRunContext(true, "/repo", map[string]interface{}{"node": 16}).reuseKey())
+
+	// the containers of other matrix legs or repositories are not shared
+	assert.NotEqual(t, rc.reuseKey(), newRunContext(true, "/repo", map[string]interface{}{"node": 18}).reuseKey())
+	assert.NotEqual(t, rc.reuseKey(), newRunContext(true, "/other", map[string]interface{}{"node": 16}).reuseKey())
+
+	// nor the containers of the branches of --watch
+	branch := newRunContext(true, "/repo", map[string]interface{}{"node": 16})
+	branch.Config.BranchScope = "feature"
+	assert.NotEqual(t, rc.reuseKey(), branch.reuseKey())
+	assert.Equal(t, "feature", branch.containerLabels()["com.github.nektos.act.branch"])
+	assert.NotContains(t, rc.containerLabels(), "com.github.nektos.act.branch")
+}
+
+func TestRunContextWorkflowNamespace(t *testing.T) {
+	ci := &model.Workflow{File: "ci.yml", Name: "CI", Jobs: map[string]*model.Job{"build": {}}}
+	nightly := &model.Workflow{File: "nightly/ci.yaml", Name: "CI", Jobs: map[string]*model.Job{"build": {}}}
+	plan := &model.Plan{Stages: []*model.Stage{
+		{Runs: []*model.Run{{Workflow: ci, JobID: "build"}, {Workflow: nightly, JobID: "build"}}},
+	}}
+	single := &model.Plan{Stages: []*model.Stage{{Runs: []*model.Run{{Workflow: ci, JobID: "build"}}}}}
+
+	assert.False(t, namespacedByFile("", plan), "the jobs are only namespaced on request")
+	assert.True(t, namespacedByFile(WorkflowPrefixAuto, plan))
+	assert.False(t, namespacedByFile(WorkflowPrefixAuto, single))
+	assert.True(t, namespacedByFile(WorkflowPrefixFile, single))
+	assert.False(t, namespacedByFile(WorkflowPrefixName, plan))
+
+	newRunContext := func(workflow *model.Workflow, namespaced bool) *RunContext {
+		rc := &RunContext{
+			Name:   "build",
+			Config: &Config{Workdir: "/repo", Env: map[string]string{"GITHUB_RUN_ID": "42"}},
+			Run:    &model.Run{Workflow: workflow, JobID: "build"},
+		}
+		if namespaced {
+			rc.workflowNamespace = workflow.File
+		}
+		return rc
+	}
+
+	rc := newRunContext(ci, false)
+	assert.Equal(t, "CI/build", rc.String())
+	assert.Equal(t, "42", rc.getGithubContext(context.Background()).RunID)
+
+	rc, other := newRunContext(ci, true), newRunContext(nightly, true)
+	assert.Equal(t, "ci.yml/build", rc.String())
+	assert.Equal(t, "nightly/ci.yaml/build", other.String())
+	assert.NotEqual(t, rc.jobContainerName(), other.jobContainerName())
+	assert.Equal(t, "42", rc.getGithubContext(context.Background()).RunID, "GITHUB_RUN_ID stays numeric")
+	assert.Equal(t, "ci", rc.artifactNamespace())
+	assert.Equal(t, "nightly-ci", other.artifactNamespace())
+
+	t.Setenv("ACTIONS_RUNTIME_URL", "")
+	env := map[string]string{"GITHUB_RUN_ID": "42"}
+	setActionRuntimeVars(rc, env)
+	assert.True(t, strings.HasSuffix(env["ACTIONS_RUNTIME_URL"], "/namespace/ci/"), env["ACTIONS_RUNTIME_URL"])
+}
+
+func TestRunContextLogPrefix(t *testing.T) {
+	workflow := &model.Workflow{File: "ci.yml", Name: "CI", Jobs: map[string]*model.Job{"test": {}}}
+	newRunContext := func(prefix string, matrix map[string]interface{}) *RunContext {
+		return &RunContext{
+			Name:     "test-2",
+			JobName:  "test",
+			Config:   &Config{LogPrefix: prefix},
+			Run:      &model.Run{Workflow: workflow, JobID: "test"},
+			Matrix:   matrix,
+			jobIndex: 1,
+			jobTotal: 3,
+		}
+	}
+
+	assert.Equal(t, "CI/test-2", newRunContext("", nil).logPrefix())
+	assert.Equal(t, "CI/test/16,ubuntu", newRunContext("{workflow}/{job}/{matrix}", map[string]interface{}{"os": "ubuntu", "node": 16}).logPrefix())
+	assert.Equal(t, "CI/test", newRunContext("{workflow}/{job}/{matrix}", nil).logPrefix())
+	assert.Equal(t, "ci.yml:test-2", newRunContext("{file}:{jobID}-{index}", nil).logPrefix())
+	assert.Equal(t, "test", newRunContext("{matrix}-{job}", nil).logPrefix())
+	assert.Equal(t, "", newRunContext("{matrix}/{matrix}", nil).logPrefix())
+	// the separators of the template itself are kept
+	assert.Equal(t, "CI -- test", newRunContext("{workflow} -- {job}", nil).logPrefix())
+	assert.Equal(t, "[CI]  test", newRunContext("[{workflow}]  {job}{matrix}", nil).logPrefix())
+	assert.Equal(t, "CI--test", newRunContext("{workflow}--{matrix}-{job}", nil).logPrefix())
+
+	rc := newRunContext("", nil)
+	rc.Config.Repository = "service-a"
+	assert.Equal(t, "service-a/CI/test-2", rc.logPrefix())
+	rc.Config.LogPrefix = "{job}"
+	assert.Equal(t, "service-a/test", rc.logPrefix())
+}
+
+func TestRunContextRunName(t *testing.T) {
+	newRunContext := func(runName string) *RunContext {
+		workflow, err := model.ReadWorkflow(strings.NewReader(fmt.Sprintf(`
+name: deploy
+run-name: %s
+on:
+  workflow_dispatch:
+    inputs:
+      environment:
+        default: production
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+    - run: echo
+`, runName)))
+		assert.NoError(t, err)
+		return &RunContext{
+			Config:    &Config{EventName: "workflow_dispatch", Actor: "nektos", Workdir: "."},
+			EventJSON: `{"inputs": {"environment": "staging"}}`,
+			Run:       &model.Run{Workflow: workflow, JobID: "test"},
+		}
+	}
+
+	ctx := context.Background()
+	assert.Equal(t, "Deploy to staging by @nektos", newRunContext("Deploy to ${{ inputs.environment }} by @${{ github.actor }}").runName(ctx))
+	assert.Equal(t, "deploy", newRunContext("''").runName(ctx))
+	assert.Equal(t, "deploy", newRunContext("${{ inputs.missing }}").runName(ctx))
+
+	// the runner evaluates the run-name without setting up a job
+	rc := newRunContext("Deploy to ${{ inputs.environment }}")
+	runner := &runnerImpl{config: rc.Config, eventJSON: rc.EventJSON}
+	logger, hook := test.NewNullLogger()
+	plan := &model.Plan{Stages: []*model.Stage{{Runs: []*model.Run{rc.Run, rc.Run}}}}
+	assert.NoError(t, runner.logRunNames(plan)(common.WithLogger(ctx, logger)))
+	// the git context of the repository logs too, e.g. on a detached HEAD
+	runNames := make([]string, 0)
+	for _, entry := range hook.AllEntries() {
+		if strings.HasPrefix(entry.Message, "\U0001F3F7") {
+			runNames = append(runNames, entry.Message)
+		}
+	}
+	if assert.Len(t, runNames, 1, "the run-name of a workflow is logged once") {
+		assert.Contains(t, runNames[0], ": Deploy to staging")
+	}
+}
+
+func TestRunContextEvaluateEnv(t *testing.T) {
+	workflow := &model.Workflow{
+		Name: "test",
+		Env: map[string]string{
+			"DAY_OF_WEEK": "Monday",
+			"WORKFLOW":    "${{ github.workflow }}",
+		},
+		Jobs: map[string]*model.Job{
+			"job": createJob(t, `
+env:
+  Greeting: Hello
+  FROM_WORKFLOW: ${{ env.DAY_OF_WEEK }}
+  LEG: ${{ matrix.leg }}
+`, ""),
+		},
+	}
+	rc := &RunContext{
+		Config: &Config{Env: map[string]string{"Greeting": "Hi"}},
+		Run:    &model.Run{Workflow: workflow, JobID: "job"},
+		Matrix: map[string]interface{}{"leg": "one"},
+	}
+	rc.evaluateEnv(context.Background())
+
+	assert.Equal(t, "Monday", rc.Env["DAY_OF_WEEK"])
+	assert.Equal(t, "test", rc.Env["WORKFLOW"])
+	assert.Equal(t, "", rc.Env["FROM_WORKFLOW"])
+	assert.Equal(t, "one", rc.Env["LEG"])
+	assert.Equal(t, "Hi", rc.Env["Greeting"])
+
+	assert.Equal(t, map[string]string{"ACT": "true", "Greeting": "Hi"}, rc.jobIfEnv())
+}
+
+func TestRunContextPropagateEnv(t *testing.T) {
+	workflow := &model.Workflow{
+		Name: "test",
+		Jobs: map[string]*model.Job{
+			"build": createJob(t, `
+outputs:
+  version: ${{ steps.version.outputs.version }}
+`, ""),
+			"deploy": createJob(t, `
+needs: build
+env:
+  TARGET: staging
+`, ""),
+		},
+	}
+	build := &RunContext{
+		Config: &Config{PropagateEnvToNeeds: true},
+		Run:    &model.Run{Workflow: workflow, JobID: "build"},
+	}
+	build.propagateEnv(map[string]string{"VERSION": "1.2.3", "TARGET": "production"})
+	assert.Equal(t, "1.2.3", workflow.Jobs["build"].Outputs["ACT_ENV_VERSION"])
+	assert.Equal(t, "${{ steps.version.outputs.version }}", workflow.Jobs["build"].Outputs["version"])
+
+	deploy := &RunContext{
+		Config: &Config{PropagateEnvToNeeds: true},
+		Run:    &model.Run{Workflow: workflow, JobID: "deploy"},
+	}
+	deploy.evaluateEnv(context.Background())
+	assert.Equal(t, "1.2.3", deploy.Env["VERSION"])
+	assert.Equal(t, "staging", deploy.Env["TARGET"], "the env of the job takes precedence")
+
+	deploy = &RunContext{
+		Config: &Config{},
+		Run:    &model.Run{Workflow: workflow, JobID: "deploy"},
+	}
+	deploy.evaluateEnv(context.Background())
+	assert.NotContains(t, deploy.Env, "VERSION", "the variables are only propagated with --propagate-env-to-needs")
+}
+
+func TestRunContextJobContainer(t *testing.T) {
+	tables := []struct {
+		name     string
+		job      string
+		matrix   map[string]interface{}
+		image    string
+		options  string
+		expected string
+	}{
+		{"literal", "container: node:16-bullseye", nil, "node:16-bullseye", "", "node:16-bullseye"},
+		{"matrix image", "container: ${{ matrix.image }}", map[string]interface{}{"image": "alpine:3.17"}, "alpine:3.17", "", "alpine:3.17"},
+		{"matrix image of a mapping", "container:\n  image: ${{ matrix.image }}\n  options: --cpus 1", map[string]interface{}{"image": "alpine:3.17"}, "alpine:3.17", "--cpus 1", "alpine:3.17"},
+		{"matrix object", "container: ${{ matrix.container }}", map[string]interface{}{"container": map[string]interface{}{"image": "golang:1.20", "options": "--cpus 2"}}, "golang:1.20", "--cpus 2", "golang:1.20"},
+		// like on GitHub an empty image runs the job on the runner
+		{"empty image", "container: ${{ matrix.image }}", map[string]interface{}{"image": ""}, "", "--default", "node:16-buster-slim"},
+	}
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			workflow := &model.Workflow{
+				Name: "test",
+				Jobs: map[string]*model.Job{"job": createJob(t, "runs-on: ubuntu-latest\n"+table.job, "")},
+			}
+			rc := &RunContext{
+				Config: &Config{
+					Platforms:        map[string]string{"ubuntu-latest": "node:16-buster-slim"},
+					ContainerOptions: "--default",
+				},
+				Run:    &model.Run{Workflow: workflow, JobID: "job"},
+				Matrix: table.matrix,
+			}
+			rc.ExprEval = rc.NewExpressionEvaluator(context.Background())
+
+			c := rc.jobContainer(context.Background())
+			if table.image == "" {
+				assert.Nil(t, c)
+			} else if assert.NotNil(t, c) {
+				assert.Equal(t, table.image, c.Image)
+			}
+			assert.Equal(t, table.options, rc.options(context.Background()))
+			assert.Equal(t, table.expected, rc.platformImage(context.Background()))
+		})
+	}
+}
+
+func TestRunContextRunsOnGroup(t *testing.T) {
+	workflow := &model.Workflow{
+		Name: "test",
+		Jobs: map[string]*model.Job{
+			"group":  createJob(t, "runs-on:\n  group: Large-Runners\n  labels: [ubuntu-latest]", ""),
+			"labels": createJob(t, "runs-on:\n  group: other\n  labels: [ubuntu-latest]", ""),
+			"none":   createJob(t, "runs-on:\n  group: other", ""),
+		},
+	}
+	for jobID, expected := range map[string]string{"group": "catthehacker/ubuntu:full-latest", "labels": "node:16-buster-slim", "none": ""} {
+		rc := &RunContext{
+			Config: &Config{Platforms: map[string]string{"ubuntu-latest": "node:16-buster-slim", "group:large-runners": "catthehacker/ubuntu:full-latest"}},
+			Run:    &model.Run{Workflow: workflow, JobID: jobID},
+		}
+		rc.ExprEval = rc.NewExpressionEvaluator(context.Background())
+		assert.Equal(t, expected, rc.platformImage(context.Background()), jobID)
+	}
+}
+
+func TestRunContextUseHost(t *testing.T) {
+	workflow := &model.Workflow{
+		Name: "test",
+		Jobs: map[string]*model.Job{
+			"build": createJob(t, "runs-on: ubuntu-latest", ""),
+			"test":  createJob(t, "runs-on: macos-latest\ncontainer: node:16-bullseye", ""),
+		},
+	}
+	for _, jobID := range []string{"build", "test"} {
+		rc := &RunContext{
+			Config: &Config{
+				Platforms: map[string]string{"ubuntu-latest": "node:16-buster-slim"},
+				UseHost:   true,
+			},
+			Run: &model.Run{Workflow: workflow, JobID: jobID},
+		}
+		rc.ExprEval = rc.NewExpressionEvaluator(context.Background())
+		assert.Equal(t, "-self-hosted", rc.platformImage(context.Background()), "%s runs on the host", jobID)
+	}
+}
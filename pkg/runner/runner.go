@@ -3,13 +3,21 @@ package runner
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 
 	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/common/git"
 	"github.com/nektos/act/pkg/container"
+	"github.com/nektos/act/pkg/githubapi"
+	"github.com/nektos/act/pkg/logsink"
 	"github.com/nektos/act/pkg/model"
 )
 
@@ -27,6 +35,7 @@ type Config struct {
 	EventPath                          string            // path to JSON file to use for event.json in containers
 	DefaultBranch                      string            // name of the main branch for this repository
 	ReuseContainers                    bool              // reuse containers to maintain state
+	BranchScope                        string            // the branch isolating the reused containers and the caches of the runs of --watch, empty on the default branch
 	ForcePull                          bool              // force pulling of the image, even if already present
 	ForceRebuild                       bool              // force rebuilding local docker image action
 	LogOutput                          bool              // log the output from docker run
@@ -40,30 +49,139 @@ type Config struct {
 	Privileged                         bool              // use privileged mode
 	UsernsMode                         string            // user namespace to use
 	ContainerArchitecture              string            // Desired OS/architecture platform for running containers
-	ContainerDaemonSocket              string            // Path to Docker daemon socket
+	ContainerDaemonSocket              string            // Path to Docker daemon socket, a URI like unix:///path is the docker host of act as well
+	ContainerBackend                   string            // container engine running the containers: docker (default) or podman
 	ContainerOptions                   string            // Options for the job container
+	ContainerRuntime                   string            // OCI runtime of the job, service and step containers, like runsc (gVisor) or kata, the default runtime of the engine if empty
 	UseGitIgnore                       bool              // controls if paths in .gitignore should not be copied into container, default true
 	GitHubInstance                     string            // GitHub instance to use, default "github.com"
+	GitHubServerURL                    string            // overrides the GITHUB_SERVER_URL derived from GitHubInstance
+	GitHubAPIURL                       string            // overrides the GITHUB_API_URL derived from GitHubInstance
+	GitHubGraphQLURL                   string            // overrides the GITHUB_GRAPHQL_URL derived from GitHubInstance
 	ContainerCapAdd                    []string          // list of kernel capabilities to add to the containers
 	ContainerCapDrop                   []string          // list of kernel capabilities to remove from the containers
+	ContainerUsers                     map[string]string // users the job containers run as by platform, "" for all platforms, the user of the image if missing
 	AutoRemove                         bool              // controls if the container is automatically removed upon workflow completion
 	ArtifactServerPath                 string            // the path where the artifact server stores uploads
 	ArtifactServerAddr                 string            // the address the artifact server binds to
 	ArtifactServerPort                 string            // the port the artifact server binds to
+	CacheServerPath                    string            // the path where the cache server of actions/cache stores the entries, disabled if empty
+	CacheServerPort                    string            // the port the cache server binds to, on the address of the artifact server
+	FixturesServerPath                 string            // the directory the fixtures server serves to the jobs at ACT_FIXTURES_URL, disabled if empty
+	FixturesServerPort                 string            // the port the fixtures server binds to, on the address of the artifact server
 	NoSkipCheckout                     bool              // do not skip actions/checkout
 	RemoteName                         string            // remote name in local git repo config
 	ReplaceGheActionWithGithubCom      []string          // Use actions from GitHub Enterprise instance to GitHub
 	ReplaceGheActionTokenWithGithubCom string            // Token of private action repo on GitHub.
+	ComposeServices                    string            // path to a docker-compose file providing the services for the run
+	NoSimulateCI                       bool              // remove the CI detection variables (CI, GITHUB_ACTIONS, ...) from the env of the containers
+	MockActions                        map[string]string // actions (owner/repo@ref, may contain wildcards) replaced by a local action
+	InjectSteps                        []*InjectStep     // steps spliced into the jobs at run time
+	RunAttempt                         int               // attempt of the run, GITHUB_RUN_ATTEMPT of the env when 0
+	Preflight                          bool              // probe the images for the tools the jobs need before running them
+	SimulatePermissions                bool              // restrict the GITHUB_TOKEN of the jobs to their permissions by proxying the GitHub API
+	LogSinks                           []logsink.Sink    // additional destinations of the job logs, the secrets are masked before
+	OutputSizeLimit                    int               // size in bytes of an output of a step and of the outputs of a job, 0 disables the check
+	EnvSizeLimit                       int               // size in bytes of a variable exported through GITHUB_ENV, 0 disables the check
+	StrictLimits                       bool              // fail the steps and jobs exceeding the limits instead of warning
+	PropagateEnvToNeeds                bool              // add the variables exported through GITHUB_ENV to the outputs of the job and the env of the jobs needing it
+	ContextOverrides                   *ContextOverrides // merged into the contexts of the expressions, read from --context-file
+	WorkflowPrefix                     string            // how the jobs are namespaced by their workflow, one of the WorkflowPrefix constants, name if empty
+	ExperimentalGoActions              bool              // run the actions using 'go', which are built in the job container
+	RunnerManifest                     *RunnerManifest   // tools installed into the tool cache of the jobs lacking them, read from --runner-manifest
+	LogPrefix                          string            // template of the prefix of the log lines of a job, e.g. {workflow}/{job}/{matrix}
+	Repository                         string            // name of the repository prepended to the log prefix of the jobs, set when several repositories run
+	MatrixWorkspace                    string            // how the legs of a matrix job get their workspace with BindWorkdir, one of the MatrixWorkspace constants, isolated if empty
+	CACertificates                     string            // PEM encoded certificates installed into the trust stores of the job containers, read from --install-ca
+	SSHAgent                           bool              // forward the SSH agent of SSH_AUTH_SOCK into the job containers
+	SSHAgentKeys                       []string          // private key files served by an agent scoped to the run, forwarded instead of the agent of SSH_AUTH_SOCK
+	GitIdentity                        *GitIdentity      // git identity configured in the job containers, nil to keep the one of the image
+	ProgressInterval                   time.Duration     // interval of the rendering of the states of the jobs while the plan runs, 0 disables it
+	CacheVolumes                       map[string]string // names of the cache volumes managed by act mapped to the paths they are mounted at in the job containers
+	ImageLock                          *ImageLock        // digests the images of the workflows are pinned to, read from act.lock
+	FrozenImageLock                    bool              // fail the jobs using an image which isn't pinned by ImageLock
+	LocalRegistry                      string            // address (host:port) of a throwaway registry the images built by act are pushed to, disabled if empty
+	UseHost                            bool              // run all the jobs on the host like -P <platform>=-self-hosted, without a container engine
+	ConcurrencyDir                     string            // directory the concurrency groups are shared through with the other invocations of act, the groups are shared by the runs of the process if empty
+	TraceExpressions                   bool              // log the evaluated expressions with the values of their context references and function calls
+	EngineRoutes                       []*EngineRoute    // engines running the jobs by the labels of their runs-on, the first matching route wins
+	GithubScript                       string            // how the steps of actions/github-script run, one of the GithubScript constants, auto if empty
+	HangTimeout                        time.Duration     // time without output nor CPU activity after which a step possibly hangs, 0 disables the detection
+	KillHungSteps                      bool              // kill the steps which possibly hang after HangTimeout
+	SkipUnsupported                    bool              // skip the steps using a feature act can't emulate instead of failing them
+	ShellOnError                       bool              // open an interactive shell in the job container when a step fails, before the job goes on
+	StepDebugger                       *StepDebugger     // pauses the jobs before and after their steps with an interactive prompt, nil to run without pausing
+	ExportWorkspace                    string            // directory the workspaces of the jobs are copied into once their steps are done, in a directory per job and matrix leg, disabled if empty
+	MaxParallelJobs                    int               // number of jobs running at once, the legs of the matrixes and the jobs of the reusable workflows included, the number of CPUs of the container engine if 0
+
+	RuntimeTokens        *common.RuntimeTokens       // issues the ACTIONS_RUNTIME_TOKEN of the jobs, the static "token" is used if nil
+	GraphQLFixtures      []*githubapi.GraphQLFixture // responses of the GraphQL API served by the GitHub API proxy instead of GitHub
+	Matrix               map[string]map[string]bool  // values of the matrix keys selecting the legs of the matrix jobs to run, all legs run if empty
+	ContainerCreateHooks []container.CreateHook      // change the requests creating the job, service and step containers, like the patch of --container-create-patch
 }
 
+// Ways to namespace the jobs of a plan by their workflow
+const (
+	WorkflowPrefixAuto = "auto" // by the workflow file if the plan runs several workflow files, otherwise by the name
+	WorkflowPrefixFile = "file" // by the workflow file
+	WorkflowPrefixName = "name" // by the name of the workflow, jobs of workflows with the same name may collide
+)
+
+// Ways the legs of a matrix job get their workspace when the workdir is bound, so legs running concurrently don't see
+// the changes of each other
+const (
+	MatrixWorkspaceIsolated = "isolated" // every leg gets a copy of the workdir in its own volume
+	MatrixWorkspaceBase     = "base"     // like isolated, but the copy is made in the container from the workdir bound read-only
+	MatrixWorkspaceShared   = "shared"   // the legs share the bound workdir
+)
+
+// Ways the steps of actions/github-script run, the shims run the script in the job without the action
+const (
+	GithubScriptAuto   = "auto"   // like api if the GitHub API proxy runs, the proxy answers the GraphQL fixtures, otherwise like action
+	GithubScriptAction = "action" // run actions/github-script
+	GithubScriptAPI    = "api"    // run the script with a shim of octokit sending its calls to GITHUB_API_URL
+	GithubScriptNoop   = "noop"   // run the script with a shim of octokit logging its calls without sending them
+)
+
 type caller struct {
 	runContext *RunContext
 }
 
+// jobID returns the ID of the job calling the reusable workflow, with the index of the leg when a matrix calls it,
+// so the jobs of the workflow called by the legs get distinct names and containers
+func (c *caller) jobID() string {
+	if c.runContext.jobTotal > 1 {
+		return fmt.Sprintf("%s-%d", c.runContext.Run.JobID, c.runContext.jobIndex+1)
+	}
+	return c.runContext.Run.JobID
+}
+
 type runnerImpl struct {
 	config    *Config
 	eventJSON string
 	caller    *caller // the job calling this runner (caller of a reusable workflow)
+	apiProxy  *githubapi.Proxy
+	// the jobs are namespaced by their workflow file, see Config.WorkflowPrefix
+	namespaced bool
+	// how the containers reach the servers on the host, looked up when the plan runs
+	hostAddress *container.HostAddress
+	// socket of the SSH agent forwarded into the job containers, see Config.SSHAgent
+	sshAgentSocket string
+	// states of the jobs rendered while the plan runs, see Config.ProgressInterval
+	progress *progress
+	// concurrency groups of the jobs and workflows, shared with the reusable workflows called by the run
+	concurrency concurrencyLocker
+	// identifier of the run claiming the concurrency groups, shared with the reusable workflows called by the run
+	concurrencyRun string
+	// concurrency groups of the workflows of the plan, claimed by their first job
+	workflowConcurrency   map[string]*workflowConcurrency
+	workflowConcurrencyMu sync.Mutex
+	// docker-compose project providing the services of the run, see Config.ComposeServices
+	composeProject string
+	// masks the values in the logs of the jobs, shared with the reusable workflows called by the run
+	masker *Masker
+	// limits the jobs running at once, shared with the reusable workflows called by the run, see Config.MaxParallelJobs
+	jobSlots jobSlots
 }
 
 // New Creates a new Runner
@@ -76,6 +194,38 @@ func New(runnerConfig *Config) (Runner, error) {
 }
 
 func (runner *runnerImpl) configure() (Runner, error) {
+	if _, err := githubURLs(runner.config); err != nil {
+		return nil, err
+	}
+	switch runner.config.WorkflowPrefix {
+	case "", WorkflowPrefixAuto, WorkflowPrefixFile, WorkflowPrefixName:
+	default:
+		return nil, fmt.Errorf("invalid workflow prefix '%s', expected %s, %s or %s", runner.config.WorkflowPrefix, WorkflowPrefixAuto, WorkflowPrefixFile, WorkflowPrefixName)
+	}
+	switch runner.config.MatrixWorkspace {
+	case "", MatrixWorkspaceIsolated, MatrixWorkspaceBase, MatrixWorkspaceShared:
+	default:
+		return nil, fmt.Errorf("invalid matrix workspace '%s', expected %s, %s or %s", runner.config.MatrixWorkspace, MatrixWorkspaceIsolated, MatrixWorkspaceBase, MatrixWorkspaceShared)
+	}
+	switch runner.config.GithubScript {
+	case "", GithubScriptAuto, GithubScriptAction, GithubScriptAPI, GithubScriptNoop:
+	default:
+		return nil, fmt.Errorf("invalid github-script mode '%s', expected %s, %s, %s or %s", runner.config.GithubScript, GithubScriptAuto, GithubScriptAction, GithubScriptAPI, GithubScriptNoop)
+	}
+
+	if _, err := container.ParseBackend(runner.config.ContainerBackend); err != nil {
+		return nil, err
+	}
+	if runner.config.LocalRegistry != "" {
+		if _, err := parseLocalRegistry(runner.config.LocalRegistry); err != nil {
+			return nil, err
+		}
+	}
+
+	if runner.masker == nil {
+		runner.masker = NewMasker()
+	}
+
 	runner.eventJSON = "{}"
 	if runner.config.EventPath != "" {
 		log.Debugf("Reading event.json from %s", runner.config.EventPath)
@@ -93,16 +243,110 @@ func (runner *runnerImpl) configure() (Runner, error) {
 			return nil, err
 		}
 		runner.eventJSON = string(eventJSON)
+	} else if event := runner.synthesizeEvent(); event != nil {
+		eventJSON, err := json.Marshal(event)
+		if err != nil {
+			return nil, err
+		}
+		runner.eventJSON = string(eventJSON)
+	}
+	if err := checkEventPayloadLimits(runner.config.EventName, runner.eventJSON); err != nil {
+		if runner.config.EventPath != "" {
+			return nil, fmt.Errorf("%s: %w", runner.config.EventPath, err)
+		}
+		return nil, err
 	}
 	return runner, nil
 }
 
+// synthesizeEvent returns a payload of the event of the run for the HEAD of the workdir, nil if act doesn't synthesize
+// the payloads of the event
+func (runner *runnerImpl) synthesizeEvent() map[string]interface{} {
+	ctx := context.Background()
+	_, sha, err := git.FindGitRevision(ctx, runner.config.Workdir)
+	if err != nil {
+		log.Debugf("unable to get the git revision of the synthesized event: %v", err)
+	}
+	event := model.SynthesizeEvent(runner.config.EventName, runner.config.DefaultBranch, sha)
+	if event != nil {
+		log.Infof("Using a synthesized payload of the %s event, pass --eventpath to use another one", runner.config.EventName)
+	}
+	return event
+}
+
+// namespacedByFile returns whether the jobs of the plan are namespaced by their workflow file
+func namespacedByFile(prefix string, plan *model.Plan) bool {
+	switch prefix {
+	case WorkflowPrefixFile:
+		return true
+	case "", WorkflowPrefixName:
+		return false
+	}
+	files := map[string]bool{}
+	for _, stage := range plan.Stages {
+		for _, run := range stage.Runs {
+			files[run.Workflow.File] = true
+		}
+	}
+	return len(files) > 1
+}
+
+// githubURLs returns the URLs of the GitHub instance of the config
+func githubURLs(config *Config) (*githubapi.URLs, error) {
+	instance := config.GitHubInstance
+	if instance == "" {
+		instance = "github.com"
+	}
+	urls, err := githubapi.NewURLs(instance)
+	if err != nil {
+		return nil, err
+	}
+	if err := urls.Override(config.GitHubServerURL, config.GitHubAPIURL, config.GitHubGraphQLURL); err != nil {
+		return nil, err
+	}
+	return urls, nil
+}
+
+// githubServerURL returns the URL of the web interface and git of the GitHub instance of the config, actions and
+// reusable workflows are cloned from it
+func githubServerURL(config *Config) string {
+	urls, err := githubURLs(config)
+	if err != nil {
+		return "https://" + strings.TrimSuffix(config.GitHubInstance, "/")
+	}
+	return urls.ServerURL
+}
+
 // NewPlanExecutor ...
 func (runner *runnerImpl) NewPlanExecutor(plan *model.Plan) common.Executor {
+	if runner.config.EventName == "workflow_call" && runner.caller == nil {
+		if err := runner.validateWorkflowCall(plan); err != nil {
+			return common.NewErrorExecutor(err)
+		}
+	}
+	if runner.config.EventName == "workflow_dispatch" && runner.caller == nil {
+		if err := runner.validateWorkflowDispatch(plan); err != nil {
+			return common.NewErrorExecutor(err)
+		}
+	}
+
+	runner.namespaced = namespacedByFile(runner.config.WorkflowPrefix, plan)
+	if runner.concurrency == nil {
+		runner.concurrency = processConcurrency
+		if runner.config.ConcurrencyDir != "" {
+			runner.concurrency = newConcurrencyDir(runner.config.ConcurrencyDir)
+		}
+		runner.concurrencyRun = newConcurrencyRun()
+	}
+	if runner.jobSlots == nil {
+		runner.jobSlots = newJobSlots(runner.config.MaxParallelJobs)
+	}
+
 	maxJobNameLen := 0
 
 	stagePipeline := make([]common.Executor, 0)
 	for i := range plan.Stages {
+		stageIndex := i
 		stage := plan.Stages[i]
 		stagePipeline = append(stagePipeline, func(ctx context.Context) error {
 			pipeline := make([]common.Executor, 0)
@@ -117,45 +361,241 @@ func (runner *runnerImpl) NewPlanExecutor(plan *model.Plan) common.Executor {
 					}
 				}
 				matrixes := job.GetMatrixes()
+				// the legs keep the index they have in the whole matrix, the selected legs run with their usual names
+				indexes := make([]int, 0, len(matrixes))
+				for i, matrix := range matrixes {
+					if selectsMatrix(runner.config.Matrix, matrix) {
+						indexes = append(indexes, i)
+					}
+				}
+				if len(indexes) == 0 {
+					log.Infof("Skipping job '%s', no leg of its matrix is selected by --matrix", run.String())
+					runner.progress.jobSkipped(run)
+					continue
+				}
 				maxParallel := 4
 				if job.Strategy != nil {
 					maxParallel = job.Strategy.MaxParallel
 				}
 
-				if len(matrixes) < maxParallel {
-					maxParallel = len(matrixes)
+				if len(indexes) < maxParallel {
+					maxParallel = len(indexes)
 				}
+				runner.progress.jobScheduled(run, len(indexes), maxParallel)
+				failFast := &failFast{enabled: job.Strategy != nil && job.Strategy.FailFast && len(indexes) > 1}
 
-				for i, matrix := range matrixes {
-					matrix := matrix
+				for _, i := range indexes {
+					matrix := matrixes[i]
 					rc := runner.newRunContext(ctx, run, matrix)
 					rc.JobName = rc.Name
+					rc.jobIndex = i
+					rc.jobTotal = len(matrixes)
 					if len(matrixes) > 1 {
 						rc.Name = fmt.Sprintf("%s-%d", rc.Name, i+1)
 					}
-					if len(rc.String()) > maxJobNameLen {
-						maxJobNameLen = len(rc.String())
+					if len(rc.logPrefix()) > maxJobNameLen {
+						maxJobNameLen = len(rc.logPrefix())
 					}
 					stageExecutor = append(stageExecutor, func(ctx context.Context) error {
-						jobName := fmt.Sprintf("%-*s", maxJobNameLen, rc.String())
-						return rc.Executor()(common.WithJobErrorContainer(WithJobLogger(ctx, rc.Run.JobID, jobName, rc.Config, &rc.Masks, matrix)))
+						jobName := fmt.Sprintf("%-*s", maxJobNameLen, rc.logPrefix())
+						ctx = WithJobLogger(ctx, rc.Run.JobID, jobName, rc.Config, rc.Masks, matrix)
+						if leg, _ := failFast.failedLeg(); leg != "" {
+							common.Logger(ctx).WithField("jobResult", "cancelled").Infof("\U0001F3C1  Job cancelled, fail-fast after the failure of '%s'", leg)
+							if rc.Run.Job().Result != "failure" {
+								rc.result("cancelled")
+							}
+							runner.progress.legStarted(rc.Run)
+							runner.progress.legFinished(rc.Run)
+							return nil
+						}
+						ctx, release, err := runner.acquireConcurrency(ctx, rc)
+						if errors.Is(err, errConcurrencyCancelled) {
+							// like on GitHub the pending job of an older run is cancelled
+							common.Logger(ctx).WithField("jobResult", "cancelled").Infof("\U0001F3C1  Job cancelled, a newer run is queued in its concurrency group")
+							rc.result("cancelled")
+							runner.progress.legStarted(rc.Run)
+							runner.progress.legFinished(rc.Run)
+							return nil
+						} else if err != nil {
+							return err
+						}
+						defer release()
+						if rc.Run.Job().Type() == model.JobTypeDefault {
+							// the jobs calling a reusable workflow don't take a slot, their jobs do
+							releaseSlot, err := runner.jobSlots.acquire(ctx)
+							if err != nil {
+								return err
+							}
+							defer releaseSlot()
+						}
+						revoke, err := rc.registerAPIToken(ctx)
+						if err != nil {
+							return err
+						}
+						defer revoke()
+						runner.progress.legStarted(rc.Run)
+						defer runner.progress.legFinished(rc.Run)
+						ctx = rc.withEngine(ctx)
+						// the identifiers of the job in its JobResult, to join the JSON logs with the results
+						ctx = common.WithLogger(ctx, common.Logger(ctx).WithFields(log.Fields{
+							"workflow": rc.Run.Workflow.File,
+							"jobName":  rc.logPrefix(),
+							"runID":    rc.getGithubContext(ctx).RunID,
+							"runName":  rc.runName(ctx),
+						}))
+						ctx = common.WithJobErrorContainer(ctx)
+						err = rc.newDiagnosticsExecutor(rc.Executor())(ctx)
+						if err != nil || common.JobError(ctx) != nil {
+							failFast.fail(ctx, rc, err)
+						}
+						return err
 					})
 				}
-				pipeline = append(pipeline, common.NewParallelExecutor(maxParallel, stageExecutor...))
+				legs := common.NewParallelExecutor(maxParallel, stageExecutor...)
+				pipeline = append(pipeline, func(ctx context.Context) error {
+					legsCtx, cancel := context.WithCancel(ctx)
+					defer cancel()
+					failFast.cancel = cancel
+					err := legs(legsCtx)
+					if leg, legErr := failFast.failedLeg(); leg != "" && ctx.Err() == nil {
+						// the legs cancelled by fail-fast don't fail the run with their cancellation
+						return legErr
+					}
+					return err
+				})
 			}
 			var ncpu int
-			info, err := container.GetHostInfo(ctx)
-			if err != nil {
+			if runner.config.MaxParallelJobs > 0 {
+				ncpu = runner.config.MaxParallelJobs
+			} else if runner.config.UseHost {
+				// there may be no container engine on the host
+				ncpu = runtime.NumCPU()
+			} else if info, err := container.GetHostInfo(ctx); err != nil {
 				log.Errorf("failed to obtain container engine info: %s", err)
 				ncpu = 1 // sane default?
 			} else {
 				ncpu = info.NCPU
 			}
+			runner.progress.stageStarted(stageIndex, ncpu)
 			return common.NewParallelExecutor(ncpu, pipeline...)(ctx)
 		})
 	}
 
-	return common.NewPipelineExecutor(stagePipeline...).Then(handleFailure(plan))
+	executor := common.NewPipelineExecutor(stagePipeline...).Finally(runner.releaseWorkflowConcurrency).Then(handleFailure(plan))
+	if runner.caller == nil {
+		executor = runner.logRunNames(plan).Then(executor)
+	}
+	if runner.config.ProgressInterval > 0 && runner.caller == nil {
+		executor = runner.newProgressExecutor(plan, executor)
+	}
+	if runner.config.ComposeServices != "" && runner.caller == nil {
+		// reusable workflows share the services of their caller
+		runner.composeProject = composeProject(runner.config)
+		executor = newComposeServicesExecutor(runner.config, runner.composeProject, executor)
+	}
+	if runner.config.Preflight && runner.caller == nil {
+		executor = runner.newPreflightExecutor(plan).Then(executor)
+	}
+	if (runner.config.SimulatePermissions || len(runner.config.GraphQLFixtures) > 0) && runner.caller == nil {
+		executor = runner.newAPIProxyExecutor(plan, executor)
+	}
+	if (runner.config.SSHAgent || len(runner.config.SSHAgentKeys) > 0) && runner.caller == nil {
+		executor = runner.newSSHAgentExecutor(executor)
+	}
+	if runner.config.LocalRegistry != "" && runner.caller == nil {
+		executor = runner.newLocalRegistryExecutor(executor)
+	}
+	if (runner.config.ArtifactServerPath != "" || runner.config.CacheServerPath != "" || runner.config.SimulatePermissions || len(runner.config.GraphQLFixtures) > 0) && runner.caller == nil {
+		executor = runner.newHostAddressExecutor(executor)
+	}
+	if runner.caller == nil {
+		backend, _ := container.ParseBackend(runner.config.ContainerBackend)
+		inner := executor
+		executor = func(ctx context.Context) error {
+			return inner(container.WithBackend(ctx, backend))
+		}
+	}
+	if strings.Contains(runner.config.ContainerDaemonSocket, "://") && runner.caller == nil {
+		host := runner.config.ContainerDaemonSocket
+		inner := executor
+		executor = func(ctx context.Context) error {
+			return inner(container.WithDockerHost(ctx, host))
+		}
+	}
+	return executor
+}
+
+// logRunNames logs the title of the run of every workflow of the plan which has a run-name
+func (runner *runnerImpl) logRunNames(plan *model.Plan) common.Executor {
+	return func(ctx context.Context) error {
+		logged := map[string]bool{}
+		for _, stage := range plan.Stages {
+			for _, run := range stage.Runs {
+				if run.Workflow.RunName == "" || logged[run.Workflow.File] {
+					continue
+				}
+				logged[run.Workflow.File] = true
+				// the run-name is evaluated in the context of the workflow, no job needs to be set up for it
+				rc := &RunContext{
+					Name:      run.JobID,
+					Config:    runner.config,
+					Run:       run,
+					EventJSON: runner.eventJSON,
+				}
+				common.Logger(ctx).Infof("\U0001F3F7  %s: %s", run.Workflow.File, rc.runName(ctx))
+			}
+		}
+		return nil
+	}
+}
+
+// validateWorkflowCall validates the inputs and secrets of the reusable workflows in the plan,
+// the inputs are taken from the event as for a workflow_dispatch event
+func (runner *runnerImpl) validateWorkflowCall(plan *model.Plan) error {
+	var event struct {
+		Inputs map[string]interface{} `json:"inputs"`
+	}
+	if err := json.Unmarshal([]byte(runner.eventJSON), &event); err != nil {
+		return err
+	}
+
+	validated := map[*model.Workflow]bool{}
+	for _, stage := range plan.Stages {
+		for _, run := range stage.Runs {
+			if validated[run.Workflow] {
+				continue
+			}
+			validated[run.Workflow] = true
+			if err := validateWorkflowCall(run.Workflow, event.Inputs, runner.config.Secrets); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validateWorkflowDispatch validates the inputs of the workflow_dispatch event against the workflows in the plan
+func (runner *runnerImpl) validateWorkflowDispatch(plan *model.Plan) error {
+	var event struct {
+		Inputs map[string]interface{} `json:"inputs"`
+	}
+	if err := json.Unmarshal([]byte(runner.eventJSON), &event); err != nil {
+		return err
+	}
+
+	validated := map[*model.Workflow]bool{}
+	for _, stage := range plan.Stages {
+		for _, run := range stage.Runs {
+			if validated[run.Workflow] {
+				continue
+			}
+			validated[run.Workflow] = true
+			if err := validateWorkflowDispatch(run.Workflow, event.Inputs); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
 }
 
 func handleFailure(plan *model.Plan) common.Executor {
@@ -165,6 +605,9 @@ func handleFailure(plan *model.Plan) common.Executor {
 				if run.Job().Result == "failure" {
 					return fmt.Errorf("Job '%s' failed", run.String())
 				}
+				if run.Job().Result == "cancelled" {
+					return fmt.Errorf("Job '%s' was cancelled", run.String())
+				}
 			}
 		}
 		return nil
@@ -180,6 +623,29 @@ func (runner *runnerImpl) newRunContext(ctx context.Context, run *model.Run, mat
 		Matrix:      matrix,
 		caller:      runner.caller,
 	}
+	if runner.caller != nil {
+		// a reusable workflow belongs to the workflow of its caller
+		rc.workflowNamespace = runner.caller.runContext.workflowNamespace
+	} else if runner.namespaced {
+		rc.workflowNamespace = run.Workflow.File
+	}
+	if runner.config.EventName == "workflow_dispatch" && runner.caller == nil {
+		rc.EventJSON = workflowDispatchEvent(rc.EventJSON, run.Workflow)
+	}
+	// the outputs of the job are replaced by their values once a leg finished
+	rc.outputTemplates = make(map[string]string, len(run.Job().Outputs))
+	for k, v := range run.Job().Outputs {
+		rc.outputTemplates[k] = v
+	}
+	rc.hostAddress = runner.hostAddress
+	rc.sshAgentSocket = runner.sshAgentSocket
+	rc.progress = runner.progress
+	rc.concurrency = runner.concurrency
+	rc.concurrencyRun = runner.concurrencyRun
+	rc.jobSlots = runner.jobSlots
+	rc.composeProject = runner.composeProject
+	rc.Masks = runner.masker
+	rc.apiProxy = runner.apiProxy
 	rc.ExprEval = rc.NewExpressionEvaluator(ctx)
 	rc.Name = rc.ExprEval.Interpolate(ctx, run.String())
 
@@ -0,0 +1,135 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/nektos/act/pkg/common"
+)
+
+// RunnerManifest lists the tools preinstalled on a GitHub-hosted runner, the tools missing in the environment of a job
+// are installed into the tool cache before its steps run, e.g.
+//
+//	{"name": "ubuntu-22.04", "tools": [
+//		{"name": "jq", "install": "apt-get update && apt-get install -y jq"},
+//		{"name": "go", "version": "1.20.1", "path": ["bin"],
+//		 "install": "curl -sSL https://go.dev/dl/go1.20.1.linux-amd64.tar.gz | tar -xz --strip-components 1 -C \"$TOOL_DIR\""}
+//	]}
+type RunnerManifest struct {
+	Name  string          `json:"name"` // name of the runner image, the tool cache is kept per name
+	Tools []*ManifestTool `json:"tools"`
+}
+
+// ManifestTool is a tool of a RunnerManifest, the commands are run by sh with RUNNER_TOOL_CACHE, TOOL_NAME,
+// TOOL_VERSION and TOOL_DIR (the directory of the tool in the tool cache) in their env
+type ManifestTool struct {
+	Name    string   `json:"name"`
+	Version string   `json:"version"`
+	Check   string   `json:"check"`   // succeeds if the tool is installed, by default the tool has to be in the PATH or, with a version, in the tool cache
+	Install string   `json:"install"` // installs the tool, a tool with a version is marked as complete in the tool cache afterwards
+	Path    []string `json:"path"`    // directories added to the PATH of the steps, relative to TOOL_DIR
+}
+
+var manifestNamePattern = regexp.MustCompile(`[^a-zA-Z0-9_.-]+`)
+
+// ReadRunnerManifest reads a runner manifest from a JSON file
+func ReadRunnerManifest(path string) (*RunnerManifest, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	manifest := new(RunnerManifest)
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(manifest); err != nil {
+		return nil, fmt.Errorf("unable to read the runner manifest %s: %w", path, err)
+	}
+	for i, tool := range manifest.Tools {
+		if tool == nil || tool.Name == "" {
+			return nil, fmt.Errorf("unable to read the runner manifest %s: tool %d has no name", path, i)
+		}
+		if tool.Install == "" {
+			return nil, fmt.Errorf("unable to read the runner manifest %s: tool '%s' has no install command", path, tool.Name)
+		}
+	}
+	return manifest, nil
+}
+
+// volumeName is the name of the volume keeping the tool cache of the job containers
+func (m *RunnerManifest) volumeName() string {
+	name := strings.Trim(manifestNamePattern.ReplaceAllString(m.Name, "-"), "-")
+	if name == "" {
+		return "act-hostedtoolcache"
+	}
+	return "act-hostedtoolcache-" + name
+}
+
+// installManifestTools installs the tools of Config.RunnerManifest missing in the environment of the job
+func (rc *RunContext) installManifestTools() common.Executor {
+	return func(ctx context.Context) error {
+		manifest := rc.Config.RunnerManifest
+		if manifest == nil || common.Dryrun(ctx) {
+			return nil
+		}
+		logger := common.Logger(ctx)
+
+		runnerContext := rc.JobContainer.GetRunnerContext(ctx)
+		toolCache, _ := runnerContext["tool_cache"].(string)
+		arch, _ := runnerContext["arch"].(string)
+		if arch == "" {
+			arch = "x64"
+		}
+
+		for _, tool := range manifest.Tools {
+			version := tool.Version
+			if version == "" {
+				version = "latest"
+			}
+			toolDir := path.Join(toolCache, tool.Name, version, strings.ToLower(arch))
+
+			env := map[string]string{}
+			for k, v := range rc.Env {
+				env[k] = v
+			}
+			env["RUNNER_TOOL_CACHE"] = toolCache
+			env["TOOL_NAME"] = tool.Name
+			env["TOOL_VERSION"] = tool.Version
+			env["TOOL_DIR"] = toolDir
+			rc.ApplyExtraPath(&env)
+
+			check := tool.Check
+			if check == "" && tool.Version == "" {
+				check = fmt.Sprintf(`command -v "%s" >/dev/null 2>&1`, tool.Name)
+			} else if check == "" {
+				check = `test -f "$TOOL_DIR.complete"`
+			}
+
+			if err := rc.JobContainer.Exec([]string{"sh", "-c", check}, env, "", "")(ctx); err != nil {
+				logger.Infof("  \U0001f9f0  Installing %s %s from the runner manifest", tool.Name, version)
+				install := fmt.Sprintf(`mkdir -p "$TOOL_DIR" && (%s)`, tool.Install)
+				if tool.Version != "" {
+					install += ` && touch "$TOOL_DIR.complete"`
+				}
+				if err := rc.JobContainer.Exec([]string{"sh", "-c", install}, env, "", "")(ctx); err != nil {
+					return fmt.Errorf("failed to install %s %s from the runner manifest: %w", tool.Name, version, err)
+				}
+			} else {
+				logger.Debugf("%s %s of the runner manifest is installed", tool.Name, version)
+			}
+
+			for _, dir := range tool.Path {
+				if !path.IsAbs(dir) {
+					dir = path.Join(toolDir, dir)
+				}
+				rc.ExtraPath = append(rc.ExtraPath, dir)
+			}
+		}
+		return nil
+	}
+}
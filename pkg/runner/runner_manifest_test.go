@@ -0,0 +1,66 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestReadRunnerManifest(t *testing.T) {
+	dir := t.TempDir()
+	write := func(content string) string {
+		path := filepath.Join(dir, "ubuntu-22.04.json")
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+
+	manifest, err := ReadRunnerManifest(write(`{"name": "ubuntu-22.04", "tools": [{"name": "jq", "install": "apt-get install -y jq"}]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "jq", manifest.Tools[0].Name)
+	assert.Equal(t, "act-hostedtoolcache-ubuntu-22.04", manifest.volumeName())
+
+	_, err = ReadRunnerManifest(write(`{"tools": [{"name": "jq", "instal": "apt-get install -y jq"}]}`))
+	assert.ErrorContains(t, err, `unknown field "instal"`)
+
+	_, err = ReadRunnerManifest(write(`{"tools": [{"name": "jq"}]}`))
+	assert.ErrorContains(t, err, "tool 'jq' has no install command")
+}
+
+func TestInstallManifestTools(t *testing.T) {
+	cm := &containerMock{}
+	rc := &RunContext{
+		Config: &Config{
+			RunnerManifest: &RunnerManifest{Tools: []*ManifestTool{
+				{Name: "jq", Install: "apt-get install -y jq"},
+				{Name: "go", Version: "1.20.1", Install: "tar -xzf go.tar.gz -C \"$TOOL_DIR\"", Path: []string{"bin"}},
+			}},
+		},
+		Env:          map[string]string{},
+		JobContainer: cm,
+	}
+
+	ok := func(ctx context.Context) error { return nil }
+	notFound := func(ctx context.Context) error { return errors.New("exit with `FAILURE`: 1") }
+	toolEnv := func(name string) interface{} {
+		return mock.MatchedBy(func(env map[string]string) bool {
+			return env["TOOL_NAME"] == name && env["RUNNER_TOOL_CACHE"] == "/opt/hostedtoolcache"
+		})
+	}
+	cm.On("Exec", []string{"sh", "-c", `command -v "jq" >/dev/null 2>&1`}, toolEnv("jq"), "", "").Return(ok)
+	cm.On("Exec", []string{"sh", "-c", `test -f "$TOOL_DIR.complete"`}, toolEnv("go"), "", "").Return(notFound)
+	cm.On("Exec", []string{"sh", "-c", `mkdir -p "$TOOL_DIR" && (tar -xzf go.tar.gz -C "$TOOL_DIR") && touch "$TOOL_DIR.complete"`}, toolEnv("go"), "", "").Return(ok)
+
+	err := rc.installManifestTools()(context.Background())
+
+	assert.NoError(t, err)
+	cm.AssertExpectations(t)
+	assert.Len(t, rc.ExtraPath, 1)
+	assert.True(t, strings.HasPrefix(rc.ExtraPath[0], "/opt/hostedtoolcache/go/1.20.1/"), rc.ExtraPath[0])
+	assert.True(t, strings.HasSuffix(rc.ExtraPath[0], "/bin"), rc.ExtraPath[0])
+}
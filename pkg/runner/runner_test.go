@@ -144,6 +144,7 @@ func TestRunEvent(t *testing.T) {
 		{workdir, "uses-composite", "push", "", platforms, secrets},
 		{workdir, "uses-composite-with-error", "push", "Job 'failing-composite-action' failed", platforms, secrets},
 		{workdir, "uses-nested-composite", "push", "", platforms, secrets},
+		{workdir, "uses-nested-composite-inputs", "push", "", platforms, secrets},
 		{workdir, "remote-action-composite-js-pre-with-defaults", "push", "", platforms, secrets},
 		{workdir, "uses-workflow", "push", "", platforms, map[string]string{"secret": "keep_it_private"}},
 		{workdir, "uses-workflow", "pull_request", "", platforms, map[string]string{"secret": "keep_it_private"}},
@@ -179,6 +180,7 @@ func TestRunEvent(t *testing.T) {
 		{workdir, "issue-597", "push", "", platforms, secrets},
 		{workdir, "issue-598", "push", "", platforms, secrets},
 		{workdir, "if-env-act", "push", "", platforms, secrets},
+		{workdir, "env-evaluation-order", "push", "", platforms, secrets},
 		{workdir, "env-and-path", "push", "", platforms, secrets},
 		{workdir, "environment-files", "push", "", platforms, secrets},
 		{workdir, "GITHUB_STATE", "push", "", platforms, secrets},
@@ -199,6 +201,7 @@ func TestRunEvent(t *testing.T) {
 		{workdir, "workflow_dispatch-scalar", "workflow_dispatch", "", platforms, secrets},
 		{workdir, "workflow_dispatch-scalar-composite-action", "workflow_dispatch", "", platforms, secrets},
 		{workdir, "job-needs-context-contains-result", "push", "", platforms, secrets},
+		{workdir, "job-continue-on-error", "push", "", platforms, secrets},
 		{"../model/testdata", "strategy", "push", "", platforms, secrets}, // TODO: move all testdata into pkg so we can validate it with planner and runner
 		// {"testdata", "issue-228", "push", "", platforms, }, // TODO [igni]: Remove this once everything passes
 		{"../model/testdata", "container-volumes", "push", "", platforms, secrets},
@@ -251,6 +254,7 @@ func TestRunEventHostEnvironment(t *testing.T) {
 			{workdir, "uses-composite", "push", "", platforms, secrets},
 			{workdir, "uses-composite-with-error", "push", "Job 'failing-composite-action' failed", platforms, secrets},
 			{workdir, "uses-nested-composite", "push", "", platforms, secrets},
+			{workdir, "uses-nested-composite-inputs", "push", "", platforms, secrets},
 			{workdir, "act-composite-env-test", "push", "", platforms, secrets},
 
 			// Eval
@@ -262,6 +266,7 @@ func TestRunEventHostEnvironment(t *testing.T) {
 			{workdir, "issue-1195", "push", "", platforms, secrets},
 
 			{workdir, "fail", "push", "exit with `FAILURE`: 1", platforms, secrets},
+			{workdir, "timeout-minutes", "push", "Job 'job-timeout' was cancelled", platforms, secrets},
 			{workdir, "runs-on", "push", "", platforms, secrets},
 			{workdir, "checkout", "push", "", platforms, secrets},
 			{workdir, "remote-action-js", "push", "", platforms, secrets},
@@ -439,6 +444,8 @@ func TestRunActionInputs(t *testing.T) {
 
 	inputs := map[string]string{
 		"SOME_INPUT": "input",
+		"NAME":       "name",
+		"SOME_VALUE": "value",
 	}
 
 	tjfi.runTest(context.Background(), t, &Config{Inputs: inputs})
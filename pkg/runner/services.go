@@ -0,0 +1,186 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/container"
+	"github.com/nektos/act/pkg/model"
+)
+
+// serviceHealthTimeout limits the wait for the health checks of the service containers, docker marks a service
+// unhealthy on its own once the retries of its health check are exhausted
+const serviceHealthTimeout = 10 * time.Minute
+
+// serviceContainerName returns the name of the container of a service of the job
+func (rc *RunContext) serviceContainerName(name string) string {
+	return rc.reusableContainerName(createContainerName(createContainerName("act", rc.String()), name))
+}
+
+// jobNetworkName returns the name of the network the job container and the service containers of the job share
+func (rc *RunContext) jobNetworkName() string {
+	return rc.jobContainerName() + "-network"
+}
+
+// serviceNetworkName returns the network of the service containers, the network of the docker-compose project
+// providing the services or the network of the job
+func (rc *RunContext) serviceNetworkName() string {
+	if rc.Config.ComposeServices != "" {
+		return composeInput(rc.Config, rc.composeProject).ComposeNetworkName()
+	}
+	return rc.jobNetworkName()
+}
+
+// serviceNames returns the names of the services of the job in a stable order
+func (rc *RunContext) serviceNames() []string {
+	names := make([]string, 0, len(rc.Run.Job().Services))
+	for name := range rc.Run.Job().Services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// newServiceContainerInput returns the container of a service of the job with its fields evaluated, nil if its image
+// evaluates to an empty string, which skips the service like on GitHub
+func (rc *RunContext) newServiceContainerInput(ctx context.Context, name string, spec *model.ContainerSpec) (*container.NewContainerInput, error) {
+	image, err := rc.pinImage(ctx, rc.ExprEval.Interpolate(ctx, spec.Image))
+	if err != nil || image == "" {
+		return nil, err
+	}
+
+	var username, password string
+	if spec.Credentials != nil {
+		if len(spec.Credentials) != 2 {
+			return nil, fmt.Errorf("invalid property count for key 'credentials:' of the service %s", name)
+		}
+		username = rc.ExprEval.Interpolate(ctx, spec.Credentials["username"])
+		password = rc.ExprEval.Interpolate(ctx, spec.Credentials["password"])
+		if username == "" || password == "" {
+			return nil, fmt.Errorf("failed to interpolate the credentials of the service %s", name)
+		}
+	}
+
+	env := make([]string, 0, len(spec.Env))
+	for k, v := range spec.Env {
+		env = append(env, fmt.Sprintf("%s=%s", k, rc.ExprEval.Interpolate(ctx, v)))
+	}
+	sort.Strings(env)
+	ports := make([]string, 0, len(spec.Ports))
+	for _, port := range spec.Ports {
+		ports = append(ports, rc.ExprEval.Interpolate(ctx, port))
+	}
+	binds := make([]string, 0, len(spec.Volumes))
+	for _, volume := range spec.Volumes {
+		binds = append(binds, rc.ExprEval.Interpolate(ctx, volume))
+	}
+
+	return &container.NewContainerInput{
+		Image:          image,
+		Username:       username,
+		Password:       password,
+		Name:           rc.serviceContainerName(name),
+		Env:            env,
+		Binds:          binds,
+		NetworkMode:    rc.serviceNetworkName(),
+		NetworkAliases: []string{name},
+		Ports:          ports,
+		Privileged:     rc.Config.Privileged,
+		UsernsMode:     rc.Config.UsernsMode,
+		Platform:       rc.Config.ContainerArchitecture,
+		Options:        rc.ExprEval.Interpolate(ctx, spec.Options),
+		Labels:         rc.containerLabels(),
+		Runtime:        rc.Config.ContainerRuntime,
+		CreateHooks:    rc.Config.ContainerCreateHooks,
+	}, nil
+}
+
+// createServiceContainers creates the references to the service containers of the job, they are started by
+// startServiceContainers
+func (rc *RunContext) createServiceContainers(ctx context.Context) error {
+	rc.serviceContainers = make(map[string]container.Container, len(rc.Run.Job().Services))
+	for _, name := range rc.serviceNames() {
+		input, err := rc.newServiceContainerInput(ctx, name, rc.Run.Job().Services[name])
+		if err != nil {
+			return err
+		}
+		if input == nil {
+			common.Logger(ctx).Infof("Skipping the service %s, its image is empty", name)
+			continue
+		}
+		c := container.NewContainer(input)
+		if c == nil {
+			return fmt.Errorf("failed to create the container of the service %s", name)
+		}
+		rc.serviceContainers[name] = c
+	}
+	return nil
+}
+
+// startServiceContainers creates the network of the job and starts the service containers on it, the job starts
+// once all services are healthy
+func (rc *RunContext) startServiceContainers() common.Executor {
+	return func(ctx context.Context) error {
+		if len(rc.serviceContainers) == 0 {
+			return nil
+		}
+		executors := make([]common.Executor, 0, len(rc.serviceContainers))
+		for _, name := range rc.serviceNames() {
+			c, ok := rc.serviceContainers[name]
+			if !ok {
+				continue
+			}
+			name := name
+			executors = append(executors, common.NewPipelineExecutor(
+				common.NewInfoExecutor("\U0001f680  Start service %s", name),
+				c.Pull(rc.Config.ForcePull),
+				c.Create(nil, nil),
+				c.Start(false),
+				container.NewDockerHealthyExecutor(rc.serviceContainerName(name), serviceHealthTimeout),
+			))
+		}
+		network := common.Executor(func(ctx context.Context) error { return nil })
+		if rc.Config.ComposeServices == "" {
+			network = container.NewDockerNetworkCreateExecutor(rc.jobNetworkName())
+		}
+		return network.Then(common.NewParallelExecutor(len(executors), executors...))(ctx)
+	}
+}
+
+// inspectServiceContainers adds the service containers of the job to the job context with the ports they publish
+func (rc *RunContext) inspectServiceContainers(ctx context.Context) {
+	for name := range rc.serviceContainers {
+		service, err := container.GetServiceContainer(ctx, rc.serviceContainerName(name), rc.networkName())
+		if err != nil {
+			common.Logger(ctx).Debugf("Unable to inspect the service %s: %v", name, err)
+			continue
+		}
+		rc.services[name] = &model.JobServiceContext{
+			ID:      service.ID,
+			Network: service.Network,
+			Ports:   service.Ports,
+		}
+	}
+}
+
+// removeServiceContainers removes the service containers of the job and its network, after the job container which
+// is connected to the network
+func (rc *RunContext) removeServiceContainers() common.Executor {
+	return func(ctx context.Context) error {
+		if len(rc.serviceContainers) == 0 {
+			return nil
+		}
+		executors := make([]common.Executor, 0, len(rc.serviceContainers))
+		for _, c := range rc.serviceContainers {
+			executors = append(executors, c.Remove())
+		}
+		remove := common.NewParallelExecutor(len(executors), executors...)
+		if rc.Config.ComposeServices == "" {
+			remove = remove.Then(container.NewDockerNetworkRemoveExecutor(rc.jobNetworkName()))
+		}
+		return remove(ctx)
+	}
+}
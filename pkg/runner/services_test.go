@@ -0,0 +1,114 @@
+package runner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nektos/act/pkg/model"
+)
+
+func newServicesRunContext(t *testing.T, job string, config *Config) *RunContext {
+	workflow := &model.Workflow{
+		Name: "test",
+		Jobs: map[string]*model.Job{"job": createJob(t, job, "")},
+	}
+	rc := &RunContext{
+		Config: config,
+		Run:    &model.Run{Workflow: workflow, JobID: "job"},
+		Matrix: map[string]interface{}{"postgres": "15"},
+	}
+	rc.ExprEval = rc.NewExpressionEvaluator(context.Background())
+	return rc
+}
+
+func TestRunContextServiceContainerInput(t *testing.T) {
+	rc := newServicesRunContext(t, `runs-on: ubuntu-latest
+services:
+  db:
+    image: postgres:${{ matrix.postgres }}
+    env:
+      POSTGRES_PASSWORD: ${{ matrix.postgres }}-secret
+      POSTGRES_DB: test
+    ports:
+      - 5432
+    volumes:
+      - data:/var/lib/postgresql/data
+    options: --health-cmd pg_isready --health-interval 1s
+  skipped:
+    image: ${{ matrix.none }}
+`, &Config{Workdir: "/repo"})
+
+	assert.Equal(t, []string{"db", "skipped"}, rc.serviceNames())
+
+	input, err := rc.newServiceContainerInput(context.Background(), "db", rc.Run.Job().Services["db"])
+	assert.NoError(t, err)
+	assert.Equal(t, "postgres:15", input.Image)
+	assert.Equal(t, rc.serviceContainerName("db"), input.Name)
+	assert.Equal(t, []string{"POSTGRES_DB=test", "POSTGRES_PASSWORD=15-secret"}, input.Env)
+	assert.Equal(t, []string{"5432"}, input.Ports)
+	assert.Equal(t, []string{"data:/var/lib/postgresql/data"}, input.Binds)
+	assert.Equal(t, "--health-cmd pg_isready --health-interval 1s", input.Options)
+	assert.Equal(t, rc.jobNetworkName(), input.NetworkMode)
+	assert.Equal(t, []string{"db"}, input.NetworkAliases)
+
+	input, err = rc.newServiceContainerInput(context.Background(), "skipped", rc.Run.Job().Services["skipped"])
+	assert.NoError(t, err)
+	assert.Nil(t, input)
+
+	// the job container joins the network of the job once service containers are created for it
+	assert.Equal(t, "host", rc.networkName())
+	assert.NoError(t, rc.createServiceContainers(context.Background()))
+	assert.Len(t, rc.serviceContainers, 1)
+	assert.Equal(t, rc.jobNetworkName(), rc.networkName())
+}
+
+func TestRunContextServiceContainersSkipped(t *testing.T) {
+	rc := newServicesRunContext(t, `runs-on: ubuntu-latest
+services:
+  skipped:
+    image: ${{ matrix.none }}
+`, &Config{Workdir: "/repo"})
+
+	// without a service container the network of the job isn't created, the job container stays on the host
+	assert.NoError(t, rc.createServiceContainers(context.Background()))
+	assert.Empty(t, rc.serviceContainers)
+	assert.Equal(t, "host", rc.networkName())
+}
+
+func TestRunContextServiceContainerInputCredentials(t *testing.T) {
+	rc := newServicesRunContext(t, `runs-on: ubuntu-latest
+services:
+  db:
+    image: ghcr.io/owner/db
+    credentials:
+      username: ${{ secrets.USER }}
+      password: ${{ secrets.PASSWORD }}
+`, &Config{Workdir: "/repo", Secrets: map[string]string{"USER": "owner", "PASSWORD": "token"}})
+
+	input, err := rc.newServiceContainerInput(context.Background(), "db", rc.Run.Job().Services["db"])
+	assert.NoError(t, err)
+	assert.Equal(t, "owner", input.Username)
+	assert.Equal(t, "token", input.Password)
+
+	rc.Config.Secrets = map[string]string{}
+	rc.ExprEval = rc.NewExpressionEvaluator(context.Background())
+	_, err = rc.newServiceContainerInput(context.Background(), "db", rc.Run.Job().Services["db"])
+	assert.ErrorContains(t, err, "failed to interpolate the credentials of the service db")
+}
+
+func TestRunContextServicesNetworkName(t *testing.T) {
+	rc := newServicesRunContext(t, `runs-on: ubuntu-latest
+services:
+  redis:
+    image: redis
+`, &Config{Workdir: "/repo", ComposeServices: "/repo/docker-compose.yml"})
+	rc.composeProject = "act-repo-services-0a1b2c3d"
+
+	// the services of the job join the network of the docker-compose project
+	assert.Equal(t, "act-repo-services-0a1b2c3d_default", rc.networkName())
+	input, err := rc.newServiceContainerInput(context.Background(), "redis", rc.Run.Job().Services["redis"])
+	assert.NoError(t, err)
+	assert.Equal(t, "act-repo-services-0a1b2c3d_default", input.NetworkMode)
+}
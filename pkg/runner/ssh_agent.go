@@ -0,0 +1,129 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+
+	"github.com/nektos/act/pkg/common"
+)
+
+// sshAgentSocketPath is where the socket of the SSH agent is bound in the job containers
+const sshAgentSocketPath = "/var/run/act-ssh-agent.sock"
+
+// dockerDesktopSSHAgentSocket is the socket Docker Desktop forwards the SSH agent of the macOS host to, the socket
+// of SSH_AUTH_SOCK can't be bound from macOS
+const dockerDesktopSSHAgentSocket = "/run/host-services/ssh-auth.sock"
+
+// newSSHAgentExecutor provides the SSH agent forwarded into the job containers while the plan runs, the agent of
+// SSH_AUTH_SOCK or, with Config.SSHAgentKeys, an agent holding only these keys
+func (runner *runnerImpl) newSSHAgentExecutor(executor common.Executor) common.Executor {
+	return func(ctx context.Context) error {
+		if len(runner.config.SSHAgentKeys) == 0 {
+			socket := os.Getenv("SSH_AUTH_SOCK")
+			if socket == "" {
+				return errors.New("unable to forward the SSH agent: SSH_AUTH_SOCK is not set, start an SSH agent or pass the keys with --ssh-agent-key")
+			}
+			runner.sshAgentSocket = socket
+			return executor(ctx)
+		}
+
+		if runtime.GOOS == "darwin" {
+			// Docker Desktop only forwards the agent of the host, the socket of the agent of the run can't be bound
+			return errors.New("--ssh-agent-key is not supported on macOS, add the keys to the SSH agent of the host and use --ssh-agent")
+		}
+		keyring, err := newSSHKeyring(runner.config.SSHAgentKeys)
+		if err != nil {
+			return err
+		}
+		dir, err := os.MkdirTemp("", "act-ssh-agent")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(dir)
+
+		socket := filepath.Join(dir, "agent.sock")
+		listener, err := net.Listen("unix", socket)
+		if err != nil {
+			return fmt.Errorf("unable to start the SSH agent: %w", err)
+		}
+		defer listener.Close()
+		// the directory keeps other users of the host away, the users of the containers need to reach the socket
+		if err := os.Chmod(socket, 0o666); err != nil {
+			return err
+		}
+		go func() {
+			for {
+				conn, err := listener.Accept()
+				if err != nil {
+					return
+				}
+				go func() {
+					defer conn.Close()
+					_ = agent.ServeAgent(readOnlyAgent{keyring}, conn)
+				}()
+			}
+		}()
+		common.Logger(ctx).Debugf("Serving %d SSH key(s) on %s", len(runner.config.SSHAgentKeys), socket)
+
+		runner.sshAgentSocket = socket
+		return executor(ctx)
+	}
+}
+
+// errReadOnlyAgent is returned to the jobs changing the keys of the agent of the run
+var errReadOnlyAgent = errors.New("the SSH agent of the run is read-only")
+
+// readOnlyAgent serves the keys of an agent to the jobs, a job can't add, remove or lock the keys of the other jobs
+type readOnlyAgent struct {
+	agent.Agent
+}
+
+func (readOnlyAgent) Add(agent.AddedKey) error {
+	return errReadOnlyAgent
+}
+
+func (readOnlyAgent) Remove(ssh.PublicKey) error {
+	return errReadOnlyAgent
+}
+
+func (readOnlyAgent) RemoveAll() error {
+	return errReadOnlyAgent
+}
+
+func (readOnlyAgent) Lock([]byte) error {
+	return errReadOnlyAgent
+}
+
+func (readOnlyAgent) Unlock([]byte) error {
+	return errReadOnlyAgent
+}
+
+// newSSHKeyring loads the private keys of the files into an agent keyring
+func newSSHKeyring(paths []string) (agent.Agent, error) {
+	keyring := agent.NewKeyring()
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		key, err := ssh.ParseRawPrivateKey(content)
+		var passphraseErr *ssh.PassphraseMissingError
+		if errors.As(err, &passphraseErr) {
+			return nil, fmt.Errorf("unable to load the SSH key %s: keys with a passphrase are not supported, add it to the agent of the host and use --ssh-agent", path)
+		} else if err != nil {
+			return nil, fmt.Errorf("unable to load the SSH key %s: %w", path, err)
+		}
+		if err := keyring.Add(agent.AddedKey{PrivateKey: key, Comment: path}); err != nil {
+			return nil, fmt.Errorf("unable to load the SSH key %s: %w", path, err)
+		}
+	}
+	return keyring, nil
+}
@@ -0,0 +1,75 @@
+package runner
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+func TestSSHAgentExecutor(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+		t.Skip("the agent is served on a unix socket bound into the containers")
+	}
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	assert.NoError(t, err)
+	keyPath := filepath.Join(t.TempDir(), "deploy_key")
+	assert.NoError(t, os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600))
+
+	runner := &runnerImpl{config: &Config{SSHAgentKeys: []string{keyPath}}}
+	err = runner.newSSHAgentExecutor(func(ctx context.Context) error {
+		conn, err := net.Dial("unix", runner.sshAgentSocket)
+		if !assert.NoError(t, err) {
+			return err
+		}
+		defer conn.Close()
+		client := agent.NewClient(conn)
+		keys, err := client.List()
+		assert.NoError(t, err)
+		if assert.Len(t, keys, 1) {
+			assert.Equal(t, keyPath, keys[0].Comment)
+		}
+
+		// the jobs can't change the keys of the agent
+		_, other, err := ed25519.GenerateKey(rand.Reader)
+		assert.NoError(t, err)
+		assert.Error(t, client.Add(agent.AddedKey{PrivateKey: other}))
+		assert.Error(t, client.RemoveAll())
+		assert.Error(t, client.Lock([]byte("passphrase")))
+		keys, err = client.List()
+		assert.NoError(t, err)
+		assert.Len(t, keys, 1)
+		return nil
+	})(context.Background())
+	assert.NoError(t, err)
+	assert.NoFileExists(t, runner.sshAgentSocket)
+
+	t.Setenv("SSH_AUTH_SOCK", "")
+	runner = &runnerImpl{config: &Config{SSHAgent: true}}
+	err = runner.newSSHAgentExecutor(func(ctx context.Context) error { return nil })(context.Background())
+	assert.ErrorContains(t, err, "SSH_AUTH_SOCK is not set")
+
+	t.Setenv("SSH_AUTH_SOCK", "/tmp/ssh-agent.sock")
+	err = runner.newSSHAgentExecutor(func(ctx context.Context) error { return nil })(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "/tmp/ssh-agent.sock", runner.sshAgentSocket)
+}
+
+func TestSSHAgentKeyring(t *testing.T) {
+	keyPath := filepath.Join(t.TempDir(), "id_rsa")
+	assert.NoError(t, os.WriteFile(keyPath, []byte("not a key"), 0o600))
+
+	_, err := newSSHKeyring([]string{keyPath})
+	assert.ErrorContains(t, err, "unable to load the SSH key "+keyPath)
+}
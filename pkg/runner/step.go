@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/nektos/act/pkg/common"
 	"github.com/nektos/act/pkg/container"
@@ -68,6 +69,9 @@ func runStepExecutor(step step, stage stepStage, executor common.Executor) commo
 		}
 
 		runStep, err := isStepEnabled(ctx, ifExpression, step, stage)
+		if rc.skipUnsupported(ctx, stepModel, stepModel.String(), stepResult, err) {
+			return nil
+		}
 		if err != nil {
 			stepResult.Conclusion = model.StepStatusFailure
 			stepResult.Outcome = model.StepStatusFailure
@@ -85,6 +89,21 @@ func runStepExecutor(step step, stage stepStage, executor common.Executor) commo
 		if strings.Contains(stepString, "::add-mask::") {
 			stepString = "add-mask command"
 		}
+		if rc.Config.SkipUnsupported && rc.skipUnsupported(ctx, stepModel, stepString, stepResult, rc.unsupportedExpression(ctx, step)) {
+			return nil
+		}
+		skip, err := rc.debugStep(ctx, step, stage, stepString, nil)
+		if err != nil {
+			stepResult.Conclusion = model.StepStatusFailure
+			stepResult.Outcome = model.StepStatusFailure
+			return err
+		}
+		if skip {
+			stepResult.Conclusion = model.StepStatusSkipped
+			stepResult.Outcome = model.StepStatusSkipped
+			logger.WithField("stepResult", stepResult.Outcome).Infof("Skipping %s %s in the step debugger", stage, stepString)
+			return nil
+		}
 		logger.Infof("\u2B50 Run %s %s", stage, stepString)
 
 		// Prepare and clean Runner File Commands
@@ -95,6 +114,7 @@ func runStepExecutor(step step, stage stepStage, executor common.Executor) commo
 		(*step.getEnv())["GITHUB_OUTPUT"] = path.Join(actPath, outputFileCommand)
 		(*step.getEnv())["GITHUB_STATE"] = path.Join(actPath, stateFileCommand)
 		(*step.getEnv())["GITHUB_PATH"] = path.Join(actPath, pathFileCommand)
+		(*step.getEnv())["GITHUB_STEP_SUMMARY"] = path.Join(actPath, summaryFileCommand)
 		_ = rc.JobContainer.Copy(actPath, &container.FileEntry{
 			Name: outputFileCommand,
 			Mode: 0666,
@@ -104,12 +124,55 @@ func runStepExecutor(step step, stage stepStage, executor common.Executor) commo
 		}, &container.FileEntry{
 			Name: pathFileCommand,
 			Mode: 0666,
+		}, &container.FileEntry{
+			Name: summaryFileCommand,
+			Mode: 0666,
 		})(ctx)
 
-		err = executor(ctx)
+		stepCtx := ctx
+		var timeout time.Duration
+		if stage == stepStageMain {
+			timeout, err = timeoutMinutes(ctx, rc.ExprEval, stepModel.TimeoutMinutes)
+			if err != nil {
+				stepResult.Conclusion = model.StepStatusFailure
+				stepResult.Outcome = model.StepStatusFailure
+				return err
+			}
+			if timeout > 0 {
+				var cancel context.CancelFunc
+				stepCtx, cancel = context.WithTimeout(ctx, timeout)
+				defer cancel()
+			}
+		}
+
+		watchedCtx, stopHangWatch := rc.watchHang(stepCtx)
+		started := time.Now()
+		err = executor(watchedCtx)
+		duration := time.Since(started)
+		hungFor := stopHangWatch()
+		if stage == stepStageMain {
+			if rc.stepDurations == nil {
+				rc.stepDurations = map[string]time.Duration{}
+			}
+			rc.stepDurations[rc.CurrentStep] = duration
+		}
+		if ctx.Err() != nil {
+			// the job was cancelled or exceeded its timeout-minutes while the step ran
+			stepResult.Outcome = model.StepStatusCancelled
+			stepResult.Conclusion = model.StepStatusCancelled
+			logger.WithField("stepResult", stepResult.Outcome).Errorf("  \u274C  Cancelled - %s %s after %s", stage, stepString, duration.Round(time.Second))
+			return ctx.Err()
+		}
+		if err != nil && stepCtx.Err() == context.DeadlineExceeded {
+			err = fmt.Errorf("the step has exceeded its maximum execution time of %s", timeout)
+		} else if err != nil && hungFor > 0 {
+			err = fmt.Errorf("the step was killed, it has written no output and used no CPU for %s", hungFor.Round(time.Second))
+		}
 
 		if err == nil {
 			logger.WithField("stepResult", stepResult.Outcome).Infof("  \u2705  Success - %s %s", stage, stepString)
+		} else if rc.skipUnsupported(ctx, stepModel, stepString, stepResult, err) {
+			err = nil
 		} else {
 			stepResult.Outcome = model.StepStatusFailure
 
@@ -126,9 +189,16 @@ func runStepExecutor(step step, stage stepStage, executor common.Executor) commo
 			} else {
 				stepResult.Conclusion = model.StepStatusFailure
 			}
+			if stepResult.Conclusion == model.StepStatusFailure {
+				rc.shellOnError(ctx, step, stage, stepString)
+			}
 
 			logger.WithField("stepResult", stepResult.Outcome).Errorf("  \u274C  Failure - %s %s", stage, stepString)
 		}
+		if _, debugErr := rc.debugStep(ctx, step, stage, stepString, stepResult); debugErr != nil {
+			stepResult.Conclusion = model.StepStatusFailure
+			return debugErr
+		}
 		// Process Runner File Commands
 		orgerr := err
 		state := map[string]string{}
@@ -151,6 +221,21 @@ func runStepExecutor(step step, stage stepStage, executor common.Executor) commo
 		if err != nil {
 			return err
 		}
+		if err = rc.collectStepSummary(ctx); err != nil {
+			return err
+		}
+		if err = rc.checkEventPayload(ctx); err != nil {
+			return err
+		}
+		if err = rc.checkStepOutputsLimit(ctx, rc.CurrentStep); err == nil {
+			err = rc.checkExportedEnv(ctx, rc.CurrentStep)
+		}
+		if err != nil {
+			stepResult.Outcome = model.StepStatusFailure
+			stepResult.Conclusion = model.StepStatusFailure
+			logger.WithField("stepResult", stepResult.Outcome).Errorf("  \u274C  Failure - %s %s", stage, stepString)
+			return err
+		}
 		if orgerr != nil {
 			return orgerr
 		}
@@ -171,10 +256,10 @@ func setupEnv(ctx context.Context, step step) error {
 		return err
 	}
 	// merge step env last, since it should not be overwritten
-	mergeIntoMap(step.getEnv(), step.getStepModel().GetEnv())
-
-	exprEval := rc.NewExpressionEvaluator(ctx)
-	for k, v := range *step.getEnv() {
+	// the env of the step can use the env of the workflow, the job and the previous steps, but not its own
+	stepEnv := step.getStepModel().GetEnv()
+	exprEval := rc.NewExpressionEvaluatorWithEnv(ctx, mergeMaps(*step.getEnv()))
+	for k, v := range stepEnv {
 		if !strings.HasPrefix(k, "INPUT_") {
 			(*step.getEnv())[k] = exprEval.Interpolate(ctx, v)
 		}
@@ -182,7 +267,7 @@ func setupEnv(ctx context.Context, step step) error {
 	// after we have an evaluated step context, update the expressions evaluator with a new env context
 	// you can use step level env in the with property of a uses construct
 	exprEval = rc.NewExpressionEvaluatorWithEnv(ctx, *step.getEnv())
-	for k, v := range *step.getEnv() {
+	for k, v := range stepEnv {
 		if strings.HasPrefix(k, "INPUT_") {
 			(*step.getEnv())[k] = exprEval.Interpolate(ctx, v)
 		}
@@ -196,13 +281,14 @@ func setupEnv(ctx context.Context, step step) error {
 func mergeEnv(ctx context.Context, step step) {
 	env := step.getEnv()
 	rc := step.getRunContext()
-	job := rc.Run.Job()
 
-	c := job.Container()
-	if c != nil {
-		mergeIntoMap(env, rc.GetEnv(), c.Env)
-	} else {
-		mergeIntoMap(env, rc.GetEnv())
+	mergeIntoMap(env, rc.GetEnv())
+	if c := rc.jobContainer(ctx); c != nil {
+		// like the env of the job, the env of the container can't use the env context
+		exprEval := rc.NewExpressionEvaluatorWithEnv(ctx, map[string]string{})
+		for k, v := range c.Env {
+			(*env)[k] = exprEval.Interpolate(ctx, v)
+		}
 	}
 
 	rc.withGithubEnv(ctx, step.getGithubContext(ctx), *env)
@@ -3,12 +3,15 @@ package runner
 import (
 	"archive/tar"
 	"context"
+	"fmt"
 	"io"
 	"os"
 	"path"
 	"path/filepath"
+	"strings"
 
 	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/common/git"
 	"github.com/nektos/act/pkg/model"
 )
 
@@ -21,6 +24,7 @@ type stepActionLocal struct {
 	readAction          readAction
 	env                 map[string]string
 	action              *model.Action
+	actionDir           string
 }
 
 func (sal *stepActionLocal) pre() common.Executor {
@@ -37,7 +41,12 @@ func (sal *stepActionLocal) main() common.Executor {
 			return nil
 		}
 
-		actionDir := filepath.Join(sal.getRunContext().Config.Workdir, sal.Step.Uses)
+		actionDir, tried := sal.resolveActionDir(ctx)
+		if len(tried) == 0 {
+			if err := sal.copyActionToContainer(ctx, actionDir); err != nil {
+				return err
+			}
+		}
 
 		localReader := func(ctx context.Context) actionYamlReader {
 			_, cpath := getContainerActionPaths(sal.Step, path.Join(actionDir, ""), sal.RunContext)
@@ -56,6 +65,9 @@ func (sal *stepActionLocal) main() common.Executor {
 
 		actionModel, err := sal.readAction(ctx, sal.Step, actionDir, "", localReader(ctx), os.WriteFile)
 		if err != nil {
+			if len(tried) > 0 {
+				return fmt.Errorf("unable to find the local action '%s', neither %s nor the job container contains it: %w", sal.Step.Uses, strings.Join(tried, " nor "), err)
+			}
 			return err
 		}
 
@@ -65,6 +77,48 @@ func (sal *stepActionLocal) main() common.Executor {
 	})
 }
 
+// localActionFiles are the files marking the directory of an action
+var localActionFiles = []string{"action.yml", "action.yaml", "Dockerfile"}
+
+// resolveActionDir returns the directory of the local action. Like ./ is the root of the checkout on GitHub, the path
+// is resolved from the root of the git repository of the workdir, then from the workdir. If neither contains the
+// action, e.g. because a previous step checks it out in the job container, the workdir is returned with the
+// directories tried.
+func (sal *stepActionLocal) resolveActionDir(ctx context.Context) (string, []string) {
+	workdir := sal.RunContext.Config.Workdir
+	roots := []string{workdir}
+	if root, err := git.FindGitRoot(ctx, workdir); err == nil && filepath.Clean(root) != filepath.Clean(workdir) {
+		roots = []string{root, workdir}
+	}
+
+	tried := make([]string, 0, len(roots))
+	for _, root := range roots {
+		dir := filepath.Join(root, sal.Step.Uses)
+		for _, file := range localActionFiles {
+			if _, err := os.Stat(filepath.Join(dir, file)); err == nil {
+				sal.actionDir = dir
+				return dir, nil
+			}
+		}
+		tried = append(tried, dir)
+	}
+	common.Logger(ctx).Debugf("The local action '%s' is not in %s, reading it from the job container", sal.Step.Uses, strings.Join(tried, " or "))
+	sal.actionDir = filepath.Join(workdir, sal.Step.Uses)
+	return sal.actionDir, tried
+}
+
+// copyActionToContainer copies the local action into the job container without applying .gitignore, the workspace
+// misses it if it is outside the workdir or ignored, unless the workdir is bound
+func (sal *stepActionLocal) copyActionToContainer(ctx context.Context, actionDir string) error {
+	rc := sal.RunContext
+	if _, outside := relativeOutside(rc.Config.Workdir, actionDir); rc.bindsWorkdir() && !outside {
+		return nil
+	}
+	_, containerActionDir := getContainerActionPaths(sal.Step, actionDir, rc)
+	common.Logger(ctx).Debugf("Copying the local action '%s' from %s into the job container", sal.Step.Uses, actionDir)
+	return rc.JobContainer.CopyDir(containerActionDir+"/", actionDir+string(filepath.Separator), false)(ctx)
+}
+
 func (sal *stepActionLocal) post() common.Executor {
 	return runStepExecutor(sal, stepStagePost, runPostStep(sal)).If(hasPostStep(sal)).If(shouldRunPostStep(sal))
 }
@@ -101,7 +155,7 @@ func (sal *stepActionLocal) getActionModel() *model.Action {
 
 func (sal *stepActionLocal) getCompositeRunContext(ctx context.Context) *RunContext {
 	if sal.compositeRunContext == nil {
-		actionDir := filepath.Join(sal.RunContext.Config.Workdir, sal.Step.Uses)
+		actionDir, _ := getActionDir(sal)
 		_, containerActionDir := getContainerActionPaths(sal.getStepModel(), actionDir, sal.RunContext)
 
 		sal.compositeRunContext = newCompositeRunContext(ctx, sal.RunContext, sal, containerActionDir)
@@ -4,10 +4,13 @@ import (
 	"bytes"
 	"context"
 	"io"
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
+	gogit "github.com/go-git/go-git/v5"
+
 	"github.com/nektos/act/pkg/common"
 	"github.com/nektos/act/pkg/model"
 	"github.com/stretchr/testify/assert"
@@ -91,6 +94,10 @@ func TestStepActionLocalTest(t *testing.T) {
 
 	cm.On("GetContainerArchive", ctx, "/var/run/act/workflow/pathcmd.txt").Return(io.NopCloser(&bytes.Buffer{}), nil)
 
+	cm.On("GetContainerArchive", ctx, "/var/run/act/workflow/event.json").Return(io.NopCloser(&bytes.Buffer{}), nil)
+
+	cm.On("GetContainerArchive", ctx, "/var/run/act/workflow/SUMMARY.md").Return(io.NopCloser(&bytes.Buffer{}), nil)
+
 	salm.On("runAction", sal, filepath.Clean("/tmp/path/to/action"), (*remoteAction)(nil)).Return(func(ctx context.Context) error {
 		return nil
 	})
@@ -285,6 +292,10 @@ func TestStepActionLocalPost(t *testing.T) {
 				})
 
 				cm.On("GetContainerArchive", ctx, "/var/run/act/workflow/pathcmd.txt").Return(io.NopCloser(&bytes.Buffer{}), nil)
+
+				cm.On("GetContainerArchive", ctx, "/var/run/act/workflow/event.json").Return(io.NopCloser(&bytes.Buffer{}), nil)
+
+				cm.On("GetContainerArchive", ctx, "/var/run/act/workflow/SUMMARY.md").Return(io.NopCloser(&bytes.Buffer{}), nil)
 			}
 
 			err := sal.post()(ctx)
@@ -295,3 +306,41 @@ func TestStepActionLocalPost(t *testing.T) {
 		})
 	}
 }
+
+func TestStepActionLocalResolveActionDir(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	_, err := gogit.PlainInit(root, false)
+	assert.NoError(t, err)
+	workdir := filepath.Join(root, "sub", "project")
+	assert.NoError(t, os.MkdirAll(filepath.Join(root, ".github", "actions", "setup"), 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, ".github", "actions", "setup", "action.yml"), []byte("runs:\n  using: node16\n  main: index.js\n"), 0o600))
+	assert.NoError(t, os.MkdirAll(filepath.Join(workdir, "actions", "build"), 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(workdir, "actions", "build", "Dockerfile"), []byte("FROM alpine\n"), 0o600))
+
+	cm := &containerMock{}
+	rc := &RunContext{Config: &Config{Workdir: workdir, BindWorkdir: true}, JobContainer: cm}
+
+	sal := &stepActionLocal{RunContext: rc, Step: &model.Step{Uses: "./.github/actions/setup"}}
+	dir, tried := sal.resolveActionDir(ctx)
+	assert.Equal(t, filepath.Join(root, ".github", "actions", "setup"), dir, "resolved from the root of the repository")
+	assert.Empty(t, tried)
+
+	// the action is outside the bound workdir
+	cm.On("CopyDir", filepath.Join(root, ".github", "actions", "setup")+"/", dir+string(filepath.Separator), false).Return(func(ctx context.Context) error {
+		return nil
+	})
+	assert.NoError(t, sal.copyActionToContainer(ctx, dir))
+	cm.AssertExpectations(t)
+
+	sal = &stepActionLocal{RunContext: rc, Step: &model.Step{Uses: "./actions/build"}}
+	dir, tried = sal.resolveActionDir(ctx)
+	assert.Equal(t, filepath.Join(workdir, "actions", "build"), dir, "resolved from the workdir")
+	assert.Empty(t, tried)
+	assert.NoError(t, sal.copyActionToContainer(ctx, dir), "the bound workdir contains the action")
+
+	sal = &stepActionLocal{RunContext: rc, Step: &model.Step{Uses: "./.github/actions/checked-out"}}
+	dir, tried = sal.resolveActionDir(ctx)
+	assert.Equal(t, filepath.Join(workdir, ".github", "actions", "checked-out"), dir)
+	assert.Equal(t, []string{filepath.Join(root, ".github", "actions", "checked-out"), filepath.Join(workdir, ".github", "actions", "checked-out")}, tried)
+}
@@ -46,7 +46,7 @@ func (sar *stepActionRemote) prepareActionExecutor() common.Executor {
 			return fmt.Errorf("Expected format {org}/{repo}[/path]@ref. Actual '%s' Input string was not in a correct format", sar.Step.Uses)
 		}
 
-		sar.remoteAction.URL = sar.RunContext.Config.GitHubInstance
+		sar.remoteAction.URL = githubServerURL(sar.RunContext.Config)
 
 		github := sar.getGithubContext(ctx)
 		if sar.remoteAction.IsCheckout() && isLocalCheckout(github, sar.Step) && !sar.RunContext.Config.NoSkipCheckout {
@@ -54,21 +54,9 @@ func (sar *stepActionRemote) prepareActionExecutor() common.Executor {
 			return nil
 		}
 
-		sar.remoteAction.URL = sar.RunContext.Config.GitHubInstance
-		for _, action := range sar.RunContext.Config.ReplaceGheActionWithGithubCom {
-			if strings.EqualFold(fmt.Sprintf("%s/%s", sar.remoteAction.Org, sar.remoteAction.Repo), action) {
-				sar.remoteAction.URL = "github.com"
-				github.Token = sar.RunContext.Config.ReplaceGheActionTokenWithGithubCom
-			}
-		}
-
-		actionDir := fmt.Sprintf("%s/%s", sar.RunContext.ActionCacheDir(), strings.ReplaceAll(sar.Step.Uses, "/", "-"))
-		gitClone := stepActionRemoteNewCloneExecutor(git.NewGitCloneExecutorInput{
-			URL:   sar.remoteAction.CloneURL(),
-			Ref:   sar.remoteAction.Ref,
-			Dir:   actionDir,
-			Token: github.Token,
-		})
+		cloneInput := sar.RunContext.actionCloneInput(sar.Step, sar.remoteAction)
+		actionDir := cloneInput.Dir
+		gitClone := sar.RunContext.newActionCloneExecutor(sar.Step.Uses, sar.remoteAction.Org, sar.remoteAction.Repo, cloneInput)
 		var ntErr common.Executor
 		if err := gitClone(ctx); err != nil {
 			if errors.Is(err, git.ErrShortRef) {
@@ -80,6 +68,8 @@ func (sar *stepActionRemote) prepareActionExecutor() common.Executor {
 				return err
 			}
 		}
+		// the actions used least recently are evicted first by --cache-max-size
+		common.TouchCacheEntry(actionDir)
 
 		remoteReader := func(ctx context.Context) actionYamlReader {
 			return func(filename string) (io.Reader, io.Closer, error) {
@@ -99,6 +89,26 @@ func (sar *stepActionRemote) prepareActionExecutor() common.Executor {
 	}
 }
 
+// actionCloneInput returns where the action of the step is cloned from and to, actions are always cloned with the
+// configured token, not with the token of the job
+func (rc *RunContext) actionCloneInput(step *model.Step, ra *remoteAction) git.NewGitCloneExecutorInput {
+	token := rc.Config.Token
+	ra.URL = githubServerURL(rc.Config)
+	for _, action := range rc.Config.ReplaceGheActionWithGithubCom {
+		if strings.EqualFold(fmt.Sprintf("%s/%s", ra.Org, ra.Repo), action) {
+			ra.URL = "https://github.com"
+			token = rc.Config.ReplaceGheActionTokenWithGithubCom
+		}
+	}
+
+	return git.NewGitCloneExecutorInput{
+		URL:   ra.CloneURL(),
+		Ref:   ra.Ref,
+		Dir:   fmt.Sprintf("%s/%s", rc.ActionCacheDir(), strings.ReplaceAll(step.Uses, "/", "-")),
+		Token: token,
+	}
+}
+
 func (sar *stepActionRemote) pre() common.Executor {
 	sar.env = map[string]string{}
 
@@ -206,7 +216,7 @@ func (sar *stepActionRemote) getCompositeSteps() *compositeSteps {
 }
 
 type remoteAction struct {
-	URL  string
+	URL  string // the server URL of the GitHub instance, e.g. https://github.com
 	Org  string
 	Repo string
 	Path string
@@ -214,7 +224,7 @@ type remoteAction struct {
 }
 
 func (ra *remoteAction) CloneURL() string {
-	return fmt.Sprintf("https://%s/%s/%s", ra.URL, ra.Org, ra.Repo)
+	return fmt.Sprintf("%s/%s/%s", ra.URL, ra.Org, ra.Repo)
 }
 
 func (ra *remoteAction) IsCheckout() bool {
@@ -240,6 +250,6 @@ func newRemoteAction(action string) *remoteAction {
 		Repo: matches[2],
 		Path: matches[4],
 		Ref:  matches[6],
-		URL:  "github.com",
+		URL:  "https://github.com",
 	}
 }
@@ -170,10 +170,10 @@ func TestStepActionRemote(t *testing.T) {
 				cm.On("UpdateFromEnv", "/var/run/act/workflow/envs.txt", &sar.env).Return(func(ctx context.Context) error { return nil })
 			}
 			if tt.mocks.read {
-				sarm.On("readAction", sar.Step, suffixMatcher("act/remote-action@v1"), "", mock.Anything, mock.Anything).Return(&model.Action{}, nil)
+				sarm.On("readAction", sar.Step, suffixMatcher("act/actions/remote-action@v1"), "", mock.Anything, mock.Anything).Return(&model.Action{}, nil)
 			}
 			if tt.mocks.run {
-				sarm.On("runAction", sar, suffixMatcher("act/remote-action@v1"), newRemoteAction(sar.Step.Uses)).Return(func(ctx context.Context) error { return tt.runError })
+				sarm.On("runAction", sar, suffixMatcher("act/actions/remote-action@v1"), newRemoteAction(sar.Step.Uses)).Return(func(ctx context.Context) error { return tt.runError })
 
 				cm.On("Copy", "/var/run/act", mock.AnythingOfType("[]*container.FileEntry")).Return(func(ctx context.Context) error {
 					return nil
@@ -188,6 +188,10 @@ func TestStepActionRemote(t *testing.T) {
 				})
 
 				cm.On("GetContainerArchive", ctx, "/var/run/act/workflow/pathcmd.txt").Return(io.NopCloser(&bytes.Buffer{}), nil)
+
+				cm.On("GetContainerArchive", ctx, "/var/run/act/workflow/event.json").Return(io.NopCloser(&bytes.Buffer{}), nil)
+
+				cm.On("GetContainerArchive", ctx, "/var/run/act/workflow/SUMMARY.md").Return(io.NopCloser(&bytes.Buffer{}), nil)
 			}
 
 			err := sar.pre()(ctx)
@@ -463,6 +467,42 @@ func TestStepActionRemotePost(t *testing.T) {
 				exec: true,
 			},
 		},
+		{
+			name: "main-success-node20",
+			stepModel: &model.Step{
+				ID:   "step",
+				Uses: "remote/action@v1",
+			},
+			actionModel: &model.Action{
+				Runs: model.ActionRuns{
+					Using:  "node20",
+					Post:   "post.js",
+					PostIf: "always()",
+				},
+			},
+			initialStepResults: map[string]*model.StepResult{
+				"step": {
+					Conclusion: model.StepStatusSuccess,
+					Outcome:    model.StepStatusSuccess,
+					Outputs:    map[string]string{},
+				},
+			},
+			IntraActionState: map[string]map[string]string{
+				"step": {
+					"key": "value",
+				},
+			},
+			expectedEnv: map[string]string{
+				"STATE_key": "value",
+			},
+			mocks: struct {
+				env  bool
+				exec bool
+			}{
+				env:  true,
+				exec: true,
+			},
+		},
 		{
 			name: "main-failed",
 			stepModel: &model.Step{
@@ -599,6 +639,10 @@ func TestStepActionRemotePost(t *testing.T) {
 				})
 
 				cm.On("GetContainerArchive", ctx, "/var/run/act/workflow/pathcmd.txt").Return(io.NopCloser(&bytes.Buffer{}), nil)
+
+				cm.On("GetContainerArchive", ctx, "/var/run/act/workflow/event.json").Return(io.NopCloser(&bytes.Buffer{}), nil)
+
+				cm.On("GetContainerArchive", ctx, "/var/run/act/workflow/SUMMARY.md").Return(io.NopCloser(&bytes.Buffer{}), nil)
 			}
 
 			err := sar.post()(ctx)
@@ -615,3 +659,28 @@ func TestStepActionRemotePost(t *testing.T) {
 		})
 	}
 }
+
+func TestActionCloneURL(t *testing.T) {
+	tables := []struct {
+		config   *Config
+		action   string
+		workflow string
+	}{
+		{&Config{}, "https://github.com/org/repo", "https://github.com/org/repo"},
+		{&Config{GitHubInstance: "github.com"}, "https://github.com/org/repo", "https://github.com/org/repo"},
+		{&Config{GitHubInstance: "ghe.example.com"}, "https://ghe.example.com/org/repo", "https://ghe.example.com/org/repo"},
+		{&Config{GitHubInstance: "http://ghe.local:8080"}, "http://ghe.local:8080/org/repo", "http://ghe.local:8080/org/repo"},
+		{&Config{GitHubInstance: "https://ghe.example.com/github/"}, "https://ghe.example.com/github/org/repo", "https://ghe.example.com/github/org/repo"},
+		{&Config{GitHubInstance: "ghe.example.com", GitHubServerURL: "https://git.example.com"}, "https://git.example.com/org/repo", "https://git.example.com/org/repo"},
+		{&Config{GitHubInstance: "http://ghe.local:8080", ReplaceGheActionWithGithubCom: []string{"org/repo"}}, "https://github.com/org/repo", "http://ghe.local:8080/org/repo"},
+	}
+	for _, table := range tables {
+		rc := &RunContext{Config: table.config}
+		input := rc.actionCloneInput(&model.Step{Uses: "org/repo@v1"}, newRemoteAction("org/repo@v1"))
+		assert.Equal(t, table.action, input.URL, table.config.GitHubInstance)
+
+		workflow := newRemoteReusableWorkflow("org/repo/.github/workflows/ci.yml@v1")
+		workflow.URL = githubServerURL(table.config)
+		assert.Equal(t, table.workflow, workflow.CloneURL(), table.config.GitHubInstance)
+	}
+}
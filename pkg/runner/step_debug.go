@@ -0,0 +1,141 @@
+package runner
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/container"
+	"github.com/nektos/act/pkg/model"
+)
+
+// StepDebugger is the interactive prompt of --step-debug pausing the jobs before and after their steps, the prompts
+// of the jobs running in parallel are asked one after the other
+type StepDebugger struct {
+	mu       sync.Mutex
+	in       *bufio.Reader
+	out      io.Writer
+	detached bool // the steps run without pausing, after the run command or the end of the input
+}
+
+// NewStepDebugger returns a step debugger reading the commands from in and writing the prompts to out
+func NewStepDebugger(in io.Reader, out io.Writer) *StepDebugger {
+	return &StepDebugger{in: bufio.NewReader(in), out: out}
+}
+
+// terminalMu serializes the interactive shells of the jobs running in parallel
+var terminalMu sync.Mutex
+
+const stepDebugHelp = `  c, continue  run the step, or the next one after the step
+  s, skip      skip the step, before the step only
+  h, shell     open a shell with the env of the step in the job container, exit the shell to get back to the prompt
+  e, env       print the env of the step
+  o, context   print the github, job, steps and matrix contexts
+  r, run       run the rest of the steps without pausing
+  a, abort     fail the job
+`
+
+// debugStep pauses the step with the prompt of Config.StepDebugger, before the step if stepResult is nil or after it
+// otherwise. It returns whether the step is skipped, and an error if the job is aborted.
+func (rc *RunContext) debugStep(ctx context.Context, step step, stage stepStage, stepString string, stepResult *model.StepResult) (bool, error) {
+	debugger := rc.Config.StepDebugger
+	if debugger == nil || common.Dryrun(ctx) {
+		return false, nil
+	}
+	debugger.mu.Lock()
+	defer debugger.mu.Unlock()
+	if debugger.detached {
+		return false, nil
+	}
+
+	after := stepResult != nil
+	if after {
+		fmt.Fprintf(debugger.out, "\n⏸  After %s %s of job '%s': %s\n", stage, stepString, rc.String(), stepResult.Outcome)
+	} else {
+		fmt.Fprintf(debugger.out, "\n⏸  Before %s %s of job '%s'\n", stage, stepString, rc.String())
+	}
+	for {
+		fmt.Fprint(debugger.out, "step-debug [c,s,h,e,o,r,a,?]> ")
+		line, err := debugger.in.ReadString('\n')
+		command := strings.TrimSpace(line)
+		if err != nil && command == "" {
+			// without input, like without a terminal, the steps don't pause anymore
+			fmt.Fprintln(debugger.out)
+			debugger.detached = true
+			return false, nil
+		}
+		switch command {
+		case "", "c", "continue":
+			return false, nil
+		case "s", "skip":
+			if after {
+				fmt.Fprintln(debugger.out, "The step has already run")
+				continue
+			}
+			return true, nil
+		case "h", "shell":
+			rc.debugShell(ctx, step, debugger.out)
+		case "e", "env":
+			env := *step.getEnv()
+			keys := make([]string, 0, len(env))
+			for k := range env {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				fmt.Fprintf(debugger.out, "%s=%s\n", k, env[k])
+			}
+		case "o", "context":
+			contexts, err := json.MarshalIndent(map[string]interface{}{
+				"github": step.getGithubContext(ctx),
+				"job":    rc.getJobContext(),
+				"steps":  rc.getStepsContext(),
+				"matrix": rc.Matrix,
+			}, "", "  ")
+			if err != nil {
+				fmt.Fprintf(debugger.out, "Unable to print the contexts: %v\n", err)
+				continue
+			}
+			fmt.Fprintf(debugger.out, "%s\n", contexts)
+		case "r", "run":
+			debugger.detached = true
+			return false, nil
+		case "a", "abort":
+			return false, fmt.Errorf("the job was aborted in the step debugger")
+		default:
+			fmt.Fprint(debugger.out, stepDebugHelp)
+		}
+	}
+}
+
+// debugShell opens a shell with the env of the step in its working directory
+func (rc *RunContext) debugShell(ctx context.Context, step step, out io.Writer) {
+	shell, ok := rc.JobContainer.(container.InteractiveShell)
+	if !ok {
+		fmt.Fprintln(out, "The job container doesn't support shells")
+		return
+	}
+	terminalMu.Lock()
+	defer terminalMu.Unlock()
+	workdir := rc.ExprEval.Interpolate(ctx, step.getStepModel().WorkingDirectory)
+	if err := shell.Shell(ctx, *step.getEnv(), workdir); err != nil {
+		fmt.Fprintf(out, "The shell exited with: %v\n", err)
+	}
+}
+
+// shellOnError opens a shell in the job container after the step failed with Config.ShellOnError, the job continues
+// when the shell exits
+func (rc *RunContext) shellOnError(ctx context.Context, step step, stage stepStage, stepString string) {
+	if !rc.Config.ShellOnError || common.Dryrun(ctx) {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "\n🐚 %s %s of job '%s' failed, opening a shell in the job container, exit it to continue the job\n", stage, stepString, rc.String())
+	rc.debugShell(ctx, step, os.Stderr)
+}
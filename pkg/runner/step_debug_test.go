@@ -0,0 +1,124 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/model"
+)
+
+type shellContainerMock struct {
+	containerMock
+	env     map[string]string
+	workdir string
+}
+
+func (cm *shellContainerMock) Shell(ctx context.Context, env map[string]string, workdir string) error {
+	cm.env = env
+	cm.workdir = workdir
+	return nil
+}
+
+func TestDebugStep(t *testing.T) {
+	newStep := func(commands string) (*RunContext, *stepMock, *shellContainerMock, *bytes.Buffer) {
+		out := &bytes.Buffer{}
+		cm := &shellContainerMock{}
+		workflow := &model.Workflow{Name: "test", Jobs: map[string]*model.Job{"job": createJob(t, "runs-on: ubuntu-latest", "")}}
+		rc := &RunContext{
+			Name:         "job",
+			Config:       &Config{StepDebugger: NewStepDebugger(strings.NewReader(commands), out)},
+			Run:          &model.Run{Workflow: workflow, JobID: "job"},
+			JobContainer: cm,
+		}
+		rc.ExprEval = rc.NewExpressionEvaluator(context.Background())
+		sm := &stepMock{}
+		sm.On("getEnv").Return(&map[string]string{"B": "2", "A": "1"})
+		sm.On("getStepModel").Return(&model.Step{Run: "make", WorkingDirectory: "src"})
+		return rc, sm, cm, out
+	}
+
+	t.Run("skip", func(t *testing.T) {
+		rc, sm, _, out := newStep("s\n")
+		skip, err := rc.debugStep(context.Background(), sm, stepStageMain, "make", nil)
+		assert.NoError(t, err)
+		assert.True(t, skip)
+		assert.Contains(t, out.String(), "Before Main make of job 'test/job'")
+	})
+
+	t.Run("env and shell", func(t *testing.T) {
+		rc, sm, cm, out := newStep("e\nh\nc\n")
+		skip, err := rc.debugStep(context.Background(), sm, stepStageMain, "make", nil)
+		assert.NoError(t, err)
+		assert.False(t, skip)
+		assert.Contains(t, out.String(), "A=1\nB=2\n")
+		assert.Equal(t, map[string]string{"B": "2", "A": "1"}, cm.env)
+		assert.Equal(t, "src", cm.workdir)
+	})
+
+	t.Run("after", func(t *testing.T) {
+		rc, sm, _, out := newStep("s\nx\na\n")
+		_, err := rc.debugStep(context.Background(), sm, stepStageMain, "make", &model.StepResult{Outcome: model.StepStatusFailure})
+		assert.EqualError(t, err, "the job was aborted in the step debugger")
+		assert.Contains(t, out.String(), "After Main make of job 'test/job': failure")
+		assert.Contains(t, out.String(), "The step has already run")
+		assert.Contains(t, out.String(), "s, skip")
+	})
+
+	t.Run("run", func(t *testing.T) {
+		rc, sm, _, out := newStep("r\ns\n")
+		_, err := rc.debugStep(context.Background(), sm, stepStageMain, "make", nil)
+		assert.NoError(t, err)
+		out.Reset()
+		skip, err := rc.debugStep(context.Background(), sm, stepStageMain, "make", nil)
+		assert.NoError(t, err)
+		assert.False(t, skip)
+		assert.Empty(t, out.String())
+	})
+
+	t.Run("end of input", func(t *testing.T) {
+		rc, sm, _, _ := newStep("")
+		skip, err := rc.debugStep(context.Background(), sm, stepStageMain, "make", nil)
+		assert.NoError(t, err)
+		assert.False(t, skip)
+		assert.True(t, rc.Config.StepDebugger.detached)
+	})
+
+	t.Run("dryrun", func(t *testing.T) {
+		rc, sm, _, out := newStep("s\n")
+		skip, err := rc.debugStep(common.WithDryrun(context.Background(), true), sm, stepStageMain, "make", nil)
+		assert.NoError(t, err)
+		assert.False(t, skip)
+		assert.Empty(t, out.String())
+	})
+}
+
+func TestShellOnError(t *testing.T) {
+	cm := &shellContainerMock{}
+	workflow := &model.Workflow{Name: "test", Jobs: map[string]*model.Job{"job": createJob(t, "runs-on: ubuntu-latest", "")}}
+	rc := &RunContext{
+		Name:         "job",
+		Config:       &Config{},
+		Run:          &model.Run{Workflow: workflow, JobID: "job"},
+		JobContainer: cm,
+	}
+	rc.ExprEval = rc.NewExpressionEvaluator(context.Background())
+	sm := &stepMock{}
+	sm.On("getEnv").Return(&map[string]string{"A": "1"})
+	sm.On("getStepModel").Return(&model.Step{Run: "make"})
+
+	rc.shellOnError(context.Background(), sm, stepStageMain, "make")
+	assert.Nil(t, cm.env)
+
+	rc.Config.ShellOnError = true
+	rc.shellOnError(common.WithDryrun(context.Background(), true), sm, stepStageMain, "make")
+	assert.Nil(t, cm.env)
+
+	rc.shellOnError(context.Background(), sm, stepStageMain, "make")
+	assert.Equal(t, map[string]string{"A": "1"}, cm.env)
+	assert.Equal(t, "", cm.workdir)
+}
@@ -60,7 +60,10 @@ func (sd *stepDocker) runUsesContainer() common.Executor {
 	step := sd.Step
 
 	return func(ctx context.Context) error {
-		image := strings.TrimPrefix(step.Uses, "docker://")
+		image, err := rc.pinImage(ctx, strings.TrimPrefix(step.Uses, "docker://"))
+		if err != nil {
+			return err
+		}
 		eval := rc.NewExpressionEvaluator(ctx)
 		cmd, err := shellquote.Split(eval.Interpolate(ctx, step.With["args"]))
 		if err != nil {
@@ -120,7 +123,7 @@ func (sd *stepDocker) newStepContainer(ctx context.Context, image string, cmd []
 		Image:       image,
 		Username:    rc.Config.Secrets["DOCKER_USERNAME"],
 		Password:    rc.Config.Secrets["DOCKER_PASSWORD"],
-		Name:        createContainerName(rc.jobContainerName(), step.ID),
+		Name:        rc.stepContainerName(step.ID),
 		Env:         envList,
 		Mounts:      mounts,
 		NetworkMode: fmt.Sprintf("container:%s", rc.jobContainerName()),
@@ -130,6 +133,9 @@ func (sd *stepDocker) newStepContainer(ctx context.Context, image string, cmd []
 		Privileged:  rc.Config.Privileged,
 		UsernsMode:  rc.Config.UsernsMode,
 		Platform:    rc.Config.ContainerArchitecture,
+		Labels:      rc.containerLabels(),
+		Runtime:     rc.Config.ContainerRuntime,
+		CreateHooks: rc.Config.ContainerCreateHooks,
 	})
 	return stepContainer
 }
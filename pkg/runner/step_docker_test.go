@@ -99,6 +99,10 @@ func TestStepDockerMain(t *testing.T) {
 
 	cm.On("GetContainerArchive", ctx, "/var/run/act/workflow/pathcmd.txt").Return(io.NopCloser(&bytes.Buffer{}), nil)
 
+	cm.On("GetContainerArchive", ctx, "/var/run/act/workflow/event.json").Return(io.NopCloser(&bytes.Buffer{}), nil)
+
+	cm.On("GetContainerArchive", ctx, "/var/run/act/workflow/SUMMARY.md").Return(io.NopCloser(&bytes.Buffer{}), nil)
+
 	err := sd.main()(ctx)
 	assert.Nil(t, err)
 
@@ -2,6 +2,11 @@ package runner
 
 import (
 	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
 
 	"github.com/nektos/act/pkg/model"
 )
@@ -13,6 +18,8 @@ type stepFactory interface {
 type stepFactoryImpl struct{}
 
 func (sf *stepFactoryImpl) newStep(stepModel *model.Step, rc *RunContext) (step, error) {
+	stepModel = githubScriptStep(mockStep(stepModel, rc), rc)
+
 	switch stepModel.Type() {
 	case model.StepTypeInvalid:
 		return nil, fmt.Errorf("Invalid run/uses syntax for job:%s step:%+v", rc.Run, stepModel)
@@ -44,3 +51,45 @@ func (sf *stepFactoryImpl) newStep(stepModel *model.Step, rc *RunContext) (step,
 
 	return nil, fmt.Errorf("Unable to determine how to run job:%s step:%+v", rc.Run, stepModel)
 }
+
+// mockStep replaces the action of a step with the local stub configured for it in Config.MockActions
+func mockStep(stepModel *model.Step, rc *RunContext) *model.Step {
+	if rc.Config == nil || len(rc.Config.MockActions) == 0 {
+		return stepModel
+	}
+	if stepType := stepModel.Type(); stepType != model.StepTypeUsesActionRemote && stepType != model.StepTypeUsesActionLocal {
+		return stepModel
+	}
+
+	patterns := make([]string, 0, len(rc.Config.MockActions))
+	for pattern := range rc.Config.MockActions {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+
+	for _, pattern := range patterns {
+		if matchesUses(pattern, stepModel.Uses) {
+			mocked := *stepModel
+			mocked.Uses = rc.Config.MockActions[pattern]
+			log.Infof("Mocking action '%s' with '%s'", stepModel.Uses, mocked.Uses)
+			return &mocked
+		}
+	}
+	return stepModel
+}
+
+// matchesUses matches a uses reference (owner/repo[/path]@ref) against a pattern,
+// both parts of the pattern may contain wildcards and a pattern without a ref matches every ref
+func matchesUses(pattern string, uses string) bool {
+	patternName, patternRef, hasRef := strings.Cut(pattern, "@")
+	name, ref, _ := strings.Cut(uses, "@")
+
+	if ok, _ := path.Match(patternName, name); !ok {
+		return false
+	}
+	if !hasRef {
+		return true
+	}
+	ok, _ := path.Match(patternRef, ref)
+	return ok
+}
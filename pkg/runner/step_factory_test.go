@@ -79,3 +79,51 @@ func TestStepFactoryInvalidStep(t *testing.T) {
 
 	assert.Error(t, err)
 }
+
+func TestStepFactoryMockAction(t *testing.T) {
+	rc := &RunContext{
+		Config: &Config{
+			MockActions: map[string]string{
+				"aws-actions/configure-aws-credentials@*": "./mocks/aws-creds",
+				"docker/*": "./mocks/docker",
+			},
+		},
+	}
+	sf := &stepFactoryImpl{}
+
+	s, err := sf.newStep(&model.Step{Uses: "aws-actions/configure-aws-credentials@v1"}, rc)
+	assert.Nil(t, err)
+	if assert.IsType(t, &stepActionLocal{}, s) {
+		assert.Equal(t, "./mocks/aws-creds", s.(*stepActionLocal).Step.Uses)
+	}
+
+	s, err = sf.newStep(&model.Step{Uses: "docker/build-push-action@v3"}, rc)
+	assert.Nil(t, err)
+	if assert.IsType(t, &stepActionLocal{}, s) {
+		assert.Equal(t, "./mocks/docker", s.(*stepActionLocal).Step.Uses)
+	}
+
+	s, err = sf.newStep(&model.Step{Uses: "actions/checkout@v3"}, rc)
+	assert.Nil(t, err)
+	assert.IsType(t, &stepActionRemote{}, s)
+}
+
+func TestMatchesUses(t *testing.T) {
+	table := []struct {
+		pattern string
+		uses    string
+		match   bool
+	}{
+		{"actions/checkout@v3", "actions/checkout@v3", true},
+		{"actions/checkout@v3", "actions/checkout@v2", false},
+		{"actions/checkout@*", "actions/checkout@v2", true},
+		{"actions/checkout", "actions/checkout@main", true},
+		{"actions/*", "actions/setup-node@v3", true},
+		{"actions/*", "owner/actions@v3", false},
+		{"owner/repo/path@v1", "owner/repo/path@v1", true},
+	}
+
+	for _, tt := range table {
+		assert.Equal(t, tt.match, matchesUses(tt.pattern, tt.uses), "%s ~ %s", tt.pattern, tt.uses)
+	}
+}
@@ -3,6 +3,9 @@ package runner
 import (
 	"context"
 	"fmt"
+	"os"
+	"path"
+	"path/filepath"
 	"strings"
 
 	"github.com/kballard/go-shellquote"
@@ -31,6 +34,12 @@ func (sr *stepRun) main() common.Executor {
 		sr.setupShellCommandExecutor(),
 		func(ctx context.Context) error {
 			sr.getRunContext().ApplyExtraPath(&sr.env)
+			if err := sr.provisionShell(ctx); err != nil {
+				return err
+			}
+			if err := sr.checkWorkingDirectory(ctx); err != nil {
+				return err
+			}
 			return sr.getRunContext().JobContainer.Exec(sr.cmd, sr.env, "", sr.Step.WorkingDirectory)(ctx)
 		},
 	))
@@ -104,25 +113,11 @@ func (sr *stepRun) setupShellCommand(ctx context.Context) (name, script string,
 
 	name = getScriptName(sr.RunContext, step)
 
-	// Reference: https://github.com/actions/runner/blob/8109c962f09d9acc473d92c595ff43afceddb347/src/Runner.Worker/Handlers/ScriptHandlerHelpers.cs#L47-L64
-	// Reference: https://github.com/actions/runner/blob/8109c962f09d9acc473d92c595ff43afceddb347/src/Runner.Worker/Handlers/ScriptHandlerHelpers.cs#L19-L27
-	runPrepend := ""
-	runAppend := ""
-	switch step.Shell {
-	case "bash", "sh":
-		name += ".sh"
-	case "pwsh", "powershell":
-		name += ".ps1"
-		runPrepend = "$ErrorActionPreference = 'stop'"
-		runAppend = "if ((Test-Path -LiteralPath variable:/LASTEXITCODE)) { exit $LASTEXITCODE }"
-	case "cmd":
-		name += ".cmd"
-		runPrepend = "@echo off"
-	case "python":
-		name += ".py"
-	}
-
-	script = fmt.Sprintf("%s\n%s\n%s", runPrepend, script, runAppend)
+	// a custom shell runs the script like the shell keyword of its interpreter, e.g. "pwsh -noprofile {0}" stops on errors
+	shell := model.LookupShell(step.Shell)
+	name += shell.Extension
+
+	script = fmt.Sprintf("%s\n%s\n%s", shell.Prepend, script, shell.Append)
 
 	if !strings.Contains(script, "::add-mask::") && !sr.RunContext.Config.InsecureSecrets {
 		logger.Debugf("Wrote command \n%s\n to '%s'", script, name)
@@ -137,6 +132,57 @@ func (sr *stepRun) setupShellCommand(ctx context.Context) (name, script string,
 	return name, script, err
 }
 
+// provisionShell puts the interpreter of the shell on the PATH of the step if the job container misses it, like python
+// or node in the images without them. The latest version in the tool cache is used, e.g. one installed by
+// actions/setup-python in a previous job.
+func (sr *stepRun) provisionShell(ctx context.Context) error {
+	rc := sr.getRunContext()
+	shell := model.LookupShell(sr.Step.Shell)
+	if shell.ToolCache == "" || len(sr.cmd) == 0 || common.Dryrun(ctx) {
+		return nil
+	}
+	if _, ok := rc.JobContainer.(*container.HostEnvironment); ok {
+		return nil
+	}
+	interpreter := sr.cmd[0]
+
+	dir, ok := rc.shellPaths[interpreter]
+	if !ok {
+		envFile := fmt.Sprintf("%s/shell-%s.env", rc.JobContainer.GetActPath(), path.Base(interpreter))
+		probe := fmt.Sprintf(`rm -f %[1]s
+if ! command -v %[2]s >/dev/null 2>&1; then
+  dir=$(ls -d /opt/hostedtoolcache/%[3]s/*/*/bin 2>/dev/null | sort -V | tail -n 1)
+  echo "ACT_SHELL_PATH=${dir:-missing}" > %[1]s
+fi`, shellquote.Join(envFile), shellquote.Join(interpreter), shell.ToolCache)
+		if err := rc.JobContainer.Exec([]string{"sh", "-c", probe}, sr.env, "", "")(ctx); err != nil {
+			return err
+		}
+		found := map[string]string{}
+		if err := rc.JobContainer.UpdateFromEnv(envFile, &found)(ctx); err != nil {
+			return err
+		}
+		dir = found["ACT_SHELL_PATH"]
+		if dir == "missing" {
+			return fmt.Errorf("the shell '%s' needs %s, which is neither installed in the image %s nor in the tool cache /opt/hostedtoolcache, install it with a setup action like actions/setup-%s", sr.Step.Shell, interpreter, rc.platformImage(ctx), strings.ToLower(shell.ToolCache))
+		}
+		if rc.shellPaths == nil {
+			rc.shellPaths = map[string]string{}
+		}
+		rc.shellPaths[interpreter] = dir
+		if dir != "" {
+			common.Logger(ctx).Infof("  \U0001F9F0  %s is not installed in the image, using %s from the tool cache", interpreter, dir)
+		}
+	}
+	if dir != "" {
+		pathName := rc.JobContainer.GetPathVariableName()
+		if sr.env[pathName] == "" {
+			sr.env[pathName] = rc.JobContainer.DefaultPathVariable()
+		}
+		sr.env[pathName] = rc.JobContainer.JoinPathVariable(dir, sr.env[pathName])
+	}
+	return nil
+}
+
 func (sr *stepRun) setupShell(ctx context.Context) {
 	rc := sr.RunContext
 	step := sr.Step
@@ -156,26 +202,73 @@ func (sr *stepRun) setupShell(ctx context.Context) {
 	// if `bash` is available, and provides `bash` if it is
 	// for now I'm going to leave below logic, will address it in different PR
 	// https://github.com/actions/runner/blob/9a829995e02d2db64efb939dc2f283002595d4d9/src/Runner.Worker/Handlers/ScriptHandler.cs#L87-L91
-	if rc.Run.Job().Container() != nil {
-		if rc.Run.Job().Container().Image != "" && step.Shell == "" {
-			step.Shell = "sh"
-		}
+	if rc.jobContainer(ctx) != nil && step.Shell == "" {
+		step.Shell = "sh"
 	}
+
+	step.Shell = emulatedShell(rc.emulatedOS(ctx), step.Shell, !rc.noPwsh)
 }
 
 func (sr *stepRun) setupWorkingDirectory(ctx context.Context) {
 	rc := sr.RunContext
 	step := sr.Step
 
-	if step.WorkingDirectory == "" {
-		step.WorkingDirectory = rc.Run.Job().Defaults.Run.WorkingDirectory
+	if step.WorkingDirectory != "" {
+		// the working-directory of the step can use the contexts of the step, like env and steps
+		step.WorkingDirectory = rc.NewStepExpressionEvaluator(ctx, sr).Interpolate(ctx, step.WorkingDirectory)
+	} else {
+		// jobs can receive context values, so we interpolate
+		step.WorkingDirectory = rc.NewExpressionEvaluator(ctx).Interpolate(ctx, rc.Run.Job().Defaults.Run.WorkingDirectory)
 	}
 
-	// jobs can receive context values, so we interpolate
-	step.WorkingDirectory = rc.NewExpressionEvaluator(ctx).Interpolate(ctx, step.WorkingDirectory)
-
 	// but top level keys in workflow file like `defaults` or `env` can't
 	if step.WorkingDirectory == "" {
 		step.WorkingDirectory = rc.Run.Workflow.Defaults.Run.WorkingDirectory
 	}
+
+	step.WorkingDirectory = emulatedPath(rc.emulatedOS(ctx), step.WorkingDirectory)
+}
+
+// workingDirectory returns the directory the step runs in, a relative working-directory is in the workspace
+func (sr *stepRun) workingDirectory() string {
+	rc := sr.getRunContext()
+	workspace := rc.JobContainer.ToContainerPath(rc.Config.Workdir)
+	wd := sr.Step.WorkingDirectory
+	if _, ok := rc.JobContainer.(*container.HostEnvironment); ok {
+		if filepath.IsAbs(wd) {
+			return wd
+		}
+		return filepath.Join(workspace, wd)
+	}
+	if strings.HasPrefix(wd, "/") {
+		return wd
+	}
+	return path.Join(workspace, wd)
+}
+
+// checkWorkingDirectory fails the step if its working-directory doesn't exist with the error of GitHub, which doesn't
+// create it. The dry-run prints the directory instead.
+func (sr *stepRun) checkWorkingDirectory(ctx context.Context) error {
+	if sr.Step.WorkingDirectory == "" {
+		return nil
+	}
+	rc := sr.getRunContext()
+	logger := common.Logger(ctx)
+	wd := sr.workingDirectory()
+	if common.Dryrun(ctx) {
+		logger.Infof("  \U0001F4C2  working-directory: %s", wd)
+		return nil
+	}
+	logger.Debugf("working-directory: %s", wd)
+
+	var err error
+	if _, ok := rc.JobContainer.(*container.HostEnvironment); ok {
+		_, err = os.Stat(wd)
+	} else {
+		err = rc.JobContainer.Exec([]string{"test", "-d", wd}, sr.env, "", "")(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("an error occurred trying to start process '%s' with working directory '%s'. No such file or directory", sr.cmd[0], wd)
+	}
+	return nil
 }
@@ -3,9 +3,14 @@ package runner
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"io"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/nektos/act/pkg/common"
 	"github.com/nektos/act/pkg/container"
 	"github.com/nektos/act/pkg/model"
 	"github.com/stretchr/testify/assert"
@@ -55,6 +60,12 @@ func TestStepRun(t *testing.T) {
 		return nil
 	})
 
+	cm.On("Exec", mock.MatchedBy(func(cmd []string) bool {
+		return len(cmd) == 3 && cmd[0] == "test" && strings.HasSuffix(cmd[2], "/workdir")
+	}), mock.AnythingOfType("map[string]string"), "", "").Return(func(ctx context.Context) error {
+		return nil
+	})
+
 	cm.On("UpdateFromImageEnv", mock.AnythingOfType("*map[string]string")).Return(func(ctx context.Context) error {
 		return nil
 	})
@@ -79,6 +90,10 @@ func TestStepRun(t *testing.T) {
 
 	cm.On("GetContainerArchive", ctx, "/var/run/act/workflow/pathcmd.txt").Return(io.NopCloser(&bytes.Buffer{}), nil)
 
+	cm.On("GetContainerArchive", ctx, "/var/run/act/workflow/event.json").Return(io.NopCloser(&bytes.Buffer{}), nil)
+
+	cm.On("GetContainerArchive", ctx, "/var/run/act/workflow/SUMMARY.md").Return(io.NopCloser(&bytes.Buffer{}), nil)
+
 	err := sr.main()(ctx)
 	assert.Nil(t, err)
 
@@ -95,3 +110,64 @@ func TestStepRunPrePost(t *testing.T) {
 	err = sr.post()(ctx)
 	assert.Nil(t, err)
 }
+
+func TestStepRunProvisionShell(t *testing.T) {
+	cm := &containerMock{}
+	sr := &stepRun{
+		RunContext: &RunContext{
+			Config:       &Config{},
+			JobContainer: cm,
+		},
+		Step: &model.Step{ID: "1", Shell: "python"},
+		cmd:  []string{"python", "/var/run/act/workflow/1.py"},
+		env:  map[string]string{"PATH": "/usr/bin"},
+	}
+
+	probe := mock.MatchedBy(func(cmd []string) bool {
+		return len(cmd) == 3 && cmd[0] == "sh" && strings.Contains(cmd[2], "/opt/hostedtoolcache/Python/*/*/bin")
+	})
+	cm.On("Exec", probe, mock.AnythingOfType("map[string]string"), "", "").Return(func(ctx context.Context) error {
+		return nil
+	}).Once()
+	cm.On("UpdateFromEnv", "/var/run/act/shell-python.env", mock.AnythingOfType("*map[string]string")).Run(func(args mock.Arguments) {
+		(*args.Get(1).(*map[string]string))["ACT_SHELL_PATH"] = "/opt/hostedtoolcache/Python/3.11.4/x64/bin"
+	}).Return(func(ctx context.Context) error {
+		return nil
+	}).Once()
+
+	assert.NoError(t, sr.provisionShell(context.Background()))
+	assert.Equal(t, "/opt/hostedtoolcache/Python/3.11.4/x64/bin:/usr/bin", sr.env["PATH"])
+
+	// the interpreter is probed once per job
+	sr.env = map[string]string{"PATH": "/usr/bin"}
+	assert.NoError(t, sr.provisionShell(context.Background()))
+	assert.Equal(t, "/opt/hostedtoolcache/Python/3.11.4/x64/bin:/usr/bin", sr.env["PATH"])
+	cm.AssertExpectations(t)
+
+	// bash isn't provisioned
+	sr.Step.Shell = "bash"
+	sr.cmd = []string{"bash", "/var/run/act/workflow/1.sh"}
+	assert.NoError(t, sr.provisionShell(context.Background()))
+}
+
+func TestStepRunCheckWorkingDirectory(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "app"), 0o755))
+	sr := &stepRun{
+		RunContext: &RunContext{
+			Config:       &Config{Workdir: dir},
+			JobContainer: &container.HostEnvironment{Path: dir, Workdir: dir},
+		},
+		Step: &model.Step{ID: "1", WorkingDirectory: "app"},
+		cmd:  []string{"bash", "1.sh"},
+	}
+	assert.Equal(t, filepath.Join(dir, "app"), sr.workingDirectory())
+	assert.NoError(t, sr.checkWorkingDirectory(context.Background()))
+
+	sr.Step.WorkingDirectory = "missing"
+	err := sr.checkWorkingDirectory(context.Background())
+	assert.EqualError(t, err, fmt.Sprintf("an error occurred trying to start process 'bash' with working directory '%s'. No such file or directory", filepath.Join(dir, "missing")))
+
+	// the dry-run doesn't check the directory
+	assert.NoError(t, sr.checkWorkingDirectory(common.WithDryrun(context.Background(), true)))
+}
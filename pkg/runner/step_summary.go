@@ -0,0 +1,64 @@
+package runner
+
+import (
+	"archive/tar"
+	"context"
+	"io"
+	"path"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/container"
+)
+
+// summaryFileCommand is the file of GITHUB_STEP_SUMMARY in the act path, it is emptied before every step
+var summaryFileCommand = path.Join("workflow", "SUMMARY.md")
+
+// stepSummaryLimit is the size of the summary of a step GitHub accepts, a larger summary is dropped
+const stepSummaryLimit = 1024 * 1024
+
+// collectStepSummary adds the markdown the step wrote to GITHUB_STEP_SUMMARY to the summary of the job. The steps of
+// a composite action add it to the summary of the job running the composite action. The file is emptied after, so
+// the composite action doesn't add the summary of its last step again.
+func (rc *RunContext) collectStepSummary(ctx context.Context) error {
+	if common.Dryrun(ctx) {
+		return nil
+	}
+	summaryPath := path.Join(rc.JobContainer.GetActPath(), summaryFileCommand)
+	archive, err := rc.JobContainer.GetContainerArchive(ctx, summaryPath)
+	if err != nil {
+		// the step may have removed the file
+		common.Logger(ctx).Debugf("unable to read the step summary: %v", err)
+		return nil
+	}
+	defer archive.Close()
+
+	reader := tar.NewReader(archive)
+	if _, err := reader.Next(); err == io.EOF {
+		return nil
+	} else if err != nil {
+		common.Logger(ctx).Warnf("unable to read the step summary: %v", err)
+		return nil
+	}
+	content, err := io.ReadAll(io.LimitReader(reader, stepSummaryLimit+1))
+	if err != nil {
+		common.Logger(ctx).Warnf("unable to read the step summary: %v", err)
+		return nil
+	}
+	if len(content) == 0 {
+		return nil
+	}
+
+	if len(content) > stepSummaryLimit {
+		common.Logger(ctx).Warnf("$GITHUB_STEP_SUMMARY upload aborted, supports content up to a size of %dk, got more", stepSummaryLimit/1024)
+	} else {
+		job := rc
+		for job.Parent != nil {
+			job = job.Parent
+		}
+		job.stepSummaries = append(job.stepSummaries, string(content))
+	}
+	return rc.JobContainer.Copy(rc.JobContainer.GetActPath(), &container.FileEntry{
+		Name: summaryFileCommand,
+		Mode: 0666,
+	})(ctx)
+}
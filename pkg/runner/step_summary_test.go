@@ -0,0 +1,52 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nektos/act/pkg/container"
+)
+
+func TestCollectStepSummary(t *testing.T) {
+	ctx := context.Background()
+	cm := &containerMock{}
+	job := &RunContext{JobContainer: cm}
+	composite := &RunContext{JobContainer: cm, Parent: job}
+
+	emptied := 0
+	cm.On("Copy", "/var/run/act", []*container.FileEntry{{
+		Name: "workflow/SUMMARY.md",
+		Mode: 0666,
+	}}).Return(func(ctx context.Context) error {
+		emptied++
+		return nil
+	})
+
+	cm.On("GetContainerArchive", ctx, "/var/run/act/workflow/SUMMARY.md").Return(eventPayloadArchive(t, "# Build\n"), nil).Once()
+	assert.NoError(t, job.collectStepSummary(ctx))
+	cm.On("GetContainerArchive", ctx, "/var/run/act/workflow/SUMMARY.md").Return(eventPayloadArchive(t, "- tests passed\n"), nil).Once()
+	assert.NoError(t, composite.collectStepSummary(ctx))
+	cm.On("GetContainerArchive", ctx, "/var/run/act/workflow/SUMMARY.md").Return(eventPayloadArchive(t, ""), nil).Once()
+	assert.NoError(t, job.collectStepSummary(ctx))
+	cm.On("GetContainerArchive", ctx, "/var/run/act/workflow/SUMMARY.md").Return(io.NopCloser(&bytes.Buffer{}), errors.New("no such file")).Once()
+	assert.NoError(t, job.collectStepSummary(ctx))
+
+	// a broken archive doesn't fail the step
+	cm.On("GetContainerArchive", ctx, "/var/run/act/workflow/SUMMARY.md").Return(io.NopCloser(strings.NewReader("not a tar archive")), nil).Once()
+	assert.NoError(t, job.collectStepSummary(ctx))
+
+	// a summary larger than GitHub accepts is dropped
+	cm.On("GetContainerArchive", ctx, "/var/run/act/workflow/SUMMARY.md").Return(eventPayloadArchive(t, strings.Repeat("x", stepSummaryLimit+1)), nil).Once()
+	assert.NoError(t, job.collectStepSummary(ctx))
+
+	assert.Equal(t, []string{"# Build\n", "- tests passed\n"}, job.stepSummaries)
+	assert.Empty(t, composite.stepSummaries)
+	assert.Equal(t, 3, emptied)
+	cm.AssertExpectations(t)
+}
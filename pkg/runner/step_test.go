@@ -172,18 +172,19 @@ func TestSetupEnv(t *testing.T) {
 		"GITHUB_ACTION_PATH":       "",
 		"GITHUB_ACTION_REF":        "",
 		"GITHUB_ACTION_REPOSITORY": "",
-		"GITHUB_API_URL":           "https:///api/v3",
+		"GITHUB_API_URL":           "https://api.github.com",
 		"GITHUB_BASE_REF":          "",
 		"GITHUB_ENV":               "/var/run/act/workflow/envs.txt",
 		"GITHUB_EVENT_NAME":        "",
 		"GITHUB_EVENT_PATH":        "/var/run/act/workflow/event.json",
-		"GITHUB_GRAPHQL_URL":       "https:///api/graphql",
+		"GITHUB_GRAPHQL_URL":       "https://api.github.com/graphql",
 		"GITHUB_HEAD_REF":          "",
 		"GITHUB_JOB":               "",
 		"GITHUB_RETENTION_DAYS":    "0",
 		"GITHUB_RUN_ID":            "runId",
 		"GITHUB_RUN_NUMBER":        "1",
-		"GITHUB_SERVER_URL":        "https://",
+		"GITHUB_RUN_ATTEMPT":       "1",
+		"GITHUB_SERVER_URL":        "https://github.com",
 		"GITHUB_TOKEN":             "",
 		"GITHUB_WORKFLOW":          "",
 		"INPUT_STEP_WITH":          "with-value",
@@ -346,3 +347,53 @@ func TestIsContinueOnError(t *testing.T) {
 	assertObject.False(continueOnError)
 	assertObject.NotNil(err)
 }
+
+func TestSetupEnvEvaluationOrder(t *testing.T) {
+	cm := &containerMock{}
+	sm := &stepMock{}
+
+	rc := &RunContext{
+		Config: &Config{},
+		Run: &model.Run{
+			JobID: "1",
+			Workflow: &model.Workflow{
+				Jobs: map[string]*model.Job{"1": {}},
+			},
+		},
+		Env: map[string]string{
+			"Greeting": "Hello",
+		},
+		JobContainer: cm,
+	}
+	var step *model.Step
+	assert.NoError(t, yaml.Unmarshal([]byte(`
+env:
+  GREETING_OF_JOB: ${{ env.Greeting }}
+  First_Name: Mona
+  OWN: ${{ env.First_Name }}
+  EXPORTED_COPY: ${{ env.EXPORTED }}
+with:
+  name: ${{ env.First_Name }}
+`), &step))
+	env := map[string]string{}
+
+	sm.On("getRunContext").Return(rc)
+	sm.On("getGithubContext").Return(rc)
+	sm.On("getStepModel").Return(step)
+	sm.On("getEnv").Return(&env)
+
+	cm.On("UpdateFromImageEnv", &env).Return(func(ctx context.Context) error { return nil })
+	cm.On("UpdateFromEnv", "/var/run/act/workflow/envs.txt", &env).Return(func(ctx context.Context) error {
+		env["EXPORTED"] = "${{ not evaluated }}"
+		return nil
+	})
+
+	assert.NoError(t, setupEnv(context.Background(), sm))
+
+	assert.Equal(t, "Hello", env["GREETING_OF_JOB"])
+	assert.Equal(t, "", env["OWN"])
+	assert.Equal(t, "${{ not evaluated }}", env["EXPORTED"])
+	assert.Equal(t, "${{ not evaluated }}", env["EXPORTED_COPY"])
+	// the inputs can use the env of the step
+	assert.Equal(t, "Mona", env["INPUT_NAME"])
+}
@@ -0,0 +1,4 @@
+const fs = require('fs');
+
+console.log(`Hello ${process.env['INPUT_WHO-TO-GREET']}!`);
+fs.appendFileSync(process.env['GITHUB_STATE'], 'greeted=true\n');
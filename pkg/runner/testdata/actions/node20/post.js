@@ -0,0 +1,5 @@
+const greeted = process.env['STATE_greeted'];
+
+if (greeted !== 'true') {
+  throw new Error(`Expected the state 'greeted' of the main entrypoint but got '${greeted}'`);
+}
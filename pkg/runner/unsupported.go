@@ -0,0 +1,94 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/container"
+	"github.com/nektos/act/pkg/exprparser"
+	"github.com/nektos/act/pkg/model"
+)
+
+// UnsupportedStep is a step skipped by --skip-unsupported, as it uses a feature act can't emulate
+type UnsupportedStep struct {
+	ID     string
+	Name   string
+	Reason string
+}
+
+// unsupportedReason returns why act can't emulate a step failing with err, empty if the error isn't caused by a
+// feature act doesn't support
+func unsupportedReason(err error) string {
+	switch {
+	case errors.Is(err, exprparser.ErrUnsupported):
+		return fmt.Sprintf("an expression uses a context or a function act doesn't support: %v", err)
+	case errors.Is(err, container.ErrCommandNotFound):
+		return fmt.Sprintf("a command isn't installed in the environment of the job, like the tools preinstalled on the GitHub-hosted runners: %v", err)
+	}
+	return ""
+}
+
+// unsupportedExpression returns the error of the first expression of the step using a context or a function act
+// doesn't support, nil if there is none. The failed interpolations of the step only log their errors.
+func (rc *RunContext) unsupportedExpression(ctx context.Context, step step) error {
+	stepModel := step.getStepModel()
+	values := []string{stepModel.Name, stepModel.Run, stepModel.Shell, stepModel.WorkingDirectory}
+	for _, vars := range []map[string]string{stepModel.With, stepModel.GetEnv()} {
+		keys := make([]string, 0, len(vars))
+		for k := range vars {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			values = append(values, vars[k])
+		}
+	}
+
+	ee := rc.NewExpressionEvaluatorWithEnv(ctx, *step.getEnv())
+	for _, value := range values {
+		if !strings.Contains(value, "${{") || !strings.Contains(value, "}}") {
+			continue
+		}
+		expr, _ := rewriteSubExpression(ctx, value, true)
+		if _, err := ee.evaluate(ctx, expr, exprparser.DefaultStatusCheckNone); errors.Is(err, exprparser.ErrUnsupported) {
+			return err
+		}
+	}
+	return nil
+}
+
+// skipUnsupported skips the step with Config.SkipUnsupported if err is caused by a feature act doesn't support and
+// reports whether it did. A step which failed while running is skipped as well, its outputs are still read.
+func (rc *RunContext) skipUnsupported(ctx context.Context, stepModel *model.Step, stepString string, stepResult *model.StepResult, err error) bool {
+	if !rc.Config.SkipUnsupported || err == nil {
+		return false
+	}
+	reason := unsupportedReason(err)
+	if reason == "" {
+		return false
+	}
+	stepResult.Outcome = model.StepStatusSkipped
+	stepResult.Conclusion = model.StepStatusSkipped
+	common.Logger(ctx).WithField("stepResult", stepResult.Outcome).Warnf("  ⚠  Skipped unsupported %s: %s", stepString, reason)
+
+	job := rc.jobRunContext()
+	job.unsupportedSteps = append(job.unsupportedSteps, &UnsupportedStep{ID: stepModel.ID, Name: stepString, Reason: reason})
+	return true
+}
+
+// logUnsupportedSteps reports the steps of the job skipped by Config.SkipUnsupported when the job is done
+func (rc *RunContext) logUnsupportedSteps(ctx context.Context) {
+	if len(rc.unsupportedSteps) == 0 {
+		return
+	}
+	var report strings.Builder
+	fmt.Fprintf(&report, "⚠  %d step(s) were skipped as they use features act can't emulate, the rest of the job ran:", len(rc.unsupportedSteps))
+	for _, step := range rc.unsupportedSteps {
+		fmt.Fprintf(&report, "\n  - %s: %s", step.Name, step.Reason)
+	}
+	common.Logger(ctx).Warn(report.String())
+}
@@ -0,0 +1,56 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nektos/act/pkg/container"
+	"github.com/nektos/act/pkg/exprparser"
+	"github.com/nektos/act/pkg/model"
+)
+
+func TestUnsupportedReason(t *testing.T) {
+	assert.Contains(t, unsupportedReason(fmt.Errorf("exitcode '127': %w", container.ErrCommandNotFound)), "a command isn't installed")
+	assert.Contains(t, unsupportedReason(fmt.Errorf("Unavailable context: foo (%w)", exprparser.ErrUnsupported)), "Unavailable context: foo")
+	assert.Empty(t, unsupportedReason(errors.New("exitcode '1': failure")))
+}
+
+func TestSkipUnsupported(t *testing.T) {
+	workflow := &model.Workflow{Name: "test", Jobs: map[string]*model.Job{"job": createJob(t, "runs-on: ubuntu-latest", "")}}
+	job := &RunContext{
+		Config: &Config{SkipUnsupported: true},
+		Run:    &model.Run{Workflow: workflow, JobID: "job"},
+	}
+	job.ExprEval = job.NewExpressionEvaluator(context.Background())
+	// the steps of a composite action report to the job
+	rc := &RunContext{Config: job.Config, Run: job.Run, Parent: job}
+	rc.ExprEval = rc.NewExpressionEvaluator(context.Background())
+
+	stepModel := &model.Step{ID: "tool", Run: "echo ${{ unknown.value }}"}
+	sm := &stepMock{}
+	sm.On("getStepModel").Return(stepModel)
+	sm.On("getEnv").Return(&map[string]string{})
+
+	err := rc.unsupportedExpression(context.Background(), sm)
+	assert.ErrorIs(t, err, exprparser.ErrUnsupported)
+
+	stepResult := &model.StepResult{}
+	assert.True(t, rc.skipUnsupported(context.Background(), stepModel, "tool", stepResult, err))
+	assert.Equal(t, model.StepStatusSkipped, stepResult.Outcome)
+	assert.Equal(t, model.StepStatusSkipped, stepResult.Conclusion)
+	if assert.Len(t, job.unsupportedSteps, 1) {
+		assert.Equal(t, "tool", job.unsupportedSteps[0].ID)
+		assert.Contains(t, job.unsupportedSteps[0].Reason, "Unavailable context: unknown")
+	}
+
+	assert.False(t, rc.skipUnsupported(context.Background(), stepModel, "tool", &model.StepResult{}, errors.New("exitcode '1': failure")))
+	rc.Config = &Config{}
+	assert.False(t, rc.skipUnsupported(context.Background(), stepModel, "tool", &model.StepResult{}, err))
+
+	stepModel.Run = "echo ${{ github.sha }}"
+	assert.NoError(t, rc.unsupportedExpression(context.Background(), sm))
+}
@@ -0,0 +1,275 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/common/git"
+	"github.com/nektos/act/pkg/model"
+)
+
+// VendorDir is the directory of the working directory 'act vendor' copies the repositories of the remote actions and
+// reusable workflows into, the jobs use the vendored copies instead of cloning the repositories
+const VendorDir = ".github/vendor/actions"
+
+// vendoredMarker marks a directory of the action cache holding a vendored copy, which has no git repository to fetch
+const vendoredMarker = ".act-vendored"
+
+// vendoredDir returns the directory of the vendored copy of the repository at ref, the slashes of the ref are
+// replaced like in the action cache
+func vendoredDir(workdir string, org string, repo string, ref string) string {
+	return filepath.Join(workdir, filepath.FromSlash(VendorDir), org, repo+"@"+strings.ReplaceAll(ref, "/", "-"))
+}
+
+// vendored returns the vendored copy of the repository at ref, empty if it isn't vendored
+func (rc *RunContext) vendored(org string, repo string, ref string) string {
+	dir := vendoredDir(rc.Config.Workdir, org, repo, ref)
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return ""
+	}
+	return dir
+}
+
+// vendorCopies are the directories of the action cache the vendored copies were copied to by the process, a copy
+// is refreshed once per process as the jobs read it while they run
+var vendorCopies = struct {
+	sync.Mutex
+	dirs map[string]bool
+}{dirs: map[string]bool{}}
+
+// newVendoredCopyExecutor copies the vendored copy of a repository to the directory of the action cache the
+// repository is cloned to otherwise
+func newVendoredCopyExecutor(uses string, vendored string, dir string) common.Executor {
+	return func(ctx context.Context) error {
+		vendorCopies.Lock()
+		defer vendorCopies.Unlock()
+		if vendorCopies.dirs[dir] {
+			return nil
+		}
+		common.Logger(ctx).Infof("  \U0001F4E6  %s vendored in %s", uses, vendored)
+		if err := os.RemoveAll(dir); err != nil {
+			return err
+		}
+		if err := copyVendorDir(vendored, dir); err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(dir, vendoredMarker), nil, 0o644); err != nil {
+			return err
+		}
+		vendorCopies.dirs[dir] = true
+		return nil
+	}
+}
+
+// newActionCloneExecutor clones the repository of the input, or copies its vendored copy if the workdir has one.
+// A copy left in the action cache by a vendored run is replaced by a clone once the repository isn't vendored anymore.
+func (rc *RunContext) newActionCloneExecutor(uses string, org string, repo string, input git.NewGitCloneExecutorInput) common.Executor {
+	if vendored := rc.vendored(org, repo, input.Ref); vendored != "" {
+		return newVendoredCopyExecutor(uses, vendored, input.Dir)
+	}
+	clone := stepActionRemoteNewCloneExecutor(input)
+	return func(ctx context.Context) error {
+		if _, err := os.Stat(filepath.Join(input.Dir, vendoredMarker)); err == nil {
+			vendorCopies.Lock()
+			delete(vendorCopies.dirs, input.Dir)
+			err = os.RemoveAll(input.Dir)
+			vendorCopies.Unlock()
+			if err != nil {
+				return err
+			}
+		}
+		return clone(ctx)
+	}
+}
+
+// copyVendorDir copies the files of a repository without its .git directory, the symlinks are kept
+func copyVendorDir(source string, dest string) error {
+	return filepath.WalkDir(source, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(source, file)
+		if err != nil {
+			return err
+		}
+		if entry.Name() == ".git" && rel != "." {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		target := filepath.Join(dest, rel)
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case entry.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm()|0o700)
+		case info.Mode()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(file)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		default:
+			content, err := os.ReadFile(file)
+			if err != nil {
+				return err
+			}
+			return os.WriteFile(target, content, info.Mode().Perm())
+		}
+	})
+}
+
+// vendorer vendors the repositories used by a plan, see VendorPlan
+type vendorer struct {
+	rc       *RunContext
+	vendored map[string]bool // org/repo@ref of the vendored repositories
+	visited  map[string]bool // uses of the steps and jobs whose actions and workflows were looked at
+}
+
+// VendorPlan copies the repositories of the remote actions and reusable workflows used by the jobs of the plan into
+// VendorDir of the working directory, along with the ones used by the composite actions and reusable workflows they
+// use. It returns the vendored repositories as org/repo@ref.
+func VendorPlan(ctx context.Context, config *Config, plan *model.Plan) ([]string, error) {
+	v := &vendorer{
+		rc:       &RunContext{Config: config},
+		vendored: map[string]bool{},
+		visited:  map[string]bool{},
+	}
+	for _, stage := range plan.Stages {
+		for _, run := range stage.Runs {
+			if err := v.vendorJob(ctx, run.Job()); err != nil {
+				return nil, err
+			}
+		}
+	}
+	vendored := make([]string, 0, len(v.vendored))
+	for repository := range v.vendored {
+		vendored = append(vendored, repository)
+	}
+	sort.Strings(vendored)
+	return vendored, nil
+}
+
+func (v *vendorer) vendorJob(ctx context.Context, job *model.Job) error {
+	if job.Type() == model.JobTypeReusableWorkflowRemote {
+		if v.visited[job.Uses] {
+			return nil
+		}
+		v.visited[job.Uses] = true
+		workflow := newRemoteReusableWorkflow(job.Uses)
+		if workflow == nil {
+			return fmt.Errorf("expected format {owner}/{repo}/.github/workflows/{filename}@{ref}. Actual '%s' Input string was not in a correct format", job.Uses)
+		}
+		dir, err := v.vendor(ctx, job.Uses, workflow.Org, workflow.Repo, workflow.Ref)
+		if err != nil {
+			return err
+		}
+		// the actions and reusable workflows used by the called workflow
+		planner, err := model.NewWorkflowPlanner(filepath.Join(dir, ".github", "workflows", workflow.Filename), true)
+		if err != nil {
+			return err
+		}
+		plan := planner.PlanAll()
+		for _, stage := range plan.Stages {
+			for _, run := range stage.Runs {
+				if err := v.vendorJob(ctx, run.Job()); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	return v.vendorSteps(ctx, job.Steps)
+}
+
+func (v *vendorer) vendorSteps(ctx context.Context, steps []*model.Step) error {
+	for _, step := range steps {
+		if step == nil || step.Type() != model.StepTypeUsesActionRemote || v.visited[step.Uses] {
+			continue
+		}
+		v.visited[step.Uses] = true
+		action := newRemoteAction(step.Uses)
+		if action == nil {
+			return fmt.Errorf("Expected format {org}/{repo}[/path]@ref. Actual '%s' Input string was not in a correct format", step.Uses)
+		}
+		dir, err := v.vendor(ctx, step.Uses, action.Org, action.Repo, action.Ref)
+		if err != nil {
+			return err
+		}
+		// the actions used by the steps of a composite action
+		actionModel, err := readVendoredAction(path.Join(dir, action.Path))
+		if err != nil {
+			return fmt.Errorf("unable to read the action %s: %w", step.Uses, err)
+		}
+		if actionModel == nil || actionModel.Runs.Using != model.ActionRunsUsingComposite {
+			continue
+		}
+		compositeSteps := make([]*model.Step, 0, len(actionModel.Runs.Steps))
+		for i := range actionModel.Runs.Steps {
+			compositeSteps = append(compositeSteps, &actionModel.Runs.Steps[i])
+		}
+		if err := v.vendorSteps(ctx, compositeSteps); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// vendor clones the repository at ref into the action cache and copies it into VendorDir once per plan, a vendored
+// copy is replaced. It returns the vendored copy.
+func (v *vendorer) vendor(ctx context.Context, uses string, org string, repo string, ref string) (string, error) {
+	repository := fmt.Sprintf("%s/%s@%s", org, repo, ref)
+	dir := vendoredDir(v.rc.Config.Workdir, org, repo, ref)
+	if v.vendored[repository] {
+		return dir, nil
+	}
+
+	input := v.rc.actionCloneInput(&model.Step{Uses: repository}, &remoteAction{Org: org, Repo: repo, Ref: ref})
+	if _, err := os.Stat(filepath.Join(input.Dir, vendoredMarker)); err == nil {
+		// a copy of the vendored copy, which may be outdated
+		if err := os.RemoveAll(input.Dir); err != nil {
+			return "", err
+		}
+	}
+	if err := stepActionRemoteNewCloneExecutor(input)(ctx); err != nil {
+		return "", fmt.Errorf("unable to clone %s used by %s: %w", repository, uses, err)
+	}
+	common.TouchCacheEntry(input.Dir)
+
+	if err := os.RemoveAll(dir); err != nil {
+		return "", err
+	}
+	if err := copyVendorDir(input.Dir, dir); err != nil {
+		return "", err
+	}
+	common.Logger(ctx).Infof("  \U0001F4E6  %s vendored in %s", repository, dir)
+	v.vendored[repository] = true
+	return dir, nil
+}
+
+// readVendoredAction reads the action in dir, it is nil if dir has no action.yml or action.yaml, e.g. a Dockerfile
+func readVendoredAction(dir string) (*model.Action, error) {
+	for _, name := range []string{"action.yml", "action.yaml"} {
+		f, err := os.Open(filepath.Join(dir, name))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return model.ReadAction(f)
+	}
+	return nil, nil
+}
@@ -0,0 +1,119 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/common/git"
+	"github.com/nektos/act/pkg/model"
+)
+
+func TestVendorPlan(t *testing.T) {
+	workdir := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	assert.NoError(t, os.MkdirAll(filepath.Join(workdir, ".github", "workflows"), 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(workdir, ".github", "workflows", "test.yml"), []byte(`
+on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v3
+      - uses: nektos/composite-action/setup@v1
+      - uses: docker://alpine:3.17
+      - uses: ./local-action
+  call:
+    uses: nektos/workflows/.github/workflows/build.yml@main
+`), 0o600))
+
+	repositories := map[string]map[string]string{
+		"actions/checkout": {"action.yml": "runs:\n  using: node16\n  main: index.js\n"},
+		"actions/cache":    {"action.yml": "runs:\n  using: node16\n  main: index.js\n"},
+		"nektos/composite-action": {
+			"setup/action.yml": "runs:\n  using: composite\n  steps:\n    - uses: actions/cache@v3\n",
+		},
+		"nektos/workflows": {
+			".github/workflows/build.yml": "on: workflow_call\njobs:\n  build:\n    runs-on: ubuntu-latest\n    steps:\n      - uses: actions/checkout@v3\n",
+		},
+	}
+	origin := stepActionRemoteNewCloneExecutor
+	defer func() { stepActionRemoteNewCloneExecutor = origin }()
+	stepActionRemoteNewCloneExecutor = func(input git.NewGitCloneExecutorInput) common.Executor {
+		return func(ctx context.Context) error {
+			for repo, files := range repositories {
+				if !strings.HasSuffix(input.URL, "/"+repo) {
+					continue
+				}
+				files[".git/HEAD"] = "ref: refs/heads/main\n"
+				for name, content := range files {
+					file := filepath.Join(input.Dir, filepath.FromSlash(name))
+					if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+						return err
+					}
+					if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+						return err
+					}
+				}
+				return nil
+			}
+			return fmt.Errorf("repository not found")
+		}
+	}
+
+	planner, err := model.NewWorkflowPlanner(filepath.Join(workdir, ".github", "workflows"), true)
+	assert.NoError(t, err)
+	vendored, err := VendorPlan(context.Background(), &Config{Workdir: workdir, GitHubInstance: "github.com"}, planner.PlanAll())
+	assert.NoError(t, err)
+	// the actions of the composite actions and the reusable workflows are vendored too
+	assert.Equal(t, []string{
+		"actions/cache@v3",
+		"actions/checkout@v3",
+		"nektos/composite-action@v1",
+		"nektos/workflows@main",
+	}, vendored)
+
+	assert.FileExists(t, filepath.Join(workdir, ".github", "vendor", "actions", "nektos", "composite-action@v1", "setup", "action.yml"))
+	assert.FileExists(t, filepath.Join(workdir, ".github", "vendor", "actions", "nektos", "workflows@main", ".github", "workflows", "build.yml"))
+	assert.NoDirExists(t, filepath.Join(workdir, ".github", "vendor", "actions", "actions", "checkout@v3", ".git"))
+}
+
+func TestNewActionCloneExecutorVendored(t *testing.T) {
+	workdir := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	vendored := vendoredDir(workdir, "nektos", "test-action", "releases/v1")
+	assert.NoError(t, os.MkdirAll(vendored, 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(vendored, "action.yml"), []byte("runs:\n  using: node16\n  main: index.js\n"), 0o600))
+
+	cloned := false
+	origin := stepActionRemoteNewCloneExecutor
+	defer func() { stepActionRemoteNewCloneExecutor = origin }()
+	stepActionRemoteNewCloneExecutor = func(input git.NewGitCloneExecutorInput) common.Executor {
+		return func(ctx context.Context) error {
+			cloned = true
+			return os.MkdirAll(input.Dir, 0o755)
+		}
+	}
+
+	rc := &RunContext{Config: &Config{Workdir: workdir, GitHubInstance: "github.com"}}
+	step := &model.Step{Uses: "nektos/test-action@releases/v1"}
+	action := newRemoteAction(step.Uses)
+	input := rc.actionCloneInput(step, action)
+
+	// the vendored copy is used instead of a clone
+	assert.NoError(t, rc.newActionCloneExecutor(step.Uses, action.Org, action.Repo, input)(context.Background()))
+	assert.False(t, cloned)
+	assert.FileExists(t, filepath.Join(input.Dir, "action.yml"))
+
+	// the copy is replaced by a clone once the action isn't vendored anymore
+	assert.NoError(t, os.RemoveAll(vendored))
+	assert.NoError(t, rc.newActionCloneExecutor(step.Uses, action.Org, action.Repo, input)(context.Background()))
+	assert.True(t, cloned)
+	assert.NoFileExists(t, filepath.Join(input.Dir, "action.yml"))
+}
@@ -0,0 +1,124 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nektos/act/pkg/exprparser"
+	"github.com/nektos/act/pkg/model"
+)
+
+// Condition is the outcome of the if: of a job, or of one of its steps, for the event of a run
+type Condition struct {
+	Workflow string
+	Job      string // the name of the job, with the index of the leg of a matrix job
+	Step     string // the step, empty for the condition of the job
+	If       string
+	Runs     bool
+	Err      error
+}
+
+// EvaluateConditions evaluates the if: of the jobs of the plan and of their steps for the event of the config, without
+// running anything. The jobs and the steps which run are assumed to succeed: success() of a job is true if the jobs it
+// needs run, of a step if the job runs. The results of the jobs of the plan are set accordingly.
+func EvaluateConditions(ctx context.Context, config *Config, plan *model.Plan) ([]Condition, error) {
+	runner := &runnerImpl{config: config}
+	if _, err := runner.configure(); err != nil {
+		return nil, err
+	}
+
+	for _, stage := range plan.Stages {
+		for _, run := range stage.Runs {
+			run.Job().Result = ""
+		}
+	}
+
+	conditions := make([]Condition, 0)
+	for _, stage := range plan.Stages {
+		for _, run := range stage.Runs {
+			job := run.Job()
+			if job.Strategy != nil {
+				strategyRc := runner.newRunContext(ctx, run, nil)
+				if err := strategyRc.NewExpressionEvaluator(ctx).EvaluateYamlNode(ctx, &job.Strategy.RawMatrix); err != nil {
+					return nil, fmt.Errorf("unable to evaluate the matrix of %s: %w", run, err)
+				}
+			}
+
+			result := "skipped"
+			matrixes := job.GetMatrixes()
+			for i, matrix := range matrixes {
+				if !selectsMatrix(config.Matrix, matrix) {
+					continue
+				}
+				rc := runner.newRunContext(ctx, run, matrix)
+				if len(matrixes) > 1 {
+					rc.Name = fmt.Sprintf("%s-%d", rc.Name, i+1)
+				}
+				legConditions, runs := rc.evaluateConditions(ctx)
+				conditions = append(conditions, legConditions...)
+				if runs {
+					result = "success"
+				}
+			}
+			job.Result = result
+		}
+	}
+	return conditions, nil
+}
+
+// evaluateConditions evaluates the if: of the job and, if it runs, the if: of its steps
+func (rc *RunContext) evaluateConditions(ctx context.Context) ([]Condition, bool) {
+	job := rc.Run.Job()
+	ghc := rc.getGithubContext(ctx)
+	jobCondition := Condition{Workflow: rc.Run.Workflow.File, Job: rc.String(), If: job.If.Value}
+	if !rc.Run.Workflow.RunsForActivityType(ghc.EventName, ghc.Event) {
+		jobCondition.If = fmt.Sprintf("on.%s.types", ghc.EventName)
+		return []Condition{jobCondition}, false
+	}
+	jobCondition.Runs, jobCondition.Err = EvalBool(ctx, rc.NewExpressionEvaluatorWithEnv(ctx, rc.jobIfEnv()), job.If.Value, exprparser.DefaultStatusCheckSuccess)
+	conditions := []Condition{jobCondition}
+	if !jobCondition.Runs || job.Type() != model.JobTypeDefault {
+		return conditions, jobCondition.Runs
+	}
+
+	rc.evaluateEnv(ctx)
+	sf := &stepFactoryImpl{}
+	for i, stepModel := range job.Steps {
+		if stepModel == nil {
+			continue
+		}
+		if stepModel.ID == "" {
+			stepModel.ID = fmt.Sprintf("%d", i)
+		}
+		condition := Condition{Workflow: jobCondition.Workflow, Job: jobCondition.Job, Step: stepModel.String(), If: stepModel.If.Value}
+		step, err := sf.newStep(stepModel, rc)
+		if err != nil {
+			condition.Err = err
+			conditions = append(conditions, condition)
+			continue
+		}
+
+		// like a step which runs, the step sees the results of the previous steps and its own env
+		stepResult := &model.StepResult{
+			Outcome:    model.StepStatusSuccess,
+			Conclusion: model.StepStatusSuccess,
+			Outputs:    make(map[string]string),
+		}
+		rc.CurrentStep = stepModel.ID
+		rc.StepResults[stepModel.ID] = stepResult
+		env := mergeMaps(rc.GetEnv())
+		exprEval := rc.NewExpressionEvaluatorWithEnv(ctx, rc.GetEnv())
+		for k, v := range stepModel.GetEnv() {
+			env[k] = exprEval.Interpolate(ctx, v)
+		}
+		*step.getEnv() = env
+
+		condition.Runs, condition.Err = isStepEnabled(ctx, step.getIfExpression(ctx, stepStageMain), step, stepStageMain)
+		if !condition.Runs {
+			stepResult.Outcome = model.StepStatusSkipped
+			stepResult.Conclusion = model.StepStatusSkipped
+		}
+		conditions = append(conditions, condition)
+	}
+	return conditions, true
+}
@@ -0,0 +1,87 @@
+package runner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nektos/act/pkg/model"
+)
+
+func TestEvaluateConditions(t *testing.T) {
+	workflow, err := model.ReadWorkflow(strings.NewReader(`
+on:
+  pull_request:
+    types: [opened, closed]
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    strategy:
+      matrix:
+        os: [linux, windows]
+    steps:
+      - id: docs
+        if: contains(github.event.pull_request.labels.*.name, 'docs')
+        run: make docs
+      - name: publish docs
+        if: steps.docs.outcome == 'success'
+        run: make publish
+  deploy:
+    runs-on: ubuntu-latest
+    needs: build
+    if: github.event.pull_request.merged
+    steps:
+      - run: make deploy
+`))
+	assert.NoError(t, err)
+	workflow.Name = "CI"
+	plan := &model.Plan{Stages: []*model.Stage{
+		{Runs: []*model.Run{{Workflow: workflow, JobID: "build"}}},
+		{Runs: []*model.Run{{Workflow: workflow, JobID: "deploy"}}},
+	}}
+
+	dir := t.TempDir()
+	evaluate := func(payload string) []Condition {
+		path := filepath.Join(dir, "event.json")
+		assert.NoError(t, os.WriteFile(path, []byte(payload), 0o600))
+		conditions, err := EvaluateConditions(context.Background(), &Config{Workdir: dir, EventName: "pull_request", EventPath: path}, plan)
+		assert.NoError(t, err)
+		for i := range conditions {
+			conditions[i].Workflow = ""
+		}
+		return conditions
+	}
+
+	assert.Equal(t, []Condition{
+		{Job: "CI/build-1", If: "success()", Runs: true},
+		{Job: "CI/build-1", Step: "make docs", If: "contains(github.event.pull_request.labels.*.name, 'docs')", Runs: true},
+		{Job: "CI/build-1", Step: "publish docs", If: "steps.docs.outcome == 'success'", Runs: true},
+		{Job: "CI/build-2", If: "success()", Runs: true},
+		{Job: "CI/build-2", Step: "make docs", If: "contains(github.event.pull_request.labels.*.name, 'docs')", Runs: true},
+		{Job: "CI/build-2", Step: "publish docs", If: "steps.docs.outcome == 'success'", Runs: true},
+		{Job: "CI/deploy", If: "github.event.pull_request.merged", Runs: true},
+		{Job: "CI/deploy", Step: "make deploy", If: "", Runs: true},
+	}, evaluate(`{"action": "closed", "pull_request": {"merged": true, "labels": [{"name": "docs"}]}}`))
+
+	// the steps of a job which doesn't run aren't evaluated, a step after a skipped step sees its outcome
+	assert.Equal(t, []Condition{
+		{Job: "CI/build-1", If: "success()", Runs: true},
+		{Job: "CI/build-1", Step: "make docs", If: "contains(github.event.pull_request.labels.*.name, 'docs')", Runs: false},
+		{Job: "CI/build-1", Step: "publish docs", If: "steps.docs.outcome == 'success'", Runs: false},
+		{Job: "CI/build-2", If: "success()", Runs: true},
+		{Job: "CI/build-2", Step: "make docs", If: "contains(github.event.pull_request.labels.*.name, 'docs')", Runs: false},
+		{Job: "CI/build-2", Step: "publish docs", If: "steps.docs.outcome == 'success'", Runs: false},
+		{Job: "CI/deploy", If: "github.event.pull_request.merged", Runs: false},
+	}, evaluate(`{"action": "opened", "pull_request": {"merged": false, "labels": []}}`))
+
+	// the workflow doesn't run for the activity type of the event
+	assert.Equal(t, []Condition{
+		{Job: "CI/build-1", If: "on.pull_request.types", Runs: false},
+		{Job: "CI/build-2", If: "on.pull_request.types", Runs: false},
+		{Job: "CI/deploy", If: "on.pull_request.types", Runs: false},
+	}, evaluate(`{"action": "labeled", "pull_request": {"merged": true}}`))
+}
@@ -0,0 +1,83 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/nektos/act/pkg/model"
+)
+
+// validateWorkflowDispatch checks the inputs of the workflow_dispatch event against the inputs the workflow declares:
+// the required inputs, the booleans, the numbers and the options of the choices. The inputs the workflow doesn't
+// declare aren't rejected, they may be the inputs of another workflow of the plan or of the actions.
+func validateWorkflowDispatch(workflow *model.Workflow, inputs map[string]interface{}) error {
+	config := workflow.WorkflowDispatchConfig()
+	if config == nil {
+		return nil
+	}
+
+	for name, input := range config.Inputs {
+		value, ok := inputs[name]
+		if !ok || fmt.Sprint(value) == "" {
+			if input.Required && input.Default == "" {
+				return fmt.Errorf("workflow '%s' requires the input '%s', pass it with --input %s=<value>", workflow.File, name, name)
+			}
+			continue
+		}
+		str := fmt.Sprint(value)
+		switch input.Type {
+		case "boolean":
+			if str != "true" && str != "false" {
+				return fmt.Errorf("input '%s' of workflow '%s' must be a boolean, got '%s'", name, workflow.File, str)
+			}
+		case "number":
+			if _, err := strconv.ParseFloat(str, 64); err != nil {
+				return fmt.Errorf("input '%s' of workflow '%s' must be a number, got '%s'", name, workflow.File, str)
+			}
+		case "choice":
+			valid := false
+			for _, option := range input.Options {
+				valid = valid || option == str
+			}
+			if !valid {
+				return fmt.Errorf("input '%s' of workflow '%s' must be one of %s, got '%s'", name, workflow.File, strings.Join(input.Options, ", "), str)
+			}
+		}
+	}
+	return nil
+}
+
+// workflowDispatchEvent returns the workflow_dispatch event with the defaults of the inputs of the workflow the event
+// doesn't set, like on GitHub the values of github.event.inputs are strings
+func workflowDispatchEvent(eventJSON string, workflow *model.Workflow) string {
+	config := workflow.WorkflowDispatchConfig()
+	if config == nil || len(config.Inputs) == 0 {
+		return eventJSON
+	}
+	var event map[string]interface{}
+	if err := json.Unmarshal([]byte(eventJSON), &event); err != nil || event == nil {
+		return eventJSON
+	}
+	inputs, _ := event["inputs"].(map[string]interface{})
+	if inputs == nil {
+		inputs = map[string]interface{}{}
+	}
+	changed := false
+	for name, input := range config.Inputs {
+		if _, ok := inputs[name]; !ok && input.Default != "" {
+			inputs[name] = input.Default
+			changed = true
+		}
+	}
+	if !changed {
+		return eventJSON
+	}
+	event["inputs"] = inputs
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return eventJSON
+	}
+	return string(payload)
+}
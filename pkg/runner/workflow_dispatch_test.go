@@ -0,0 +1,60 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nektos/act/pkg/model"
+)
+
+func TestWorkflowDispatchInputs(t *testing.T) {
+	workflow, err := model.ReadWorkflow(strings.NewReader(`
+name: deploy
+on:
+  workflow_dispatch:
+    inputs:
+      environment:
+        type: choice
+        required: true
+        options: [staging, production]
+      version:
+        required: true
+      replicas:
+        type: number
+        default: "2"
+      dry-run:
+        type: boolean
+        default: "true"
+jobs:
+  deploy:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo
+`))
+	assert.NoError(t, err)
+	workflow.File = "deploy.yml"
+
+	assert.NoError(t, validateWorkflowDispatch(workflow, map[string]interface{}{"environment": "staging", "version": "1.2.3", "other": "input of an action"}))
+	assert.NoError(t, validateWorkflowDispatch(workflow, map[string]interface{}{"environment": "production", "version": "1.2.3", "replicas": 3.0, "dry-run": false}))
+	assert.EqualError(t, validateWorkflowDispatch(workflow, map[string]interface{}{"environment": "staging"}), "workflow 'deploy.yml' requires the input 'version', pass it with --input version=<value>")
+	assert.EqualError(t, validateWorkflowDispatch(workflow, map[string]interface{}{"environment": "dev", "version": "1"}), "input 'environment' of workflow 'deploy.yml' must be one of staging, production, got 'dev'")
+	assert.EqualError(t, validateWorkflowDispatch(workflow, map[string]interface{}{"environment": "staging", "version": "1", "replicas": "many"}), "input 'replicas' of workflow 'deploy.yml' must be a number, got 'many'")
+	assert.EqualError(t, validateWorkflowDispatch(workflow, map[string]interface{}{"environment": "staging", "version": "1", "dry-run": "yes"}), "input 'dry-run' of workflow 'deploy.yml' must be a boolean, got 'yes'")
+
+	var event map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(workflowDispatchEvent(`{"inputs":{"environment":"staging","replicas":"5"}}`, workflow)), &event))
+	assert.Equal(t, map[string]interface{}{"environment": "staging", "replicas": "5", "dry-run": "true"}, event["inputs"])
+	assert.Equal(t, `{"ref":"main"}`, workflowDispatchEvent(`{"ref":"main"}`, &model.Workflow{}))
+
+	rc := &RunContext{
+		Config:    &Config{EventName: "workflow_dispatch"},
+		Run:       &model.Run{Workflow: workflow, JobID: "deploy"},
+		EventJSON: workflowDispatchEvent(`{"inputs":{"environment":"staging","version":"1.2.3"}}`, workflow),
+	}
+	rc.ExprEval = rc.NewExpressionEvaluator(context.Background())
+	assert.Equal(t, map[string]interface{}{"environment": "staging", "version": "1.2.3", "replicas": 2.0, "dry-run": true}, getEvaluatorInputs(context.Background(), rc, nil, rc.getGithubContext(context.Background())))
+}
@@ -0,0 +1,228 @@
+// Package workflowtest runs the workflows of a repository from the tests of a Go project and asserts on
+// the results of their jobs and steps, e.g.
+//
+//	func TestRelease(t *testing.T) {
+//		workflowtest.RunAll(t, []workflowtest.Case{{
+//			Name:      "tag",
+//			Workflow:  ".github/workflows/release.yml",
+//			EventPath: "testdata/tag.json",
+//			Mocks:     map[string]string{"softprops/action-gh-release@*": "./testdata/noop"},
+//			Expect: map[string]*runner.JobAssertion{
+//				"release": {Result: "success", Outputs: map[string]string{"version": "1.0.0"}},
+//			},
+//		}})
+//	}
+package workflowtest
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/nektos/act/pkg/model"
+	"github.com/nektos/act/pkg/runner"
+)
+
+// Engine runs the jobs of a workflow
+type Engine string
+
+const (
+	// EngineDocker runs the jobs in containers of the images of Case.Platforms
+	EngineDocker Engine = "docker"
+	// EngineHost runs the steps on the host like a self-hosted runner, no container engine is needed. The steps
+	// can change the host, so a case has to opt in to it.
+	EngineHost Engine = "host"
+)
+
+// images of the platforms of EngineDocker, unless the case configures them
+var defaultImages = map[string]string{
+	"ubuntu-latest": "node:16-buster-slim",
+	"ubuntu-22.04":  "node:16-bullseye-slim",
+	"ubuntu-20.04":  "node:16-buster-slim",
+	"ubuntu-18.04":  "node:16-buster-slim",
+}
+
+// Case is a run of a workflow and the results expected from it
+type Case struct {
+	Name      string            // name of the subtest of RunAll
+	Workdir   string            // root of the repository, the working directory of the test if empty
+	Workflow  string            // path of the workflow file, relative to Workdir
+	Event     string            // name of the event, push if empty
+	EventPath string            // path of the JSON fixture of the event, relative to Workdir
+	Job       string            // ID of the job to run, all jobs of the event if empty
+	Mocks     map[string]string // actions (owner/repo@ref, may contain wildcards) replaced by a local action
+	Env       map[string]string
+	Secrets   map[string]string
+	Inputs    map[string]string
+	Platforms map[string]string // images of the runs-on labels of EngineDocker
+	Engine    Engine            // EngineDocker if empty
+
+	// Expect are the results expected from the jobs by their ID, a matrix job has to match in every leg
+	Expect map[string]*runner.JobAssertion
+	// ExpectError is a part of the error expected from the run, the run has to succeed if it's empty
+	ExpectError string
+}
+
+// RunAll runs every case as a subtest
+func RunAll(t *testing.T, cases []Case) {
+	for _, c := range cases {
+		c := c
+		name := c.Name
+		if name == "" {
+			name = c.Workflow
+		}
+		t.Run(name, func(t *testing.T) {
+			Run(t, c)
+		})
+	}
+}
+
+// Run runs the workflow of the case and fails the test if the run doesn't end as expected or the results don't
+// match the expectations, the results are returned for further assertions. The case is skipped with -short.
+func Run(t testing.TB, c Case) *runner.Results {
+	t.Helper()
+	if testing.Short() {
+		t.Skipf("skipping the run of %s in short mode", c.Workflow)
+	}
+
+	results, err := run(context.Background(), t, c)
+	if c.ExpectError == "" && err != nil {
+		t.Fatalf("%s failed: %v", c.Workflow, err)
+	} else if c.ExpectError != "" && err == nil {
+		t.Fatalf("%s succeeded, expected the error '%s'", c.Workflow, c.ExpectError)
+	} else if c.ExpectError != "" && !strings.Contains(err.Error(), c.ExpectError) {
+		t.Fatalf("%s failed with '%v', expected the error '%s'", c.Workflow, err, c.ExpectError)
+	}
+
+	if results == nil {
+		return nil
+	}
+	assertions := &runner.Assertions{Jobs: c.Expect}
+	if err := assertions.Verify(results, noArtifacts); err != nil {
+		t.Errorf("%s: %v", c.Workflow, err)
+	}
+	return results
+}
+
+func noArtifacts(_ string, name string, _ string) ([]byte, error) {
+	return nil, fmt.Errorf("artifact %s is not available, the artifact server doesn't run in the tests", name)
+}
+
+func run(ctx context.Context, t testing.TB, c Case) (*runner.Results, error) {
+	workdir, err := filepath.Abs(c.Workdir)
+	if err != nil {
+		return nil, err
+	}
+	if c.Workflow == "" {
+		return nil, errors.New("the case has no workflow")
+	}
+	event := c.Event
+	if event == "" {
+		event = "push"
+	}
+
+	planner, err := model.NewWorkflowPlanner(filepath.Join(workdir, c.Workflow), true)
+	if err != nil {
+		return nil, err
+	}
+	var plan *model.Plan
+	if c.Job != "" {
+		plan = planner.PlanJob(c.Job)
+	} else {
+		plan = planner.PlanEvent(event)
+	}
+	if len(plan.Stages) == 0 {
+		return nil, fmt.Errorf("%s has no jobs for the event %s", c.Workflow, event)
+	}
+
+	eventPath := c.EventPath
+	if eventPath != "" && !filepath.IsAbs(eventPath) {
+		eventPath = filepath.Join(workdir, eventPath)
+	}
+
+	config := &runner.Config{
+		Workdir:        workdir,
+		EventName:      event,
+		EventPath:      eventPath,
+		Env:            c.Env,
+		Secrets:        c.Secrets,
+		Inputs:         c.Inputs,
+		Platforms:      platforms(c, plan),
+		MockActions:    c.Mocks,
+		GitHubInstance: "github.com",
+		AutoRemove:     true,
+	}
+	if config.Env == nil {
+		config.Env = map[string]string{}
+	}
+	if config.Secrets == nil {
+		config.Secrets = map[string]string{}
+	}
+	r, err := runner.New(config)
+	if err != nil {
+		return nil, err
+	}
+
+	results := &runner.Results{}
+	ctx = runner.WithResults(ctx, results)
+	ctx = runner.WithJobLoggerFactory(ctx, &testLoggerFactory{t: t})
+	return results, r.NewPlanExecutor(plan)(ctx)
+}
+
+// platforms maps the runs-on labels of the plan to the images of the engine
+func platforms(c Case, plan *model.Plan) map[string]string {
+	images := map[string]string{}
+	if c.Engine != EngineHost {
+		for label, image := range defaultImages {
+			images[label] = image
+		}
+	}
+	for label, image := range c.Platforms {
+		images[strings.ToLower(label)] = image
+	}
+	if c.Engine != EngineHost {
+		return images
+	}
+
+	for _, stage := range plan.Stages {
+		for _, run := range stage.Runs {
+			for _, label := range run.Job().RunsOn() {
+				if _, ok := images[strings.ToLower(label)]; !ok {
+					images[strings.ToLower(label)] = "-self-hosted"
+				}
+			}
+		}
+	}
+	return images
+}
+
+// testLoggerFactory writes the logs of the jobs to the log of the test, which is shown if the test fails or with -v
+type testLoggerFactory struct {
+	t testing.TB
+}
+
+func (f *testLoggerFactory) WithJobLogger() *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(&testLogWriter{t: f.t})
+	logger.SetLevel(logrus.GetLevel())
+	logger.SetFormatter(&logrus.TextFormatter{DisableTimestamp: true, DisableQuote: true})
+	return logger
+}
+
+type testLogWriter struct {
+	t testing.TB
+}
+
+func (w *testLogWriter) Write(p []byte) (int, error) {
+	scanner := bufio.NewScanner(strings.NewReader(string(p)))
+	for scanner.Scan() {
+		w.t.Log(scanner.Text())
+	}
+	return len(p), nil
+}
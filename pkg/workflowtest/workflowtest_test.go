@@ -0,0 +1,69 @@
+package workflowtest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+
+	"github.com/nektos/act/pkg/model"
+	"github.com/nektos/act/pkg/runner"
+)
+
+func TestRunAll(t *testing.T) {
+	RunAll(t, []Case{
+		{
+			Name:      "outputs",
+			Workdir:   "testdata",
+			Workflow:  ".github/workflows/build.yml",
+			Job:       "build",
+			EventPath: "release.json",
+			Mocks:     map[string]string{"actions/setup-go@*": "./noop"},
+			Engine:    EngineHost,
+			Expect: map[string]*runner.JobAssertion{
+				"build": {
+					Result:  "success",
+					Outputs: map[string]string{"version": "1.0.0"},
+					Steps: map[string]*runner.StepAssertion{
+						"checkout": {Conclusion: "success"},
+						"setup":    {Conclusion: "success"},
+						"optional": {Outcome: "failure", Conclusion: "success"},
+					},
+				},
+			},
+		},
+		{
+			Name:        "failure",
+			Workdir:     "testdata",
+			Workflow:    ".github/workflows/build.yml",
+			Mocks:       map[string]string{"actions/setup-go@*": "./noop"},
+			Secrets:     map[string]string{"EXIT_CODE": "3"},
+			Engine:      EngineHost,
+			ExpectError: "Job 'fail' failed",
+			Expect: map[string]*runner.JobAssertion{
+				"build": {Result: "success", Outputs: map[string]string{"version": "0.0.0"}},
+				"fail":  {Result: "failure"},
+			},
+		},
+	})
+}
+
+func TestPlatforms(t *testing.T) {
+	plan := &model.Plan{Stages: []*model.Stage{{Runs: []*model.Run{{
+		JobID: "build",
+		Workflow: &model.Workflow{Jobs: map[string]*model.Job{
+			"build": {RawRunsOn: yamlNode(t, "[self-hosted, Linux]")},
+		}},
+	}}}}}
+
+	assert.Equal(t, map[string]string{"self-hosted": "-self-hosted", "linux": "-self-hosted"}, platforms(Case{Engine: EngineHost}, plan))
+	assert.Equal(t, "node:16-buster-slim", platforms(Case{}, plan)["ubuntu-latest"], "the jobs run in containers by default")
+	assert.Equal(t, "node:16-buster-slim", platforms(Case{Engine: EngineDocker}, plan)["ubuntu-latest"])
+	assert.Equal(t, "alpine", platforms(Case{Engine: EngineDocker, Platforms: map[string]string{"Linux": "alpine"}}, plan)["linux"])
+}
+
+func yamlNode(t *testing.T, content string) yaml.Node {
+	var node yaml.Node
+	assert.NoError(t, yaml.Unmarshal([]byte(content), &node))
+	return *node.Content[0]
+}